@@ -9,6 +9,7 @@ const { generateFooterWithMessages } = require("./messages_footer.cjs");
 const { getRepositoryUrl } = require("./get_repository_url.cjs");
 const { replaceTemporaryIdReferences } = require("./temporary_id.cjs");
 const { getTrackerID } = require("./get_tracker_id.cjs");
+const { ADD_COMMENT_MARKER } = require("./safe_output_quota.cjs");
 const { getErrorMessage } = require("./error_helpers.cjs");
 const { resolveTarget } = require("./safe_output_helpers.cjs");
 const { resolveTargetRepoConfig, resolveAndValidateRepo } = require("./repo_helpers.cjs");
@@ -411,7 +412,7 @@ async function main(config = {}) {
     // Add tracker ID and footer
     const trackerIDComment = getTrackerID("markdown");
     if (trackerIDComment) {
-      processedBody += "\n\n" + trackerIDComment;
+      processedBody += "\n\n" + trackerIDComment + "\n" + ADD_COMMENT_MARKER;
     }
 
     const workflowName = process.env.GH_AW_WORKFLOW_NAME || "Workflow";
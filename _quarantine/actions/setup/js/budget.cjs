@@ -0,0 +1,271 @@
+// @ts-check
+
+/**
+ * Shared helpers for workflow budget enforcement.
+ *
+ * Used by the pre-activation budget check (check_budget.cjs), the in-run guard that
+ * stops the agent when a per-run limit is crossed (budget_guard.cjs) and the step that
+ * records the usage of each run (record_budget_usage.cjs).
+ */
+
+const fs = require("fs");
+const path = require("path");
+
+/** Directory holding per-run budget state (usage, guard log, exceeded marker) */
+const BUDGET_DIR = "/tmp/gh-aw/budget";
+
+/** Name of the artifact each run uploads its usage.json under */
+const USAGE_ARTIFACT_NAME = "budget-usage";
+
+const DAY_MS = 24 * 60 * 60 * 1000;
+
+/**
+ * Parses a budget limit from an environment variable value
+ * @param {string|undefined} value - Raw value
+ * @returns {number} The limit, or 0 when unset or invalid
+ */
+function parseLimit(value) {
+  const limit = parseFloat(value ?? "");
+  return Number.isFinite(limit) && limit > 0 ? limit : 0;
+}
+
+/**
+ * Reads the configured budget limits from the environment
+ * @param {Record<string, string|undefined>} [env] - Environment to read from
+ * @returns {{maxTokens: number, maxCost: number, maxCost7d: number, maxCost30d: number}}
+ */
+function readBudgetLimits(env = process.env) {
+  return {
+    maxTokens: parseLimit(env.GH_AW_BUDGET_MAX_TOKENS),
+    maxCost: parseLimit(env.GH_AW_BUDGET_MAX_COST),
+    maxCost7d: parseLimit(env.GH_AW_BUDGET_MAX_COST_7D),
+    maxCost30d: parseLimit(env.GH_AW_BUDGET_MAX_COST_30D),
+  };
+}
+
+/**
+ * Sums the token counts of a usage object (Claude and OpenAI formats)
+ * @param {any} usage - Usage object
+ * @returns {number}
+ */
+function sumUsageTokens(usage) {
+  if (!usage || typeof usage !== "object") {
+    return 0;
+  }
+  const input = Number(usage.input_tokens || usage.prompt_tokens || 0);
+  const output = Number(usage.output_tokens || usage.completion_tokens || 0);
+  const cacheCreation = Number(usage.cache_creation_input_tokens || 0);
+  const cacheRead = Number(usage.cache_read_input_tokens || 0);
+  const total = input + output + cacheCreation + cacheRead;
+  if (total > 0) {
+    return total;
+  }
+  return Number(usage.total_tokens || 0);
+}
+
+/**
+ * Creates an accumulator that extracts token usage and cost from agent logs.
+ * Content can be added incrementally; only complete lines should be passed in.
+ *
+ * Supported formats:
+ * - Claude stream-json: the final "result" entry (usage and total_cost_usd) wins,
+ *   otherwise per-message usage is summed, de-duplicated by message id
+ * - JSON lines with an OpenAI-style "usage" object
+ * - Codex text output ("tokens used: N", "total_tokens: N")
+ * - Pretty-printed JSON in debug logs ("prompt_tokens": N, "completion_tokens": N)
+ *
+ * @returns {{add: function(string): void, totals: function(): {tokens: number, cost: number}}}
+ */
+function createUsageAccumulator() {
+  /** @type {{tokens: number, cost: number}|null} */
+  let result = null;
+  /** @type {Map<string, number>} */
+  const messageTokens = new Map();
+  let otherTokens = 0;
+  let cost = 0;
+
+  /**
+   * @param {string} line
+   */
+  function addLine(line) {
+    const trimmed = line.trim();
+    if (!trimmed) {
+      return;
+    }
+
+    if (trimmed.startsWith("{") && trimmed.endsWith("}")) {
+      let entry;
+      try {
+        entry = JSON.parse(trimmed);
+      } catch {
+        entry = null;
+      }
+      if (entry && typeof entry === "object") {
+        if (entry.type === "result" && (entry.usage || entry.total_cost_usd !== undefined)) {
+          result = { tokens: sumUsageTokens(entry.usage), cost: Number(entry.total_cost_usd || 0) };
+        } else if (entry.message && entry.message.usage) {
+          const tokens = sumUsageTokens(entry.message.usage);
+          if (entry.message.id) {
+            messageTokens.set(entry.message.id, tokens);
+          } else {
+            otherTokens += tokens;
+          }
+        } else if (entry.usage) {
+          otherTokens += sumUsageTokens(entry.usage);
+        }
+        if (entry.type !== "result" && typeof entry.cost_usd === "number") {
+          cost += entry.cost_usd;
+        }
+        return;
+      }
+    }
+
+    const patterns = [/tokens used[:\s]+([\d,]+)/i, /\btotal_tokens:\s*(\d+)/, /"prompt_tokens":\s*(\d+)/, /"completion_tokens":\s*(\d+)/, /"input_tokens":\s*(\d+)/, /"output_tokens":\s*(\d+)/];
+    for (const pattern of patterns) {
+      const match = trimmed.match(pattern);
+      if (match) {
+        otherTokens += parseInt(match[1].replace(/,/g, ""), 10) || 0;
+      }
+    }
+  }
+
+  return {
+    add(content) {
+      for (const line of content.split("\n")) {
+        addLine(line);
+      }
+    },
+    totals() {
+      if (result) {
+        return { tokens: result.tokens, cost: result.cost };
+      }
+      let tokens = otherTokens;
+      for (const value of messageTokens.values()) {
+        tokens += value;
+      }
+      return { tokens, cost };
+    },
+  };
+}
+
+/**
+ * Extracts token usage and cost from log content
+ * @param {string} content - Log content
+ * @returns {{tokens: number, cost: number}}
+ */
+function extractUsage(content) {
+  const accumulator = createUsageAccumulator();
+  accumulator.add(content || "");
+  return accumulator.totals();
+}
+
+/**
+ * Lists the log files for a path that may be a file or a directory
+ * @param {string} logPath - File or directory path
+ * @returns {string[]}
+ */
+function listLogFiles(logPath) {
+  if (!logPath || !fs.existsSync(logPath)) {
+    return [];
+  }
+  const stat = fs.statSync(logPath);
+  if (!stat.isDirectory()) {
+    return [logPath];
+  }
+  return fs
+    .readdirSync(logPath)
+    .filter(file => file.endsWith(".log") || file.endsWith(".txt") || file.endsWith(".jsonl"))
+    .sort()
+    .map(file => path.join(logPath, file));
+}
+
+/**
+ * Parses a comma-separated list of log paths, dropping duplicates and empty entries
+ * @param {string|undefined} value - Comma-separated paths
+ * @returns {string[]}
+ */
+function parseLogPaths(value) {
+  const paths = (value || "")
+    .split(",")
+    .map(p => p.trim())
+    .filter(Boolean);
+  return [...new Set(paths)];
+}
+
+/**
+ * Extracts usage from every log file under the given paths
+ * @param {string[]} logPaths - Files or directories
+ * @returns {{tokens: number, cost: number}}
+ */
+function extractUsageFromPaths(logPaths) {
+  let best = { tokens: 0, cost: 0 };
+  // Each path is a different view of the same run, so take the largest figures
+  // rather than summing across paths
+  for (const logPath of logPaths) {
+    const accumulator = createUsageAccumulator();
+    for (const file of listLogFiles(logPath)) {
+      accumulator.add(fs.readFileSync(file, "utf8"));
+    }
+    const usage = accumulator.totals();
+    best = { tokens: Math.max(best.tokens, usage.tokens), cost: Math.max(best.cost, usage.cost) };
+  }
+  return best;
+}
+
+/**
+ * Returns a description of the per-run limit that the usage exceeds, if any
+ * @param {{tokens: number, cost: number}} usage - Usage of the run
+ * @param {{maxTokens: number, maxCost: number}} limits - Configured limits
+ * @returns {string} Empty when no limit is exceeded
+ */
+function describeRunLimitExceeded(usage, limits) {
+  if (limits.maxTokens > 0 && usage.tokens > limits.maxTokens) {
+    return `token usage ${usage.tokens} exceeds max-tokens ${limits.maxTokens}`;
+  }
+  if (limits.maxCost > 0 && usage.cost > limits.maxCost) {
+    return `cost $${usage.cost.toFixed(3)} exceeds max-cost $${limits.maxCost}`;
+  }
+  return "";
+}
+
+/**
+ * Parses the usage.json recorded by a run
+ * @param {string} content - File content
+ * @returns {{tokens: number, cost: number}}
+ */
+function parseRunUsage(content) {
+  const usage = JSON.parse(content);
+  if (!usage || typeof usage !== "object") {
+    throw new Error("usage.json is not an object");
+  }
+  return { tokens: Number(usage.tokens) || 0, cost: Number(usage.cost) || 0 };
+}
+
+/**
+ * Sums the cost of the runs created within the last number of days
+ * @param {Array<{created_at: string, cost: number}>} runs - Recorded runs
+ * @param {number} days - Window size in days
+ * @param {Date} [now] - Current time
+ * @returns {number}
+ */
+function sumCostSince(runs, days, now = new Date()) {
+  const cutoff = now.getTime() - days * DAY_MS;
+  return runs.filter(run => new Date(run.created_at).getTime() >= cutoff).reduce((total, run) => total + (Number(run.cost) || 0), 0);
+}
+
+module.exports = {
+  BUDGET_DIR,
+  USAGE_ARTIFACT_NAME,
+  DAY_MS,
+  parseLimit,
+  readBudgetLimits,
+  sumUsageTokens,
+  createUsageAccumulator,
+  extractUsage,
+  listLogFiles,
+  parseLogPaths,
+  extractUsageFromPaths,
+  describeRunLimitExceeded,
+  parseRunUsage,
+  sumCostSince,
+};
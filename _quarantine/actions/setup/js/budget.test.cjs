@@ -0,0 +1,135 @@
+import { describe, it, expect, beforeEach, afterEach } from "vitest";
+import fs from "fs";
+import os from "os";
+import path from "path";
+
+const { extractUsage, createUsageAccumulator, extractUsageFromPaths, describeRunLimitExceeded, readBudgetLimits, parseLogPaths, parseRunUsage, sumCostSince } = require("./budget.cjs");
+
+describe("budget.cjs", () => {
+  let tmpDir;
+
+  beforeEach(() => {
+    tmpDir = fs.mkdtempSync(path.join(os.tmpdir(), "budget-test-"));
+  });
+
+  afterEach(() => {
+    fs.rmSync(tmpDir, { recursive: true, force: true });
+  });
+
+  describe("extractUsage", () => {
+    it("uses the Claude result entry when present", () => {
+      const log = [
+        JSON.stringify({ type: "assistant", message: { id: "msg_1", usage: { input_tokens: 100, output_tokens: 10 } } }),
+        JSON.stringify({ type: "result", total_cost_usd: 0.42, usage: { input_tokens: 1000, output_tokens: 200, cache_read_input_tokens: 300 } }),
+      ].join("\n");
+      expect(extractUsage(log)).toEqual({ tokens: 1500, cost: 0.42 });
+    });
+
+    it("sums Claude message usage de-duplicated by message id before the result arrives", () => {
+      const log = [
+        JSON.stringify({ type: "assistant", message: { id: "msg_1", usage: { input_tokens: 100, output_tokens: 10 } } }),
+        JSON.stringify({ type: "assistant", message: { id: "msg_1", usage: { input_tokens: 100, output_tokens: 10 } } }),
+        JSON.stringify({ type: "assistant", message: { id: "msg_2", usage: { input_tokens: 200, output_tokens: 20 } } }),
+      ].join("\n");
+      expect(extractUsage(log)).toEqual({ tokens: 330, cost: 0 });
+    });
+
+    it("sums OpenAI-style usage objects", () => {
+      const log = [JSON.stringify({ usage: { prompt_tokens: 50, completion_tokens: 5 } }), JSON.stringify({ usage: { prompt_tokens: 60, completion_tokens: 6 } })].join("\n");
+      expect(extractUsage(log).tokens).toBe(121);
+    });
+
+    it("parses Codex token counts", () => {
+      const log = "[2025-01-01T00:00:00] tokens used: 1,200\nsome output\nTokenCount(TokenCountEvent { total_tokens: 300 })\n";
+      expect(extractUsage(log).tokens).toBe(1500);
+    });
+
+    it("parses pretty-printed usage in debug logs", () => {
+      const log = '[DEBUG] data:\n{\n  "usage": {\n    "prompt_tokens": 400,\n    "completion_tokens": 40,\n    "total_tokens": 440\n  }\n}\n';
+      expect(extractUsage(log).tokens).toBe(440);
+    });
+
+    it("returns zero for empty content", () => {
+      expect(extractUsage("")).toEqual({ tokens: 0, cost: 0 });
+    });
+  });
+
+  describe("createUsageAccumulator", () => {
+    it("accumulates content added incrementally", () => {
+      const accumulator = createUsageAccumulator();
+      accumulator.add("tokens used: 100\n");
+      accumulator.add("tokens used: 50\n");
+      expect(accumulator.totals().tokens).toBe(150);
+    });
+  });
+
+  describe("extractUsageFromPaths", () => {
+    it("reads files and directories and takes the largest figures across paths", () => {
+      const logFile = path.join(tmpDir, "agent-stdio.log");
+      fs.writeFileSync(logFile, "tokens used: 100\n");
+      const logDir = path.join(tmpDir, "logs");
+      fs.mkdirSync(logDir);
+      fs.writeFileSync(path.join(logDir, "a.log"), '"prompt_tokens": 300\n');
+      fs.writeFileSync(path.join(logDir, "b.log"), '"completion_tokens": 30\n');
+      fs.writeFileSync(path.join(logDir, "ignored.json"), '"prompt_tokens": 9999\n');
+
+      expect(extractUsageFromPaths([logFile, logDir, path.join(tmpDir, "missing")])).toEqual({ tokens: 330, cost: 0 });
+    });
+  });
+
+  describe("describeRunLimitExceeded", () => {
+    it("reports the exceeded limit", () => {
+      expect(describeRunLimitExceeded({ tokens: 2000, cost: 0 }, { maxTokens: 1000, maxCost: 0 })).toContain("max-tokens 1000");
+      expect(describeRunLimitExceeded({ tokens: 0, cost: 3 }, { maxTokens: 0, maxCost: 2.5 })).toContain("max-cost $2.5");
+    });
+
+    it("returns an empty string within the limits", () => {
+      expect(describeRunLimitExceeded({ tokens: 1000, cost: 2.5 }, { maxTokens: 1000, maxCost: 2.5 })).toBe("");
+      expect(describeRunLimitExceeded({ tokens: 5000, cost: 5 }, { maxTokens: 0, maxCost: 0 })).toBe("");
+    });
+  });
+
+  describe("readBudgetLimits", () => {
+    it("parses limits and ignores invalid values", () => {
+      expect(readBudgetLimits({ GH_AW_BUDGET_MAX_TOKENS: "1000", GH_AW_BUDGET_MAX_COST: "2.5", GH_AW_BUDGET_MAX_COST_7D: "abc", GH_AW_BUDGET_MAX_COST_30D: "-1" })).toEqual({
+        maxTokens: 1000,
+        maxCost: 2.5,
+        maxCost7d: 0,
+        maxCost30d: 0,
+      });
+    });
+  });
+
+  describe("parseLogPaths", () => {
+    it("splits and de-duplicates paths", () => {
+      expect(parseLogPaths("/a.log, /b/,/a.log,")).toEqual(["/a.log", "/b/"]);
+      expect(parseLogPaths(undefined)).toEqual([]);
+    });
+  });
+
+  describe("parseRunUsage", () => {
+    it("reads tokens and cost from usage.json", () => {
+      expect(parseRunUsage(JSON.stringify({ run_id: 1, tokens: 10, cost: 1.5 }))).toEqual({ tokens: 10, cost: 1.5 });
+      expect(parseRunUsage("{}")).toEqual({ tokens: 0, cost: 0 });
+    });
+
+    it("throws on corrupt content", () => {
+      expect(() => parseRunUsage("not json")).toThrow();
+      expect(() => parseRunUsage("null")).toThrow();
+    });
+  });
+
+  describe("sumCostSince", () => {
+    it("sums cost by window", () => {
+      const now = new Date("2025-06-30T12:00:00Z");
+      const daysAgo = days => new Date(now.getTime() - days * 24 * 60 * 60 * 1000).toISOString();
+      const runs = [
+        { created_at: daysAgo(1), cost: 1 },
+        { created_at: daysAgo(10), cost: 2 },
+        { created_at: daysAgo(40), cost: 4 },
+      ];
+      expect(sumCostSince(runs, 7, now)).toBe(1);
+      expect(sumCostSince(runs, 30, now)).toBe(3);
+    });
+  });
+});
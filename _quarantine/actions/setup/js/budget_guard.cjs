@@ -0,0 +1,183 @@
+// @ts-check
+
+/**
+ * Budget guard
+ *
+ * Runs in the background while the agent executes and stops the agent as soon as the
+ * run crosses the per-run token or cost limit configured in the workflow's `budget:`
+ * section. Started by start_budget_guard.sh before agent execution and stopped by
+ * record_budget_usage.cjs once the agent has finished.
+ *
+ * The agent process tree is located through the `tee <agent log>` process that every
+ * engine execution step pipes its output into: the agent commands are the other
+ * descendants of the step shell that owns that tee.
+ */
+
+const fs = require("fs");
+const path = require("path");
+const { execFileSync } = require("child_process");
+const { BUDGET_DIR, readBudgetLimits, parseLogPaths, extractUsageFromPaths, describeRunLimitExceeded } = require("./budget.cjs");
+
+const POLL_INTERVAL_MS = 5000;
+const DEFAULT_AGENT_LOG = "/tmp/gh-aw/agent-stdio.log";
+
+/**
+ * @param {string} message
+ */
+function log(message) {
+  console.log(`[${new Date().toISOString()}] ${message}`);
+}
+
+/**
+ * Lists running processes by reading /proc
+ * @param {string} [procDir] - Path to the proc filesystem
+ * @returns {Array<{pid: number, ppid: number, cmdline: string}>}
+ */
+function listProcesses(procDir = "/proc") {
+  const processes = [];
+  for (const entry of fs.readdirSync(procDir)) {
+    if (!/^\d+$/.test(entry)) {
+      continue;
+    }
+    try {
+      const stat = fs.readFileSync(path.join(procDir, entry, "stat"), "utf8");
+      // The command name in field 2 may contain spaces, so parse after the closing paren
+      const fields = stat.slice(stat.lastIndexOf(")") + 2).split(" ");
+      const cmdline = fs.readFileSync(path.join(procDir, entry, "cmdline"), "utf8").split("\0").join(" ").trim();
+      processes.push({ pid: parseInt(entry, 10), ppid: parseInt(fields[1], 10), cmdline });
+    } catch {
+      // Process exited while we were reading it
+    }
+  }
+  return processes;
+}
+
+/**
+ * Finds the agent processes of the engine execution step
+ * @param {Array<{pid: number, ppid: number, cmdline: string}>} processes - Running processes
+ * @param {string} agentLog - Agent stdio log that the execution step tees into
+ * @param {number} [selfPid] - PID of the guard, never returned
+ * @returns {number[]} PIDs of the agent processes
+ */
+function findAgentProcesses(processes, agentLog, selfPid = process.pid) {
+  const isTee = (/** @type {{cmdline: string}} */ p) => /(^|\/)tee(\s|$)/.test(p.cmdline);
+  const tees = processes.filter(p => isTee(p) && p.cmdline.includes(agentLog));
+  if (tees.length === 0) {
+    return [];
+  }
+
+  /** @type {Map<number, number[]>} */
+  const children = new Map();
+  for (const p of processes) {
+    const list = children.get(p.ppid) || [];
+    list.push(p.pid);
+    children.set(p.ppid, list);
+  }
+  const teePids = new Set(tees.map(t => t.pid));
+
+  const result = [];
+  const queue = [...new Set(tees.map(t => t.ppid))];
+  const seen = new Set(queue);
+  while (queue.length > 0) {
+    const parent = /** @type {number} */ (queue.shift());
+    for (const child of children.get(parent) || []) {
+      if (seen.has(child) || teePids.has(child) || child === selfPid) {
+        continue;
+      }
+      seen.add(child);
+      result.push(child);
+      queue.push(child);
+    }
+  }
+  return result;
+}
+
+/**
+ * Sends SIGTERM to the given processes, retrying through sudo for processes owned by root
+ * (e.g. the firewall wrapper)
+ * @param {number[]} pids - Processes to stop
+ */
+function stopProcesses(pids) {
+  const denied = [];
+  for (const pid of pids) {
+    try {
+      process.kill(pid, "SIGTERM");
+    } catch (error) {
+      if (error && /** @type {NodeJS.ErrnoException} */ (error).code === "EPERM") {
+        denied.push(pid);
+      }
+    }
+  }
+  if (denied.length > 0) {
+    try {
+      execFileSync("sudo", ["-n", "kill", "-TERM", ...denied.map(String)], { stdio: "ignore" });
+    } catch (error) {
+      log(`Failed to stop processes ${denied.join(", ")}: ${error instanceof Error ? error.message : String(error)}`);
+    }
+  }
+}
+
+/**
+ * Checks usage once and stops the agent when a per-run limit is exceeded
+ * @param {{logPaths: string[], agentLog: string, limits: {maxTokens: number, maxCost: number}, budgetDir?: string}} options
+ * @returns {boolean} True when the budget was exceeded and the agent was stopped
+ */
+function checkBudget(options) {
+  const usage = extractUsageFromPaths(options.logPaths);
+  const reason = describeRunLimitExceeded(usage, options.limits);
+  if (!reason) {
+    return false;
+  }
+
+  const budgetDir = options.budgetDir || BUDGET_DIR;
+  fs.mkdirSync(budgetDir, { recursive: true });
+  fs.writeFileSync(
+    path.join(budgetDir, "exceeded.json"),
+    JSON.stringify(
+      {
+        reason,
+        tokens: usage.tokens,
+        cost: usage.cost,
+        max_tokens: options.limits.maxTokens || undefined,
+        max_cost: options.limits.maxCost || undefined,
+        stopped_at: new Date().toISOString(),
+      },
+      null,
+      2
+    )
+  );
+
+  const pids = findAgentProcesses(listProcesses(), options.agentLog);
+  log(`Budget exceeded: ${reason}. Stopping agent processes: ${pids.join(", ") || "none found"}`);
+  stopProcesses(pids);
+  return true;
+}
+
+async function main() {
+  const limits = readBudgetLimits();
+  if (limits.maxTokens <= 0 && limits.maxCost <= 0) {
+    log("No per-run budget configured, nothing to guard");
+    return;
+  }
+
+  const agentLog = process.env.GH_AW_BUDGET_AGENT_LOG || DEFAULT_AGENT_LOG;
+  const logPaths = parseLogPaths(process.env.GH_AW_BUDGET_LOG_PATHS || agentLog);
+  log(`Guarding per-run budget (max-tokens: ${limits.maxTokens || "-"}, max-cost: ${limits.maxCost || "-"}) using ${logPaths.join(", ")}`);
+
+  for (;;) {
+    await new Promise(resolve => setTimeout(resolve, POLL_INTERVAL_MS));
+    try {
+      if (checkBudget({ logPaths, agentLog, limits })) {
+        return;
+      }
+    } catch (error) {
+      log(`Budget check failed: ${error instanceof Error ? error.message : String(error)}`);
+    }
+  }
+}
+
+if (require.main === module) {
+  main();
+}
+
+module.exports = { main, listProcesses, findAgentProcesses, stopProcesses, checkBudget };
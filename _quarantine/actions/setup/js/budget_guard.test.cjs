@@ -0,0 +1,82 @@
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+import fs from "fs";
+import os from "os";
+import path from "path";
+
+const { findAgentProcesses, listProcesses, checkBudget } = require("./budget_guard.cjs");
+
+describe("budget_guard.cjs", () => {
+  describe("findAgentProcesses", () => {
+    const processes = [
+      { pid: 100, ppid: 1, cmdline: "bash /home/runner/work/_temp/step.sh" },
+      { pid: 101, ppid: 100, cmdline: "sudo -E awf --allow-domains example.com -- claude --print" },
+      { pid: 102, ppid: 101, cmdline: "awf --allow-domains example.com -- claude --print" },
+      { pid: 103, ppid: 100, cmdline: "tee -a /tmp/gh-aw/agent-stdio.log" },
+      { pid: 200, ppid: 1, cmdline: "node /opt/gh-aw/actions/budget_guard.cjs" },
+      { pid: 300, ppid: 1, cmdline: "bash other.sh" },
+      { pid: 301, ppid: 300, cmdline: "sleep 100" },
+    ];
+
+    it("returns the agent process tree of the step that tees into the agent log", () => {
+      expect(findAgentProcesses(processes, "/tmp/gh-aw/agent-stdio.log", 200)).toEqual([101, 102]);
+    });
+
+    it("returns nothing when the agent is not running", () => {
+      expect(findAgentProcesses(processes, "/tmp/gh-aw/other.log", 200)).toEqual([]);
+    });
+
+    it("matches tee without -a", () => {
+      const codex = [
+        { pid: 10, ppid: 1, cmdline: "bash step.sh" },
+        { pid: 11, ppid: 10, cmdline: "codex exec" },
+        { pid: 12, ppid: 10, cmdline: "/usr/bin/tee /tmp/gh-aw/agent-stdio.log" },
+      ];
+      expect(findAgentProcesses(codex, "/tmp/gh-aw/agent-stdio.log", 99)).toEqual([11]);
+    });
+  });
+
+  describe("listProcesses", () => {
+    it("parses a proc filesystem", () => {
+      const procDir = fs.mkdtempSync(path.join(os.tmpdir(), "budget-proc-"));
+      try {
+        fs.mkdirSync(path.join(procDir, "42"));
+        fs.writeFileSync(path.join(procDir, "42", "stat"), "42 (tee with space) S 7 42 42 0 -1");
+        fs.writeFileSync(path.join(procDir, "42", "cmdline"), "tee\0-a\0/tmp/gh-aw/agent-stdio.log\0");
+        fs.mkdirSync(path.join(procDir, "self"));
+        expect(listProcesses(procDir)).toEqual([{ pid: 42, ppid: 7, cmdline: "tee -a /tmp/gh-aw/agent-stdio.log" }]);
+      } finally {
+        fs.rmSync(procDir, { recursive: true, force: true });
+      }
+    });
+  });
+
+  describe("checkBudget", () => {
+    let tmpDir;
+
+    beforeEach(() => {
+      tmpDir = fs.mkdtempSync(path.join(os.tmpdir(), "budget-guard-"));
+      vi.spyOn(console, "log").mockImplementation(() => {});
+    });
+
+    afterEach(() => {
+      vi.restoreAllMocks();
+      fs.rmSync(tmpDir, { recursive: true, force: true });
+    });
+
+    it("does nothing within the budget", () => {
+      const logFile = path.join(tmpDir, "agent.log");
+      fs.writeFileSync(logFile, "tokens used: 100\n");
+      expect(checkBudget({ logPaths: [logFile], agentLog: logFile, limits: { maxTokens: 1000, maxCost: 0 }, budgetDir: tmpDir })).toBe(false);
+      expect(fs.existsSync(path.join(tmpDir, "exceeded.json"))).toBe(false);
+    });
+
+    it("records the exceeded limit", () => {
+      const logFile = path.join(tmpDir, "agent-not-running.log");
+      fs.writeFileSync(logFile, "tokens used: 5000\n");
+      expect(checkBudget({ logPaths: [logFile], agentLog: logFile, limits: { maxTokens: 1000, maxCost: 0 }, budgetDir: tmpDir })).toBe(true);
+      const exceeded = JSON.parse(fs.readFileSync(path.join(tmpDir, "exceeded.json"), "utf8"));
+      expect(exceeded.reason).toContain("max-tokens 1000");
+      expect(exceeded.tokens).toBe(5000);
+    });
+  });
+});
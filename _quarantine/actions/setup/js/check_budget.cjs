@@ -0,0 +1,109 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+const fs = require("fs");
+const os = require("os");
+const path = require("path");
+const { readBudgetLimits, parseRunUsage, sumCostSince, USAGE_ARTIFACT_NAME, DAY_MS } = require("./budget.cjs");
+const { getErrorMessage } = require("./error_helpers.cjs");
+
+/**
+ * Downloads a usage artifact and returns the usage.json it contains
+ * @param {number} artifactId - Artifact ID
+ * @param {string} workDir - Directory to download the archive to
+ * @returns {Promise<{tokens: number, cost: number}>}
+ */
+async function downloadRunUsage(artifactId, workDir) {
+  const { owner, repo } = context.repo;
+  const response = await github.rest.actions.downloadArtifact({ owner, repo, artifact_id: artifactId, archive_format: "zip" });
+  const archive = path.join(workDir, `${artifactId}.zip`);
+  fs.writeFileSync(archive, Buffer.from(/** @type {ArrayBuffer} */ (response.data)));
+  const { stdout } = await exec.getExecOutput("unzip", ["-p", archive, "usage.json"], { silent: true });
+  return parseRunUsage(stdout);
+}
+
+/**
+ * Lists the cost of the runs of this workflow created within the last number of days,
+ * read from the usage artifact each run uploads. Runs without a usage artifact (still
+ * in progress, or stopped before recording usage) cost nothing.
+ * @param {number} days - Window size in days
+ * @returns {Promise<Array<{run_id: number, created_at: string, cost: number}>>}
+ */
+async function listRecentRunCosts(days) {
+  const { owner, repo } = context.repo;
+  const { data: currentRun } = await github.rest.actions.getWorkflowRun({ owner, repo, run_id: context.runId });
+  const since = new Date(Date.now() - days * DAY_MS).toISOString();
+  const runs = await github.paginate(github.rest.actions.listWorkflowRuns, {
+    owner,
+    repo,
+    workflow_id: currentRun.workflow_id,
+    created: `>=${since}`,
+    per_page: 100,
+  });
+
+  const workDir = fs.mkdtempSync(path.join(os.tmpdir(), "gh-aw-budget-"));
+  const costs = [];
+  for (const run of runs) {
+    if (run.id === context.runId) {
+      continue;
+    }
+    const { data } = await github.rest.actions.listWorkflowRunArtifacts({ owner, repo, run_id: run.id, name: USAGE_ARTIFACT_NAME });
+    // Re-run attempts upload a new artifact; the most recent one holds the latest usage
+    const artifact = data.artifacts.sort((a, b) => String(b.created_at).localeCompare(String(a.created_at)))[0];
+    if (!artifact) {
+      continue;
+    }
+    if (artifact.expired) {
+      throw new Error(`usage artifact of run ${run.id} has expired; artifact retention must cover the ${days} day budget window`);
+    }
+    const usage = await downloadRunUsage(artifact.id, workDir);
+    costs.push({ run_id: run.id, created_at: run.created_at, cost: usage.cost });
+  }
+  return costs;
+}
+
+async function main() {
+  const workflowName = process.env.GH_AW_WORKFLOW_NAME;
+
+  if (!workflowName) {
+    core.setFailed("Configuration error: GH_AW_WORKFLOW_NAME not specified.");
+    return;
+  }
+
+  const limits = readBudgetLimits();
+  const windows = [
+    { days: 7, limit: limits.maxCost7d, key: "max-cost-7d" },
+    { days: 30, limit: limits.maxCost30d, key: "max-cost-30d" },
+  ].filter(window => window.limit > 0);
+  if (windows.length === 0) {
+    core.info("No rolling cost budget configured, workflow can proceed");
+    core.setOutput("budget_ok", "true");
+    return;
+  }
+
+  let runs;
+  try {
+    runs = await listRecentRunCosts(Math.max(...windows.map(window => window.days)));
+  } catch (error) {
+    // Fail closed: a budget that cannot be checked is treated as exhausted
+    core.error(`💸 Failed to read the cost of recent runs: ${getErrorMessage(error)}. Workflow execution will be prevented by activation job.`);
+    core.setOutput("budget_ok", "false");
+    return;
+  }
+  core.info(`Checking rolling cost budget for ${workflowName} (${runs.length} recorded runs)`);
+
+  for (const window of windows) {
+    const spent = sumCostSince(runs, window.days);
+    core.info(`Cost in the last ${window.days} days: $${spent.toFixed(3)} (${window.key}: $${window.limit})`);
+    if (spent >= window.limit) {
+      core.warning(`💸 Budget exhausted: $${spent.toFixed(3)} spent in the last ${window.days} days (${window.key}: $${window.limit}). Workflow execution will be prevented by activation job.`);
+      core.setOutput("budget_ok", "false");
+      return;
+    }
+  }
+
+  core.info("✓ Rolling cost budget not exhausted, workflow can proceed");
+  core.setOutput("budget_ok", "true");
+}
+
+module.exports = { main, listRecentRunCosts };
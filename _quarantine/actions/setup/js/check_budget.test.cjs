@@ -0,0 +1,129 @@
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+
+const mockCore = {
+  info: vi.fn(),
+  warning: vi.fn(),
+  error: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+};
+const mockGithub = {
+  paginate: vi.fn(),
+  rest: {
+    actions: {
+      getWorkflowRun: vi.fn(),
+      listWorkflowRuns: vi.fn(),
+      listWorkflowRunArtifacts: vi.fn(),
+      downloadArtifact: vi.fn(),
+    },
+  },
+};
+const mockExec = {
+  getExecOutput: vi.fn(),
+};
+global.core = mockCore;
+global.github = mockGithub;
+global.exec = mockExec;
+global.context = { runId: 100, repo: { owner: "octo", repo: "repo" } };
+
+const { main } = require("./check_budget.cjs");
+
+describe("check_budget.cjs", () => {
+  let originalEnv;
+
+  const daysAgo = days => new Date(Date.now() - days * 24 * 60 * 60 * 1000).toISOString();
+
+  // Each run records its cost in a usage artifact whose ID is the run ID
+  const mockRuns = runs => {
+    mockGithub.paginate.mockResolvedValue([{ id: 100, created_at: daysAgo(0) }, ...runs.map(run => ({ id: run.id, created_at: run.created_at }))]);
+    mockGithub.rest.actions.listWorkflowRunArtifacts.mockImplementation(async ({ run_id }) => {
+      const run = runs.find(r => r.id === run_id);
+      return { data: { artifacts: run && run.cost !== undefined ? [{ id: run_id, created_at: run.created_at, expired: !!run.expired }] : [] } };
+    });
+    mockExec.getExecOutput.mockImplementation(async (_cmd, args) => {
+      const id = parseInt(args[1].match(/(\d+)\.zip$/)[1], 10);
+      return { stdout: JSON.stringify({ run_id: id, cost: runs.find(r => r.id === id).cost }) };
+    });
+  };
+
+  beforeEach(() => {
+    vi.clearAllMocks();
+    originalEnv = { ...process.env };
+    process.env.GH_AW_WORKFLOW_NAME = "test-workflow";
+    delete process.env.GH_AW_BUDGET_MAX_COST_7D;
+    delete process.env.GH_AW_BUDGET_MAX_COST_30D;
+    mockGithub.rest.actions.getWorkflowRun.mockResolvedValue({ data: { workflow_id: 42 } });
+    mockGithub.rest.actions.downloadArtifact.mockResolvedValue({ data: new ArrayBuffer(0) });
+  });
+
+  afterEach(() => {
+    process.env = originalEnv;
+  });
+
+  it("fails when the workflow name is missing", async () => {
+    delete process.env.GH_AW_WORKFLOW_NAME;
+    await main();
+    expect(mockCore.setFailed).toHaveBeenCalledWith(expect.stringContaining("GH_AW_WORKFLOW_NAME not specified"));
+    expect(mockCore.setOutput).not.toHaveBeenCalled();
+  });
+
+  it("allows the run when there are no previous runs", async () => {
+    process.env.GH_AW_BUDGET_MAX_COST_7D = "10";
+    mockRuns([]);
+    await main();
+    expect(mockGithub.paginate).toHaveBeenCalledWith(mockGithub.rest.actions.listWorkflowRuns, expect.objectContaining({ workflow_id: 42 }));
+    expect(mockCore.setOutput).toHaveBeenCalledWith("budget_ok", "true");
+  });
+
+  it("allows the run when recent cost is below the limits", async () => {
+    process.env.GH_AW_BUDGET_MAX_COST_7D = "10";
+    process.env.GH_AW_BUDGET_MAX_COST_30D = "50";
+    mockRuns([
+      { id: 1, created_at: daysAgo(1), cost: 4 },
+      { id: 2, created_at: daysAgo(20), cost: 30 },
+      { id: 3, created_at: daysAgo(2) },
+    ]);
+    await main();
+    expect(mockCore.setOutput).toHaveBeenCalledWith("budget_ok", "true");
+    expect(mockCore.warning).not.toHaveBeenCalled();
+  });
+
+  it("skips the run when the 7 day budget is exhausted", async () => {
+    process.env.GH_AW_BUDGET_MAX_COST_7D = "10";
+    mockRuns([
+      { id: 1, created_at: daysAgo(1), cost: 6 },
+      { id: 2, created_at: daysAgo(2), cost: 5 },
+    ]);
+    await main();
+    expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("max-cost-7d"));
+    expect(mockCore.setOutput).toHaveBeenCalledWith("budget_ok", "false");
+  });
+
+  it("skips the run when the 30 day budget is exhausted", async () => {
+    process.env.GH_AW_BUDGET_MAX_COST_7D = "10";
+    process.env.GH_AW_BUDGET_MAX_COST_30D = "20";
+    mockRuns([
+      { id: 1, created_at: daysAgo(1), cost: 2 },
+      { id: 2, created_at: daysAgo(15), cost: 19 },
+    ]);
+    await main();
+    expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("max-cost-30d"));
+    expect(mockCore.setOutput).toHaveBeenCalledWith("budget_ok", "false");
+  });
+
+  it("fails closed when the runs cannot be listed", async () => {
+    process.env.GH_AW_BUDGET_MAX_COST_7D = "10";
+    mockGithub.paginate.mockRejectedValue(new Error("Resource not accessible by integration"));
+    await main();
+    expect(mockCore.error).toHaveBeenCalledWith(expect.stringContaining("Resource not accessible by integration"));
+    expect(mockCore.setOutput).toHaveBeenCalledWith("budget_ok", "false");
+  });
+
+  it("fails closed when a usage artifact has expired", async () => {
+    process.env.GH_AW_BUDGET_MAX_COST_7D = "10";
+    mockRuns([{ id: 1, created_at: daysAgo(1), cost: 1, expired: true }]);
+    await main();
+    expect(mockCore.error).toHaveBeenCalledWith(expect.stringContaining("has expired"));
+    expect(mockCore.setOutput).toHaveBeenCalledWith("budget_ok", "false");
+  });
+});
@@ -0,0 +1,129 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+// Checks the runtime guard of the cron expression that triggered a scheduled run.
+// Cron expressions are evaluated in UTC and cannot express daylight saving time,
+// "first monday of month" or "every 2 weeks", so the compiler emits cron expressions
+// that fire at least as often as needed and a guard that skips the extra runs.
+
+const DAY_MS = 24 * 60 * 60 * 1000;
+
+// Monday 1970-01-05, the week intervals are counted from
+const WEEK_EPOCH_MS = Date.UTC(1970, 0, 5);
+
+/**
+ * Returns the time a run of the cron expression was scheduled at: the latest time at or
+ * before now matching the cron's minute and hour (runs start with a delay).
+ * @param {string} cron - UTC cron expression with a fixed minute and hour
+ * @param {Date} now - Current time
+ * @returns {Date}
+ */
+function scheduledTime(cron, now) {
+  const [minuteField, hourField] = cron.trim().split(/\s+/);
+  const minute = Number(minuteField);
+  const hour = Number(hourField);
+  if (!Number.isInteger(minute) || !Number.isInteger(hour)) {
+    return now;
+  }
+  let scheduled = Date.UTC(now.getUTCFullYear(), now.getUTCMonth(), now.getUTCDate(), hour, minute);
+  if (scheduled > now.getTime()) {
+    scheduled -= DAY_MS;
+  }
+  return new Date(scheduled);
+}
+
+/**
+ * Returns the local date and time of an instant in a timezone, with the UTC offset in minutes
+ * @param {Date} date - The instant
+ * @param {string} timeZone - IANA timezone
+ * @returns {{year: number, month: number, day: number, offset: number}}
+ */
+function localTime(date, timeZone) {
+  const formatter = new Intl.DateTimeFormat("en-US", {
+    timeZone,
+    hourCycle: "h23",
+    year: "numeric",
+    month: "numeric",
+    day: "numeric",
+    hour: "numeric",
+    minute: "numeric",
+  });
+  /** @type {Record<string, number>} */
+  const parts = {};
+  for (const part of formatter.formatToParts(date)) {
+    if (part.type !== "literal") {
+      parts[part.type] = Number(part.value);
+    }
+  }
+  const localMs = Date.UTC(parts.year, parts.month - 1, parts.day, parts.hour, parts.minute);
+  const instantMs = Math.floor(date.getTime() / 60000) * 60000;
+  return { year: parts.year, month: parts.month, day: parts.day, offset: Math.round((localMs - instantMs) / 60000) };
+}
+
+/**
+ * Reports whether a run scheduled at the given time satisfies a guard
+ * @param {{timezone?: string, utc_offset?: number, nth?: number, every_weeks?: number}} guard
+ * @param {Date} scheduled - Time the run was scheduled at
+ * @returns {boolean}
+ */
+function guardAllows(guard, scheduled) {
+  const local = localTime(scheduled, guard.timezone || "UTC");
+
+  if (typeof guard.utc_offset === "number" && local.offset !== guard.utc_offset) {
+    return false;
+  }
+  if (guard.nth && guard.nth > 0 && Math.floor((local.day - 1) / 7) + 1 !== guard.nth) {
+    return false;
+  }
+  if (guard.nth === -1) {
+    const daysInMonth = new Date(Date.UTC(local.year, local.month, 0)).getUTCDate();
+    if (local.day + 7 <= daysInMonth) {
+      return false;
+    }
+  }
+  if (guard.every_weeks && guard.every_weeks > 1) {
+    const weeks = Math.floor((Date.UTC(local.year, local.month - 1, local.day) - WEEK_EPOCH_MS) / DAY_MS / 7);
+    if (weeks % guard.every_weeks !== 0) {
+      return false;
+    }
+  }
+  return true;
+}
+
+async function main() {
+  /** @type {Record<string, Array<{timezone?: string, utc_offset?: number, nth?: number, every_weeks?: number}>>} */
+  let guards;
+  try {
+    guards = JSON.parse(process.env.GH_AW_SCHEDULE_GUARDS || "{}");
+  } catch (error) {
+    core.setFailed(`Configuration error: GH_AW_SCHEDULE_GUARDS is not valid JSON: ${error instanceof Error ? error.message : String(error)}`);
+    return;
+  }
+
+  if (context.eventName !== "schedule") {
+    core.info(`Event ${context.eventName} is not a scheduled run, no schedule check needed`);
+    core.setOutput("schedule_ok", "true");
+    return;
+  }
+
+  const cron = context.payload && context.payload.schedule;
+  const cronGuards = cron ? guards[cron] : undefined;
+  if (!cronGuards || cronGuards.length === 0) {
+    core.info(`No schedule guard for cron '${cron}', workflow can proceed`);
+    core.setOutput("schedule_ok", "true");
+    return;
+  }
+
+  const scheduled = scheduledTime(cron, new Date());
+  core.info(`Checking schedule guard for cron '${cron}' (scheduled at ${scheduled.toISOString()})`);
+  if (cronGuards.some(guard => guardAllows(guard, scheduled))) {
+    core.info("✓ Scheduled run matches its schedule, workflow can proceed");
+    core.setOutput("schedule_ok", "true");
+    return;
+  }
+
+  core.info(`⏭ Scheduled run at ${scheduled.toISOString()} does not match its schedule (${JSON.stringify(cronGuards)}). Workflow execution will be skipped by activation job.`);
+  core.setOutput("schedule_ok", "false");
+}
+
+module.exports = { main, scheduledTime, guardAllows };
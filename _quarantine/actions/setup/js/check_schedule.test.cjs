@@ -0,0 +1,104 @@
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+
+const mockCore = {
+  info: vi.fn(),
+  warning: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+};
+global.core = mockCore;
+
+const { main, scheduledTime, guardAllows } = require("./check_schedule.cjs");
+
+describe("check_schedule.cjs", () => {
+  let originalEnv;
+
+  beforeEach(() => {
+    vi.clearAllMocks();
+    originalEnv = { ...process.env };
+    global.context = { eventName: "schedule", payload: { schedule: "44 6 * 1-3,10-12 1-5" } };
+    process.env.GH_AW_SCHEDULE_GUARDS = JSON.stringify({
+      "44 6 * 1-3,10-12 1-5": [{ timezone: "Europe/Kyiv", utc_offset: 120 }],
+      "44 5 * 3-10 1-5": [{ timezone: "Europe/Kyiv", utc_offset: 180 }],
+    });
+  });
+
+  afterEach(() => {
+    process.env = originalEnv;
+    vi.useRealTimers();
+  });
+
+  describe("scheduledTime", () => {
+    it("returns the cron time of the current day when it has passed", () => {
+      expect(scheduledTime("44 6 * * *", new Date("2026-01-12T07:05:00Z")).toISOString()).toBe("2026-01-12T06:44:00.000Z");
+    });
+
+    it("returns the cron time of the previous day for runs delayed past midnight", () => {
+      expect(scheduledTime("50 23 * * *", new Date("2026-01-12T00:10:00Z")).toISOString()).toBe("2026-01-11T23:50:00.000Z");
+    });
+  });
+
+  describe("guardAllows", () => {
+    it("checks the UTC offset of the timezone", () => {
+      const guard = { timezone: "Europe/Kyiv", utc_offset: 120 };
+      expect(guardAllows(guard, new Date("2026-01-12T06:44:00Z"))).toBe(true);
+      expect(guardAllows(guard, new Date("2026-07-13T06:44:00Z"))).toBe(false);
+      expect(guardAllows({ timezone: "Europe/London", utc_offset: 0 }, new Date("2026-07-13T06:44:00Z"))).toBe(false);
+    });
+
+    it("checks the week of the month", () => {
+      // Mondays of March 2026: 2, 9, 16, 23, 30
+      expect(guardAllows({ nth: 1 }, new Date("2026-03-02T09:00:00Z"))).toBe(true);
+      expect(guardAllows({ nth: 1 }, new Date("2026-03-09T09:00:00Z"))).toBe(false);
+      expect(guardAllows({ nth: -1 }, new Date("2026-03-30T09:00:00Z"))).toBe(true);
+      expect(guardAllows({ nth: -1 }, new Date("2026-03-23T09:00:00Z"))).toBe(false);
+    });
+
+    it("checks the week of the month in the local date", () => {
+      // 23:30 UTC on Sunday March 1st is Monday March 2nd in Kyiv
+      expect(guardAllows({ timezone: "Europe/Kyiv", nth: 1 }, new Date("2026-03-01T23:30:00Z"))).toBe(true);
+      expect(guardAllows({ nth: 1 }, new Date("2026-03-01T23:30:00Z"))).toBe(true);
+      expect(guardAllows({ nth: -1 }, new Date("2026-03-01T23:30:00Z"))).toBe(false);
+    });
+
+    it("checks the week interval", () => {
+      // Friday 2026-01-09 is in an even week counted from Monday 1970-01-05
+      expect(guardAllows({ every_weeks: 2 }, new Date("2026-01-02T09:00:00Z"))).toBe(false);
+      expect(guardAllows({ every_weeks: 2 }, new Date("2026-01-09T09:00:00Z"))).toBe(true);
+      expect(guardAllows({ every_weeks: 2 }, new Date("2026-01-16T09:00:00Z"))).toBe(false);
+    });
+  });
+
+  it("allows runs of other events", async () => {
+    global.context = { eventName: "workflow_dispatch", payload: {} };
+    await main();
+    expect(mockCore.setOutput).toHaveBeenCalledWith("schedule_ok", "true");
+  });
+
+  it("allows scheduled runs of cron expressions without a guard", async () => {
+    global.context = { eventName: "schedule", payload: { schedule: "0 9 * * *" } };
+    await main();
+    expect(mockCore.setOutput).toHaveBeenCalledWith("schedule_ok", "true");
+  });
+
+  it("allows the run of the cron expression matching the current UTC offset", async () => {
+    vi.useFakeTimers();
+    vi.setSystemTime(new Date("2026-01-12T06:51:00Z"));
+    await main();
+    expect(mockCore.setOutput).toHaveBeenCalledWith("schedule_ok", "true");
+  });
+
+  it("skips the run of the cron expression for the other UTC offset", async () => {
+    vi.useFakeTimers();
+    vi.setSystemTime(new Date("2026-03-31T06:51:00Z"));
+    await main();
+    expect(mockCore.setOutput).toHaveBeenCalledWith("schedule_ok", "false");
+  });
+
+  it("fails on invalid guard configuration", async () => {
+    process.env.GH_AW_SCHEDULE_GUARDS = "{not json";
+    await main();
+    expect(mockCore.setFailed).toHaveBeenCalledWith(expect.stringContaining("GH_AW_SCHEDULE_GUARDS is not valid JSON"));
+    expect(mockCore.setOutput).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,197 @@
+// @ts-check
+
+/**
+ * JSON Schema Validator
+ *
+ * A small, dependency-free JSON Schema validator for the scripts copied to the runner,
+ * which cannot install packages. It implements the subset of keywords that the compiler
+ * accepts for repo-memory schemas (see pkg/workflow/repo_memory_schema.go); keeping the
+ * two lists in sync guarantees that no keyword is silently ignored at runtime.
+ *
+ * Supported keywords: type, enum, const, properties, required, additionalProperties,
+ * items, minItems, maxItems, uniqueItems, minLength, maxLength, pattern, minimum,
+ * maximum, exclusiveMinimum, exclusiveMaximum, anyOf, oneOf, allOf, not, and local
+ * $ref pointers into $defs or definitions.
+ */
+
+/**
+ * @param {unknown} value
+ * @returns {string} JSON Schema type name of the value
+ */
+function jsonType(value) {
+  if (value === null) return "null";
+  if (Array.isArray(value)) return "array";
+  if (typeof value === "number") return Number.isInteger(value) ? "integer" : "number";
+  return typeof value;
+}
+
+/**
+ * @param {string} expected - JSON Schema type name
+ * @param {unknown} value
+ * @returns {boolean}
+ */
+function matchesType(expected, value) {
+  const actual = jsonType(value);
+  return actual === expected || (expected === "number" && actual === "integer");
+}
+
+/**
+ * Deep equality for JSON values
+ * @param {unknown} a
+ * @param {unknown} b
+ * @returns {boolean}
+ */
+function jsonEqual(a, b) {
+  if (a === b) return true;
+  if (jsonType(a) !== jsonType(b) || typeof a !== "object" || a === null || b === null) return false;
+  if (Array.isArray(a)) {
+    const other = /** @type {unknown[]} */ (b);
+    return a.length === other.length && a.every((item, i) => jsonEqual(item, other[i]));
+  }
+  const aObj = /** @type {Record<string, unknown>} */ (a);
+  const bObj = /** @type {Record<string, unknown>} */ (b);
+  const keys = Object.keys(aObj);
+  return keys.length === Object.keys(bObj).length && keys.every(key => key in bObj && jsonEqual(aObj[key], bObj[key]));
+}
+
+/**
+ * Resolve a local $ref such as "#/$defs/entry"
+ * @param {any} root - Root schema
+ * @param {string} ref
+ * @returns {any}
+ */
+function resolveRef(root, ref) {
+  if (!ref.startsWith("#")) {
+    throw new Error(`Unsupported $ref "${ref}": only local references are supported`);
+  }
+  let target = root;
+  for (const part of ref.slice(1).split("/").filter(Boolean)) {
+    const key = decodeURIComponent(part).replace(/~1/g, "/").replace(/~0/g, "~");
+    if (target === null || typeof target !== "object" || !(key in target)) {
+      throw new Error(`Unresolvable $ref "${ref}"`);
+    }
+    target = target[key];
+  }
+  return target;
+}
+
+/**
+ * Validate a value against a JSON Schema
+ * @param {any} schema - JSON Schema (object or boolean)
+ * @param {unknown} value - Value to validate
+ * @param {string} [path] - JSON pointer of the value, used in error messages
+ * @param {any} [root] - Root schema for $ref resolution
+ * @returns {string[]} Validation errors (empty when valid)
+ */
+function validateJSONSchema(schema, value, path = "", root = schema) {
+  const at = path || "/";
+  if (schema === true || schema === undefined) return [];
+  if (schema === false) return [`${at}: no value is allowed here`];
+
+  if (typeof schema.$ref === "string") {
+    return validateJSONSchema(resolveRef(root, schema.$ref), value, path, root);
+  }
+
+  /** @type {string[]} */
+  const errors = [];
+
+  if (schema.type !== undefined) {
+    const types = Array.isArray(schema.type) ? schema.type : [schema.type];
+    if (!types.some(/** @param {string} t */ t => matchesType(t, value))) {
+      return [`${at}: expected ${types.join(" or ")}, got ${jsonType(value)}`];
+    }
+  }
+  if (Array.isArray(schema.enum) && !schema.enum.some(/** @param {unknown} v */ v => jsonEqual(v, value))) {
+    errors.push(`${at}: must be one of ${schema.enum.map(/** @param {unknown} v */ v => JSON.stringify(v)).join(", ")}`);
+  }
+  if ("const" in schema && !jsonEqual(schema.const, value)) {
+    errors.push(`${at}: must be ${JSON.stringify(schema.const)}`);
+  }
+
+  if (typeof value === "string") {
+    const length = [...value].length;
+    if (typeof schema.minLength === "number" && length < schema.minLength) {
+      errors.push(`${at}: must be at least ${schema.minLength} characters`);
+    }
+    if (typeof schema.maxLength === "number" && length > schema.maxLength) {
+      errors.push(`${at}: must be at most ${schema.maxLength} characters`);
+    }
+    if (typeof schema.pattern === "string" && !new RegExp(schema.pattern, "u").test(value)) {
+      errors.push(`${at}: must match pattern ${schema.pattern}`);
+    }
+  }
+
+  if (typeof value === "number") {
+    if (typeof schema.minimum === "number" && value < schema.minimum) {
+      errors.push(`${at}: must be >= ${schema.minimum}`);
+    }
+    if (typeof schema.maximum === "number" && value > schema.maximum) {
+      errors.push(`${at}: must be <= ${schema.maximum}`);
+    }
+    if (typeof schema.exclusiveMinimum === "number" && value <= schema.exclusiveMinimum) {
+      errors.push(`${at}: must be > ${schema.exclusiveMinimum}`);
+    }
+    if (typeof schema.exclusiveMaximum === "number" && value >= schema.exclusiveMaximum) {
+      errors.push(`${at}: must be < ${schema.exclusiveMaximum}`);
+    }
+  }
+
+  if (Array.isArray(value)) {
+    if (typeof schema.minItems === "number" && value.length < schema.minItems) {
+      errors.push(`${at}: must have at least ${schema.minItems} items`);
+    }
+    if (typeof schema.maxItems === "number" && value.length > schema.maxItems) {
+      errors.push(`${at}: must have at most ${schema.maxItems} items`);
+    }
+    if (schema.uniqueItems === true && value.some((item, i) => value.findIndex(other => jsonEqual(other, item)) !== i)) {
+      errors.push(`${at}: items must be unique`);
+    }
+    if (schema.items !== undefined) {
+      value.forEach((item, i) => errors.push(...validateJSONSchema(schema.items, item, `${path}/${i}`, root)));
+    }
+  }
+
+  if (jsonType(value) === "object") {
+    const obj = /** @type {Record<string, unknown>} */ (value);
+    const properties = schema.properties || {};
+    for (const name of Array.isArray(schema.required) ? schema.required : []) {
+      if (!(name in obj)) {
+        errors.push(`${at}: missing required property "${name}"`);
+      }
+    }
+    for (const [name, propValue] of Object.entries(obj)) {
+      if (name in properties) {
+        errors.push(...validateJSONSchema(properties[name], propValue, `${path}/${name}`, root));
+      } else if (schema.additionalProperties === false) {
+        errors.push(`${at}: unexpected property "${name}"`);
+      } else if (schema.additionalProperties !== undefined) {
+        errors.push(...validateJSONSchema(schema.additionalProperties, propValue, `${path}/${name}`, root));
+      }
+    }
+  }
+
+  if (Array.isArray(schema.allOf)) {
+    for (const sub of schema.allOf) {
+      errors.push(...validateJSONSchema(sub, value, path, root));
+    }
+  }
+  if (Array.isArray(schema.anyOf) && !schema.anyOf.some(/** @param {any} sub */ sub => validateJSONSchema(sub, value, path, root).length === 0)) {
+    errors.push(`${at}: must match at least one schema in anyOf`);
+  }
+  if (Array.isArray(schema.oneOf)) {
+    const matches = schema.oneOf.filter(/** @param {any} sub */ sub => validateJSONSchema(sub, value, path, root).length === 0).length;
+    if (matches !== 1) {
+      errors.push(`${at}: must match exactly one schema in oneOf (matched ${matches})`);
+    }
+  }
+  if (schema.not !== undefined && validateJSONSchema(schema.not, value, path, root).length === 0) {
+    errors.push(`${at}: must not match the schema in not`);
+  }
+
+  return errors;
+}
+
+module.exports = {
+  validateJSONSchema,
+  jsonEqual,
+};
@@ -0,0 +1,74 @@
+import { describe, it, expect } from "vitest";
+import { validateJSONSchema, jsonEqual } from "./json_schema_validator.cjs";
+
+describe("json_schema_validator.cjs", () => {
+  describe("jsonEqual", () => {
+    it("should compare nested values structurally", () => {
+      expect(jsonEqual({ a: [1, { b: "x" }] }, { a: [1, { b: "x" }] })).toBe(true);
+      expect(jsonEqual({ a: 1 }, { a: 1, b: 2 })).toBe(false);
+      expect(jsonEqual([1, 2], [2, 1])).toBe(false);
+      expect(jsonEqual(null, {})).toBe(false);
+    });
+  });
+
+  describe("validateJSONSchema", () => {
+    const schema = {
+      type: "object",
+      required: ["status"],
+      additionalProperties: false,
+      properties: {
+        status: { enum: ["open", "closed"] },
+        count: { type: "integer", minimum: 0 },
+        labels: { type: "array", items: { type: "string", minLength: 1 }, uniqueItems: true, maxItems: 3 },
+        owner: { $ref: "#/$defs/login" },
+      },
+      $defs: {
+        login: { type: "string", pattern: "^[a-z0-9-]+$" },
+      },
+    };
+
+    it("should accept a valid document", () => {
+      expect(validateJSONSchema(schema, { status: "open", count: 2, labels: ["bug"], owner: "octocat" })).toEqual([]);
+    });
+
+    it("should report type, enum and required violations with JSON pointers", () => {
+      const errors = validateJSONSchema(schema, { count: 1.5, labels: ["", "a", "a"] });
+
+      expect(errors).toContain('/: missing required property "status"');
+      expect(errors).toContain("/count: expected integer, got number");
+      expect(errors).toContain("/labels: items must be unique");
+      expect(errors).toContain("/labels/0: must be at least 1 characters");
+    });
+
+    it("should reject unexpected properties when additionalProperties is false", () => {
+      expect(validateJSONSchema(schema, { status: "open", extra: true })).toEqual(['/: unexpected property "extra"']);
+    });
+
+    it("should resolve local references", () => {
+      expect(validateJSONSchema(schema, { status: "open", owner: "Not Valid" })).toEqual(["/owner: must match pattern ^[a-z0-9-]+$"]);
+    });
+
+    it("should throw on remote references", () => {
+      expect(() => validateJSONSchema({ $ref: "https://example.com/schema.json" }, {})).toThrow("only local references are supported");
+    });
+
+    it("should combine subschemas", () => {
+      const combined = {
+        anyOf: [{ type: "string" }, { type: "number" }],
+        not: { const: "forbidden" },
+      };
+      expect(validateJSONSchema(combined, "ok")).toEqual([]);
+      expect(validateJSONSchema(combined, true)).toEqual(["/: must match at least one schema in anyOf"]);
+      expect(validateJSONSchema(combined, "forbidden")).toEqual(["/: must not match the schema in not"]);
+
+      const exclusive = { oneOf: [{ type: "integer" }, { type: "number" }] };
+      expect(validateJSONSchema(exclusive, 1)).toEqual(["/: must match exactly one schema in oneOf (matched 2)"]);
+      expect(validateJSONSchema(exclusive, 1.5)).toEqual([]);
+    });
+
+    it("should support boolean schemas", () => {
+      expect(validateJSONSchema(true, { any: "thing" })).toEqual([]);
+      expect(validateJSONSchema({ properties: { x: false } }, { x: 1 })).toEqual(["/x: no value is allowed here"]);
+    });
+  });
+});
@@ -3,12 +3,29 @@
 /**
  * JavaScript Handler for Safe-Inputs
  *
- * This module provides a handler for executing JavaScript (.cjs) files in safe-inputs tools.
+ * This module provides a handler for executing JavaScript (.cjs) and TypeScript (.mts) files in
+ * safe-inputs tools; TypeScript runs with Node.js type stripping.
  * It executes JavaScript handlers in a separate Node.js process for isolation.
  */
 
 const { execFile } = require("child_process");
 
+/**
+ * First Node.js version supporting --experimental-strip-types, which runs TypeScript handlers
+ */
+const MIN_TYPE_STRIPPING_NODE_VERSION = "22.6";
+
+/**
+ * Check whether a Node.js version can run TypeScript handlers with type stripping
+ * @param {string} version - Node.js version, e.g. process.versions.node
+ * @returns {boolean} True if the version is 22.6 or later
+ */
+function supportsTypeStripping(version) {
+  const [major, minor] = version.replace(/^v/, "").split(".").map(Number);
+  const [minMajor, minMinor] = MIN_TYPE_STRIPPING_NODE_VERSION.split(".").map(Number);
+  return major > minMajor || (major === minMajor && minor >= minMinor);
+}
+
 /**
  * Create a JavaScript handler function that executes a .cjs file in a separate Node.js process.
  * Inputs are passed as JSON via stdin:
@@ -32,12 +49,22 @@ function createJavaScriptHandler(server, toolName, scriptPath, timeoutSeconds =
     const inputJson = JSON.stringify(args || {});
     server.debug(`  [${toolName}] Input JSON (${inputJson.length} bytes): ${inputJson.substring(0, 200)}${inputJson.length > 200 ? "..." : ""}`);
 
+    // TypeScript handlers are run with Node.js type stripping
+    const isTypeScript = /\.[cm]?ts$/.test(scriptPath);
+    if (isTypeScript && !supportsTypeStripping(process.versions.node)) {
+      const message = `TypeScript tool ${toolName} requires Node.js ${MIN_TYPE_STRIPPING_NODE_VERSION} or later for type stripping, but the safe-inputs server runs on Node.js ${process.versions.node}`;
+      server.debugError(`  [${toolName}] `, message);
+      return Promise.reject(new Error(message));
+    }
+
     return new Promise((resolve, reject) => {
       server.debug(`  [${toolName}] Executing JavaScript script in separate Node.js process...`);
 
+      const nodeArgs = isTypeScript ? ["--experimental-strip-types", scriptPath] : [scriptPath];
+
       const child = execFile(
         process.execPath, // Use the same Node.js binary as the current process
-        [scriptPath],
+        nodeArgs,
         {
           env: process.env,
           cwd: process.env.GITHUB_WORKSPACE || process.cwd(),
@@ -98,4 +125,5 @@ function createJavaScriptHandler(server, toolName, scriptPath, timeoutSeconds =
 
 module.exports = {
   createJavaScriptHandler,
+  supportsTypeStripping,
 };
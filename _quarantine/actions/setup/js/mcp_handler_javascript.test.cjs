@@ -1,7 +1,7 @@
 // @ts-check
 
 import { describe, it, expect, beforeEach, afterEach } from "vitest";
-import { createJavaScriptHandler } from "./mcp_handler_javascript.cjs";
+import { createJavaScriptHandler, supportsTypeStripping } from "./mcp_handler_javascript.cjs";
 import fs from "fs";
 import path from "path";
 import os from "os";
@@ -211,4 +211,45 @@ console.log(JSON.stringify(result));
       }
     }
   });
+
+  const supportsStripTypes = supportsTypeStripping(process.versions.node);
+
+  it.skipIf(!supportsStripTypes)("should execute a TypeScript module with type stripping", async () => {
+    testScriptPath = path.join(tempDir, "test.mts");
+    const tsCode = `
+interface Inputs {
+  name: string;
+}
+function execute(inputs: Inputs): { greeting: string } {
+  return { greeting: "Hello, " + inputs.name };
+}
+let input = "";
+process.stdin.on("data", chunk => (input += chunk));
+process.stdin.on("end", () => console.log(JSON.stringify(execute(JSON.parse(input)))));
+`;
+    fs.writeFileSync(testScriptPath, tsCode);
+
+    const handler = createJavaScriptHandler(mockServer, "ts-tool", testScriptPath, 60);
+    const result = await handler({ name: "World" });
+
+    const output = JSON.parse(result.content[0].text);
+    expect(output.greeting).toBe("Hello, World");
+  });
+
+  it.skipIf(supportsStripTypes)("should reject TypeScript modules on Node.js versions without type stripping", async () => {
+    testScriptPath = path.join(tempDir, "test.mts");
+    fs.writeFileSync(testScriptPath, "console.log(JSON.stringify({}));");
+
+    const handler = createJavaScriptHandler(mockServer, "ts-tool", testScriptPath, 60);
+    await expect(handler({})).rejects.toThrow("TypeScript tool ts-tool requires Node.js 22.6 or later");
+  });
+});
+
+describe("supportsTypeStripping", () => {
+  it("should require Node.js 22.6 or later", () => {
+    expect(supportsTypeStripping("20.19.5")).toBe(false);
+    expect(supportsTypeStripping("22.5.1")).toBe(false);
+    expect(supportsTypeStripping("22.6.0")).toBe(true);
+    expect(supportsTypeStripping("v24.1.0")).toBe(true);
+  });
 });
@@ -0,0 +1,219 @@
+// @ts-check
+
+/**
+ * MCP Recording Proxy
+ *
+ * This module records the MCP calls of an agent run for `gh aw mcp replay`. It runs on the
+ * host next to the MCP gateway when sandbox.mcp.record is enabled: the agent is pointed at the
+ * proxy, which forwards every request to the gateway unchanged, streams the response back and
+ * appends each JSON-RPC request with its result to <record dir>/<server>.jsonl.
+ *
+ * The recordings are written under /tmp/gh-aw/mcp-logs so the secret redaction step masks them
+ * before they are uploaded with the MCP logs artifact.
+ *
+ * Environment variables:
+ * - MCP_GATEWAY_PORT: Port of the MCP gateway to forward requests to
+ * - MCP_GATEWAY_RECORD_PORT: Port the proxy listens on
+ * - MCP_GATEWAY_RECORD_DIR: Directory the recordings are written to
+ */
+
+const fs = require("fs");
+const http = require("http");
+const path = require("path");
+
+/**
+ * Extracts the MCP server name from a gateway request path (/mcp/<server>)
+ * @param {string} url - Request URL
+ * @returns {string|null} Server name, or null for non-MCP endpoints such as /health
+ */
+function serverNameFromPath(url) {
+  const match = /^\/mcp\/([^/?#]+)/.exec(url || "");
+  if (!match) {
+    return null;
+  }
+  const name = decodeURIComponent(match[1]);
+  // The name is used as a file name
+  return /^[A-Za-z0-9._-]+$/.test(name) && !name.startsWith(".") ? name : null;
+}
+
+/**
+ * Parses JSON-RPC messages from a request or response body. Streamable HTTP responses
+ * may be sent as server-sent events with one JSON message per data field.
+ * @param {string} body - Body text
+ * @param {string} [contentType] - Content-Type header of the body
+ * @returns {any[]} Parsed JSON-RPC messages (batches are flattened)
+ */
+function parseMessages(body, contentType) {
+  /** @type {string[]} */
+  let payloads = [body];
+  if (contentType && contentType.includes("text/event-stream")) {
+    payloads = [];
+    for (const event of body.split(/\r?\n\r?\n/)) {
+      const data = event
+        .split(/\r?\n/)
+        .filter(line => line.startsWith("data:"))
+        .map(line => line.slice(5).replace(/^ /, ""))
+        .join("\n");
+      if (data) {
+        payloads.push(data);
+      }
+    }
+  }
+
+  const messages = [];
+  for (const payload of payloads) {
+    if (!payload.trim()) {
+      continue;
+    }
+    try {
+      const parsed = JSON.parse(payload);
+      messages.push(...(Array.isArray(parsed) ? parsed : [parsed]));
+    } catch {
+      // Not JSON-RPC (e.g. an HTML error page); nothing to record
+    }
+  }
+  return messages;
+}
+
+/**
+ * Pairs JSON-RPC requests with their responses and builds recording entries in the format
+ * read by `gh aw mcp replay`. Notifications (requests without an id) are not recorded.
+ * @param {string} serverName - MCP server the requests were sent to
+ * @param {any[]} requests - JSON-RPC requests
+ * @param {any[]} responses - JSON-RPC responses
+ * @param {string} timestamp - Time of the request
+ * @returns {Object[]} Recording entries
+ */
+function buildRecordEntries(serverName, requests, responses, timestamp) {
+  const responsesById = new Map();
+  for (const response of responses) {
+    if (response && response.id !== undefined && response.id !== null) {
+      responsesById.set(JSON.stringify(response.id), response);
+    }
+  }
+
+  const entries = [];
+  for (const request of requests) {
+    if (!request || typeof request.method !== "string" || request.id === undefined || request.id === null) {
+      continue;
+    }
+    /** @type {Record<string, any>} */
+    const entry = { timestamp, server_name: serverName, method: request.method };
+    if (request.method === "tools/call" && request.params && typeof request.params.name === "string") {
+      entry.tool_name = request.params.name;
+    }
+    if (request.params !== undefined) {
+      entry.request = request.params;
+    }
+    const response = responsesById.get(JSON.stringify(request.id));
+    if (response && response.error) {
+      entry.error = response.error.message || JSON.stringify(response.error);
+    } else if (response && response.result !== undefined) {
+      entry.response = response.result;
+    }
+    entries.push(entry);
+  }
+  return entries;
+}
+
+/**
+ * Appends recording entries to the recording file of a server
+ * @param {string} recordDir - Recordings directory
+ * @param {string} serverName - MCP server name
+ * @param {Object[]} entries - Recording entries
+ */
+function appendRecordEntries(recordDir, serverName, entries) {
+  if (entries.length === 0) {
+    return;
+  }
+  const lines = entries.map(entry => JSON.stringify(entry)).join("\n") + "\n";
+  fs.appendFileSync(path.join(recordDir, `${serverName}.jsonl`), lines);
+}
+
+/**
+ * Creates the recording proxy server
+ * @param {Object} options - Proxy options
+ * @param {number} options.upstreamPort - Port of the MCP gateway
+ * @param {string} options.recordDir - Recordings directory
+ * @param {string} [options.upstreamHost="127.0.0.1"] - Host of the MCP gateway
+ * @returns {http.Server} HTTP server (not listening yet)
+ */
+function createRecordingProxy({ upstreamPort, recordDir, upstreamHost = "127.0.0.1" }) {
+  return http.createServer((req, res) => {
+    /** @type {Buffer[]} */
+    const requestChunks = [];
+    req.on("data", chunk => requestChunks.push(chunk));
+    req.on("end", () => {
+      const requestBody = Buffer.concat(requestChunks);
+      const timestamp = new Date().toISOString();
+      const upstream = http.request(
+        {
+          host: upstreamHost,
+          port: upstreamPort,
+          method: req.method,
+          path: req.url,
+          headers: req.headers,
+        },
+        upstreamRes => {
+          res.writeHead(upstreamRes.statusCode || 502, upstreamRes.headers);
+          /** @type {Buffer[]} */
+          const responseChunks = [];
+          upstreamRes.on("data", chunk => {
+            responseChunks.push(chunk);
+            res.write(chunk);
+          });
+          upstreamRes.on("end", () => {
+            res.end();
+            const serverName = serverNameFromPath(req.url || "");
+            if (!serverName || req.method !== "POST") {
+              return;
+            }
+            try {
+              const requests = parseMessages(requestBody.toString("utf8"), req.headers["content-type"]);
+              const responses = parseMessages(Buffer.concat(responseChunks).toString("utf8"), upstreamRes.headers["content-type"]);
+              appendRecordEntries(recordDir, serverName, buildRecordEntries(serverName, requests, responses, timestamp));
+            } catch (error) {
+              console.error(`Failed to record MCP call to ${serverName}: ${error instanceof Error ? error.message : String(error)}`);
+            }
+          });
+        }
+      );
+      upstream.on("error", error => {
+        console.error(`Gateway request failed: ${error.message}`);
+        if (!res.headersSent) {
+          res.writeHead(502, { "Content-Type": "text/plain" });
+        }
+        res.end("MCP gateway unavailable");
+      });
+      upstream.end(requestBody);
+    });
+  });
+}
+
+function main() {
+  const upstreamPort = parseInt(process.env.MCP_GATEWAY_PORT || "", 10);
+  const listenPort = parseInt(process.env.MCP_GATEWAY_RECORD_PORT || "", 10);
+  const recordDir = process.env.MCP_GATEWAY_RECORD_DIR;
+  if (!upstreamPort || !listenPort || !recordDir) {
+    console.error("MCP_GATEWAY_PORT, MCP_GATEWAY_RECORD_PORT and MCP_GATEWAY_RECORD_DIR must be set");
+    process.exit(1);
+  }
+
+  fs.mkdirSync(recordDir, { recursive: true });
+  const server = createRecordingProxy({ upstreamPort, recordDir });
+  server.listen(listenPort, "0.0.0.0", () => {
+    console.log(`Recording MCP calls on port ${listenPort} (gateway port ${upstreamPort}) to ${recordDir}`);
+  });
+}
+
+if (require.main === module) {
+  main();
+}
+
+module.exports = {
+  serverNameFromPath,
+  parseMessages,
+  buildRecordEntries,
+  appendRecordEntries,
+  createRecordingProxy,
+};
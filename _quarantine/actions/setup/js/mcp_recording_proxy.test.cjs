@@ -0,0 +1,109 @@
+// @ts-check
+
+import { describe, it, expect, beforeEach, afterEach } from "vitest";
+import { serverNameFromPath, parseMessages, buildRecordEntries, createRecordingProxy } from "./mcp_recording_proxy.cjs";
+import fs from "fs";
+import http from "http";
+import path from "path";
+import os from "os";
+
+describe("serverNameFromPath", () => {
+  it("extracts the server name from gateway paths", () => {
+    expect(serverNameFromPath("/mcp/github")).toBe("github");
+    expect(serverNameFromPath("/mcp/safe-outputs?session=1")).toBe("safe-outputs");
+  });
+
+  it("ignores non-MCP endpoints and unsafe names", () => {
+    expect(serverNameFromPath("/health")).toBeNull();
+    expect(serverNameFromPath("/mcp/..")).toBeNull();
+    expect(serverNameFromPath("/mcp/a%2Fb")).toBeNull();
+  });
+});
+
+describe("parseMessages", () => {
+  it("parses JSON bodies and batches", () => {
+    expect(parseMessages('{"jsonrpc":"2.0","id":1}', "application/json")).toEqual([{ jsonrpc: "2.0", id: 1 }]);
+    expect(parseMessages('[{"id":1},{"id":2}]', "application/json")).toHaveLength(2);
+  });
+
+  it("parses server-sent event bodies", () => {
+    const body = 'event: message\ndata: {"id":1,"result":{}}\n\nevent: message\ndata: {"id":2,"result":{}}\n\n';
+    expect(parseMessages(body, "text/event-stream").map(m => m.id)).toEqual([1, 2]);
+  });
+
+  it("ignores bodies that are not JSON", () => {
+    expect(parseMessages("<html>Bad Gateway</html>", "text/html")).toEqual([]);
+  });
+});
+
+describe("buildRecordEntries", () => {
+  it("pairs requests with results and errors and skips notifications", () => {
+    const requests = [
+      { jsonrpc: "2.0", id: 1, method: "tools/call", params: { name: "get_issue", arguments: { number: 1 } } },
+      { jsonrpc: "2.0", id: 2, method: "tools/list" },
+      { jsonrpc: "2.0", method: "notifications/initialized" },
+    ];
+    const responses = [
+      { jsonrpc: "2.0", id: 2, error: { code: -32601, message: "not found" } },
+      { jsonrpc: "2.0", id: 1, result: { content: [{ type: "text", text: "issue" }] } },
+    ];
+
+    const entries = buildRecordEntries("github", requests, responses, "2026-01-01T00:00:00.000Z");
+
+    expect(entries).toEqual([
+      {
+        timestamp: "2026-01-01T00:00:00.000Z",
+        server_name: "github",
+        method: "tools/call",
+        tool_name: "get_issue",
+        request: { name: "get_issue", arguments: { number: 1 } },
+        response: { content: [{ type: "text", text: "issue" }] },
+      },
+      { timestamp: "2026-01-01T00:00:00.000Z", server_name: "github", method: "tools/list", error: "not found" },
+    ]);
+  });
+});
+
+describe("createRecordingProxy", () => {
+  let tempDir;
+  let upstream;
+  let proxy;
+
+  beforeEach(async () => {
+    tempDir = fs.mkdtempSync(path.join(os.tmpdir(), "mcp-recording-test-"));
+    upstream = http.createServer((req, res) => {
+      let body = "";
+      req.on("data", chunk => (body += chunk));
+      req.on("end", () => {
+        const request = JSON.parse(body);
+        res.writeHead(200, { "Content-Type": "application/json" });
+        res.end(JSON.stringify({ jsonrpc: "2.0", id: request.id, result: { echo: request.params } }));
+      });
+    });
+    await new Promise(resolve => upstream.listen(0, "127.0.0.1", resolve));
+    proxy = createRecordingProxy({ upstreamPort: upstream.address().port, recordDir: tempDir });
+    await new Promise(resolve => proxy.listen(0, "127.0.0.1", resolve));
+  });
+
+  afterEach(async () => {
+    await new Promise(resolve => proxy.close(resolve));
+    await new Promise(resolve => upstream.close(resolve));
+    fs.rmSync(tempDir, { recursive: true, force: true });
+  });
+
+  it("forwards requests to the gateway and records them per server", async () => {
+    const request = { jsonrpc: "2.0", id: 7, method: "tools/call", params: { name: "search", arguments: { q: "x" } } };
+    const response = await fetch(`http://127.0.0.1:${proxy.address().port}/mcp/github`, {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify(request),
+    });
+    expect(await response.json()).toEqual({ jsonrpc: "2.0", id: 7, result: { echo: request.params } });
+
+    // The recording is written once the response has been forwarded
+    await new Promise(resolve => setTimeout(resolve, 50));
+    const lines = fs.readFileSync(path.join(tempDir, "github.jsonl"), "utf8").trim().split("\n");
+    expect(lines).toHaveLength(1);
+    expect(JSON.parse(lines[0])).toMatchObject({ server_name: "github", method: "tools/call", tool_name: "search", response: { echo: request.params } });
+  });
+});
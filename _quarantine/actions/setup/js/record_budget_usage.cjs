@@ -0,0 +1,100 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+const fs = require("fs");
+const path = require("path");
+const { BUDGET_DIR, readBudgetLimits, parseLogPaths, extractUsageFromPaths, describeRunLimitExceeded } = require("./budget.cjs");
+const { getErrorMessage } = require("./error_helpers.cjs");
+
+/**
+ * Stops the background budget guard started before agent execution
+ * @param {string} budgetDir - Budget state directory
+ */
+function stopBudgetGuard(budgetDir) {
+  const pidFile = path.join(budgetDir, "guard.pid");
+  if (!fs.existsSync(pidFile)) {
+    return;
+  }
+  const pid = parseInt(fs.readFileSync(pidFile, "utf8").trim(), 10);
+  if (!isNaN(pid)) {
+    try {
+      process.kill(pid, "SIGTERM");
+      core.info(`Stopped budget guard (PID ${pid})`);
+    } catch {
+      // Guard already exited
+    }
+  }
+}
+
+/**
+ * Formats a usage value against its limit for the step summary
+ * @param {string} value - Formatted value
+ * @param {number} used - Raw used amount
+ * @param {number} limit - Limit, 0 when not configured
+ * @returns {string}
+ */
+function formatAgainstLimit(value, used, limit) {
+  if (limit <= 0) {
+    return `${value} | - |`;
+  }
+  return `${value} | ${Math.round((used / limit) * 100)}% |`;
+}
+
+async function main() {
+  const budgetDir = process.env.GH_AW_BUDGET_DIR || BUDGET_DIR;
+
+  stopBudgetGuard(budgetDir);
+
+  const limits = readBudgetLimits();
+  const logPaths = parseLogPaths(process.env.GH_AW_BUDGET_LOG_PATHS);
+  const usage = extractUsageFromPaths(logPaths);
+  core.info(`Run usage: ${usage.tokens} tokens, $${usage.cost.toFixed(3)}`);
+
+  let reason = describeRunLimitExceeded(usage, limits);
+  const exceededPath = path.join(budgetDir, "exceeded.json");
+  if (fs.existsSync(exceededPath)) {
+    try {
+      reason = JSON.parse(fs.readFileSync(exceededPath, "utf8")).reason || reason;
+    } catch (error) {
+      core.warning(`Failed to read ${exceededPath}: ${getErrorMessage(error)}`);
+    }
+  }
+
+  const entry = {
+    run_id: context.runId,
+    created_at: new Date().toISOString(),
+    tokens: usage.tokens,
+    cost: usage.cost,
+  };
+
+  fs.mkdirSync(budgetDir, { recursive: true });
+  fs.writeFileSync(
+    path.join(budgetDir, "usage.json"),
+    JSON.stringify(
+      {
+        ...entry,
+        max_tokens: limits.maxTokens || undefined,
+        max_cost: limits.maxCost || undefined,
+        exceeded: reason || undefined,
+      },
+      null,
+      2
+    )
+  );
+
+  let summary = "## Budget\n\n| Metric | Used | Budget used |\n| --- | --- | --- |\n";
+  summary += `| Tokens | ${formatAgainstLimit(String(usage.tokens), usage.tokens, limits.maxTokens)}\n`;
+  summary += `| Cost | ${formatAgainstLimit(`$${usage.cost.toFixed(3)}`, usage.cost, limits.maxCost)}\n`;
+
+  if (reason) {
+    summary += `\n> [!WARNING]\n> The agent was stopped because the run exceeded its budget: ${reason}.\n`;
+  }
+  core.summary.addRaw(summary);
+  await core.summary.write();
+
+  if (reason) {
+    core.setFailed(`💸 Budget exceeded: ${reason}`);
+  }
+}
+
+module.exports = { main };
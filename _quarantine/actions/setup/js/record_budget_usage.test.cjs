@@ -0,0 +1,64 @@
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+import fs from "fs";
+import os from "os";
+import path from "path";
+
+const mockCore = {
+  info: vi.fn(),
+  warning: vi.fn(),
+  setFailed: vi.fn(),
+  summary: { addRaw: vi.fn().mockReturnThis(), write: vi.fn().mockResolvedValue() },
+};
+global.core = mockCore;
+global.context = { runId: 12345 };
+
+const { main } = require("./record_budget_usage.cjs");
+
+describe("record_budget_usage.cjs", () => {
+  let tmpDir;
+  let budgetDir;
+  let logFile;
+  let originalEnv;
+
+  beforeEach(() => {
+    vi.clearAllMocks();
+    originalEnv = { ...process.env };
+    tmpDir = fs.mkdtempSync(path.join(os.tmpdir(), "record-budget-test-"));
+    budgetDir = path.join(tmpDir, "budget");
+    logFile = path.join(tmpDir, "agent-stdio.log");
+    process.env.GH_AW_BUDGET_DIR = budgetDir;
+    process.env.GH_AW_BUDGET_LOG_PATHS = logFile;
+    process.env.GH_AW_BUDGET_MAX_TOKENS = "1000";
+    delete process.env.GH_AW_BUDGET_MAX_COST;
+  });
+
+  afterEach(() => {
+    process.env = originalEnv;
+    fs.rmSync(tmpDir, { recursive: true, force: true });
+  });
+
+  it("writes the run usage and the step summary", async () => {
+    fs.writeFileSync(logFile, JSON.stringify({ type: "result", total_cost_usd: 0.5, usage: { input_tokens: 400, output_tokens: 100 } }) + "\n");
+    await main();
+
+    const usage = JSON.parse(fs.readFileSync(path.join(budgetDir, "usage.json"), "utf8"));
+    expect(usage).toMatchObject({ run_id: 12345, tokens: 500, cost: 0.5, max_tokens: 1000 });
+    expect(usage.exceeded).toBeUndefined();
+    expect(mockCore.summary.addRaw).toHaveBeenCalledWith(expect.stringContaining("| Tokens | 500 | 50% |"));
+    expect(mockCore.setFailed).not.toHaveBeenCalled();
+  });
+
+  it("fails the step when the per-run budget was exceeded", async () => {
+    fs.writeFileSync(logFile, "tokens used: 1500\n");
+    await main();
+    expect(mockCore.setFailed).toHaveBeenCalledWith(expect.stringContaining("max-tokens 1000"));
+  });
+
+  it("reports the reason recorded by the guard", async () => {
+    fs.mkdirSync(budgetDir, { recursive: true });
+    fs.writeFileSync(path.join(budgetDir, "exceeded.json"), JSON.stringify({ reason: "cost $3.000 exceeds max-cost $2.5" }));
+    fs.writeFileSync(logFile, "");
+    await main();
+    expect(mockCore.setFailed).toHaveBeenCalledWith(expect.stringContaining("max-cost $2.5"));
+  });
+});
@@ -0,0 +1,244 @@
+// @ts-check
+
+const fs = require("fs");
+const path = require("path");
+
+const { validateJSONSchema, jsonEqual } = require("./json_schema_validator.cjs");
+
+/**
+ * Repo Memory Documents
+ *
+ * Keyed JSON documents stored in a repo-memory directory. Each document lives in
+ * "<key>.json" so it is an ordinary memory file on the branch. Every mutation made
+ * through the repo-memory MCP server is also appended to an operations journal; the
+ * push job replays the journal on top of the latest branch content instead of copying
+ * the agent's files, so concurrent runs appending to the same document both land.
+ */
+
+// Keys are slash-separated segments starting with an alphanumeric character, which rules out
+// absolute paths, "..", hidden files and empty segments
+const DOCUMENT_KEY_PATTERN = /^[A-Za-z0-9][A-Za-z0-9._-]*(\/[A-Za-z0-9][A-Za-z0-9._-]*)*$/;
+
+/**
+ * @typedef {Object} MemoryOperation
+ * @property {"put"|"append"} op - Operation type
+ * @property {string} key - Document key
+ * @property {unknown} value - Document (put) or item to append (append)
+ * @property {string} [field] - Top-level array field to append to (append only)
+ */
+
+/**
+ * Validate a document key and return the relative file path of the document
+ * @param {string} key
+ * @returns {string}
+ */
+function documentFile(key) {
+  if (typeof key !== "string" || key.length > 200 || !DOCUMENT_KEY_PATTERN.test(key)) {
+    throw new Error(`Invalid document key "${key}": use letters, digits, ".", "_", "-" and "/" separated segments`);
+  }
+  return `${key}.json`;
+}
+
+/**
+ * Read a document
+ * @param {string} dir - Memory directory
+ * @param {string} key
+ * @returns {unknown} The document, or undefined when it does not exist
+ */
+function readDocument(dir, key) {
+  const file = path.join(dir, documentFile(key));
+  if (!fs.existsSync(file)) {
+    return undefined;
+  }
+  try {
+    return JSON.parse(fs.readFileSync(file, "utf8"));
+  } catch (error) {
+    throw new Error(`Document "${key}" is not valid JSON: ${error instanceof Error ? error.message : String(error)}`);
+  }
+}
+
+/**
+ * Write a document
+ * @param {string} dir - Memory directory
+ * @param {string} key
+ * @param {unknown} value
+ * @returns {number} Size of the written file in bytes
+ */
+function writeDocument(dir, key, value) {
+  const file = path.join(dir, documentFile(key));
+  const content = JSON.stringify(value, null, 2) + "\n";
+  fs.mkdirSync(path.dirname(file), { recursive: true });
+  fs.writeFileSync(file, content, "utf8");
+  return Buffer.byteLength(content);
+}
+
+/**
+ * Apply an operation to a document
+ * @param {unknown} document - Current document (undefined when missing)
+ * @param {MemoryOperation} operation
+ * @returns {unknown} The new document
+ */
+function applyOperation(document, operation) {
+  if (operation.op === "put") {
+    return operation.value;
+  }
+  if (operation.op !== "append") {
+    throw new Error(`Unknown memory operation "${operation.op}"`);
+  }
+  if (!operation.field) {
+    const list = document === undefined ? [] : document;
+    if (!Array.isArray(list)) {
+      throw new Error(`Cannot append to document "${operation.key}": it is not an array (use field to append to an array property)`);
+    }
+    return [...list, operation.value];
+  }
+  const obj = document === undefined ? {} : document;
+  if (obj === null || typeof obj !== "object" || Array.isArray(obj)) {
+    throw new Error(`Cannot append to field "${operation.field}" of document "${operation.key}": it is not an object`);
+  }
+  const record = /** @type {Record<string, unknown>} */ (obj);
+  const list = record[operation.field] === undefined ? [] : record[operation.field];
+  if (!Array.isArray(list)) {
+    throw new Error(`Cannot append to field "${operation.field}" of document "${operation.key}": it is not an array`);
+  }
+  return { ...record, [operation.field]: [...list, operation.value] };
+}
+
+/**
+ * List document keys under a prefix
+ * @param {string} dir - Memory directory
+ * @param {string} [prefix] - Key prefix
+ * @returns {string[]} Sorted keys
+ */
+function listDocumentKeys(dir, prefix = "") {
+  /** @type {string[]} */
+  const keys = [];
+  /** @param {string} current @param {string} relative */
+  function walk(current, relative) {
+    if (!fs.existsSync(current)) return;
+    for (const entry of fs.readdirSync(current, { withFileTypes: true })) {
+      if (entry.name.startsWith(".")) continue;
+      const rel = relative ? `${relative}/${entry.name}` : entry.name;
+      if (entry.isDirectory()) {
+        walk(path.join(current, entry.name), rel);
+      } else if (entry.isFile() && entry.name.endsWith(".json")) {
+        const key = rel.slice(0, -".json".length);
+        if (DOCUMENT_KEY_PATTERN.test(key) && key.startsWith(prefix)) {
+          keys.push(key);
+        }
+      }
+    }
+  }
+  walk(dir, "");
+  return keys.sort();
+}
+
+/**
+ * Read a dotted field path (e.g. "status" or "owner.login") from a document
+ * @param {unknown} document
+ * @param {string} fieldPath
+ * @returns {unknown}
+ */
+function getField(document, fieldPath) {
+  let current = /** @type {any} */ (document);
+  for (const part of fieldPath.split(".")) {
+    if (current === null || typeof current !== "object") return undefined;
+    current = current[part];
+  }
+  return current;
+}
+
+/**
+ * Check whether a document matches every field of a where clause
+ * @param {unknown} document
+ * @param {Record<string, unknown>} [where] - Dotted field paths mapped to expected values
+ * @returns {boolean}
+ */
+function matchesWhere(document, where) {
+  if (!where) return true;
+  return Object.entries(where).every(([field, expected]) => jsonEqual(getField(document, field), expected));
+}
+
+/**
+ * Append an operation to the journal
+ * @param {string} journalFile
+ * @param {MemoryOperation} operation
+ */
+function appendOperation(journalFile, operation) {
+  fs.mkdirSync(path.dirname(journalFile), { recursive: true });
+  fs.appendFileSync(journalFile, JSON.stringify(operation) + "\n", "utf8");
+}
+
+/**
+ * Read the operations journal
+ * @param {string} journalFile
+ * @returns {MemoryOperation[]}
+ */
+function readOperations(journalFile) {
+  if (!journalFile || !fs.existsSync(journalFile)) {
+    return [];
+  }
+  return fs
+    .readFileSync(journalFile, "utf8")
+    .split("\n")
+    .filter(line => line.trim())
+    .map(line => JSON.parse(line));
+}
+
+/**
+ * Replay operations on the documents in a directory
+ * @param {string} dir - Memory directory holding the latest documents
+ * @param {MemoryOperation[]} operations
+ * @returns {string[]} Keys of the documents that were written
+ */
+function replayOperations(dir, operations) {
+  /** @type {Map<string, unknown>} */
+  const documents = new Map();
+  for (const operation of operations) {
+    const current = documents.has(operation.key) ? documents.get(operation.key) : readDocument(dir, operation.key);
+    documents.set(operation.key, applyOperation(current, operation));
+  }
+  for (const [key, value] of documents) {
+    writeDocument(dir, key, value);
+  }
+  return [...documents.keys()];
+}
+
+/**
+ * Validate the JSON files of a memory against its schema
+ * @param {string} dir - Memory directory
+ * @param {string[]} files - Relative file paths to check; files not ending in .json are skipped
+ * @param {any} schema - JSON Schema every document must satisfy
+ * @returns {string[]} Validation errors prefixed with the file path
+ */
+function validateDocumentFiles(dir, files, schema) {
+  /** @type {string[]} */
+  const errors = [];
+  for (const file of files) {
+    if (!file.endsWith(".json")) continue;
+    let document;
+    try {
+      document = JSON.parse(fs.readFileSync(path.join(dir, file), "utf8"));
+    } catch (error) {
+      errors.push(`${file}: invalid JSON: ${error instanceof Error ? error.message : String(error)}`);
+      continue;
+    }
+    for (const error of validateJSONSchema(schema, document)) {
+      errors.push(`${file}: ${error}`);
+    }
+  }
+  return errors;
+}
+
+module.exports = {
+  documentFile,
+  readDocument,
+  writeDocument,
+  applyOperation,
+  listDocumentKeys,
+  matchesWhere,
+  appendOperation,
+  readOperations,
+  replayOperations,
+  validateDocumentFiles,
+};
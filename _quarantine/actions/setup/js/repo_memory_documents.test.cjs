@@ -0,0 +1,121 @@
+import { describe, it, expect, beforeEach, afterEach } from "vitest";
+import fs from "fs";
+import os from "os";
+import path from "path";
+import {
+  documentFile,
+  readDocument,
+  writeDocument,
+  applyOperation,
+  listDocumentKeys,
+  matchesWhere,
+  appendOperation,
+  readOperations,
+  replayOperations,
+  validateDocumentFiles,
+} from "./repo_memory_documents.cjs";
+
+describe("repo_memory_documents.cjs", () => {
+  let dir;
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), "repo-memory-documents-"));
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  describe("documentFile", () => {
+    it("should map keys to JSON files", () => {
+      expect(documentFile("state")).toBe("state.json");
+      expect(documentFile("issues/123")).toBe("issues/123.json");
+    });
+
+    it("should reject keys escaping the memory directory", () => {
+      for (const key of ["../secrets", "/etc/passwd", "a//b", ".hidden", "a/../b", ""]) {
+        expect(() => documentFile(key)).toThrow("Invalid document key");
+      }
+    });
+  });
+
+  describe("applyOperation", () => {
+    it("should replace the document on put", () => {
+      expect(applyOperation({ a: 1 }, { op: "put", key: "k", value: { b: 2 } })).toEqual({ b: 2 });
+    });
+
+    it("should append to array documents and array fields", () => {
+      expect(applyOperation(undefined, { op: "append", key: "k", value: 1 })).toEqual([1]);
+      expect(applyOperation([1], { op: "append", key: "k", value: 2 })).toEqual([1, 2]);
+      expect(applyOperation({ status: "open" }, { op: "append", key: "k", field: "notes", value: "x" })).toEqual({ status: "open", notes: ["x"] });
+    });
+
+    it("should reject appends to non-arrays", () => {
+      expect(() => applyOperation({ a: 1 }, { op: "append", key: "k", value: 1 })).toThrow("it is not an array");
+      expect(() => applyOperation({ notes: "x" }, { op: "append", key: "k", field: "notes", value: 1 })).toThrow("it is not an array");
+    });
+  });
+
+  describe("documents on disk", () => {
+    it("should write, read and list documents", () => {
+      writeDocument(dir, "issues/1", { status: "open" });
+      writeDocument(dir, "issues/2", { status: "closed" });
+      writeDocument(dir, "summary", []);
+      fs.writeFileSync(path.join(dir, "notes.md"), "# Notes\n");
+
+      expect(readDocument(dir, "issues/1")).toEqual({ status: "open" });
+      expect(readDocument(dir, "missing")).toBeUndefined();
+      expect(listDocumentKeys(dir)).toEqual(["issues/1", "issues/2", "summary"]);
+      expect(listDocumentKeys(dir, "issues/")).toEqual(["issues/1", "issues/2"]);
+    });
+
+    it("should match where clauses on dotted fields", () => {
+      const doc = { status: "open", owner: { login: "octocat" } };
+      expect(matchesWhere(doc, { status: "open", "owner.login": "octocat" })).toBe(true);
+      expect(matchesWhere(doc, { "owner.login": "hubot" })).toBe(false);
+      expect(matchesWhere(doc, undefined)).toBe(true);
+    });
+  });
+
+  describe("operations journal", () => {
+    it("should replay journaled operations on the latest documents", () => {
+      const journal = path.join(dir, "ops", "memory.jsonl");
+      appendOperation(journal, { op: "append", key: "log", value: "from this run" });
+      appendOperation(journal, { op: "append", key: "issues/1", field: "notes", value: "triaged" });
+      appendOperation(journal, { op: "put", key: "summary", value: { total: 2 } });
+
+      // A concurrent run pushed these documents after this run started
+      const memoryDir = path.join(dir, "memory");
+      writeDocument(memoryDir, "log", ["from another run"]);
+      writeDocument(memoryDir, "issues/1", { status: "open", notes: ["duplicate"] });
+
+      const keys = replayOperations(memoryDir, readOperations(journal));
+
+      expect(keys).toEqual(["log", "issues/1", "summary"]);
+      expect(readDocument(memoryDir, "log")).toEqual(["from another run", "from this run"]);
+      expect(readDocument(memoryDir, "issues/1")).toEqual({ status: "open", notes: ["duplicate", "triaged"] });
+      expect(readDocument(memoryDir, "summary")).toEqual({ total: 2 });
+    });
+
+    it("should return no operations when the journal is missing", () => {
+      expect(readOperations(path.join(dir, "missing.jsonl"))).toEqual([]);
+      expect(readOperations("")).toEqual([]);
+    });
+  });
+
+  describe("validateDocumentFiles", () => {
+    it("should validate JSON files and skip other files", () => {
+      writeDocument(dir, "good", { status: "open" });
+      writeDocument(dir, "bad", { status: 1 });
+      fs.writeFileSync(path.join(dir, "broken.json"), "{");
+      fs.writeFileSync(path.join(dir, "notes.md"), "not json");
+
+      const schema = { type: "object", properties: { status: { type: "string" } } };
+      const errors = validateDocumentFiles(dir, ["good.json", "bad.json", "broken.json", "notes.md"], schema);
+
+      expect(errors).toHaveLength(2);
+      expect(errors[0]).toBe("bad.json: /status: expected string, got integer");
+      expect(errors[1]).toMatch(/^broken\.json: invalid JSON/);
+    });
+  });
+});
@@ -0,0 +1,341 @@
+#!/usr/bin/env node
+// @ts-check
+
+/**
+ * Repo Memory MCP Server
+ *
+ * Provides keyed get/put/append/query tools over the JSON documents of the workflow's
+ * repo-memory directories, so agents do not have to write memory files by hand. Writes
+ * are validated against the memory's file-glob, size limits and JSON Schema before they
+ * reach the disk, and each one is recorded in the memory's operations journal, which the
+ * push_repo_memory job replays on top of the latest branch content.
+ *
+ * Configuration is read from GH_AW_REPO_MEMORY_CONFIG_PATH (default:
+ * /opt/gh-aw/repo-memory/config.json):
+ *
+ *   { "memories": [{ "id": "default", "dir": "/tmp/gh-aw/repo-memory/default",
+ *                    "journal": "/tmp/gh-aw/repo-memory-operations/default.jsonl",
+ *                    "schema": {...}, "file_glob": ["*.json"],
+ *                    "max_file_size": 10240, "max_file_count": 100 }] }
+ *
+ * The HTTP server listens on GH_AW_REPO_MEMORY_PORT and requires the
+ * GH_AW_REPO_MEMORY_API_KEY value in the Authorization header when it is set.
+ */
+
+const fs = require("fs");
+const http = require("http");
+const path = require("path");
+
+const { MCPServer, MCPHTTPTransport } = require("./mcp_http_transport.cjs");
+const { createLogger } = require("./mcp_logger.cjs");
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { globPatternToRegex } = require("./glob_pattern_helpers.cjs");
+const { validateJSONSchema } = require("./json_schema_validator.cjs");
+const { documentFile, readDocument, writeDocument, applyOperation, listDocumentKeys, matchesWhere, appendOperation } = require("./repo_memory_documents.cjs");
+
+const DEFAULT_QUERY_LIMIT = 50;
+
+/**
+ * @typedef {Object} RepoMemoryServerEntry
+ * @property {string} id
+ * @property {string} dir - Memory directory
+ * @property {string} journal - Operations journal file
+ * @property {any} [schema] - JSON Schema for documents
+ * @property {string[]} [file_glob]
+ * @property {number} [max_file_size]
+ * @property {number} [max_file_count]
+ * @property {string} [description]
+ */
+
+/**
+ * Count the files of a memory directory, ignoring the git metadata
+ * @param {string} dir
+ * @returns {number}
+ */
+function countMemoryFiles(dir) {
+  let count = 0;
+  /** @param {string} current */
+  function walk(current) {
+    if (!fs.existsSync(current)) return;
+    for (const entry of fs.readdirSync(current, { withFileTypes: true })) {
+      if (entry.name === ".git") continue;
+      if (entry.isDirectory()) {
+        walk(path.join(current, entry.name));
+      } else if (entry.isFile()) {
+        count++;
+      }
+    }
+  }
+  walk(dir);
+  return count;
+}
+
+/**
+ * Create the tool handlers for a set of memories
+ * @param {RepoMemoryServerEntry[]} memories
+ */
+function createRepoMemoryHandlers(memories) {
+  /**
+   * @param {string} [id]
+   * @returns {RepoMemoryServerEntry}
+   */
+  function selectMemory(id) {
+    if (!id) {
+      if (memories.length === 1) return memories[0];
+      const fallback = memories.find(m => m.id === "default");
+      if (fallback) return fallback;
+      throw new Error(`Specify the memory to use: ${memories.map(m => m.id).join(", ")}`);
+    }
+    const memory = memories.find(m => m.id === id);
+    if (!memory) {
+      throw new Error(`Unknown memory "${id}". Available memories: ${memories.map(m => m.id).join(", ")}`);
+    }
+    return memory;
+  }
+
+  /**
+   * Validate and write a document, then record the operation in the journal
+   * @param {RepoMemoryServerEntry} memory
+   * @param {import("./repo_memory_documents.cjs").MemoryOperation} operation
+   */
+  function mutate(memory, operation) {
+    const file = documentFile(operation.key);
+    if (memory.file_glob && memory.file_glob.length > 0 && !memory.file_glob.map(globPatternToRegex).some(re => re.test(file))) {
+      throw new Error(`Document file ${file} does not match the memory file-glob (${memory.file_glob.join(" ")})`);
+    }
+
+    const current = readDocument(memory.dir, operation.key);
+    const next = applyOperation(current, operation);
+    if (memory.schema) {
+      const errors = validateJSONSchema(memory.schema, next);
+      if (errors.length > 0) {
+        throw new Error(`Document "${operation.key}" does not match the memory schema:\n${errors.join("\n")}`);
+      }
+    }
+
+    const size = Buffer.byteLength(JSON.stringify(next, null, 2) + "\n");
+    if (memory.max_file_size && size > memory.max_file_size) {
+      throw new Error(`Document "${operation.key}" would be ${size} bytes, more than the memory max-file-size of ${memory.max_file_size} bytes`);
+    }
+    if (current === undefined && memory.max_file_count && countMemoryFiles(memory.dir) >= memory.max_file_count) {
+      throw new Error(`Memory "${memory.id}" already has ${memory.max_file_count} files (max-file-count)`);
+    }
+
+    writeDocument(memory.dir, operation.key, next);
+    appendOperation(memory.journal, operation);
+    return { memory: memory.id, key: operation.key, file, size };
+  }
+
+  return {
+    /** @param {{key: string, memory?: string}} args */
+    memory_get(args) {
+      const memory = selectMemory(args.memory);
+      const value = readDocument(memory.dir, args.key);
+      if (value === undefined) {
+        throw new Error(`Document "${args.key}" not found in memory "${memory.id}"`);
+      }
+      return { memory: memory.id, key: args.key, value };
+    },
+
+    /** @param {{key: string, value: unknown, memory?: string}} args */
+    memory_put(args) {
+      return mutate(selectMemory(args.memory), { op: "put", key: args.key, value: args.value });
+    },
+
+    /** @param {{key: string, value: unknown, field?: string, memory?: string}} args */
+    memory_append(args) {
+      /** @type {import("./repo_memory_documents.cjs").MemoryOperation} */
+      const operation = { op: "append", key: args.key, value: args.value };
+      if (args.field) {
+        operation.field = args.field;
+      }
+      return mutate(selectMemory(args.memory), operation);
+    },
+
+    /** @param {{prefix?: string, where?: Record<string, unknown>, limit?: number, memory?: string}} args */
+    memory_query(args) {
+      const memory = selectMemory(args.memory);
+      const limit = args.limit && args.limit > 0 ? args.limit : DEFAULT_QUERY_LIMIT;
+      const results = [];
+      let total = 0;
+      for (const key of listDocumentKeys(memory.dir, args.prefix || "")) {
+        const value = readDocument(memory.dir, key);
+        if (!matchesWhere(value, args.where)) continue;
+        total++;
+        if (results.length < limit) {
+          results.push({ key, value });
+        }
+      }
+      return { memory: memory.id, total, results };
+    },
+  };
+}
+
+/**
+ * Build the tool definitions
+ * @param {RepoMemoryServerEntry[]} memories
+ */
+function createRepoMemoryTools(memories) {
+  const ids = memories.map(m => m.id);
+  const described = memories.map(m => (m.description ? `${m.id} (${m.description})` : m.id)).join(", ");
+  const memoryProperty = {
+    type: "string",
+    enum: ids,
+    description: `Memory to use: ${described}. Defaults to ${ids.length === 1 ? ids[0] : '"default"'}.`,
+  };
+  const keyProperty = {
+    type: "string",
+    description: 'Document key, e.g. "issues/123" or "state". Stored as <key>.json in the memory.',
+  };
+  const schemaNote = memories.some(m => m.schema) ? " Documents are validated against the memory's JSON Schema." : "";
+
+  return [
+    {
+      name: "memory_get",
+      description: "Read a JSON document from repo memory by key.",
+      inputSchema: { type: "object", properties: { key: keyProperty, memory: memoryProperty }, required: ["key"], additionalProperties: false },
+    },
+    {
+      name: "memory_put",
+      description: `Create or replace a JSON document in repo memory.${schemaNote} Concurrent runs replacing the same key keep the last write; prefer memory_append for accumulating data.`,
+      inputSchema: {
+        type: "object",
+        properties: { key: keyProperty, value: { description: "The document" }, memory: memoryProperty },
+        required: ["key", "value"],
+        additionalProperties: false,
+      },
+    },
+    {
+      name: "memory_append",
+      description: `Append an item to an array document, or to an array field of an object document, creating it when missing.${schemaNote} Appends from concurrent runs are merged.`,
+      inputSchema: {
+        type: "object",
+        properties: {
+          key: keyProperty,
+          value: { description: "Item to append" },
+          field: { type: "string", description: "Top-level array field of an object document to append to" },
+          memory: memoryProperty,
+        },
+        required: ["key", "value"],
+        additionalProperties: false,
+      },
+    },
+    {
+      name: "memory_query",
+      description: "List JSON documents in repo memory whose key starts with a prefix and whose fields equal the given values.",
+      inputSchema: {
+        type: "object",
+        properties: {
+          prefix: { type: "string", description: 'Key prefix, e.g. "issues/"' },
+          where: { type: "object", description: 'Fields (dotted paths allowed) and the values they must equal, e.g. {"status": "open"}' },
+          limit: { type: "integer", minimum: 1, description: `Maximum number of documents to return (default: ${DEFAULT_QUERY_LIMIT})` },
+          memory: memoryProperty,
+        },
+        additionalProperties: false,
+      },
+    },
+  ];
+}
+
+/**
+ * Create the MCP server
+ * @param {RepoMemoryServerEntry[]} memories
+ */
+function createRepoMemoryServer(memories) {
+  const server = new MCPServer({ name: "repomemory", version: "1.0.0" }, { capabilities: { tools: {} } });
+  const handlers = createRepoMemoryHandlers(memories);
+  for (const tool of createRepoMemoryTools(memories)) {
+    const handler = /** @type {Record<string, (args: any) => unknown>} */ (handlers)[tool.name];
+    server.tool(tool.name, tool.description, tool.inputSchema, async (/** @type {any} */ args) => {
+      const result = handler(args || {});
+      return { content: [{ type: "text", text: JSON.stringify(result) }], isError: false };
+    });
+  }
+  return server;
+}
+
+/**
+ * Start the HTTP server
+ * @param {{port: number, apiKey?: string, configPath: string}} options
+ */
+async function startHttpServer(options) {
+  const logger = createLogger("repomemory");
+  const config = JSON.parse(fs.readFileSync(options.configPath, "utf8"));
+  /** @type {RepoMemoryServerEntry[]} */
+  const memories = config.memories || [];
+  for (const memory of memories) {
+    fs.mkdirSync(memory.dir, { recursive: true });
+  }
+  logger.debug(`Serving ${memories.length} memories: ${memories.map(m => m.id).join(", ")}`);
+
+  const server = createRepoMemoryServer(memories);
+  const transport = new MCPHTTPTransport({ sessionIdGenerator: undefined, enableJsonResponse: true, enableDnsRebindingProtection: false });
+  await server.connect(transport);
+
+  const httpServer = http.createServer(async (req, res) => {
+    if (req.method === "GET" && req.url === "/health") {
+      res.writeHead(200, { "Content-Type": "application/json" });
+      res.end(JSON.stringify({ status: "ok", server: "repomemory", memories: memories.length }));
+      return;
+    }
+    if (options.apiKey && req.headers.authorization !== options.apiKey) {
+      res.writeHead(401, { "Content-Type": "application/json" });
+      res.end(JSON.stringify({ error: "Unauthorized" }));
+      return;
+    }
+    if (req.method !== "POST") {
+      res.writeHead(405, { "Content-Type": "application/json" });
+      res.end(JSON.stringify({ error: "Method not allowed" }));
+      return;
+    }
+    try {
+      const chunks = [];
+      for await (const chunk of req) {
+        chunks.push(chunk);
+      }
+      const bodyStr = Buffer.concat(chunks).toString();
+      let body = null;
+      try {
+        body = bodyStr ? JSON.parse(bodyStr) : null;
+      } catch {
+        res.writeHead(400, { "Content-Type": "application/json" });
+        res.end(JSON.stringify({ jsonrpc: "2.0", error: { code: -32700, message: "Parse error: Invalid JSON in request body" }, id: null }));
+        return;
+      }
+      await transport.handleRequest(req, res, body);
+    } catch (error) {
+      logger.debugError("Error handling request: ", error);
+      if (!res.headersSent) {
+        res.writeHead(500, { "Content-Type": "application/json" });
+        res.end(JSON.stringify({ jsonrpc: "2.0", error: { code: -32603, message: "Internal server error" }, id: null }));
+      }
+    }
+  });
+
+  httpServer.on("error", error => {
+    logger.debugError(`Failed to start repo-memory MCP server on port ${options.port}: `, error);
+    process.exit(1);
+  });
+  httpServer.listen(options.port, () => {
+    logger.debug(`Repo memory MCP server listening on http://localhost:${options.port}`);
+  });
+  return httpServer;
+}
+
+if (require.main === module) {
+  startHttpServer({
+    port: parseInt(process.env.GH_AW_REPO_MEMORY_PORT || "3002", 10),
+    apiKey: process.env.GH_AW_REPO_MEMORY_API_KEY,
+    configPath: process.env.GH_AW_REPO_MEMORY_CONFIG_PATH || "/opt/gh-aw/repo-memory/config.json",
+  }).catch(error => {
+    console.error(`Error starting repo-memory MCP server: ${getErrorMessage(error)}`);
+    process.exit(1);
+  });
+}
+
+module.exports = {
+  createRepoMemoryHandlers,
+  createRepoMemoryTools,
+  createRepoMemoryServer,
+  startHttpServer,
+};
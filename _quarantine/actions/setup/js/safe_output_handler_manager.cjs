@@ -16,6 +16,7 @@ const { generateMissingInfoSections } = require("./missing_info_formatter.cjs");
 const { setCollectedMissings } = require("./missing_messages_helper.cjs");
 const { writeSafeOutputSummaries } = require("./safe_output_summary.cjs");
 const { getIssuesToAssignCopilot } = require("./create_issue.cjs");
+const { applySafeOutputQuota } = require("./safe_output_quota.cjs");
 
 /**
  * Handler map configuration
@@ -115,7 +116,8 @@ async function loadHandlers(config) {
             throw error;
           }
 
-          messageHandlers.set(type, messageHandler);
+          // Enforce cross-run quotas (max-per-day, max-open) when configured
+          messageHandlers.set(type, applySafeOutputQuota(type, handlerConfig, messageHandler));
           core.info(`✓ Loaded and initialized handler for: ${type}`);
         } else {
           core.warning(`Handler module ${type} does not export a main function`);
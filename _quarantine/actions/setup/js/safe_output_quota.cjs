@@ -0,0 +1,318 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { getDefaultTargetRepo, parseRepoSlug } = require("./repo_helpers.cjs");
+
+/**
+ * Prefix of the warning logged when a quota is reached.
+ * `gh aw logs` looks for this prefix in the safe outputs job log.
+ */
+const QUOTA_REACHED_PREFIX = "Safe output quota reached:";
+
+/**
+ * Marker identifying comments created by the add_comment safe output. Activation and
+ * status comments carry the same tracker-id marker, so only comments with this marker
+ * count against the add_comment quota.
+ */
+const ADD_COMMENT_MARKER = "<!-- gh-aw-comment-type: add-comment -->";
+
+/** Length of the max-per-day window */
+const DAY_MS = 24 * 60 * 60 * 1000;
+
+/** Maximum number of comment pages scanned when counting recent comments */
+const MAX_COMMENT_PAGES = 10;
+
+/**
+ * Safe output types that support cross-run quotas and the kind of item they create
+ * @type {Record<string, {kind: "issue" | "pr" | "discussion" | "comment"}>}
+ */
+const QUOTA_TYPES = {
+  create_issue: { kind: "issue" },
+  create_pull_request: { kind: "pr" },
+  create_discussion: { kind: "discussion" },
+  add_comment: { kind: "comment" },
+};
+
+/**
+ * @typedef {Object} SafeOutputQuota
+ * @property {number} maxPerDay - Maximum items created in the last 24 hours, 0 when unlimited
+ * @property {number} maxOpen - Maximum open items, 0 when unlimited
+ */
+
+/**
+ * @typedef {Object} QuotaUsage
+ * @property {number} createdToday - Items created in the last 24 hours
+ * @property {number} open - Items that are currently open
+ */
+
+/**
+ * Reads the cross-run quota from a handler configuration
+ * @param {string} type - Safe output type
+ * @param {any} config - Handler configuration
+ * @returns {SafeOutputQuota|null} The quota, or null when none is configured
+ */
+function readQuota(type, config) {
+  if (!QUOTA_TYPES[type] || !config) {
+    return null;
+  }
+  const maxPerDay = parseInt(String(config.max_per_day ?? 0), 10) || 0;
+  // Comments cannot be open or closed
+  const maxOpen = QUOTA_TYPES[type].kind === "comment" ? 0 : parseInt(String(config.max_open ?? 0), 10) || 0;
+  if (maxPerDay <= 0 && maxOpen <= 0) {
+    return null;
+  }
+  return { maxPerDay, maxOpen };
+}
+
+/**
+ * Builds the tracker-id marker searched for in item bodies
+ * @param {string} trackerId - Workflow tracker-id
+ * @returns {string}
+ */
+function trackerMarker(trackerId) {
+  return `gh-aw-tracker-id: ${trackerId}`;
+}
+
+/**
+ * Counts issues or pull requests carrying the tracker-id marker
+ * @param {any} github - GitHub API client
+ * @param {string} owner - Repository owner
+ * @param {string} repo - Repository name
+ * @param {"issue" | "pr"} kind - Item kind
+ * @param {string} trackerId - Workflow tracker-id
+ * @param {string} filter - Additional search qualifier (created date or open state)
+ * @returns {Promise<number>}
+ */
+async function countIssuesOrPullRequests(github, owner, repo, kind, trackerId, filter) {
+  const marker = trackerMarker(trackerId).replace(/"/g, '\\"');
+  const query = `repo:${owner}/${repo} is:${kind} "${marker}" in:body ${filter}`;
+  core.info(`Counting ${kind === "pr" ? "pull requests" : "issues"}: ${query}`);
+  const result = await github.rest.search.issuesAndPullRequests({ q: query, per_page: 1 });
+  return result.data.total_count;
+}
+
+/**
+ * Counts discussions carrying the tracker-id marker
+ * @param {any} github - GitHub API client
+ * @param {string} owner - Repository owner
+ * @param {string} repo - Repository name
+ * @param {string} trackerId - Workflow tracker-id
+ * @param {string} filter - Additional search qualifier (created date or open state)
+ * @returns {Promise<number>}
+ */
+async function countDiscussions(github, owner, repo, trackerId, filter) {
+  const marker = trackerMarker(trackerId).replace(/"/g, '\\"');
+  const query = `repo:${owner}/${repo} "${marker}" in:body ${filter}`;
+  core.info(`Counting discussions: ${query}`);
+  const result = await github.graphql(
+    `
+    query($searchTerms: String!) {
+      search(query: $searchTerms, type: DISCUSSION, first: 1) {
+        discussionCount
+      }
+    }`,
+    { searchTerms: query }
+  );
+  return result?.search?.discussionCount || 0;
+}
+
+/**
+ * Counts issue, pull request and discussion comments created by the add_comment safe
+ * output (carrying both the tracker-id and the add_comment markers) since the given time
+ * @param {any} github - GitHub API client
+ * @param {string} owner - Repository owner
+ * @param {string} repo - Repository name
+ * @param {string} trackerId - Workflow tracker-id
+ * @param {Date} since - Start of the window
+ * @returns {Promise<number>}
+ */
+async function countRecentComments(github, owner, repo, trackerId, since) {
+  const marker = trackerMarker(trackerId);
+  /** @param {{body?: string | null, created_at?: string, createdAt?: string}} comment */
+  const matches = comment => !!comment.body?.includes(marker) && comment.body.includes(ADD_COMMENT_MARKER) && new Date(comment.created_at || comment.createdAt || 0) >= since;
+
+  let count = 0;
+  for (let page = 1; page <= MAX_COMMENT_PAGES; page++) {
+    const { data } = await github.rest.issues.listCommentsForRepo({
+      owner,
+      repo,
+      since: since.toISOString(),
+      sort: "created",
+      direction: "desc",
+      per_page: 100,
+      page,
+    });
+    count += data.filter(matches).length;
+    if (data.length < 100) {
+      break;
+    }
+  }
+
+  // Discussion comments are not returned by the REST API; scan recently updated discussions
+  const result = await github.graphql(
+    `
+    query($owner: String!, $repo: String!) {
+      repository(owner: $owner, name: $repo) {
+        discussions(first: 50, orderBy: { field: UPDATED_AT, direction: DESC }) {
+          nodes {
+            updatedAt
+            comments(last: 100) {
+              nodes {
+                body
+                createdAt
+              }
+            }
+          }
+        }
+      }
+    }`,
+    { owner, repo }
+  );
+  for (const discussion of result?.repository?.discussions?.nodes || []) {
+    if (!discussion || new Date(discussion.updatedAt) < since) {
+      continue;
+    }
+    count += (discussion.comments?.nodes || []).filter(/** @param {any} c */ c => c && matches(c)).length;
+  }
+  return count;
+}
+
+/**
+ * Queries how many items created by previous runs count against the quota
+ * @param {any} github - GitHub API client
+ * @param {string} type - Safe output type
+ * @param {SafeOutputQuota} quota - Configured quota
+ * @param {string} repoSlug - Repository in owner/repo format
+ * @param {string} trackerId - Workflow tracker-id
+ * @param {Date} [now] - Current time
+ * @returns {Promise<QuotaUsage>}
+ */
+async function loadQuotaUsage(github, type, quota, repoSlug, trackerId, now = new Date()) {
+  const parsed = parseRepoSlug(repoSlug);
+  if (!parsed) {
+    throw new Error(`Invalid repository '${repoSlug}'`);
+  }
+  const { owner, repo } = parsed;
+  const kind = QUOTA_TYPES[type].kind;
+  const since = new Date(now.getTime() - DAY_MS);
+  const createdFilter = `created:>=${since.toISOString().replace(/\.\d{3}Z$/, "Z")}`;
+
+  const usage = { createdToday: 0, open: 0 };
+  if (quota.maxPerDay > 0) {
+    if (kind === "comment") {
+      usage.createdToday = await countRecentComments(github, owner, repo, trackerId, since);
+    } else if (kind === "discussion") {
+      usage.createdToday = await countDiscussions(github, owner, repo, trackerId, createdFilter);
+    } else {
+      usage.createdToday = await countIssuesOrPullRequests(github, owner, repo, kind, trackerId, createdFilter);
+    }
+  }
+  if (quota.maxOpen > 0) {
+    if (kind === "discussion") {
+      usage.open = await countDiscussions(github, owner, repo, trackerId, "is:open");
+    } else if (kind !== "comment") {
+      usage.open = await countIssuesOrPullRequests(github, owner, repo, kind, trackerId, "is:open");
+    }
+  }
+  core.info(`Quota usage for ${type} in ${repoSlug}: ${usage.createdToday} created in the last 24 hours, ${usage.open} open`);
+  return usage;
+}
+
+/**
+ * Checks the usage against the quota
+ * @param {SafeOutputQuota} quota - Configured quota
+ * @param {QuotaUsage} usage - Current usage
+ * @returns {{quota: string, limit: number, detail: string}|null} The exceeded quota, or null when within limits
+ */
+function checkQuota(quota, usage) {
+  if (quota.maxPerDay > 0 && usage.createdToday >= quota.maxPerDay) {
+    return { quota: "max-per-day", limit: quota.maxPerDay, detail: `${usage.createdToday} created in the last 24 hours` };
+  }
+  if (quota.maxOpen > 0 && usage.open >= quota.maxOpen) {
+    return { quota: "max-open", limit: quota.maxOpen, detail: `${usage.open} open` };
+  }
+  return null;
+}
+
+/**
+ * Wraps a safe output message handler so that it enforces the cross-run quota of its
+ * configuration. Handlers without a quota are returned unchanged.
+ *
+ * Usage is queried once, before the first message is processed, and then updated with
+ * the items created by this run. The quota fails closed: if the usage cannot be
+ * determined, every message of the type is rejected.
+ *
+ * @param {string} type - Safe output type
+ * @param {any} config - Handler configuration
+ * @param {Function} messageHandler - Message handler returned by the handler factory
+ * @returns {Function} Message handler enforcing the quota
+ */
+function applySafeOutputQuota(type, config, messageHandler) {
+  const quota = readQuota(type, config);
+  if (!quota) {
+    return messageHandler;
+  }
+
+  const trackerId = process.env.GH_AW_TRACKER_ID || "";
+  if (!trackerId) {
+    core.warning(`Quota for ${type} is not enforced: the workflow has no tracker-id`);
+    return messageHandler;
+  }
+
+  core.info(`Quota for ${type}: max-per-day=${quota.maxPerDay || "unlimited"}, max-open=${quota.maxOpen || "unlimited"}`);
+
+  /** @type {QuotaUsage|null} */
+  let usage = null;
+  /** @type {string} */
+  let usageError = "";
+  let reported = false;
+
+  return async function handleWithQuota(/** @type {any} */ message, /** @type {any} */ resolvedTemporaryIds) {
+    if (!usage && !usageError) {
+      try {
+        usage = await loadQuotaUsage(github, type, quota, getDefaultTargetRepo(config), trackerId);
+      } catch (error) {
+        usageError = getErrorMessage(error);
+        core.warning(`Failed to determine quota usage for ${type}, rejecting ${type} messages: ${usageError}`);
+      }
+    }
+
+    if (!usage) {
+      return {
+        success: false,
+        error: `Quota for ${type} could not be checked (${usageError})`,
+      };
+    }
+
+    const exceeded = checkQuota(quota, usage);
+    if (exceeded) {
+      if (!reported) {
+        core.warning(`${QUOTA_REACHED_PREFIX} ${type} ${exceeded.quota}=${exceeded.limit} (${exceeded.detail})`);
+        reported = true;
+      }
+      return {
+        success: false,
+        error: `Quota ${exceeded.quota} of ${exceeded.limit} reached for ${type} (${exceeded.detail})`,
+      };
+    }
+
+    const result = await messageHandler(message, resolvedTemporaryIds);
+
+    if (result && result.success !== false && !result.deferred && !result.skipped) {
+      const created = Array.isArray(result) ? result.length : 1;
+      usage.createdToday += created;
+      usage.open += created;
+    }
+    return result;
+  };
+}
+
+module.exports = {
+  QUOTA_REACHED_PREFIX,
+  ADD_COMMENT_MARKER,
+  readQuota,
+  loadQuotaUsage,
+  checkQuota,
+  applySafeOutputQuota,
+};
@@ -0,0 +1,174 @@
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+
+const mockCore = {
+  info: vi.fn(),
+  warning: vi.fn(),
+};
+const mockGithub = {
+  rest: {
+    search: { issuesAndPullRequests: vi.fn() },
+    issues: { listCommentsForRepo: vi.fn() },
+  },
+  graphql: vi.fn(),
+};
+global.core = mockCore;
+global.github = mockGithub;
+global.context = { repo: { owner: "octo", repo: "repo" } };
+
+const { QUOTA_REACHED_PREFIX, readQuota, loadQuotaUsage, checkQuota, applySafeOutputQuota } = require("./safe_output_quota.cjs");
+
+describe("safe_output_quota.cjs", () => {
+  let originalEnv;
+
+  beforeEach(() => {
+    vi.clearAllMocks();
+    originalEnv = { ...process.env };
+    process.env.GH_AW_TRACKER_ID = "daily-report";
+    delete process.env.GH_AW_TARGET_REPO_SLUG;
+  });
+
+  afterEach(() => {
+    process.env = originalEnv;
+  });
+
+  describe("readQuota", () => {
+    it("returns null without quota fields or for unsupported types", () => {
+      expect(readQuota("create_issue", { max: 3 })).toBeNull();
+      expect(readQuota("add_labels", { max_per_day: 3 })).toBeNull();
+    });
+
+    it("reads max-per-day and max-open", () => {
+      expect(readQuota("create_issue", { max_per_day: 5, max_open: 10 })).toEqual({ maxPerDay: 5, maxOpen: 10 });
+    });
+
+    it("ignores max-open for comments", () => {
+      expect(readQuota("add_comment", { max_per_day: 5, max_open: 10 })).toEqual({ maxPerDay: 5, maxOpen: 0 });
+      expect(readQuota("add_comment", { max_open: 10 })).toBeNull();
+    });
+  });
+
+  describe("checkQuota", () => {
+    it("reports the first exceeded quota", () => {
+      expect(checkQuota({ maxPerDay: 5, maxOpen: 10 }, { createdToday: 4, open: 9 })).toBeNull();
+      expect(checkQuota({ maxPerDay: 5, maxOpen: 10 }, { createdToday: 5, open: 0 })).toEqual({
+        quota: "max-per-day",
+        limit: 5,
+        detail: "5 created in the last 24 hours",
+      });
+      expect(checkQuota({ maxPerDay: 0, maxOpen: 10 }, { createdToday: 50, open: 10 })).toEqual({ quota: "max-open", limit: 10, detail: "10 open" });
+    });
+  });
+
+  describe("loadQuotaUsage", () => {
+    const now = new Date("2025-06-30T12:00:00.000Z");
+
+    it("searches issues by tracker-id, creation date and state", async () => {
+      mockGithub.rest.search.issuesAndPullRequests.mockResolvedValueOnce({ data: { total_count: 2 } }).mockResolvedValueOnce({ data: { total_count: 7 } });
+
+      const usage = await loadQuotaUsage(mockGithub, "create_issue", { maxPerDay: 5, maxOpen: 10 }, "octo/repo", "daily-report", now);
+
+      expect(usage).toEqual({ createdToday: 2, open: 7 });
+      expect(mockGithub.rest.search.issuesAndPullRequests).toHaveBeenNthCalledWith(1, {
+        q: 'repo:octo/repo is:issue "gh-aw-tracker-id: daily-report" in:body created:>=2025-06-29T12:00:00Z',
+        per_page: 1,
+      });
+      expect(mockGithub.rest.search.issuesAndPullRequests).toHaveBeenNthCalledWith(2, {
+        q: 'repo:octo/repo is:issue "gh-aw-tracker-id: daily-report" in:body is:open',
+        per_page: 1,
+      });
+    });
+
+    it("searches pull requests", async () => {
+      mockGithub.rest.search.issuesAndPullRequests.mockResolvedValueOnce({ data: { total_count: 1 } });
+      await loadQuotaUsage(mockGithub, "create_pull_request", { maxPerDay: 0, maxOpen: 3 }, "octo/repo", "daily-report", now);
+      expect(mockGithub.rest.search.issuesAndPullRequests).toHaveBeenCalledWith(expect.objectContaining({ q: expect.stringContaining("is:pr") }));
+    });
+
+    it("counts discussions through GraphQL search", async () => {
+      mockGithub.graphql.mockResolvedValueOnce({ search: { discussionCount: 4 } });
+      const usage = await loadQuotaUsage(mockGithub, "create_discussion", { maxPerDay: 5, maxOpen: 0 }, "octo/repo", "daily-report", now);
+      expect(usage.createdToday).toBe(4);
+      expect(mockGithub.graphql).toHaveBeenCalledWith(expect.any(String), { searchTerms: expect.stringContaining("created:>=2025-06-29T12:00:00Z") });
+    });
+
+    it("counts recent issue and discussion comments carrying the marker", async () => {
+      const marker = "<!-- gh-aw-tracker-id: daily-report -->\n<!-- gh-aw-comment-type: add-comment -->";
+      const activation = "<!-- gh-aw-tracker-id: daily-report -->\n\n<!-- gh-aw-comment-type: reaction -->";
+      mockGithub.rest.issues.listCommentsForRepo.mockResolvedValueOnce({
+        data: [
+          { body: `Report${marker}`, created_at: "2025-06-30T10:00:00Z" },
+          { body: `Old report${marker}`, created_at: "2025-06-28T10:00:00Z" },
+          { body: "Unrelated", created_at: "2025-06-30T11:00:00Z" },
+          { body: `Agentic workflow started${activation}`, created_at: "2025-06-30T11:00:00Z" },
+        ],
+      });
+      mockGithub.graphql.mockResolvedValueOnce({
+        repository: {
+          discussions: {
+            nodes: [
+              { updatedAt: "2025-06-30T09:00:00Z", comments: { nodes: [{ body: `Digest${marker}`, createdAt: "2025-06-30T09:00:00Z" }] } },
+              { updatedAt: "2025-06-01T09:00:00Z", comments: { nodes: [{ body: `Digest${marker}`, createdAt: "2025-06-01T09:00:00Z" }] } },
+            ],
+          },
+        },
+      });
+
+      const usage = await loadQuotaUsage(mockGithub, "add_comment", { maxPerDay: 5, maxOpen: 0 }, "octo/repo", "daily-report", now);
+      expect(usage).toEqual({ createdToday: 2, open: 0 });
+    });
+  });
+
+  describe("applySafeOutputQuota", () => {
+    it("returns the handler unchanged without a quota", () => {
+      const handler = vi.fn();
+      expect(applySafeOutputQuota("create_issue", { max: 3 }, handler)).toBe(handler);
+    });
+
+    it("does not enforce quotas without a tracker-id", () => {
+      delete process.env.GH_AW_TRACKER_ID;
+      const handler = vi.fn();
+      expect(applySafeOutputQuota("create_issue", { max_per_day: 3 }, handler)).toBe(handler);
+      expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("no tracker-id"));
+    });
+
+    it("skips messages once the quota is reached, counting items created by this run", async () => {
+      mockGithub.rest.search.issuesAndPullRequests.mockResolvedValueOnce({ data: { total_count: 4 } });
+      const handler = vi.fn().mockResolvedValue({ success: true, number: 1, repo: "octo/repo" });
+      const wrapped = applySafeOutputQuota("create_issue", { max_per_day: 5 }, handler);
+
+      expect(await wrapped({ title: "first" }, {})).toEqual({ success: true, number: 1, repo: "octo/repo" });
+      const second = await wrapped({ title: "second" }, {});
+      const third = await wrapped({ title: "third" }, {});
+
+      expect(handler).toHaveBeenCalledTimes(1);
+      expect(second).toEqual({ success: false, error: "Quota max-per-day of 5 reached for create_issue (5 created in the last 24 hours)" });
+      expect(third.success).toBe(false);
+      expect(mockGithub.rest.search.issuesAndPullRequests).toHaveBeenCalledTimes(1);
+      const quotaWarnings = mockCore.warning.mock.calls.filter(([message]) => message.startsWith(QUOTA_REACHED_PREFIX));
+      expect(quotaWarnings).toEqual([[`${QUOTA_REACHED_PREFIX} create_issue max-per-day=5 (5 created in the last 24 hours)`]]);
+    });
+
+    it("does not count failed or skipped results", async () => {
+      mockGithub.rest.search.issuesAndPullRequests.mockResolvedValueOnce({ data: { total_count: 0 } });
+      const handler = vi.fn().mockResolvedValueOnce({ success: false, error: "boom" }).mockResolvedValueOnce({ success: true, skipped: true }).mockResolvedValueOnce({ success: true });
+      const wrapped = applySafeOutputQuota("create_issue", { max_open: 1 }, handler);
+
+      await wrapped({}, {});
+      await wrapped({}, {});
+      expect(await wrapped({}, {})).toEqual({ success: true });
+      expect((await wrapped({}, {})).success).toBe(false);
+    });
+
+    it("rejects messages when the usage cannot be determined", async () => {
+      mockGithub.rest.search.issuesAndPullRequests.mockRejectedValueOnce(new Error("rate limited"));
+      const handler = vi.fn().mockResolvedValue({ success: true });
+      const wrapped = applySafeOutputQuota("create_issue", { max_per_day: 1 }, handler);
+
+      expect(await wrapped({}, {})).toEqual({ success: false, error: "Quota for create_issue could not be checked (rate limited)" });
+      expect((await wrapped({}, {})).success).toBe(false);
+      expect(handler).not.toHaveBeenCalled();
+      expect(mockGithub.rest.search.issuesAndPullRequests).toHaveBeenCalledTimes(1);
+      expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("rejecting create_issue messages: rate limited"));
+    });
+  });
+});
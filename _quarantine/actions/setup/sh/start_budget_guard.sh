@@ -0,0 +1,14 @@
+#!/usr/bin/env bash
+# Start the budget guard in the background
+# The guard stops the agent when the run crosses the per-run token or cost limit
+# configured in the workflow's budget: section. It is stopped again by
+# record_budget_usage.cjs after agent execution.
+
+set -e
+
+BUDGET_DIR=/tmp/gh-aw/budget
+mkdir -p "$BUDGET_DIR"
+
+nohup node /opt/gh-aw/actions/budget_guard.cjs >> "$BUDGET_DIR/guard.log" 2>&1 &
+echo $! > "$BUDGET_DIR/guard.pid"
+echo "Started budget guard with PID $(cat "$BUDGET_DIR/guard.pid")"
@@ -324,6 +324,37 @@ if jq -e '.error' /tmp/gh-aw/mcp-config/gateway-output.json >/dev/null 2>&1; the
   exit 1
 fi
 
+# Start the recording proxy in record mode (sandbox.mcp.record)
+# The agent is pointed at the proxy, which forwards to the gateway and records each MCP call
+# to $MCP_GATEWAY_RECORD_DIR/<server>.jsonl for 'gh aw mcp replay'
+AGENT_GATEWAY_PORT="$MCP_GATEWAY_PORT"
+if [ -n "$MCP_GATEWAY_RECORD_DIR" ]; then
+  if [ -z "$MCP_GATEWAY_RECORD_PORT" ]; then
+    echo "ERROR: MCP_GATEWAY_RECORD_PORT must be set when MCP_GATEWAY_RECORD_DIR is set"
+    kill $GATEWAY_PID 2>/dev/null || true
+    exit 1
+  fi
+  echo "Starting MCP recording proxy on port $MCP_GATEWAY_RECORD_PORT..."
+  mkdir -p "$MCP_GATEWAY_RECORD_DIR"
+  nohup node /opt/gh-aw/actions/mcp_recording_proxy.cjs > /tmp/gh-aw/mcp-logs/recording-proxy.log 2>&1 &
+  RECORDING_PROXY_PID=$!
+  for i in $(seq 1 30); do
+    if curl -s -o /dev/null "http://localhost:${MCP_GATEWAY_RECORD_PORT}/health"; then
+      break
+    fi
+    sleep 0.2
+  done
+  if ! ps -p $RECORDING_PROXY_PID > /dev/null 2>&1; then
+    echo "ERROR: MCP recording proxy failed to start"
+    cat /tmp/gh-aw/mcp-logs/recording-proxy.log 2>/dev/null || true
+    kill $GATEWAY_PID 2>/dev/null || true
+    exit 1
+  fi
+  echo "MCP recording proxy started (PID: $RECORDING_PROXY_PID), recordings: $MCP_GATEWAY_RECORD_DIR"
+  AGENT_GATEWAY_PORT="$MCP_GATEWAY_RECORD_PORT"
+fi
+echo ""
+
 # Convert gateway output to agent-specific format
 echo "Converting gateway configuration to agent format..."
 CONFIG_CONVERT_START=$(date +%s%3N)
@@ -355,22 +386,22 @@ echo "Detected engine type: $ENGINE_TYPE"
 case "$ENGINE_TYPE" in
   copilot)
     echo "Using Copilot converter..."
-    bash /opt/gh-aw/actions/convert_gateway_config_copilot.sh
+    MCP_GATEWAY_PORT="$AGENT_GATEWAY_PORT" bash /opt/gh-aw/actions/convert_gateway_config_copilot.sh
     ;;
   codex)
     echo "Using Codex converter..."
-    bash /opt/gh-aw/actions/convert_gateway_config_codex.sh
+    MCP_GATEWAY_PORT="$AGENT_GATEWAY_PORT" bash /opt/gh-aw/actions/convert_gateway_config_codex.sh
     ;;
   claude)
     echo "Using Claude converter..."
-    bash /opt/gh-aw/actions/convert_gateway_config_claude.sh
+    MCP_GATEWAY_PORT="$AGENT_GATEWAY_PORT" bash /opt/gh-aw/actions/convert_gateway_config_claude.sh
     ;;
   *)
     echo "No agent-specific converter found for engine: $ENGINE_TYPE"
     echo "Using gateway output directly"
     # Default fallback - copy to most common location
     mkdir -p /home/runner/.copilot
-    cp /tmp/gh-aw/mcp-config/gateway-output.json /home/runner/.copilot/mcp-config.json
+    sed "s#:${MCP_GATEWAY_PORT}/mcp/#:${AGENT_GATEWAY_PORT}/mcp/#g" /tmp/gh-aw/mcp-config/gateway-output.json > /home/runner/.copilot/mcp-config.json
     cat /home/runner/.copilot/mcp-config.json
     ;;
 esac
@@ -0,0 +1,99 @@
+#!/usr/bin/env bash
+# Start Repo Memory MCP HTTP Server
+# This script starts the repo-memory MCP server and waits for it to become ready
+
+set -e
+
+cd /opt/gh-aw/actions || exit 1
+
+# Verify required files exist
+echo "Verifying repo-memory setup..."
+
+if [ ! -f "$GH_AW_REPO_MEMORY_CONFIG_PATH" ]; then
+  echo "ERROR: repo-memory config not found at $GH_AW_REPO_MEMORY_CONFIG_PATH"
+  exit 1
+fi
+
+# These files are required by repo_memory_mcp_server.cjs and its dependencies
+REQUIRED_DEPS=(
+  "repo_memory_mcp_server.cjs"
+  "repo_memory_documents.cjs"
+  "json_schema_validator.cjs"
+  "glob_pattern_helpers.cjs"
+  "mcp_http_transport.cjs"
+  "mcp_logger.cjs"
+  "error_helpers.cjs"
+)
+
+MISSING_FILES=()
+for dep in "${REQUIRED_DEPS[@]}"; do
+  if [ ! -f "$dep" ]; then
+    MISSING_FILES+=("$dep")
+  fi
+done
+
+if [ ${#MISSING_FILES[@]} -gt 0 ]; then
+  echo "ERROR: Missing required dependency files in /opt/gh-aw/actions/"
+  for file in "${MISSING_FILES[@]}"; do
+    echo "  - $file"
+  done
+  echo
+  echo "These files should have been copied by the Setup Scripts action."
+  exit 1
+fi
+
+echo "All ${#REQUIRED_DEPS[@]} required dependency files present"
+
+# Log environment configuration
+echo "Server configuration:"
+echo "  Port: $GH_AW_REPO_MEMORY_PORT"
+echo "  API Key: ${GH_AW_REPO_MEMORY_API_KEY:0:8}..."
+echo "  Config: $GH_AW_REPO_MEMORY_CONFIG_PATH"
+
+# Ensure logs directory exists
+LOG_DIR=/tmp/gh-aw/mcp-logs/repomemory
+mkdir -p "$LOG_DIR"
+{
+  echo "Repo Memory MCP Server Log"
+  echo "Start time: $(date)"
+  echo "==========================================="
+  echo ""
+} > "$LOG_DIR/server.log"
+
+# Start the HTTP server in the background with DEBUG enabled
+echo "Starting repo-memory MCP HTTP server..."
+DEBUG="*" node repo_memory_mcp_server.cjs >> "$LOG_DIR/server.log" 2>&1 &
+SERVER_PID=$!
+echo "Started repo-memory MCP server with PID $SERVER_PID"
+
+# Wait for server to be ready (max 10 seconds)
+echo "Waiting for server to become ready..."
+for i in {1..10}; do
+  if ! kill -0 $SERVER_PID 2>/dev/null; then
+    echo "ERROR: Server process $SERVER_PID has died"
+    echo "Server log contents:"
+    cat "$LOG_DIR/server.log"
+    exit 1
+  fi
+
+  if curl -s -f "http://localhost:$GH_AW_REPO_MEMORY_PORT/health" > /dev/null 2>&1; then
+    echo "Repo memory MCP server is ready (attempt $i/10)"
+    break
+  fi
+
+  if [ "$i" -eq 10 ]; then
+    echo "ERROR: Repo memory MCP server failed to start after 10 seconds"
+    echo "Server log contents:"
+    cat "$LOG_DIR/server.log"
+    exit 1
+  fi
+
+  echo "Waiting for server... (attempt $i/10)"
+  sleep 1
+done
+
+# Output the configuration for the MCP client
+{
+  echo "port=$GH_AW_REPO_MEMORY_PORT"
+  echo "api_key=$GH_AW_REPO_MEMORY_API_KEY"
+} >> "$GITHUB_OUTPUT"
@@ -37,6 +37,13 @@ func TestArgumentSyntaxConsistency(t *testing.T) {
 			argsValidator:  "MinimumNArgs(1)",
 			shouldValidate: func(cmd *cobra.Command) error { return cmd.Args(cmd, []string{"test"}) },
 		},
+		{
+			name:           "sbom command requires workflow",
+			command:        cli.NewSBOMCommand(),
+			expectedUse:    "sbom <workflow>",
+			argsValidator:  "ExactArgs(1)",
+			shouldValidate: func(cmd *cobra.Command) error { return cmd.Args(cmd, []string{"test"}) },
+		},
 		{
 			name:           "add command requires workflow",
 			command:        cli.NewAddCommand(validateEngine),
@@ -166,6 +173,16 @@ func TestMCPSubcommandArgumentSyntax(t *testing.T) {
 			subcommand:  "inspect",
 			expectedUse: "inspect [workflow]",
 		},
+		{
+			name:        "mcp test has optional workflow",
+			subcommand:  "test",
+			expectedUse: "test [workflow]",
+		},
+		{
+			name:        "mcp replay requires run-id",
+			subcommand:  "replay",
+			expectedUse: "replay <run-id>",
+		},
 		{
 			name:        "mcp add has optional workflow and server",
 			subcommand:  "add",
@@ -42,8 +42,8 @@ func formatListWithOr(items []string) string {
 
 // validateEngine validates the engine flag value
 func validateEngine(engine string) error {
-	// Get the global engine registry
-	registry := workflow.GetGlobalEngineRegistry()
+	// Get the engine registry including the repository's engine manifests
+	registry := workflow.GetRepositoryEngineRegistry()
 	validEngines := registry.GetSupportedEngines()
 
 	if engine != "" && !registry.IsValidEngine(engine) {
@@ -231,6 +231,13 @@ The --dependabot flag generates dependency manifests when dependencies are detec
   - Cannot be used with specific workflow files or custom --dir
   - Only processes workflows in the default .github/workflows directory
 
+The --lock-deps flag pins the dependencies workflows install at run time:
+  - Resolves engine CLI versions and npx, uvx and pip packages to exact versions
+  - Records container images with their digest when docker has pulled them
+  - Writes them to .github/aw/aw-deps.lock, which should be committed
+  - Compiled steps install the locked versions whenever aw-deps.lock exists
+  - Use --force to resolve every dependency again
+
 Examples:
   ` + string(constants.CLIExtensionPrefix) + ` compile                    # Compile all Markdown files
   ` + string(constants.CLIExtensionPrefix) + ` compile ci-doctor    # Compile a specific workflow
@@ -240,7 +247,9 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` compile --watch ci-doctor     # Watch and auto-compile
   ` + string(constants.CLIExtensionPrefix) + ` compile --trial --logical-repo owner/repo  # Compile for trial mode
   ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot        # Generate Dependabot manifests
-  ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot --force  # Force overwrite existing dependabot.yml`,
+  ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot --force  # Force overwrite existing dependabot.yml
+  ` + string(constants.CLIExtensionPrefix) + ` compile --lock-deps        # Pin runtime dependencies in aw-deps.lock
+  ` + string(constants.CLIExtensionPrefix) + ` compile --zizmor --sarif results.sarif  # Write findings for code scanning`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		engineOverride, _ := cmd.Flags().GetString("engine")
 		actionMode, _ := cmd.Flags().GetString("action-mode")
@@ -256,6 +265,7 @@ Examples:
 		logicalRepo, _ := cmd.Flags().GetString("logical-repo")
 		dependabot, _ := cmd.Flags().GetBool("dependabot")
 		forceOverwrite, _ := cmd.Flags().GetBool("force")
+		lockDeps, _ := cmd.Flags().GetBool("lock-deps")
 		refreshStopTime, _ := cmd.Flags().GetBool("refresh-stop-time")
 		forceRefreshActionPins, _ := cmd.Flags().GetBool("force-refresh-action-pins")
 		zizmor, _ := cmd.Flags().GetBool("zizmor")
@@ -265,6 +275,8 @@ Examples:
 		fix, _ := cmd.Flags().GetBool("fix")
 		stats, _ := cmd.Flags().GetBool("stats")
 		failFast, _ := cmd.Flags().GetBool("fail-fast")
+		policy, _ := cmd.Flags().GetString("policy")
+		sarifOutput, _ := cmd.Flags().GetString("sarif")
 		noCheckUpdate, _ := cmd.Flags().GetBool("no-check-update")
 		verbose, _ := cmd.Flags().GetBool("verbose")
 		if err := validateEngine(engineOverride); err != nil {
@@ -309,6 +321,7 @@ Examples:
 			Strict:                 strict,
 			Dependabot:             dependabot,
 			ForceOverwrite:         forceOverwrite,
+			LockDeps:               lockDeps,
 			RefreshStopTime:        refreshStopTime,
 			ForceRefreshActionPins: forceRefreshActionPins,
 			Zizmor:                 zizmor,
@@ -317,6 +330,8 @@ Examples:
 			JSONOutput:             jsonOutput,
 			Stats:                  stats,
 			FailFast:               failFast,
+			Policy:                 policy,
+			SARIFOutput:            sarifOutput,
 		}
 		if _, err := cli.CompileWorkflows(cmd.Context(), config); err != nil {
 			// Return error as-is without additional formatting
@@ -527,7 +542,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	compileCmd.Flags().StringP("engine", "e", "", "Override AI engine (claude, codex, copilot, custom)")
 	compileCmd.Flags().String("action-mode", "", "Action script inlining mode (inline, dev, release). Auto-detected if not specified")
 	compileCmd.Flags().String("action-tag", "", "Override action SHA or tag for actions/setup (overrides action-mode to release). Accepts full SHA or tag name")
-	compileCmd.Flags().Bool("validate", false, "Enable GitHub Actions workflow schema validation, container image validation, and action SHA validation")
+	compileCmd.Flags().Bool("validate", false, "Enable GitHub Actions workflow schema validation, container image validation, action SHA validation, and always-false condition detection")
 	compileCmd.Flags().BoolP("watch", "w", false, "Watch for changes to workflow files and recompile automatically")
 	compileCmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
 	compileCmd.Flags().String("workflows-dir", "", "Deprecated: use --dir instead")
@@ -538,9 +553,10 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	compileCmd.Flags().Bool("trial", false, "Enable trial mode compilation (modifies workflows for trial execution)")
 	compileCmd.Flags().String("logical-repo", "", "Repository to simulate workflow execution against (for trial mode)")
 	compileCmd.Flags().Bool("dependabot", false, "Generate dependency manifests (package.json, requirements.txt, go.mod) and Dependabot config when dependencies are detected")
-	compileCmd.Flags().Bool("force", false, "Force overwrite of existing dependency files (e.g., dependabot.yml)")
+	compileCmd.Flags().Bool("force", false, "Force overwrite of existing dependency files (e.g., dependabot.yml) and re-resolve locked dependencies")
+	compileCmd.Flags().Bool("lock-deps", false, "Resolve engine CLIs, npx/uvx/pip packages and container images to exact versions in .github/aw/aw-deps.lock")
 	compileCmd.Flags().Bool("refresh-stop-time", false, "Force regeneration of stop-after times instead of preserving existing values from lock files")
-	compileCmd.Flags().Bool("force-refresh-action-pins", false, "Force refresh of action pins by clearing the cache and resolving all action SHAs from GitHub API")
+	compileCmd.Flags().Bool("force-refresh-action-pins", false, "Force refresh of action pins by clearing the cache and resolving all action SHAs from GitHub API and container image digests from their registries")
 	compileCmd.Flags().Bool("zizmor", false, "Run zizmor security scanner on generated .lock.yml files")
 	compileCmd.Flags().Bool("poutine", false, "Run poutine security scanner on generated .lock.yml files")
 	compileCmd.Flags().Bool("actionlint", false, "Run actionlint linter on generated .lock.yml files")
@@ -548,6 +564,8 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	compileCmd.Flags().BoolP("json", "j", false, "Output results in JSON format")
 	compileCmd.Flags().Bool("stats", false, "Display statistics table sorted by file size (shows jobs, steps, scripts, and shells)")
 	compileCmd.Flags().Bool("fail-fast", false, "Stop at the first validation error instead of collecting all errors")
+	compileCmd.Flags().String("policy", "", "Organization policy enforced on every workflow: local path or owner/repo/path@ref (default: .github/aw/policy.yml when present)")
+	compileCmd.Flags().String("sarif", "", "Write compiler, validator and scanner findings as a SARIF 2.1.0 log located in the workflow .md files ('-' for stdout)")
 	compileCmd.Flags().Bool("no-check-update", false, "Skip checking for gh-aw updates")
 	compileCmd.MarkFlagsMutuallyExclusive("dir", "workflows-dir")
 
@@ -604,6 +622,18 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	completionCmd := cli.NewCompletionCommand()
 	hashCmd := cli.NewHashCommand()
 	projectCmd := cli.NewProjectCommand()
+	simulateCmd := cli.NewSimulateCommand()
+	verifyCmd := cli.NewVerifyCommand()
+	diffCmd := cli.NewDiffCommand()
+	networkCmd := cli.NewNetworkCommand()
+	lspCmd := cli.NewLSPCommand()
+	policyCmd := cli.NewPolicyCommand()
+	memoryCmd := cli.NewMemoryCommand()
+	cacheMemoryCmd := cli.NewCacheMemoryCommand()
+	scheduleCmd := cli.NewScheduleCommand()
+
+	// Create and setup sbom command
+	sbomCmd := cli.NewSBOMCommand()
 
 	// Assign commands to groups
 	// Setup Commands
@@ -621,17 +651,27 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	statusCmd.GroupID = "development"
 	listCmd.GroupID = "development"
 	fixCmd.GroupID = "development"
+	verifyCmd.GroupID = "development"
+	diffCmd.GroupID = "development"
+	lspCmd.GroupID = "development"
 
 	// Execution Commands
 	runCmd.GroupID = "execution"
 	enableCmd.GroupID = "execution"
 	disableCmd.GroupID = "execution"
 	trialCmd.GroupID = "execution"
+	simulateCmd.GroupID = "execution"
 
 	// Analysis Commands
 	logsCmd.GroupID = "analysis"
 	auditCmd.GroupID = "analysis"
 	healthCmd.GroupID = "analysis"
+	networkCmd.GroupID = "analysis"
+	policyCmd.GroupID = "analysis"
+	memoryCmd.GroupID = "analysis"
+	cacheMemoryCmd.GroupID = "analysis"
+	scheduleCmd.GroupID = "analysis"
+	sbomCmd.GroupID = "analysis"
 
 	// Utilities
 	mcpServerCmd.GroupID = "utilities"
@@ -647,6 +687,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(upgradeCmd)
 	rootCmd.AddCommand(trialCmd)
+	rootCmd.AddCommand(simulateCmd)
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(initCmd)
 
@@ -659,12 +700,21 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(logsCmd)
 	rootCmd.AddCommand(auditCmd)
 	rootCmd.AddCommand(healthCmd)
+	rootCmd.AddCommand(networkCmd)
+	rootCmd.AddCommand(policyCmd)
+	rootCmd.AddCommand(memoryCmd)
+	rootCmd.AddCommand(cacheMemoryCmd)
+	rootCmd.AddCommand(scheduleCmd)
+	rootCmd.AddCommand(sbomCmd)
 	rootCmd.AddCommand(mcpCmd)
 	rootCmd.AddCommand(mcpServerCmd)
 	rootCmd.AddCommand(prCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(secretsCmd)
 	rootCmd.AddCommand(fixCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(lspCmd)
 	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(hashCmd)
 	rootCmd.AddCommand(projectCmd)
@@ -391,6 +391,12 @@ func parseAndDisplayActionlintOutput(stdout string, verbose bool) (int, map[stri
 		}
 
 		fmt.Fprint(os.Stderr, console.FormatError(compilerErr))
+
+		ruleID := "actionlint"
+		if err.Kind != "" {
+			ruleID += "/" + err.Kind
+		}
+		recordCompileSARIFLockFinding(err.Filepath, err.Line, ruleID, "actionlint "+err.Kind+" check", errorType, err.Message)
 	}
 
 	return totalErrors, errorsByKind, nil
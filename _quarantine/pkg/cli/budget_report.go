@@ -0,0 +1,126 @@
+// This file provides budget consumption reporting for the logs and health commands.
+//
+// Workflows can declare token and cost limits in a budget: frontmatter section. The
+// compiled workflow records the limits in aw_info.json, which the logs command uses to
+// report how much of the per-run budget each run consumed. The health command reads
+// the limits from the local workflow file and reports consumption of the per-run and
+// rolling 7/30 day limits over the analyzed period.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var budgetReportLog = logger.New("cli:budget_report")
+
+// runBudgetUsagePercent returns the share of the per-run budget a run consumed, as a
+// percentage of whichever limit (tokens or cost) the run is closest to. Returns 0 when
+// no per-run limit is configured.
+func runBudgetUsagePercent(tokens int, cost float64, budget *AwInfoBudget) float64 {
+	if budget == nil {
+		return 0
+	}
+	var percent float64
+	if budget.MaxTokens > 0 {
+		percent = max(percent, float64(tokens)/float64(budget.MaxTokens)*100)
+	}
+	if budget.MaxCost > 0 {
+		percent = max(percent, cost/budget.MaxCost*100)
+	}
+	return percent
+}
+
+// formatBudgetUsage formats a budget consumption percentage for display
+func formatBudgetUsage(percent float64) string {
+	if percent >= 100 {
+		return fmt.Sprintf("%.0f%% ⚠", percent)
+	}
+	return fmt.Sprintf("%.0f%%", percent)
+}
+
+// loadWorkflowBudget reads the budget: section of the local workflow source for a compiled
+// workflow path (e.g. .github/workflows/triage.lock.yml). Returns nil when the workflow has
+// no budget or its source is not available locally.
+func loadWorkflowBudget(workflowPath string) *workflow.BudgetConfig {
+	if !strings.HasSuffix(workflowPath, ".lock.yml") {
+		return nil
+	}
+	markdownPath := strings.TrimSuffix(workflowPath, ".lock.yml") + ".md"
+	if !filepath.IsAbs(markdownPath) {
+		if gitRoot, err := findGitRoot(); err == nil {
+			markdownPath = filepath.Join(gitRoot, markdownPath)
+		}
+	}
+
+	content, err := os.ReadFile(markdownPath)
+	if err != nil {
+		budgetReportLog.Printf("Workflow source not available for %s: %v", workflowPath, err)
+		return nil
+	}
+	result, err := parser.ExtractFrontmatterFromContent(string(content))
+	if err != nil {
+		budgetReportLog.Printf("Failed to parse frontmatter of %s: %v", markdownPath, err)
+		return nil
+	}
+	budget, err := workflow.ExtractBudgetConfig(result.Frontmatter)
+	if err != nil {
+		budgetReportLog.Printf("Invalid budget in %s: %v", markdownPath, err)
+		return nil
+	}
+	return budget
+}
+
+// calculateBudgetConsumption returns the highest consumption, as a percentage, of the
+// workflow's budget limits by the given runs. Rolling limits are only evaluated when the
+// analyzed period covers their window.
+func calculateBudgetConsumption(runs []WorkflowRun, budget *workflow.BudgetConfig, days int, now time.Time) float64 {
+	if budget == nil {
+		return 0
+	}
+
+	perRun := &AwInfoBudget{MaxTokens: budget.MaxTokens, MaxCost: budget.MaxCost}
+	var percent float64
+	var cost7d, cost30d float64
+	for _, run := range runs {
+		percent = max(percent, runBudgetUsagePercent(run.TokenUsage, run.EstimatedCost, perRun))
+		age := now.Sub(run.CreatedAt)
+		if age <= 7*24*time.Hour {
+			cost7d += run.EstimatedCost
+		}
+		if age <= 30*24*time.Hour {
+			cost30d += run.EstimatedCost
+		}
+	}
+
+	if budget.MaxCost7d > 0 && days >= 7 {
+		percent = max(percent, cost7d/budget.MaxCost7d*100)
+	}
+	if budget.MaxCost30d > 0 && days >= 30 {
+		percent = max(percent, cost30d/budget.MaxCost30d*100)
+	}
+	return percent
+}
+
+// applyWorkflowBudget fills in the budget consumption of a workflow's health metrics
+// from the budget declared in the local workflow source
+func applyWorkflowBudget(health *WorkflowHealth, runs []WorkflowRun, days int) {
+	if len(runs) == 0 {
+		return
+	}
+	budget := loadWorkflowBudget(runs[0].WorkflowPath)
+	if budget == nil {
+		return
+	}
+	health.BudgetUsage = calculateBudgetConsumption(runs, budget, days, time.Now())
+	health.DisplayBudget = formatBudgetUsage(health.BudgetUsage)
+	budgetReportLog.Printf("Budget consumption for %s: %.1f%%", health.WorkflowName, health.BudgetUsage)
+}
@@ -0,0 +1,84 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBudgetUsagePercent(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens int
+		cost   float64
+		budget *AwInfoBudget
+		want   float64
+	}{
+		{name: "no budget", tokens: 1000, cost: 1, budget: nil, want: 0},
+		{name: "token limit", tokens: 250, cost: 0, budget: &AwInfoBudget{MaxTokens: 1000}, want: 25},
+		{name: "cost limit", tokens: 0, cost: 1.5, budget: &AwInfoBudget{MaxCost: 2}, want: 75},
+		{name: "closest limit wins", tokens: 900, cost: 0.5, budget: &AwInfoBudget{MaxTokens: 1000, MaxCost: 2}, want: 90},
+		{name: "rolling limits ignored", tokens: 100, cost: 10, budget: &AwInfoBudget{MaxCost7d: 5}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, runBudgetUsagePercent(tt.tokens, tt.cost, tt.budget), 0.001)
+		})
+	}
+}
+
+func TestFormatBudgetUsage(t *testing.T) {
+	assert.Equal(t, "42%", formatBudgetUsage(42.4))
+	assert.Equal(t, "100% ⚠", formatBudgetUsage(100))
+	assert.Equal(t, "150% ⚠", formatBudgetUsage(150.2))
+}
+
+func TestCalculateBudgetConsumption(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	runs := []WorkflowRun{
+		{CreatedAt: now.Add(-24 * time.Hour), TokenUsage: 400, EstimatedCost: 1},
+		{CreatedAt: now.Add(-3 * 24 * time.Hour), TokenUsage: 200, EstimatedCost: 2},
+		{CreatedAt: now.Add(-20 * 24 * time.Hour), TokenUsage: 100, EstimatedCost: 6},
+	}
+
+	tests := []struct {
+		name   string
+		budget *workflow.BudgetConfig
+		days   int
+		want   float64
+	}{
+		{name: "no budget", budget: nil, days: 7, want: 0},
+		{name: "per-run token limit", budget: &workflow.BudgetConfig{MaxTokens: 1000}, days: 7, want: 40},
+		{name: "7 day limit", budget: &workflow.BudgetConfig{MaxCost7d: 10}, days: 7, want: 30},
+		{name: "30 day limit", budget: &workflow.BudgetConfig{MaxCost30d: 10}, days: 30, want: 90},
+		{name: "30 day limit outside analyzed period", budget: &workflow.BudgetConfig{MaxCost30d: 10}, days: 7, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, calculateBudgetConsumption(runs, tt.budget, tt.days, now), 0.001)
+		})
+	}
+}
+
+func TestLoadWorkflowBudget(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "test-*")
+	withBudget := filepath.Join(tmpDir, "budgeted.md")
+	require.NoError(t, os.WriteFile(withBudget, []byte("---\non: push\nbudget:\n  max-cost-7d: 20\n---\n# Budgeted\n"), 0644))
+	withoutBudget := filepath.Join(tmpDir, "plain.md")
+	require.NoError(t, os.WriteFile(withoutBudget, []byte("---\non: push\n---\n# Plain\n"), 0644))
+
+	assert.Equal(t, &workflow.BudgetConfig{MaxCost7d: 20}, loadWorkflowBudget(filepath.Join(tmpDir, "budgeted.lock.yml")))
+	assert.Nil(t, loadWorkflowBudget(filepath.Join(tmpDir, "plain.lock.yml")))
+	assert.Nil(t, loadWorkflowBudget(filepath.Join(tmpDir, "missing.lock.yml")))
+	assert.Nil(t, loadWorkflowBudget(withBudget), "only compiled workflow paths are resolved")
+}
@@ -0,0 +1,613 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var cacheMemoryCommandLog = logger.New("cli:cache_memory_command")
+
+// CacheMemoryCommandConfig holds the options shared by the cache-memory subcommands
+type CacheMemoryCommandConfig struct {
+	WorkflowID string
+	CacheID    string // cache-memory ID (defaults to the only cache of the workflow)
+	Repo       string // Repository owning the caches (defaults to the current repository)
+	CacheDir   string // Local directory standing in for the Actions cache (one subdirectory per key)
+	Verbose    bool
+}
+
+// NewCacheMemoryCommand creates the main cache-memory command with subcommands
+func NewCacheMemoryCommand() *cobra.Command {
+	cacheMemoryCommandLog.Print("Creating cache-memory command with subcommands")
+	cmd := &cobra.Command{
+		Use:   "cache-memory",
+		Short: "Inspect, clean up and migrate the cache-memory of agentic workflows",
+		Long: `Inspect and maintain the caches that workflows save with tools.cache-memory.
+
+Every run saves its cache-memory under a new key (memory-<workflow>-<run id> by default)
+and GitHub evicts caches that are not accessed for 7 days. These commands list the caches
+of a workflow with the Actions cache API, download their content, compare runs, delete
+stale caches and move the content to repo-memory, which is kept on a git branch.
+
+The Actions cache API does not serve cache content, so downloads use the cache-memory
+artifact uploaded by the run that saved the cache (workflows with threat detection upload
+it). Use --cache-dir to work with a local directory holding one subdirectory per key instead.
+
+Available subcommands:
+  • list     - List the caches of a workflow
+  • download - Download the content of a cache
+  • diff     - Compare the content of two caches
+  • prune    - Delete stale caches
+  • migrate  - Copy a cache into a repo-memory branch
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory list daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory download daily-report -o ./cache
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory migrate daily-report`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(NewCacheMemoryListSubcommand())
+	cmd.AddCommand(NewCacheMemoryDownloadSubcommand())
+	cmd.AddCommand(NewCacheMemoryDiffSubcommand())
+	cmd.AddCommand(NewCacheMemoryPruneSubcommand())
+	cmd.AddCommand(NewCacheMemoryMigrateSubcommand())
+
+	return cmd
+}
+
+// addCacheMemoryFlags adds the flags shared by the cache-memory subcommands
+func addCacheMemoryFlags(cmd *cobra.Command) {
+	cmd.Flags().String("id", "", "Cache-memory ID (default: the only cache of the workflow)")
+	cmd.Flags().String("cache-dir", "", "Local directory standing in for the Actions cache, with one subdirectory per cache key")
+	addRepoFlag(cmd)
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+}
+
+// cacheMemoryConfigFromFlags reads the shared cache-memory flags
+func cacheMemoryConfigFromFlags(cmd *cobra.Command, workflowID string) CacheMemoryCommandConfig {
+	cacheID, _ := cmd.Flags().GetString("id")
+	cacheDir, _ := cmd.Flags().GetString("cache-dir")
+	repo, _ := cmd.Flags().GetString("repo")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	return CacheMemoryCommandConfig{WorkflowID: workflowID, CacheID: cacheID, Repo: repo, CacheDir: cacheDir, Verbose: verbose}
+}
+
+// NewCacheMemoryListSubcommand creates the cache-memory list subcommand
+func NewCacheMemoryListSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list <workflow>",
+		Short: "List the caches saved by a workflow's cache-memory",
+		Long: `List the caches saved by the cache-memory of a workflow, newest first, with the run that
+saved them, their size and when they were last restored.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory list daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory list daily-report --id notes --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return RunCacheMemoryList(cacheMemoryConfigFromFlags(cmd, args[0]), jsonOutput)
+		},
+	}
+	addCacheMemoryFlags(cmd)
+	addJSONFlag(cmd)
+	return cmd
+}
+
+// NewCacheMemoryDownloadSubcommand creates the cache-memory download subcommand
+func NewCacheMemoryDownloadSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "download <workflow> [key]",
+		Short: "Download and unpack the content of a cache",
+		Long: `Download the content of a cache into a directory. The cache is given by its key or by the
+ID of the run that saved it; the newest cache is used when omitted.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory download daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory download daily-report 12345678 -o ./cache`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
+			key := ""
+			if len(args) > 1 {
+				key = args[1]
+			}
+			return RunCacheMemoryDownload(cacheMemoryConfigFromFlags(cmd, args[0]), key, output)
+		},
+	}
+	addCacheMemoryFlags(cmd)
+	cmd.Flags().StringP("output", "o", "", "Directory to unpack the cache into (default: ./<cache key>)")
+	return cmd
+}
+
+// NewCacheMemoryDiffSubcommand creates the cache-memory diff subcommand
+func NewCacheMemoryDiffSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <workflow> <from> [to]",
+		Short: "Compare the content of two caches",
+		Long: `Show how the cache-memory content changed between two caches, given by key or run ID.
+'to' defaults to the newest cache.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory diff daily-report 12345678
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory diff daily-report 12345678 12349999 --name-only`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nameOnly, _ := cmd.Flags().GetBool("name-only")
+			to := ""
+			if len(args) > 2 {
+				to = args[2]
+			}
+			return RunCacheMemoryDiff(cacheMemoryConfigFromFlags(cmd, args[0]), args[1], to, nameOnly)
+		},
+	}
+	addCacheMemoryFlags(cmd)
+	cmd.Flags().Bool("name-only", false, "Only list the files that changed")
+	return cmd
+}
+
+// NewCacheMemoryPruneSubcommand creates the cache-memory prune subcommand
+func NewCacheMemoryPruneSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune <workflow>",
+		Short: "Delete stale caches of a workflow's cache-memory",
+		Long: `Delete caches that were not restored within a period (--older-than, e.g. 3d, 1w) and/or
+all but the newest caches (--keep). Only the newest cache is restored by the next run, so
+older ones just use up the repository cache quota.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory prune daily-report --keep 1 --dry-run
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory prune daily-report --older-than 3d`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			olderThan, _ := cmd.Flags().GetString("older-than")
+			keep, _ := cmd.Flags().GetInt("keep")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			return RunCacheMemoryPrune(cacheMemoryConfigFromFlags(cmd, args[0]), olderThan, keep, dryRun)
+		},
+	}
+	addCacheMemoryFlags(cmd)
+	cmd.Flags().String("older-than", "", "Delete caches not restored within this period (e.g. 3d, 1w)")
+	cmd.Flags().Int("keep", 0, "Keep only the newest N caches")
+	cmd.Flags().Bool("dry-run", false, "Show the caches that would be deleted without deleting them")
+	return cmd
+}
+
+// NewCacheMemoryMigrateSubcommand creates the cache-memory migrate subcommand
+func NewCacheMemoryMigrateSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate <workflow> [key]",
+		Short: "Copy the content of a cache into a repo-memory branch",
+		Long: `Copy the content of a cache (the newest by default) into a repo-memory branch, so the
+workflow can switch from cache-memory to repo-memory without losing what it learned.
+
+The target is the repo-memory of the workflow selected with --to (default: the only one).
+When the workflow has no repo-memory yet, the content goes to the branch memory/<id> and
+the frontmatter to add is printed. The memory's file-glob, max-file-size and
+max-file-count limits are enforced.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory migrate daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` cache-memory migrate daily-report 12345678 --to notes --force`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			to, _ := cmd.Flags().GetString("to")
+			force, _ := cmd.Flags().GetBool("force")
+			key := ""
+			if len(args) > 1 {
+				key = args[1]
+			}
+			return RunCacheMemoryMigrate(cacheMemoryConfigFromFlags(cmd, args[0]), key, to, force)
+		},
+	}
+	addCacheMemoryFlags(cmd)
+	cmd.Flags().String("to", "", "Repo-memory ID to migrate into (default: the only repo-memory, or the cache ID)")
+	cmd.Flags().Bool("force", false, "Replace a repo-memory that already has files")
+	return cmd
+}
+
+// workflowCacheMemory is the cache-memory of a workflow selected by the command flags
+type workflowCacheMemory struct {
+	data   *workflow.WorkflowData
+	cache  workflow.CacheMemoryEntry
+	prefix string // key prefix shared by the caches of every run
+	store  cacheStore
+}
+
+// openWorkflowCacheMemory parses a workflow and selects one of its cache-memories
+func openWorkflowCacheMemory(config CacheMemoryCommandConfig) (*workflowCacheMemory, error) {
+	workflowFile, err := resolveWorkflowFile(config.WorkflowID, config.Verbose)
+	if err != nil {
+		return nil, err
+	}
+	compiler := workflow.NewCompiler(workflow.WithVerbose(config.Verbose))
+	data, err := compiler.ParseWorkflowFile(workflowFile)
+	if err != nil {
+		return nil, err
+	}
+	if data.CacheMemoryConfig == nil || len(data.CacheMemoryConfig.Caches) == 0 {
+		return nil, fmt.Errorf("workflow '%s' does not use tools.cache-memory", config.WorkflowID)
+	}
+	cache, err := selectCacheMemory(data.CacheMemoryConfig.Caches, config.CacheID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ${{ github.workflow }} is the name of the workflow
+	prefix := workflow.CacheMemoryKeyPrefix(cache, data.Name)
+	cacheMemoryCommandLog.Printf("Selected cache-memory %s with key prefix %q", cache.ID, prefix)
+	return &workflowCacheMemory{
+		data:   data,
+		cache:  cache,
+		prefix: prefix,
+		store:  newCacheStore(config.Repo, config.CacheDir, config.Verbose),
+	}, nil
+}
+
+// selectCacheMemory selects a cache-memory by ID, defaulting to the only cache of the workflow
+func selectCacheMemory(caches []workflow.CacheMemoryEntry, cacheID string) (workflow.CacheMemoryEntry, error) {
+	ids := make([]string, 0, len(caches))
+	for _, cache := range caches {
+		if cache.ID == cacheID || (cacheID == "" && len(caches) == 1) {
+			return cache, nil
+		}
+		ids = append(ids, cache.ID)
+	}
+	if cacheID == "" {
+		return workflow.CacheMemoryEntry{}, fmt.Errorf("the workflow has %d cache-memories, select one with --id: %s", len(caches), strings.Join(ids, ", "))
+	}
+	return workflow.CacheMemoryEntry{}, fmt.Errorf("cache-memory '%s' not found, available: %s", cacheID, strings.Join(ids, ", "))
+}
+
+// artifactNames returns the names the agent job may have used for the cache-memory artifact
+func (w *workflowCacheMemory) artifactNames() []string {
+	if w.cache.ID == "default" {
+		return []string{"cache-memory", "cache-memory-default"}
+	}
+	return []string{"cache-memory-" + w.cache.ID}
+}
+
+// resolve finds the cache for a key or run ID; an empty reference selects the newest cache
+func (w *workflowCacheMemory) resolve(entries []cacheEntry, reference string) (cacheEntry, error) {
+	if len(entries) == 0 {
+		return cacheEntry{}, fmt.Errorf("no caches found for key prefix '%s'", w.prefix)
+	}
+	if reference == "" {
+		return entries[0], nil
+	}
+	for _, entry := range entries {
+		if entry.Key == reference || w.prefix+reference == entry.Key {
+			return entry, nil
+		}
+	}
+	return cacheEntry{}, fmt.Errorf("no cache with key or run ID '%s' (keys start with '%s')", reference, w.prefix)
+}
+
+// fetch downloads the content of a cache into a new temporary directory
+func (w *workflowCacheMemory) fetch(entry cacheEntry) (string, error) {
+	dir, err := os.MkdirTemp("", "gh-aw-cache-memory-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	if err := w.store.download(entry, w.artifactNames(), dir); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// RunCacheMemoryList lists the caches of a workflow's cache-memory
+func RunCacheMemoryList(config CacheMemoryCommandConfig, jsonOutput bool) error {
+	w, err := openWorkflowCacheMemory(config)
+	if err != nil {
+		return err
+	}
+	entries, err := w.store.list(w.prefix)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		if entries == nil {
+			entries = []cacheEntry{}
+		}
+		return printMemoryJSON(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No caches found for cache-memory %s (key prefix '%s')", w.cache.ID, w.prefix)))
+		return nil
+	}
+
+	rows := make([][]string, 0, len(entries))
+	var total int64
+	for _, entry := range entries {
+		run := "-"
+		if runID := cacheRunID(entry.Key, w.prefix); runID != 0 {
+			run = fmt.Sprintf("%d", runID)
+		}
+		rows = append(rows, []string{entry.Key, run, console.FormatFileSize(entry.Size), entry.CreatedAt.Format("2006-01-02 15:04"), entry.LastAccessedAt.Format("2006-01-02 15:04")})
+		total += entry.Size
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:     fmt.Sprintf("Caches of cache-memory %s", w.cache.ID),
+		Headers:   []string{"Key", "Run", "Size", "Created", "Last Accessed"},
+		Rows:      rows,
+		ShowTotal: true,
+		TotalRow:  []string{fmt.Sprintf("%d cache(s)", len(entries)), "", console.FormatFileSize(total), "", ""},
+	}))
+	return nil
+}
+
+// RunCacheMemoryDownload unpacks the content of a cache into a directory
+func RunCacheMemoryDownload(config CacheMemoryCommandConfig, key, output string) error {
+	w, err := openWorkflowCacheMemory(config)
+	if err != nil {
+		return err
+	}
+	entries, err := w.store.list(w.prefix)
+	if err != nil {
+		return err
+	}
+	entry, err := w.resolve(entries, key)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = entry.Key
+	}
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	if err := w.store.download(entry, w.artifactNames(), output); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Downloaded cache %s to %s", entry.Key, output)))
+	return nil
+}
+
+// RunCacheMemoryDiff prints the differences between the content of two caches
+func RunCacheMemoryDiff(config CacheMemoryCommandConfig, from, to string, nameOnly bool) error {
+	w, err := openWorkflowCacheMemory(config)
+	if err != nil {
+		return err
+	}
+	entries, err := w.store.list(w.prefix)
+	if err != nil {
+		return err
+	}
+	fromEntry, err := w.resolve(entries, from)
+	if err != nil {
+		return err
+	}
+	toEntry, err := w.resolve(entries, to)
+	if err != nil {
+		return err
+	}
+
+	// Lay both caches out side by side so the diff shows paths relative to the cache
+	parent, err := os.MkdirTemp("", "gh-aw-cache-memory-diff-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(parent)
+	for name, entry := range map[string]cacheEntry{"from": fromEntry, "to": toEntry} {
+		dir := filepath.Join(parent, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := w.store.download(entry, w.artifactNames(), dir); err != nil {
+			return err
+		}
+	}
+
+	diff, err := diffCacheDirs(parent, nameOnly)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No changes between %s and %s", fromEntry.Key, toEntry.Key)))
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "%s\n", console.FormatInfoMessage(fmt.Sprintf("Changes from %s to %s", fromEntry.Key, toEntry.Key)))
+	fmt.Fprint(os.Stdout, diff)
+	return nil
+}
+
+// diffCacheDirs diffs the "from" and "to" directories below parent with git
+func diffCacheDirs(parent string, nameOnly bool) (string, error) {
+	args := []string{"diff", "--no-index", "--no-color"}
+	if nameOnly {
+		args = append(args, "--name-status")
+	}
+	args = append(args, "from", "to")
+	cmd := exec.Command("git", args...)
+	cmd.Dir = parent
+	output, err := cmd.Output()
+	var exitErr *exec.ExitError
+	// git diff --no-index exits with 1 when the directories differ
+	if err != nil && (!errors.As(err, &exitErr) || exitErr.ExitCode() != 1) {
+		return "", fmt.Errorf("failed to diff caches: %w", err)
+	}
+	return string(output), nil
+}
+
+// RunCacheMemoryPrune deletes stale caches of a workflow's cache-memory
+func RunCacheMemoryPrune(config CacheMemoryCommandConfig, olderThan string, keep int, dryRun bool) error {
+	if olderThan == "" && keep <= 0 {
+		return fmt.Errorf("specify --older-than and/or --keep")
+	}
+	var cutoff time.Time
+	if olderThan != "" {
+		resolved, err := workflow.ResolveRelativeDate("-"+strings.TrimPrefix(olderThan, "-"), time.Now())
+		if err != nil {
+			return fmt.Errorf("invalid --older-than '%s': %w", olderThan, err)
+		}
+		if cutoff, err = time.Parse(time.RFC3339, resolved); err != nil {
+			return fmt.Errorf("invalid --older-than '%s': use a period like 3d or 1w", olderThan)
+		}
+	}
+
+	w, err := openWorkflowCacheMemory(config)
+	if err != nil {
+		return err
+	}
+	entries, err := w.store.list(w.prefix)
+	if err != nil {
+		return err
+	}
+	stale := selectCachesToPrune(entries, cutoff, keep)
+	if len(stale) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Nothing to prune for cache-memory %s", w.cache.ID)))
+		return nil
+	}
+	for _, entry := range stale {
+		fmt.Fprintln(os.Stderr, console.FormatListItem(fmt.Sprintf("%s (%s, last accessed %s)", entry.Key, console.FormatFileSize(entry.Size), entry.LastAccessedAt.Format("2006-01-02 15:04"))))
+	}
+	if dryRun {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Would delete %d of %d cache(s)", len(stale), len(entries))))
+		return nil
+	}
+	for _, entry := range stale {
+		if err := w.store.delete(entry); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Deleted %d of %d cache(s)", len(stale), len(entries))))
+	return nil
+}
+
+// selectCachesToPrune returns the caches, sorted newest first, that were last accessed before
+// cutoff (when set) or that are beyond the newest keep caches (when positive)
+func selectCachesToPrune(entries []cacheEntry, cutoff time.Time, keep int) []cacheEntry {
+	var stale []cacheEntry
+	for i, entry := range entries {
+		tooOld := !cutoff.IsZero() && entry.LastAccessedAt.Before(cutoff)
+		if tooOld || (keep > 0 && i >= keep) {
+			stale = append(stale, entry)
+		}
+	}
+	return stale
+}
+
+// RunCacheMemoryMigrate copies the content of a cache into a repo-memory branch
+func RunCacheMemoryMigrate(config CacheMemoryCommandConfig, key, to string, force bool) error {
+	w, err := openWorkflowCacheMemory(config)
+	if err != nil {
+		return err
+	}
+	memory, configured, err := migrationRepoMemory(w.data, w.cache, to)
+	if err != nil {
+		return err
+	}
+
+	entries, err := w.store.list(w.prefix)
+	if err != nil {
+		return err
+	}
+	entry, err := w.resolve(entries, key)
+	if err != nil {
+		return err
+	}
+	dir, err := w.fetch(entry)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	files, err := collectMemoryDirFiles(dir, memory)
+	if err != nil {
+		return fmt.Errorf("cache %s does not fit repo-memory %s: %w", entry.Key, memory.ID, err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("cache %s has no files to migrate", entry.Key)
+	}
+
+	store, err := openMemoryStore(memory, memoryRepo(MemoryConfig{Repo: config.Repo}, memory), config.Verbose)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	if store.exists() && !force {
+		existing, err := store.files(store.ref)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			return fmt.Errorf("repo-memory %s already has %d file(s) on %s; use --force to replace them", memory.ID, len(existing), store.branch)
+		}
+	}
+
+	commit, err := store.replaceFiles(dir, "Migrate cache-memory "+entry.Key)
+	if err != nil {
+		return err
+	}
+	if commit != "" {
+		if err := store.update(commit); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Migrated %d file(s) from cache %s to %s", len(files), entry.Key, store.branch)))
+
+	if !configured {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Add the repo-memory to the workflow frontmatter and remove cache-memory when ready:"))
+		fmt.Fprintln(os.Stderr, repoMemoryFrontmatterSnippet(memory))
+	}
+	return nil
+}
+
+// migrationRepoMemory selects the repo-memory a cache migrates into. It reports whether the memory
+// is configured by the workflow; otherwise a default memory named after the cache is returned.
+func migrationRepoMemory(data *workflow.WorkflowData, cache workflow.CacheMemoryEntry, to string) (workflow.RepoMemoryEntry, bool, error) {
+	if data.RepoMemoryConfig != nil && len(data.RepoMemoryConfig.Memories) > 0 {
+		memory, err := selectRepoMemory(data.RepoMemoryConfig.Memories, to)
+		if err == nil {
+			return memory, true, nil
+		}
+		if to == "" {
+			return workflow.RepoMemoryEntry{}, false, err
+		}
+	}
+
+	id := to
+	if id == "" {
+		id = cache.ID
+	}
+	// Defaults of a repo-memory entry without limits (see extractRepoMemoryConfig)
+	return workflow.RepoMemoryEntry{
+		ID:           id,
+		BranchName:   "memory/" + id,
+		MaxFileSize:  10240,
+		MaxFileCount: 100,
+		Description:  cache.Description,
+		CreateOrphan: true,
+	}, false, nil
+}
+
+// repoMemoryFrontmatterSnippet returns the tools frontmatter configuring a repo-memory
+func repoMemoryFrontmatterSnippet(memory workflow.RepoMemoryEntry) string {
+	var b strings.Builder
+	b.WriteString("tools:\n")
+	if memory.ID == "default" {
+		b.WriteString("  repo-memory: true\n")
+		return b.String()
+	}
+	b.WriteString("  repo-memory:\n")
+	fmt.Fprintf(&b, "    - id: %s\n", memory.ID)
+	if memory.Description != "" {
+		fmt.Fprintf(&b, "      description: %q\n", memory.Description)
+	}
+	return b.String()
+}
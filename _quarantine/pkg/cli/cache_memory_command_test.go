@@ -0,0 +1,136 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheRunID(t *testing.T) {
+	assert.Equal(t, int64(12345), cacheRunID("memory-Daily Report-12345", "memory-Daily Report-"))
+	assert.Equal(t, int64(0), cacheRunID("memory-Daily Report-latest", "memory-Daily Report-"))
+	assert.Equal(t, int64(0), cacheRunID("other-12345", "memory-Daily Report-"))
+}
+
+func TestSelectCacheMemory(t *testing.T) {
+	cache, err := selectCacheMemory([]workflow.CacheMemoryEntry{{ID: "default"}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "default", cache.ID)
+
+	multiple := []workflow.CacheMemoryEntry{{ID: "notes"}, {ID: "logs"}}
+	_, err = selectCacheMemory(multiple, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "select one with --id: notes, logs")
+
+	_, err = selectCacheMemory(multiple, "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cache-memory 'missing' not found")
+}
+
+func TestSelectCachesToPrune(t *testing.T) {
+	now := time.Now()
+	entries := []cacheEntry{
+		{Key: "memory-3", LastAccessedAt: now},
+		{Key: "memory-2", LastAccessedAt: now.AddDate(0, 0, -1)},
+		{Key: "memory-1", LastAccessedAt: now.AddDate(0, 0, -10)},
+	}
+
+	keys := func(entries []cacheEntry) []string {
+		var keys []string
+		for _, entry := range entries {
+			keys = append(keys, entry.Key)
+		}
+		return keys
+	}
+	assert.Equal(t, []string{"memory-1"}, keys(selectCachesToPrune(entries, now.AddDate(0, 0, -7), 0)))
+	assert.Equal(t, []string{"memory-2", "memory-1"}, keys(selectCachesToPrune(entries, time.Time{}, 1)))
+	assert.Empty(t, selectCachesToPrune(entries, now.AddDate(0, 0, -30), 5))
+}
+
+func TestMigrationRepoMemory(t *testing.T) {
+	cache := workflow.CacheMemoryEntry{ID: "notes", Description: "Research notes"}
+
+	memory, configured, err := migrationRepoMemory(&workflow.WorkflowData{}, cache, "")
+	require.NoError(t, err)
+	assert.False(t, configured)
+	assert.Equal(t, "notes", memory.ID)
+	assert.Equal(t, "memory/notes", memory.BranchName)
+	assert.Contains(t, repoMemoryFrontmatterSnippet(memory), "- id: notes")
+
+	data := &workflow.WorkflowData{RepoMemoryConfig: &workflow.RepoMemoryConfig{Memories: []workflow.RepoMemoryEntry{{ID: "default", BranchName: "memory/default"}}}}
+	memory, configured, err = migrationRepoMemory(data, cache, "")
+	require.NoError(t, err)
+	assert.True(t, configured)
+	assert.Equal(t, "default", memory.ID)
+}
+
+func TestRunCacheMemoryCommands(t *testing.T) {
+	setupMemoryRepo(t)
+	workflowsDir := filepath.Join(".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "report.md"), []byte("---\nname: Report\non: workflow_dispatch\nengine: claude\ntools:\n  cache-memory:\n    - id: notes\n      key: notes-${{ github.workflow }}\n---\n# Report\n"), 0644))
+
+	cacheDir := testutil.TempDir(t, "caches-*")
+	writeCache := func(key string, age time.Duration, files map[string]string) {
+		t.Helper()
+		dir := filepath.Join(cacheDir, key)
+		for name, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+		}
+		modified := time.Now().Add(-age)
+		require.NoError(t, os.Chtimes(dir, modified, modified))
+	}
+	writeCache("notes-Report-100", 10*24*time.Hour, map[string]string{"notes.md": "first\n"})
+	writeCache("notes-Report-200", time.Hour, map[string]string{"notes.md": "first\nsecond\n", "data/state.json": "{}"})
+	writeCache("memory-other-300", time.Hour, map[string]string{"x.md": "x"})
+
+	config := CacheMemoryCommandConfig{WorkflowID: "report", CacheDir: cacheDir}
+	w, err := openWorkflowCacheMemory(config)
+	require.NoError(t, err)
+	assert.Equal(t, "notes-Report-", w.prefix)
+	entries, err := w.store.list(w.prefix)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "notes-Report-200", entries[0].Key, "newest cache first")
+
+	require.NoError(t, RunCacheMemoryList(config, true))
+
+	output := filepath.Join(cacheDir, "out")
+	require.NoError(t, RunCacheMemoryDownload(config, "100", output))
+	content, err := os.ReadFile(filepath.Join(output, "notes.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "first\n", string(content))
+
+	require.NoError(t, RunCacheMemoryDiff(config, "100", "", true))
+	err = RunCacheMemoryDiff(config, "999", "", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no cache with key or run ID '999'")
+
+	require.NoError(t, RunCacheMemoryMigrate(config, "", "", false))
+	store, err := openMemoryStore(workflow.RepoMemoryEntry{ID: "notes", BranchName: "memory/notes"}, "", false)
+	require.NoError(t, err)
+	files, err := store.files(store.ref)
+	require.NoError(t, err)
+	store.Close()
+	assert.Len(t, files, 2)
+	err = RunCacheMemoryMigrate(config, "100", "", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use --force to replace them")
+
+	require.Error(t, RunCacheMemoryPrune(config, "", 0, false), "a prune criterion is required")
+	require.NoError(t, RunCacheMemoryPrune(config, "7d", 0, true))
+	assert.DirExists(t, filepath.Join(cacheDir, "notes-Report-100"), "dry run keeps caches")
+	require.NoError(t, RunCacheMemoryPrune(config, "7d", 0, false))
+	assert.NoDirExists(t, filepath.Join(cacheDir, "notes-Report-100"))
+	assert.DirExists(t, filepath.Join(cacheDir, "notes-Report-200"))
+	assert.DirExists(t, filepath.Join(cacheDir, "memory-other-300"))
+}
@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var cacheMemoryStoreLog = logger.New("cli:cache_memory_store")
+
+// cacheEntry is a cache saved by a workflow run
+type cacheEntry struct {
+	ID             int64     `json:"id,omitempty"`
+	Key            string    `json:"key"`
+	Ref            string    `json:"ref,omitempty"`
+	Size           int64     `json:"size_in_bytes"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastAccessedAt time.Time `json:"last_accessed_at"`
+}
+
+// cacheStore lists, downloads and deletes the caches of a repository
+type cacheStore interface {
+	// list returns the caches whose key starts with prefix, newest first
+	list(prefix string) ([]cacheEntry, error)
+	// download unpacks the content of a cache into dir
+	download(entry cacheEntry, artifactNames []string, dir string) error
+	// delete removes a cache
+	delete(entry cacheEntry) error
+}
+
+// newCacheStore returns the local stand-in store when localDir is set, the GitHub Actions cache otherwise
+func newCacheStore(repo, localDir string, verbose bool) cacheStore {
+	if localDir != "" {
+		return &localCacheStore{dir: localDir}
+	}
+	return &githubCacheStore{repo: repo, verbose: verbose}
+}
+
+// sortCacheEntries sorts caches newest first
+func sortCacheEntries(entries []cacheEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+}
+
+// cacheRunID extracts the workflow run ID that saved a cache from its key, 0 when the key has none
+func cacheRunID(key, prefix string) int64 {
+	runID, err := strconv.ParseInt(strings.TrimPrefix(key, prefix), 10, 64)
+	if err != nil || !strings.HasPrefix(key, prefix) {
+		return 0
+	}
+	return runID
+}
+
+// githubCacheStore uses the GitHub Actions cache API. The API lists and deletes caches but does
+// not serve their archives, so content is downloaded from the cache-memory artifact that the
+// run saving the cache uploaded (the run ID is the last part of every cache-memory key).
+type githubCacheStore struct {
+	repo    string // owner/repo, "" for the current repository
+	verbose bool
+	prefix  string // key prefix of the last list, used to find the run of a cache
+}
+
+// repoPath returns the API path of the repository
+func (s *githubCacheStore) repoPath() string {
+	if s.repo == "" {
+		return "repos/{owner}/{repo}"
+	}
+	return "repos/" + s.repo
+}
+
+func (s *githubCacheStore) list(prefix string) ([]cacheEntry, error) {
+	s.prefix = prefix
+	endpoint := fmt.Sprintf("%s/actions/caches?per_page=100&key=%s", s.repoPath(), url.QueryEscape(prefix))
+	cacheMemoryStoreLog.Printf("Listing caches: %s", endpoint)
+	output, err := workflow.RunGH("Listing caches...", "api", "--paginate", endpoint, "--jq", ".actions_caches[]")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list caches: %w", err)
+	}
+
+	var entries []cacheEntry
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var entry cacheEntry
+		if err := decoder.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse caches: %w", err)
+		}
+		// The key filter is a prefix match on the API side; keep the check for safety
+		if strings.HasPrefix(entry.Key, prefix) {
+			entries = append(entries, entry)
+		}
+	}
+	sortCacheEntries(entries)
+	return entries, nil
+}
+
+func (s *githubCacheStore) download(entry cacheEntry, artifactNames []string, dir string) error {
+	runID := cacheRunID(entry.Key, s.prefix)
+	if runID == 0 {
+		return fmt.Errorf("cache %s was not saved by a workflow run with a cache-memory artifact; the Actions cache API does not serve cache content", entry.Key)
+	}
+
+	var lastErr error
+	for _, name := range artifactNames {
+		args := []string{"run", "download", strconv.FormatInt(runID, 10), "--name", name, "--dir", dir}
+		if s.repo != "" {
+			args = append(args, "--repo", s.repo)
+		}
+		output, err := workflow.RunGHCombined("Downloading cache content...", args...)
+		if err == nil {
+			return nil
+		}
+		cacheMemoryStoreLog.Printf("Artifact %s of run %d unavailable: %v", name, runID, err)
+		lastErr = fmt.Errorf("%w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return fmt.Errorf("cannot download cache %s: the Actions cache API does not serve cache content and run %d has no cache-memory artifact "+
+		"(it is uploaded when threat detection is enabled, and expires with the run artifacts): %w", entry.Key, runID, lastErr)
+}
+
+func (s *githubCacheStore) delete(entry cacheEntry) error {
+	endpoint := fmt.Sprintf("%s/actions/caches/%d", s.repoPath(), entry.ID)
+	if _, err := workflow.RunGH("Deleting cache...", "api", "--method", "DELETE", endpoint); err != nil {
+		return fmt.Errorf("failed to delete cache %s: %w", entry.Key, err)
+	}
+	return nil
+}
+
+// localCacheStore is a stand-in for the Actions cache backed by a directory holding one
+// subdirectory per cache key, e.g. for workflows run locally or in tests
+type localCacheStore struct {
+	dir string
+}
+
+func (s *localCacheStore) list(prefix string) ([]cacheEntry, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	var entries []cacheEntry
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() || !strings.HasPrefix(dirEntry.Name(), prefix) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			return nil, err
+		}
+		size, err := dirSize(filepath.Join(s.dir, dirEntry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, cacheEntry{Key: dirEntry.Name(), Size: size, CreatedAt: info.ModTime(), LastAccessedAt: info.ModTime()})
+	}
+	sortCacheEntries(entries)
+	return entries, nil
+}
+
+func (s *localCacheStore) download(entry cacheEntry, _ []string, dir string) error {
+	return copyDirTree(filepath.Join(s.dir, entry.Key), dir)
+}
+
+func (s *localCacheStore) delete(entry cacheEntry) error {
+	return os.RemoveAll(filepath.Join(s.dir, entry.Key))
+}
+
+// dirSize returns the total size of the regular files below a directory
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// copyDirTree copies the regular files below src into dst
+func copyDirTree(src, dst string) error {
+	return filepath.WalkDir(src, func(filePath string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, filePath)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, 0644)
+	})
+}
@@ -0,0 +1,402 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/goccy/go-yaml"
+)
+
+var userCodemodLog = logger.New("cli:codemod_user")
+
+// userCodemodsDir is the repository directory holding declarative codemods for the fix command
+var userCodemodsDir = filepath.Join(".github", "aw", "codemods")
+
+// UserCodemodFile is a declarative codemod loaded from .github/aw/codemods/<id>.yml.
+//
+// Paths are JSON paths into the frontmatter, written either as JSON pointers
+// ("/tools/github") or dotted paths ("$.tools.github").
+//
+//	id: house-timeout
+//	name: Always set timeout-minutes
+//	match:
+//	  path: /on
+//	  conditions:
+//	    - path: /timeout-minutes
+//	      exists: false
+//	actions:
+//	  - set: {path: /timeout-minutes, value: 20}
+type UserCodemodFile struct {
+	ID          string              `yaml:"id"`
+	Name        string              `yaml:"name"`
+	Description string              `yaml:"description"`
+	Match       UserCodemodMatch    `yaml:"match"`
+	Actions     []UserCodemodAction `yaml:"actions"`
+}
+
+// UserCodemodMatch selects the workflows a declarative codemod applies to. The codemod
+// applies when Path (if set) exists and all conditions hold.
+type UserCodemodMatch struct {
+	Path       string                 `yaml:"path"`
+	Conditions []UserCodemodCondition `yaml:"conditions"`
+}
+
+// UserCodemodCondition is a check on the frontmatter value at a path
+type UserCodemodCondition struct {
+	Path    string `yaml:"path"`
+	Exists  *bool  `yaml:"exists"`
+	Equals  any    `yaml:"equals"`
+	Matches string `yaml:"matches"`
+}
+
+// UserCodemodAction is a single edit. Exactly one of the fields must be set.
+type UserCodemodAction struct {
+	Rename *struct {
+		Path string `yaml:"path"`
+		To   string `yaml:"to"`
+	} `yaml:"rename"`
+	Move *struct {
+		From string `yaml:"from"`
+		To   string `yaml:"to"`
+	} `yaml:"move"`
+	Set *struct {
+		Path  string `yaml:"path"`
+		Value any    `yaml:"value"`
+	} `yaml:"set"`
+	Delete *struct {
+		Path string `yaml:"path"`
+	} `yaml:"delete"`
+	Wrap *struct {
+		Path string `yaml:"path"`
+		Key  string `yaml:"key"`
+	} `yaml:"wrap"`
+}
+
+// userCodemodCondition is a parsed condition
+type userCodemodCondition struct {
+	path    []string
+	exists  *bool
+	equals  any
+	hasEq   bool
+	matches *regexp.Regexp
+}
+
+// userCodemodEdit applies one parsed action to a frontmatter document
+type userCodemodEdit func(doc *yamlDocument, frontmatter map[string]any) ([]string, bool, error)
+
+// loadUserCodemods loads the declarative codemods of a directory, sorted by ID. A missing
+// directory yields no codemods. IDs must not collide with built-in codemods.
+func loadUserCodemods(dir string) ([]Codemod, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read codemods directory %s: %w", dir, err)
+	}
+
+	builtin := make(map[string]bool)
+	for _, codemod := range GetAllCodemods() {
+		builtin[codemod.ID] = true
+	}
+
+	var codemods []Codemod
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yml" && ext != ".yaml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		codemod, err := loadUserCodemodFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if builtin[codemod.ID] {
+			return nil, fmt.Errorf("%s: codemod id '%s' is already used by a built-in codemod", path, codemod.ID)
+		}
+		if other, ok := seen[codemod.ID]; ok {
+			return nil, fmt.Errorf("%s: codemod id '%s' is already defined in %s", path, codemod.ID, other)
+		}
+		seen[codemod.ID] = path
+		codemods = append(codemods, codemod)
+	}
+
+	sort.Slice(codemods, func(i, j int) bool { return codemods[i].ID < codemods[j].ID })
+	userCodemodLog.Printf("Loaded %d user codemods from %s", len(codemods), dir)
+	return codemods, nil
+}
+
+// loadUserCodemodFile parses and validates a declarative codemod file
+func loadUserCodemodFile(path string) (Codemod, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Codemod{}, fmt.Errorf("failed to read codemod %s: %w", path, err)
+	}
+	var file UserCodemodFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Codemod{}, fmt.Errorf("failed to parse codemod %s: %w", path, err)
+	}
+	if file.ID == "" {
+		file.ID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	codemod, err := buildUserCodemod(file)
+	if err != nil {
+		return Codemod{}, fmt.Errorf("invalid codemod %s: %w", path, err)
+	}
+	codemod.Source = path
+	return codemod, nil
+}
+
+// buildUserCodemod converts a declarative codemod into a Codemod
+func buildUserCodemod(file UserCodemodFile) (Codemod, error) {
+	if len(file.Actions) == 0 {
+		return Codemod{}, errors.New("at least one action is required")
+	}
+
+	var matchPath []string
+	if file.Match.Path != "" {
+		path, err := parseCodemodPath(file.Match.Path)
+		if err != nil {
+			return Codemod{}, fmt.Errorf("match.path: %w", err)
+		}
+		matchPath = path
+	}
+
+	conditions := make([]userCodemodCondition, 0, len(file.Match.Conditions))
+	for i, c := range file.Match.Conditions {
+		condition, err := parseUserCodemodCondition(c)
+		if err != nil {
+			return Codemod{}, fmt.Errorf("match.conditions[%d]: %w", i, err)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	edits := make([]userCodemodEdit, 0, len(file.Actions))
+	for i, action := range file.Actions {
+		edit, err := parseUserCodemodAction(action)
+		if err != nil {
+			return Codemod{}, fmt.Errorf("actions[%d]: %w", i, err)
+		}
+		edits = append(edits, edit)
+	}
+
+	name := file.Name
+	if name == "" {
+		name = file.ID
+	}
+	return Codemod{
+		ID:          file.ID,
+		Name:        name,
+		Description: file.Description,
+		Apply: func(content string, frontmatter map[string]any) (string, bool, error) {
+			if matchPath != nil {
+				if _, ok := lookupFrontmatterPath(frontmatter, matchPath); !ok {
+					return content, false, nil
+				}
+			}
+			for _, condition := range conditions {
+				if !condition.holds(frontmatter) {
+					return content, false, nil
+				}
+			}
+
+			frontmatterLines, markdown, err := parseFrontmatterLines(content)
+			if err != nil {
+				return content, false, err
+			}
+			var modified bool
+			for _, edit := range edits {
+				lines, changed, err := edit(parseYAMLDocument(frontmatterLines), frontmatter)
+				if err != nil {
+					return content, false, err
+				}
+				if changed {
+					frontmatterLines = lines
+					modified = true
+				}
+			}
+			if !modified {
+				return content, false, nil
+			}
+			updated := reconstructContent(frontmatterLines, markdown)
+			if strings.HasSuffix(content, "\n") && !strings.HasSuffix(updated, "\n") {
+				updated += "\n"
+			}
+			return updated, true, nil
+		},
+	}, nil
+}
+
+func parseUserCodemodCondition(c UserCodemodCondition) (userCodemodCondition, error) {
+	path, err := parseCodemodPath(c.Path)
+	if err != nil {
+		return userCodemodCondition{}, err
+	}
+	condition := userCodemodCondition{path: path, exists: c.Exists, equals: c.Equals, hasEq: c.Equals != nil}
+	if c.Matches != "" {
+		re, err := regexp.Compile(c.Matches)
+		if err != nil {
+			return userCodemodCondition{}, fmt.Errorf("invalid matches pattern: %w", err)
+		}
+		condition.matches = re
+	}
+	if condition.exists == nil && !condition.hasEq && condition.matches == nil {
+		return userCodemodCondition{}, errors.New("one of exists, equals or matches is required")
+	}
+	return condition, nil
+}
+
+// holds evaluates the condition against the frontmatter
+func (c userCodemodCondition) holds(frontmatter map[string]any) bool {
+	value, ok := lookupFrontmatterPath(frontmatter, c.path)
+	if c.exists != nil && ok != *c.exists {
+		return false
+	}
+	if c.hasEq && (!ok || !codemodValuesEqual(value, c.equals)) {
+		return false
+	}
+	if c.matches != nil {
+		if !ok {
+			return false
+		}
+		switch value.(type) {
+		case map[string]any, []any:
+			return false
+		}
+		if !c.matches.MatchString(fmt.Sprint(value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseUserCodemodAction(action UserCodemodAction) (userCodemodEdit, error) {
+	var kinds []string
+	var edit userCodemodEdit
+	var err error
+
+	if a := action.Rename; a != nil {
+		kinds = append(kinds, "rename")
+		var path []string
+		if path, err = parseCodemodPath(a.Path); err == nil {
+			if a.To == "" || strings.Contains(a.To, "/") {
+				err = errors.New("rename.to must be a key name")
+			}
+			edit = func(doc *yamlDocument, _ map[string]any) ([]string, bool, error) {
+				return doc.renameEntry(path, a.To)
+			}
+		}
+	}
+	if a := action.Move; a != nil {
+		kinds = append(kinds, "move")
+		var from, to []string
+		if from, err = parseCodemodPath(a.From); err == nil {
+			to, err = parseCodemodPath(a.To)
+		}
+		edit = func(doc *yamlDocument, _ map[string]any) ([]string, bool, error) {
+			return doc.moveEntry(from, to)
+		}
+	}
+	if a := action.Set; a != nil {
+		kinds = append(kinds, "set")
+		var path []string
+		var value yamlFragment
+		if path, err = parseCodemodPath(a.Path); err == nil {
+			value, err = valueFragment(a.Value)
+		}
+		edit = func(doc *yamlDocument, frontmatter map[string]any) ([]string, bool, error) {
+			if current, ok := lookupFrontmatterPath(frontmatter, path); ok && codemodValuesEqual(current, a.Value) {
+				return doc.Lines, false, nil
+			}
+			lines, err := doc.setEntry(path, value)
+			return lines, err == nil, err
+		}
+	}
+	if a := action.Delete; a != nil {
+		kinds = append(kinds, "delete")
+		var path []string
+		path, err = parseCodemodPath(a.Path)
+		edit = func(doc *yamlDocument, _ map[string]any) ([]string, bool, error) {
+			return doc.deleteEntry(path)
+		}
+	}
+	if a := action.Wrap; a != nil {
+		kinds = append(kinds, "wrap")
+		var path []string
+		if path, err = parseCodemodPath(a.Path); err == nil && a.Key == "" {
+			err = errors.New("wrap.key is required")
+		}
+		edit = func(doc *yamlDocument, _ map[string]any) ([]string, bool, error) {
+			return doc.wrapEntry(path, a.Key)
+		}
+	}
+
+	if len(kinds) != 1 {
+		return nil, fmt.Errorf("exactly one of rename, move, set, delete or wrap is required, got %d", len(kinds))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", kinds[0], err)
+	}
+	return edit, nil
+}
+
+// parseCodemodPath parses a JSON pointer ("/tools/github") or dotted JSON path
+// ("$.tools.github") into key segments
+func parseCodemodPath(path string) ([]string, error) {
+	var segments []string
+	switch {
+	case strings.HasPrefix(path, "/"):
+		for _, segment := range strings.Split(path[1:], "/") {
+			segments = append(segments, strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~"))
+		}
+	case strings.HasPrefix(path, "$."):
+		segments = strings.Split(path[2:], ".")
+	default:
+		return nil, fmt.Errorf("invalid path '%s': use a JSON pointer like /tools/github or $.tools.github", path)
+	}
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, fmt.Errorf("invalid path '%s': empty segment", path)
+		}
+	}
+	return segments, nil
+}
+
+// formatCodemodPath formats key segments as a JSON pointer
+func formatCodemodPath(path []string) string {
+	if len(path) == 0 {
+		return "/"
+	}
+	return "/" + strings.Join(path, "/")
+}
+
+// lookupFrontmatterPath returns the frontmatter value at a path
+func lookupFrontmatterPath(frontmatter map[string]any, path []string) (any, bool) {
+	var current any = frontmatter
+	for _, segment := range path {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[segment]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// codemodValuesEqual compares YAML values independent of the numeric types produced by parsing
+func codemodValuesEqual(a, b any) bool {
+	left, err1 := json.Marshal(a)
+	right, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && string(left) == string(right)
+}
@@ -0,0 +1,150 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestUserCodemod(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func applyTestCodemod(t *testing.T, codemod Codemod, content string) (string, bool) {
+	t.Helper()
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	require.NoError(t, err)
+	updated, applied, err := codemod.Apply(content, result.Frontmatter)
+	require.NoError(t, err)
+	return updated, applied
+}
+
+func TestLoadUserCodemods(t *testing.T) {
+	dir := filepath.Join(testutil.TempDir(t, "test-*"), "codemods")
+	writeTestUserCodemod(t, dir, "house-timeout.yml", `name: Always set timeout-minutes
+description: House convention
+match:
+  path: /on
+  conditions:
+    - path: /timeout-minutes
+      exists: false
+    - path: $.engine
+      equals: claude
+actions:
+  - set: {path: /timeout-minutes, value: 20}
+`)
+	writeTestUserCodemod(t, dir, "internal-mcp.yaml", `id: internal-mcp-rename
+match:
+  conditions:
+    - path: /mcp-servers/legacy-search/url
+      matches: "\\.internal$"
+actions:
+  - rename: {path: /mcp-servers/legacy-search, to: search}
+`)
+	writeTestUserCodemod(t, dir, "README.md", "ignored")
+
+	codemods, err := loadUserCodemods(dir)
+	require.NoError(t, err)
+	require.Len(t, codemods, 2)
+	assert.Equal(t, "house-timeout", codemods[0].ID, "the ID defaults to the file name")
+	assert.Equal(t, "Always set timeout-minutes", codemods[0].Name)
+	assert.Equal(t, filepath.Join(dir, "house-timeout.yml"), codemods[0].Source)
+	assert.Equal(t, "internal-mcp-rename", codemods[1].Name, "the name defaults to the ID")
+
+	content := `---
+on: issues
+engine: claude # engine
+mcp-servers:
+  legacy-search:
+    url: https://search.internal
+---
+
+# Test
+`
+	updated, applied := applyTestCodemod(t, codemods[0], content)
+	require.True(t, applied)
+	assert.Contains(t, updated, "engine: claude # engine\nmcp-servers:\n  legacy-search:\n    url: https://search.internal\ntimeout-minutes: 20\n---")
+	assert.Equal(t, "\n", updated[len(updated)-1:], "the trailing newline is kept")
+
+	_, applied = applyTestCodemod(t, codemods[0], updated)
+	assert.False(t, applied, "conditions no longer hold once applied")
+
+	_, applied = applyTestCodemod(t, codemods[0], "---\non: issues\nengine: codex\n---\n")
+	assert.False(t, applied, "equals condition does not hold")
+
+	updated, applied = applyTestCodemod(t, codemods[1], content)
+	require.True(t, applied)
+	assert.Contains(t, updated, "  search:\n    url: https://search.internal")
+}
+
+func TestLoadUserCodemodsMissingDir(t *testing.T) {
+	codemods, err := loadUserCodemods(filepath.Join(testutil.TempDir(t, "test-*"), "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, codemods)
+}
+
+func TestLoadUserCodemodsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "no actions", content: "id: x\n", wantErr: "at least one action is required"},
+		{name: "two kinds in one action", content: "actions:\n  - delete: {path: /a}\n    set: {path: /b, value: 1}\n", wantErr: "exactly one of rename, move, set, delete or wrap"},
+		{name: "invalid path", content: "actions:\n  - delete: {path: tools.github}\n", wantErr: "invalid path 'tools.github'"},
+		{name: "empty condition", content: "match:\n  conditions:\n    - path: /on\nactions:\n  - delete: {path: /a}\n", wantErr: "one of exists, equals or matches is required"},
+		{name: "builtin id", content: "id: timeout-minutes-migration\nactions:\n  - delete: {path: /a}\n", wantErr: "already used by a built-in codemod"},
+		{name: "wrap without key", content: "actions:\n  - wrap: {path: /engine}\n", wantErr: "wrap.key is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := testutil.TempDir(t, "test-*")
+			writeTestUserCodemod(t, dir, "codemod.yml", tt.content)
+			_, err := loadUserCodemods(dir)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSelectCodemods(t *testing.T) {
+	codemods := []Codemod{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	selected, err := selectCodemods(codemods, nil)
+	require.NoError(t, err)
+	assert.Len(t, selected, 3)
+
+	selected, err = selectCodemods(codemods, []string{"c", "a"})
+	require.NoError(t, err)
+	assert.Equal(t, []Codemod{{ID: "c"}, {ID: "a"}}, selected)
+
+	_, err = selectCodemods(codemods, []string{"d"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown codemod 'd'. Available codemods: a, b, c")
+}
+
+func TestParseCodemodPath(t *testing.T) {
+	path, err := parseCodemodPath("/safe-outputs/create-issue")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"safe-outputs", "create-issue"}, path)
+
+	path, err = parseCodemodPath("/network/a~1b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"network", "a/b"}, path)
+
+	path, err = parseCodemodPath("$.tools.github")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tools", "github"}, path)
+
+	_, err = parseCodemodPath("/tools//github")
+	require.Error(t, err)
+}
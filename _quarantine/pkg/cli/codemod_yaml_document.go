@@ -0,0 +1,390 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/goccy/go-yaml"
+)
+
+var yamlDocumentLog = logger.New("cli:codemod_yaml_document")
+
+// yamlEntry is a key of a block mapping in frontmatter lines, with the span of lines holding
+// its value. Edits replace or insert whole spans so every other line (comments, blank lines,
+// key order, quoting) is kept as written.
+type yamlEntry struct {
+	Key      string       // Unquoted key
+	RawKey   string       // Key as written in the file
+	Line     int          // Index of the key line
+	End      int          // Index after the last line of the value (trailing comments excluded)
+	Indent   int          // Indentation of the key
+	Value    string       // Inline value after the colon, without trailing comment
+	Comment  string       // Trailing comment on the key line, including '#'
+	Children []*yamlEntry // Entries of a nested block mapping
+}
+
+// isEmptyMapping reports whether the entry has no value at all (e.g. "github:")
+func (e *yamlEntry) isEmptyMapping() bool {
+	return e.Value == "" && e.End == e.Line+1
+}
+
+// isBlockMapping reports whether the entry value is a block mapping that can hold children
+func (e *yamlEntry) isBlockMapping() bool {
+	return e.Value == "" && (len(e.Children) > 0 || e.End == e.Line+1)
+}
+
+// yamlDocument is a comment- and ordering-preserving view of frontmatter lines
+type yamlDocument struct {
+	Lines  []string
+	Root   []*yamlEntry
+	indent int // indentation step of the document
+}
+
+// yamlFragment is the value of an entry: the text after the colon on the key line and the
+// following lines of a block value
+type yamlFragment struct {
+	Inline string
+	Lines  []string
+}
+
+// parseYAMLDocument builds the mapping structure of frontmatter lines
+func parseYAMLDocument(lines []string) *yamlDocument {
+	doc := &yamlDocument{Lines: lines, indent: 2}
+	doc.Root = parseYAMLEntries(lines, 0, len(lines))
+
+	// Use the indentation of the first nested entry as the step for new entries
+	for _, entry := range doc.Root {
+		if len(entry.Children) > 0 {
+			doc.indent = entry.Children[0].Indent - entry.Indent
+			break
+		}
+	}
+	return doc
+}
+
+// parseYAMLEntries parses the block mapping entries between start and end
+func parseYAMLEntries(lines []string, start, end int) []*yamlEntry {
+	var entries []*yamlEntry
+	var current *yamlEntry
+	indent := -1
+	lastSignificant := start - 1
+
+	closeCurrent := func() {
+		if current == nil {
+			return
+		}
+		current.End = max(lastSignificant+1, current.Line+1)
+		if current.Value == "" && current.End > current.Line+1 {
+			current.Children = parseYAMLEntries(lines, current.Line+1, current.End)
+		}
+		entries = append(entries, current)
+	}
+
+	for i := start; i < end; i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		lineIndent := len(lines[i]) - len(strings.TrimLeft(lines[i], " "))
+		if indent < 0 {
+			indent = lineIndent
+		}
+		if lineIndent == indent {
+			if rawKey, rest, ok := splitYAMLKeyLine(trimmed); ok {
+				closeCurrent()
+				value, comment := splitYAMLComment(rest)
+				current = &yamlEntry{
+					Key:     unquoteYAMLKey(rawKey),
+					RawKey:  rawKey,
+					Line:    i,
+					Indent:  lineIndent,
+					Value:   value,
+					Comment: comment,
+				}
+			} else if current == nil {
+				// Not a mapping (e.g. a sequence), nothing to index
+				return nil
+			}
+		}
+		lastSignificant = i
+	}
+	closeCurrent()
+	return entries
+}
+
+// splitYAMLKeyLine splits "key: rest" into the raw key and the text after the colon
+func splitYAMLKeyLine(trimmed string) (string, string, bool) {
+	if strings.HasPrefix(trimmed, "- ") || trimmed == "-" || strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return "", "", false
+	}
+
+	keyEnd := -1
+	if trimmed[0] == '"' || trimmed[0] == '\'' {
+		closing := strings.IndexByte(trimmed[1:], trimmed[0])
+		if closing < 0 {
+			return "", "", false
+		}
+		keyEnd = closing + 2
+		if keyEnd >= len(trimmed) || trimmed[keyEnd] != ':' {
+			return "", "", false
+		}
+	} else {
+		for i := 0; i < len(trimmed); i++ {
+			if trimmed[i] == ':' && (i+1 == len(trimmed) || trimmed[i+1] == ' ') {
+				keyEnd = i
+				break
+			}
+		}
+		if keyEnd <= 0 {
+			return "", "", false
+		}
+	}
+	return trimmed[:keyEnd], trimmed[keyEnd+1:], true
+}
+
+// splitYAMLComment splits an inline value from its trailing comment, ignoring '#' in quotes
+func splitYAMLComment(rest string) (string, string) {
+	var quote byte
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || rest[i-1] == ' ' || rest[i-1] == '\t'):
+			return strings.TrimSpace(rest[:i]), strings.TrimSpace(rest[i:])
+		}
+	}
+	return strings.TrimSpace(rest), ""
+}
+
+func unquoteYAMLKey(rawKey string) string {
+	if len(rawKey) >= 2 && (rawKey[0] == '"' || rawKey[0] == '\'') {
+		if rawKey[0] == '"' {
+			if unquoted, err := strconv.Unquote(rawKey); err == nil {
+				return unquoted
+			}
+		}
+		return rawKey[1 : len(rawKey)-1]
+	}
+	return rawKey
+}
+
+// formatYAMLKey quotes a key when it cannot be written as a plain scalar
+func formatYAMLKey(key string) string {
+	if key == "" || strings.ContainsAny(key, ":#{}[],&*!|>'\"%@`") || strings.HasPrefix(key, "-") || strings.TrimSpace(key) != key {
+		return strconv.Quote(key)
+	}
+	return key
+}
+
+// lookup returns the entry at a path, or nil when it does not exist. An error is
+// returned when the path goes through a value that is not a block mapping.
+func (d *yamlDocument) lookup(path []string) (*yamlEntry, error) {
+	entries := d.Root
+	var entry *yamlEntry
+	for i, segment := range path {
+		if entry != nil && !entry.isBlockMapping() {
+			return nil, fmt.Errorf("cannot edit '%s': '%s' is not a block mapping", formatCodemodPath(path), formatCodemodPath(path[:i]))
+		}
+		entry = nil
+		for _, candidate := range entries {
+			if candidate.Key == segment {
+				entry = candidate
+				break
+			}
+		}
+		if entry == nil {
+			return nil, nil
+		}
+		entries = entry.Children
+	}
+	return entry, nil
+}
+
+// fragment returns the value of an entry
+func (d *yamlDocument) fragment(entry *yamlEntry) yamlFragment {
+	line := d.Lines[entry.Line]
+	rest := strings.TrimLeft(line, " ")[len(entry.RawKey)+1:]
+	return yamlFragment{
+		Inline: strings.TrimSpace(rest),
+		Lines:  append([]string(nil), d.Lines[entry.Line+1:entry.End]...),
+	}
+}
+
+// renderEntry writes a key and its value at an indentation
+func (d *yamlDocument) renderEntry(indent int, rawKey string, value yamlFragment) []string {
+	first := strings.Repeat(" ", indent) + rawKey + ":"
+	if value.Inline != "" {
+		first += " " + value.Inline
+	}
+	return append([]string{first}, reindentYAMLLines(value.Lines, indent+d.indent)...)
+}
+
+// reindentYAMLLines shifts lines so the least indented one starts at indent
+func reindentYAMLLines(lines []string, indent int) []string {
+	base := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+		if base < 0 || lineIndent < base {
+			base = lineIndent
+		}
+	}
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			result[i] = ""
+			continue
+		}
+		result[i] = strings.Repeat(" ", indent) + line[base:]
+	}
+	return result
+}
+
+// replaceLines returns the document lines with [start, end) replaced
+func (d *yamlDocument) replaceLines(start, end int, replacement []string) []string {
+	result := make([]string, 0, len(d.Lines)-(end-start)+len(replacement))
+	result = append(result, d.Lines[:start]...)
+	result = append(result, replacement...)
+	result = append(result, d.Lines[end:]...)
+	return result
+}
+
+// valueFragment renders a value as a YAML fragment
+func valueFragment(value any) (yamlFragment, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return yamlFragment{}, fmt.Errorf("failed to render value: %w", err)
+	}
+	rendered := strings.TrimRight(string(data), "\n")
+	lines := strings.Split(rendered, "\n")
+	switch value.(type) {
+	case map[string]any, []any:
+		if rendered == "{}" || rendered == "[]" {
+			return yamlFragment{Inline: rendered}, nil
+		}
+		return yamlFragment{Lines: lines}, nil
+	}
+	if len(lines) == 1 {
+		return yamlFragment{Inline: rendered}, nil
+	}
+	return yamlFragment{Lines: lines}, nil
+}
+
+// setEntry sets the value at a path, creating missing parent mappings. The trailing comment
+// of an existing key line is kept when the new value is inline.
+func (d *yamlDocument) setEntry(path []string, value yamlFragment) ([]string, error) {
+	entry, err := d.lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return d.insertEntry(path, value)
+	}
+	if value.Inline != "" && len(value.Lines) == 0 && entry.Comment != "" {
+		value.Inline += " " + entry.Comment
+	}
+	return d.replaceLines(entry.Line, entry.End, d.renderEntry(entry.Indent, entry.RawKey, value)), nil
+}
+
+// insertEntry adds a new key after the last entry of its parent mapping
+func (d *yamlDocument) insertEntry(path []string, value yamlFragment) ([]string, error) {
+	key := path[len(path)-1]
+	parentPath := path[:len(path)-1]
+
+	if len(parentPath) == 0 {
+		insertAt := len(d.Lines)
+		if len(d.Root) > 0 {
+			insertAt = d.Root[len(d.Root)-1].End
+		}
+		return d.replaceLines(insertAt, insertAt, d.renderEntry(0, formatYAMLKey(key), value)), nil
+	}
+
+	parent, err := d.lookup(parentPath)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		// Create the missing parent mapping first, then insert into it
+		lines, err := d.insertEntry(parentPath, yamlFragment{})
+		if err != nil {
+			return nil, err
+		}
+		return parseYAMLDocument(lines).insertEntry(path, value)
+	}
+	if !parent.isBlockMapping() {
+		return nil, fmt.Errorf("cannot add '%s': '%s' is not a block mapping", formatCodemodPath(path), formatCodemodPath(parentPath))
+	}
+
+	indent := parent.Indent + d.indent
+	if len(parent.Children) > 0 {
+		indent = parent.Children[0].Indent
+	}
+	yamlDocumentLog.Printf("Inserting %s at line %d", formatCodemodPath(path), parent.End)
+	return d.replaceLines(parent.End, parent.End, d.renderEntry(indent, formatYAMLKey(key), value)), nil
+}
+
+// deleteEntry removes the entry at a path with its value
+func (d *yamlDocument) deleteEntry(path []string) ([]string, bool, error) {
+	entry, err := d.lookup(path)
+	if err != nil || entry == nil {
+		return d.Lines, false, err
+	}
+	return d.replaceLines(entry.Line, entry.End, nil), true, nil
+}
+
+// renameEntry renames the key at a path in place
+func (d *yamlDocument) renameEntry(path []string, newKey string) ([]string, bool, error) {
+	entry, err := d.lookup(path)
+	if err != nil || entry == nil || entry.Key == newKey {
+		return d.Lines, false, err
+	}
+	sibling := append(append([]string(nil), path[:len(path)-1]...), newKey)
+	if existing, _ := d.lookup(sibling); existing != nil {
+		return nil, false, fmt.Errorf("cannot rename '%s': '%s' already exists", formatCodemodPath(path), formatCodemodPath(sibling))
+	}
+
+	line := d.Lines[entry.Line]
+	renamed := line[:entry.Indent] + formatYAMLKey(newKey) + line[entry.Indent+len(entry.RawKey):]
+	return d.replaceLines(entry.Line, entry.Line+1, []string{renamed}), true, nil
+}
+
+// moveEntry moves the entry at from to the path to, keeping the comments of its value
+func (d *yamlDocument) moveEntry(from, to []string) ([]string, bool, error) {
+	entry, err := d.lookup(from)
+	if err != nil || entry == nil {
+		return d.Lines, false, err
+	}
+	if existing, err := d.lookup(to); err != nil {
+		return nil, false, err
+	} else if existing != nil {
+		return nil, false, fmt.Errorf("cannot move '%s': '%s' already exists", formatCodemodPath(from), formatCodemodPath(to))
+	}
+
+	value := d.fragment(entry)
+	lines := d.replaceLines(entry.Line, entry.End, nil)
+	lines, err = parseYAMLDocument(lines).insertEntry(to, value)
+	if err != nil {
+		return nil, false, err
+	}
+	return lines, true, nil
+}
+
+// wrapEntry nests the value at a path under a new key (e.g. "engine: claude" becomes
+// "engine:\n  id: claude")
+func (d *yamlDocument) wrapEntry(path []string, key string) ([]string, bool, error) {
+	entry, err := d.lookup(path)
+	if err != nil || entry == nil {
+		return d.Lines, false, err
+	}
+	inner := d.renderEntry(0, formatYAMLKey(key), d.fragment(entry))
+	return d.replaceLines(entry.Line, entry.End, d.renderEntry(entry.Indent, entry.RawKey, yamlFragment{Lines: inner})), true, nil
+}
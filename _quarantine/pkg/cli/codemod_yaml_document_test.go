@@ -0,0 +1,191 @@
+//go:build !integration
+
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testYAMLDocument = `# Workflow settings
+on: issues # trigger
+engine: claude
+"quoted-key": value
+mcp-servers:
+  legacy:
+    # internal server
+    url: https://example.com # endpoint
+    allowed: ["*"]
+
+tools:
+  github:
+  bash:
+    - "echo #1"
+labels: [a, b]`
+
+func parseTestYAMLDocument() *yamlDocument {
+	return parseYAMLDocument(strings.Split(testYAMLDocument, "\n"))
+}
+
+func TestParseYAMLDocument(t *testing.T) {
+	doc := parseTestYAMLDocument()
+
+	var keys []string
+	for _, entry := range doc.Root {
+		keys = append(keys, entry.Key)
+	}
+	assert.Equal(t, []string{"on", "engine", "quoted-key", "mcp-servers", "tools", "labels"}, keys)
+
+	on, err := doc.lookup([]string{"on"})
+	require.NoError(t, err)
+	assert.Equal(t, "issues", on.Value)
+	assert.Equal(t, "# trigger", on.Comment)
+
+	url, err := doc.lookup([]string{"mcp-servers", "legacy", "url"})
+	require.NoError(t, err)
+	require.NotNil(t, url)
+	assert.Equal(t, 7, url.Line)
+	assert.Equal(t, "https://example.com", url.Value)
+
+	legacy, err := doc.lookup([]string{"mcp-servers", "legacy"})
+	require.NoError(t, err)
+	assert.Equal(t, 9, legacy.End, "the value span ends before the blank line")
+
+	bash, err := doc.lookup([]string{"tools", "bash"})
+	require.NoError(t, err)
+	assert.False(t, bash.isBlockMapping(), "sequences are not mappings")
+
+	missing, err := doc.lookup([]string{"tools", "playwright"})
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	_, err = doc.lookup([]string{"labels", "x"})
+	require.Error(t, err, "flow values cannot be traversed")
+}
+
+func TestYAMLDocumentEdits(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(doc *yamlDocument) ([]string, error)
+		want string // expected replacement of the lines that changed
+		old  string
+	}{
+		{
+			name: "rename keeps value and comments",
+			edit: func(doc *yamlDocument) ([]string, error) {
+				lines, _, err := doc.renameEntry([]string{"on"}, "trigger")
+				return lines, err
+			},
+			old:  "on: issues # trigger",
+			want: "trigger: issues # trigger",
+		},
+		{
+			name: "set existing scalar keeps comment",
+			edit: func(doc *yamlDocument) ([]string, error) {
+				return doc.setEntry([]string{"on"}, yamlFragment{Inline: "push"})
+			},
+			old:  "on: issues # trigger",
+			want: "on: push # trigger",
+		},
+		{
+			name: "set into empty mapping",
+			edit: func(doc *yamlDocument) ([]string, error) {
+				return doc.setEntry([]string{"tools", "github", "toolsets"}, yamlFragment{Inline: "[repos]"})
+			},
+			old:  "  github:\n",
+			want: "  github:\n    toolsets: [repos]\n",
+		},
+		{
+			name: "set creates parents",
+			edit: func(doc *yamlDocument) ([]string, error) {
+				return doc.setEntry([]string{"network", "allowed"}, yamlFragment{Lines: []string{"- defaults"}})
+			},
+			old:  "labels: [a, b]",
+			want: "labels: [a, b]\nnetwork:\n  allowed:\n    - defaults",
+		},
+		{
+			name: "delete removes nested lines",
+			edit: func(doc *yamlDocument) ([]string, error) {
+				lines, _, err := doc.deleteEntry([]string{"mcp-servers", "legacy"})
+				return lines, err
+			},
+			old:  "  legacy:\n    # internal server\n    url: https://example.com # endpoint\n    allowed: [\"*\"]\n",
+			want: "",
+		},
+		{
+			name: "move keeps comments and reindents",
+			edit: func(doc *yamlDocument) ([]string, error) {
+				lines, _, err := doc.moveEntry([]string{"mcp-servers", "legacy"}, []string{"tools", "github", "legacy"})
+				return lines, err
+			},
+			old:  "  legacy:\n    # internal server\n    url: https://example.com # endpoint\n    allowed: [\"*\"]\n\ntools:\n  github:\n",
+			want: "\ntools:\n  github:\n    legacy:\n      # internal server\n      url: https://example.com # endpoint\n      allowed: [\"*\"]\n",
+		},
+		{
+			name: "wrap scalar",
+			edit: func(doc *yamlDocument) ([]string, error) {
+				lines, _, err := doc.wrapEntry([]string{"engine"}, "id")
+				return lines, err
+			},
+			old:  "engine: claude",
+			want: "engine:\n  id: claude",
+		},
+		{
+			name: "wrap sequence",
+			edit: func(doc *yamlDocument) ([]string, error) {
+				lines, _, err := doc.wrapEntry([]string{"tools", "bash"}, "allowed")
+				return lines, err
+			},
+			old:  "  bash:\n    - \"echo #1\"",
+			want: "  bash:\n    allowed:\n      - \"echo #1\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := tt.edit(parseTestYAMLDocument())
+			require.NoError(t, err)
+			require.Contains(t, testYAMLDocument, tt.old, "test setup")
+			assert.Equal(t, strings.Replace(testYAMLDocument, tt.old, tt.want, 1), strings.Join(lines, "\n"))
+		})
+	}
+}
+
+func TestYAMLDocumentEditErrors(t *testing.T) {
+	doc := parseTestYAMLDocument()
+
+	_, _, err := doc.renameEntry([]string{"on"}, "engine")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'/engine' already exists")
+
+	_, _, err = doc.moveEntry([]string{"on"}, []string{"engine"})
+	require.Error(t, err)
+
+	_, err = doc.setEntry([]string{"engine", "model"}, yamlFragment{Inline: "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'/engine' is not a block mapping")
+
+	lines, changed, err := doc.deleteEntry([]string{"missing"})
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, doc.Lines, lines)
+}
+
+func TestSplitYAMLComment(t *testing.T) {
+	tests := []struct {
+		rest, value, comment string
+	}{
+		{rest: " value # note", value: "value", comment: "# note"},
+		{rest: ` "a # b" # note`, value: `"a # b"`, comment: "# note"},
+		{rest: " url#fragment", value: "url#fragment"},
+		{rest: "", value: ""},
+	}
+	for _, tt := range tests {
+		value, comment := splitYAMLComment(tt.rest)
+		assert.Equal(t, tt.value, value, tt.rest)
+		assert.Equal(t, tt.comment, comment, tt.rest)
+	}
+}
@@ -17,6 +17,7 @@
 //   - runBatchActionlint() - Run actionlint on multiple lock files
 //
 // File Cleanup:
+//   - findOrphanedLockFiles() - Find .lock.yml files without a .md source
 //   - purgeOrphanedLockFiles() - Remove orphaned .lock.yml files
 //   - purgeInvalidFiles() - Remove .invalid.yml files
 //
@@ -118,23 +119,8 @@ func purgeOrphanedLockFiles(workflowsDir string, expectedLockFiles []string, ver
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Found %d existing .lock.yml files", len(existingLockFiles))))
 	}
 
-	// Build a set of expected lock files
-	expectedLockFileSet := make(map[string]bool)
-	for _, expected := range expectedLockFiles {
-		expectedLockFileSet[expected] = true
-	}
-
 	// Find lock files that should be deleted (exist but aren't expected)
-	var orphanedFiles []string
-	for _, existing := range existingLockFiles {
-		// Skip .campaign.lock.yml files - they're handled by purgeOrphanedCampaignOrchestratorLockFiles
-		if strings.HasSuffix(existing, ".campaign.lock.yml") {
-			continue
-		}
-		if !expectedLockFileSet[existing] {
-			orphanedFiles = append(orphanedFiles, existing)
-		}
-	}
+	orphanedFiles := findOrphanedLockFiles(existingLockFiles, expectedLockFiles)
 
 	// Delete orphaned lock files
 	if len(orphanedFiles) > 0 {
@@ -156,6 +142,27 @@ func purgeOrphanedLockFiles(workflowsDir string, expectedLockFiles []string, ver
 	return nil
 }
 
+// findOrphanedLockFiles returns the existing lock files that don't correspond to any
+// expected lock file (i.e. whose .md source no longer exists)
+func findOrphanedLockFiles(existingLockFiles []string, expectedLockFiles []string) []string {
+	expectedLockFileSet := make(map[string]bool)
+	for _, expected := range expectedLockFiles {
+		expectedLockFileSet[expected] = true
+	}
+
+	var orphanedFiles []string
+	for _, existing := range existingLockFiles {
+		// Skip .campaign.lock.yml files - they're handled by purgeOrphanedCampaignOrchestratorLockFiles
+		if strings.HasSuffix(existing, ".campaign.lock.yml") {
+			continue
+		}
+		if !expectedLockFileSet[existing] {
+			orphanedFiles = append(orphanedFiles, existing)
+		}
+	}
+	return orphanedFiles
+}
+
 // purgeInvalidFiles removes all .invalid.yml files
 // These are temporary debugging artifacts that should not persist
 func purgeInvalidFiles(workflowsDir string, verbose bool) error {
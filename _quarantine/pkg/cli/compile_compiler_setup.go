@@ -21,6 +21,7 @@
 //   - configureCompilerFlags() - Sets validation, strict mode, trial mode flags
 //   - setupActionMode() - Configures action script inlining mode
 //   - setupRepositoryContext() - Sets repository slug for schedule scattering
+//   - setupSchedulePlan() - Plans fuzzy schedules across the repository (schedule_plan.go)
 //
 // These functions abstract compiler setup, allowing the main compile
 // orchestrator to focus on coordination while these handle configuration.
@@ -107,6 +108,9 @@ func createAndConfigureCompiler(config CompileConfig) *workflow.Compiler {
 	// Set up repository context
 	setupRepositoryContext(compiler)
 
+	// Plan fuzzy schedules across all workflows of the repository
+	setupSchedulePlan(compiler, config.WorkflowDir)
+
 	return compiler
 }
 
@@ -142,11 +146,20 @@ func configureCompilerFlags(compiler *workflow.Compiler, config CompileConfig) {
 		compileCompilerSetupLog.Print("Stop time refresh enabled: will regenerate stop-after times")
 	}
 
+	// Set dependency lock flags
+	compiler.SetLockDeps(config.LockDeps, config.ForceOverwrite)
+	if config.LockDeps {
+		compileCompilerSetupLog.Printf("Dependency locking enabled: refresh=%v", config.ForceOverwrite)
+	}
+
 	// Set force refresh action pins flag
 	compiler.SetForceRefreshActionPins(config.ForceRefreshActionPins)
 	if config.ForceRefreshActionPins {
 		compileCompilerSetupLog.Print("Force refresh action pins enabled: will clear cache and resolve all actions from GitHub API")
 	}
+
+	// Resolve container image digests that are not in the action cache yet
+	compiler.SetResolveImageDigests(true)
 }
 
 // setupActionMode configures the action script inlining mode
@@ -22,7 +22,8 @@ type CompileConfig struct {
 	TrialLogicalRepoSlug   string   // Target repository for trial mode
 	Strict                 bool     // Enable strict mode validation
 	Dependabot             bool     // Generate Dependabot manifests for npm dependencies
-	ForceOverwrite         bool     // Force overwrite of existing files (dependabot.yml)
+	ForceOverwrite         bool     // Force overwrite of existing files (dependabot.yml) and re-resolution of the dependency lock
+	LockDeps               bool     // Resolve runtime dependencies into the dependency lock (aw-deps.lock)
 	RefreshStopTime        bool     // Force regeneration of stop-after times instead of preserving existing ones
 	ForceRefreshActionPins bool     // Force refresh of action pins by clearing cache and resolving from GitHub API
 	Zizmor                 bool     // Run zizmor security scanner on generated .lock.yml files
@@ -33,6 +34,8 @@ type CompileConfig struct {
 	ActionTag              string   // Override action SHA or tag for actions/setup (overrides action-mode to release)
 	Stats                  bool     // Display statistics table sorted by file size
 	FailFast               bool     // Stop at first error instead of collecting all errors
+	Policy                 string   // Organization policy file or owner/repo/path@ref (defaults to .github/aw/policy.yml when present)
+	SARIFOutput            string   // SARIF output file for findings located in the workflow markdown ("-" for stdout)
 }
 
 // WorkflowFailure represents a failed workflow with its error count
@@ -416,6 +416,13 @@ func runPostProcessing(
 		}
 	}
 
+	// Save the dependency lock if requested
+	if config.LockDeps && !config.NoEmit {
+		if err := saveDepsLock(compiler, false, config.Verbose); err != nil && config.Strict {
+			return err
+		}
+	}
+
 	// Generate maintenance workflow if needed
 	// Only generate when compiling all workflows (not specific files)
 	// Skip when using custom --dir option or when compiling specific files
@@ -454,6 +461,14 @@ func runPostProcessingForDirectory(
 		}
 	}
 
+	// Save the dependency lock if requested, dropping entries of removed dependencies
+	// when every workflow of the repository was compiled
+	if config.LockDeps && !config.NoEmit {
+		if err := saveDepsLock(compiler, config.WorkflowDir == "", config.Verbose); err != nil && config.Strict {
+			return err
+		}
+	}
+
 	// Generate maintenance workflow if needed
 	// Skip maintenance workflow generation when using custom --dir option
 	if !config.NoEmit && config.WorkflowDir == "" {
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,7 +15,7 @@ import (
 var compileOrchestratorLog = logger.New("cli:compile_orchestrator")
 
 // CompileWorkflows compiles workflows based on the provided configuration
-func CompileWorkflows(ctx context.Context, config CompileConfig) ([]*workflow.WorkflowData, error) {
+func CompileWorkflows(ctx context.Context, config CompileConfig) (workflows []*workflow.WorkflowData, err error) {
 	compileOrchestratorLog.Printf("Starting workflow compilation: files=%d, validate=%v, watch=%v, noEmit=%v",
 		len(config.MarkdownFiles), config.Validate, config.Watch, config.NoEmit)
 
@@ -41,6 +42,18 @@ func CompileWorkflows(ctx context.Context, config CompileConfig) ([]*workflow.Wo
 		initActionlintStats()
 	}
 
+	// Collect findings for the SARIF log, which is written even when compilation fails
+	if config.SARIFOutput != "" {
+		initCompileSARIF()
+		defer func() {
+			if writeErr := writeCompileSARIF(config.SARIFOutput); writeErr != nil {
+				err = errors.Join(err, writeErr)
+			} else if config.SARIFOutput != "-" {
+				fmt.Fprintln(os.Stderr, console.FormatInfoMessage("SARIF log written to "+config.SARIFOutput))
+			}
+		}()
+	}
+
 	// Track compilation statistics
 	stats := &CompilationStats{}
 
@@ -60,6 +73,16 @@ func CompileWorkflows(ctx context.Context, config CompileConfig) ([]*workflow.Wo
 	// Create and configure compiler
 	compiler := createAndConfigureCompiler(config)
 
+	// Load the organization policy evaluated against every workflow
+	policy, err := loadPolicy(config.Policy)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		compileOrchestratorLog.Printf("Enforcing policy: %s", policy.Source)
+		compiler.SetPolicy(policy)
+	}
+
 	// Handle watch mode (early return)
 	if config.Watch {
 		// Watch mode: watch for file changes and recompile automatically
@@ -16,6 +16,7 @@
 // Generation:
 //   - generateDependabotManifestsWrapper() - Generate Dependabot manifests
 //   - generateMaintenanceWorkflowWrapper() - Generate maintenance workflow
+//   - saveDepsLock() - Save the dependency lock (aw-deps.lock)
 //
 // Statistics:
 //   - collectWorkflowStatisticsWrapper() - Collect workflow statistics
@@ -151,6 +152,26 @@ func saveActionCache(actionCache *workflow.ActionCache, verbose bool) error {
 	return nil
 }
 
+// saveDepsLock saves the dependency lock resolved while compiling. With prune, entries no
+// compiled workflow uses are removed.
+func saveDepsLock(compiler *workflow.Compiler, prune bool, verbose bool) error {
+	depsLock := compiler.GetDepsLock()
+	if prune {
+		depsLock.Prune()
+	}
+
+	compilePostProcessingLog.Printf("Saving dependency lock: prune=%v", prune)
+	if err := depsLock.Save(); err != nil {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save dependency lock: %v", err)))
+		return err
+	}
+
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Dependency lock saved to %s", depsLock.GetPath())))
+	}
+	return nil
+}
+
 // getAbsoluteWorkflowDir converts a relative workflow dir to absolute path
 func getAbsoluteWorkflowDir(workflowDir string, gitRoot string) string {
 	absWorkflowDir := workflowDir
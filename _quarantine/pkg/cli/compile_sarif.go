@@ -0,0 +1,341 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var compileSARIFLog = logger.New("cli:compile_sarif")
+
+// compileSARIFToolName is the tool name reported in SARIF logs produced by compile --sarif
+const compileSARIFToolName = "gh-aw"
+
+// compileSARIFFindings collects the findings of a compile --sarif run; nil when SARIF output is disabled
+var compileSARIFFindings *compileSARIFCollector
+
+// compileSARIFCollector accumulates compiler and scanner findings located in workflow markdown files
+type compileSARIFCollector struct {
+	mu       sync.Mutex
+	gitRoot  string
+	findings []sarifFinding
+}
+
+// initCompileSARIF enables SARIF collection for the current compile run
+func initCompileSARIF() {
+	gitRoot, err := findGitRoot()
+	if err != nil {
+		gitRoot = ""
+	}
+	compileSARIFFindings = &compileSARIFCollector{gitRoot: gitRoot}
+}
+
+// writeCompileSARIF writes the collected findings and disables collection
+func writeCompileSARIF(path string) error {
+	collector := compileSARIFFindings
+	compileSARIFFindings = nil
+	if collector == nil {
+		return nil
+	}
+	compileSARIFLog.Printf("Writing %d compile findings to %s", len(collector.findings), path)
+	return writeSARIFFile(path, compileSARIFToolName, collector.findings)
+}
+
+func (c *compileSARIFCollector) add(finding sarifFinding) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.findings = append(c.findings, finding)
+}
+
+// displayPath returns a path relative to the repository root when the file is inside it
+func (c *compileSARIFCollector) displayPath(file string) string {
+	if c.gitRoot == "" {
+		return file
+	}
+	absPath, err := filepath.Abs(file)
+	if err != nil {
+		return file
+	}
+	if rel, err := filepath.Rel(c.gitRoot, absPath); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return file
+}
+
+// resolvePath resolves a scanner-reported path, which is relative to either the working
+// directory or the repository root
+func (c *compileSARIFCollector) resolvePath(file string) string {
+	if filepath.IsAbs(file) || c.gitRoot == "" {
+		return file
+	}
+	if _, err := os.Stat(file); err == nil {
+		return file
+	}
+	return filepath.Join(c.gitRoot, file)
+}
+
+// recordCompileSARIFError records a compiler error for a workflow markdown file. Errors carrying
+// source locations are reported at the offending frontmatter value; other errors are reported
+// at the position in their message, usually the start of the file.
+func recordCompileSARIFError(markdownFile string, err error) {
+	collector := compileSARIFFindings
+	if collector == nil || err == nil {
+		return
+	}
+	content, readErr := os.ReadFile(markdownFile)
+	if readErr != nil {
+		compileSARIFLog.Printf("Failed to read %s: %v", markdownFile, readErr)
+	}
+	file := collector.displayPath(markdownFile)
+	errorText := parser.StripANSI(err.Error())
+
+	locations := workflow.SourceLocations(err)
+	for _, location := range locations {
+		line, column := 1, 1
+		if location.Path != "" {
+			line, column = locateFrontmatterPath(string(content), location.Path)
+		} else if location.Text != "" {
+			if l, c, ok := findSourceText(string(content), location.Text); ok {
+				line, column = l, c
+			}
+		}
+		message := location.Message
+		if message == "" {
+			message = firstCompilerErrorMessage(errorText)
+		}
+		collector.add(sarifFinding{
+			RuleID:          location.Rule,
+			RuleDescription: compileSARIFRuleDescription(location.Rule),
+			Level:           "error",
+			Message:         message,
+			File:            file,
+			Line:            line,
+			Column:          column,
+		})
+	}
+	if len(locations) > 0 {
+		return
+	}
+
+	lines := strings.Split(string(content), "\n")
+	for _, diagnostic := range compilerErrorToLSPDiagnostics(markdownFile, errorText, lines) {
+		level := "error"
+		if diagnostic.Severity == lspSeverityWarning {
+			level = "warning"
+		}
+		collector.add(sarifFinding{
+			RuleID:          "compile",
+			RuleDescription: compileSARIFRuleDescription("compile"),
+			Level:           level,
+			Message:         diagnostic.Message,
+			File:            file,
+			Line:            diagnostic.Range.Start.Line + 1,
+			Column:          diagnostic.Range.Start.Character + 1,
+		})
+	}
+}
+
+// recordCompileSARIFLockFinding records a scanner finding reported against a lock file at
+// the corresponding location of the workflow markdown file it was compiled from
+func recordCompileSARIFLockFinding(lockFile string, line int, ruleID, description, level, message string) {
+	collector := compileSARIFFindings
+	if collector == nil {
+		return
+	}
+	lockPath := collector.resolvePath(lockFile)
+	lockDisplay := collector.displayPath(lockPath)
+
+	markdownFile := strings.TrimSuffix(lockPath, ".lock.yml") + ".md"
+	markdown, err := os.ReadFile(markdownFile)
+	if err != nil || !strings.HasSuffix(lockPath, ".lock.yml") {
+		compileSARIFLog.Printf("No markdown source for %s, reporting the lock file location", lockFile)
+		collector.add(sarifFinding{RuleID: ruleID, RuleDescription: description, Level: level, Message: message, File: lockDisplay, Line: line, Column: 1})
+		return
+	}
+
+	mdLine, mdColumn := 1, 1
+	if lockContent, err := os.ReadFile(lockPath); err == nil {
+		mdLine, mdColumn = mapLockFileLine(string(lockContent), string(markdown), line)
+	}
+	collector.add(sarifFinding{
+		RuleID:          ruleID,
+		RuleDescription: description,
+		Level:           level,
+		Message:         fmt.Sprintf("%s (%s:%d)", message, lockDisplay, line),
+		File:            collector.displayPath(markdownFile),
+		Line:            mdLine,
+		Column:          mdColumn,
+	})
+}
+
+// compileSARIFRuleDescription returns the description of a compiler rule
+func compileSARIFRuleDescription(rule string) string {
+	switch {
+	case rule == "compile":
+		return "Workflow compilation error"
+	case rule == "dangerous-permissions":
+		return "Write permissions require the dangerous-permissions-write feature"
+	case rule == "strict-mode":
+		return "Strict mode restriction"
+	case rule == "template-injection":
+		return "Expression used directly in a shell command"
+	case rule == "validation", rule == "configuration":
+		return "Invalid workflow configuration"
+	case strings.HasPrefix(rule, "policy/"):
+		return "Organization policy rule " + strings.TrimPrefix(rule, "policy/")
+	}
+	return rule
+}
+
+// firstCompilerErrorMessage returns the first message of a compiler error without its position prefix
+func firstCompilerErrorMessage(errorText string) string {
+	for _, line := range strings.Split(errorText, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if match := compilerErrorLinePattern.FindStringSubmatch(line); match != nil {
+			return match[5]
+		}
+		return line
+	}
+	return errorText
+}
+
+// findSourceText returns the 1-based line and column of the only occurrence of text in content
+func findSourceText(content, text string) (int, int, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.Count(content, text) != 1 {
+		return 0, 0, false
+	}
+	before := content[:strings.Index(content, text)]
+	line := strings.Count(before, "\n") + 1
+	column := len(before) - strings.LastIndex(before, "\n")
+	return line, column, true
+}
+
+// lockFileKeyPattern matches a YAML mapping key at the start of a (list item) line
+var lockFileKeyPattern = regexp.MustCompile(`^(- )?("[^"]+"|'[^']+'|[A-Za-z0-9_.-]+):(\s|$)`)
+
+// lockFileJobKeys are job keys copied from the frontmatter into the agent job
+var lockFileJobKeys = map[string]bool{
+	"runs-on":         true,
+	"container":       true,
+	"services":        true,
+	"timeout-minutes": true,
+	"environment":     true,
+	"env":             true,
+	"concurrency":     true,
+}
+
+// mapLockFileLine maps a 1-based line of a lock file to the 1-based line and column of the
+// markdown source it was generated from. The frontmatter value is located from the YAML path
+// of the lock file line (e.g. on/issues/types, jobs/agent/permissions/contents); otherwise
+// the line is searched for verbatim in the markdown (run scripts, expressions, prompt text).
+func mapLockFileLine(lockContent, markdown string, line int) (int, int) {
+	lockLines := strings.Split(lockContent, "\n")
+	if line < 1 || line > len(lockLines) {
+		return 1, 1
+	}
+
+	keyPath := lockFileKeyPath(lockLines, line)
+	jsonPath := lockPathToFrontmatterPath(keyPath, markdown)
+	if jsonPath != "" {
+		if l, c, ok := locateFrontmatterPathExact(markdown, jsonPath); ok {
+			return l, c
+		}
+	}
+
+	if l, c, ok := findSourceText(markdown, strings.TrimPrefix(strings.TrimSpace(lockLines[line-1]), "- ")); ok {
+		return l, c
+	}
+
+	if jsonPath != "" {
+		return locateFrontmatterPath(markdown, jsonPath)
+	}
+	return 1, 1
+}
+
+// lockFileKeyPath returns the mapping keys enclosing a 1-based line of a YAML document,
+// from the document root to the key on the line itself (list indices are not included)
+func lockFileKeyPath(lines []string, line int) []string {
+	type entry struct {
+		indent int
+		key    string
+	}
+	var stack []entry
+	blockIndent := -1 // Indentation of the key owning the current block scalar
+	for _, text := range lines[:line] {
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(text) - len(trimmed)
+		if blockIndent >= 0 {
+			if indent > blockIndent {
+				// Text of a block scalar (e.g. a run script), not YAML structure
+				continue
+			}
+			blockIndent = -1
+		}
+		match := lockFileKeyPattern.FindStringSubmatch(trimmed)
+		if match == nil {
+			continue
+		}
+		keyIndent := indent
+		if match[1] != "" {
+			// The key of a list item is nested below the item
+			keyIndent += 2
+		}
+		for len(stack) > 0 && stack[len(stack)-1].indent >= keyIndent {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, entry{indent: keyIndent, key: strings.Trim(match[2], `"'`)})
+
+		value := strings.TrimSpace(trimmed[len(match[0]):])
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			blockIndent = keyIndent
+		}
+	}
+
+	path := make([]string, 0, len(stack))
+	for _, e := range stack {
+		path = append(path, e.key)
+	}
+	return path
+}
+
+// lockPathToFrontmatterPath maps a lock file key path to the frontmatter JSON path of the
+// value it was generated from, or "" when the value has no frontmatter counterpart
+func lockPathToFrontmatterPath(keyPath []string, markdown string) string {
+	if len(keyPath) == 0 {
+		return ""
+	}
+	switch keyPath[0] {
+	case "on", "permissions", "env", "concurrency", "run-name":
+		return "/" + strings.Join(keyPath, "/")
+	case "jobs":
+		if len(keyPath) < 2 {
+			return ""
+		}
+		job := keyPath[1]
+		if _, _, ok := locateFrontmatterPathExact(markdown, "/jobs/"+job); ok {
+			return "/" + strings.Join(keyPath, "/")
+		}
+		if len(keyPath) < 3 {
+			return ""
+		}
+		key := keyPath[2]
+		if key == "permissions" || lockFileJobKeys[key] {
+			return "/" + strings.Join(keyPath[2:], "/")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,166 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSARIFMarkdown = `---
+on:
+  issues:
+    types: [opened]
+permissions:
+  contents: read
+runs-on: ubuntu-latest
+steps:
+  - name: Fetch data
+    run: curl -s https://example.com/data.json > data.json
+---
+# Test
+`
+
+const testSARIFLockFile = `name: "test"
+"on":
+  issues:
+    types:
+      - opened
+
+permissions: {}
+
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+    steps:
+      - name: Fetch data
+        run: curl -s https://example.com/data.json > data.json
+      - name: Create prompt
+        run: |
+          cat << 'EOF' > prompt.txt
+          runs-on: not a key
+          EOF
+`
+
+func TestLockFileKeyPath(t *testing.T) {
+	lines := strings.Split(testSARIFLockFile, "\n")
+	tests := []struct {
+		line int
+		want []string
+	}{
+		{line: 4, want: []string{"on", "issues", "types"}},
+		{line: 5, want: []string{"on", "issues", "types"}},
+		{line: 11, want: []string{"jobs", "agent", "runs-on"}},
+		{line: 13, want: []string{"jobs", "agent", "permissions", "contents"}},
+		{line: 16, want: []string{"jobs", "agent", "steps", "run"}},
+		{line: 20, want: []string{"jobs", "agent", "steps", "run"}}, // Keys inside block scalars are text
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, lockFileKeyPath(lines, tt.line), "line %d", tt.line)
+	}
+}
+
+func TestMapLockFileLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       int
+		wantLine   int
+		wantColumn int
+	}{
+		{name: "trigger", line: 5, wantLine: 4, wantColumn: 11},
+		{name: "job key copied from the frontmatter", line: 11, wantLine: 7, wantColumn: 9},
+		{name: "job permission", line: 13, wantLine: 6, wantColumn: 12},
+		{name: "step run script found verbatim", line: 16, wantLine: 10, wantColumn: 5},
+		{name: "generated line", line: 19, wantLine: 1, wantColumn: 1},
+		{name: "out of range", line: 100, wantLine: 1, wantColumn: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, column := mapLockFileLine(testSARIFLockFile, testSARIFMarkdown, tt.line)
+			assert.Equal(t, tt.wantLine, line)
+			assert.Equal(t, tt.wantColumn, column)
+		})
+	}
+}
+
+func TestFindSourceText(t *testing.T) {
+	line, column, ok := findSourceText("a\nb: ${{ inputs.x }}\n", "${{ inputs.x }}")
+	require.True(t, ok)
+	assert.Equal(t, 2, line)
+	assert.Equal(t, 4, column)
+
+	_, _, ok = findSourceText("x\nx\n", "x")
+	assert.False(t, ok, "ambiguous text is not located")
+	_, _, ok = findSourceText("x\n", "  ")
+	assert.False(t, ok)
+}
+
+func TestCompileSARIF(t *testing.T) {
+	dir := testutil.TempDir(t, "test-*")
+	markdownFile := filepath.Join(dir, "test.md")
+	lockFile := filepath.Join(dir, "test.lock.yml")
+	require.NoError(t, os.WriteFile(markdownFile, []byte(testSARIFMarkdown), 0644))
+	require.NoError(t, os.WriteFile(lockFile, []byte(testSARIFLockFile), 0644))
+
+	// Nothing is recorded when SARIF output is disabled
+	recordCompileSARIFError(markdownFile, errors.New("ignored"))
+	require.NoError(t, writeCompileSARIF(filepath.Join(dir, "unused.sarif")))
+	assert.NoFileExists(t, filepath.Join(dir, "unused.sarif"))
+
+	initCompileSARIF()
+	located := &workflow.SourceLocatedError{
+		Err:       errors.New("strict mode: write permission 'contents: write' is not allowed"),
+		Locations: []workflow.SourceLocation{{Rule: "strict-mode", Path: "/permissions/contents"}},
+	}
+	recordCompileSARIFError(markdownFile, fmt.Errorf("%s:1:1: error: failed: %w", markdownFile, located))
+	recordCompileSARIFError(markdownFile, fmt.Errorf("%s:1:1: error: unknown property 'foo'", markdownFile))
+	recordCompileSARIFLockFinding(lockFile, 16, "zizmor/artipacked", "credential persistence", "warning", "credentials persisted")
+	recordCompileSARIFLockFinding(filepath.Join(dir, "other.yml"), 3, "actionlint/syntax-check", "", "error", "not compiled from markdown")
+
+	sarifPath := filepath.Join(dir, "compile.sarif")
+	require.NoError(t, writeCompileSARIF(sarifPath))
+	assert.Nil(t, compileSARIFFindings, "collection stops once the log is written")
+
+	data, err := os.ReadFile(sarifPath)
+	require.NoError(t, err)
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(data, &log))
+	assert.Equal(t, compileSARIFToolName, log.Runs[0].Tool.Driver.Name)
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 4)
+
+	assert.Equal(t, "strict-mode", results[0].RuleID)
+	assert.Equal(t, "strict mode: write permission 'contents: write' is not allowed", results[0].Message.Text)
+	assert.Equal(t, &sarifRegion{StartLine: 6, StartColumn: 12}, results[0].Locations[0].PhysicalLocation.Region)
+
+	assert.Equal(t, "compile", results[1].RuleID)
+	assert.Equal(t, "unknown property 'foo'", results[1].Message.Text)
+	assert.Equal(t, 1, results[1].Locations[0].PhysicalLocation.Region.StartLine)
+
+	assert.Equal(t, "zizmor/artipacked", results[2].RuleID)
+	assert.Equal(t, "warning", results[2].Level)
+	assert.True(t, strings.HasSuffix(results[2].Locations[0].PhysicalLocation.ArtifactLocation.URI, "test.md"))
+	assert.Equal(t, 10, results[2].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Contains(t, results[2].Message.Text, "test.lock.yml:16")
+
+	assert.True(t, strings.HasSuffix(results[3].Locations[0].PhysicalLocation.ArtifactLocation.URI, "other.yml"), "files without a markdown source keep their location")
+}
+
+func TestValidateCompileConfigSARIF(t *testing.T) {
+	require.Error(t, validateCompileConfig(CompileConfig{SARIFOutput: "out.sarif", Watch: true}))
+	require.Error(t, validateCompileConfig(CompileConfig{SARIFOutput: "-", JSONOutput: true}))
+	require.NoError(t, validateCompileConfig(CompileConfig{SARIFOutput: "out.sarif", JSONOutput: true}))
+}
@@ -211,6 +211,16 @@ func validateCompileConfig(config CompileConfig) error {
 		return fmt.Errorf("--purge flag can only be used when compiling all markdown files (no specific files specified)")
 	}
 
+	// Validate SARIF output usage
+	if config.SARIFOutput != "" && config.Watch {
+		compileValidationLog.Print("Config validation failed: sarif flag with watch mode")
+		return fmt.Errorf("--sarif flag cannot be used with --watch")
+	}
+	if config.SARIFOutput == "-" && config.JSONOutput {
+		compileValidationLog.Print("Config validation failed: sarif and json output both on stdout")
+		return fmt.Errorf("--sarif - cannot be used with --json since both write to stdout")
+	}
+
 	// Validate workflow directory path
 	if config.WorkflowDir != "" && filepath.IsAbs(config.WorkflowDir) {
 		compileValidationLog.Printf("Config validation failed: absolute path in workflowDir: %s", config.WorkflowDir)
@@ -42,6 +42,26 @@ type compileWorkflowFileResult struct {
 	success          bool
 }
 
+// configureCompilerForFile sets the per-file compiler context (workflow identifier and
+// repository slug) that influences the generated lock file
+func configureCompilerForFile(compiler *workflow.Compiler, resolvedFile string) {
+	// Set workflow identifier for schedule scattering (use repository-relative path for stability)
+	relPath, err := getRepositoryRelativePath(resolvedFile)
+	if err != nil {
+		compileWorkflowProcessorLog.Printf("Warning: failed to get repository-relative path for %s: %v", resolvedFile, err)
+		// Fallback to basename if we can't get relative path
+		relPath = filepath.Base(resolvedFile)
+	}
+	compiler.SetWorkflowIdentifier(relPath)
+
+	// Set repository slug for this specific file (may differ from CWD's repo)
+	fileRepoSlug := getRepositorySlugFromRemoteForPath(resolvedFile)
+	if fileRepoSlug != "" {
+		compiler.SetRepositorySlug(fileRepoSlug)
+		compileWorkflowProcessorLog.Printf("Repository slug for file set: %s", fileRepoSlug)
+	}
+}
+
 // compileWorkflowFile compiles a single workflow file (not a campaign spec)
 // Returns the workflow data, lock file path, validation result, and success status
 func compileWorkflowFile(
@@ -78,21 +98,7 @@ func compileWorkflowFile(
 	// Parse workflow file to get data
 	compileWorkflowProcessorLog.Printf("Parsing workflow file: %s", resolvedFile)
 
-	// Set workflow identifier for schedule scattering (use repository-relative path for stability)
-	relPath, err := getRepositoryRelativePath(resolvedFile)
-	if err != nil {
-		compileWorkflowProcessorLog.Printf("Warning: failed to get repository-relative path for %s: %v", resolvedFile, err)
-		// Fallback to basename if we can't get relative path
-		relPath = filepath.Base(resolvedFile)
-	}
-	compiler.SetWorkflowIdentifier(relPath)
-
-	// Set repository slug for this specific file (may differ from CWD's repo)
-	fileRepoSlug := getRepositorySlugFromRemoteForPath(resolvedFile)
-	if fileRepoSlug != "" {
-		compiler.SetRepositorySlug(fileRepoSlug)
-		compileWorkflowProcessorLog.Printf("Repository slug for file set: %s", fileRepoSlug)
-	}
+	configureCompilerForFile(compiler, resolvedFile)
 
 	// Parse the workflow
 	workflowData, err := compiler.ParseWorkflowFile(resolvedFile)
@@ -115,6 +121,7 @@ func compileWorkflowFile(
 
 		// Don't print error here - it will be displayed in the compilation summary
 		// The error is stored in ValidationResult for JSON output and summary display
+		recordCompileSARIFError(resolvedFile, err)
 		result.validationResult.Valid = false
 		result.validationResult.Errors = append(result.validationResult.Errors, CompileValidationError{
 			Type:    "parse_error",
@@ -131,6 +138,7 @@ func compileWorkflowFile(
 	if err := CompileWorkflowDataWithValidation(compiler, workflowData, resolvedFile, verbose && !jsonOutput, zizmor && !noEmit, poutine && !noEmit, false, strict, validate && !noEmit); err != nil {
 		// Don't print error here - it will be displayed in the compilation summary
 		// The error is stored in ValidationResult for JSON output and summary display
+		recordCompileSARIFError(resolvedFile, err)
 		result.validationResult.Valid = false
 		result.validationResult.Errors = append(result.validationResult.Errors, CompileValidationError{
 			Type:    "compilation_error",
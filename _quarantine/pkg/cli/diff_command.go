@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var diffLog = logger.New("cli:diff_command")
+
+// DiffConfig holds configuration for the diff command
+type DiffConfig struct {
+	WorkflowName string
+	Against      string
+	Version      string
+	WorkflowDir  string
+	ActionMode   string
+	JSONOutput   bool
+	Verbose      bool
+}
+
+// DiffReport is the structural diff of a workflow between a base and the working tree
+type DiffReport struct {
+	Workflow string                 `json:"workflow"`
+	Base     string                 `json:"base"`
+	Head     string                 `json:"head"`
+	Changes  *WorkflowStructureDiff `json:"changes"`
+}
+
+// NewDiffCommand creates the diff command
+func NewDiffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <workflow>",
+		Short: "Show the structural changes of a compiled workflow against a git ref or gh-aw release",
+		Long: `Compile a workflow twice and report the security-relevant differences between the results.
+
+The working tree version of the workflow is compiled with the current gh-aw version and
+compared with a base, which is either:
+  --against <ref>   the workflow sources at a git ref, compiled with the current gh-aw version
+  --version <tag>   the working tree sources, compiled with another gh-aw release
+
+The report is structural rather than textual: jobs added or removed, permission changes per
+job, new network domains, changed action pins, new secrets referenced, changed safe-output
+limits and MCP servers. Widened permissions, new secrets and new domains are highlighted.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` diff ci-doctor                     # Compare with the workflow at HEAD
+  ` + string(constants.CLIExtensionPrefix) + ` diff ci-doctor --against main      # Compare with the workflow on main
+  ` + string(constants.CLIExtensionPrefix) + ` diff ci-doctor --version v0.30.0   # Compare with the output of gh-aw v0.30.0
+  ` + string(constants.CLIExtensionPrefix) + ` diff ci-doctor --json              # Output the diff as JSON`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			against, _ := cmd.Flags().GetString("against")
+			version, _ := cmd.Flags().GetString("version")
+			dir, _ := cmd.Flags().GetString("dir")
+			actionMode, _ := cmd.Flags().GetString("action-mode")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+
+			return RunDiff(DiffConfig{
+				WorkflowName: args[0],
+				Against:      against,
+				Version:      version,
+				WorkflowDir:  dir,
+				ActionMode:   actionMode,
+				JSONOutput:   jsonOutput,
+				Verbose:      verbose,
+			})
+		},
+	}
+
+	cmd.Flags().String("against", "HEAD", "Git ref whose workflow sources are the base of the comparison")
+	cmd.Flags().String("version", "", "gh-aw release whose compiler output is the base of the comparison (e.g. v0.30.0)")
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	cmd.Flags().String("action-mode", "", "Action script inlining mode (inline, dev, release). Auto-detected if not specified")
+	addJSONFlag(cmd)
+	cmd.MarkFlagsMutuallyExclusive("against", "version")
+
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// RunDiff compiles both sides of a workflow and reports their structural differences
+func RunDiff(config DiffConfig) error {
+	diffLog.Printf("Diffing workflow %s: against=%s, version=%s", config.WorkflowName, config.Against, config.Version)
+
+	if err := validateActionModeConfig(config.ActionMode); err != nil {
+		return err
+	}
+
+	report, err := diffWorkflow(config)
+	if err != nil {
+		return err
+	}
+
+	if config.JSONOutput {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	displayDiffReport(report)
+	return nil
+}
+
+// diffWorkflow compiles the working tree workflow and its base and compares their structure
+func diffWorkflow(config DiffConfig) (*DiffReport, error) {
+	markdownPath, err := resolveDiffWorkflowFile(config)
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := createAndConfigureCompiler(CompileConfig{
+		NoEmit:     true,
+		ActionMode: config.ActionMode,
+		Verbose:    config.Verbose,
+	})
+	compiler.SetQuiet(true)
+	configureCompilerForFile(compiler, markdownPath)
+	_, headLock, err := compiler.CompileToYAML(markdownPath)
+	if err != nil {
+		var sharedErr *workflow.SharedWorkflowError
+		if errors.As(err, &sharedErr) {
+			return nil, fmt.Errorf("%s is a shared workflow component and cannot be compiled on its own", filepath.Base(markdownPath))
+		}
+		return nil, fmt.Errorf("failed to compile %s: %w", filepath.Base(markdownPath), err)
+	}
+
+	report := &DiffReport{
+		Workflow: strings.TrimSuffix(filepath.Base(markdownPath), ".md"),
+		Head:     "working tree (gh-aw " + GetVersion() + ")",
+	}
+
+	var baseLock string
+	baseExists := true
+	if config.Version != "" {
+		report.Base = "working tree (gh-aw " + config.Version + ")"
+		baseLock, err = compileWorkflowWithRelease(markdownPath, config.Version, config)
+	} else {
+		against := config.Against
+		if against == "" {
+			against = "HEAD"
+		}
+		report.Base = against + " (gh-aw " + GetVersion() + ")"
+		baseLock, baseExists, err = compileWorkflowAtRef(markdownPath, against, config)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := extractWorkflowStructure(headLock)
+	if err != nil {
+		return nil, err
+	}
+	var base *compiledWorkflowStructure
+	if baseExists {
+		if base, err = extractWorkflowStructure(baseLock); err != nil {
+			return nil, err
+		}
+	}
+
+	report.Changes = diffWorkflowStructures(base, head)
+	diffLog.Printf("Diff complete: changes=%t, permissions widened=%t", report.Changes.HasChanges(), report.Changes.PermissionsWidened)
+	return report, nil
+}
+
+// resolveDiffWorkflowFile resolves the workflow argument to its markdown file
+func resolveDiffWorkflowFile(config DiffConfig) (string, error) {
+	if config.WorkflowDir == "" {
+		return resolveWorkflowFile(config.WorkflowName, config.Verbose)
+	}
+	workflowsDir := config.WorkflowDir
+	if !filepath.IsAbs(workflowsDir) {
+		gitRoot, err := findGitRoot()
+		if err != nil {
+			return "", fmt.Errorf("diff requires being in a git repository: %w", err)
+		}
+		workflowsDir = filepath.Join(gitRoot, workflowsDir)
+	}
+	return resolveWorkflowFileInDir(config.WorkflowName, config.Verbose, workflowsDir)
+}
+
+// displayDiffReport prints the structural diff in a human-readable format
+func displayDiffReport(report *DiffReport) {
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Comparing %s: %s → %s", report.Workflow, report.Base, report.Head)))
+
+	changes := report.Changes
+	if changes.BaseMissing {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Workflow does not exist in the base, showing its full structure as added"))
+	}
+	if !changes.HasChanges() {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("No structural changes"))
+		return
+	}
+
+	printDiffSection("Jobs", prefixAll("+ ", changes.JobsAdded), prefixAll("- ", changes.JobsRemoved))
+
+	var permissionLines []string
+	for _, change := range changes.PermissionChanges {
+		line := fmt.Sprintf("%s: %s %s → %s", change.Job, change.Scope, levelOrNone(change.Before), levelOrNone(change.After))
+		if permissionRank(change.After) > permissionRank(change.Before) {
+			line = console.FormatWarningMessage(line + " (widened)")
+		}
+		permissionLines = append(permissionLines, line)
+	}
+	printDiffSection("Permissions", permissionLines)
+
+	var domainLines []string
+	for _, domain := range changes.DomainsAdded {
+		domainLines = append(domainLines, console.FormatWarningMessage("+ "+domain))
+	}
+	printDiffSection("Network domains", domainLines, prefixAll("- ", changes.DomainsRemoved))
+
+	var secretLines []string
+	for _, secret := range changes.SecretsAdded {
+		secretLines = append(secretLines, console.FormatWarningMessage("+ "+secret))
+	}
+	printDiffSection("Secrets", secretLines, prefixAll("- ", changes.SecretsRemoved))
+
+	printDiffSection("Action pins", formatValueChanges(changes.ActionChanges))
+	printDiffSection("Safe outputs", formatValueChanges(changes.SafeOutputChanges))
+	printDiffSection("MCP servers", prefixAll("+ ", changes.MCPServersAdded), prefixAll("- ", changes.MCPServersRemoved))
+}
+
+// printDiffSection prints a section of the diff report when it has any lines
+func printDiffSection(title string, lineGroups ...[]string) {
+	var lines []string
+	for _, group := range lineGroups {
+		lines = append(lines, group...)
+	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, console.FormatListHeader(title))
+	for _, line := range lines {
+		fmt.Fprintln(os.Stderr, console.FormatListItem(line))
+	}
+}
+
+// formatValueChanges formats value changes as added, removed or changed lines
+func formatValueChanges(changes []ValueChange) []string {
+	lines := make([]string, 0, len(changes))
+	for _, change := range changes {
+		switch {
+		case change.Before == "":
+			lines = append(lines, fmt.Sprintf("+ %s: %s", change.Name, change.After))
+		case change.After == "":
+			lines = append(lines, fmt.Sprintf("- %s: %s", change.Name, change.Before))
+		default:
+			lines = append(lines, fmt.Sprintf("~ %s: %s → %s", change.Name, change.Before, change.After))
+		}
+	}
+	return lines
+}
+
+func prefixAll(prefix string, values []string) []string {
+	lines := make([]string, 0, len(values))
+	for _, value := range values {
+		lines = append(lines, prefix+value)
+	}
+	return lines
+}
+
+func levelOrNone(level string) string {
+	if level == "" {
+		return "none"
+	}
+	return level
+}
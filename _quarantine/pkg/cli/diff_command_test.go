@@ -0,0 +1,126 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const diffTestWorkflow = `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: copilot
+safe-outputs:
+  create-issue:
+    max: 1
+---
+
+# Diff Test
+
+Summarize the repository.
+`
+
+func runGitForDiffTest(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, output)
+}
+
+func TestDiffWorkflowAgainstRef(t *testing.T) {
+	repoDir := testutil.TempDir(t, "test-*")
+	workflowsDir := filepath.Join(repoDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	workflowPath := filepath.Join(workflowsDir, "diff-test.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(diffTestWorkflow), 0644))
+
+	runGitForDiffTest(t, repoDir, "init", "-q")
+	runGitForDiffTest(t, repoDir, "add", "-A")
+	runGitForDiffTest(t, repoDir, "commit", "-q", "-m", "base")
+
+	t.Run("unchanged", func(t *testing.T) {
+		report, err := diffWorkflow(DiffConfig{WorkflowName: workflowPath, Against: "HEAD"})
+		require.NoError(t, err)
+		assert.Equal(t, "diff-test", report.Workflow)
+		assert.False(t, report.Changes.HasChanges())
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		modified := strings.Replace(diffTestWorkflow, "  contents: read\n", "  contents: read\n  issues: read\n", 1)
+		modified = strings.Replace(modified, "    max: 1\n", "    max: 3\n", 1)
+		require.NoError(t, os.WriteFile(workflowPath, []byte(modified), 0644))
+		defer func() {
+			require.NoError(t, os.WriteFile(workflowPath, []byte(diffTestWorkflow), 0644))
+		}()
+
+		report, err := diffWorkflow(DiffConfig{WorkflowName: workflowPath, Against: "HEAD"})
+		require.NoError(t, err)
+		assert.Contains(t, report.Changes.PermissionChanges, PermissionChange{Job: "agent", Scope: "issues", After: "read"})
+		assert.True(t, report.Changes.PermissionsWidened)
+		assert.Contains(t, report.Changes.SafeOutputChanges, ValueChange{Name: "create_issue.max", Before: "1", After: "3"})
+	})
+
+	t.Run("not in base", func(t *testing.T) {
+		newPath := filepath.Join(workflowsDir, "added.md")
+		require.NoError(t, os.WriteFile(newPath, []byte(diffTestWorkflow), 0644))
+		defer os.Remove(newPath)
+
+		report, err := diffWorkflow(DiffConfig{WorkflowName: newPath, Against: "HEAD"})
+		require.NoError(t, err)
+		assert.True(t, report.Changes.BaseMissing)
+		assert.Contains(t, report.Changes.JobsAdded, "agent")
+	})
+
+	t.Run("invalid ref", func(t *testing.T) {
+		_, err := diffWorkflow(DiffConfig{WorkflowName: workflowPath, Against: "no-such-ref"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no-such-ref")
+	})
+}
+
+func TestFormatValueChanges(t *testing.T) {
+	lines := formatValueChanges([]ValueChange{
+		{Name: "add_labels", After: "enabled"},
+		{Name: "actions/cache", Before: "ccc (v4)"},
+		{Name: "create_issue.max", Before: "1", After: "3"},
+	})
+	assert.Equal(t, []string{
+		"+ add_labels: enabled",
+		"- actions/cache: ccc (v4)",
+		"~ create_issue.max: 1 → 3",
+	}, lines)
+}
+
+func TestVerifyCachedReleaseBinary(t *testing.T) {
+	dir := testutil.TempDir(t, "release-*")
+	binary := filepath.Join(dir, "linux-amd64")
+	require.NoError(t, os.WriteFile(binary, []byte("gh-aw"), 0700))
+	digestPath := filepath.Join(dir, verifiedDigestFile)
+	// sha256 of "gh-aw"
+	sum := "7a41a0b255c2720a2253796133c9a5f5e0ba18bb718c6be2fa5266e6537f0f26"
+
+	digest, err := fileSHA256(binary)
+	require.NoError(t, err)
+	assert.Equal(t, sum, digest)
+
+	require.NoError(t, os.WriteFile(digestPath, []byte(sum+"\n"), 0600))
+	require.NoError(t, verifyCachedReleaseBinary(binary, digestPath))
+
+	require.NoError(t, os.WriteFile(binary, []byte("planted"), 0700))
+	err = verifyCachedReleaseBinary(binary, digestPath)
+	require.ErrorContains(t, err, "digest mismatch", "a modified binary should be rejected")
+
+	require.NoError(t, os.WriteFile(digestPath, nil, 0600))
+	err = verifyCachedReleaseBinary(binary, digestPath)
+	require.ErrorContains(t, err, "no verified digest")
+}
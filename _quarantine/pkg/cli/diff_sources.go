@@ -0,0 +1,339 @@
+// This file compiles the base side of a workflow diff.
+//
+// The base can be the workflow sources at another git ref, compiled with the current
+// gh-aw version, or the current sources compiled with another gh-aw release. In both
+// cases the compilation happens in a temporary copy of the repository's .github tree so
+// that imports resolve the same way they do for the working tree.
+
+package cli
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/cli/fileutil"
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var diffSourcesLog = logger.New("cli:diff_sources")
+
+// compileWorkflowAtRef compiles the workflow as it exists at a git ref with the current
+// gh-aw version. Returns false when the workflow does not exist at the ref.
+func compileWorkflowAtRef(markdownPath, ref string, config DiffConfig) (string, bool, error) {
+	diffSourcesLog.Printf("Compiling %s at ref %s", markdownPath, ref)
+
+	gitRoot, relPath, err := splitRepositoryPath(markdownPath)
+	if err != nil {
+		return "", false, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gh-aw-diff-*")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	topDir := strings.SplitN(filepath.ToSlash(relPath), "/", 2)[0]
+	if err := extractGitTree(gitRoot, ref, topDir, tmpDir); err != nil {
+		return "", false, err
+	}
+
+	baseMarkdown := filepath.Join(tmpDir, relPath)
+	if _, err := os.Stat(baseMarkdown); os.IsNotExist(err) {
+		diffSourcesLog.Printf("Workflow %s does not exist at %s", relPath, ref)
+		return "", false, nil
+	}
+
+	compiler := createAndConfigureCompiler(CompileConfig{
+		NoEmit:     true,
+		ActionMode: config.ActionMode,
+		Verbose:    config.Verbose,
+	})
+	compiler.SetQuiet(true)
+	// Use the working tree path for the per-file context so both sides share the same
+	// workflow identifier and repository slug
+	configureCompilerForFile(compiler, markdownPath)
+
+	_, lockContent, err := compiler.CompileToYAML(baseMarkdown)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to compile %s at %s: %w", relPath, ref, err)
+	}
+	return lockContent, true, nil
+}
+
+// splitRepositoryPath returns the git root containing a file and the file's path relative to it
+func splitRepositoryPath(path string) (string, string, error) {
+	gitRoot, err := findGitRootForPath(path)
+	if err != nil {
+		return "", "", fmt.Errorf("diff requires the workflow to be in a git repository: %w", err)
+	}
+	// Resolve symlinks on both sides so the relative path is computed consistently
+	resolvedRoot, err := filepath.EvalSymlinks(gitRoot)
+	if err != nil {
+		resolvedRoot = gitRoot
+	}
+	resolvedPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		resolvedPath = path
+	}
+	relPath, err := filepath.Rel(resolvedRoot, resolvedPath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return "", "", fmt.Errorf("workflow %s is not inside repository %s", path, gitRoot)
+	}
+	return resolvedRoot, relPath, nil
+}
+
+// extractGitTree writes the files under a repository directory at a git ref to destDir
+func extractGitTree(gitRoot, ref, treePath, destDir string) error {
+	cmd := exec.Command("git", "-C", gitRoot, "archive", "--format=tar", ref, "--", treePath)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to read %s at %s: %w", treePath, ref, err)
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to read %s at %s: %w", treePath, ref, err)
+	}
+
+	extractErr := extractTar(stdout, destDir)
+	// Drain the remaining output so git can exit if extraction stopped early
+	_, _ = io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if strings.Contains(message, "did not match any files") {
+			// The directory does not exist at the ref
+			return nil
+		}
+		if message == "" {
+			message = err.Error()
+		}
+		return fmt.Errorf("failed to read %s at %s: %s", treePath, ref, message)
+	}
+	return extractErr
+}
+
+// extractTar extracts the regular files of a tar stream into destDir
+func extractTar(r io.Reader, destDir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		target := filepath.Join(destDir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(destDir)+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path in archive: %s", header.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", header.Name, err)
+		}
+		_, copyErr := io.Copy(file, tr)
+		closeErr := file.Close()
+		if copyErr != nil {
+			return fmt.Errorf("failed to write %s: %w", header.Name, copyErr)
+		}
+		if closeErr != nil {
+			return fmt.Errorf("failed to write %s: %w", header.Name, closeErr)
+		}
+	}
+}
+
+// compileWorkflowWithRelease compiles the current workflow sources with another gh-aw release
+func compileWorkflowWithRelease(markdownPath, version string, config DiffConfig) (string, error) {
+	diffSourcesLog.Printf("Compiling %s with gh-aw %s", markdownPath, version)
+
+	binary, err := ensureReleaseBinary(version, config.Verbose)
+	if err != nil {
+		return "", err
+	}
+
+	gitRoot, relPath, err := splitRepositoryPath(markdownPath)
+	if err != nil {
+		return "", err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gh-aw-diff-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	topDir := strings.SplitN(filepath.ToSlash(relPath), "/", 2)[0]
+	if err := copyTree(filepath.Join(gitRoot, topDir), filepath.Join(tmpDir, topDir)); err != nil {
+		return "", fmt.Errorf("failed to copy workflow sources: %w", err)
+	}
+
+	// The compile command needs a git repository to locate the workflows
+	if output, err := exec.Command("git", "-C", tmpDir, "init", "-q").CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to initialize temporary repository: %s", strings.TrimSpace(string(output)))
+	}
+
+	baseMarkdown := filepath.Join(tmpDir, relPath)
+	cmd := exec.Command(binary, "compile", baseMarkdown)
+	cmd.Dir = tmpDir
+	// Running as CI disables the update check and interactive output of the other release
+	cmd.Env = append(os.Environ(), "CI=true")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("gh-aw %s failed to compile %s: %s", version, relPath, strings.TrimSpace(string(output)))
+	}
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(baseMarkdown))
+	if err != nil {
+		return "", fmt.Errorf("gh-aw %s did not produce a lock file for %s: %w", version, relPath, err)
+	}
+	return string(lockContent), nil
+}
+
+// releaseRepository is the repository whose release workflow builds and attests the gh-aw binaries
+const releaseRepository = "github/gh-aw"
+
+// verifiedDigestFile records the SHA-256 of a cached release binary whose attestation was verified
+const verifiedDigestFile = "verified.sha256"
+
+// ensureReleaseBinary downloads the gh-aw binary of a release for the current platform,
+// reusing a previously downloaded copy. Downloaded binaries are only run once their build
+// provenance attestation verifies as produced by the gh-aw repository. Binaries are cached
+// in a private directory under the user cache directory together with the digest of the
+// verified download, and a cached binary is only reused while it still matches that digest.
+func ensureReleaseBinary(version string, verbose bool) (string, error) {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	platform := runtime.GOOS + "-" + runtime.GOARCH
+	if runtime.GOOS == "windows" {
+		platform += ".exe"
+	}
+
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	cacheDir := filepath.Join(userCacheDir, "gh-aw", "releases", version)
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create release cache directory: %w", err)
+	}
+	// MkdirAll leaves existing directories alone, so tighten them explicitly
+	if err := os.Chmod(cacheDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to secure release cache directory: %w", err)
+	}
+
+	binary := filepath.Join(cacheDir, platform)
+	digestPath := filepath.Join(cacheDir, verifiedDigestFile)
+	if fileutil.FileExists(binary) && fileutil.FileExists(digestPath) {
+		err := verifyCachedReleaseBinary(binary, digestPath)
+		if err == nil {
+			diffSourcesLog.Printf("Using cached gh-aw %s binary: %s", version, binary)
+			console.LogVerbose(verbose, fmt.Sprintf("Using cached gh-aw %s binary: %s", version, binary))
+			return binary, nil
+		}
+		diffSourcesLog.Printf("Discarding cached gh-aw %s binary: %v", version, err)
+		console.LogVerbose(verbose, fmt.Sprintf("Discarding cached gh-aw %s binary: %v", version, err))
+	}
+	// Forget the previous verification before replacing the binary
+	_ = os.Remove(digestPath)
+
+	output, err := workflow.RunGHCombined(fmt.Sprintf("Downloading gh-aw %s...", version),
+		"release", "download", version, "--repo", releaseRepository,
+		"--pattern", platform, "--dir", cacheDir, "--clobber")
+	if err != nil {
+		return "", fmt.Errorf("failed to download gh-aw %s for %s: %s", version, platform, strings.TrimSpace(string(output)))
+	}
+	output, err = workflow.RunGHCombined(fmt.Sprintf("Verifying gh-aw %s attestation...", version),
+		"attestation", "verify", binary, "--repo", releaseRepository)
+	if err != nil {
+		_ = os.Remove(binary)
+		return "", fmt.Errorf("refusing to run downloaded gh-aw %s: attestation verification failed: %s", version, strings.TrimSpace(string(output)))
+	}
+	digest, err := fileSHA256(binary)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(digestPath, []byte(digest+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to record verified gh-aw %s digest: %w", version, err)
+	}
+	if err := os.Chmod(binary, 0700); err != nil {
+		return "", fmt.Errorf("failed to make gh-aw %s executable: %w", version, err)
+	}
+	return binary, nil
+}
+
+// verifyCachedReleaseBinary checks that a cached release binary still has the SHA-256
+// recorded when its attestation was verified
+func verifyCachedReleaseBinary(binary, digestPath string) error {
+	recorded, err := os.ReadFile(digestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read verified digest: %w", err)
+	}
+	expected := strings.ToLower(strings.TrimSpace(string(recorded)))
+	if expected == "" {
+		return fmt.Errorf("no verified digest recorded for %s", filepath.Base(binary))
+	}
+	actual, err := fileSHA256(binary)
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return fmt.Errorf("digest mismatch for %s: expected %s, got %s", filepath.Base(binary), expected, actual)
+	}
+	return nil
+}
+
+// fileSHA256 returns the hex-encoded SHA-256 of a file
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// copyTree copies the regular files under srcDir to dstDir
+func copyTree(srcDir, dstDir string) error {
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dstDir, relPath)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return fileutil.CopyFile(path, target)
+	})
+}
@@ -0,0 +1,368 @@
+// This file extracts the security-relevant structure of a compiled workflow and compares
+// two versions of it for the diff command.
+//
+// The structure is read from the compiled lock file rather than from the parsed workflow
+// data so that lock files produced by other gh-aw releases can be compared as well. It
+// captures jobs and their effective permissions, the network allow-list, action pins,
+// referenced secrets, safe-output configuration and MCP servers.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/sliceutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+var diffStructureLog = logger.New("cli:diff_structure")
+
+var (
+	// usesPattern matches step 'uses:' references with an optional version comment
+	usesPattern = regexp.MustCompile(`(?m)^\s*(?:-\s+)?uses:\s+([^@\s]+)@(\S+)(?:\s+#\s*(\S+))?`)
+	// allowedDomainsEnvPattern matches the allowed domains passed to output sanitization
+	allowedDomainsEnvPattern = regexp.MustCompile(`GH_AW_ALLOWED_DOMAINS:\s+"([^"]*)"`)
+	// allowDomainsFlagPattern matches the firewall --allow-domains argument
+	allowDomainsFlagPattern = regexp.MustCompile(`--allow-domains\s+'?([^'\s]+)'?`)
+	// mcpServersJSONPattern matches the start of a JSON MCP configuration
+	mcpServersJSONPattern = regexp.MustCompile(`^(\s*)"mcpServers":\s*\{`)
+	// mcpServerTOMLPattern matches an MCP server table in a TOML MCP configuration
+	mcpServerTOMLPattern = regexp.MustCompile(`^\s*\[mcp_servers\."?([^"\]]+)"?\]`)
+)
+
+// safeOutputsConfigMarker precedes the safe-outputs configuration JSON in the lock file
+const safeOutputsConfigMarker = "cat > /opt/gh-aw/safeoutputs/config.json << 'EOF'"
+
+// compiledWorkflowStructure is the security-relevant structure of a compiled workflow
+type compiledWorkflowStructure struct {
+	// JobPermissions maps each job to its effective permissions (scope -> level)
+	JobPermissions map[string]map[string]string
+	Domains        []string
+	// Actions maps each action to its pinned reference
+	Actions     map[string]string
+	Secrets     []string
+	SafeOutputs map[string]map[string]any
+	MCPServers  []string
+}
+
+// extractWorkflowStructure reads the structure of a compiled workflow from its lock file content
+func extractWorkflowStructure(lockContent string) (*compiledWorkflowStructure, error) {
+	var lock map[string]any
+	if err := yaml.Unmarshal([]byte(lockContent), &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse compiled workflow: %w", err)
+	}
+
+	structure := &compiledWorkflowStructure{
+		JobPermissions: make(map[string]map[string]string),
+		Actions:        make(map[string]string),
+		SafeOutputs:    make(map[string]map[string]any),
+		Secrets:        workflow.CollectSecretReferences(lockContent),
+		Domains:        extractAllowedDomains(lockContent),
+		MCPServers:     extractMCPServers(lockContent),
+	}
+
+	// Jobs without their own permissions inherit the workflow-level permissions
+	workflowPermissions, hasWorkflowPermissions := lock["permissions"]
+	if jobs, ok := lock["jobs"].(map[string]any); ok {
+		for jobID, job := range jobs {
+			permissions := workflowPermissions
+			hasPermissions := hasWorkflowPermissions
+			if jobMap, ok := job.(map[string]any); ok {
+				if jobPermissions, exists := jobMap["permissions"]; exists {
+					permissions, hasPermissions = jobPermissions, true
+				}
+			}
+			structure.JobPermissions[jobID] = effectivePermissionLevels(permissions, hasPermissions)
+		}
+	}
+
+	for _, match := range usesPattern.FindAllStringSubmatch(lockContent, -1) {
+		ref := match[2]
+		if match[3] != "" {
+			ref += " (" + match[3] + ")"
+		}
+		structure.Actions[match[1]] = ref
+	}
+
+	safeOutputs, err := extractSafeOutputsConfig(lockContent)
+	if err != nil {
+		return nil, err
+	}
+	if safeOutputs != nil {
+		structure.SafeOutputs = safeOutputs
+	}
+
+	diffStructureLog.Printf("Extracted structure: jobs=%d, domains=%d, actions=%d, secrets=%d, safe-outputs=%d, mcp-servers=%d",
+		len(structure.JobPermissions), len(structure.Domains), len(structure.Actions), len(structure.Secrets),
+		len(structure.SafeOutputs), len(structure.MCPServers))
+	return structure, nil
+}
+
+// effectivePermissionLevels expands a permissions value into explicit levels per scope
+// using the Permissions type. Scopes without access are omitted.
+func effectivePermissionLevels(value any, present bool) map[string]string {
+	levels := make(map[string]string)
+	if !present {
+		return levels
+	}
+	permissions := workflow.NewPermissionsParserFromValue(value).ToPermissions()
+	for _, scope := range workflow.GetAllPermissionScopes() {
+		if level, ok := permissions.Get(scope); ok && level != workflow.PermissionNone {
+			levels[string(scope)] = string(level)
+		}
+	}
+	return levels
+}
+
+// extractAllowedDomains returns the network allow-list enforced by the compiled workflow
+func extractAllowedDomains(lockContent string) []string {
+	domainSet := make(map[string]bool)
+	var lists []string
+	for _, match := range allowedDomainsEnvPattern.FindAllStringSubmatch(lockContent, -1) {
+		lists = append(lists, match[1])
+	}
+	for _, match := range allowDomainsFlagPattern.FindAllStringSubmatch(lockContent, -1) {
+		lists = append(lists, match[1])
+	}
+	for _, list := range lists {
+		for domain := range strings.SplitSeq(list, ",") {
+			if domain = strings.TrimSpace(domain); domain != "" {
+				domainSet[domain] = true
+			}
+		}
+	}
+	return sortedKeys(domainSet)
+}
+
+// extractMCPServers returns the MCP servers configured for the agent, from either the JSON
+// ("mcpServers") or the TOML ([mcp_servers.<name>]) configuration format
+func extractMCPServers(lockContent string) []string {
+	servers := make(map[string]bool)
+	lines := strings.Split(lockContent, "\n")
+	for i := 0; i < len(lines); i++ {
+		if match := mcpServerTOMLPattern.FindStringSubmatch(lines[i]); match != nil {
+			// Nested tables such as [mcp_servers.github.env] belong to their server
+			servers[strings.SplitN(match[1], ".", 2)[0]] = true
+			continue
+		}
+		match := mcpServersJSONPattern.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		serverIndent := match[1] + "  "
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if !strings.HasPrefix(line, serverIndent) {
+				break
+			}
+			rest := line[len(serverIndent):]
+			if strings.HasPrefix(rest, `"`) {
+				if end := strings.Index(rest[1:], `"`); end >= 0 {
+					servers[rest[1:end+1]] = true
+				}
+			}
+		}
+	}
+	return sortedKeys(servers)
+}
+
+// extractSafeOutputsConfig returns the safe-outputs configuration written by the agent job
+func extractSafeOutputsConfig(lockContent string) (map[string]map[string]any, error) {
+	_, after, found := strings.Cut(lockContent, safeOutputsConfigMarker+"\n")
+	if !found {
+		return nil, nil
+	}
+	configLine, _, _ := strings.Cut(after, "\n")
+
+	var config map[string]map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(configLine)), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse safe-outputs configuration: %w", err)
+	}
+	return config, nil
+}
+
+// WorkflowStructureDiff describes the structural changes between two compiled versions of a workflow
+type WorkflowStructureDiff struct {
+	JobsAdded          []string           `json:"jobs_added,omitempty"`
+	JobsRemoved        []string           `json:"jobs_removed,omitempty"`
+	PermissionChanges  []PermissionChange `json:"permission_changes,omitempty"`
+	DomainsAdded       []string           `json:"domains_added,omitempty"`
+	DomainsRemoved     []string           `json:"domains_removed,omitempty"`
+	ActionChanges      []ValueChange      `json:"action_changes,omitempty"`
+	SecretsAdded       []string           `json:"secrets_added,omitempty"`
+	SecretsRemoved     []string           `json:"secrets_removed,omitempty"`
+	SafeOutputChanges  []ValueChange      `json:"safe_output_changes,omitempty"`
+	MCPServersAdded    []string           `json:"mcp_servers_added,omitempty"`
+	MCPServersRemoved  []string           `json:"mcp_servers_removed,omitempty"`
+	BaseMissing        bool               `json:"base_missing,omitempty"`
+	PermissionsWidened bool               `json:"permissions_widened"`
+}
+
+// PermissionChange is a change of a job's permission level for one scope.
+// An empty level means the job has no access to the scope.
+type PermissionChange struct {
+	Job    string `json:"job"`
+	Scope  string `json:"scope"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// ValueChange is a change of a named value. An empty Before means the value was added,
+// an empty After means it was removed.
+type ValueChange struct {
+	Name   string `json:"name"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// HasChanges returns true when the diff contains any structural change
+func (d *WorkflowStructureDiff) HasChanges() bool {
+	return len(d.JobsAdded) > 0 || len(d.JobsRemoved) > 0 || len(d.PermissionChanges) > 0 ||
+		len(d.DomainsAdded) > 0 || len(d.DomainsRemoved) > 0 || len(d.ActionChanges) > 0 ||
+		len(d.SecretsAdded) > 0 || len(d.SecretsRemoved) > 0 || len(d.SafeOutputChanges) > 0 ||
+		len(d.MCPServersAdded) > 0 || len(d.MCPServersRemoved) > 0
+}
+
+// diffWorkflowStructures compares the base and head structure of a workflow. A nil base
+// means the workflow does not exist on the base side.
+func diffWorkflowStructures(base, head *compiledWorkflowStructure) *WorkflowStructureDiff {
+	diff := &WorkflowStructureDiff{}
+	if base == nil {
+		diff.BaseMissing = true
+		base = &compiledWorkflowStructure{}
+	}
+
+	diff.JobsAdded, diff.JobsRemoved = diffStringSets(sortedKeys(base.JobPermissions), sortedKeys(head.JobPermissions))
+	for _, job := range unionKeys(base.JobPermissions, head.JobPermissions) {
+		before, after := base.JobPermissions[job], head.JobPermissions[job]
+		for _, scope := range unionKeys(before, after) {
+			if before[scope] == after[scope] {
+				continue
+			}
+			diff.PermissionChanges = append(diff.PermissionChanges, PermissionChange{
+				Job: job, Scope: scope, Before: before[scope], After: after[scope],
+			})
+			if permissionRank(after[scope]) > permissionRank(before[scope]) {
+				diff.PermissionsWidened = true
+			}
+		}
+	}
+
+	diff.DomainsAdded, diff.DomainsRemoved = diffStringSets(base.Domains, head.Domains)
+	diff.SecretsAdded, diff.SecretsRemoved = diffStringSets(base.Secrets, head.Secrets)
+	diff.MCPServersAdded, diff.MCPServersRemoved = diffStringSets(base.MCPServers, head.MCPServers)
+
+	for _, action := range unionKeys(base.Actions, head.Actions) {
+		if before, after := base.Actions[action], head.Actions[action]; before != after {
+			diff.ActionChanges = append(diff.ActionChanges, ValueChange{Name: action, Before: before, After: after})
+		}
+	}
+
+	for _, outputType := range unionKeys(base.SafeOutputs, head.SafeOutputs) {
+		before, beforeExists := base.SafeOutputs[outputType]
+		after, afterExists := head.SafeOutputs[outputType]
+		switch {
+		case !beforeExists:
+			diff.SafeOutputChanges = append(diff.SafeOutputChanges, ValueChange{Name: outputType, After: formatSafeOutputConfig(after)})
+		case !afterExists:
+			diff.SafeOutputChanges = append(diff.SafeOutputChanges, ValueChange{Name: outputType, Before: formatSafeOutputConfig(before)})
+		default:
+			for _, field := range unionKeys(before, after) {
+				beforeValue, afterValue := formatConfigValue(before[field]), formatConfigValue(after[field])
+				if beforeValue != afterValue {
+					diff.SafeOutputChanges = append(diff.SafeOutputChanges, ValueChange{
+						Name: outputType + "." + field, Before: beforeValue, After: afterValue,
+					})
+				}
+			}
+		}
+	}
+
+	return diff
+}
+
+// permissionRank orders permission levels so that widening can be detected
+func permissionRank(level string) int {
+	switch level {
+	case string(workflow.PermissionWrite):
+		return 2
+	case string(workflow.PermissionRead):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// formatSafeOutputConfig formats a safe-output configuration for display
+func formatSafeOutputConfig(config map[string]any) string {
+	if len(config) == 0 {
+		return "enabled"
+	}
+	parts := make([]string, 0, len(config))
+	for _, field := range sortedKeys(config) {
+		parts = append(parts, field+"="+formatConfigValue(config[field]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatConfigValue formats a configuration value for display and comparison
+func formatConfigValue(value any) string {
+	if value == nil {
+		return ""
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprint(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// diffStringSets returns the values added to and removed from a set
+func diffStringSets(before, after []string) (added, removed []string) {
+	for _, value := range after {
+		if !slices.Contains(before, value) {
+			added = append(added, value)
+		}
+	}
+	for _, value := range before {
+		if !slices.Contains(after, value) {
+			removed = append(removed, value)
+		}
+	}
+	return added, removed
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := sliceutil.MapToSlice(m)
+	sort.Strings(keys)
+	return keys
+}
+
+// unionKeys returns the sorted union of the keys of two maps
+func unionKeys[V any](a, b map[string]V) []string {
+	set := make(map[string]bool, len(a)+len(b))
+	for key := range a {
+		set[key] = true
+	}
+	for key := range b {
+		set[key] = true
+	}
+	return sortedKeys(set)
+}
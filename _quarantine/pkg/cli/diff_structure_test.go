@@ -0,0 +1,155 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const diffStructureTestLock = `name: "test"
+on:
+  workflow_dispatch:
+permissions: {}
+jobs:
+  activation:
+    runs-on: ubuntu-slim
+    permissions:
+      contents: read
+    steps:
+      - name: Checkout
+        uses: actions/checkout@de0fac2e4500dabe0009e67214ff5f5447ce83dd # v6.0.2
+      - name: Setup
+        uses: ./actions/setup
+  agent:
+    runs-on: ubuntu-latest
+    permissions: read-all
+    steps:
+      - name: Write safe outputs config
+        run: |
+          mkdir -p /opt/gh-aw/safeoutputs
+          cat > /opt/gh-aw/safeoutputs/config.json << 'EOF'
+          {"create_issue":{"max":2},"missing_tool":{}}
+          EOF
+      - name: Setup MCPs
+        run: |
+          cat << EOF | bash /opt/gh-aw/actions/start_mcp_gateway.sh
+          {
+            "mcpServers": {
+              "github": {
+                "type": "stdio",
+                "env": {
+                  "GITHUB_TOKEN": "x"
+                }
+              },
+              "safeoutputs": {
+                "type": "http"
+              }
+            }
+          }
+          EOF
+      - name: Run agent
+        run: |
+          awf --allow-domains 'api.github.com,github.com' -- copilot
+        env:
+          COPILOT_GITHUB_TOKEN: ${{ secrets.COPILOT_GITHUB_TOKEN }}
+  conclusion:
+    runs-on: ubuntu-slim
+    steps:
+      - name: Sanitize
+        uses: actions/github-script@ed597411d8f924073f98dfc5c65a23a2325f34cd # v8
+        env:
+          GH_AW_ALLOWED_DOMAINS: "github.com,raw.githubusercontent.com"
+          GITHUB_TOKEN: ${{ secrets.GH_AW_GITHUB_TOKEN || secrets.GITHUB_TOKEN }}
+`
+
+func TestExtractWorkflowStructure(t *testing.T) {
+	structure, err := extractWorkflowStructure(diffStructureTestLock)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"contents": "read"}, structure.JobPermissions["activation"])
+	assert.Equal(t, "read", structure.JobPermissions["agent"]["issues"], "read-all should expand to every scope")
+	assert.Empty(t, structure.JobPermissions["conclusion"], "jobs inherit the empty workflow permissions")
+
+	assert.Equal(t, []string{"api.github.com", "github.com", "raw.githubusercontent.com"}, structure.Domains)
+	assert.Equal(t, map[string]string{
+		"actions/checkout":      "de0fac2e4500dabe0009e67214ff5f5447ce83dd (v6.0.2)",
+		"actions/github-script": "ed597411d8f924073f98dfc5c65a23a2325f34cd (v8)",
+	}, structure.Actions)
+	assert.Equal(t, []string{"COPILOT_GITHUB_TOKEN", "GH_AW_GITHUB_TOKEN", "GITHUB_TOKEN"}, structure.Secrets)
+	assert.Equal(t, []string{"github", "safeoutputs"}, structure.MCPServers)
+	assert.Equal(t, map[string]map[string]any{
+		"create_issue": {"max": float64(2)},
+		"missing_tool": {},
+	}, structure.SafeOutputs)
+}
+
+func TestExtractMCPServersTOML(t *testing.T) {
+	content := "[mcp_servers.github]\ncommand = \"docker\"\n[mcp_servers.github.env]\nX = \"1\"\n[mcp_servers.\"safe-outputs\"]\n"
+	assert.Equal(t, []string{"github", "safe-outputs"}, extractMCPServers(content))
+}
+
+func TestDiffWorkflowStructures(t *testing.T) {
+	base := &compiledWorkflowStructure{
+		JobPermissions: map[string]map[string]string{
+			"agent":      {"contents": "read", "issues": "write"},
+			"conclusion": {"issues": "write"},
+		},
+		Domains:     []string{"github.com"},
+		Actions:     map[string]string{"actions/checkout": "aaa (v5)", "actions/cache": "ccc (v4)"},
+		Secrets:     []string{"GITHUB_TOKEN"},
+		SafeOutputs: map[string]map[string]any{"create_issue": {"max": float64(1)}, "add_comment": {}},
+		MCPServers:  []string{"github"},
+	}
+	head := &compiledWorkflowStructure{
+		JobPermissions: map[string]map[string]string{
+			"agent":     {"contents": "write"},
+			"safe_jobs": {},
+		},
+		Domains:     []string{"example.com", "github.com"},
+		Actions:     map[string]string{"actions/checkout": "bbb (v6)"},
+		Secrets:     []string{"API_KEY", "GITHUB_TOKEN"},
+		SafeOutputs: map[string]map[string]any{"create_issue": {"max": float64(3)}, "add_labels": {"allowed": []any{"bug"}}},
+		MCPServers:  []string{"github", "tavily"},
+	}
+
+	diff := diffWorkflowStructures(base, head)
+	assert.True(t, diff.HasChanges())
+	assert.False(t, diff.BaseMissing)
+	assert.Equal(t, []string{"safe_jobs"}, diff.JobsAdded)
+	assert.Equal(t, []string{"conclusion"}, diff.JobsRemoved)
+	assert.Equal(t, []PermissionChange{
+		{Job: "agent", Scope: "contents", Before: "read", After: "write"},
+		{Job: "agent", Scope: "issues", Before: "write"},
+		{Job: "conclusion", Scope: "issues", Before: "write"},
+	}, diff.PermissionChanges)
+	assert.True(t, diff.PermissionsWidened)
+	assert.Equal(t, []string{"example.com"}, diff.DomainsAdded)
+	assert.Empty(t, diff.DomainsRemoved)
+	assert.Equal(t, []ValueChange{
+		{Name: "actions/cache", Before: "ccc (v4)"},
+		{Name: "actions/checkout", Before: "aaa (v5)", After: "bbb (v6)"},
+	}, diff.ActionChanges)
+	assert.Equal(t, []string{"API_KEY"}, diff.SecretsAdded)
+	assert.Equal(t, []ValueChange{
+		{Name: "add_comment", Before: "enabled"},
+		{Name: "add_labels", After: `allowed=["bug"]`},
+		{Name: "create_issue.max", Before: "1", After: "3"},
+	}, diff.SafeOutputChanges)
+	assert.Equal(t, []string{"tavily"}, diff.MCPServersAdded)
+
+	t.Run("no changes", func(t *testing.T) {
+		diff := diffWorkflowStructures(head, head)
+		assert.False(t, diff.HasChanges())
+		assert.False(t, diff.PermissionsWidened)
+	})
+
+	t.Run("missing base", func(t *testing.T) {
+		diff := diffWorkflowStructures(nil, head)
+		assert.True(t, diff.BaseMissing)
+		assert.Equal(t, []string{"agent", "safe_jobs"}, diff.JobsAdded)
+		assert.True(t, diff.PermissionsWidened)
+	})
+}
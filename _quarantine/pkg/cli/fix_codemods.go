@@ -6,6 +6,7 @@ type Codemod struct {
 	Name         string // Human-readable name
 	Description  string // Description of what the codemod does
 	IntroducedIn string // Version where this codemod was introduced
+	Source       string // File a user-defined codemod was loaded from (empty for built-in codemods)
 	Apply        func(content string, frontmatter map[string]any) (string, bool, error)
 }
 
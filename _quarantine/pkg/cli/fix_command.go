@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	udiff "github.com/aymanbagabas/go-udiff"
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
@@ -20,12 +21,14 @@ type FixConfig struct {
 	WorkflowIDs []string
 	Write       bool
 	Verbose     bool
-	WorkflowDir string // Custom workflow directory
+	WorkflowDir string   // Custom workflow directory
+	CodemodIDs  []string // Only apply these codemods (empty for all)
+	DryRun      bool     // Show a diff of the changes without writing files
 }
 
 // RunFix runs the fix command with the given configuration
 func RunFix(config FixConfig) error {
-	return runFixCommand(config.WorkflowIDs, config.Write, config.Verbose, config.WorkflowDir)
+	return runFixCommand(config)
 }
 
 // NewFixCommand creates the fix command
@@ -48,6 +51,20 @@ Available codemods:
   • delete-old-agents: Deletes old .agent.md files moved to .github/aw/
   • delete-old-templates: Removes old template files from pkg/cli/templates/
 
+Repository-specific codemods can be declared as YAML files in .github/aw/codemods/.
+Each file matches workflows on JSON paths and conditions, then applies rename, move,
+set, delete or wrap actions to the frontmatter, keeping comments and key order:
+
+  id: house-timeout
+  name: Always set timeout-minutes
+  match:
+    conditions:
+      - path: /timeout-minutes
+        exists: false
+  actions:
+    - set: {path: /timeout-minutes, value: 20}
+    - rename: {path: /mcp-servers/legacy-search, to: search}
+
 If no workflows are specified, all Markdown files in .github/workflows will be processed.
 
 The command will:
@@ -68,24 +85,38 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` fix my-workflow         # Check specific workflow
   ` + string(constants.CLIExtensionPrefix) + ` fix my-workflow --write # Fix specific workflow
   ` + string(constants.CLIExtensionPrefix) + ` fix --dir custom/workflows # Fix workflows in custom directory
+  ` + string(constants.CLIExtensionPrefix) + ` fix --dry-run           # Show a diff of the changes
+  ` + string(constants.CLIExtensionPrefix) + ` fix --codemod house-timeout --write # Apply only one codemod
   ` + string(constants.CLIExtensionPrefix) + ` fix --list-codemods     # List available codemods`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			listCodemods, _ := cmd.Flags().GetBool("list-codemods")
 			write, _ := cmd.Flags().GetBool("write")
 			verbose, _ := cmd.Flags().GetBool("verbose")
 			dir, _ := cmd.Flags().GetString("dir")
+			codemodIDs, _ := cmd.Flags().GetStringSlice("codemod")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
 
 			if listCodemods {
 				return listAvailableCodemods()
 			}
 
-			return runFixCommand(args, write, verbose, dir)
+			return runFixCommand(FixConfig{
+				WorkflowIDs: args,
+				Write:       write,
+				Verbose:     verbose,
+				WorkflowDir: dir,
+				CodemodIDs:  codemodIDs,
+				DryRun:      dryRun,
+			})
 		},
 	}
 
 	cmd.Flags().Bool("write", false, "Write changes to files (default is dry-run)")
+	cmd.Flags().Bool("dry-run", false, "Show a unified diff of the changes without writing files")
+	cmd.Flags().StringSlice("codemod", nil, "Only apply the codemods with these IDs (repeatable)")
 	cmd.Flags().Bool("list-codemods", false, "List all available codemods and exit")
 	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	cmd.MarkFlagsMutuallyExclusive("write", "dry-run")
 
 	// Register completions
 	cmd.ValidArgsFunction = CompleteWorkflowNames
@@ -96,7 +127,11 @@ Examples:
 
 // listAvailableCodemods lists all available codemods
 func listAvailableCodemods() error {
-	codemods := GetAllCodemods()
+	userCodemods, err := loadUserCodemods(userCodemodsDir)
+	if err != nil {
+		return err
+	}
+	codemods := append(GetAllCodemods(), userCodemods...)
 
 	fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Available Codemods:"))
 	fmt.Fprintln(os.Stderr, "")
@@ -107,6 +142,9 @@ func listAvailableCodemods() error {
 		if codemod.IntroducedIn != "" {
 			fmt.Fprintf(os.Stderr, "    Introduced in: %s\n", codemod.IntroducedIn)
 		}
+		if codemod.Source != "" {
+			fmt.Fprintf(os.Stderr, "    Source: %s\n", codemod.Source)
+		}
 		fmt.Fprintf(os.Stderr, "    %s\n", codemod.Description)
 		fmt.Fprintln(os.Stderr, "")
 	}
@@ -115,8 +153,10 @@ func listAvailableCodemods() error {
 }
 
 // runFixCommand runs the fix command on specified or all workflows
-func runFixCommand(workflowIDs []string, write bool, verbose bool, workflowDir string) error {
-	fixLog.Printf("Running fix command: workflowIDs=%v, write=%v, verbose=%v, workflowDir=%s", workflowIDs, write, verbose, workflowDir)
+func runFixCommand(config FixConfig) error {
+	workflowIDs, write, verbose, workflowDir := config.WorkflowIDs, config.Write, config.Verbose, config.WorkflowDir
+	fixLog.Printf("Running fix command: workflowIDs=%v, write=%v, verbose=%v, workflowDir=%s, codemods=%v, dryRun=%v",
+		workflowIDs, write, verbose, workflowDir, config.CodemodIDs, config.DryRun)
 
 	// Set up workflow directory (using default if not specified)
 	if workflowDir == "" {
@@ -153,9 +193,16 @@ func runFixCommand(workflowIDs []string, write bool, verbose bool, workflowDir s
 		return nil
 	}
 
-	// Load all codemods
-	codemods := GetAllCodemods()
-	fixLog.Printf("Loaded %d codemods", len(codemods))
+	// Load built-in and repository codemods
+	userCodemods, err := loadUserCodemods(userCodemodsDir)
+	if err != nil {
+		return err
+	}
+	codemods, err := selectCodemods(append(GetAllCodemods(), userCodemods...), config.CodemodIDs)
+	if err != nil {
+		return err
+	}
+	fixLog.Printf("Loaded %d codemods (%d user-defined)", len(codemods), len(userCodemods))
 
 	// Process each file
 	var totalFixed int
@@ -165,7 +212,7 @@ func runFixCommand(workflowIDs []string, write bool, verbose bool, workflowDir s
 	for _, file := range files {
 		fixLog.Printf("Processing file: %s", file)
 
-		fixed, appliedFixes, err := processWorkflowFileWithInfo(file, codemods, write, verbose)
+		fixed, appliedFixes, err := processWorkflowFileWithInfo(file, codemods, write, config.DryRun, verbose)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%s\n", console.FormatErrorMessage(fmt.Sprintf("Error processing %s: %v", filepath.Base(file), err)))
 			continue
@@ -261,6 +308,31 @@ func runFixCommand(workflowIDs []string, write bool, verbose bool, workflowDir s
 	return nil
 }
 
+// selectCodemods returns the codemods with the given IDs, or all codemods when no IDs are given
+func selectCodemods(codemods []Codemod, ids []string) ([]Codemod, error) {
+	if len(ids) == 0 {
+		return codemods, nil
+	}
+	byID := make(map[string]Codemod, len(codemods))
+	for _, codemod := range codemods {
+		byID[codemod.ID] = codemod
+	}
+
+	selected := make([]Codemod, 0, len(ids))
+	for _, id := range ids {
+		codemod, ok := byID[id]
+		if !ok {
+			available := make([]string, 0, len(codemods))
+			for _, c := range codemods {
+				available = append(available, c.ID)
+			}
+			return nil, fmt.Errorf("unknown codemod '%s'. Available codemods: %s", id, strings.Join(available, ", "))
+		}
+		selected = append(selected, codemod)
+	}
+	return selected, nil
+}
+
 // workflowFixInfo tracks workflow files that need fixes
 type workflowFixInfo struct {
 	File  string
@@ -269,12 +341,13 @@ type workflowFixInfo struct {
 
 // processWorkflowFile processes a single workflow file with all codemods
 func processWorkflowFile(filePath string, codemods []Codemod, write bool, verbose bool) (bool, error) {
-	fixed, _, err := processWorkflowFileWithInfo(filePath, codemods, write, verbose)
+	fixed, _, err := processWorkflowFileWithInfo(filePath, codemods, write, false, verbose)
 	return fixed, err
 }
 
-// processWorkflowFileWithInfo processes a single workflow file and returns detailed fix information
-func processWorkflowFileWithInfo(filePath string, codemods []Codemod, write bool, verbose bool) (bool, []string, error) {
+// processWorkflowFileWithInfo processes a single workflow file and returns detailed fix information.
+// When showDiff is set, a unified diff of the changes is printed instead of writing the file.
+func processWorkflowFileWithInfo(filePath string, codemods []Codemod, write bool, showDiff bool, verbose bool) (bool, []string, error) {
 	fixLog.Printf("Processing workflow file: %s", filePath)
 
 	// Read the file
@@ -340,6 +413,9 @@ func processWorkflowFileWithInfo(filePath string, codemods []Codemod, write bool
 		for _, codemodName := range appliedCodemods {
 			fmt.Fprintf(os.Stderr, "    • %s\n", codemodName)
 		}
+		if showDiff {
+			fmt.Fprintln(os.Stderr, udiff.Unified(filePath, filePath+" (fixed)", originalContent, currentContent))
+		}
 	}
 
 	return true, appliedCodemods, nil
@@ -5,7 +5,7 @@ import "github.com/spf13/cobra"
 // addEngineFlag adds the --engine/-e flag to a command.
 // This flag allows overriding the AI engine type.
 func addEngineFlag(cmd *cobra.Command) {
-	cmd.Flags().StringP("engine", "e", "", "Override AI engine (claude, codex, copilot, custom)")
+	cmd.Flags().StringP("engine", "e", "", "Override AI engine (claude, codex, copilot, custom, or an engine manifest id)")
 }
 
 // addEngineFilterFlag adds the --engine/-e flag to a command for filtering.
@@ -1,12 +1,14 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/sliceutil"
 	"github.com/github/gh-aw/pkg/workflow"
 )
 
@@ -453,3 +455,179 @@ func SetFieldInOnTrigger(content, fieldName, fieldValue string) (string, error)
 	frontmatterEditorLog.Printf("No raw frontmatter lines available")
 	return "", fmt.Errorf("no frontmatter lines available to modify")
 }
+
+// AddNetworkAllowedEntries adds entries to network.allowed in the frontmatter while preserving
+// the formatting of the rest of the frontmatter. Entries that are already listed are skipped.
+//
+// When network is not configured, or set to "defaults", the resulting allow-list keeps the
+// "defaults" ecosystem so that adding entries never narrows the network access of the workflow.
+func AddNetworkAllowedEntries(content string, entries []string) (string, error) {
+	frontmatterEditorLog.Printf("Adding %d entries to network.allowed", len(entries))
+
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		frontmatterEditorLog.Printf("Failed to parse frontmatter: %v", err)
+		return "", fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if len(result.FrontmatterLines) == 0 {
+		return "", errors.New("no frontmatter found, cannot set network.allowed")
+	}
+
+	lines := make([]string, len(result.FrontmatterLines))
+	copy(lines, result.FrontmatterLines)
+
+	networkIndex := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "network:") {
+			networkIndex = i
+			break
+		}
+	}
+
+	var updated []string
+	switch networkValue := result.Frontmatter["network"].(type) {
+	case nil:
+		if networkIndex != -1 {
+			return "", errors.New("network field is empty, cannot set network.allowed")
+		}
+		// No network configuration means the defaults ecosystem is allowed
+		updated = append(lines, formatNetworkAllowedBlock("  ", append([]string{"defaults"}, entries...))...)
+	case string:
+		if networkValue != "defaults" {
+			return "", fmt.Errorf("unsupported network value %q, cannot set network.allowed", networkValue)
+		}
+		block := formatNetworkAllowedBlock("  ", append([]string{"defaults"}, entries...))
+		updated = append(append(append([]string{}, lines[:networkIndex]...), block...), lines[networkIndex+1:]...)
+	case map[string]any:
+		existing, _ := networkValue["allowed"].([]any)
+		var additions []string
+		for _, entry := range entries {
+			if !sliceContainsValue(existing, entry) && !sliceutil.Contains(additions, entry) {
+				additions = append(additions, entry)
+			}
+		}
+		if len(additions) == 0 {
+			return content, nil
+		}
+		updated, err = addToNetworkAllowedLines(lines, networkIndex, additions)
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", errors.New("network field is not an object, cannot set network.allowed")
+	}
+
+	var output []string
+	output = append(output, "---")
+	output = append(output, updated...)
+	output = append(output, "---")
+	if result.Markdown != "" {
+		// Add empty line before markdown content to match original format
+		output = append(output, "")
+		output = append(output, result.Markdown)
+	}
+
+	frontmatterEditorLog.Print("Successfully updated network.allowed")
+	return strings.Join(output, "\n"), nil
+}
+
+// formatNetworkAllowedBlock renders a network block containing an allowed list
+func formatNetworkAllowedBlock(indent string, entries []string) []string {
+	block := []string{"network:", indent + "allowed:"}
+	for _, entry := range entries {
+		block = append(block, fmt.Sprintf("%s%s- %s", indent, indent, entry))
+	}
+	return block
+}
+
+// addToNetworkAllowedLines appends entries to the allowed list of an existing network block
+func addToNetworkAllowedLines(lines []string, networkIndex int, additions []string) ([]string, error) {
+	if networkIndex == -1 {
+		return nil, errors.New("network block not found in frontmatter")
+	}
+
+	// Find the end of the network block and the allowed key within it
+	childIndent := ""
+	allowedIndex := -1
+	blockEnd := len(lines)
+	for i := networkIndex + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+		if indent == "" {
+			blockEnd = i
+			break
+		}
+		if childIndent == "" {
+			childIndent = indent
+		}
+		if indent == childIndent && strings.HasPrefix(trimmed, "allowed:") {
+			allowedIndex = i
+		}
+	}
+	if childIndent == "" {
+		childIndent = "  "
+	}
+
+	insert := func(at int, newLines []string) []string {
+		result := append([]string{}, lines[:at]...)
+		result = append(result, newLines...)
+		return append(result, lines[at:]...)
+	}
+
+	if allowedIndex == -1 {
+		newLines := []string{childIndent + "allowed:"}
+		for _, entry := range additions {
+			newLines = append(newLines, fmt.Sprintf("%s%s- %s", childIndent, childIndent, entry))
+		}
+		return insert(networkIndex+1, newLines), nil
+	}
+
+	// Flow style list, e.g. allowed: [defaults, python]
+	allowedLine := lines[allowedIndex]
+	if value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(allowedLine), "allowed:")); strings.HasPrefix(value, "[") {
+		closing := strings.LastIndex(allowedLine, "]")
+		if closing == -1 {
+			return nil, errors.New("unsupported network.allowed format")
+		}
+		separator := ", "
+		if strings.TrimSpace(value) == "[]" {
+			separator = ""
+		}
+		updated := append([]string{}, lines...)
+		updated[allowedIndex] = allowedLine[:closing] + separator + strings.Join(additions, ", ") + allowedLine[closing:]
+		return updated, nil
+	}
+
+	// Block style list: insert after the last item, reusing its indentation
+	itemIndent := childIndent + childIndent
+	lastItem := allowedIndex
+	for i := allowedIndex + 1; i < blockEnd; i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "- ") {
+			break
+		}
+		itemIndent = lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+		lastItem = i
+	}
+	newLines := make([]string, 0, len(additions))
+	for _, entry := range additions {
+		newLines = append(newLines, fmt.Sprintf("%s- %s", itemIndent, entry))
+	}
+	return insert(lastItem+1, newLines), nil
+}
+
+// sliceContainsValue reports whether a YAML list contains the given string
+func sliceContainsValue(values []any, value string) bool {
+	for _, v := range values {
+		if s, ok := v.(string); ok && s == value {
+			return true
+		}
+	}
+	return false
+}
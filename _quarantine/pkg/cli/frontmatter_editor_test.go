@@ -212,3 +212,49 @@ permissions:
 		})
 	}
 }
+
+func TestAddNetworkAllowedEntries(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "no network field",
+			content:  "---\non: push\n---\n\n# Test Workflow",
+			expected: "---\non: push\nnetwork:\n  allowed:\n    - defaults\n    - python\n    - api.example.com\n---\n\n# Test Workflow",
+		},
+		{
+			name:     "network defaults",
+			content:  "---\non: push\nnetwork: defaults\nengine: copilot\n---\n\n# Test Workflow",
+			expected: "---\non: push\nnetwork:\n  allowed:\n    - defaults\n    - python\n    - api.example.com\nengine: copilot\n---\n\n# Test Workflow",
+		},
+		{
+			name:     "block style allowed list",
+			content:  "---\non: push\nnetwork:\n    allowed:\n      - defaults # base\n      - python\n    blocked:\n      - evil.com\nengine: copilot\n---\n\n# Test Workflow",
+			expected: "---\non: push\nnetwork:\n    allowed:\n      - defaults # base\n      - python\n      - api.example.com\n    blocked:\n      - evil.com\nengine: copilot\n---\n\n# Test Workflow",
+		},
+		{
+			name:     "flow style allowed list",
+			content:  "---\non: push\nnetwork:\n  allowed: [defaults]\n---\n\n# Test Workflow",
+			expected: "---\non: push\nnetwork:\n  allowed: [defaults, python, api.example.com]\n---\n\n# Test Workflow",
+		},
+		{
+			name:     "network block without allowed",
+			content:  "---\non: push\nnetwork:\n  blocked:\n    - evil.com\n---\n\n# Test Workflow",
+			expected: "---\non: push\nnetwork:\n  allowed:\n    - python\n    - api.example.com\n  blocked:\n    - evil.com\n---\n\n# Test Workflow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := AddNetworkAllowedEntries(tt.content, []string{"python", "api.example.com"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Unexpected result.\nExpected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
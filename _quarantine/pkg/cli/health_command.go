@@ -36,6 +36,7 @@ Shows health metrics for workflows including:
 - Success/failure rates over time period
 - Trend indicators (↑ improving, → stable, ↓ degrading)
 - Average execution duration
+- Budget consumption for workflows that declare a budget: section
 - Alerts when success rate drops below threshold
 
 When called without a workflow name, displays summary for all workflows.
@@ -198,6 +199,7 @@ func displayHealthSummary(runs []WorkflowRun, config HealthConfig) error {
 	workflowHealths := make([]WorkflowHealth, 0, len(groupedRuns))
 	for workflowName, workflowRuns := range groupedRuns {
 		health := CalculateWorkflowHealth(workflowName, workflowRuns, config.Threshold)
+		applyWorkflowBudget(&health, workflowRuns, config.Days)
 		workflowHealths = append(workflowHealths, health)
 	}
 
@@ -228,6 +230,7 @@ func displayDetailedHealth(runs []WorkflowRun, config HealthConfig) error {
 
 	// Calculate health metrics
 	health := CalculateWorkflowHealth(config.WorkflowName, runs, config.Threshold)
+	applyWorkflowBudget(&health, runs, config.Days)
 
 	// Output results
 	if config.JSONOutput {
@@ -259,6 +262,7 @@ func displayDetailedHealth(runs []WorkflowRun, config HealthConfig) error {
 		{"Avg Tokens", health.DisplayTokens},
 		{"Avg Cost", fmt.Sprintf("$%s", health.DisplayCost)},
 		{"Total Cost", fmt.Sprintf("$%.3f", health.TotalCost)},
+		{"Budget Used", health.DisplayBudget},
 	}
 
 	fmt.Fprint(os.Stderr, console.RenderStruct(details))
@@ -26,6 +26,8 @@ type WorkflowHealth struct {
 	TotalCost     float64       `json:"total_cost" console:"-"`
 	AvgCost       float64       `json:"avg_cost" console:"-"`
 	DisplayCost   string        `json:"-" console:"header:Avg Cost ($)"`
+	BudgetUsage   float64       `json:"budget_usage,omitempty" console:"-"`
+	DisplayBudget string        `json:"-" console:"header:Budget"`
 	BelowThresh   bool          `json:"below_threshold" console:"-"`
 }
 
@@ -73,6 +75,7 @@ func CalculateWorkflowHealth(workflowName string, runs []WorkflowRun, threshold
 			DisplayDur:    "N/A",
 			DisplayTokens: "-",
 			DisplayCost:   "-",
+			DisplayBudget: "-",
 		}
 	}
 
@@ -141,6 +144,7 @@ func CalculateWorkflowHealth(workflowName string, runs []WorkflowRun, threshold
 		TotalCost:     totalCost,
 		AvgCost:       avgCost,
 		DisplayCost:   displayCost,
+		DisplayBudget: "-",
 		BelowThresh:   belowThreshold,
 	}
 
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var junitWriterLog = logger.New("cli:junit")
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr,omitempty"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitProblem is the failure or error of a test case
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// junitSeconds formats a duration as JUnit seconds
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// addSuite appends a suite and updates the report totals, counting its failures and errors
+func (r *junitTestSuites) addSuite(suite junitTestSuite, elapsed time.Duration) {
+	suite.Tests = len(suite.Cases)
+	suite.Failures, suite.Errors = 0, 0
+	for _, testCase := range suite.Cases {
+		if testCase.Failure != nil {
+			suite.Failures++
+		}
+		if testCase.Error != nil {
+			suite.Errors++
+		}
+	}
+	suite.Time = junitSeconds(elapsed)
+	r.Suites = append(r.Suites, suite)
+	r.Tests += suite.Tests
+	r.Failures += suite.Failures
+	r.Errors += suite.Errors
+}
+
+// writeJUnitFile writes a JUnit XML report to a file, or to stdout when path is "-"
+func writeJUnitFile(path string, report *junitTestSuites) error {
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+	data = append([]byte(xml.Header), data...)
+	data = append(data, '\n')
+
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory for JUnit report: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	junitWriterLog.Printf("Wrote %d test cases to %s", report.Tests, path)
+	return nil
+}
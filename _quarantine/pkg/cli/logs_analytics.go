@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var logsAnalyticsLog = logger.New("cli:logs_analytics")
+
+const (
+	// analyticsStoreFileName is the name of the analytics store file in the logs output directory
+	analyticsStoreFileName = "analytics.json"
+
+	// analyticsStoreVersion is bumped when the record layout changes, forcing a rebuild
+	analyticsStoreVersion = 1
+)
+
+// AnalyticsStore is a local, file-based archive of run records built from downloaded runs.
+// Records are indexed by workflow, engine and date so queries can run fully offline.
+type AnalyticsStore struct {
+	Version   int            `json:"version"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Runs      []AnalyticsRun `json:"runs"`
+	Index     AnalyticsIndex `json:"index"`
+}
+
+// AnalyticsIndex maps workflow names, engines and dates (YYYY-MM-DD) to run IDs
+type AnalyticsIndex struct {
+	Workflow map[string][]int64 `json:"workflow"`
+	Engine   map[string][]int64 `json:"engine"`
+	Date     map[string][]int64 `json:"date"`
+}
+
+// AnalyticsRun is the flattened record of a single downloaded run
+type AnalyticsRun struct {
+	RunID          int64                `json:"run_id"`
+	WorkflowName   string               `json:"workflow_name"`
+	WorkflowPath   string               `json:"workflow_path,omitempty"`
+	Engine         string               `json:"engine,omitempty"`
+	Conclusion     string               `json:"conclusion,omitempty"`
+	Event          string               `json:"event,omitempty"`
+	Branch         string               `json:"branch,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	Date           string               `json:"date"`
+	Duration       float64              `json:"duration_seconds"`
+	TokenUsage     int                  `json:"token_usage"`
+	EstimatedCost  float64              `json:"estimated_cost"`
+	Turns          int                  `json:"turns"`
+	ErrorCount     int                  `json:"error_count"`
+	WarningCount   int                  `json:"warning_count"`
+	MissingTools   []string             `json:"missing_tools,omitempty"`
+	MCPFailures    []string             `json:"mcp_failures,omitempty"`
+	MCPTools       []AnalyticsMCPTool   `json:"mcp_tools,omitempty"`
+	BlockedDomains []AnalyticsDomainHit `json:"blocked_domains,omitempty"`
+	ProcessedAt    time.Time            `json:"processed_at"`
+}
+
+// AnalyticsMCPTool holds the usage of one MCP tool in a run
+type AnalyticsMCPTool struct {
+	Server string `json:"server"`
+	Tool   string `json:"tool"`
+	Calls  int    `json:"calls"`
+	Errors int    `json:"errors,omitempty"`
+}
+
+// AnalyticsDomainHit holds the blocked requests to one domain in a run
+type AnalyticsDomainHit struct {
+	Domain   string `json:"domain"`
+	Requests int    `json:"requests"`
+}
+
+// loadAnalyticsStore reads the analytics store from the logs output directory.
+// A missing or outdated store yields an empty store.
+func loadAnalyticsStore(outputDir string) (*AnalyticsStore, error) {
+	storePath := filepath.Join(outputDir, analyticsStoreFileName)
+	data, err := os.ReadFile(storePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return &AnalyticsStore{Version: analyticsStoreVersion}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analytics store: %w", err)
+	}
+
+	var store AnalyticsStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("failed to parse analytics store %s: %w", storePath, err)
+	}
+	if store.Version != analyticsStoreVersion {
+		logsAnalyticsLog.Printf("Analytics store version mismatch: stored=%d, current=%d", store.Version, analyticsStoreVersion)
+		return &AnalyticsStore{Version: analyticsStoreVersion}, nil
+	}
+	return &store, nil
+}
+
+// saveAnalyticsStore writes the analytics store to the logs output directory
+func saveAnalyticsStore(outputDir string, store *AnalyticsStore) error {
+	store.Version = analyticsStoreVersion
+	store.UpdatedAt = time.Now()
+	store.rebuildIndex()
+
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal analytics store: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, analyticsStoreFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write analytics store: %w", err)
+	}
+	return nil
+}
+
+// syncAnalyticsStore adds the runs downloaded to outputDir that are new or were reprocessed
+// since the last sync. With rebuild set, the store is recreated from scratch.
+// Returns the store and the number of records added or updated.
+func syncAnalyticsStore(outputDir string, rebuild bool, verbose bool) (*AnalyticsStore, int, error) {
+	logsAnalyticsLog.Printf("Syncing analytics store: dir=%s, rebuild=%v", outputDir, rebuild)
+
+	store := &AnalyticsStore{Version: analyticsStoreVersion}
+	if !rebuild {
+		loaded, err := loadAnalyticsStore(outputDir)
+		if err != nil {
+			return nil, 0, err
+		}
+		store = loaded
+	}
+
+	existing := make(map[int64]int, len(store.Runs))
+	for i, run := range store.Runs {
+		existing[run.RunID] = i
+	}
+
+	entries, err := os.ReadDir(outputDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read logs directory: %w", err)
+	}
+
+	updated := 0
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "run-") {
+			continue
+		}
+		runDir := filepath.Join(outputDir, entry.Name())
+		summary, err := readRunSummaryFile(runDir)
+		if err != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Skipping %s: %v", entry.Name(), err)))
+			}
+			continue
+		}
+		if summary == nil {
+			continue
+		}
+
+		idx, found := existing[summary.RunID]
+		if found && store.Runs[idx].ProcessedAt.Equal(summary.ProcessedAt) {
+			continue
+		}
+
+		record := buildAnalyticsRun(summary, runDir)
+		if found {
+			store.Runs[idx] = record
+		} else {
+			existing[record.RunID] = len(store.Runs)
+			store.Runs = append(store.Runs, record)
+		}
+		updated++
+	}
+
+	sort.Slice(store.Runs, func(i, j int) bool {
+		return store.Runs[i].RunID > store.Runs[j].RunID
+	})
+
+	if updated > 0 || rebuild {
+		if err := saveAnalyticsStore(outputDir, store); err != nil {
+			return nil, 0, err
+		}
+	}
+	store.rebuildIndex()
+
+	logsAnalyticsLog.Printf("Analytics store synced: runs=%d, updated=%d", len(store.Runs), updated)
+	return store, updated, nil
+}
+
+// readRunSummaryFile reads the cached run summary of a run directory, regardless of the CLI
+// version that produced it. Returns nil when the run has no summary yet.
+func readRunSummaryFile(runDir string) (*RunSummary, error) {
+	data, err := os.ReadFile(filepath.Join(runDir, runSummaryFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var summary RunSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", runSummaryFileName, err)
+	}
+	if summary.RunID == 0 {
+		summary.RunID = summary.Run.DatabaseID
+	}
+	if summary.RunID == 0 {
+		// Fall back to the run-<id> directory name
+		summary.RunID, _ = strconv.ParseInt(strings.TrimPrefix(filepath.Base(runDir), "run-"), 10, 64)
+	}
+	return &summary, nil
+}
+
+// buildAnalyticsRun flattens a run summary into an analytics record
+func buildAnalyticsRun(summary *RunSummary, runDir string) AnalyticsRun {
+	run := summary.Run
+	record := AnalyticsRun{
+		RunID:         summary.RunID,
+		WorkflowName:  run.WorkflowName,
+		WorkflowPath:  run.WorkflowPath,
+		Conclusion:    run.Conclusion,
+		Event:         run.Event,
+		Branch:        run.HeadBranch,
+		CreatedAt:     run.CreatedAt,
+		Duration:      run.Duration.Seconds(),
+		TokenUsage:    summary.Metrics.TokenUsage,
+		EstimatedCost: summary.Metrics.EstimatedCost,
+		Turns:         summary.Metrics.Turns,
+		ErrorCount:    run.ErrorCount,
+		WarningCount:  run.WarningCount,
+		ProcessedAt:   summary.ProcessedAt,
+	}
+	if !run.CreatedAt.IsZero() {
+		record.Date = run.CreatedAt.UTC().Format("2006-01-02")
+	}
+	// Older summaries may only carry the metrics on the run itself
+	if record.TokenUsage == 0 && record.EstimatedCost == 0 {
+		record.TokenUsage = run.TokenUsage
+		record.EstimatedCost = run.EstimatedCost
+		record.Turns = run.Turns
+	}
+	if record.Duration == 0 && !run.StartedAt.IsZero() && !run.UpdatedAt.IsZero() {
+		record.Duration = run.UpdatedAt.Sub(run.StartedAt).Seconds()
+	}
+
+	if info, err := parseAwInfo(filepath.Join(runDir, "aw_info.json"), false); err == nil && info != nil {
+		record.Engine = info.EngineID
+	}
+
+	for _, missing := range summary.MissingTools {
+		record.MissingTools = append(record.MissingTools, missing.Tool)
+	}
+	for _, failure := range summary.MCPFailures {
+		record.MCPFailures = append(record.MCPFailures, failure.ServerName)
+	}
+	if summary.MCPToolUsage != nil {
+		for _, tool := range summary.MCPToolUsage.Summary {
+			record.MCPTools = append(record.MCPTools, AnalyticsMCPTool{
+				Server: tool.ServerName,
+				Tool:   tool.ToolName,
+				Calls:  tool.CallCount,
+				Errors: tool.ErrorCount,
+			})
+		}
+	}
+	if summary.FirewallAnalysis != nil {
+		blocked := make(map[string]int)
+		for domain, stats := range summary.FirewallAnalysis.RequestsByDomain {
+			if stats.Blocked == 0 || domain == "-" {
+				continue
+			}
+			blocked[domainPortPattern.ReplaceAllString(domain, "")] += stats.Blocked
+		}
+		for domain, requests := range blocked {
+			record.BlockedDomains = append(record.BlockedDomains, AnalyticsDomainHit{Domain: domain, Requests: requests})
+		}
+		sort.Slice(record.BlockedDomains, func(i, j int) bool {
+			return record.BlockedDomains[i].Domain < record.BlockedDomains[j].Domain
+		})
+	}
+
+	return record
+}
+
+// rebuildIndex recomputes the workflow, engine and date indexes from the run records
+func (s *AnalyticsStore) rebuildIndex() {
+	s.Index = AnalyticsIndex{
+		Workflow: make(map[string][]int64),
+		Engine:   make(map[string][]int64),
+		Date:     make(map[string][]int64),
+	}
+	for _, run := range s.Runs {
+		s.Index.Workflow[run.WorkflowName] = append(s.Index.Workflow[run.WorkflowName], run.RunID)
+		if run.Engine != "" {
+			s.Index.Engine[run.Engine] = append(s.Index.Engine[run.Engine], run.RunID)
+		}
+		if run.Date != "" {
+			s.Index.Date[run.Date] = append(s.Index.Date[run.Date], run.RunID)
+		}
+	}
+}
+
+// updateAnalyticsStore syncs the analytics store after a logs download. Failures are reported
+// as warnings since the store is a derived cache.
+func updateAnalyticsStore(outputDir string, verbose bool) {
+	_, updated, err := syncAnalyticsStore(outputDir, false, verbose)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to update analytics store: %v", err)))
+		return
+	}
+	if verbose && updated > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Added %d runs to %s", updated, filepath.Join(outputDir, analyticsStoreFileName))))
+	}
+}
@@ -0,0 +1,110 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestRunSummary writes a run folder with a run summary and aw_info.json
+func writeTestRunSummary(t *testing.T, outputDir string, summary RunSummary, engine string) {
+	t.Helper()
+	runDir := filepath.Join(outputDir, "run-"+strconv.FormatInt(summary.RunID, 10))
+	require.NoError(t, os.MkdirAll(runDir, 0755))
+
+	data, err := json.Marshal(summary)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, runSummaryFileName), data, 0644))
+
+	if engine != "" {
+		info, err := json.Marshal(map[string]any{"engine_id": engine})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(runDir, "aw_info.json"), info, 0644))
+	}
+}
+
+func TestSyncAnalyticsStore(t *testing.T) {
+	outputDir := testutil.TempDir(t, "test-*")
+	processedAt := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	writeTestRunSummary(t, outputDir, RunSummary{
+		RunID:       101,
+		ProcessedAt: processedAt,
+		Run: WorkflowRun{
+			DatabaseID:   101,
+			WorkflowName: "Daily Plan",
+			WorkflowPath: ".github/workflows/daily-plan.lock.yml",
+			Conclusion:   "success",
+			CreatedAt:    time.Date(2025, 6, 30, 8, 0, 0, 0, time.UTC),
+			Duration:     2 * time.Minute,
+		},
+		Metrics:      LogMetrics{TokenUsage: 1200, EstimatedCost: 0.25, Turns: 4},
+		MissingTools: []MissingToolReport{{Tool: "terraform"}},
+		MCPFailures:  []MCPFailureReport{{ServerName: "notion"}},
+		MCPToolUsage: &MCPToolUsageData{Summary: []MCPToolSummary{
+			{ServerName: "github", ToolName: "list_issues", CallCount: 3, ErrorCount: 1},
+		}},
+		FirewallAnalysis: &FirewallAnalysis{RequestsByDomain: map[string]DomainRequestStats{
+			"pypi.org:443":   {Blocked: 2},
+			"pypi.org":       {Blocked: 1},
+			"api.github.com": {Allowed: 5},
+		}},
+	}, "claude")
+
+	// A folder without a run summary is ignored
+	require.NoError(t, os.MkdirAll(filepath.Join(outputDir, "run-102"), 0755))
+
+	store, updated, err := syncAnalyticsStore(outputDir, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated)
+	require.Len(t, store.Runs, 1)
+
+	run := store.Runs[0]
+	assert.Equal(t, int64(101), run.RunID)
+	assert.Equal(t, "claude", run.Engine)
+	assert.Equal(t, "2025-06-30", run.Date)
+	assert.InDelta(t, 120.0, run.Duration, 0.001)
+	assert.Equal(t, []string{"terraform"}, run.MissingTools)
+	assert.Equal(t, []string{"notion"}, run.MCPFailures)
+	assert.Equal(t, []AnalyticsMCPTool{{Server: "github", Tool: "list_issues", Calls: 3, Errors: 1}}, run.MCPTools)
+	assert.Equal(t, []AnalyticsDomainHit{{Domain: "pypi.org", Requests: 3}}, run.BlockedDomains)
+
+	assert.Equal(t, []int64{101}, store.Index.Workflow["Daily Plan"])
+	assert.Equal(t, []int64{101}, store.Index.Engine["claude"])
+	assert.Equal(t, []int64{101}, store.Index.Date["2025-06-30"])
+	assert.FileExists(t, filepath.Join(outputDir, analyticsStoreFileName))
+
+	t.Run("unchanged runs are not re-added", func(t *testing.T) {
+		store, updated, err := syncAnalyticsStore(outputDir, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, 0, updated)
+		assert.Len(t, store.Runs, 1)
+	})
+
+	t.Run("runs are kept after their folder is removed", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(filepath.Join(outputDir, "run-101")))
+		store, _, err := syncAnalyticsStore(outputDir, false, false)
+		require.NoError(t, err)
+		assert.Len(t, store.Runs, 1, "the store archives runs beyond the downloaded folders")
+
+		store, _, err = syncAnalyticsStore(outputDir, true, false)
+		require.NoError(t, err)
+		assert.Empty(t, store.Runs, "rebuild should only keep runs with a folder")
+	})
+}
+
+func TestSyncAnalyticsStoreMissingDir(t *testing.T) {
+	store, updated, err := syncAnalyticsStore(filepath.Join(testutil.TempDir(t, "test-*"), "missing"), false, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, updated)
+	assert.Empty(t, store.Runs)
+}
@@ -44,6 +44,7 @@ Downloaded artifacts include:
 - aw.patch: Git patch of changes made during execution
 - workflow-logs/: GitHub Actions workflow run logs (job logs organized in subdirectory)
 - summary.json: Complete metrics and run data for all downloaded runs
+- analytics.json: Local analytics store of all downloaded runs, queried with 'logs query'
 
 Orchestrator Usage:
 	In an orchestrator workflow, use this command in a pre-step to download logs,
@@ -108,7 +109,8 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` logs --parse                   # Parse logs and generate Markdown reports
   ` + string(constants.CLIExtensionPrefix) + ` logs --json                    # Output metrics in JSON format
   ` + string(constants.CLIExtensionPrefix) + ` logs --parse --json            # Generate both Markdown and JSON
-  ` + string(constants.CLIExtensionPrefix) + ` logs weekly-research --repo owner/repo  # Download logs from specific repository`,
+  ` + string(constants.CLIExtensionPrefix) + ` logs weekly-research --repo owner/repo  # Download logs from specific repository
+  ` + string(constants.CLIExtensionPrefix) + ` logs query --metric cost --group-by engine,week  # Query downloaded runs offline`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logsCommandLog.Printf("Starting logs command: args=%d", len(args))
 
@@ -194,7 +196,7 @@ Examples:
 			// Validate engine parameter using the engine registry
 			if engine != "" {
 				logsCommandLog.Printf("Validating engine parameter: %s", engine)
-				registry := workflow.GetGlobalEngineRegistry()
+				registry := workflow.GetRepositoryEngineRegistry()
 				if !registry.IsValidEngine(engine) {
 					supportedEngines := registry.GetSupportedEngines()
 					return fmt.Errorf("invalid engine value '%s'. Must be one of: %s", engine, strings.Join(supportedEngines, ", "))
@@ -233,6 +235,8 @@ Examples:
 	RegisterEngineFlagCompletion(logsCmd)
 	RegisterDirFlagCompletion(logsCmd, "output")
 
+	logsCmd.AddCommand(NewLogsQuerySubcommand())
+
 	return logsCmd
 }
 
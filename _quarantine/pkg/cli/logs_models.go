@@ -75,6 +75,7 @@ type ProcessedRun struct {
 	MissingData             []MissingDataReport
 	Noops                   []NoopReport
 	MCPFailures             []MCPFailureReport
+	SafeOutputQuotaHits     []SafeOutputQuotaHit
 	MCPToolUsage            *MCPToolUsageData
 	JobDetails              []JobInfoWithDuration
 }
@@ -184,6 +185,7 @@ type RunSummary struct {
 	MissingData             []MissingDataReport      `json:"missing_data"`              // Missing data reports
 	Noops                   []NoopReport             `json:"noops"`                     // Noop messages
 	MCPFailures             []MCPFailureReport       `json:"mcp_failures"`              // MCP server failures
+	SafeOutputQuotaHits     []SafeOutputQuotaHit     `json:"safe_output_quota_hits"`    // Safe output quotas reached
 	MCPToolUsage            *MCPToolUsageData        `json:"mcp_tool_usage,omitempty"`  // MCP tool usage data
 	ArtifactsList           []string                 `json:"artifacts_list"`            // List of downloaded artifact files
 	JobDetails              []JobInfoWithDuration    `json:"job_details"`               // Job execution details
@@ -200,6 +202,7 @@ type DownloadResult struct {
 	MissingData             []MissingDataReport
 	Noops                   []NoopReport
 	MCPFailures             []MCPFailureReport
+	SafeOutputQuotaHits     []SafeOutputQuotaHit
 	MCPToolUsage            *MCPToolUsageData
 	JobDetails              []JobInfoWithDuration
 	Error                   error
@@ -228,19 +231,28 @@ type AwInfoSteps struct {
 	Firewall string `json:"firewall,omitempty"` // Firewall type (e.g., "squid") or empty if no firewall
 }
 
+// AwInfoBudget represents the budget limits recorded in aw_info.json files
+type AwInfoBudget struct {
+	MaxTokens  int     `json:"max_tokens,omitempty"`   // Maximum tokens per run
+	MaxCost    float64 `json:"max_cost,omitempty"`     // Maximum cost per run (USD)
+	MaxCost7d  float64 `json:"max_cost_7d,omitempty"`  // Maximum cost over a rolling 7 days (USD)
+	MaxCost30d float64 `json:"max_cost_30d,omitempty"` // Maximum cost over a rolling 30 days (USD)
+}
+
 // AwInfo represents the structure of aw_info.json files
 type AwInfo struct {
-	EngineID        string      `json:"engine_id"`
-	EngineName      string      `json:"engine_name"`
-	Model           string      `json:"model"`
-	Version         string      `json:"version"`
-	CLIVersion      string      `json:"cli_version,omitempty"` // gh-aw CLI version
-	WorkflowName    string      `json:"workflow_name"`
-	Staged          bool        `json:"staged"`
-	AwfVersion      string      `json:"awf_version,omitempty"`      // AWF firewall version (new name)
-	FirewallVersion string      `json:"firewall_version,omitempty"` // AWF firewall version (old name, for backward compatibility)
-	Steps           AwInfoSteps `json:"steps,omitempty"`            // Steps metadata
-	CreatedAt       string      `json:"created_at"`
+	EngineID        string        `json:"engine_id"`
+	EngineName      string        `json:"engine_name"`
+	Model           string        `json:"model"`
+	Version         string        `json:"version"`
+	CLIVersion      string        `json:"cli_version,omitempty"` // gh-aw CLI version
+	WorkflowName    string        `json:"workflow_name"`
+	Staged          bool          `json:"staged"`
+	AwfVersion      string        `json:"awf_version,omitempty"`      // AWF firewall version (new name)
+	FirewallVersion string        `json:"firewall_version,omitempty"` // AWF firewall version (old name, for backward compatibility)
+	Steps           AwInfoSteps   `json:"steps,omitempty"`            // Steps metadata
+	Budget          *AwInfoBudget `json:"budget,omitempty"`           // Budget limits (when budget: is configured)
+	CreatedAt       string        `json:"created_at"`
 	// Additional fields that might be present
 	RunID      any    `json:"run_id,omitempty"`
 	RunNumber  any    `json:"run_number,omitempty"`
@@ -341,6 +341,7 @@ func DownloadWorkflowLogs(ctx context.Context, workflowName string, count int, s
 					MissingData:             result.MissingData,
 					Noops:                   result.Noops,
 					MCPFailures:             result.MCPFailures,
+					SafeOutputQuotaHits:     result.SafeOutputQuotaHits,
 					MCPToolUsage:            result.MCPToolUsage,
 					JobDetails:              result.JobDetails,
 				}
@@ -489,6 +490,9 @@ func DownloadWorkflowLogs(ctx context.Context, workflowName string, count int, s
 		}
 	}
 
+	// Record the downloaded runs in the local analytics store for 'logs query'
+	updateAnalyticsStore(outputDir, verbose)
+
 	// Render output based on format preference
 	if jsonOutput {
 		if err := renderLogsJSON(logsData); err != nil {
@@ -590,6 +594,7 @@ func downloadRunArtifactsConcurrent(ctx context.Context, runs []WorkflowRun, out
 					MissingData:             summary.MissingData,
 					Noops:                   summary.Noops,
 					MCPFailures:             summary.MCPFailures,
+					SafeOutputQuotaHits:     summary.SafeOutputQuotaHits,
 					MCPToolUsage:            summary.MCPToolUsage,
 					JobDetails:              summary.JobDetails,
 					LogsPath:                runOutputDir,
@@ -708,6 +713,15 @@ func downloadRunArtifactsConcurrent(ctx context.Context, runs []WorkflowRun, out
 				}
 				result.MCPFailures = mcpFailures
 
+				// Extract safe output quotas reached in the safe outputs job
+				quotaHits, quotaErr := extractSafeOutputQuotaHitsFromRun(runOutputDir, run, verbose)
+				if quotaErr != nil {
+					if verbose {
+						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract safe output quotas for run %d: %v", run.DatabaseID, quotaErr)))
+					}
+				}
+				result.SafeOutputQuotaHits = quotaHits
+
 				// Extract MCP tool usage data from gateway logs if available
 				mcpToolUsage, mcpToolErr := extractMCPToolUsageData(runOutputDir, verbose)
 				if mcpToolErr != nil {
@@ -747,6 +761,7 @@ func downloadRunArtifactsConcurrent(ctx context.Context, runs []WorkflowRun, out
 					MissingData:             missingData,
 					Noops:                   noops,
 					MCPFailures:             mcpFailures,
+					SafeOutputQuotaHits:     quotaHits,
 					MCPToolUsage:            mcpToolUsage,
 					ArtifactsList:           artifacts,
 					JobDetails:              jobDetails,
@@ -95,6 +95,10 @@ func extractEngineFromAwInfo(infoFilePath string, verbose bool) workflow.CodingA
 	}
 
 	registry := workflow.GetGlobalEngineRegistry()
+	if !registry.IsValidEngine(info.EngineID) {
+		// The engine may be declared by an engine manifest in the repository
+		registry = workflow.GetRepositoryEngineRegistry()
+	}
 	engine, err := registry.GetEngine(info.EngineID)
 	if err != nil {
 		logsParsingCoreLog.Printf("Unknown engine: %s", info.EngineID)
@@ -0,0 +1,596 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/sliceutil"
+	"github.com/github/gh-aw/pkg/timeutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var logsQueryLog = logger.New("cli:logs_query")
+
+// LogsQueryConfig holds the configuration for a logs analytics query
+type LogsQueryConfig struct {
+	OutputDir   string
+	Metric      string
+	Aggregation string   // sum, avg or max
+	GroupBy     []string // Dimensions to group by
+	Workflows   []string // Workflow names or IDs to include
+	Engine      string
+	Conclusion  string
+	StartDate   string // Inclusive, YYYY-MM-DD
+	EndDate     string // Inclusive, YYYY-MM-DD
+	Sort        string // value or key; empty picks key for time groupings and value otherwise
+	Limit       int
+	Format      string // table, json or csv
+	Rebuild     bool
+	Verbose     bool
+}
+
+// LogsQueryRow is one group of a logs analytics query result
+type LogsQueryRow struct {
+	Group map[string]string `json:"group,omitempty"`
+	Value float64           `json:"value"`
+	Runs  int               `json:"runs"`
+}
+
+// LogsQueryResult is the result of a logs analytics query
+type LogsQueryResult struct {
+	Metric      string         `json:"metric"`
+	Aggregation string         `json:"aggregation"`
+	GroupBy     []string       `json:"group_by,omitempty"`
+	TotalRuns   int            `json:"total_runs"`
+	Rows        []LogsQueryRow `json:"rows"`
+}
+
+// analyticsGrain is the level at which a query is evaluated: one fact per run, per MCP tool
+// used in a run, or per domain blocked in a run
+type analyticsGrain int
+
+const (
+	grainRun analyticsGrain = iota
+	grainMCPTool
+	grainDomain
+)
+
+// analyticsFact is a single row the query aggregates over
+type analyticsFact struct {
+	run    *AnalyticsRun
+	tool   *AnalyticsMCPTool
+	domain *AnalyticsDomainHit
+}
+
+type queryDimension struct {
+	header string
+	grain  analyticsGrain
+	value  func(f analyticsFact) string
+}
+
+type queryMetric struct {
+	header string
+	grain  analyticsGrain
+	value  func(f analyticsFact) float64
+}
+
+var queryDimensions = map[string]queryDimension{
+	"workflow":   {header: "Workflow", value: func(f analyticsFact) string { return f.run.WorkflowName }},
+	"engine":     {header: "Engine", value: func(f analyticsFact) string { return f.run.Engine }},
+	"conclusion": {header: "Conclusion", value: func(f analyticsFact) string { return f.run.Conclusion }},
+	"event":      {header: "Event", value: func(f analyticsFact) string { return f.run.Event }},
+	"branch":     {header: "Branch", value: func(f analyticsFact) string { return f.run.Branch }},
+	"day":        {header: "Day", value: func(f analyticsFact) string { return f.run.Date }},
+	"week": {header: "Week", value: func(f analyticsFact) string {
+		if f.run.CreatedAt.IsZero() {
+			return ""
+		}
+		year, week := f.run.CreatedAt.UTC().ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}},
+	"month": {header: "Month", value: func(f analyticsFact) string {
+		if f.run.CreatedAt.IsZero() {
+			return ""
+		}
+		return f.run.CreatedAt.UTC().Format("2006-01")
+	}},
+	"mcp-server": {header: "MCP Server", grain: grainMCPTool, value: func(f analyticsFact) string { return f.tool.Server }},
+	"mcp-tool":   {header: "MCP Tool", grain: grainMCPTool, value: func(f analyticsFact) string { return f.tool.Tool }},
+	"domain":     {header: "Domain", grain: grainDomain, value: func(f analyticsFact) string { return f.domain.Domain }},
+}
+
+var queryMetrics = map[string]queryMetric{
+	"runs": {header: "Runs"},
+	"failures": {header: "Failures", value: func(f analyticsFact) float64 {
+		if f.run.Conclusion == "failure" {
+			return 1
+		}
+		return 0
+	}},
+	"cost":          {header: "Cost ($)", value: func(f analyticsFact) float64 { return f.run.EstimatedCost }},
+	"tokens":        {header: "Tokens", value: func(f analyticsFact) float64 { return float64(f.run.TokenUsage) }},
+	"turns":         {header: "Turns", value: func(f analyticsFact) float64 { return float64(f.run.Turns) }},
+	"duration":      {header: "Duration", value: func(f analyticsFact) float64 { return f.run.Duration }},
+	"errors":        {header: "Errors", value: func(f analyticsFact) float64 { return float64(f.run.ErrorCount) }},
+	"warnings":      {header: "Warnings", value: func(f analyticsFact) float64 { return float64(f.run.WarningCount) }},
+	"missing-tools": {header: "Missing Tools", value: func(f analyticsFact) float64 { return float64(len(f.run.MissingTools)) }},
+	"mcp-failures":  {header: "MCP Failures", value: func(f analyticsFact) float64 { return float64(len(f.run.MCPFailures)) }},
+	"tool-calls":    {header: "Tool Calls", grain: grainMCPTool, value: func(f analyticsFact) float64 { return float64(f.tool.Calls) }},
+	"tool-errors":   {header: "Tool Errors", grain: grainMCPTool, value: func(f analyticsFact) float64 { return float64(f.tool.Errors) }},
+	"blocked-requests": {header: "Blocked Requests", grain: grainDomain, value: func(f analyticsFact) float64 {
+		return float64(f.domain.Requests)
+	}},
+}
+
+var timeDimensions = []string{"day", "week", "month"}
+
+// NewLogsQuerySubcommand creates the logs query subcommand
+func NewLogsQuerySubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query",
+		Short: "Query the local analytics store of downloaded workflow runs",
+		Long: `Query metrics across previously downloaded workflow runs, fully offline.
+
+Every 'logs' download records its runs in an analytics store (analytics.json) in the
+output directory, indexed by workflow, engine and date. This command syncs the store
+with the run folders in the output directory and aggregates a metric, optionally
+grouped and filtered.
+
+Metrics: ` + strings.Join(sortedKeys(queryMetrics), ", ") + `
+Group-by dimensions: ` + strings.Join(sortedKeys(queryDimensions), ", ") + `
+
+The tool-calls and tool-errors metrics and the mcp-server and mcp-tool dimensions are
+evaluated per MCP tool; the blocked-requests metric and the domain dimension per
+blocked domain. They cannot be combined with each other.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` logs query --metric cost --group-by engine,week          # Cost by engine per week
+  ` + string(constants.CLIExtensionPrefix) + ` logs query --metric tool-errors --group-by mcp-server,mcp-tool --limit 10  # Top failing MCP tools
+  ` + string(constants.CLIExtensionPrefix) + ` logs query --metric blocked-requests --group-by domain   # Most blocked domains
+  ` + string(constants.CLIExtensionPrefix) + ` logs query --metric failures --agg avg --group-by workflow  # Failure rate by workflow
+  ` + string(constants.CLIExtensionPrefix) + ` logs query --metric tokens --workflow daily-plan --start-date -1mo
+  ` + string(constants.CLIExtensionPrefix) + ` logs query --metric cost --group-by engine --format csv  # CSV output
+  ` + string(constants.CLIExtensionPrefix) + ` logs query --rebuild                                    # Rebuild the store from the run folders`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			metric, _ := cmd.Flags().GetString("metric")
+			aggregation, _ := cmd.Flags().GetString("agg")
+			groupBy, _ := cmd.Flags().GetStringSlice("group-by")
+			workflows, _ := cmd.Flags().GetStringSlice("workflow")
+			engine, _ := cmd.Flags().GetString("engine")
+			conclusion, _ := cmd.Flags().GetString("conclusion")
+			startDate, _ := cmd.Flags().GetString("start-date")
+			endDate, _ := cmd.Flags().GetString("end-date")
+			sortBy, _ := cmd.Flags().GetString("sort")
+			limit, _ := cmd.Flags().GetInt("limit")
+			format, _ := cmd.Flags().GetString("format")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			outputDir, _ := cmd.Flags().GetString("output")
+			rebuild, _ := cmd.Flags().GetBool("rebuild")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+
+			if jsonOutput {
+				format = "json"
+			}
+
+			now := time.Now()
+			resolvedStartDate, err := resolveQueryDate(startDate, now)
+			if err != nil {
+				return fmt.Errorf("invalid start-date format '%s': %w", startDate, err)
+			}
+			resolvedEndDate, err := resolveQueryDate(endDate, now)
+			if err != nil {
+				return fmt.Errorf("invalid end-date format '%s': %w", endDate, err)
+			}
+
+			return RunLogsQuery(LogsQueryConfig{
+				OutputDir:   outputDir,
+				Metric:      metric,
+				Aggregation: aggregation,
+				GroupBy:     groupBy,
+				Workflows:   workflows,
+				Engine:      engine,
+				Conclusion:  conclusion,
+				StartDate:   resolvedStartDate,
+				EndDate:     resolvedEndDate,
+				Sort:        sortBy,
+				Limit:       limit,
+				Format:      format,
+				Rebuild:     rebuild,
+				Verbose:     verbose,
+			})
+		},
+	}
+
+	cmd.Flags().StringP("metric", "m", "runs", "Metric to aggregate ("+strings.Join(sortedKeys(queryMetrics), ", ")+")")
+	cmd.Flags().String("agg", "sum", "Aggregation applied to the metric (sum, avg, max)")
+	cmd.Flags().StringSliceP("group-by", "g", nil, "Comma-separated dimensions to group by ("+strings.Join(sortedKeys(queryDimensions), ", ")+")")
+	cmd.Flags().StringSliceP("workflow", "w", nil, "Only include runs of these workflows (name or workflow ID)")
+	addEngineFilterFlag(cmd)
+	cmd.Flags().String("conclusion", "", "Only include runs with this conclusion (e.g., success, failure)")
+	cmd.Flags().String("start-date", "", "Only include runs created on or after this date (YYYY-MM-DD or delta like -1d, -1w, -1mo)")
+	cmd.Flags().String("end-date", "", "Only include runs created on or before this date (YYYY-MM-DD or delta like -1d, -1w, -1mo)")
+	cmd.Flags().String("sort", "", "Sort rows by value (descending) or key (ascending); defaults to key when grouping by day, week or month")
+	cmd.Flags().Int("limit", 0, "Maximum number of rows to show (0 = all)")
+	cmd.Flags().String("format", "table", "Output format (table, json, csv)")
+	addJSONFlag(cmd)
+	addOutputFlag(cmd, defaultLogsOutputDir)
+	cmd.Flags().Bool("rebuild", false, "Rebuild the analytics store from the downloaded run folders")
+
+	RegisterEngineFlagCompletion(cmd)
+	RegisterDirFlagCompletion(cmd, "output")
+	_ = cmd.RegisterFlagCompletionFunc("metric", cobra.FixedCompletions(sortedKeys(queryMetrics), cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("group-by", cobra.FixedCompletions(sortedKeys(queryDimensions), cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+// resolveQueryDate resolves an absolute or relative date to YYYY-MM-DD
+func resolveQueryDate(date string, now time.Time) (string, error) {
+	if date == "" {
+		return "", nil
+	}
+	resolved, err := workflow.ResolveRelativeDate(date, now)
+	if err != nil {
+		return "", err
+	}
+	if t, err := time.Parse(time.RFC3339, resolved); err == nil {
+		return t.UTC().Format("2006-01-02"), nil
+	}
+	if _, err := time.Parse("2006-01-02", resolved); err != nil {
+		return "", errors.New("expected YYYY-MM-DD or a delta like -1d, -1w, -1mo")
+	}
+	return resolved, nil
+}
+
+// RunLogsQuery syncs the analytics store and runs a query against it
+func RunLogsQuery(config LogsQueryConfig) error {
+	logsQueryLog.Printf("Running logs query: metric=%s, agg=%s, group_by=%v, dir=%s", config.Metric, config.Aggregation, config.GroupBy, config.OutputDir)
+
+	if !sliceutil.Contains([]string{"table", "json", "csv"}, config.Format) {
+		return fmt.Errorf("invalid format '%s'. Must be one of: table, json, csv", config.Format)
+	}
+
+	store, updated, err := syncAnalyticsStore(config.OutputDir, config.Rebuild, config.Verbose)
+	if err != nil {
+		return err
+	}
+	if config.Verbose {
+		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Analytics store %s: %d runs (%d added or updated)", filepath.Join(config.OutputDir, analyticsStoreFileName), len(store.Runs), updated)))
+	}
+	if len(store.Runs) == 0 && config.Format == "table" {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("No downloaded runs found in %s. Run '%s logs' first to download runs.", config.OutputDir, string(constants.CLIExtensionPrefix))))
+		return nil
+	}
+
+	result, err := queryAnalyticsStore(store, config)
+	if err != nil {
+		return err
+	}
+
+	switch config.Format {
+	case "json":
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal query result: %w", err)
+		}
+		fmt.Println(string(data))
+	case "csv":
+		return writeLogsQueryCSV(os.Stdout, result)
+	default:
+		fmt.Print(console.RenderTable(buildLogsQueryTable(result)))
+	}
+	return nil
+}
+
+// queryAnalyticsStore filters, groups and aggregates the runs of the analytics store
+func queryAnalyticsStore(store *AnalyticsStore, config LogsQueryConfig) (*LogsQueryResult, error) {
+	metric, ok := queryMetrics[config.Metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown metric '%s'. Must be one of: %s", config.Metric, strings.Join(sortedKeys(queryMetrics), ", "))
+	}
+	aggregation := config.Aggregation
+	if aggregation == "" {
+		aggregation = "sum"
+	}
+	if !sliceutil.Contains([]string{"sum", "avg", "max"}, aggregation) {
+		return nil, fmt.Errorf("invalid aggregation '%s'. Must be one of: sum, avg, max", aggregation)
+	}
+	sortBy := config.Sort
+	if sortBy != "" && sortBy != "value" && sortBy != "key" {
+		return nil, fmt.Errorf("invalid sort '%s'. Must be one of: value, key", sortBy)
+	}
+
+	// Determine the grain of the query from the metric and dimensions
+	grain := metric.grain
+	grainSource := config.Metric
+	var dimensions []queryDimension
+	for _, name := range config.GroupBy {
+		dimension, ok := queryDimensions[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown group-by dimension '%s'. Must be one of: %s", name, strings.Join(sortedKeys(queryDimensions), ", "))
+		}
+		if dimension.grain != grainRun {
+			if grain != grainRun && grain != dimension.grain {
+				return nil, fmt.Errorf("'%s' cannot be combined with '%s'", name, grainSource)
+			}
+			grain = dimension.grain
+			grainSource = name
+		}
+		dimensions = append(dimensions, dimension)
+	}
+	if metric.grain == grainRun && grain != grainRun && config.Metric != "runs" {
+		return nil, fmt.Errorf("metric '%s' is per run and cannot be grouped by '%s'", config.Metric, grainSource)
+	}
+
+	type group struct {
+		key   []string
+		sum   float64
+		max   float64
+		count int
+		runs  map[int64]bool
+	}
+	groups := make(map[string]*group)
+	var order []string
+	totalRuns := 0
+
+	for _, run := range selectAnalyticsRuns(store, config) {
+		totalRuns++
+		for _, fact := range expandAnalyticsFacts(run, grain) {
+			key := make([]string, len(dimensions))
+			for i, dimension := range dimensions {
+				key[i] = dimension.value(fact)
+			}
+			id := strings.Join(key, "\x00")
+			g, exists := groups[id]
+			if !exists {
+				g = &group{key: key, runs: make(map[int64]bool)}
+				groups[id] = g
+				order = append(order, id)
+			}
+			g.runs[run.RunID] = true
+			g.count++
+			if metric.value != nil {
+				v := metric.value(fact)
+				g.sum += v
+				if g.count == 1 || v > g.max {
+					g.max = v
+				}
+			}
+		}
+	}
+
+	result := &LogsQueryResult{
+		Metric:      config.Metric,
+		Aggregation: aggregation,
+		GroupBy:     config.GroupBy,
+		TotalRuns:   totalRuns,
+		Rows:        []LogsQueryRow{},
+	}
+	for _, id := range order {
+		g := groups[id]
+		row := LogsQueryRow{Runs: len(g.runs)}
+		if len(dimensions) > 0 {
+			row.Group = make(map[string]string, len(dimensions))
+			for i, name := range config.GroupBy {
+				row.Group[name] = g.key[i]
+			}
+		}
+		switch {
+		case metric.value == nil:
+			row.Value = float64(len(g.runs))
+		case aggregation == "avg":
+			row.Value = g.sum / float64(g.count)
+		case aggregation == "max":
+			row.Value = g.max
+		default:
+			row.Value = g.sum
+		}
+		// Avoid floating point noise such as 0.30000000000000004 in the output
+		row.Value = math.Round(row.Value*1e6) / 1e6
+		result.Rows = append(result.Rows, row)
+	}
+
+	if sortBy == "" {
+		sortBy = "value"
+		for _, name := range config.GroupBy {
+			if sliceutil.Contains(timeDimensions, name) {
+				sortBy = "key"
+				break
+			}
+		}
+	}
+	sort.SliceStable(result.Rows, func(i, j int) bool {
+		a, b := result.Rows[i], result.Rows[j]
+		if sortBy == "value" && a.Value != b.Value {
+			return a.Value > b.Value
+		}
+		for _, name := range config.GroupBy {
+			if a.Group[name] != b.Group[name] {
+				return a.Group[name] < b.Group[name]
+			}
+		}
+		return false
+	})
+	if config.Limit > 0 && len(result.Rows) > config.Limit {
+		result.Rows = result.Rows[:config.Limit]
+	}
+
+	logsQueryLog.Printf("Query matched %d runs in %d groups", totalRuns, len(result.Rows))
+	return result, nil
+}
+
+// selectAnalyticsRuns returns the runs matching the query filters, using the store indexes
+// for the engine and date filters
+func selectAnalyticsRuns(store *AnalyticsStore, config LogsQueryConfig) []*AnalyticsRun {
+	var candidates map[int64]bool
+	narrow := func(ids map[int64]bool) {
+		if candidates == nil {
+			candidates = ids
+			return
+		}
+		for id := range candidates {
+			if !ids[id] {
+				delete(candidates, id)
+			}
+		}
+	}
+
+	if config.Engine != "" {
+		ids := make(map[int64]bool)
+		for _, id := range store.Index.Engine[config.Engine] {
+			ids[id] = true
+		}
+		narrow(ids)
+	}
+	if config.StartDate != "" || config.EndDate != "" {
+		ids := make(map[int64]bool)
+		for date, runIDs := range store.Index.Date {
+			if (config.StartDate != "" && date < config.StartDate) || (config.EndDate != "" && date > config.EndDate) {
+				continue
+			}
+			for _, id := range runIDs {
+				ids[id] = true
+			}
+		}
+		narrow(ids)
+	}
+
+	var runs []*AnalyticsRun
+	for i := range store.Runs {
+		run := &store.Runs[i]
+		if candidates != nil && !candidates[run.RunID] {
+			continue
+		}
+		if config.Conclusion != "" && run.Conclusion != config.Conclusion {
+			continue
+		}
+		if len(config.Workflows) > 0 && !analyticsRunMatchesWorkflow(run, config.Workflows) {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// analyticsRunMatchesWorkflow reports whether a run belongs to one of the given workflows,
+// matched by GitHub Actions workflow name or by workflow ID
+func analyticsRunMatchesWorkflow(run *AnalyticsRun, workflows []string) bool {
+	workflowID := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(run.WorkflowPath), ".yml"), ".lock")
+	for _, wf := range workflows {
+		wf = strings.TrimSuffix(wf, ".md")
+		if strings.EqualFold(wf, run.WorkflowName) || (run.WorkflowPath != "" && wf == workflowID) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandAnalyticsFacts returns the facts of a run at the given grain
+func expandAnalyticsFacts(run *AnalyticsRun, grain analyticsGrain) []analyticsFact {
+	switch grain {
+	case grainMCPTool:
+		facts := make([]analyticsFact, 0, len(run.MCPTools))
+		for i := range run.MCPTools {
+			facts = append(facts, analyticsFact{run: run, tool: &run.MCPTools[i]})
+		}
+		return facts
+	case grainDomain:
+		facts := make([]analyticsFact, 0, len(run.BlockedDomains))
+		for i := range run.BlockedDomains {
+			facts = append(facts, analyticsFact{run: run, domain: &run.BlockedDomains[i]})
+		}
+		return facts
+	default:
+		return []analyticsFact{{run: run}}
+	}
+}
+
+// buildLogsQueryTable converts a query result into a console table
+func buildLogsQueryTable(result *LogsQueryResult) console.TableConfig {
+	metric := queryMetrics[result.Metric]
+	valueHeader := metric.header
+	if metric.value != nil && result.Aggregation != "sum" {
+		valueHeader = fmt.Sprintf("%s (%s)", metric.header, result.Aggregation)
+	}
+
+	config := console.TableConfig{
+		Title: fmt.Sprintf("Logs Query: %s across %d runs", result.Metric, result.TotalRuns),
+	}
+	for _, name := range result.GroupBy {
+		config.Headers = append(config.Headers, queryDimensions[name].header)
+	}
+	config.Headers = append(config.Headers, valueHeader)
+	if result.Metric != "runs" {
+		config.Headers = append(config.Headers, "Runs")
+	}
+
+	for _, row := range result.Rows {
+		var cells []string
+		for _, name := range result.GroupBy {
+			value := row.Group[name]
+			if value == "" {
+				value = "-"
+			}
+			cells = append(cells, value)
+		}
+		cells = append(cells, formatLogsQueryValue(result.Metric, result.Aggregation, row.Value))
+		if result.Metric != "runs" {
+			cells = append(cells, strconv.Itoa(row.Runs))
+		}
+		config.Rows = append(config.Rows, cells)
+	}
+	return config
+}
+
+// formatLogsQueryValue formats a metric value for display
+func formatLogsQueryValue(metric, aggregation string, value float64) string {
+	switch {
+	case metric == "cost":
+		return fmt.Sprintf("$%.3f", value)
+	case metric == "duration":
+		return timeutil.FormatDuration(time.Duration(value * float64(time.Second)))
+	case metric == "failures" && aggregation == "avg":
+		return fmt.Sprintf("%.1f%%", value*100)
+	case value == math.Trunc(value):
+		return console.FormatNumber(int(value))
+	default:
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+}
+
+// writeLogsQueryCSV writes a query result as CSV with one column per dimension
+func writeLogsQueryCSV(w io.Writer, result *LogsQueryResult) error {
+	writer := csv.NewWriter(w)
+	header := append([]string{}, result.GroupBy...)
+	header = append(header, result.Metric, "runs")
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, row := range result.Rows {
+		record := make([]string, 0, len(header))
+		for _, name := range result.GroupBy {
+			record = append(record, row.Group[name])
+		}
+		record = append(record, strconv.FormatFloat(row.Value, 'f', -1, 64), strconv.Itoa(row.Runs))
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
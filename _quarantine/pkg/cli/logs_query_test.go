@@ -0,0 +1,152 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAnalyticsStore() *AnalyticsStore {
+	store := &AnalyticsStore{Runs: []AnalyticsRun{
+		{
+			RunID: 1, WorkflowName: "Daily Plan", WorkflowPath: ".github/workflows/daily-plan.lock.yml", Engine: "claude",
+			Conclusion: "success", CreatedAt: time.Date(2025, 6, 23, 9, 0, 0, 0, time.UTC), Date: "2025-06-23",
+			EstimatedCost: 0.10, TokenUsage: 1000,
+			MCPTools:       []AnalyticsMCPTool{{Server: "github", Tool: "list_issues", Calls: 4, Errors: 1}},
+			BlockedDomains: []AnalyticsDomainHit{{Domain: "pypi.org", Requests: 3}},
+		},
+		{
+			RunID: 2, WorkflowName: "Daily Plan", WorkflowPath: ".github/workflows/daily-plan.lock.yml", Engine: "codex",
+			Conclusion: "failure", CreatedAt: time.Date(2025, 6, 24, 9, 0, 0, 0, time.UTC), Date: "2025-06-24",
+			EstimatedCost: 0.20, TokenUsage: 3000,
+			MCPTools: []AnalyticsMCPTool{
+				{Server: "github", Tool: "list_issues", Calls: 2, Errors: 2},
+				{Server: "github", Tool: "get_file_contents", Calls: 5},
+			},
+			BlockedDomains: []AnalyticsDomainHit{{Domain: "pypi.org", Requests: 1}, {Domain: "example.com", Requests: 6}},
+		},
+		{
+			RunID: 3, WorkflowName: "Issue Triage", WorkflowPath: ".github/workflows/issue-triage.lock.yml", Engine: "claude",
+			Conclusion: "success", CreatedAt: time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC), Date: "2025-07-01",
+			EstimatedCost: 0.30, TokenUsage: 2000,
+		},
+	}}
+	store.rebuildIndex()
+	return store
+}
+
+func TestQueryAnalyticsStore(t *testing.T) {
+	store := newTestAnalyticsStore()
+
+	t.Run("cost by engine per week", func(t *testing.T) {
+		result, err := queryAnalyticsStore(store, LogsQueryConfig{Metric: "cost", GroupBy: []string{"engine", "week"}})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.TotalRuns)
+		assert.Equal(t, []LogsQueryRow{
+			{Group: map[string]string{"engine": "claude", "week": "2025-W26"}, Value: 0.1, Runs: 1},
+			{Group: map[string]string{"engine": "claude", "week": "2025-W27"}, Value: 0.3, Runs: 1},
+			{Group: map[string]string{"engine": "codex", "week": "2025-W26"}, Value: 0.2, Runs: 1},
+		}, result.Rows, "time groupings should be sorted by key")
+	})
+
+	t.Run("top failing MCP tools", func(t *testing.T) {
+		result, err := queryAnalyticsStore(store, LogsQueryConfig{Metric: "tool-errors", GroupBy: []string{"mcp-server", "mcp-tool"}, Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []LogsQueryRow{
+			{Group: map[string]string{"mcp-server": "github", "mcp-tool": "list_issues"}, Value: 3, Runs: 2},
+		}, result.Rows)
+	})
+
+	t.Run("most blocked domains", func(t *testing.T) {
+		result, err := queryAnalyticsStore(store, LogsQueryConfig{Metric: "blocked-requests", GroupBy: []string{"domain"}})
+		require.NoError(t, err)
+		assert.Equal(t, []LogsQueryRow{
+			{Group: map[string]string{"domain": "example.com"}, Value: 6, Runs: 1},
+			{Group: map[string]string{"domain": "pypi.org"}, Value: 4, Runs: 2},
+		}, result.Rows)
+	})
+
+	t.Run("failure rate with filters", func(t *testing.T) {
+		result, err := queryAnalyticsStore(store, LogsQueryConfig{
+			Metric:      "failures",
+			Aggregation: "avg",
+			Workflows:   []string{"daily-plan"},
+			StartDate:   "2025-06-01",
+			EndDate:     "2025-06-30",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []LogsQueryRow{{Value: 0.5, Runs: 2}}, result.Rows)
+	})
+
+	t.Run("engine filter uses the index", func(t *testing.T) {
+		result, err := queryAnalyticsStore(store, LogsQueryConfig{Metric: "tokens", Aggregation: "max", Engine: "claude"})
+		require.NoError(t, err)
+		assert.Equal(t, []LogsQueryRow{{Value: 2000, Runs: 2}}, result.Rows)
+	})
+
+	t.Run("runs per tool counts distinct runs", func(t *testing.T) {
+		result, err := queryAnalyticsStore(store, LogsQueryConfig{Metric: "runs", GroupBy: []string{"mcp-tool"}})
+		require.NoError(t, err)
+		assert.Equal(t, []LogsQueryRow{
+			{Group: map[string]string{"mcp-tool": "list_issues"}, Value: 2, Runs: 2},
+			{Group: map[string]string{"mcp-tool": "get_file_contents"}, Value: 1, Runs: 1},
+		}, result.Rows)
+	})
+}
+
+func TestQueryAnalyticsStoreErrors(t *testing.T) {
+	store := newTestAnalyticsStore()
+	tests := []struct {
+		name    string
+		config  LogsQueryConfig
+		wantErr string
+	}{
+		{name: "unknown metric", config: LogsQueryConfig{Metric: "latency"}, wantErr: "unknown metric 'latency'"},
+		{name: "unknown dimension", config: LogsQueryConfig{Metric: "runs", GroupBy: []string{"repo"}}, wantErr: "unknown group-by dimension 'repo'"},
+		{name: "invalid aggregation", config: LogsQueryConfig{Metric: "cost", Aggregation: "p95"}, wantErr: "invalid aggregation 'p95'"},
+		{name: "run metric by tool", config: LogsQueryConfig{Metric: "cost", GroupBy: []string{"mcp-tool"}}, wantErr: "metric 'cost' is per run"},
+		{name: "tool metric by domain", config: LogsQueryConfig{Metric: "tool-calls", GroupBy: []string{"domain"}}, wantErr: "'domain' cannot be combined with 'tool-calls'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := queryAnalyticsStore(store, tt.config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestWriteLogsQueryCSV(t *testing.T) {
+	result := &LogsQueryResult{
+		Metric:  "cost",
+		GroupBy: []string{"engine"},
+		Rows: []LogsQueryRow{
+			{Group: map[string]string{"engine": "claude"}, Value: 0.4, Runs: 2},
+			{Group: map[string]string{"engine": "codex"}, Value: 0.2, Runs: 1},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeLogsQueryCSV(&buf, result))
+	assert.Equal(t, "engine,cost,runs\nclaude,0.4,2\ncodex,0.2,1\n", buf.String())
+}
+
+func TestResolveQueryDate(t *testing.T) {
+	now := time.Date(2025, 7, 15, 10, 0, 0, 0, time.UTC)
+
+	date, err := resolveQueryDate("-1w", now)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-07-08", date)
+
+	date, err = resolveQueryDate("2025-06-01", now)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-06-01", date)
+
+	_, err = resolveQueryDate("last tuesday", now)
+	require.Error(t, err)
+}
@@ -27,6 +27,7 @@ type LogsData struct {
 	MissingTools      []MissingToolSummary       `json:"missing_tools,omitempty" console:"title:🛠️  Missing Tools Summary,omitempty"`
 	MissingData       []MissingDataSummary       `json:"missing_data,omitempty" console:"title:📊 Missing Data Summary,omitempty"`
 	MCPFailures       []MCPFailureSummary        `json:"mcp_failures,omitempty" console:"title:⚠️  MCP Server Failures,omitempty"`
+	SafeOutputQuotas  []SafeOutputQuotaSummary   `json:"safe_output_quotas,omitempty" console:"title:🚦 Safe Output Quotas Reached,omitempty"`
 	AccessLog         *AccessLogSummary          `json:"access_log,omitempty" console:"title:Access Log Analysis,omitempty"`
 	FirewallLog       *FirewallLogSummary        `json:"firewall_log,omitempty" console:"title:🔥 Firewall Log Analysis,omitempty"`
 	RedactedDomains   *RedactedDomainsLogSummary `json:"redacted_domains,omitempty" console:"title:🔒 Redacted URL Domains,omitempty"`
@@ -73,6 +74,8 @@ type RunData struct {
 	Duration         string    `json:"duration,omitempty" console:"header:Duration,omitempty"`
 	TokenUsage       int       `json:"token_usage,omitempty" console:"header:Tokens,format:number,omitempty"`
 	EstimatedCost    float64   `json:"estimated_cost,omitempty" console:"header:Cost ($),format:cost,omitempty"`
+	BudgetUsage      float64   `json:"budget_usage,omitempty" console:"-"`
+	Budget           string    `json:"-" console:"header:Budget,omitempty"`
 	Turns            int       `json:"turns,omitempty" console:"header:Turns,omitempty"`
 	ErrorCount       int       `json:"error_count" console:"header:Errors"`
 	WarningCount     int       `json:"warning_count" console:"header:Warnings"`
@@ -160,11 +163,13 @@ func buildLogsData(processedRuns []ProcessedRun, outputDir string, continuation
 		totalMissingTools += run.MissingToolCount
 		totalMissingData += run.MissingDataCount
 
-		// Extract agent/engine ID from aw_info.json
+		// Extract agent/engine ID and budget limits from aw_info.json
 		agentID := ""
+		var budget *AwInfoBudget
 		awInfoPath := filepath.Join(run.LogsPath, "aw_info.json")
 		if info, err := parseAwInfo(awInfoPath, false); err == nil && info != nil {
 			agentID = info.EngineID
+			budget = info.Budget
 		}
 
 		runData := RunData{
@@ -193,6 +198,10 @@ func buildLogsData(processedRuns []ProcessedRun, outputDir string, continuation
 		if run.Duration > 0 {
 			runData.Duration = timeutil.FormatDuration(run.Duration)
 		}
+		if budgetUsage := runBudgetUsagePercent(run.TokenUsage, run.EstimatedCost, budget); budgetUsage > 0 {
+			runData.BudgetUsage = budgetUsage
+			runData.Budget = formatBudgetUsage(budgetUsage)
+		}
 		runs = append(runs, runData)
 	}
 
@@ -223,6 +232,9 @@ func buildLogsData(processedRuns []ProcessedRun, outputDir string, continuation
 	// Build MCP failures summary
 	mcpFailures := buildMCPFailuresSummary(processedRuns)
 
+	// Build safe output quota summary
+	safeOutputQuotas := buildSafeOutputQuotaSummary(processedRuns)
+
 	// Build MCP tool usage summary
 	mcpToolUsage := buildMCPToolUsageSummary(processedRuns)
 
@@ -246,6 +258,7 @@ func buildLogsData(processedRuns []ProcessedRun, outputDir string, continuation
 		MissingTools:      missingTools,
 		MissingData:       missingData,
 		MCPFailures:       mcpFailures,
+		SafeOutputQuotas:  safeOutputQuotas,
 		AccessLog:         accessLog,
 		FirewallLog:       firewallLog,
 		RedactedDomains:   redactedDomains,
@@ -0,0 +1,171 @@
+// This file provides command-line interface functionality for gh-aw.
+// This file (logs_safe_output_quota.go) contains functions for detecting
+// safe output quotas (max-per-day, max-open) that were reached during a run.
+//
+// The safe outputs job logs a warning starting with "Safe output quota reached:"
+// the first time a quota prevents an item from being created. The warning is
+// found in the job logs downloaded to the workflow-logs directory.
+
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var logsSafeOutputQuotaLog = logger.New("cli:logs_safe_output_quota")
+
+// safeOutputQuotaPattern matches the warning logged by safe_output_quota.cjs, e.g.
+// "Safe output quota reached: create_issue max-per-day=5 (5 created in the last 24 hours)"
+var safeOutputQuotaPattern = regexp.MustCompile(`Safe output quota reached: (\w+) (max-per-day|max-open)=(\d+) \(([^)]*)\)`)
+
+// SafeOutputQuotaHit represents a safe output quota that was reached during a run
+type SafeOutputQuotaHit struct {
+	Type         string `json:"type"`   // Safe output type (e.g., create_issue)
+	Quota        string `json:"quota"`  // Quota that was reached (max-per-day or max-open)
+	Limit        int    `json:"limit"`  // Configured limit
+	Detail       string `json:"detail"` // Usage at the time the quota was reached
+	WorkflowName string `json:"workflow_name,omitempty"`
+	RunID        int64  `json:"run_id,omitempty"`
+}
+
+// SafeOutputQuotaSummary aggregates safe output quota hits across runs
+type SafeOutputQuotaSummary struct {
+	Type             string   `json:"type" console:"header:Safe Output"`
+	Quota            string   `json:"quota" console:"header:Quota"`
+	Limit            int      `json:"limit" console:"header:Limit"`
+	Count            int      `json:"count" console:"header:Runs"`
+	Workflows        []string `json:"workflows" console:"-"`
+	WorkflowsDisplay string   `json:"-" console:"header:Workflows,maxlen:60"`
+	RunIDs           []int64  `json:"run_ids" console:"-"`
+}
+
+// extractSafeOutputQuotaHitsFromRun scans the job logs of a run for reached safe output quotas
+func extractSafeOutputQuotaHitsFromRun(runDir string, run WorkflowRun, verbose bool) ([]SafeOutputQuotaHit, error) {
+	logsSafeOutputQuotaLog.Printf("Extracting safe output quota hits from run: %d", run.DatabaseID)
+
+	workflowLogsDir := filepath.Join(runDir, "workflow-logs")
+	if _, err := os.Stat(workflowLogsDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var hits []SafeOutputQuotaHit
+	seen := make(map[string]bool)
+	err := filepath.Walk(workflowLogsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(strings.ToLower(info.Name()), ".txt") {
+			return nil
+		}
+
+		fileHits, parseErr := parseSafeOutputQuotaHits(path)
+		if parseErr != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to parse safe output quotas from %s: %v", filepath.Base(path), parseErr)))
+			}
+			return nil // Continue processing other files
+		}
+		for _, hit := range fileHits {
+			// The same job log can appear both in a job file and in its step files
+			key := hit.Type + "/" + hit.Quota
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			hit.WorkflowName = run.WorkflowName
+			hit.RunID = run.DatabaseID
+			hits = append(hits, hit)
+		}
+		return nil
+	})
+	if err != nil {
+		return hits, fmt.Errorf("error walking workflow logs directory: %w", err)
+	}
+
+	if verbose && len(hits) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Found %d safe output quotas reached in run %d", len(hits), run.DatabaseID)))
+	}
+	logsSafeOutputQuotaLog.Printf("Found %d safe output quota hits", len(hits))
+
+	return hits, nil
+}
+
+// parseSafeOutputQuotaHits parses a single job log file for quota warnings
+func parseSafeOutputQuotaHits(logPath string) ([]SafeOutputQuotaHit, error) {
+	file, err := os.Open(logPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading log file: %w", err)
+	}
+	defer file.Close()
+
+	var hits []SafeOutputQuotaHit
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		match := safeOutputQuotaPattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		limit, _ := strconv.Atoi(match[3])
+		hits = append(hits, SafeOutputQuotaHit{
+			Type:   match[1],
+			Quota:  match[2],
+			Limit:  limit,
+			Detail: match[4],
+		})
+	}
+	return hits, scanner.Err()
+}
+
+// buildSafeOutputQuotaSummary aggregates safe output quota hits across all runs
+func buildSafeOutputQuotaSummary(processedRuns []ProcessedRun) []SafeOutputQuotaSummary {
+	result := aggregateSummaryItems(
+		processedRuns,
+		// getItems: extract quota hits from each run
+		func(pr ProcessedRun) []SafeOutputQuotaHit {
+			return pr.SafeOutputQuotaHits
+		},
+		// getKey: aggregate by safe output type, quota and limit
+		func(hit SafeOutputQuotaHit) string {
+			return fmt.Sprintf("%s/%s/%d", hit.Type, hit.Quota, hit.Limit)
+		},
+		// createSummary: create new summary for first occurrence
+		func(hit SafeOutputQuotaHit) *SafeOutputQuotaSummary {
+			return &SafeOutputQuotaSummary{
+				Type:      hit.Type,
+				Quota:     hit.Quota,
+				Limit:     hit.Limit,
+				Count:     1,
+				Workflows: []string{hit.WorkflowName},
+				RunIDs:    []int64{hit.RunID},
+			}
+		},
+		// updateSummary: update existing summary with new occurrence
+		func(summary *SafeOutputQuotaSummary, hit SafeOutputQuotaHit) {
+			summary.Count++
+			summary.Workflows = addUniqueWorkflow(summary.Workflows, hit.WorkflowName)
+			summary.RunIDs = append(summary.RunIDs, hit.RunID)
+		},
+		// finalizeSummary: populate display fields for console rendering
+		func(summary *SafeOutputQuotaSummary) {
+			summary.WorkflowsDisplay = strings.Join(summary.Workflows, ", ")
+		},
+	)
+
+	// Sort by count descending
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Count > result[j].Count
+	})
+
+	return result
+}
@@ -0,0 +1,60 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractSafeOutputQuotaHitsFromRun(t *testing.T) {
+	runDir := testutil.TempDir(t, "test-*")
+	run := WorkflowRun{DatabaseID: 42, WorkflowName: "Daily Report"}
+
+	t.Run("no workflow logs", func(t *testing.T) {
+		hits, err := extractSafeOutputQuotaHitsFromRun(runDir, run, false)
+		require.NoError(t, err)
+		assert.Empty(t, hits)
+	})
+
+	jobLog := `2025-06-30T12:00:01.0000000Z Quota for create_issue: max-per-day=5, max-open=unlimited
+2025-06-30T12:00:02.0000000Z ##[warning]Safe output quota reached: create_issue max-per-day=5 (5 created in the last 24 hours)
+2025-06-30T12:00:03.0000000Z ##[warning]Safe output quota reached: add_comment max-per-day=20 (20 created in the last 24 hours)
+`
+	stepDir := filepath.Join(runDir, "workflow-logs", "safe_outputs")
+	require.NoError(t, os.MkdirAll(stepDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "workflow-logs", "3_safe_outputs.txt"), []byte(jobLog), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(stepDir, "4_Process Safe Outputs.txt"), []byte(jobLog), 0644))
+
+	hits, err := extractSafeOutputQuotaHitsFromRun(runDir, run, false)
+	require.NoError(t, err)
+	assert.Equal(t, []SafeOutputQuotaHit{
+		{Type: "create_issue", Quota: "max-per-day", Limit: 5, Detail: "5 created in the last 24 hours", WorkflowName: "Daily Report", RunID: 42},
+		{Type: "add_comment", Quota: "max-per-day", Limit: 20, Detail: "20 created in the last 24 hours", WorkflowName: "Daily Report", RunID: 42},
+	}, hits, "hits repeated in job and step logs should be reported once")
+}
+
+func TestBuildSafeOutputQuotaSummary(t *testing.T) {
+	processedRuns := []ProcessedRun{
+		{SafeOutputQuotaHits: []SafeOutputQuotaHit{
+			{Type: "create_issue", Quota: "max-open", Limit: 3, WorkflowName: "triage", RunID: 1},
+		}},
+		{SafeOutputQuotaHits: []SafeOutputQuotaHit{
+			{Type: "create_issue", Quota: "max-open", Limit: 3, WorkflowName: "triage", RunID: 2},
+			{Type: "add_comment", Quota: "max-per-day", Limit: 10, WorkflowName: "digest", RunID: 2},
+		}},
+	}
+
+	summary := buildSafeOutputQuotaSummary(processedRuns)
+	require.Len(t, summary, 2)
+	assert.Equal(t, "create_issue", summary[0].Type)
+	assert.Equal(t, 2, summary[0].Count)
+	assert.Equal(t, []int64{1, 2}, summary[0].RunIDs)
+	assert.Equal(t, "triage", summary[0].WorkflowsDisplay)
+	assert.Equal(t, "add_comment", summary[1].Type)
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/spf13/cobra"
+)
+
+// NewLSPCommand creates the lsp command
+func NewLSPCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lsp",
+		Short: "Run a language server for agentic workflow markdown files",
+		Long: `Run a Language Server Protocol (LSP) server over stdio for agentic workflow files.
+
+Editors can use the server for workflow markdown files in .github/workflows to get:
+  - Diagnostics from schema validation and the compiler validators as you type
+  - Completion of frontmatter keys and values from the workflow schema
+  - Hover documentation for frontmatter fields
+  - Go to definition on imports entries and {{#import}} / @include directives
+  - Code actions applying the codemods of the fix command
+
+Diagnostics compile the unsaved editor content without writing lock files.
+Protocol messages are exchanged on stdin/stdout; logs are written to stderr.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` lsp           # Start the language server on stdio
+  ` + string(constants.CLIExtensionPrefix) + ` lsp --stdio   # Same, for editors that always pass --stdio`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunLSP()
+		},
+	}
+
+	// Accepted for compatibility with editor clients; stdio is the only transport
+	cmd.Flags().Bool("stdio", true, "Use stdio transport (default)")
+
+	return cmd
+}
+
+// RunLSP runs the language server on stdin/stdout until the client exits
+func RunLSP() error {
+	// Protocol messages own stdout. Redirect anything else printed to stdout
+	// (e.g. by the compiler) to stderr so it cannot corrupt the stream.
+	protocolOut := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = protocolOut }()
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage("gh-aw language server started on stdio"))
+	server := newLSPServer(protocolOut)
+	return server.serve(os.Stdin)
+}
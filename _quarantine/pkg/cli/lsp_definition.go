@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+// lspDefinition resolves the import on a line of a workflow document to the imported file.
+// Both frontmatter imports entries and markdown {{#import}} / @include directives are
+// supported. Only local files are resolved; workflowspec imports are not downloaded.
+func lspDefinition(path string, lines []string, line int) (lspLocation, bool) {
+	importPath := lspImportPathOnLine(lines, line)
+	if importPath == "" {
+		return lspLocation{}, false
+	}
+
+	// Drop the section reference (file.md#Section)
+	importPath, _, _ = strings.Cut(importPath, "#")
+	target := importPath
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(path), importPath)
+	}
+	if info, err := os.Stat(target); err != nil || info.IsDir() {
+		lspLog.Printf("Definition target not found: %s", target)
+		return lspLocation{}, false
+	}
+	return lspLocation{URI: lspPathToURI(target)}, true
+}
+
+// lspImportPathOnLine returns the import path referenced on a line, or ""
+func lspImportPathOnLine(lines []string, line int) string {
+	fm := findLSPFrontmatter(lines)
+	if fm.present && fm.end > 0 && line > 0 && line < fm.end {
+		ctx := resolveLSPYAMLContext(lines, line, len(lines[line]))
+		if !slices.Equal(ctx.Path, []string{"imports", lspListItemSegment}) {
+			return ""
+		}
+		if ctx.Key == "" || ctx.Key == "path" {
+			return strings.Trim(ctx.Prefix, "\"'")
+		}
+		return ""
+	}
+
+	if directive := parser.ParseImportDirective(lines[line]); directive != nil {
+		return directive.Path
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+// lspDiagnosticSource is the source shown next to diagnostics published by the language server
+const lspDiagnosticSource = "gh-aw"
+
+// compilerErrorLinePattern matches the IDE-parseable "file:line:column: type: message" format of compiler errors
+var compilerErrorLinePattern = regexp.MustCompile(`^(.+?):(\d+):(\d+):\s+(error|warning):\s+(.*)$`)
+
+// lspFrontmatter locates the frontmatter of a workflow document.
+// end is the line index of the closing "---" or -1 when the frontmatter is not closed.
+type lspFrontmatter struct {
+	present bool
+	end     int
+	yaml    string
+}
+
+// findLSPFrontmatter returns the frontmatter bounds of a document split into lines
+func findLSPFrontmatter(lines []string) lspFrontmatter {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return lspFrontmatter{end: -1}
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return lspFrontmatter{present: true, end: i, yaml: strings.Join(lines[1:i], "\n")}
+		}
+	}
+	return lspFrontmatter{present: true, end: -1}
+}
+
+// computeLSPDiagnostics returns the diagnostics of a workflow document. Frontmatter syntax and
+// schema problems are computed from the content as typed. When compile is set and the
+// frontmatter is valid, the content is also compiled (without writing a lock file) so the
+// compiler validators run against the unsaved buffer.
+func computeLSPDiagnostics(path, content string, compile bool) []lspDiagnostic {
+	lines := strings.Split(content, "\n")
+	fm := findLSPFrontmatter(lines)
+	if !fm.present {
+		return nil
+	}
+	if fm.end < 0 {
+		return []lspDiagnostic{newLSPDiagnostic(lines, 0, 0, lspSeverityError, "frontmatter not properly closed: add a closing '---' line")}
+	}
+
+	var frontmatter map[string]any
+	if err := yaml.Unmarshal([]byte(fm.yaml), &frontmatter); err != nil {
+		line, column, message := parser.ExtractYAMLError(err, 2)
+		if message == "" {
+			message = err.Error()
+		}
+		// Drop the source excerpt, the editor shows the location
+		message, _, _ = strings.Cut(message, "\n")
+		docLine := 0
+		if line > 0 {
+			docLine = line - 1
+		}
+		return []lspDiagnostic{newLSPDiagnostic(lines, docLine, max(column-1, 0), lspSeverityError, "invalid YAML: "+message)}
+	}
+	if frontmatter == nil {
+		frontmatter = make(map[string]any)
+	}
+
+	// Files without a trigger are shared workflows meant to be imported
+	if _, hasOn := frontmatter["on"]; !hasOn {
+		if err := parser.ValidateIncludedFileFrontmatterWithSchema(frontmatter); err != nil {
+			return []lspDiagnostic{newLSPDiagnostic(lines, 0, 0, lspSeverityError, parser.StripANSI(err.Error()))}
+		}
+		return nil
+	}
+
+	var diagnostics []lspDiagnostic
+	for _, d := range parser.ValidateMainWorkflowFrontmatterDiagnostics(frontmatter, fm.yaml) {
+		// Frontmatter line 1 is document line index 1 (after the opening ---)
+		diagnostics = append(diagnostics, newLSPDiagnostic(lines, d.Line, max(d.Column-1, 0), lspSeverityError, parser.StripANSI(d.Message)))
+	}
+	if len(diagnostics) > 0 || !compile {
+		return diagnostics
+	}
+
+	return compileLSPDiagnostics(path, content, lines)
+}
+
+// compileLSPDiagnostics compiles the document content without emitting a lock file and
+// converts the compiler errors into diagnostics
+func compileLSPDiagnostics(path, content string, lines []string) []lspDiagnostic {
+	compiler := workflow.NewCompiler(workflow.WithNoEmit(true))
+	compiler.SetQuiet(true)
+	compiler.SetMarkdownContent(path, []byte(content))
+
+	err := compiler.CompileWorkflow(path)
+	if err == nil {
+		return nil
+	}
+	var sharedErr *workflow.SharedWorkflowError
+	if errors.As(err, &sharedErr) {
+		return nil
+	}
+	return compilerErrorToLSPDiagnostics(path, parser.StripANSI(err.Error()), lines)
+}
+
+// compilerErrorToLSPDiagnostics parses compiler error output into diagnostics. Errors located
+// in the document are placed at their position; everything else is reported on the first line.
+func compilerErrorToLSPDiagnostics(path, errorText string, lines []string) []lspDiagnostic {
+	var diagnostics []lspDiagnostic
+	var unlocated []string
+	for _, line := range strings.Split(errorText, "\n") {
+		match := compilerErrorLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		severity := lspSeverityError
+		if match[4] == "warning" {
+			severity = lspSeverityWarning
+		}
+		if filepath.Base(match[1]) != filepath.Base(path) {
+			unlocated = append(unlocated, line)
+			continue
+		}
+		lineNum, _ := strconv.Atoi(match[2])
+		column, _ := strconv.Atoi(match[3])
+		diagnostics = append(diagnostics, newLSPDiagnostic(lines, max(lineNum-1, 0), max(column-1, 0), severity, match[5]))
+	}
+
+	if len(diagnostics) == 0 && len(unlocated) == 0 {
+		// Not in the file:line:column format, report the first line of the error
+		message, _, _ := strings.Cut(strings.TrimSpace(errorText), "\n")
+		return []lspDiagnostic{newLSPDiagnostic(lines, 0, 0, lspSeverityError, message)}
+	}
+	for _, message := range unlocated {
+		diagnostics = append(diagnostics, newLSPDiagnostic(lines, 0, 0, lspSeverityError, strings.TrimSpace(message)))
+	}
+	return diagnostics
+}
+
+// newLSPDiagnostic creates a diagnostic spanning a line from the given byte column
+func newLSPDiagnostic(lines []string, line, column, severity int, message string) lspDiagnostic {
+	return lspDiagnostic{
+		Range:    lspLineRange(lines, line, column),
+		Severity: severity,
+		Source:   lspDiagnosticSource,
+		Message:  message,
+	}
+}
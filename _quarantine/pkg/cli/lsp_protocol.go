@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// This file holds the subset of the Language Server Protocol (JSON-RPC 2.0 over
+// Content-Length framed stdio) used by the gh aw lsp command.
+
+// JSON-RPC error codes
+const (
+	lspErrorParse          = -32700
+	lspErrorMethodNotFound = -32601
+	lspErrorInvalidParams  = -32602
+	lspErrorInternal       = -32603
+)
+
+// LSP diagnostic severities
+const (
+	lspSeverityError   = 1
+	lspSeverityWarning = 2
+)
+
+// LSP completion item kinds
+const (
+	lspCompletionKindValue    = 12
+	lspCompletionKindProperty = 10
+)
+
+// lspMessage is an incoming JSON-RPC request or notification
+type lspMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// lspResponse is an outgoing JSON-RPC response
+type lspResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+	Error   *lspError       `json:"error,omitempty"`
+}
+
+// lspNotification is an outgoing JSON-RPC notification
+type lspNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// lspError is a JSON-RPC error object
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspTextDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type lspTextDocumentPositionParams struct {
+	TextDocument lspTextDocumentIdentifier `json:"textDocument"`
+	Position     lspPosition               `json:"position"`
+}
+
+type lspDidOpenParams struct {
+	TextDocument struct {
+		URI     string `json:"uri"`
+		Version int    `json:"version"`
+		Text    string `json:"text"`
+	} `json:"textDocument"`
+}
+
+type lspDidChangeParams struct {
+	TextDocument struct {
+		URI     string `json:"uri"`
+		Version int    `json:"version"`
+	} `json:"textDocument"`
+	ContentChanges []struct {
+		Range *lspRange `json:"range,omitempty"`
+		Text  string    `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type lspDidSaveParams struct {
+	TextDocument lspTextDocumentIdentifier `json:"textDocument"`
+	Text         *string                   `json:"text,omitempty"`
+}
+
+type lspCodeActionParams struct {
+	TextDocument lspTextDocumentIdentifier `json:"textDocument"`
+	Range        lspRange                  `json:"range"`
+}
+
+type lspCompletionItem struct {
+	Label         string `json:"label"`
+	Kind          int    `json:"kind,omitempty"`
+	Detail        string `json:"detail,omitempty"`
+	Documentation string `json:"documentation,omitempty"`
+	InsertText    string `json:"insertText,omitempty"`
+}
+
+type lspMarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type lspHover struct {
+	Contents lspMarkupContent `json:"contents"`
+	Range    *lspRange        `json:"range,omitempty"`
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspWorkspaceEdit struct {
+	Changes map[string][]lspTextEdit `json:"changes"`
+}
+
+type lspCodeAction struct {
+	Title string            `json:"title"`
+	Kind  string            `json:"kind"`
+	Edit  *lspWorkspaceEdit `json:"edit,omitempty"`
+}
+
+// readLSPMessage reads one Content-Length framed message
+func readLSPMessage(r *bufio.Reader) ([]byte, error) {
+	contentLength := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			contentLength, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length header: %w", err)
+			}
+		}
+	}
+	if contentLength < 0 {
+		return nil, errors.New("missing Content-Length header")
+	}
+
+	body := make([]byte, contentLength)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// writeLSPMessage writes one Content-Length framed message
+func writeLSPMessage(w io.Writer, msg any) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// lspURIToPath converts a file:// URI to a local path
+func lspURIToPath(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(parsed.Path)
+}
+
+// lspPathToURI converts a local path to a file:// URI
+func lspPathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// lspUTF16Column converts a byte offset in a line to a UTF-16 column as used by LSP positions
+func lspUTF16Column(line string, byteOffset int) int {
+	if byteOffset > len(line) {
+		byteOffset = len(line)
+	}
+	return len(utf16.Encode([]rune(line[:byteOffset])))
+}
+
+// lspByteOffset converts an LSP UTF-16 column to a byte offset in a line
+func lspByteOffset(line string, column int) int {
+	units := 0
+	for i, r := range line {
+		if units >= column {
+			return i
+		}
+		units += utf16.RuneLen(r)
+	}
+	return len(line)
+}
+
+// lspLineRange returns the range covering the text of a line from a byte offset to its end
+func lspLineRange(lines []string, line, startByte int) lspRange {
+	if line < 0 || line >= len(lines) {
+		return lspRange{Start: lspPosition{Line: max(line, 0)}, End: lspPosition{Line: max(line, 0)}}
+	}
+	text := lines[line]
+	startByte = min(max(startByte, 0), len(text))
+	// Skip leading whitespace so the squiggle starts at the offending text
+	for startByte < len(text) && (text[startByte] == ' ' || text[startByte] == '\t') {
+		startByte++
+	}
+	return lspRange{
+		Start: lspPosition{Line: line, Character: lspUTF16Column(text, startByte)},
+		End:   lspPosition{Line: line, Character: lspUTF16Column(text, len(text))},
+	}
+}
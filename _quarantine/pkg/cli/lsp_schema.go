@@ -0,0 +1,406 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+// lspListItemSegment is the path segment used for the items of a YAML list
+const lspListItemSegment = "[]"
+
+// maxSchemaRefDepth bounds $ref and composition expansion to guard against cyclic schemas
+const maxSchemaRefDepth = 16
+
+var (
+	lspSchemaOnce sync.Once
+	lspSchemaRoot map[string]any
+)
+
+// getLSPSchema returns the parsed main workflow schema used for completion and hover
+func getLSPSchema() map[string]any {
+	lspSchemaOnce.Do(func() {
+		if err := json.Unmarshal([]byte(parser.GetMainWorkflowSchema()), &lspSchemaRoot); err != nil {
+			lspLog.Printf("Failed to parse main workflow schema: %v", err)
+			lspSchemaRoot = make(map[string]any)
+		}
+	})
+	return lspSchemaRoot
+}
+
+// lspYAMLContext describes the frontmatter position of the cursor
+type lspYAMLContext struct {
+	Path    []string // path of the mapping containing the cursor ("[]" for list items)
+	Key     string   // key of the current line, if any
+	InValue bool     // cursor is after "key:"
+	Prefix  string   // text typed so far for the key or value
+}
+
+// resolveLSPYAMLContext computes the YAML path at a cursor position from indentation.
+// lines are the document lines and line is a line index inside the frontmatter.
+func resolveLSPYAMLContext(lines []string, line, byteOffset int) lspYAMLContext {
+	text := lines[line]
+	if byteOffset > len(text) {
+		byteOffset = len(text)
+	}
+	before := text[:byteOffset]
+
+	indent := len(before) - len(strings.TrimLeft(before, " "))
+	content := strings.TrimLeft(before, " ")
+
+	var ctx lspYAMLContext
+	var suffix []string
+	if rest, ok := strings.CutPrefix(content, "- "); ok {
+		suffix = []string{lspListItemSegment}
+		content = rest
+	} else if content == "-" {
+		suffix = []string{lspListItemSegment}
+		content = ""
+	}
+
+	if key, value, ok := strings.Cut(content, ":"); ok && !strings.ContainsAny(key, " \"'{[") {
+		ctx.Key = key
+		ctx.InValue = true
+		ctx.Prefix = strings.Trim(strings.TrimSpace(value), "\"'")
+	} else {
+		ctx.Prefix = strings.TrimSpace(content)
+	}
+
+	ctx.Path = append(lspParentPath(lines, line, indent), suffix...)
+	return ctx
+}
+
+// lspParentPath returns the keys of the mappings enclosing a line indented by indent,
+// scanning upward through the frontmatter.
+func lspParentPath(lines []string, line, indent int) []string {
+	var reversed []string
+	threshold := indent
+	for i := line - 1; i > 0 && threshold > 0; i-- {
+		text := lines[i]
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		lineIndent := len(text) - len(strings.TrimLeft(text, " "))
+		content := strings.TrimLeft(text, " ")
+
+		if rest, ok := strings.CutPrefix(content, "- "); ok || content == "-" {
+			keyIndent := lineIndent + 2
+			if key := lspLineKey(rest); key != "" && keyIndent < threshold {
+				reversed = append(reversed, key)
+				threshold = keyIndent
+			}
+			if lineIndent < threshold {
+				reversed = append(reversed, lspListItemSegment)
+				threshold = lineIndent
+			}
+			continue
+		}
+		if lineIndent < threshold {
+			if key := lspLineKey(content); key != "" {
+				reversed = append(reversed, key)
+			}
+			threshold = lineIndent
+		}
+	}
+
+	path := make([]string, 0, len(reversed))
+	for i := len(reversed) - 1; i >= 0; i-- {
+		path = append(path, reversed[i])
+	}
+	return path
+}
+
+// lspLineKey returns the mapping key of a line content, or "" when the line is not a key
+func lspLineKey(content string) string {
+	key, _, ok := strings.Cut(content, ":")
+	if !ok || key == "" || strings.ContainsAny(key, " \"'{[#") {
+		return ""
+	}
+	return key
+}
+
+// resolveSchemaRef follows a local "#/..." JSON pointer $ref
+func resolveSchemaRef(root map[string]any, ref string) map[string]any {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil
+	}
+	var current any = root
+	for _, segment := range strings.Split(pointer, "/") {
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		node, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = node[segment]
+	}
+	node, _ := current.(map[string]any)
+	return node
+}
+
+// expandSchemaNode flattens a schema node into itself, its $ref target and its
+// oneOf/anyOf/allOf variants, keeping the original node first so its description wins
+func expandSchemaNode(root, node map[string]any) []map[string]any {
+	var nodes []map[string]any
+	var visit func(n map[string]any, depth int)
+	visit = func(n map[string]any, depth int) {
+		if n == nil || depth > maxSchemaRefDepth {
+			return
+		}
+		nodes = append(nodes, n)
+		if ref, ok := n["$ref"].(string); ok {
+			visit(resolveSchemaRef(root, ref), depth+1)
+		}
+		for _, keyword := range []string{"allOf", "oneOf", "anyOf"} {
+			variants, _ := n[keyword].([]any)
+			for _, variant := range variants {
+				if v, ok := variant.(map[string]any); ok {
+					visit(v, depth+1)
+				}
+			}
+		}
+	}
+	visit(node, 0)
+	return nodes
+}
+
+// schemaChildren returns the schema nodes for a path segment below the given nodes
+func schemaChildren(root map[string]any, nodes []map[string]any, segment string) []map[string]any {
+	var children []map[string]any
+	for _, node := range nodes {
+		for _, n := range expandSchemaNode(root, node) {
+			if segment == lspListItemSegment {
+				if items, ok := n["items"].(map[string]any); ok {
+					children = append(children, items)
+				}
+				continue
+			}
+			if props, ok := n["properties"].(map[string]any); ok {
+				if child, ok := props[segment].(map[string]any); ok {
+					children = append(children, child)
+					continue
+				}
+			}
+			if patterns, ok := n["patternProperties"].(map[string]any); ok {
+				for pattern, child := range patterns {
+					if re, err := regexp.Compile(pattern); err == nil && re.MatchString(segment) {
+						if c, ok := child.(map[string]any); ok {
+							children = append(children, c)
+						}
+					}
+				}
+			}
+			if additional, ok := n["additionalProperties"].(map[string]any); ok {
+				children = append(children, additional)
+			}
+		}
+	}
+	return children
+}
+
+// schemaNodesAtPath returns the schema nodes describing the value at a YAML path
+func schemaNodesAtPath(path []string) []map[string]any {
+	root := getLSPSchema()
+	nodes := []map[string]any{root}
+	for _, segment := range path {
+		nodes = schemaChildren(root, nodes, segment)
+		if len(nodes) == 0 {
+			return nil
+		}
+	}
+	return nodes
+}
+
+// lspSchemaProperty is a property offered for completion
+type lspSchemaProperty struct {
+	Name        string
+	Description string
+	Types       []string
+}
+
+// schemaProperties returns the properties allowed by the given nodes, sorted by name
+func schemaProperties(nodes []map[string]any) []lspSchemaProperty {
+	root := getLSPSchema()
+	byName := make(map[string]lspSchemaProperty)
+	for _, node := range nodes {
+		for _, n := range expandSchemaNode(root, node) {
+			props, _ := n["properties"].(map[string]any)
+			for name, child := range props {
+				if _, seen := byName[name]; seen {
+					continue
+				}
+				c, _ := child.(map[string]any)
+				childNodes := expandSchemaNode(root, c)
+				byName[name] = lspSchemaProperty{
+					Name:        name,
+					Description: schemaDescription(childNodes),
+					Types:       schemaTypes(childNodes),
+				}
+			}
+		}
+	}
+
+	properties := make([]lspSchemaProperty, 0, len(byName))
+	for _, name := range sortedKeys(byName) {
+		properties = append(properties, byName[name])
+	}
+	return properties
+}
+
+// schemaValues returns the scalar values suggested by the given nodes (enum, const,
+// booleans and scalar examples)
+func schemaValues(nodes []map[string]any) []string {
+	root := getLSPSchema()
+	seen := make(map[string]bool)
+	var values []string
+	add := func(v any) {
+		var s string
+		switch value := v.(type) {
+		case string:
+			s = value
+		case bool, float64:
+			s = fmt.Sprint(value)
+		default:
+			return
+		}
+		if !seen[s] {
+			seen[s] = true
+			values = append(values, s)
+		}
+	}
+	for _, node := range nodes {
+		for _, n := range expandSchemaNode(root, node) {
+			if enum, ok := n["enum"].([]any); ok {
+				for _, v := range enum {
+					add(v)
+				}
+			}
+			if c, ok := n["const"]; ok {
+				add(c)
+			}
+			if examples, ok := n["examples"].([]any); ok {
+				for _, v := range examples {
+					add(v)
+				}
+			}
+			if n["type"] == "boolean" {
+				add(true)
+				add(false)
+			}
+		}
+	}
+	return values
+}
+
+// schemaDescription returns the first description of the given nodes
+func schemaDescription(nodes []map[string]any) string {
+	for _, n := range nodes {
+		if description, ok := n["description"].(string); ok && description != "" {
+			return description
+		}
+	}
+	return ""
+}
+
+// schemaTypes returns the distinct JSON types accepted by the given nodes
+func schemaTypes(nodes []map[string]any) []string {
+	seen := make(map[string]bool)
+	for _, n := range nodes {
+		switch t := n["type"].(type) {
+		case string:
+			seen[t] = true
+		case []any:
+			for _, v := range t {
+				if s, ok := v.(string); ok {
+					seen[s] = true
+				}
+			}
+		}
+	}
+	types := make([]string, 0, len(seen))
+	for t := range seen {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// lspCompletions returns frontmatter completions for a cursor position
+func lspCompletions(lines []string, line, byteOffset int) []lspCompletionItem {
+	ctx := resolveLSPYAMLContext(lines, line, byteOffset)
+
+	if ctx.InValue {
+		nodes := schemaNodesAtPath(append(ctx.Path, ctx.Key))
+		var items []lspCompletionItem
+		for _, value := range schemaValues(nodes) {
+			if strings.HasPrefix(value, ctx.Prefix) {
+				items = append(items, lspCompletionItem{Label: value, Kind: lspCompletionKindValue})
+			}
+		}
+		return items
+	}
+
+	var items []lspCompletionItem
+	for _, prop := range schemaProperties(schemaNodesAtPath(ctx.Path)) {
+		if !strings.HasPrefix(prop.Name, ctx.Prefix) {
+			continue
+		}
+		items = append(items, lspCompletionItem{
+			Label:         prop.Name,
+			Kind:          lspCompletionKindProperty,
+			Detail:        strings.Join(prop.Types, " | "),
+			Documentation: prop.Description,
+			InsertText:    prop.Name + ": ",
+		})
+	}
+	return items
+}
+
+// lspHoverMarkdown returns the hover documentation for the key on a frontmatter line
+func lspHoverMarkdown(lines []string, line int) (string, lspRange, bool) {
+	text := lines[line]
+	indent := len(text) - len(strings.TrimLeft(text, " "))
+	start := indent
+	if strings.HasPrefix(text[indent:], "- ") {
+		start += 2
+	}
+	key := lspLineKey(text[start:])
+	if key == "" {
+		return "", lspRange{}, false
+	}
+
+	ctx := resolveLSPYAMLContext(lines, line, start+len(key)+1)
+	nodes := schemaNodesAtPath(append(ctx.Path, key))
+	if len(nodes) == 0 {
+		return "", lspRange{}, false
+	}
+	root := getLSPSchema()
+	var expanded []map[string]any
+	for _, n := range nodes {
+		expanded = append(expanded, expandSchemaNode(root, n)...)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%s**", key)
+	if description := schemaDescription(expanded); description != "" {
+		sb.WriteString("\n\n" + description)
+	}
+	if types := schemaTypes(expanded); len(types) > 0 {
+		sb.WriteString("\n\nType: `" + strings.Join(types, "` | `") + "`")
+	}
+	if values := schemaValues(nodes); len(values) > 0 {
+		sb.WriteString("\n\nAllowed values: `" + strings.Join(values, "`, `") + "`")
+	}
+
+	keyRange := lspRange{
+		Start: lspPosition{Line: line, Character: lspUTF16Column(text, start)},
+		End:   lspPosition{Line: line, Character: lspUTF16Column(text, start+len(key))},
+	}
+	return sb.String(), keyRange, true
+}
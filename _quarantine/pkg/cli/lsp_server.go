@@ -0,0 +1,410 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var lspLog = logger.New("cli:lsp")
+
+// lspDiagnosticsDelay is how long the server waits after the last change before
+// recomputing diagnostics, so compilation does not run on every keystroke
+const lspDiagnosticsDelay = 300 * time.Millisecond
+
+// lspServer is a language server for agentic workflow markdown files
+type lspServer struct {
+	out     io.Writer
+	writeMu sync.Mutex
+
+	mu        sync.Mutex
+	documents map[string]string
+	timers    map[string]*time.Timer
+
+	// compileMu serializes diagnostics so only one compilation runs at a time
+	compileMu sync.Mutex
+
+	delay    time.Duration
+	shutdown bool
+}
+
+// newLSPServer creates a language server writing protocol messages to out
+func newLSPServer(out io.Writer) *lspServer {
+	return &lspServer{
+		out:       out,
+		documents: make(map[string]string),
+		timers:    make(map[string]*time.Timer),
+		delay:     lspDiagnosticsDelay,
+	}
+}
+
+// serve reads and handles messages until the client sends exit or closes the input
+func (s *lspServer) serve(in io.Reader) error {
+	reader := bufio.NewReader(in)
+	for {
+		body, err := readLSPMessage(reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read message: %w", err)
+		}
+
+		var msg lspMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			s.reply(nil, nil, &lspError{Code: lspErrorParse, Message: err.Error()})
+			continue
+		}
+		if msg.Method == "exit" {
+			lspLog.Print("Received exit")
+			s.stopTimers()
+			return nil
+		}
+		s.handle(msg)
+	}
+}
+
+// handle dispatches a request or notification
+func (s *lspServer) handle(msg lspMessage) {
+	lspLog.Printf("Handling %s", msg.Method)
+	isRequest := len(msg.ID) > 0
+
+	result, err := s.dispatch(msg)
+	if !isRequest {
+		if err != nil {
+			lspLog.Printf("Notification %s failed: %s", msg.Method, err.Message)
+		}
+		return
+	}
+	s.reply(msg.ID, result, err)
+}
+
+func (s *lspServer) dispatch(msg lspMessage) (any, *lspError) {
+	switch msg.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync":   map[string]any{"openClose": true, "change": 1, "save": map[string]any{"includeText": true}},
+				"completionProvider": map[string]any{"triggerCharacters": []string{":", " ", "-"}},
+				"hoverProvider":      true,
+				"definitionProvider": true,
+				"codeActionProvider": map[string]any{"codeActionKinds": []string{"quickfix", "source.fixAll"}},
+			},
+			"serverInfo": map[string]any{"name": "gh-aw", "version": GetVersion()},
+		}, nil
+	case "initialized", "$/cancelRequest", "$/setTrace", "workspace/didChangeConfiguration":
+		return nil, nil
+	case "shutdown":
+		s.mu.Lock()
+		s.shutdown = true
+		s.mu.Unlock()
+		s.stopTimers()
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params lspDidOpenParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidLSPParams(err)
+		}
+		s.setDocument(params.TextDocument.URI, params.TextDocument.Text)
+		s.scheduleDiagnostics(params.TextDocument.URI, 0)
+		return nil, nil
+	case "textDocument/didChange":
+		var params lspDidChangeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidLSPParams(err)
+		}
+		s.mu.Lock()
+		content := s.documents[params.TextDocument.URI]
+		for _, change := range params.ContentChanges {
+			content = applyLSPContentChange(content, change.Range, change.Text)
+		}
+		s.documents[params.TextDocument.URI] = content
+		s.mu.Unlock()
+		s.scheduleDiagnostics(params.TextDocument.URI, s.delay)
+		return nil, nil
+	case "textDocument/didSave":
+		var params lspDidSaveParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidLSPParams(err)
+		}
+		if params.Text != nil {
+			s.setDocument(params.TextDocument.URI, *params.Text)
+		}
+		s.scheduleDiagnostics(params.TextDocument.URI, 0)
+		return nil, nil
+	case "textDocument/didClose":
+		var params struct {
+			TextDocument lspTextDocumentIdentifier `json:"textDocument"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidLSPParams(err)
+		}
+		s.closeDocument(params.TextDocument.URI)
+		return nil, nil
+
+	case "textDocument/completion":
+		var params lspTextDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidLSPParams(err)
+		}
+		lines, byteOffset, ok := s.frontmatterPosition(params)
+		if !ok {
+			return []lspCompletionItem{}, nil
+		}
+		items := lspCompletions(lines, params.Position.Line, byteOffset)
+		if items == nil {
+			items = []lspCompletionItem{}
+		}
+		return items, nil
+	case "textDocument/hover":
+		var params lspTextDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidLSPParams(err)
+		}
+		lines, _, ok := s.frontmatterPosition(params)
+		if !ok {
+			return nil, nil
+		}
+		markdown, keyRange, ok := lspHoverMarkdown(lines, params.Position.Line)
+		if !ok {
+			return nil, nil
+		}
+		return lspHover{Contents: lspMarkupContent{Kind: "markdown", Value: markdown}, Range: &keyRange}, nil
+	case "textDocument/definition":
+		var params lspTextDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidLSPParams(err)
+		}
+		content, ok := s.document(params.TextDocument.URI)
+		lines := strings.Split(content, "\n")
+		if !ok || params.Position.Line >= len(lines) {
+			return nil, nil
+		}
+		location, ok := lspDefinition(lspURIToPath(params.TextDocument.URI), lines, params.Position.Line)
+		if !ok {
+			return nil, nil
+		}
+		return location, nil
+	case "textDocument/codeAction":
+		var params lspCodeActionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidLSPParams(err)
+		}
+		content, ok := s.document(params.TextDocument.URI)
+		if !ok {
+			return []lspCodeAction{}, nil
+		}
+		return lspCodemodActions(params.TextDocument.URI, content), nil
+	}
+
+	return nil, &lspError{Code: lspErrorMethodNotFound, Message: "method not found: " + msg.Method}
+}
+
+func invalidLSPParams(err error) *lspError {
+	return &lspError{Code: lspErrorInvalidParams, Message: err.Error()}
+}
+
+// frontmatterPosition returns the document lines and the byte offset of a position
+// when the position is inside the frontmatter
+func (s *lspServer) frontmatterPosition(params lspTextDocumentPositionParams) ([]string, int, bool) {
+	content, ok := s.document(params.TextDocument.URI)
+	if !ok {
+		return nil, 0, false
+	}
+	lines := strings.Split(content, "\n")
+	fm := findLSPFrontmatter(lines)
+	line := params.Position.Line
+	if !fm.present || line <= 0 || line >= len(lines) || (fm.end >= 0 && line >= fm.end) {
+		return nil, 0, false
+	}
+	return lines, lspByteOffset(lines[line], params.Position.Character), true
+}
+
+func (s *lspServer) document(uri string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, ok := s.documents[uri]
+	return content, ok
+}
+
+func (s *lspServer) setDocument(uri, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.documents[uri] = content
+}
+
+func (s *lspServer) closeDocument(uri string) {
+	s.mu.Lock()
+	delete(s.documents, uri)
+	if timer, ok := s.timers[uri]; ok {
+		timer.Stop()
+		delete(s.timers, uri)
+	}
+	s.mu.Unlock()
+
+	// Clear the diagnostics of the closed document
+	s.notify("textDocument/publishDiagnostics", map[string]any{"uri": uri, "diagnostics": []lspDiagnostic{}})
+}
+
+// scheduleDiagnostics recomputes the diagnostics of a document after delay,
+// replacing any pending computation for the same document
+func (s *lspServer) scheduleDiagnostics(uri string, delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shutdown {
+		return
+	}
+	if timer, ok := s.timers[uri]; ok {
+		timer.Stop()
+	}
+	s.timers[uri] = time.AfterFunc(delay, func() { s.publishDiagnostics(uri) })
+}
+
+func (s *lspServer) stopTimers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for uri, timer := range s.timers {
+		timer.Stop()
+		delete(s.timers, uri)
+	}
+}
+
+// publishDiagnostics computes and sends the diagnostics of the current document content
+func (s *lspServer) publishDiagnostics(uri string) {
+	s.compileMu.Lock()
+	defer s.compileMu.Unlock()
+
+	content, ok := s.document(uri)
+	if !ok {
+		return
+	}
+	path := lspURIToPath(uri)
+	var diagnostics []lspDiagnostic
+	if strings.HasSuffix(path, ".md") {
+		diagnostics = computeLSPDiagnostics(path, content, true)
+	}
+	if diagnostics == nil {
+		diagnostics = []lspDiagnostic{}
+	}
+
+	// Skip stale results if the document changed while compiling
+	if current, ok := s.document(uri); !ok || current != content {
+		return
+	}
+	lspLog.Printf("Publishing %d diagnostics for %s", len(diagnostics), uri)
+	s.notify("textDocument/publishDiagnostics", map[string]any{"uri": uri, "diagnostics": diagnostics})
+}
+
+func (s *lspServer) reply(id json.RawMessage, result any, err *lspError) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	s.write(lspResponse{JSONRPC: "2.0", ID: id, Result: result, Error: err})
+}
+
+func (s *lspServer) notify(method string, params any) {
+	s.write(lspNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *lspServer) write(msg any) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := writeLSPMessage(s.out, msg); err != nil {
+		lspLog.Printf("Failed to write message: %v", err)
+	}
+}
+
+// applyLSPContentChange applies a didChange content change; a nil range replaces the document
+func applyLSPContentChange(content string, changeRange *lspRange, text string) string {
+	if changeRange == nil {
+		return text
+	}
+	start := lspContentOffset(content, changeRange.Start)
+	end := lspContentOffset(content, changeRange.End)
+	if end < start {
+		start, end = end, start
+	}
+	return content[:start] + text + content[end:]
+}
+
+// lspContentOffset converts a position to a byte offset in content
+func lspContentOffset(content string, pos lspPosition) int {
+	offset := 0
+	for line := 0; line < pos.Line; line++ {
+		next := strings.IndexByte(content[offset:], '\n')
+		if next < 0 {
+			return len(content)
+		}
+		offset += next + 1
+	}
+	lineEnd := strings.IndexByte(content[offset:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(content) - offset
+	}
+	return offset + lspByteOffset(content[offset:offset+lineEnd], pos.Character)
+}
+
+// lspCodemodActions returns a quick fix for each codemod that changes the document and a
+// fix-all action applying all of them, each as a whole-document edit
+func lspCodemodActions(uri, content string) []lspCodeAction {
+	actions := []lspCodeAction{}
+	fixed := content
+	var applied int
+	for _, codemod := range GetAllCodemods() {
+		if updated, ok := applyLSPCodemod(codemod, content); ok {
+			actions = append(actions, lspCodeAction{
+				Title: fmt.Sprintf("Fix: %s", codemod.Name),
+				Kind:  "quickfix",
+				Edit:  lspReplaceDocumentEdit(uri, content, updated),
+			})
+		}
+		if updated, ok := applyLSPCodemod(codemod, fixed); ok {
+			fixed = updated
+			applied++
+		}
+	}
+	if applied > 1 {
+		actions = append(actions, lspCodeAction{
+			Title: fmt.Sprintf("Fix all (%d codemods)", applied),
+			Kind:  "source.fixAll",
+			Edit:  lspReplaceDocumentEdit(uri, content, fixed),
+		})
+	}
+	return actions
+}
+
+// applyLSPCodemod runs a codemod on content, reporting whether it changed anything
+func applyLSPCodemod(codemod Codemod, content string) (string, bool) {
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		return "", false
+	}
+	updated, applied, err := codemod.Apply(content, result.Frontmatter)
+	if err != nil || !applied || updated == content {
+		return "", false
+	}
+	return updated, true
+}
+
+// lspReplaceDocumentEdit returns an edit replacing the whole document
+func lspReplaceDocumentEdit(uri, content, updated string) *lspWorkspaceEdit {
+	lines := strings.Split(content, "\n")
+	last := len(lines) - 1
+	return &lspWorkspaceEdit{Changes: map[string][]lspTextEdit{
+		uri: {{
+			Range: lspRange{
+				End: lspPosition{Line: last, Character: lspUTF16Column(lines[last], len(lines[last]))},
+			},
+			NewText: updated,
+		}},
+	}}
+}
@@ -0,0 +1,283 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLSPMessageFraming(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeLSPMessage(&buf, map[string]any{"jsonrpc": "2.0", "method": "initialized"}))
+	assert.True(t, strings.HasPrefix(buf.String(), "Content-Length: 40\r\n\r\n"), buf.String())
+
+	body, err := readLSPMessage(bufio.NewReader(&buf))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","method":"initialized"}`, string(body))
+
+	_, err = readLSPMessage(bufio.NewReader(strings.NewReader("Content-Type: x\r\n\r\n{}")))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing Content-Length")
+}
+
+func TestComputeLSPDiagnostics(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantLine    int
+		wantMessage string
+	}{
+		{
+			name:        "unknown property is located",
+			content:     "---\non: issues\ntools:\n  github:\n    toolset: [repos]\n---\n# Test\n",
+			wantLine:    4,
+			wantMessage: "Did you mean 'toolsets'?",
+		},
+		{
+			name:        "invalid YAML",
+			content:     "---\non: issues\nengine: [claude\n---\n# Test\n",
+			wantLine:    2,
+			wantMessage: "invalid YAML",
+		},
+		{
+			name:        "unclosed frontmatter",
+			content:     "---\non: issues\n",
+			wantLine:    0,
+			wantMessage: "not properly closed",
+		},
+		{
+			name:        "compiler validation of unsaved content",
+			content:     "---\non: issues\nengine: unknown-engine\n---\n# Test\n",
+			wantLine:    0,
+			wantMessage: "unknown-engine",
+		},
+	}
+
+	// The document does not exist on disk, diagnostics must use the editor content
+	path := filepath.Join(testutil.TempDir(t, "test-*"), ".github", "workflows", "test.md")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := computeLSPDiagnostics(path, tt.content, true)
+			require.NotEmpty(t, diagnostics)
+			assert.Equal(t, tt.wantLine, diagnostics[0].Range.Start.Line)
+			assert.Contains(t, diagnostics[0].Message, tt.wantMessage)
+			assert.Equal(t, lspSeverityError, diagnostics[0].Severity)
+		})
+	}
+
+	t.Run("valid workflow", func(t *testing.T) {
+		content := "---\non: issues\npermissions:\n  contents: read\n---\n# Test\n"
+		assert.Empty(t, computeLSPDiagnostics(path, content, true))
+	})
+
+	t.Run("shared workflow is not compiled", func(t *testing.T) {
+		content := "---\ntools:\n  github:\n---\nShared instructions\n"
+		assert.Empty(t, computeLSPDiagnostics(path, content, true))
+	})
+}
+
+func TestCompilerErrorToLSPDiagnostics(t *testing.T) {
+	lines := strings.Split("---\non: issues\nengine: claude\n---\n", "\n")
+	errorText := ".github/workflows/test.md:3:9: error: engine problem\nshared/other.md:1:1: warning: imported problem"
+
+	diagnostics := compilerErrorToLSPDiagnostics("/repo/.github/workflows/test.md", errorText, lines)
+	require.Len(t, diagnostics, 2)
+	assert.Equal(t, lspRange{Start: lspPosition{Line: 2, Character: 8}, End: lspPosition{Line: 2, Character: 14}}, diagnostics[0].Range)
+	assert.Equal(t, "engine problem", diagnostics[0].Message)
+	assert.Equal(t, 0, diagnostics[1].Range.Start.Line, "errors in other files are reported on the first line")
+	assert.Contains(t, diagnostics[1].Message, "shared/other.md")
+}
+
+func TestResolveLSPYAMLContext(t *testing.T) {
+	lines := strings.Split("---\non: issues\ntools:\n  github:\n    toolsets: [repos]\n  \nsafe-outputs:\n  create-issue:\n    labels:\n      - bug\nimports:\n  - path: shared/a.md\n---\n", "\n")
+
+	tests := []struct {
+		name   string
+		line   int
+		offset int
+		want   lspYAMLContext
+	}{
+		{name: "top-level key", line: 1, offset: 2, want: lspYAMLContext{Path: []string{}, Prefix: "on"}},
+		{name: "value", line: 1, offset: 7, want: lspYAMLContext{Path: []string{}, Key: "on", InValue: true, Prefix: "iss"}},
+		{name: "nested key", line: 4, offset: 8, want: lspYAMLContext{Path: []string{"tools", "github"}, Prefix: "tool"}},
+		{name: "blank line", line: 5, offset: 2, want: lspYAMLContext{Path: []string{"tools"}}},
+		{name: "list item", line: 9, offset: 11, want: lspYAMLContext{Path: []string{"safe-outputs", "create-issue", "labels", "[]"}, Prefix: "bug"}},
+		{name: "key in list item", line: 11, offset: 16, want: lspYAMLContext{Path: []string{"imports", "[]"}, Key: "path", InValue: true, Prefix: "shared"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resolveLSPYAMLContext(lines, tt.line, tt.offset))
+		})
+	}
+}
+
+func TestLSPCompletionsAndHover(t *testing.T) {
+	lines := strings.Split("---\non: issues\nengine: cl\ntools:\n  github:\n    tool\n---\n", "\n")
+
+	labels := func(items []lspCompletionItem) []string {
+		var result []string
+		for _, item := range items {
+			result = append(result, item.Label)
+		}
+		return result
+	}
+
+	t.Run("property keys", func(t *testing.T) {
+		items := lspCompletions(lines, 5, 8)
+		assert.Contains(t, labels(items), "toolsets")
+		for _, item := range items {
+			assert.True(t, strings.HasPrefix(item.Label, "tool"), item.Label)
+		}
+	})
+
+	t.Run("root keys", func(t *testing.T) {
+		items := lspCompletions([]string{"---", "perm", "---"}, 1, 4)
+		require.Len(t, items, 1)
+		assert.Equal(t, "permissions", items[0].Label)
+		assert.Equal(t, "permissions: ", items[0].InsertText)
+		assert.NotEmpty(t, items[0].Documentation)
+	})
+
+	t.Run("values", func(t *testing.T) {
+		assert.Contains(t, labels(lspCompletions(lines, 2, 10)), "claude")
+	})
+
+	t.Run("hover", func(t *testing.T) {
+		markdown, keyRange, ok := lspHoverMarkdown(lines, 2)
+		require.True(t, ok)
+		assert.Contains(t, markdown, "**engine**")
+		assert.Contains(t, markdown, "AI engine configuration")
+		assert.Equal(t, lspRange{Start: lspPosition{Line: 2}, End: lspPosition{Line: 2, Character: 6}}, keyRange)
+
+		_, _, ok = lspHoverMarkdown(lines, 5)
+		assert.False(t, ok, "incomplete keys have no hover")
+	})
+}
+
+func TestLSPDefinition(t *testing.T) {
+	workflowsDir := filepath.Join(testutil.TempDir(t, "test-*"), ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+	sharedPath := filepath.Join(workflowsDir, "shared", "tools.md")
+	require.NoError(t, os.WriteFile(sharedPath, []byte("---\ntools:\n  github:\n---\n"), 0644))
+
+	path := filepath.Join(workflowsDir, "test.md")
+	lines := strings.Split("---\non: issues\nimports:\n  - shared/tools.md\n  - shared/missing.md\n---\n{{#import shared/tools.md#Section}}\n@include shared/tools.md\nText\n", "\n")
+
+	for _, line := range []int{3, 6, 7} {
+		location, ok := lspDefinition(path, lines, line)
+		require.True(t, ok, "line %d should resolve", line)
+		assert.Equal(t, lspPathToURI(sharedPath), location.URI)
+	}
+	for _, line := range []int{1, 4, 8} {
+		_, ok := lspDefinition(path, lines, line)
+		assert.False(t, ok, "line %d should not resolve", line)
+	}
+}
+
+func TestLSPCodemodActions(t *testing.T) {
+	uri := "file:///repo/.github/workflows/test.md"
+	content := "---\non: issues\ntimeout_minutes: 10\n---\n# Test\n"
+
+	actions := lspCodemodActions(uri, content)
+	require.NotEmpty(t, actions)
+	action := actions[0]
+	assert.Equal(t, "quickfix", action.Kind)
+	assert.Contains(t, action.Title, "timeout-minutes")
+	edits := action.Edit.Changes[uri]
+	require.Len(t, edits, 1)
+	assert.Contains(t, edits[0].NewText, "timeout-minutes: 10")
+	assert.Equal(t, lspPosition{Line: 5}, edits[0].Range.End)
+
+	assert.Empty(t, lspCodemodActions(uri, "---\non: issues\ntimeout-minutes: 10\n---\n"))
+}
+
+func TestApplyLSPContentChange(t *testing.T) {
+	content := "---\non: issues\n---\n"
+	changed := applyLSPContentChange(content, &lspRange{Start: lspPosition{Line: 1, Character: 4}, End: lspPosition{Line: 1, Character: 10}}, "push")
+	assert.Equal(t, "---\non: push\n---\n", changed)
+	assert.Equal(t, "new", applyLSPContentChange(content, nil, "new"))
+}
+
+func TestLSPServerSession(t *testing.T) {
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+
+	server := newLSPServer(serverOut)
+	server.delay = 10 * time.Millisecond
+	done := make(chan error, 1)
+	go func() { done <- server.serve(serverIn) }()
+
+	responses := make(chan map[string]any, 16)
+	go func() {
+		reader := bufio.NewReader(clientIn)
+		for {
+			body, err := readLSPMessage(reader)
+			if err != nil {
+				close(responses)
+				return
+			}
+			var msg map[string]any
+			if json.Unmarshal(body, &msg) == nil {
+				responses <- msg
+			}
+		}
+	}()
+
+	send := func(msg map[string]any) {
+		require.NoError(t, writeLSPMessage(clientOut, msg))
+	}
+	receive := func(method string) map[string]any {
+		for {
+			select {
+			case msg := <-responses:
+				if msg["method"] == method || (method == "" && msg["id"] != nil) {
+					return msg
+				}
+			case <-time.After(30 * time.Second):
+				t.Fatalf("timed out waiting for %q", method)
+			}
+		}
+	}
+
+	send(map[string]any{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": map[string]any{}})
+	initResult := receive("")["result"].(map[string]any)
+	assert.Contains(t, initResult["capabilities"], "completionProvider")
+
+	uri := lspPathToURI(filepath.Join(testutil.TempDir(t, "test-*"), "test.md"))
+	send(map[string]any{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]any{
+		"textDocument": map[string]any{"uri": uri, "version": 1, "text": "---\non: issues\nengin: claude\n---\n# Test\n"},
+	}})
+	params := receive("textDocument/publishDiagnostics")["params"].(map[string]any)
+	diagnostics := params["diagnostics"].([]any)
+	require.Len(t, diagnostics, 1)
+	assert.Contains(t, diagnostics[0].(map[string]any)["message"], "engin")
+
+	send(map[string]any{"jsonrpc": "2.0", "id": 2, "method": "textDocument/completion", "params": map[string]any{
+		"textDocument": map[string]any{"uri": uri}, "position": map[string]any{"line": 2, "character": 5},
+	}})
+	items := receive("")["result"].([]any)
+	require.NotEmpty(t, items)
+	assert.Equal(t, "engine", items[0].(map[string]any)["label"])
+
+	send(map[string]any{"jsonrpc": "2.0", "id": 3, "method": "workspace/unknown"})
+	assert.Contains(t, receive("")["error"], "code")
+
+	send(map[string]any{"jsonrpc": "2.0", "id": 4, "method": "shutdown"})
+	receive("")
+	send(map[string]any{"jsonrpc": "2.0", "method": "exit"})
+	require.NoError(t, <-done)
+	clientOut.Close()
+	serverOut.Close()
+}
@@ -23,13 +23,16 @@ Available subcommands:
   • list       - List MCP servers defined in agentic workflows
   • list-tools - List available tools for a specific MCP server
   • inspect    - Inspect MCP servers and list available tools, resources, and roots
+  • test       - Run test cases against safe-inputs tools
+  • replay     - Serve the MCP responses recorded in a workflow run
   • add        - Add an MCP tool to an agentic workflow
 
 Examples:
   gh aw mcp list                              # List all workflows with MCP servers
   gh aw mcp inspect weekly-research           # Inspect MCP servers in workflow
   gh aw mcp add my-workflow tavily            # Add Tavily MCP server to workflow
-  gh aw mcp inspect weekly-research --server github --tool create_issue  # Inspect specific tool`,
+  gh aw mcp inspect weekly-research --server github --tool create_issue  # Inspect specific tool
+  gh aw mcp test issue-triage --junit results.xml  # Test safe-inputs tools with a JUnit report`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
@@ -40,6 +43,8 @@ Examples:
 	cmd.AddCommand(NewMCPListSubcommand())
 	cmd.AddCommand(NewMCPListToolsSubcommand())
 	cmd.AddCommand(NewMCPInspectSubcommand())
+	cmd.AddCommand(NewMCPTestSubcommand())
+	cmd.AddCommand(NewMCPReplaySubcommand())
 
 	return cmd
 }
@@ -163,6 +163,8 @@ func NewMCPInspectSubcommand() *cobra.Command {
 	var toolFilter string
 	var spawnInspector bool
 	var checkSecrets bool
+	var callTool string
+	var callArgs string
 
 	cmd := &cobra.Command{
 		Use:   "inspect [workflow]",
@@ -186,6 +188,7 @@ Examples:
   gh aw mcp inspect weekly-research -v # Verbose output with detailed connection info
   gh aw mcp inspect weekly-research --inspector  # Launch @modelcontextprotocol/inspector
   gh aw mcp inspect weekly-research --check-secrets  # Check GitHub Actions secrets
+  gh aw mcp inspect weekly-research --call search --args '{"query":"bug"}'  # Run a safe-inputs tool locally
 
 The command will:
 - Parse the workflow file to extract MCP server configurations
@@ -193,7 +196,11 @@ The command will:
 - Automatically start and inspect safe-inputs server if present
 - Query available tools, resources, and roots
 - Validate required secrets are available  
-- Display results in formatted tables with error details`,
+- Display results in formatted tables with error details
+
+With --call, the named safe-inputs tool is generated as in the compiled workflow and run
+locally with the JSON --args instead, printing its result. Environment variables declared by
+the tool (usually secrets) are read from the local environment.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var workflowFile string
@@ -219,6 +226,17 @@ The command will:
 				return fmt.Errorf("--tool flag requires --server flag to be specified")
 			}
 
+			// Run a safe-inputs tool locally
+			if callTool != "" {
+				if workflowFile == "" {
+					return fmt.Errorf("--call requires a workflow")
+				}
+				return CallSafeInputTool(workflowFile, callTool, callArgs, verbose)
+			}
+			if callArgs != "" {
+				return fmt.Errorf("--args requires --call")
+			}
+
 			// Handle spawn inspector flag
 			if spawnInspector {
 				return spawnMCPInspector(workflowFile, serverFilter, verbose)
@@ -232,6 +250,8 @@ The command will:
 	cmd.Flags().StringVar(&toolFilter, "tool", "", "Show detailed information about a specific tool (requires --server)")
 	cmd.Flags().BoolVar(&spawnInspector, "inspector", false, "Launch the official @modelcontextprotocol/inspector tool")
 	cmd.Flags().BoolVar(&checkSecrets, "check-secrets", false, "Check GitHub Actions repository secrets for missing secrets")
+	cmd.Flags().StringVar(&callTool, "call", "", "Run the specified safe-inputs tool locally and print its result")
+	cmd.Flags().StringVar(&callArgs, "args", "", "JSON object of tool arguments for --call")
 
 	// Register completions for mcp inspect command
 	cmd.ValidArgsFunction = CompleteWorkflowNames
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+// safeInputCallRunner loads a CommonJS tool handler, calls its execute function with the JSON
+// inputs read from stdin and writes the JSON result to stdout
+const safeInputCallRunner = `
+const { execute } = require(process.argv[1]);
+let input = "";
+process.stdin.setEncoding("utf8");
+process.stdin.on("data", chunk => (input += chunk));
+process.stdin.on("end", async () => {
+  try {
+    const result = await execute(input.trim() ? JSON.parse(input) : {});
+    process.stdout.write(JSON.stringify(result === undefined ? null : result));
+  } catch (error) {
+    console.error(error instanceof Error ? error.stack || error.message : String(error));
+    process.exit(1);
+  }
+});
+`
+
+// CallSafeInputTool runs a safe-input tool of a workflow locally with JSON arguments and prints its
+// result. The tool handler is generated exactly as in the compiled workflow and executed directly,
+// without starting the safe-inputs MCP server. Environment variables declared by the tool (usually
+// secrets) are read from the local environment.
+func CallSafeInputTool(workflowFile, toolName, argsJSON string, verbose bool) error {
+	mcpInspectLog.Printf("Calling safe-input tool: workflow=%s, tool=%s", workflowFile, toolName)
+
+	workflowPath, err := ResolveWorkflowPath(workflowFile)
+	if err != nil {
+		return err
+	}
+	if workflowPath, err = filepath.Abs(workflowPath); err != nil {
+		return fmt.Errorf("failed to resolve workflow path: %w", err)
+	}
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose))
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+	if workflowData.SafeInputs == nil || len(workflowData.SafeInputs.Tools) == 0 {
+		return fmt.Errorf("workflow %s has no safe-inputs tools", workflowFile)
+	}
+	tool, ok := workflowData.SafeInputs.Tools[toolName]
+	if !ok {
+		return fmt.Errorf("safe-inputs tool '%s' not found; available tools: %s", toolName, strings.Join(sortedKeys(workflowData.SafeInputs.Tools), ", "))
+	}
+
+	inputs, err := safeInputCallArguments(tool, argsJSON)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gh-aw-safe-input-call-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := writeSafeInputsFiles(tmpDir, &workflow.SafeInputsConfig{Tools: map[string]*workflow.SafeInputToolConfig{toolName: tool}}, verbose); err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(tool.Env) {
+		if _, set := os.LookupEnv(name); !set {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Environment variable %s used by tool %s is not set", name, toolName)))
+		}
+	}
+
+	output, err := runSafeInputTool(tmpDir, tool, inputs, verbose)
+	if err != nil {
+		return err
+	}
+	fmt.Println(output)
+	return nil
+}
+
+// safeInputCallArguments parses the JSON arguments of a tool call, applies input defaults and
+// checks required inputs, as the MCP server does
+func safeInputCallArguments(tool *workflow.SafeInputToolConfig, argsJSON string) (map[string]any, error) {
+	inputs := make(map[string]any)
+	if strings.TrimSpace(argsJSON) != "" {
+		if err := json.Unmarshal([]byte(argsJSON), &inputs); err != nil {
+			return nil, fmt.Errorf("--args must be a JSON object: %w", err)
+		}
+	}
+
+	var missing []string
+	for _, name := range sortedKeys(tool.Inputs) {
+		param := tool.Inputs[name]
+		if _, ok := inputs[name]; ok {
+			continue
+		}
+		if param.Default != nil {
+			inputs[name] = param.Default
+		} else if param.Required {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required inputs for tool %s: %s", tool.Name, strings.Join(missing, ", "))
+	}
+	for name := range inputs {
+		if _, ok := tool.Inputs[name]; !ok {
+			return nil, fmt.Errorf("tool %s has no input '%s'", tool.Name, name)
+		}
+	}
+	return inputs, nil
+}
+
+// runSafeInputTool executes the handler written in dir with the given inputs and returns its output
+func runSafeInputTool(dir string, tool *workflow.SafeInputToolConfig, inputs map[string]any, verbose bool) (string, error) {
+	inputJSON, err := json.Marshal(inputs)
+	if err != nil {
+		return "", err
+	}
+
+	timeout := time.Duration(tool.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	workspace, err := findGitRoot()
+	if err != nil {
+		workspace, _ = os.Getwd()
+	}
+	env := append(os.Environ(), "GITHUB_WORKSPACE="+workspace)
+
+	var cmd *exec.Cmd
+	var outputFile string
+	switch {
+	case tool.Run != "":
+		// Shell tools receive inputs as INPUT_* variables and write outputs to GITHUB_OUTPUT
+		outputFile = filepath.Join(dir, "outputs.txt")
+		for name, value := range inputs {
+			env = append(env, fmt.Sprintf("INPUT_%s=%v", strings.ReplaceAll(strings.ToUpper(name), "-", "_"), value))
+		}
+		env = append(env, "GITHUB_OUTPUT="+outputFile)
+		cmd = exec.CommandContext(ctx, "bash", filepath.Join(dir, tool.Name+".sh"))
+	case tool.Py != "":
+		cmd = exec.CommandContext(ctx, "python3", filepath.Join(dir, tool.Name+".py"))
+	case tool.Go != "":
+		cmd = exec.CommandContext(ctx, "go", "run", filepath.Join(dir, tool.Name+".go"))
+	case tool.TS != "":
+		// TypeScript tool modules read their inputs from stdin themselves
+		cmd = exec.CommandContext(ctx, "node", "--experimental-strip-types", filepath.Join(dir, tool.Name+".mts"))
+	default:
+		cmd = exec.CommandContext(ctx, "node", "-e", safeInputCallRunner, filepath.Join(dir, tool.Name+".cjs"))
+	}
+	cmd.Dir = workspace
+	cmd.Env = env
+	cmd.Stdin = bytes.NewReader(inputJSON)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Running tool %s with inputs %s", tool.Name, inputJSON)))
+	}
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("tool %s timed out after %s", tool.Name, timeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("tool %s failed: %w\n%s", tool.Name, err, strings.TrimSpace(stderr.String()))
+	}
+	if verbose && stderr.Len() > 0 {
+		fmt.Fprintln(os.Stderr, strings.TrimSpace(stderr.String()))
+	}
+
+	output := strings.TrimSpace(stdout.String())
+	if outputFile != "" {
+		outputs, err := readSafeInputOutputs(outputFile)
+		if err != nil {
+			return "", err
+		}
+		if len(outputs) > 0 {
+			data, _ := json.MarshalIndent(map[string]any{"stdout": output, "outputs": outputs}, "", "  ")
+			return string(data), nil
+		}
+	}
+
+	// Pretty-print JSON results
+	var result any
+	if json.Unmarshal([]byte(output), &result) == nil {
+		if data, err := json.MarshalIndent(result, "", "  "); err == nil {
+			return string(data), nil
+		}
+	}
+	return output, nil
+}
+
+// readSafeInputOutputs reads the key=value outputs written by a shell tool
+func readSafeInputOutputs(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	outputs := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), "="); ok {
+			outputs[key] = value
+		}
+	}
+	return outputs, scanner.Err()
+}
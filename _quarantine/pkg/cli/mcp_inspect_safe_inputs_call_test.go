@@ -0,0 +1,98 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeInputCallArguments(t *testing.T) {
+	tool := &workflow.SafeInputToolConfig{
+		Name: "search",
+		Inputs: map[string]*workflow.SafeInputParam{
+			"query": {Type: "string", Required: true},
+			"limit": {Type: "number", Default: float64(10)},
+			"label": {Type: "string"},
+		},
+	}
+
+	inputs, err := safeInputCallArguments(tool, `{"query":"bug"}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"query": "bug", "limit": float64(10)}, inputs)
+
+	inputs, err = safeInputCallArguments(tool, `{"query":"bug","limit":3,"label":"x"}`)
+	require.NoError(t, err)
+	assert.Equal(t, float64(3), inputs["limit"])
+
+	_, err = safeInputCallArguments(tool, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required inputs for tool search: query")
+
+	_, err = safeInputCallArguments(tool, `{"query":"bug","page":2}`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tool search has no input 'page'")
+
+	_, err = safeInputCallArguments(tool, `["bug"]`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--args must be a JSON object")
+}
+
+func TestCallSafeInputToolTypeScript(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node not available")
+	}
+	if err := exec.Command("node", "--experimental-strip-types", "-e", "").Run(); err != nil {
+		t.Skip("node does not support type stripping")
+	}
+
+	tmpDir := t.TempDir()
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	workflowContent := `---
+on: push
+engine: copilot
+safe-inputs:
+  greet:
+    description: Greets someone
+    inputs:
+      name:
+        type: string
+        required: true
+    ts: |
+      export function execute(inputs: { name: string }): { greeting: string } {
+        return { greeting: "hello " + inputs.name };
+      }
+---
+# Test Workflow
+`
+	workflowPath := filepath.Join(workflowsDir, "test.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflowContent), 0644))
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(tmpDir))
+
+	tmp := filepath.Join(tmpDir, "out")
+	require.NoError(t, os.MkdirAll(tmp, 0755))
+	compiler := workflow.NewCompiler()
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	require.NoError(t, err)
+	tool := workflowData.SafeInputs.Tools["greet"]
+	require.NotNil(t, tool)
+	require.NoError(t, writeSafeInputsFiles(tmp, workflowData.SafeInputs, false))
+
+	output, err := runSafeInputTool(tmp, tool, map[string]any{"name": "octocat"}, false)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"greeting":"hello octocat"}`, output)
+
+	err = CallSafeInputTool("test", "missing", "", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "safe-inputs tool 'missing' not found; available tools: greet")
+}
@@ -87,6 +87,16 @@ func writeSafeInputsFiles(dir string, safeInputsConfig *workflow.SafeInputsConfi
 		} else if toolConfig.Py != "" {
 			content = workflow.GenerateSafeInputPythonToolScriptForInspector(toolConfig)
 			extension = ".py"
+		} else if toolConfig.Go != "" {
+			content = workflow.GenerateSafeInputGoToolScriptForInspector(toolConfig)
+			extension = ".go"
+		} else if toolConfig.TS != "" {
+			script, err := workflow.GenerateSafeInputTypeScriptToolScriptForInspector(toolConfig)
+			if err != nil {
+				return err
+			}
+			content = script
+			extension = ".mts"
 		} else {
 			continue
 		}
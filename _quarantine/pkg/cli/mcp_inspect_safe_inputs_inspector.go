@@ -92,7 +92,7 @@ func spawnSafeInputsInspector(workflowFile string, verbose bool) error {
 	}
 
 	// Start the HTTP server
-	serverCmd, err := startSafeInputsHTTPServer(tmpDir, port, verbose)
+	serverCmd, err := startSafeInputsHTTPServer(tmpDir, port, nil, verbose)
 	if err != nil {
 		return fmt.Errorf("failed to start safe-inputs HTTP server: %w", err)
 	}
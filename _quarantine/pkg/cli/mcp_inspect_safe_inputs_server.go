@@ -65,8 +65,9 @@ func waitForServerReady(port int, timeout time.Duration, verbose bool) bool {
 	return false
 }
 
-// startSafeInputsHTTPServer starts the safe-inputs HTTP MCP server
-func startSafeInputsHTTPServer(dir string, port int, verbose bool) (*exec.Cmd, error) {
+// startSafeInputsHTTPServer starts the safe-inputs HTTP MCP server. The extra environment
+// variables (KEY=value) are visible to the server and the tools it runs.
+func startSafeInputsHTTPServer(dir string, port int, env []string, verbose bool) (*exec.Cmd, error) {
 	mcpInspectLog.Printf("Starting safe-inputs HTTP server on port %d", port)
 
 	mcpServerPath := filepath.Join(dir, "mcp-server.cjs")
@@ -76,6 +77,7 @@ func startSafeInputsHTTPServer(dir string, port int, verbose bool) (*exec.Cmd, e
 	cmd.Env = append(os.Environ(),
 		fmt.Sprintf("GH_AW_SAFE_INPUTS_PORT=%d", port),
 	)
+	cmd.Env = append(cmd.Env, env...)
 
 	// Capture output for debugging
 	if verbose {
@@ -98,6 +100,12 @@ func startSafeInputsHTTPServer(dir string, port int, verbose bool) (*exec.Cmd, e
 
 // startSafeInputsServer starts the safe-inputs HTTP server and returns the MCP config
 func startSafeInputsServer(safeInputsConfig *workflow.SafeInputsConfig, verbose bool) (*parser.MCPServerConfig, *exec.Cmd, string, error) {
+	return startSafeInputsServerWithEnv(safeInputsConfig, nil, verbose)
+}
+
+// startSafeInputsServerWithEnv starts the safe-inputs HTTP server with extra environment
+// variables (KEY=value) and returns the MCP config
+func startSafeInputsServerWithEnv(safeInputsConfig *workflow.SafeInputsConfig, env []string, verbose bool) (*parser.MCPServerConfig, *exec.Cmd, string, error) {
 	mcpInspectLog.Printf("Starting safe-inputs server with %d tools", len(safeInputsConfig.Tools))
 
 	// Check if node is available
@@ -152,7 +160,7 @@ func startSafeInputsServer(safeInputsConfig *workflow.SafeInputsConfig, verbose
 	}
 
 	// Start the HTTP server
-	serverCmd, err := startSafeInputsHTTPServer(tmpDir, port, verbose)
+	serverCmd, err := startSafeInputsHTTPServer(tmpDir, port, env, verbose)
 	if err != nil {
 		// Clean up temporary directory on error
 		if rmErr := os.RemoveAll(tmpDir); rmErr != nil && verbose {
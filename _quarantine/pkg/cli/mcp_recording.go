@@ -0,0 +1,130 @@
+// This file provides command-line interface functionality for gh-aw.
+// This file (mcp_recording.go) contains functions for loading MCP gateway recordings,
+// the full request/response payloads captured when sandbox.mcp.record is enabled.
+//
+// The recording proxy in front of the MCP gateway (actions/setup/js/mcp_recording_proxy.cjs) writes
+// one JSON Lines file per MCP server to /tmp/gh-aw/mcp-logs/recordings,
+// which is redacted by the secret redaction step and uploaded with the mcp-logs artifact.
+
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mcpRecordingLog = logger.New("cli:mcp_recording")
+
+// MCPRecordedCall is a recorded MCP request and its response (one line of a recording file)
+type MCPRecordedCall struct {
+	Timestamp  string          `json:"timestamp"`
+	ServerName string          `json:"server_name"`
+	Method     string          `json:"method"`
+	ToolName   string          `json:"tool_name,omitempty"`
+	Request    json.RawMessage `json:"request,omitempty"`  // JSON-RPC params
+	Response   json.RawMessage `json:"response,omitempty"` // JSON-RPC result
+	Error      string          `json:"error,omitempty"`    // JSON-RPC error message
+}
+
+// MCPRecording holds the recorded calls of a run grouped by server, in recorded order
+type MCPRecording struct {
+	Dir     string
+	Servers map[string][]MCPRecordedCall
+}
+
+// findMCPRecordingDir returns the recordings directory of a downloaded run, or the directory
+// itself when it contains recording files
+func findMCPRecordingDir(runDir string) (string, error) {
+	candidates := []string{
+		filepath.Join(runDir, "mcp-logs", "recordings"),
+		filepath.Join(runDir, "recordings"),
+		runDir,
+	}
+	for _, dir := range candidates {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+		if len(files) > 0 {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("no MCP recordings found in %s; enable recording with 'sandbox.mcp.record: true' and re-run the workflow", runDir)
+}
+
+// loadMCPRecording loads the recording files of a downloaded run
+func loadMCPRecording(runDir string) (*MCPRecording, error) {
+	dir, err := findMCPRecordingDir(runDir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recordings: %w", err)
+	}
+	sort.Strings(files)
+
+	recording := &MCPRecording{Dir: dir, Servers: make(map[string][]MCPRecordedCall)}
+	for _, file := range files {
+		calls, err := readMCPRecordingFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, call := range calls {
+			recording.Servers[call.ServerName] = append(recording.Servers[call.ServerName], call)
+		}
+	}
+	if len(recording.Servers) == 0 {
+		return nil, fmt.Errorf("the recordings in %s are empty", dir)
+	}
+	mcpRecordingLog.Printf("Loaded recordings for %d server(s) from %s", len(recording.Servers), dir)
+	return recording, nil
+}
+
+// readMCPRecordingFile reads a recording file. Calls without a server name are attributed to
+// the server named after the file.
+func readMCPRecordingFile(path string) ([]MCPRecordedCall, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+
+	defaultServer := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	var calls []MCPRecordedCall
+	scanner := bufio.NewScanner(file)
+	// Recorded payloads can be much larger than the default 64KB line limit
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var call MCPRecordedCall
+		if err := json.Unmarshal([]byte(line), &call); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid recording entry: %w", filepath.Base(path), lineNum, err)
+		}
+		if call.ServerName == "" {
+			call.ServerName = defaultServer
+		}
+		if call.Method == "tools/call" && call.ToolName == "" {
+			var params struct {
+				Name string `json:"name"`
+			}
+			if json.Unmarshal(call.Request, &params) == nil {
+				call.ToolName = params.Name
+			}
+		}
+		calls = append(calls, call)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", filepath.Base(path), err)
+	}
+	return calls, nil
+}
@@ -0,0 +1,384 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+)
+
+var mcpReplayLog = logger.New("cli:mcp_replay")
+
+// defaultMCPReplayPort is the default port of the replay server
+const defaultMCPReplayPort = 8090
+
+// NewMCPReplaySubcommand creates the mcp replay subcommand
+func NewMCPReplaySubcommand() *cobra.Command {
+	var serverName string
+	var port int
+	var stdio bool
+	var strict bool
+
+	cmd := &cobra.Command{
+		Use:   "replay <run-id>",
+		Short: "Serve the MCP tool results recorded in a workflow run as fake MCP servers",
+		Long: `Serve the MCP responses recorded during a workflow run so the agent run can be
+re-executed locally against identical tool results.
+
+Recording is enabled in the workflow with:
+
+  sandbox:
+    mcp:
+      container: ghcr.io/github/gh-aw-mcpg
+      record: true
+
+The MCP gateway then records every MCP request and response (redacted by the secret
+redaction step) and uploads them with the mcp-logs artifact. This command downloads the
+artifacts of the run and starts one fake MCP server per recorded server, mirroring the gateway
+routes at http://localhost:<port>/mcp/<server>. With --stdio, the server selected with --server
+is served over stdin/stdout instead.
+
+Tool calls are answered with the recorded response of the first unused call with the same tool
+and arguments. When the arguments differ from the recording, the next unused call to the tool
+is replayed, unless --strict is set. Calls beyond the recording repeat the last matching
+response.
+
+The run can be given as a run ID, a run URL or a local directory containing the downloaded
+artifacts or recording files.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` mcp replay 1234567890                      # Serve all recorded servers over HTTP
+  ` + string(constants.CLIExtensionPrefix) + ` mcp replay 1234567890 --port 9000          # Use another port
+  ` + string(constants.CLIExtensionPrefix) + ` mcp replay 1234567890 --server github --stdio  # Serve one server over stdio
+  ` + string(constants.CLIExtensionPrefix) + ` mcp replay ./run-1234567890 --strict       # Replay from a local directory, exact arguments only`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputDir, _ := cmd.Flags().GetString("output")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			if cmd.Parent() != nil {
+				if parentVerbose, _ := cmd.Parent().PersistentFlags().GetBool("verbose"); parentVerbose {
+					verbose = true
+				}
+			}
+			if stdio && serverName == "" {
+				return fmt.Errorf("--stdio requires --server")
+			}
+			return RunMCPReplay(cmd.Context(), MCPReplayConfig{
+				Run:        args[0],
+				OutputDir:  outputDir,
+				ServerName: serverName,
+				Port:       port,
+				Stdio:      stdio,
+				Strict:     strict,
+				Verbose:    verbose,
+			})
+		},
+	}
+
+	addOutputFlag(cmd, defaultLogsOutputDir)
+	cmd.Flags().StringVar(&serverName, "server", "", "Serve only the specified MCP server")
+	cmd.Flags().IntVarP(&port, "port", "p", defaultMCPReplayPort, "Port of the HTTP replay server")
+	cmd.Flags().BoolVar(&stdio, "stdio", false, "Serve the server selected with --server over stdio")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Only replay calls whose arguments match the recording exactly")
+	RegisterDirFlagCompletion(cmd, "output")
+
+	return cmd
+}
+
+// MCPReplayConfig holds the options of the mcp replay command
+type MCPReplayConfig struct {
+	Run        string // Run ID, run URL or local directory
+	OutputDir  string // Directory runs are downloaded to
+	ServerName string
+	Port       int
+	Stdio      bool
+	Strict     bool
+	Verbose    bool
+}
+
+// RunMCPReplay loads the recordings of a run and serves them
+func RunMCPReplay(ctx context.Context, config MCPReplayConfig) error {
+	mcpReplayLog.Printf("Replaying MCP recordings: run=%s, server=%s, stdio=%t", config.Run, config.ServerName, config.Stdio)
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	runDir, err := resolveMCPReplayRunDir(config.Run, config.OutputDir, config.Verbose)
+	if err != nil {
+		return err
+	}
+	recording, err := loadMCPRecording(runDir)
+	if err != nil {
+		return err
+	}
+
+	names := sortedKeys(recording.Servers)
+	if config.ServerName != "" {
+		if _, ok := recording.Servers[config.ServerName]; !ok {
+			return fmt.Errorf("no recording for MCP server '%s'; recorded servers: %s", config.ServerName, strings.Join(names, ", "))
+		}
+		names = []string{config.ServerName}
+	}
+
+	servers := make(map[string]*mcp.Server, len(names))
+	for _, name := range names {
+		replay := newMCPReplayServer(name, recording.Servers[name], config.Strict)
+		servers[name] = replay.server()
+		if !config.Stdio {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("%s: %d recorded tool call(s), tools: %s", name, replay.callCount(), strings.Join(sortedToolNames(replay.tools), ", "))))
+		}
+	}
+
+	if config.Stdio {
+		return servers[config.ServerName].Run(ctx, &mcp.StdioTransport{})
+	}
+	return serveMCPReplay(ctx, servers, config.Port)
+}
+
+// resolveMCPReplayRunDir returns the directory holding the recordings of a run, downloading the
+// run artifacts when a run ID or URL is given
+func resolveMCPReplayRunDir(run, outputDir string, verbose bool) (string, error) {
+	if info, err := os.Stat(run); err == nil && info.IsDir() {
+		return run, nil
+	}
+	runID, err := extractRunID(run)
+	if err != nil {
+		return "", err
+	}
+	runDir := filepath.Join(outputDir, fmt.Sprintf("run-%d", runID))
+	if err := downloadRunArtifacts(runID, runDir, verbose); err != nil {
+		return "", fmt.Errorf("failed to download artifacts for run %d: %w", runID, err)
+	}
+	return runDir, nil
+}
+
+// serveMCPReplay serves replay servers over HTTP at /mcp/<server> until the context is done
+func serveMCPReplay(ctx context.Context, servers map[string]*mcp.Server, port int) error {
+	mux := http.NewServeMux()
+	for _, name := range sortedKeys(servers) {
+		server := servers[name]
+		mux.Handle("/mcp/"+name, mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+			return server
+		}, &mcp.StreamableHTTPOptions{Logger: logger.NewSlogLoggerWithHandler(mcpReplayLog)}))
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Replaying %s at http://localhost:%d/mcp/%s", name, port, name)))
+	}
+
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: MCPServerHTTPTimeout,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Press Ctrl+C to stop"))
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("replay server failed: %w", err)
+	}
+	return nil
+}
+
+// mcpReplayCall is a recorded tool call available for replay
+type mcpReplayCall struct {
+	arguments string // Canonical JSON of the arguments
+	result    json.RawMessage
+	err       string
+	used      bool
+}
+
+// mcpReplayServer answers tool calls with recorded responses
+type mcpReplayServer struct {
+	name   string
+	strict bool
+	tools  []*mcp.Tool
+
+	mu    sync.Mutex
+	calls map[string][]*mcpReplayCall // By tool name, in recorded order
+}
+
+// newMCPReplayServer builds a replay server from the recorded calls of a server. The tools are
+// taken from the recorded tools/list response when present, and otherwise inferred from the
+// recorded tool calls.
+func newMCPReplayServer(name string, recorded []MCPRecordedCall, strict bool) *mcpReplayServer {
+	replay := &mcpReplayServer{name: name, strict: strict, calls: make(map[string][]*mcpReplayCall)}
+
+	known := make(map[string]bool)
+	for _, call := range recorded {
+		switch call.Method {
+		case "tools/list":
+			var result struct {
+				Tools []*mcp.Tool `json:"tools"`
+			}
+			if call.Error != "" || json.Unmarshal(call.Response, &result) != nil {
+				continue
+			}
+			for _, tool := range result.Tools {
+				if tool != nil && tool.Name != "" && !known[tool.Name] {
+					known[tool.Name] = true
+					replay.tools = append(replay.tools, tool)
+				}
+			}
+		case "tools/call":
+			if call.ToolName == "" {
+				continue
+			}
+			var params struct {
+				Arguments json.RawMessage `json:"arguments"`
+			}
+			_ = json.Unmarshal(call.Request, &params)
+			replay.calls[call.ToolName] = append(replay.calls[call.ToolName], &mcpReplayCall{
+				arguments: canonicalJSON(params.Arguments),
+				result:    call.Response,
+				err:       call.Error,
+			})
+		}
+	}
+	for _, toolName := range sortedKeys(replay.calls) {
+		if !known[toolName] {
+			known[toolName] = true
+			replay.tools = append(replay.tools, &mcp.Tool{Name: toolName, Description: "Replayed tool"})
+		}
+	}
+	return replay
+}
+
+// callCount returns the number of recorded tool calls
+func (r *mcpReplayServer) callCount() int {
+	count := 0
+	for _, calls := range r.calls {
+		count += len(calls)
+	}
+	return count
+}
+
+// server returns an MCP server exposing the recorded tools
+func (r *mcpReplayServer) server() *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: r.name, Version: "replay"}, nil)
+	for _, tool := range r.tools {
+		server.AddTool(replayableTool(tool), r.handle)
+	}
+	return server
+}
+
+// replayableTool returns a copy of a recorded tool whose schemas the MCP server accepts
+func replayableTool(tool *mcp.Tool) *mcp.Tool {
+	copied := *tool
+	if !isObjectSchema(copied.InputSchema) {
+		copied.InputSchema = map[string]any{"type": "object"}
+	}
+	if copied.OutputSchema != nil && !isObjectSchema(copied.OutputSchema) {
+		copied.OutputSchema = nil
+	}
+	return &copied
+}
+
+// isObjectSchema reports whether a JSON schema has type object
+func isObjectSchema(schema any) bool {
+	if schema == nil {
+		return false
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return false
+	}
+	var m map[string]any
+	return json.Unmarshal(data, &m) == nil && m["type"] == "object"
+}
+
+// handle answers a tool call with a recorded response
+func (r *mcpReplayServer) handle(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := req.Params.Name
+	arguments := canonicalJSON(req.Params.Arguments)
+
+	call := r.next(name, arguments)
+	if call == nil {
+		mcpReplayLog.Printf("No recorded response for %s.%s(%s)", r.name, name, arguments)
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("no recorded response for tool %s with arguments %s", name, arguments)}},
+		}, nil
+	}
+	if call.err != "" {
+		return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: call.err}}}, nil
+	}
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(call.result, &result); err != nil {
+		return nil, fmt.Errorf("invalid recorded response for tool %s: %w", name, err)
+	}
+	return &result, nil
+}
+
+// next returns the recorded call to replay for a tool call: the first unused call with the same
+// arguments, else (unless strict) the first unused call to the tool, else the last call with the
+// same arguments
+func (r *mcpReplayServer) next(name, arguments string) *mcpReplayCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls := r.calls[name]
+	for _, call := range calls {
+		if !call.used && call.arguments == arguments {
+			call.used = true
+			return call
+		}
+	}
+	if !r.strict {
+		for _, call := range calls {
+			if !call.used {
+				call.used = true
+				return call
+			}
+		}
+	}
+	for i := len(calls) - 1; i >= 0; i-- {
+		if calls[i].arguments == arguments {
+			return calls[i]
+		}
+	}
+	return nil
+}
+
+// canonicalJSON re-encodes a JSON value with sorted object keys so equal values compare equal
+func canonicalJSON(data json.RawMessage) string {
+	if len(data) == 0 {
+		return "{}"
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return string(data)
+	}
+	if value == nil {
+		return "{}"
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return string(data)
+	}
+	return string(canonical)
+}
+
+// sortedToolNames returns the names of tools in order
+func sortedToolNames(tools []*mcp.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,149 @@
+//go:build !integration
+
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const githubRecording = `{"timestamp":"2026-01-01T00:00:00Z","server_name":"github","method":"tools/list","response":{"tools":[{"name":"get_issue","description":"Get an issue","inputSchema":{"type":"object","properties":{"number":{"type":"integer"}}}}]}}
+{"timestamp":"2026-01-01T00:00:01Z","server_name":"github","method":"tools/call","tool_name":"get_issue","request":{"name":"get_issue","arguments":{"number":1}},"response":{"content":[{"type":"text","text":"issue 1"}]}}
+{"timestamp":"2026-01-01T00:00:02Z","server_name":"github","method":"tools/call","tool_name":"get_issue","request":{"name":"get_issue","arguments":{"number":2}},"response":{"content":[{"type":"text","text":"issue 2"}]}}
+
+{"timestamp":"2026-01-01T00:00:03Z","server_name":"github","method":"tools/call","request":{"name":"search","arguments":{"q":"bug","page":1}},"error":"rate limited"}
+`
+
+// writeRecording writes recording files to <dir>/mcp-logs/recordings
+func writeRecording(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	recordingsDir := filepath.Join(dir, "mcp-logs", "recordings")
+	require.NoError(t, os.MkdirAll(recordingsDir, 0755))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(recordingsDir, name), []byte(content), 0644))
+	}
+}
+
+func TestLoadMCPRecording(t *testing.T) {
+	dir := testutil.TempDir(t, "recording-*")
+	writeRecording(t, dir, map[string]string{
+		"github.jsonl": githubRecording,
+		"tavily.jsonl": `{"method":"tools/call","request":{"name":"search","arguments":{}},"response":{"content":[]}}` + "\n",
+	})
+
+	recording, err := loadMCPRecording(dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "mcp-logs", "recordings"), recording.Dir)
+	assert.Equal(t, []string{"github", "tavily"}, sortedKeys(recording.Servers))
+	require.Len(t, recording.Servers["github"], 4)
+	assert.Equal(t, "search", recording.Servers["github"][3].ToolName, "tool name is taken from the request")
+	assert.Equal(t, "rate limited", recording.Servers["github"][3].Error)
+	require.Len(t, recording.Servers["tavily"], 1)
+	assert.Equal(t, "tavily", recording.Servers["tavily"][0].ServerName, "server defaults to the file name")
+
+	_, err = loadMCPRecording(testutil.TempDir(t, "empty-*"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no MCP recordings found")
+
+	bad := testutil.TempDir(t, "bad-*")
+	writeRecording(t, bad, map[string]string{"github.jsonl": "{\"method\":\"tools/call\"}\nnot json\n"})
+	_, err = loadMCPRecording(bad)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "github.jsonl:2: invalid recording entry")
+}
+
+// connectReplay connects an MCP client to a replay server in memory
+func connectReplay(t *testing.T, replay *mcpReplayServer) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := replay.server().Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { serverSession.Close() })
+	client := mcp.NewClient(&mcp.Implementation{Name: "agent", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+// callReplay calls a tool and returns its text and error flag
+func callReplay(t *testing.T, session *mcp.ClientSession, name string, args map[string]any) (string, bool) {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	require.NoError(t, err)
+	return toolResultText(result), result.IsError
+}
+
+func TestMCPReplayServer(t *testing.T) {
+	dir := testutil.TempDir(t, "recording-*")
+	writeRecording(t, dir, map[string]string{"github.jsonl": githubRecording})
+	recording, err := loadMCPRecording(dir)
+	require.NoError(t, err)
+
+	replay := newMCPReplayServer("github", recording.Servers["github"], false)
+	assert.Equal(t, 3, replay.callCount())
+	assert.Equal(t, []string{"get_issue", "search"}, sortedToolNames(replay.tools))
+
+	session := connectReplay(t, replay)
+	tools, err := session.ListTools(context.Background(), &mcp.ListToolsParams{})
+	require.NoError(t, err)
+	require.Len(t, tools.Tools, 2)
+	assert.Equal(t, "Get an issue", tools.Tools[0].Description, "recorded tool definitions are served")
+
+	// Exact arguments are matched regardless of recorded order
+	text, isError := callReplay(t, session, "get_issue", map[string]any{"number": 2})
+	assert.False(t, isError)
+	assert.Equal(t, "issue 2", text)
+	// Different arguments replay the next unused call
+	text, _ = callReplay(t, session, "get_issue", map[string]any{"number": 3})
+	assert.Equal(t, "issue 1", text)
+	// Beyond the recording the last matching response is repeated
+	text, _ = callReplay(t, session, "get_issue", map[string]any{"number": 2})
+	assert.Equal(t, "issue 2", text)
+	text, isError = callReplay(t, session, "get_issue", map[string]any{"number": 4})
+	assert.True(t, isError)
+	assert.Contains(t, text, `no recorded response for tool get_issue with arguments {"number":4}`)
+
+	// Recorded errors are replayed as tool errors; argument key order does not matter
+	text, isError = callReplay(t, session, "search", map[string]any{"page": 1, "q": "bug"})
+	assert.True(t, isError)
+	assert.Equal(t, "rate limited", text)
+}
+
+func TestMCPReplayServerStrict(t *testing.T) {
+	dir := testutil.TempDir(t, "recording-*")
+	writeRecording(t, dir, map[string]string{"github.jsonl": githubRecording})
+	recording, err := loadMCPRecording(dir)
+	require.NoError(t, err)
+
+	session := connectReplay(t, newMCPReplayServer("github", recording.Servers["github"], true))
+	text, isError := callReplay(t, session, "get_issue", map[string]any{"number": 3})
+	assert.True(t, isError)
+	assert.True(t, strings.HasPrefix(text, "no recorded response"))
+	text, _ = callReplay(t, session, "get_issue", map[string]any{"number": 1})
+	assert.Equal(t, "issue 1", text)
+}
+
+func TestRunMCPReplayUnknownServer(t *testing.T) {
+	dir := testutil.TempDir(t, "recording-*")
+	writeRecording(t, dir, map[string]string{"github.jsonl": githubRecording})
+
+	err := RunMCPReplay(context.Background(), MCPReplayConfig{Run: dir, ServerName: "tavily", Stdio: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recording for MCP server 'tavily'; recorded servers: github")
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	assert.Equal(t, `{"a":1,"b":[2,{"c":3,"d":4}]}`, canonicalJSON([]byte(`{"b":[2,{"d":4,"c":3}],"a":1}`)))
+	assert.Equal(t, "{}", canonicalJSON(nil))
+	assert.Equal(t, "{}", canonicalJSON([]byte("null")))
+}
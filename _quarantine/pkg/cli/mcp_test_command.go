@@ -0,0 +1,540 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+)
+
+var mcpTestLog = logger.New("cli:mcp_test")
+
+// safeInputsTestSuffix is the suffix of safe-inputs test files; the test file of
+// .github/workflows/triage.md is .github/workflows/triage.safe-inputs.test.yml
+const safeInputsTestSuffix = ".safe-inputs.test.yml"
+
+// safeInputsTestFile is a file of test cases for the safe-inputs tools of a workflow
+type safeInputsTestFile struct {
+	Env   map[string]string    `yaml:"env"`   // Environment stubs for all test cases
+	Tests []safeInputsTestCase `yaml:"tests"` // Test cases, run in order
+}
+
+// safeInputsTestCase calls one tool with arguments and checks its output
+type safeInputsTestCase struct {
+	Name   string               `yaml:"name"`
+	Tool   string               `yaml:"tool"`
+	Args   map[string]any       `yaml:"args"`
+	Env    map[string]string    `yaml:"env"` // Environment stubs, overriding the file-level ones
+	Expect safeInputsTestExpect `yaml:"expect"`
+}
+
+// safeInputsTestExpect describes the expected result of a tool call. Without expectations a test
+// case passes when the tool succeeds.
+type safeInputsTestExpect struct {
+	Output *string `yaml:"output"` // Exact output, after trimming whitespace
+	Regex  string  `yaml:"regex"`  // Regular expression the output must match
+	JSON   any     `yaml:"json"`   // JSON value the output must contain (objects may have extra keys)
+	Error  string  `yaml:"error"`  // Regular expression the error must match; the call must fail
+}
+
+// safeInputsTestResult is the outcome of a test case
+type safeInputsTestResult struct {
+	Case     safeInputsTestCase
+	Output   string
+	Failure  string // Expectation that was not met
+	Error    string // Problem running the test case
+	Duration time.Duration
+}
+
+// NewMCPTestSubcommand creates the mcp test subcommand
+func NewMCPTestSubcommand() *cobra.Command {
+	var junitOutput string
+
+	cmd := &cobra.Command{
+		Use:   "test [workflow]",
+		Short: "Run test cases against the safe-inputs tools of workflows",
+		Long: `Run regression tests for safe-inputs tools.
+
+Test cases live next to the workflow in <workflow>` + safeInputsTestSuffix + `. The command starts
+the safe-inputs MCP server of the workflow, calls each tool through an MCP client and
+compares the result with the expectations:
+
+  env:                          # Environment stubs for every test case (e.g. secrets)
+    API_TOKEN: test-token
+  tests:
+    - name: finds open issues
+      tool: search-issues
+      args:
+        query: "is:open"
+      env:
+        API_URL: http://localhost:9999   # Per-case stubs override the file-level ones
+      expect:
+        json: {total: 2}        # The output JSON must contain these values
+    - name: rejects empty queries
+      tool: search-issues
+      args: {query: ""}
+      expect:
+        error: "query is required"  # Regular expression the error must match
+
+Expectations are 'output' (exact text), 'regex' and 'json' (subset match; objects may have
+extra keys, arrays must match element by element). Tools returning a JSON string are compared
+by the string's value.
+
+Without a workflow, all test files in .github/workflows are run.
+
+Examples:
+  gh aw mcp test                       # Run all safe-inputs tests
+  gh aw mcp test issue-triage          # Run the tests of issue-triage.md
+  gh aw mcp test --junit results.xml   # Also write a JUnit XML report`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var workflowFile string
+			if len(args) > 0 {
+				workflowFile = args[0]
+			}
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			if cmd.Parent() != nil {
+				if parentVerbose, _ := cmd.Parent().PersistentFlags().GetBool("verbose"); parentVerbose {
+					verbose = true
+				}
+			}
+			return RunMCPTest(workflowFile, junitOutput, verbose)
+		},
+	}
+
+	cmd.Flags().StringVar(&junitOutput, "junit", "", "Write results as a JUnit XML report to this file ('-' for stdout)")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
+
+// safeInputsTestFilePath returns the test file path of a workflow markdown file
+func safeInputsTestFilePath(workflowPath string) string {
+	return strings.TrimSuffix(workflowPath, ".md") + safeInputsTestSuffix
+}
+
+// RunMCPTest runs the safe-inputs test cases of a workflow, or of all workflows with test files
+func RunMCPTest(workflowFile, junitOutput string, verbose bool) error {
+	mcpTestLog.Printf("Running safe-inputs tests: workflow=%s, junit=%s", workflowFile, junitOutput)
+
+	var workflowPaths []string
+	if workflowFile != "" {
+		workflowPath, err := ResolveWorkflowPath(workflowFile)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(safeInputsTestFilePath(workflowPath)); err != nil {
+			return fmt.Errorf("no test file found for %s: create %s", workflowFile, safeInputsTestFilePath(workflowPath))
+		}
+		workflowPaths = append(workflowPaths, workflowPath)
+	} else {
+		testFiles, err := filepath.Glob(filepath.Join(getWorkflowsDir(), "*"+safeInputsTestSuffix))
+		if err != nil {
+			return fmt.Errorf("failed to find test files: %w", err)
+		}
+		if len(testFiles) == 0 {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No *%s files found in %s", safeInputsTestSuffix, getWorkflowsDir())))
+			return nil
+		}
+		sort.Strings(testFiles)
+		for _, testFile := range testFiles {
+			workflowPaths = append(workflowPaths, strings.TrimSuffix(testFile, safeInputsTestSuffix)+".md")
+		}
+	}
+
+	report := &junitTestSuites{Name: "safe-inputs"}
+	start := time.Now()
+	passed, failed := 0, 0
+	for _, workflowPath := range workflowPaths {
+		suiteStart := time.Now()
+		name := strings.TrimSuffix(filepath.Base(workflowPath), ".md")
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Testing safe-inputs of "+name))
+
+		results, err := runSafeInputsTestFile(workflowPath, verbose)
+		if err != nil {
+			// The whole file could not be run: report it as a single errored test case
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s: %v", name, err)))
+			results = []safeInputsTestResult{{Case: safeInputsTestCase{Name: "load " + filepath.Base(safeInputsTestFilePath(workflowPath))}, Error: err.Error()}}
+		} else {
+			for _, result := range results {
+				displaySafeInputsTestResult(result, verbose)
+			}
+		}
+
+		suite := junitTestSuite{Name: name, Timestamp: suiteStart.UTC().Format(time.RFC3339)}
+		for _, result := range results {
+			if result.Failure == "" && result.Error == "" {
+				passed++
+			} else {
+				failed++
+			}
+			suite.Cases = append(suite.Cases, result.junitTestCase(name))
+		}
+		report.addSuite(suite, time.Since(suiteStart))
+	}
+	report.Time = junitSeconds(time.Since(start))
+
+	if junitOutput != "" {
+		if err := writeJUnitFile(junitOutput, report); err != nil {
+			return err
+		}
+		if junitOutput != "-" {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("JUnit report written to "+junitOutput))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d safe-inputs test(s) failed", failed, passed+failed)
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%d safe-inputs test(s) passed", passed)))
+	return nil
+}
+
+// displaySafeInputsTestResult prints the outcome of a test case
+func displaySafeInputsTestResult(result safeInputsTestResult, verbose bool) {
+	switch {
+	case result.Error != "":
+		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s: %s", result.Case.Name, result.Error)))
+	case result.Failure != "":
+		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s: %s", result.Case.Name, result.Failure)))
+	default:
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%s (%s)", result.Case.Name, result.Duration.Round(time.Millisecond))))
+	}
+	if verbose && result.Output != "" {
+		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage("Output: "+result.Output))
+	}
+}
+
+// junitTestCase converts a test result to a JUnit test case
+func (r safeInputsTestResult) junitTestCase(suite string) junitTestCase {
+	testCase := junitTestCase{
+		Name:      r.Case.Name,
+		ClassName: suite + "." + r.Case.Tool,
+		Time:      junitSeconds(r.Duration),
+		SystemOut: r.Output,
+	}
+	if r.Case.Tool == "" {
+		testCase.ClassName = suite
+	}
+	if r.Failure != "" {
+		testCase.Failure = &junitProblem{Message: r.Failure, Type: "AssertionError", Text: r.Output}
+	}
+	if r.Error != "" {
+		testCase.Error = &junitProblem{Message: r.Error, Type: "Error"}
+	}
+	return testCase
+}
+
+// loadSafeInputsTestFile reads and checks the test file of a workflow
+func loadSafeInputsTestFile(path string, config *workflow.SafeInputsConfig) (*safeInputsTestFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test file: %w", err)
+	}
+	var file safeInputsTestFile
+	if err := yaml.UnmarshalWithOptions(data, &file, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("invalid test file %s: %w", filepath.Base(path), err)
+	}
+	if len(file.Tests) == 0 {
+		return nil, fmt.Errorf("test file %s has no tests", filepath.Base(path))
+	}
+
+	seen := make(map[string]bool)
+	for i := range file.Tests {
+		testCase := &file.Tests[i]
+		if testCase.Tool == "" {
+			return nil, fmt.Errorf("test %d: tool is required", i+1)
+		}
+		if testCase.Name == "" {
+			testCase.Name = fmt.Sprintf("%s #%d", testCase.Tool, i+1)
+		}
+		if seen[testCase.Name] {
+			return nil, fmt.Errorf("test '%s' is defined more than once", testCase.Name)
+		}
+		seen[testCase.Name] = true
+		if config == nil || config.Tools[testCase.Tool] == nil {
+			return nil, fmt.Errorf("test '%s': the workflow has no safe-inputs tool '%s'", testCase.Name, testCase.Tool)
+		}
+		if testCase.Expect.Regex != "" {
+			if _, err := regexp.Compile(testCase.Expect.Regex); err != nil {
+				return nil, fmt.Errorf("test '%s': invalid regex: %w", testCase.Name, err)
+			}
+		}
+		if testCase.Expect.Error != "" {
+			if _, err := regexp.Compile(testCase.Expect.Error); err != nil {
+				return nil, fmt.Errorf("test '%s': invalid error regex: %w", testCase.Name, err)
+			}
+		}
+		testCase.Env = mergeTestEnv(file.Env, testCase.Env)
+	}
+	return &file, nil
+}
+
+// mergeTestEnv returns the file-level environment stubs overridden by the test case ones
+func mergeTestEnv(base, override map[string]string) map[string]string {
+	env := make(map[string]string, len(base)+len(override))
+	for key, value := range base {
+		env[key] = value
+	}
+	for key, value := range override {
+		env[key] = value
+	}
+	return env
+}
+
+// testEnvList returns environment stubs as sorted KEY=value entries
+func testEnvList(env map[string]string) []string {
+	list := make([]string, 0, len(env))
+	for _, key := range sortedKeys(env) {
+		list = append(list, key+"="+env[key])
+	}
+	return list
+}
+
+// runSafeInputsTestFile runs the test cases of a workflow. A safe-inputs server is started for
+// each distinct set of environment stubs, since tools read them from the server's environment.
+func runSafeInputsTestFile(workflowPath string, verbose bool) ([]safeInputsTestResult, error) {
+	absPath, err := filepath.Abs(workflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve workflow path: %w", err)
+	}
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose))
+	workflowData, err := compiler.ParseWorkflowFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+	file, err := loadSafeInputsTestFile(safeInputsTestFilePath(workflowPath), workflowData.SafeInputs)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath("node"); err != nil {
+		return nil, fmt.Errorf("node not found. Please install Node.js to run the safe-inputs MCP server: %w", err)
+	}
+
+	// Group test cases by environment, keeping the first-seen order of groups
+	var groupKeys []string
+	groups := make(map[string][]int)
+	for i, testCase := range file.Tests {
+		key := strings.Join(testEnvList(testCase.Env), "\n")
+		if _, ok := groups[key]; !ok {
+			groupKeys = append(groupKeys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	results := make([]safeInputsTestResult, len(file.Tests))
+	for _, key := range groupKeys {
+		indexes := groups[key]
+		mcpTestLog.Printf("Running %d test case(s) with %d environment stub(s)", len(indexes), len(file.Tests[indexes[0]].Env))
+		runSafeInputsTestGroup(workflowData.SafeInputs, file.Tests, indexes, results, verbose)
+	}
+	return results, nil
+}
+
+// runSafeInputsTestGroup starts a safe-inputs server with the environment stubs of the given test
+// cases and runs them, storing their results at the same indexes
+func runSafeInputsTestGroup(config *workflow.SafeInputsConfig, cases []safeInputsTestCase, indexes []int, results []safeInputsTestResult, verbose bool) {
+	fail := func(err error) {
+		for _, i := range indexes {
+			results[i] = safeInputsTestResult{Case: cases[i], Error: err.Error()}
+		}
+	}
+
+	serverConfig, serverCmd, tmpDir, err := startSafeInputsServerWithEnv(config, testEnvList(cases[indexes[0]].Env), verbose)
+	if err != nil {
+		fail(err)
+		return
+	}
+	defer stopSafeInputsServer(serverCmd, tmpDir, verbose)
+
+	ctx := context.Background()
+	client := mcp.NewClient(&mcp.Implementation{Name: "gh-aw-test", Version: GetVersion()}, nil)
+	connectCtx, cancel := context.WithTimeout(ctx, MCPConnectTimeout)
+	defer cancel()
+	session, err := client.Connect(connectCtx, &mcp.StreamableClientTransport{Endpoint: serverConfig.URL}, nil)
+	if err != nil {
+		fail(fmt.Errorf("failed to connect to safe-inputs server: %w", err))
+		return
+	}
+	defer session.Close()
+
+	for _, i := range indexes {
+		results[i] = runSafeInputsTestCase(ctx, session, config.Tools[cases[i].Tool], cases[i])
+	}
+}
+
+// stopSafeInputsServer stops a safe-inputs server and removes its files
+func stopSafeInputsServer(serverCmd *exec.Cmd, tmpDir string, verbose bool) {
+	if serverCmd.Process != nil {
+		if err := serverCmd.Process.Signal(os.Interrupt); err != nil && verbose {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to send interrupt signal: %v", err)))
+		}
+		time.Sleep(500 * time.Millisecond)
+		_ = serverCmd.Process.Kill()
+	}
+	if err := os.RemoveAll(tmpDir); err != nil && verbose {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to cleanup temporary directory: %v", err)))
+	}
+}
+
+// runSafeInputsTestCase calls the tool of a test case and checks the result
+func runSafeInputsTestCase(ctx context.Context, session *mcp.ClientSession, tool *workflow.SafeInputToolConfig, testCase safeInputsTestCase) safeInputsTestResult {
+	result := safeInputsTestResult{Case: testCase}
+
+	timeout := time.Duration(tool.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	args := testCase.Args
+	if args == nil {
+		args = map[string]any{}
+	}
+	start := time.Now()
+	callResult, err := session.CallTool(callCtx, &mcp.CallToolParams{Name: testCase.Tool, Arguments: args})
+	result.Duration = time.Since(start)
+
+	var callErr string
+	switch {
+	case callCtx.Err() == context.DeadlineExceeded:
+		result.Error = fmt.Sprintf("timed out after %s", timeout)
+		return result
+	case err != nil:
+		callErr = err.Error()
+	case callResult.IsError:
+		callErr = toolResultText(callResult)
+		if callErr == "" {
+			callErr = "tool returned an error"
+		}
+	default:
+		result.Output = toolResultText(callResult)
+	}
+
+	result.Failure = checkSafeInputsTestExpect(testCase.Expect, result.Output, callErr)
+	if callErr != "" && result.Failure == "" && testCase.Expect.Error == "" {
+		result.Failure = callErr
+	}
+	return result
+}
+
+// toolResultText concatenates the text content of a tool result
+func toolResultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n"))
+}
+
+// checkSafeInputsTestExpect checks a tool output or error against the expectations and returns a
+// description of the first unmet expectation, or "" when all are met
+func checkSafeInputsTestExpect(expect safeInputsTestExpect, output, callErr string) string {
+	if expect.Error != "" {
+		if callErr == "" {
+			return fmt.Sprintf("expected an error matching %q but the tool succeeded", expect.Error)
+		}
+		if !regexp.MustCompile(expect.Error).MatchString(callErr) {
+			return fmt.Sprintf("error %q does not match %q", callErr, expect.Error)
+		}
+		return ""
+	}
+	if callErr != "" {
+		return "tool failed: " + callErr
+	}
+
+	// Tools returning a string produce a JSON string; compare its value
+	text := output
+	var value any
+	isJSON := json.Unmarshal([]byte(output), &value) == nil
+	if s, ok := value.(string); isJSON && ok {
+		text = s
+	}
+
+	if expect.Output != nil && strings.TrimSpace(text) != strings.TrimSpace(*expect.Output) {
+		return fmt.Sprintf("output %q does not equal %q", text, strings.TrimSpace(*expect.Output))
+	}
+	if expect.Regex != "" && !regexp.MustCompile(expect.Regex).MatchString(text) {
+		return fmt.Sprintf("output %q does not match %q", text, expect.Regex)
+	}
+	if expect.JSON != nil {
+		if !isJSON {
+			return fmt.Sprintf("output is not JSON: %s", output)
+		}
+		expected, err := normalizeJSONValue(expect.JSON)
+		if err != nil {
+			return fmt.Sprintf("invalid json expectation: %v", err)
+		}
+		if path, ok := jsonContains(value, expected, "$"); !ok {
+			return fmt.Sprintf("output does not contain the expected JSON at %s", path)
+		}
+	}
+	return ""
+}
+
+// normalizeJSONValue converts a YAML value to the types produced by encoding/json
+func normalizeJSONValue(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized any
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+// jsonContains reports whether actual contains expected: objects may have extra keys, arrays must
+// have the same length and match element by element, other values must be equal. On mismatch it
+// returns the path of the first difference.
+func jsonContains(actual, expected any, path string) (string, bool) {
+	switch exp := expected.(type) {
+	case map[string]any:
+		act, ok := actual.(map[string]any)
+		if !ok {
+			return path, false
+		}
+		for _, key := range sortedKeys(exp) {
+			value, ok := act[key]
+			if !ok {
+				return path + "." + key, false
+			}
+			if p, ok := jsonContains(value, exp[key], path+"."+key); !ok {
+				return p, false
+			}
+		}
+		return "", true
+	case []any:
+		act, ok := actual.([]any)
+		if !ok || len(act) != len(exp) {
+			return path, false
+		}
+		for i := range exp {
+			if p, ok := jsonContains(act[i], exp[i], fmt.Sprintf("%s[%d]", path, i)); !ok {
+				return p, false
+			}
+		}
+		return "", true
+	default:
+		if !reflect.DeepEqual(actual, expected) {
+			return path, false
+		}
+		return "", true
+	}
+}
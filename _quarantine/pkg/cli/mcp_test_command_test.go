@@ -0,0 +1,210 @@
+//go:build !integration
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSafeInputsTestFile(t *testing.T) {
+	dir := testutil.TempDir(t, "safe-inputs-test-*")
+	config := &workflow.SafeInputsConfig{Tools: map[string]*workflow.SafeInputToolConfig{
+		"search": {Name: "search"},
+	}}
+	write := func(content string) string {
+		path := filepath.Join(dir, "triage"+safeInputsTestSuffix)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	file, err := loadSafeInputsTestFile(write(`env:
+  TOKEN: stub
+  URL: http://example.test
+tests:
+  - name: finds issues
+    tool: search
+    args: {query: bug}
+    env: {URL: http://localhost}
+    expect:
+      json: {total: 2}
+  - tool: search
+    expect:
+      output: ""
+`), config)
+	require.NoError(t, err)
+	require.Len(t, file.Tests, 2)
+	assert.Equal(t, map[string]string{"TOKEN": "stub", "URL": "http://localhost"}, file.Tests[0].Env)
+	assert.Equal(t, map[string]string{"TOKEN": "stub", "URL": "http://example.test"}, file.Tests[1].Env)
+	assert.Equal(t, "search #2", file.Tests[1].Name)
+	require.NotNil(t, file.Tests[1].Expect.Output, "an empty output expectation is kept")
+	assert.Equal(t, []string{"TOKEN=stub", "URL=http://localhost"}, testEnvList(file.Tests[0].Env))
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"no tests", "env: {A: b}\n", "has no tests"},
+		{"missing tool", "tests:\n  - name: x\n", "test 1: tool is required"},
+		{"unknown tool", "tests:\n  - tool: fetch\n", "the workflow has no safe-inputs tool 'fetch'"},
+		{"duplicate name", "tests:\n  - {name: a, tool: search}\n  - {name: a, tool: search}\n", "test 'a' is defined more than once"},
+		{"bad regex", "tests:\n  - {tool: search, expect: {regex: '('}}\n", "invalid regex"},
+		{"unknown field", "tests:\n  - {tool: search, expected: {}}\n", "invalid test file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadSafeInputsTestFile(write(tt.content), config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckSafeInputsTestExpect(t *testing.T) {
+	exact := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		expect  safeInputsTestExpect
+		output  string
+		callErr string
+		want    string
+	}{
+		{name: "no expectations", output: `{"ok":true}`},
+		{name: "tool failure", callErr: "boom", want: "tool failed: boom"},
+		{name: "exact string value", expect: safeInputsTestExpect{Output: exact("hello")}, output: `"hello\n"`},
+		{name: "exact mismatch", expect: safeInputsTestExpect{Output: exact("hello")}, output: `"bye"`, want: `output "bye" does not equal "hello"`},
+		{name: "regex", expect: safeInputsTestExpect{Regex: `^\{"stdout":"ok`}, output: `{"stdout":"ok\n"}`},
+		{name: "regex mismatch", expect: safeInputsTestExpect{Regex: `^ok$`}, output: "nope", want: `does not match "^ok$"`},
+		{
+			name:   "json subset",
+			expect: safeInputsTestExpect{JSON: map[string]any{"total": uint64(2), "items": []any{map[string]any{"id": 1}}}},
+			output: `{"total":2,"items":[{"id":1,"title":"x"}],"extra":true}`,
+		},
+		{
+			name:   "json missing key",
+			expect: safeInputsTestExpect{JSON: map[string]any{"items": []any{map[string]any{"title": "y"}}}},
+			output: `{"items":[{"id":1,"title":"x"}]}`,
+			want:   "output does not contain the expected JSON at $.items[0].title",
+		},
+		{name: "json array length", expect: safeInputsTestExpect{JSON: []any{1}}, output: `[1,2]`, want: "at $"},
+		{name: "json not json", expect: safeInputsTestExpect{JSON: true}, output: "true!", want: "output is not JSON"},
+		{name: "expected error", expect: safeInputsTestExpect{Error: "query is required"}, callErr: "validation: query is required"},
+		{name: "expected error mismatch", expect: safeInputsTestExpect{Error: "^timeout"}, callErr: "boom", want: `error "boom" does not match "^timeout"`},
+		{name: "expected error missing", expect: safeInputsTestExpect{Error: "boom"}, output: "{}", want: "but the tool succeeded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkSafeInputsTestExpect(tt.expect, tt.output, tt.callErr)
+			if tt.want == "" {
+				assert.Empty(t, got)
+			} else {
+				assert.Contains(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSafeInputsTestCase(t *testing.T) {
+	type echoArgs struct {
+		Query string `json:"query,omitempty"`
+	}
+	server := mcp.NewServer(&mcp.Implementation{Name: "safeinputs", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "search"}, func(ctx context.Context, req *mcp.CallToolRequest, args echoArgs) (*mcp.CallToolResult, any, error) {
+		if args.Query == "" {
+			return nil, nil, errors.New("query is required")
+		}
+		data, _ := json.Marshal(map[string]any{"query": args.Query, "total": 2})
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(data)}}}, nil, nil
+	})
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+	client := mcp.NewClient(&mcp.Implementation{Name: "gh-aw-test", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	tool := &workflow.SafeInputToolConfig{Name: "search", Timeout: 5}
+
+	result := runSafeInputsTestCase(ctx, session, tool, safeInputsTestCase{
+		Name:   "finds issues",
+		Tool:   "search",
+		Args:   map[string]any{"query": "bug"},
+		Expect: safeInputsTestExpect{JSON: map[string]any{"total": 2}},
+	})
+	assert.Empty(t, result.Failure)
+	assert.Empty(t, result.Error)
+	assert.JSONEq(t, `{"query":"bug","total":2}`, result.Output)
+
+	result = runSafeInputsTestCase(ctx, session, tool, safeInputsTestCase{
+		Name:   "wrong total",
+		Tool:   "search",
+		Args:   map[string]any{"query": "bug"},
+		Expect: safeInputsTestExpect{JSON: map[string]any{"total": 3}},
+	})
+	assert.Contains(t, result.Failure, "at $.total")
+
+	result = runSafeInputsTestCase(ctx, session, tool, safeInputsTestCase{
+		Name:   "rejects empty queries",
+		Tool:   "search",
+		Expect: safeInputsTestExpect{Error: "query is required"},
+	})
+	assert.Empty(t, result.Failure)
+
+	result = runSafeInputsTestCase(ctx, session, tool, safeInputsTestCase{Name: "unexpected error", Tool: "search"})
+	assert.Contains(t, result.Failure, "query is required")
+}
+
+func TestWriteJUnitFile(t *testing.T) {
+	results := []safeInputsTestResult{
+		{Case: safeInputsTestCase{Name: "passes", Tool: "search"}, Output: "{}", Duration: 1500 * time.Millisecond},
+		{Case: safeInputsTestCase{Name: "fails", Tool: "search"}, Output: "[]", Failure: "output does not contain the expected JSON at $"},
+		{Case: safeInputsTestCase{Name: "load triage.safe-inputs.test.yml"}, Error: "node not found"},
+	}
+	suite := junitTestSuite{Name: "triage"}
+	for _, result := range results {
+		suite.Cases = append(suite.Cases, result.junitTestCase("triage"))
+	}
+	report := &junitTestSuites{Name: "safe-inputs"}
+	report.addSuite(suite, 2*time.Second)
+
+	path := filepath.Join(testutil.TempDir(t, "junit-*"), "reports", "results.xml")
+	require.NoError(t, writeJUnitFile(path, report))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var parsed junitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &parsed))
+	assert.Equal(t, 3, parsed.Tests)
+	assert.Equal(t, 1, parsed.Failures)
+	assert.Equal(t, 1, parsed.Errors)
+	require.Len(t, parsed.Suites, 1)
+	assert.Equal(t, "2.000", parsed.Suites[0].Time)
+	cases := parsed.Suites[0].Cases
+	require.Len(t, cases, 3)
+	assert.Equal(t, "triage.search", cases[0].ClassName)
+	assert.Equal(t, "1.500", cases[0].Time)
+	assert.Nil(t, cases[0].Failure)
+	require.NotNil(t, cases[1].Failure)
+	assert.Equal(t, "output does not contain the expected JSON at $", cases[1].Failure.Message)
+	assert.Equal(t, "triage", cases[2].ClassName)
+	require.NotNil(t, cases[2].Error)
+	assert.Equal(t, "node not found", cases[2].Error.Message)
+}
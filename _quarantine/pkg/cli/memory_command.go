@@ -0,0 +1,712 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var memoryCommandLog = logger.New("cli:memory_command")
+
+// MemoryConfig holds the options shared by the memory subcommands
+type MemoryConfig struct {
+	WorkflowID string
+	MemoryID   string // repo-memory ID (defaults to the only memory of the workflow)
+	Repo       string // Repository holding the memory branch (defaults to the memory target-repo, then the current repository)
+	Verbose    bool
+}
+
+// NewMemoryCommand creates the main memory command with subcommands
+func NewMemoryCommand() *cobra.Command {
+	memoryCommandLog.Print("Creating memory command with subcommands")
+	cmd := &cobra.Command{
+		Use:   "memory",
+		Short: "Browse, diff and maintain the repo-memory of agentic workflows",
+		Long: `Inspect and maintain the memory that workflows persist with tools.repo-memory.
+
+Each repo-memory is stored on a git branch (memory/<id> by default) and every workflow
+run that changes it adds a commit naming the run. These commands read that history
+from the local repository (fetching the branch from origin) or, with --repo or a
+memory target-repo, from another repository.
+
+Available subcommands:
+  • list   - List the memories of a workflow with their size and last update
+  • show   - List the files of a memory or print a file
+  • log    - Show the history of a memory or a file across runs
+  • diff   - Diff the memory between two runs
+  • prune  - Remove files by age or size
+  • export - Export a memory as a tarball
+  • import - Seed a memory from a tarball
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory list daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` memory log daily-report notes.md
+  ` + string(constants.CLIExtensionPrefix) + ` memory diff daily-report 12345678 12349999`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(NewMemoryListSubcommand())
+	cmd.AddCommand(NewMemoryShowSubcommand())
+	cmd.AddCommand(NewMemoryLogSubcommand())
+	cmd.AddCommand(NewMemoryDiffSubcommand())
+	cmd.AddCommand(NewMemoryPruneSubcommand())
+	cmd.AddCommand(NewMemoryExportSubcommand())
+	cmd.AddCommand(NewMemoryImportSubcommand())
+
+	return cmd
+}
+
+// addMemoryFlags adds the flags shared by the memory subcommands
+func addMemoryFlags(cmd *cobra.Command) {
+	cmd.Flags().String("id", "", "Repo-memory ID (default: the only memory of the workflow)")
+	addRepoFlag(cmd)
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+}
+
+// memoryConfigFromFlags reads the shared memory flags
+func memoryConfigFromFlags(cmd *cobra.Command, workflowID string) MemoryConfig {
+	memoryID, _ := cmd.Flags().GetString("id")
+	repo, _ := cmd.Flags().GetString("repo")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	return MemoryConfig{WorkflowID: workflowID, MemoryID: memoryID, Repo: repo, Verbose: verbose}
+}
+
+// NewMemoryListSubcommand creates the memory list subcommand
+func NewMemoryListSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list <workflow>",
+		Short: "List the repo-memories of a workflow with their size and last update",
+		Long: `List the repo-memories configured by a workflow with their branch, number of files,
+total size and the run that last updated them.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory list daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` memory list daily-report --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return RunMemoryList(memoryConfigFromFlags(cmd, args[0]), jsonOutput)
+		},
+	}
+	addMemoryFlags(cmd)
+	addJSONFlag(cmd)
+	return cmd
+}
+
+// NewMemoryShowSubcommand creates the memory show subcommand
+func NewMemoryShowSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <workflow> [file]",
+		Short: "List the files of a repo-memory or print one of them",
+		Long: `List the files of a repo-memory, or print a file to stdout.
+
+Use --run to look at the memory as a given workflow run left it (a run ID or a commit).
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory show daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` memory show daily-report notes.md
+  ` + string(constants.CLIExtensionPrefix) + ` memory show daily-report notes.md --run 12345678`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			revision, _ := cmd.Flags().GetString("run")
+			file := ""
+			if len(args) > 1 {
+				file = args[1]
+			}
+			return RunMemoryShow(memoryConfigFromFlags(cmd, args[0]), file, revision)
+		},
+	}
+	addMemoryFlags(cmd)
+	cmd.Flags().String("run", "", "Workflow run ID or commit to show (default: latest)")
+	return cmd
+}
+
+// NewMemoryLogSubcommand creates the memory log subcommand
+func NewMemoryLogSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "log <workflow> [file]",
+		Short: "Show the history of a repo-memory or one of its files across runs",
+		Long: `Show the commits of a repo-memory branch, newest first, with the workflow run that
+made each change and the files it touched. Give a file to follow only its history.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory log daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` memory log daily-report notes.md --json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			file := ""
+			if len(args) > 1 {
+				file = args[1]
+			}
+			return RunMemoryLog(memoryConfigFromFlags(cmd, args[0]), file, jsonOutput)
+		},
+	}
+	addMemoryFlags(cmd)
+	addJSONFlag(cmd)
+	return cmd
+}
+
+// NewMemoryDiffSubcommand creates the memory diff subcommand
+func NewMemoryDiffSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <workflow> <from> [to]",
+		Short: "Diff a repo-memory between two workflow runs",
+		Long: `Show how a repo-memory changed between two workflow runs. Runs are given by run ID
+(as shown by 'memory log') or commit; 'to' defaults to the latest state.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory diff daily-report 12345678             # Changes since run 12345678
+  ` + string(constants.CLIExtensionPrefix) + ` memory diff daily-report 12345678 12349999    # Changes between two runs
+  ` + string(constants.CLIExtensionPrefix) + ` memory diff daily-report 12345678 --file notes.md`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, _ := cmd.Flags().GetString("file")
+			to := ""
+			if len(args) > 2 {
+				to = args[2]
+			}
+			return RunMemoryDiff(memoryConfigFromFlags(cmd, args[0]), args[1], to, file)
+		},
+	}
+	addMemoryFlags(cmd)
+	cmd.Flags().String("file", "", "Only diff this file")
+	return cmd
+}
+
+// NewMemoryPruneSubcommand creates the memory prune subcommand
+func NewMemoryPruneSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune <workflow>",
+		Short: "Remove old or large files from a repo-memory",
+		Long: `Remove files from a repo-memory and push the change to its branch. Files are selected
+by the age of their last change (--older-than, e.g. 30d, 2w, 3mo) and/or by size
+(--larger-than, in bytes). The history of the branch is kept.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory prune daily-report --older-than 30d --dry-run
+  ` + string(constants.CLIExtensionPrefix) + ` memory prune daily-report --larger-than 10240`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			olderThan, _ := cmd.Flags().GetString("older-than")
+			largerThan, _ := cmd.Flags().GetInt64("larger-than")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			return RunMemoryPrune(memoryConfigFromFlags(cmd, args[0]), olderThan, largerThan, dryRun)
+		},
+	}
+	addMemoryFlags(cmd)
+	cmd.Flags().String("older-than", "", "Remove files not changed within this period (e.g. 30d, 2w, 3mo)")
+	cmd.Flags().Int64("larger-than", 0, "Remove files larger than this many bytes")
+	cmd.Flags().Bool("dry-run", false, "Show the files that would be removed without changing the branch")
+	return cmd
+}
+
+// NewMemoryExportSubcommand creates the memory export subcommand
+func NewMemoryExportSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <workflow>",
+		Short: "Export a repo-memory as a tarball",
+		Long: `Export the files of a repo-memory as a gzip-compressed tarball, e.g. to seed the memory
+of the workflow in another repository with 'memory import'.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory export daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` memory export daily-report -o memory.tar.gz --run 12345678`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
+			revision, _ := cmd.Flags().GetString("run")
+			return RunMemoryExport(memoryConfigFromFlags(cmd, args[0]), output, revision)
+		},
+	}
+	addMemoryFlags(cmd)
+	cmd.Flags().StringP("output", "o", "", "Tarball to write ('-' for stdout, default: <workflow>-<id>-memory.tar.gz)")
+	cmd.Flags().String("run", "", "Workflow run ID or commit to export (default: latest)")
+	return cmd
+}
+
+// NewMemoryImportSubcommand creates the memory import subcommand
+func NewMemoryImportSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <workflow> <tarball>",
+		Short: "Seed a repo-memory from a tarball",
+		Long: `Replace the files of a repo-memory with the content of a gzip-compressed tarball and
+push the result to its branch, creating the branch when needed. The memory's file-glob,
+max-file-size and max-file-count limits are enforced.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` memory import daily-report memory.tar.gz
+  ` + string(constants.CLIExtensionPrefix) + ` memory import daily-report memory.tar.gz --repo my-org/other-repo --force`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			force, _ := cmd.Flags().GetBool("force")
+			return RunMemoryImport(memoryConfigFromFlags(cmd, args[0]), args[1], force)
+		},
+	}
+	addMemoryFlags(cmd)
+	cmd.Flags().Bool("force", false, "Replace a memory that already has files")
+	return cmd
+}
+
+// loadRepoMemories returns the repo-memory entries configured by a workflow
+func loadRepoMemories(workflowID string, verbose bool) ([]workflow.RepoMemoryEntry, error) {
+	workflowFile, err := resolveWorkflowFile(workflowID, verbose)
+	if err != nil {
+		return nil, err
+	}
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose))
+	workflowData, err := compiler.ParseWorkflowFile(workflowFile)
+	if err != nil {
+		return nil, err
+	}
+	if workflowData.RepoMemoryConfig == nil || len(workflowData.RepoMemoryConfig.Memories) == 0 {
+		return nil, fmt.Errorf("workflow '%s' does not use tools.repo-memory", workflowID)
+	}
+	return workflowData.RepoMemoryConfig.Memories, nil
+}
+
+// selectRepoMemory selects a repo-memory by ID, defaulting to the only memory of the workflow
+func selectRepoMemory(memories []workflow.RepoMemoryEntry, memoryID string) (workflow.RepoMemoryEntry, error) {
+	ids := make([]string, 0, len(memories))
+	for _, memory := range memories {
+		if memory.ID == memoryID || (memoryID == "" && len(memories) == 1) {
+			return memory, nil
+		}
+		ids = append(ids, memory.ID)
+	}
+	if memoryID == "" {
+		return workflow.RepoMemoryEntry{}, fmt.Errorf("the workflow has %d repo-memories, select one with --id: %s", len(memories), strings.Join(ids, ", "))
+	}
+	return workflow.RepoMemoryEntry{}, fmt.Errorf("repo-memory '%s' not found, available: %s", memoryID, strings.Join(ids, ", "))
+}
+
+// memoryRepo returns the repository holding a memory branch, "" for the current repository
+func memoryRepo(config MemoryConfig, memory workflow.RepoMemoryEntry) string {
+	repo := config.Repo
+	if repo == "" {
+		repo = memory.TargetRepo
+	}
+	if repo == "" {
+		return ""
+	}
+	if current, err := GetCurrentRepoSlug(); err == nil && strings.EqualFold(current, repo) {
+		return ""
+	}
+	return repo
+}
+
+// openWorkflowMemory opens the memory branch selected by a memory configuration
+func openWorkflowMemory(config MemoryConfig) (*memoryStore, workflow.RepoMemoryEntry, error) {
+	memories, err := loadRepoMemories(config.WorkflowID, config.Verbose)
+	if err != nil {
+		return nil, workflow.RepoMemoryEntry{}, err
+	}
+	memory, err := selectRepoMemory(memories, config.MemoryID)
+	if err != nil {
+		return nil, workflow.RepoMemoryEntry{}, err
+	}
+	store, err := openMemoryStore(memory, memoryRepo(config, memory), config.Verbose)
+	if err != nil {
+		return nil, workflow.RepoMemoryEntry{}, err
+	}
+	return store, memory, nil
+}
+
+// memorySummary describes a repo-memory for memory list
+type memorySummary struct {
+	ID         string     `json:"id"`
+	Branch     string     `json:"branch"`
+	Repo       string     `json:"repo,omitempty"`
+	Exists     bool       `json:"exists"`
+	Files      int        `json:"files"`
+	Size       int64      `json:"size"`
+	LastUpdate *time.Time `json:"last_update,omitempty"`
+	LastRunID  int64      `json:"last_run_id,omitempty"`
+}
+
+// RunMemoryList lists the repo-memories of a workflow
+func RunMemoryList(config MemoryConfig, jsonOutput bool) error {
+	memoryCommandLog.Printf("Listing memories: workflow=%s, id=%s", config.WorkflowID, config.MemoryID)
+	memories, err := loadRepoMemories(config.WorkflowID, config.Verbose)
+	if err != nil {
+		return err
+	}
+	if config.MemoryID != "" {
+		memory, err := selectRepoMemory(memories, config.MemoryID)
+		if err != nil {
+			return err
+		}
+		memories = []workflow.RepoMemoryEntry{memory}
+	}
+
+	summaries := make([]memorySummary, 0, len(memories))
+	for _, memory := range memories {
+		repo := memoryRepo(config, memory)
+		store, err := openMemoryStore(memory, repo, config.Verbose)
+		if err != nil {
+			return err
+		}
+		summary, err := summarizeMemory(store, memory, repo)
+		store.Close()
+		if err != nil {
+			return err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if jsonOutput {
+		return printMemoryJSON(summaries)
+	}
+	rows := make([][]string, 0, len(summaries))
+	for _, summary := range summaries {
+		lastUpdate, lastRun := "-", "-"
+		if summary.LastUpdate != nil {
+			lastUpdate = summary.LastUpdate.Format("2006-01-02 15:04")
+		}
+		if summary.LastRunID != 0 {
+			lastRun = fmt.Sprintf("%d", summary.LastRunID)
+		}
+		files := fmt.Sprintf("%d", summary.Files)
+		if !summary.Exists {
+			files = "(no branch)"
+		}
+		rows = append(rows, []string{summary.ID, summary.Branch, files, console.FormatFileSize(summary.Size), lastUpdate, lastRun})
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   "Repo memories of " + config.WorkflowID,
+		Headers: []string{"ID", "Branch", "Files", "Size", "Last Update", "Last Run"},
+		Rows:    rows,
+	}))
+	return nil
+}
+
+// summarizeMemory collects the size and last update of a memory branch
+func summarizeMemory(store *memoryStore, memory workflow.RepoMemoryEntry, repo string) (memorySummary, error) {
+	summary := memorySummary{ID: memory.ID, Branch: store.branch, Repo: repo, Exists: store.exists()}
+	if !store.exists() {
+		return summary, nil
+	}
+	files, err := store.files(store.ref)
+	if err != nil {
+		return summary, err
+	}
+	summary.Files = len(files)
+	for _, file := range files {
+		summary.Size += file.Size
+	}
+	commits, err := store.log("")
+	if err != nil {
+		return summary, err
+	}
+	if len(commits) > 0 {
+		summary.LastUpdate = &commits[0].Date
+		// The last run is the newest commit made by a workflow run
+		for _, commit := range commits {
+			if commit.RunID != 0 {
+				summary.LastRunID = commit.RunID
+				break
+			}
+		}
+	}
+	return summary, nil
+}
+
+// RunMemoryShow lists the files of a memory or prints one of them
+func RunMemoryShow(config MemoryConfig, file, revision string) error {
+	store, memory, err := openWorkflowMemory(config)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	commit, err := store.resolveRevision(revision)
+	if err != nil {
+		return err
+	}
+	if file != "" {
+		content, err := store.readFile(commit, file)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(content)
+		return err
+	}
+
+	files, err := store.files(commit)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(files))
+	var total int64
+	for _, f := range files {
+		rows = append(rows, []string{f.Path, console.FormatFileSize(f.Size)})
+		total += f.Size
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:     fmt.Sprintf("Memory %s at %s", memory.ID, shortSHA(commit)),
+		Headers:   []string{"File", "Size"},
+		Rows:      rows,
+		ShowTotal: true,
+		TotalRow:  []string{fmt.Sprintf("%d file(s)", len(files)), console.FormatFileSize(total)},
+	}))
+	return nil
+}
+
+// RunMemoryLog shows the history of a memory or one of its files
+func RunMemoryLog(config MemoryConfig, file string, jsonOutput bool) error {
+	store, memory, err := openWorkflowMemory(config)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if !store.exists() {
+		return fmt.Errorf("memory branch %s does not exist", store.branch)
+	}
+	commits, err := store.log(file)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printMemoryJSON(commits)
+	}
+	if len(commits) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No history for %s in memory %s", file, memory.ID)))
+		return nil
+	}
+
+	rows := make([][]string, 0, len(commits))
+	for _, commit := range commits {
+		run := "-"
+		if commit.RunID != 0 {
+			run = fmt.Sprintf("%d", commit.RunID)
+		}
+		changes := make([]string, 0, len(commit.Changes))
+		for _, change := range commit.Changes {
+			changes = append(changes, strings.ReplaceAll(change, "\t", " "))
+		}
+		rows = append(rows, []string{shortSHA(commit.SHA), commit.Date.Format("2006-01-02 15:04"), run, strings.Join(changes, ", ")})
+	}
+	title := "History of memory " + memory.ID
+	if file != "" {
+		title += " (" + file + ")"
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   title,
+		Headers: []string{"Commit", "Date", "Run", "Changes"},
+		Rows:    rows,
+	}))
+	return nil
+}
+
+// RunMemoryDiff prints the diff of a memory between two runs
+func RunMemoryDiff(config MemoryConfig, from, to, file string) error {
+	store, _, err := openWorkflowMemory(config)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	fromCommit, err := store.resolveRevision(from)
+	if err != nil {
+		return err
+	}
+	toCommit, err := store.resolveRevision(to)
+	if err != nil {
+		return err
+	}
+	diff, err := store.diff(fromCommit, toCommit, file)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No changes between %s and %s", shortSHA(fromCommit), shortSHA(toCommit))))
+		return nil
+	}
+	fmt.Fprint(os.Stdout, diff)
+	return nil
+}
+
+// RunMemoryPrune removes files by age or size from a memory
+func RunMemoryPrune(config MemoryConfig, olderThan string, largerThan int64, dryRun bool) error {
+	if olderThan == "" && largerThan <= 0 {
+		return fmt.Errorf("specify --older-than and/or --larger-than")
+	}
+	var cutoff time.Time
+	if olderThan != "" {
+		resolved, err := workflow.ResolveRelativeDate("-"+strings.TrimPrefix(olderThan, "-"), time.Now())
+		if err != nil {
+			return fmt.Errorf("invalid --older-than '%s': %w", olderThan, err)
+		}
+		if cutoff, err = time.Parse(time.RFC3339, resolved); err != nil {
+			return fmt.Errorf("invalid --older-than '%s': use a period like 30d, 2w or 3mo", olderThan)
+		}
+	}
+
+	store, memory, err := openWorkflowMemory(config)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	if !store.exists() {
+		return fmt.Errorf("memory branch %s does not exist", store.branch)
+	}
+
+	files, err := store.files(store.ref)
+	if err != nil {
+		return err
+	}
+	modified, err := store.lastModified()
+	if err != nil {
+		return err
+	}
+	prune := selectMemoryFilesToPrune(files, modified, cutoff, largerThan)
+	if len(prune) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Nothing to prune in memory %s", memory.ID)))
+		return nil
+	}
+	for _, file := range prune {
+		fmt.Fprintln(os.Stderr, console.FormatListItem(file))
+	}
+	if dryRun {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Would remove %d of %d file(s) from %s", len(prune), len(files), store.branch)))
+		return nil
+	}
+
+	commit, err := store.removeFiles(prune, fmt.Sprintf("Prune %d file(s) from repo memory", len(prune)))
+	if err != nil {
+		return err
+	}
+	if err := store.update(commit); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Removed %d of %d file(s) from %s", len(prune), len(files), store.branch)))
+	return nil
+}
+
+// selectMemoryFilesToPrune returns the files last changed before cutoff (when set) or larger than largerThan (when positive)
+func selectMemoryFilesToPrune(files []memoryFile, modified map[string]time.Time, cutoff time.Time, largerThan int64) []string {
+	var prune []string
+	for _, file := range files {
+		tooOld := false
+		if !cutoff.IsZero() {
+			if changed, ok := modified[file.Path]; ok && changed.Before(cutoff) {
+				tooOld = true
+			}
+		}
+		if tooOld || (largerThan > 0 && file.Size > largerThan) {
+			prune = append(prune, file.Path)
+		}
+	}
+	slices.Sort(prune)
+	return prune
+}
+
+// RunMemoryExport writes a memory as a gzip-compressed tarball
+func RunMemoryExport(config MemoryConfig, output, revision string) error {
+	store, memory, err := openWorkflowMemory(config)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	commit, err := store.resolveRevision(revision)
+	if err != nil {
+		return err
+	}
+	if output == "-" {
+		return store.export(commit, os.Stdout)
+	}
+	if output == "" {
+		name := strings.TrimSuffix(filepath.Base(config.WorkflowID), ".md")
+		output = fmt.Sprintf("%s-%s-memory.tar.gz", name, memory.ID)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	if err := store.export(commit, file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Exported memory %s at %s to %s", memory.ID, shortSHA(commit), output)))
+	return nil
+}
+
+// RunMemoryImport replaces the files of a memory with the content of a tarball
+func RunMemoryImport(config MemoryConfig, tarball string, force bool) error {
+	store, memory, err := openWorkflowMemory(config)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if store.exists() && !force {
+		files, err := store.files(store.ref)
+		if err != nil {
+			return err
+		}
+		if len(files) > 0 {
+			return fmt.Errorf("memory %s already has %d file(s) on %s; use --force to replace them", memory.ID, len(files), store.branch)
+		}
+	}
+
+	input, err := os.Open(tarball)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", tarball, err)
+	}
+	defer input.Close()
+
+	dir, err := os.MkdirTemp("", "gh-aw-memory-import-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files, err := extractMemoryTarball(input, dir, memory)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%s has no files to import", tarball)
+	}
+
+	commit, err := store.replaceFiles(dir, "Import repo memory from "+filepath.Base(tarball))
+	if err != nil {
+		return err
+	}
+	if commit == "" {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Memory %s already matches %s", memory.ID, tarball)))
+		return nil
+	}
+	if err := store.update(commit); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Imported %d file(s) into memory %s on %s", len(files), memory.ID, store.branch)))
+	return nil
+}
+
+// printMemoryJSON prints a value as indented JSON on stdout
+func printMemoryJSON(value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
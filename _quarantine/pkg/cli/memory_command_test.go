@@ -0,0 +1,81 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectRepoMemory(t *testing.T) {
+	single := []workflow.RepoMemoryEntry{{ID: "default"}}
+	memory, err := selectRepoMemory(single, "")
+	require.NoError(t, err)
+	assert.Equal(t, "default", memory.ID)
+
+	multiple := []workflow.RepoMemoryEntry{{ID: "notes"}, {ID: "metrics"}}
+	_, err = selectRepoMemory(multiple, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "select one with --id: notes, metrics")
+
+	memory, err = selectRepoMemory(multiple, "metrics")
+	require.NoError(t, err)
+	assert.Equal(t, "metrics", memory.ID)
+
+	_, err = selectRepoMemory(multiple, "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "repo-memory 'missing' not found")
+}
+
+func TestSelectMemoryFilesToPrune(t *testing.T) {
+	now := time.Now()
+	files := []memoryFile{{Path: "old.md", Size: 10}, {Path: "big.json", Size: 5000}, {Path: "fresh.md", Size: 10}}
+	modified := map[string]time.Time{
+		"old.md":   now.AddDate(0, 0, -60),
+		"big.json": now,
+		"fresh.md": now,
+	}
+
+	assert.Equal(t, []string{"old.md"}, selectMemoryFilesToPrune(files, modified, now.AddDate(0, 0, -30), 0))
+	assert.Equal(t, []string{"big.json"}, selectMemoryFilesToPrune(files, modified, time.Time{}, 1024))
+	assert.Equal(t, []string{"big.json", "old.md"}, selectMemoryFilesToPrune(files, modified, now.AddDate(0, 0, -30), 1024))
+	assert.Empty(t, selectMemoryFilesToPrune(files, modified, now.AddDate(0, 0, -90), 0))
+}
+
+func TestRunMemoryCommands(t *testing.T) {
+	dir := setupMemoryRepo(t)
+	workflowsDir := filepath.Join(dir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "notes.md"), []byte("---\non: workflow_dispatch\nengine: claude\ntools:\n  repo-memory: true\n---\n# Notes\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "plain.md"), []byte("---\non: workflow_dispatch\nengine: claude\n---\n# Plain\n"), 0644))
+
+	config := MemoryConfig{WorkflowID: "notes"}
+	require.NoError(t, RunMemoryList(config, true))
+	require.NoError(t, RunMemoryLog(config, "notes.md", false))
+	require.NoError(t, RunMemoryDiff(config, "1111111", "", ""))
+
+	err := RunMemoryList(MemoryConfig{WorkflowID: "plain"}, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not use tools.repo-memory")
+
+	require.Error(t, RunMemoryPrune(config, "", 0, false), "a prune criterion is required")
+	require.NoError(t, RunMemoryPrune(config, "30d", 0, false))
+	store, err := openMemoryStore(workflow.RepoMemoryEntry{ID: "default"}, "", false)
+	require.NoError(t, err)
+	files, err := store.files(store.ref)
+	require.NoError(t, err)
+	assert.Len(t, files, 2, "old.md was last changed in 2024")
+
+	tarball := filepath.Join(dir, "memory.tar.gz")
+	require.NoError(t, RunMemoryExport(config, tarball, ""))
+	err = RunMemoryImport(config, tarball, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use --force to replace them")
+	require.NoError(t, RunMemoryImport(config, tarball, true))
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var networkCommandLog = logger.New("cli:network")
+
+// NewNetworkCommand creates the main network command with subcommands
+func NewNetworkCommand() *cobra.Command {
+	networkCommandLog.Print("Creating network command with subcommands")
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "Inspect and tune the network permissions of agentic workflows",
+		Long: `Network permission tooling for agentic workflows.
+
+Agentic workflows run behind a firewall that only allows the domains and ecosystems
+listed in network.allowed. This command helps keep that allow-list minimal.
+
+Available subcommands:
+  • suggest - Propose network.allowed entries from domains blocked in recent runs
+
+Examples:
+  gh aw network suggest weekly-research           # Propose entries from the last 10 runs
+  gh aw network suggest weekly-research --apply   # Apply the proposal and recompile`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	// Add subcommands
+	cmd.AddCommand(NewNetworkSuggestSubcommand())
+
+	return cmd
+}
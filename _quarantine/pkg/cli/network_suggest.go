@@ -0,0 +1,418 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/sliceutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var networkSuggestLog = logger.New("cli:network_suggest")
+
+// domainPortPattern matches a trailing port on a domain recorded by the firewall
+var domainPortPattern = regexp.MustCompile(`:\d+$`)
+
+// NetworkSuggestConfig holds configuration for the network suggest command
+type NetworkSuggestConfig struct {
+	WorkflowName string
+	Runs         int
+	OutputDir    string
+	RepoOverride string
+	Apply        bool
+	Verbose      bool
+	JSONOutput   bool
+}
+
+// BlockedDomainStat aggregates blocked requests to a domain across runs
+type BlockedDomainStat struct {
+	Domain    string `json:"domain" console:"header:Domain"`
+	Ecosystem string `json:"ecosystem,omitempty" console:"header:Ecosystem,omitempty"`
+	Requests  int    `json:"requests" console:"header:Blocked Requests"`
+	Runs      int    `json:"runs" console:"header:Runs"`
+}
+
+// NetworkSuggestion is an entry proposed for network.allowed
+type NetworkSuggestion struct {
+	Entry     string   `json:"entry"`     // Ecosystem identifier or domain
+	Ecosystem bool     `json:"ecosystem"` // True when Entry is an ecosystem identifier
+	Domains   []string `json:"domains"`   // Blocked domains covered by this entry
+}
+
+// NetworkSuggestReport is the result of analyzing blocked network requests for a workflow
+type NetworkSuggestReport struct {
+	Workflow        string              `json:"workflow"`
+	RunsAnalyzed    int                 `json:"runs_analyzed"` // Runs whose firewall logs were parsed
+	CurrentAllowed  []string            `json:"current_allowed"`
+	BlockedDomains  []BlockedDomainStat `json:"blocked_domains"`
+	Suggestions     []NetworkSuggestion `json:"suggestions"`
+	ProposedAllowed []string            `json:"proposed_allowed"`
+	Applied         bool                `json:"applied"`
+}
+
+// NewNetworkSuggestSubcommand creates the network suggest subcommand
+func NewNetworkSuggestSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "suggest <workflow>",
+		Short: "Propose network.allowed entries from domains blocked in recent runs",
+		Long: `Analyze the firewall logs of recent runs of a workflow and propose a minimal
+network.allowed patch that would have allowed the blocked requests.
+
+Blocked domains are mapped to ecosystem identifiers (e.g., python, node) when possible,
+since ecosystem identifiers are preferred over raw domains. Domains that are not part of
+an ecosystem are proposed as-is. Domains listed in network.blocked are never proposed.
+
+Run artifacts are downloaded to the output directory, and runs already downloaded with
+'` + string(constants.CLIExtensionPrefix) + ` logs' are reused.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` network suggest weekly-research            # Analyze the last 10 runs
+  ` + string(constants.CLIExtensionPrefix) + ` network suggest weekly-research --runs 25  # Analyze the last 25 runs
+  ` + string(constants.CLIExtensionPrefix) + ` network suggest weekly-research --apply    # Update the frontmatter and recompile
+  ` + string(constants.CLIExtensionPrefix) + ` network suggest weekly-research --json     # Output the report as JSON`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runs, _ := cmd.Flags().GetInt("runs")
+			outputDir, _ := cmd.Flags().GetString("output")
+			repoOverride, _ := cmd.Flags().GetString("repo")
+			apply, _ := cmd.Flags().GetBool("apply")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			return RunNetworkSuggest(NetworkSuggestConfig{
+				WorkflowName: args[0],
+				Runs:         runs,
+				OutputDir:    outputDir,
+				RepoOverride: repoOverride,
+				Apply:        apply,
+				Verbose:      verbose,
+				JSONOutput:   jsonOutput,
+			})
+		},
+	}
+
+	cmd.Flags().Int("runs", 10, "Number of recent runs to analyze")
+	cmd.Flags().Bool("apply", false, "Add the proposed entries to network.allowed and recompile the workflow")
+	addOutputFlag(cmd, defaultLogsOutputDir)
+	addRepoFlag(cmd)
+	addJSONFlag(cmd)
+
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+	RegisterDirFlagCompletion(cmd, "output")
+
+	return cmd
+}
+
+// RunNetworkSuggest analyzes recent runs of a workflow and proposes network.allowed entries
+func RunNetworkSuggest(config NetworkSuggestConfig) error {
+	networkSuggestLog.Printf("Running network suggest: workflow=%s, runs=%d, apply=%v", config.WorkflowName, config.Runs, config.Apply)
+
+	if config.Runs < 1 {
+		return errors.New("--runs must be at least 1")
+	}
+
+	workflowPath, err := ResolveWorkflowPath(config.WorkflowName)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to read workflow file: %w", err)
+	}
+	currentAllowed, blocked, err := readNetworkConfig(string(content))
+	if err != nil {
+		return err
+	}
+
+	workflowName, err := workflow.ResolveWorkflowName(config.WorkflowName)
+	if err != nil {
+		return err
+	}
+
+	runs, _, err := listWorkflowRunsWithPagination(ListWorkflowRunsOptions{
+		WorkflowName: workflowName,
+		Limit:        config.Runs,
+		RepoOverride: config.RepoOverride,
+		Verbose:      config.Verbose,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+	if len(runs) > config.Runs {
+		runs = runs[:config.Runs]
+	}
+	if !config.JSONOutput {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Analyzing firewall logs of %d runs of %s", len(runs), workflowName)))
+	}
+
+	var analyses []*FirewallAnalysis
+	for _, run := range runs {
+		analysis, err := loadRunFirewallAnalysis(run, config.OutputDir, config.Verbose)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Skipping run %d: %v", run.DatabaseID, err)))
+			continue
+		}
+		if analysis != nil {
+			analyses = append(analyses, analysis)
+		}
+	}
+
+	stats := aggregateBlockedDomains(analyses)
+	suggestions := suggestNetworkAllowed(stats, currentAllowed, blocked)
+	report := NetworkSuggestReport{
+		Workflow:        filepath.Base(workflowPath),
+		RunsAnalyzed:    len(analyses),
+		CurrentAllowed:  currentAllowed,
+		BlockedDomains:  stats,
+		Suggestions:     suggestions,
+		ProposedAllowed: proposedAllowedList(currentAllowed, suggestions),
+	}
+
+	if config.Apply && len(suggestions) > 0 {
+		if err := applyNetworkSuggestions(workflowPath, string(content), suggestions, config.Verbose); err != nil {
+			return err
+		}
+		report.Applied = true
+	}
+
+	if config.JSONOutput {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report to JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	displayNetworkSuggestReport(report)
+	return nil
+}
+
+// readNetworkConfig returns the network.allowed and network.blocked entries of a workflow.
+// Workflows without a network configuration are allowed the defaults ecosystem.
+func readNetworkConfig(content string) ([]string, []string, error) {
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	toStrings := func(value any) []string {
+		var values []string
+		if list, ok := value.([]any); ok {
+			for _, item := range list {
+				if s, ok := item.(string); ok {
+					values = append(values, s)
+				}
+			}
+		}
+		return values
+	}
+
+	switch network := result.Frontmatter["network"].(type) {
+	case nil:
+		return []string{"defaults"}, nil, nil
+	case string:
+		return []string{network}, nil, nil
+	case map[string]any:
+		return toStrings(network["allowed"]), toStrings(network["blocked"]), nil
+	default:
+		return nil, nil, errors.New("network field is not a string or an object")
+	}
+}
+
+// loadRunFirewallAnalysis returns the firewall analysis of a run, using the cached run summary
+// written by the logs command when available and downloading the run artifacts otherwise
+func loadRunFirewallAnalysis(run WorkflowRun, outputDir string, verbose bool) (*FirewallAnalysis, error) {
+	runOutputDir := filepath.Join(outputDir, fmt.Sprintf("run-%d", run.DatabaseID))
+	if summary, ok := loadRunSummary(runOutputDir, verbose); ok {
+		networkSuggestLog.Printf("Using cached summary for run %d", run.DatabaseID)
+		return summary.FirewallAnalysis, nil
+	}
+
+	if err := downloadRunArtifacts(run.DatabaseID, runOutputDir, verbose); err != nil {
+		if errors.Is(err, ErrNoArtifacts) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return analyzeFirewallLogs(runOutputDir, verbose)
+}
+
+// aggregateBlockedDomains combines the blocked domains of several runs
+func aggregateBlockedDomains(analyses []*FirewallAnalysis) []BlockedDomainStat {
+	byDomain := make(map[string]*BlockedDomainStat)
+	for _, analysis := range analyses {
+		seenInRun := make(map[string]bool)
+		for rawDomain, requestStats := range analysis.RequestsByDomain {
+			domain := domainPortPattern.ReplaceAllString(rawDomain, "")
+			if requestStats.Blocked == 0 || domain == "" || domain == "-" {
+				continue
+			}
+			stat, ok := byDomain[domain]
+			if !ok {
+				stat = &BlockedDomainStat{Domain: domain, Ecosystem: workflow.GetDomainEcosystem(domain)}
+				byDomain[domain] = stat
+			}
+			stat.Requests += requestStats.Blocked
+			if !seenInRun[domain] {
+				seenInRun[domain] = true
+				stat.Runs++
+			}
+		}
+	}
+
+	stats := make([]BlockedDomainStat, 0, len(byDomain))
+	for _, stat := range byDomain {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Requests != stats[j].Requests {
+			return stats[i].Requests > stats[j].Requests
+		}
+		return stats[i].Domain < stats[j].Domain
+	})
+	return stats
+}
+
+// suggestNetworkAllowed proposes the entries to add to network.allowed so that the blocked
+// domains are allowed. Ecosystem identifiers are preferred over raw domains; domains that are
+// explicitly blocked or already covered by the allow-list are skipped.
+func suggestNetworkAllowed(stats []BlockedDomainStat, currentAllowed, blocked []string) []NetworkSuggestion {
+	allowedDomains := workflow.GetAllowedDomains(&workflow.NetworkPermissions{Allowed: currentAllowed})
+
+	byEntry := make(map[string]*NetworkSuggestion)
+	for _, stat := range stats {
+		if domainMatchesAny(stat.Domain, blocked) {
+			networkSuggestLog.Printf("Skipping explicitly blocked domain: %s", stat.Domain)
+			continue
+		}
+
+		entry, isEcosystem := stat.Domain, false
+		if stat.Ecosystem != "" {
+			entry, isEcosystem = stat.Ecosystem, true
+		}
+		if sliceutil.Contains(currentAllowed, entry) || (!isEcosystem && domainMatchesAny(stat.Domain, allowedDomains)) {
+			continue
+		}
+
+		suggestion, ok := byEntry[entry]
+		if !ok {
+			suggestion = &NetworkSuggestion{Entry: entry, Ecosystem: isEcosystem}
+			byEntry[entry] = suggestion
+		}
+		suggestion.Domains = append(suggestion.Domains, stat.Domain)
+	}
+
+	suggestions := make([]NetworkSuggestion, 0, len(byEntry))
+	for _, suggestion := range byEntry {
+		sort.Strings(suggestion.Domains)
+		suggestions = append(suggestions, *suggestion)
+	}
+	// Ecosystems first, then domains, alphabetically within each group
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Ecosystem != suggestions[j].Ecosystem {
+			return suggestions[i].Ecosystem
+		}
+		return suggestions[i].Entry < suggestions[j].Entry
+	})
+	return suggestions
+}
+
+// domainMatchesAny reports whether a domain matches one of the given domains or wildcard patterns
+func domainMatchesAny(domain string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if domain == pattern {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok && (domain == suffix || strings.HasSuffix(domain, "."+suffix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// proposedAllowedList returns the allow-list after adding the suggestions
+func proposedAllowedList(currentAllowed []string, suggestions []NetworkSuggestion) []string {
+	proposed := append([]string{}, currentAllowed...)
+	for _, suggestion := range suggestions {
+		proposed = append(proposed, suggestion.Entry)
+	}
+	return proposed
+}
+
+// applyNetworkSuggestions writes the suggested entries to the workflow frontmatter and recompiles it
+func applyNetworkSuggestions(workflowPath, content string, suggestions []NetworkSuggestion, verbose bool) error {
+	entries := make([]string, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		entries = append(entries, suggestion.Entry)
+	}
+
+	updated, err := AddNetworkAllowedEntries(content, entries)
+	if err != nil {
+		return fmt.Errorf("failed to update network.allowed: %w", err)
+	}
+	if err := os.WriteFile(workflowPath, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write workflow file: %w", err)
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Added %s to network.allowed in %s", strings.Join(entries, ", "), console.ToRelativePath(workflowPath))))
+
+	networkSuggestLog.Print("Compiling workflow after updating network.allowed")
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose))
+	if err := compiler.CompileWorkflow(workflowPath); err != nil {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Workflow compilation failed: %v", err)))
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("You can fix the issues and run '%s compile' manually", string(constants.CLIExtensionPrefix))))
+		return nil
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Workflow compiled successfully"))
+	return nil
+}
+
+// displayNetworkSuggestReport renders the report to stderr
+func displayNetworkSuggestReport(report NetworkSuggestReport) {
+	if report.RunsAnalyzed == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("No firewall logs found in the analyzed runs"))
+		return
+	}
+	if len(report.BlockedDomains) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("No blocked requests found in %d runs", report.RunsAnalyzed)))
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatSectionHeader("Blocked Domains"))
+	fmt.Fprint(os.Stderr, console.RenderStruct(report.BlockedDomains))
+
+	if len(report.Suggestions) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("All blocked domains are explicitly blocked or already allowed; no changes proposed"))
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatSectionHeader("Proposed network.allowed"))
+	for _, suggestion := range report.Suggestions {
+		fmt.Fprintln(os.Stderr, console.FormatListItem(fmt.Sprintf("%s (%s)", suggestion.Entry, strings.Join(suggestion.Domains, ", "))))
+	}
+
+	lines := formatNetworkAllowedBlock("  ", report.ProposedAllowed)
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Frontmatter patch:"))
+	for _, line := range lines {
+		fmt.Fprintln(os.Stderr, "  "+line)
+	}
+
+	if !report.Applied {
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Run again with --apply to update the workflow"))
+	}
+}
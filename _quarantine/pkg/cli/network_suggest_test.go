@@ -0,0 +1,63 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateBlockedDomains(t *testing.T) {
+	analyses := []*FirewallAnalysis{
+		{RequestsByDomain: map[string]DomainRequestStats{
+			"pypi.org:443":   {Blocked: 3},
+			"api.github.com": {Allowed: 10},
+			"-":              {Blocked: 1},
+		}},
+		{RequestsByDomain: map[string]DomainRequestStats{
+			"pypi.org":        {Blocked: 1},
+			"api.example.com": {Blocked: 2},
+		}},
+	}
+
+	stats := aggregateBlockedDomains(analyses)
+	assert.Equal(t, []BlockedDomainStat{
+		{Domain: "pypi.org", Ecosystem: "python", Requests: 4, Runs: 2},
+		{Domain: "api.example.com", Requests: 2, Runs: 1},
+	}, stats)
+}
+
+func TestSuggestNetworkAllowed(t *testing.T) {
+	stats := []BlockedDomainStat{
+		{Domain: "pypi.org", Ecosystem: "python", Requests: 4},
+		{Domain: "files.pythonhosted.org", Ecosystem: "python", Requests: 2},
+		{Domain: "api.example.com", Requests: 2},
+		{Domain: "tracker.example.net", Requests: 1},
+		{Domain: "cdn.internal.dev", Requests: 1},
+	}
+
+	suggestions := suggestNetworkAllowed(stats, []string{"defaults", "*.internal.dev"}, []string{"tracker.example.net"})
+	assert.Equal(t, []NetworkSuggestion{
+		{Entry: "python", Ecosystem: true, Domains: []string{"files.pythonhosted.org", "pypi.org"}},
+		{Entry: "api.example.com", Domains: []string{"api.example.com"}},
+	}, suggestions, "ecosystems should be preferred and blocked or already allowed domains skipped")
+
+	t.Run("ecosystem already allowed", func(t *testing.T) {
+		suggestions := suggestNetworkAllowed(stats[:2], []string{"python"}, nil)
+		assert.Empty(t, suggestions)
+	})
+}
+
+func TestReadNetworkConfig(t *testing.T) {
+	allowed, blocked, err := readNetworkConfig("---\non: push\n---\n# Test\n")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"defaults"}, allowed)
+	assert.Empty(t, blocked)
+
+	allowed, blocked, err = readNetworkConfig("---\non: push\nnetwork:\n  allowed: [defaults, node]\n  blocked:\n    - evil.com\n---\n# Test\n")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"defaults", "node"}, allowed)
+	assert.Equal(t, []string{"evil.com"}, blocked)
+}
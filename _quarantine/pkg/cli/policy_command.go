@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var policyCommandLog = logger.New("cli:policy")
+
+// policySARIFToolName is the tool name reported in SARIF logs produced by policy check
+const policySARIFToolName = "gh-aw policy"
+
+// PolicyCheckConfig holds configuration for the policy check command
+type PolicyCheckConfig struct {
+	WorkflowIDs []string
+	Policy      string // Local path or owner/repo/path@ref (defaults to .github/aw/policy.yml)
+	WorkflowDir string
+	SARIFOutput string // SARIF output file ("-" for stdout)
+	Verbose     bool
+}
+
+// NewPolicyCommand creates the main policy command with subcommands
+func NewPolicyCommand() *cobra.Command {
+	policyCommandLog.Print("Creating policy command with subcommands")
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Check agentic workflows against an organization policy",
+		Long: `Organization policy tooling for agentic workflows.
+
+A policy file declares the engines, models, permissions, MCP servers, containers,
+network ecosystems, threat detection, safe output limits and roles that workflows
+may use. The same policy is enforced at compile time with 'compile --policy'.
+
+Available subcommands:
+  • check - Evaluate workflows against a policy and report violations
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` policy check                                  # Use .github/aw/policy.yml
+  ` + string(constants.CLIExtensionPrefix) + ` policy check --policy my-org/policies/aw.yml@main --sarif policy.sarif`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(NewPolicyCheckSubcommand())
+
+	return cmd
+}
+
+// NewPolicyCheckSubcommand creates the policy check subcommand
+func NewPolicyCheckSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check [workflow]...",
+		Short: "Evaluate workflows against an organization policy and report violations",
+		Long: `Evaluate workflows against an organization policy and report every violation with
+its location in the workflow markdown file.
+
+The policy is read from --policy, which accepts a local path or a remote file
+(owner/repo/path@ref, cached like remote imports). Without --policy, the
+repository policy at ` + workflow.DefaultPolicyFile + ` is used.
+
+Policy file format (every section is optional):
+
+  engines:
+    allowed: [copilot, claude]           # Allowed engine IDs
+    models: ["gpt-5*"]                   # Allowed models, when a model is set
+  permissions:
+    max:
+      all: read                          # Maximum level of scopes that are not listed
+      issues: write
+  mcp-servers:
+    allowed: [github, "internal-*"]      # Allowed MCP server names
+  containers:
+    allowed: ["ghcr.io/my-org/*"]        # Allowed job, service and MCP server images
+  network:
+    forbidden-ecosystems: [python]       # Ecosystems that may not appear in network.allowed
+  threat-detection:
+    required: true                       # Workflows with safe outputs must keep threat detection
+  safe-outputs:
+    max:
+      create-issue: 3                    # Upper bound of each safe output's max
+  roles:
+    required: true                       # roles: must be declared explicitly
+    allowed: [admin, maintainer]
+
+Patterns use '*' as a wildcard. Use --sarif to write the violations as a SARIF 2.1.0
+log, e.g. for upload to code scanning.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` policy check                                # Check all workflows
+  ` + string(constants.CLIExtensionPrefix) + ` policy check ci-doctor                      # Check a single workflow
+  ` + string(constants.CLIExtensionPrefix) + ` policy check --policy ./org-policy.yml      # Use a local policy file
+  ` + string(constants.CLIExtensionPrefix) + ` policy check --sarif results/policy.sarif   # Write a SARIF log`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy, _ := cmd.Flags().GetString("policy")
+			dir, _ := cmd.Flags().GetString("dir")
+			sarifOutput, _ := cmd.Flags().GetString("sarif")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+
+			return RunPolicyCheck(PolicyCheckConfig{
+				WorkflowIDs: args,
+				Policy:      policy,
+				WorkflowDir: dir,
+				SARIFOutput: sarifOutput,
+				Verbose:     verbose,
+			})
+		},
+	}
+
+	cmd.Flags().String("policy", "", "Policy file: local path or owner/repo/path@ref (default: "+workflow.DefaultPolicyFile+")")
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	cmd.Flags().String("sarif", "", "Write violations as a SARIF 2.1.0 log to this file ('-' for stdout)")
+
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// loadPolicy loads a policy from a path or workflowspec. When spec is empty, the repository
+// policy file is used if it exists; nil is returned when there is no policy.
+func loadPolicy(spec string) (*workflow.Policy, error) {
+	gitRoot, err := findGitRoot()
+	if err != nil {
+		gitRoot = "."
+	}
+	if spec == "" {
+		defaultPath := filepath.Join(gitRoot, workflow.DefaultPolicyFile)
+		if _, err := os.Stat(defaultPath); err != nil {
+			policyCommandLog.Printf("No policy given and %s does not exist", defaultPath)
+			return nil, nil
+		}
+		spec = defaultPath
+	}
+	return workflow.LoadPolicy(spec, parser.NewImportCache(gitRoot))
+}
+
+// RunPolicyCheck evaluates workflows against a policy
+func RunPolicyCheck(config PolicyCheckConfig) error {
+	policyCommandLog.Printf("Running policy check: workflows=%d, policy=%s, sarif=%s", len(config.WorkflowIDs), config.Policy, config.SARIFOutput)
+
+	policy, err := loadPolicy(config.Policy)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return fmt.Errorf("no policy found: pass --policy or create %s", workflow.DefaultPolicyFile)
+	}
+
+	var files []string
+	if len(config.WorkflowIDs) == 0 {
+		files, err = getMarkdownWorkflowFiles(config.WorkflowDir)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, id := range config.WorkflowIDs {
+			file, err := resolveWorkflowFileInDir(id, config.Verbose, config.WorkflowDir)
+			if err != nil {
+				return err
+			}
+			files = append(files, file)
+		}
+	}
+
+	gitRoot, _ := findGitRoot()
+	compiler := workflow.NewCompiler(workflow.WithVerbose(config.Verbose))
+
+	var findings []sarifFinding
+	var failedFiles []string
+	violatingWorkflows := 0
+	for _, file := range files {
+		fileFindings, err := checkWorkflowPolicy(compiler, policy, file, gitRoot)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s: %v", file, err)))
+			failedFiles = append(failedFiles, file)
+			continue
+		}
+		if len(fileFindings) > 0 {
+			violatingWorkflows++
+		}
+		for _, finding := range fileFindings {
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s:%d:%d: %s: %s", finding.File, finding.Line, finding.Column, finding.RuleID, finding.Message)))
+		}
+		findings = append(findings, fileFindings...)
+	}
+
+	if config.SARIFOutput != "" {
+		if err := writeSARIFFile(config.SARIFOutput, policySARIFToolName, findings); err != nil {
+			return err
+		}
+		if config.SARIFOutput != "-" {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("SARIF log written to "+config.SARIFOutput))
+		}
+	}
+
+	if len(failedFiles) > 0 {
+		return fmt.Errorf("failed to parse %d workflow(s)", len(failedFiles))
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("found %d policy violation(s) in %d of %d workflow(s)", len(findings), violatingWorkflows, len(files))
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%d workflow(s) comply with policy %s", len(files), policy.Source)))
+	return nil
+}
+
+// checkWorkflowPolicy parses a workflow and returns its policy violations located in the markdown file
+func checkWorkflowPolicy(compiler *workflow.Compiler, policy *workflow.Policy, file, gitRoot string) ([]sarifFinding, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow file: %w", err)
+	}
+	workflowData, err := compiler.ParseWorkflowFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	displayPath := file
+	if gitRoot != "" {
+		if absPath, err := filepath.Abs(file); err == nil {
+			if rel, err := filepath.Rel(gitRoot, absPath); err == nil && !strings.HasPrefix(rel, "..") {
+				displayPath = rel
+			}
+		}
+	}
+
+	violations := policy.Evaluate(workflowData)
+	findings := make([]sarifFinding, 0, len(violations))
+	for _, violation := range violations {
+		line, column := locateFrontmatterPath(string(content), violation.Path)
+		findings = append(findings, sarifFinding{
+			RuleID:          violation.Rule,
+			RuleDescription: "Organization policy rule " + violation.Rule,
+			Level:           "error",
+			Message:         violation.Message,
+			File:            displayPath,
+			Line:            line,
+			Column:          column,
+		})
+	}
+	return findings, nil
+}
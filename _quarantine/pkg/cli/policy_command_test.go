@@ -0,0 +1,93 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocateFrontmatterPath(t *testing.T) {
+	content := "---\non: issues\npermissions:\n  contents: read\n  issues: write\nengine: claude\n---\n# Test\n"
+
+	tests := []struct {
+		path       string
+		wantLine   int
+		wantColumn int
+	}{
+		{path: "/permissions/issues", wantLine: 5, wantColumn: 10},
+		{path: "/engine/model", wantLine: 6, wantColumn: 8},
+		{path: "/roles", wantLine: 1, wantColumn: 1},
+		{path: "", wantLine: 1, wantColumn: 1},
+	}
+	for _, tt := range tests {
+		line, column := locateFrontmatterPath(content, tt.path)
+		assert.Equal(t, tt.wantLine, line, tt.path)
+		assert.Equal(t, tt.wantColumn, column, tt.path)
+	}
+}
+
+func TestWriteSARIFFile(t *testing.T) {
+	path := filepath.Join(testutil.TempDir(t, "test-*"), "results", "policy.sarif")
+	findings := []sarifFinding{
+		{RuleID: "roles.required", Message: "roles: must be declared explicitly", File: ".github/workflows/b.md", Line: 1, Column: 1},
+		{RuleID: "engines.allowed", RuleDescription: "Allowed engines", Message: "engine 'codex' is not allowed", File: ".github/workflows/a.md", Line: 3, Column: 9},
+		{RuleID: "engines.allowed", Level: "warning", Message: "no location"},
+	}
+	require.NoError(t, writeSARIFFile(path, "gh-aw policy", findings))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(data, &log))
+
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "gh-aw policy", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, "engines.allowed", run.Tool.Driver.Rules[0].ID, "rules are sorted")
+	assert.Equal(t, "Allowed engines", run.Tool.Driver.Rules[0].ShortDescription.Text)
+	assert.Equal(t, "roles.required", run.Tool.Driver.Rules[1].ShortDescription.Text, "the rule ID is the fallback description")
+
+	require.Len(t, run.Results, 3)
+	assert.Equal(t, "error", run.Results[0].Level, "the level defaults to error")
+	location := run.Results[1].Locations[0].PhysicalLocation
+	assert.Equal(t, ".github/workflows/a.md", location.ArtifactLocation.URI)
+	assert.Equal(t, &sarifRegion{StartLine: 3, StartColumn: 9}, location.Region)
+	assert.Equal(t, "warning", run.Results[2].Level)
+	assert.Empty(t, run.Results[2].Locations)
+}
+
+func TestRunPolicyCheck(t *testing.T) {
+	dir := testutil.TempDir(t, "test-*")
+	workflowsDir := filepath.Join(dir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "test.md"), []byte("---\non: issues\nengine: claude\npermissions:\n  contents: read\n---\n# Test\n"), 0644))
+
+	policyPath := filepath.Join(dir, "policy.yml")
+	sarifPath := filepath.Join(dir, "policy.sarif")
+
+	require.NoError(t, os.WriteFile(policyPath, []byte("engines:\n  allowed: [claude]\n"), 0644))
+	require.NoError(t, RunPolicyCheck(PolicyCheckConfig{Policy: policyPath, WorkflowDir: workflowsDir, SARIFOutput: sarifPath}))
+
+	require.NoError(t, os.WriteFile(policyPath, []byte("engines:\n  allowed: [copilot]\n"), 0644))
+	err := RunPolicyCheck(PolicyCheckConfig{Policy: policyPath, WorkflowDir: workflowsDir, SARIFOutput: sarifPath})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "found 1 policy violation(s) in 1 of 1 workflow(s)")
+
+	data, err := os.ReadFile(sarifPath)
+	require.NoError(t, err)
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(data, &log))
+	require.Len(t, log.Runs[0].Results, 1)
+	result := log.Runs[0].Results[0]
+	assert.Equal(t, "engines.allowed", result.RuleID)
+	assert.Equal(t, 3, result.Locations[0].PhysicalLocation.Region.StartLine)
+}
@@ -368,6 +368,7 @@ func parseAndDisplayPoutineOutput(stdout, targetFile string, verbose bool) (int,
 		}
 
 		fmt.Fprint(os.Stderr, console.FormatError(compilerErr))
+		recordPoutineSARIFFinding(finding.Meta.Path, lineNum, finding, title, severity)
 	}
 
 	return totalWarnings, nil
@@ -511,8 +512,22 @@ func parseAndDisplayPoutineOutputForDirectory(stdout string, verbose bool, gitRo
 			}
 
 			fmt.Fprint(os.Stderr, console.FormatError(compilerErr))
+			recordPoutineSARIFFinding(absPath, lineNum, finding, title, severity)
 		}
 	}
 
 	return totalWarnings, nil
 }
+
+// recordPoutineSARIFFinding records a poutine finding for compile --sarif
+func recordPoutineSARIFFinding(lockFile string, line int, finding poutineFinding, title, severity string) {
+	level := severity
+	if level != "error" && level != "note" {
+		level = "warning"
+	}
+	message := title
+	if finding.Meta.Details != "" {
+		message = fmt.Sprintf("%s - %s", message, finding.Meta.Details)
+	}
+	recordCompileSARIFLockFinding(lockFile, line, "poutine/"+finding.RuleID, title, level, message)
+}
@@ -0,0 +1,520 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var repoMemoryStoreLog = logger.New("cli:repo_memory_store")
+
+// memoryRunIDPattern extracts the workflow run ID from commits made by push_repo_memory.cjs
+// ("Update repo memory from workflow run 123456")
+var memoryRunIDPattern = regexp.MustCompile(`workflow run (\d+)`)
+
+// memoryFile is a file stored on a repo-memory branch
+type memoryFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// memoryCommit is a commit of a repo-memory branch
+type memoryCommit struct {
+	SHA     string    `json:"sha"`
+	RunID   int64     `json:"run_id,omitempty"` // 0 when the commit was not made by a workflow run
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+	Changes []string  `json:"changes,omitempty"` // git name-status lines, e.g. "M\tnotes.md"
+}
+
+// memoryStore reads and updates a repo-memory branch with git. The branch is read from the
+// local repository (refreshed from origin when possible) or from a bare clone of a remote
+// repository made with gh, so both use the same git plumbing.
+type memoryStore struct {
+	gitDir  string // git directory of the repository holding the branch
+	branch  string // branch name, e.g. memory/default
+	ref     string // resolved ref of the branch, "" when it does not exist yet
+	remote  string // remote that updates are pushed to, "" to update the local branch
+	tempDir string // temporary clone removed by Close
+}
+
+// openMemoryStore opens the branch of a repo-memory entry. repoSlug selects a repository other
+// than the current one (owner/repo); it is cloned into a temporary bare repository.
+func openMemoryStore(entry workflow.RepoMemoryEntry, repoSlug string, verbose bool) (*memoryStore, error) {
+	branch := entry.BranchName
+	if branch == "" {
+		branch = "memory/" + entry.ID
+	}
+	repoMemoryStoreLog.Printf("Opening memory store: id=%s, branch=%s, repo=%s", entry.ID, branch, repoSlug)
+
+	if repoSlug != "" {
+		return openRemoteMemoryStore(repoSlug, branch, verbose)
+	}
+
+	output, err := exec.Command("git", "rev-parse", "--absolute-git-dir").Output()
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+	store := &memoryStore{gitDir: strings.TrimSpace(string(output)), branch: branch}
+
+	if _, err := store.git("remote", "get-url", "origin"); err == nil {
+		store.remote = "origin"
+		// Refresh the remote-tracking branch; offline reads use the last fetched state
+		if _, err := store.git("fetch", "--quiet", "origin", "+refs/heads/"+branch+":refs/remotes/origin/"+branch); err != nil {
+			repoMemoryStoreLog.Printf("Failed to fetch %s: %v", branch, err)
+		}
+	}
+	for _, ref := range []string{"refs/remotes/origin/" + branch, "refs/heads/" + branch} {
+		if _, err := store.git("rev-parse", "--verify", "--quiet", ref+"^{commit}"); err == nil {
+			store.ref = ref
+			break
+		}
+	}
+	if store.ref == "refs/heads/"+branch {
+		// The branch only exists locally, keep updates local as well
+		store.remote = ""
+	}
+	return store, nil
+}
+
+// openRemoteMemoryStore clones the memory branch of another repository into a bare repository
+func openRemoteMemoryStore(repoSlug, branch string, verbose bool) (*memoryStore, error) {
+	tempDir, err := os.MkdirTemp("", "gh-aw-memory-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	store := &memoryStore{gitDir: tempDir, branch: branch, remote: "origin", tempDir: tempDir}
+
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Cloning %s from %s", branch, repoSlug)))
+	}
+	cmd := workflow.ExecGH("repo", "clone", repoSlug, tempDir, "--", "--bare", "--quiet", "--single-branch", "--branch", branch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		text := string(output)
+		if !strings.Contains(text, "not found") && !strings.Contains(text, "Could not find remote branch") {
+			store.Close()
+			return nil, fmt.Errorf("failed to clone %s from %s: %w\n%s", branch, repoSlug, err, strings.TrimSpace(text))
+		}
+		// The branch does not exist yet: start from an empty bare repository that can push it
+		repoMemoryStoreLog.Printf("Branch %s does not exist in %s", branch, repoSlug)
+		if output, err := exec.Command("git", "init", "--bare", "--quiet", tempDir).CombinedOutput(); err != nil {
+			store.Close()
+			return nil, fmt.Errorf("failed to initialize repository: %w\n%s", err, output)
+		}
+		if _, err := store.git("remote", "add", "origin", "https://github.com/"+repoSlug+".git"); err != nil {
+			store.Close()
+			return nil, err
+		}
+		return store, nil
+	}
+	store.ref = "refs/heads/" + branch
+	return store, nil
+}
+
+// Close removes the temporary clone of a remote repository
+func (s *memoryStore) Close() {
+	if s.tempDir != "" {
+		_ = os.RemoveAll(s.tempDir)
+	}
+}
+
+// exists reports whether the memory branch exists
+func (s *memoryStore) exists() bool {
+	return s.ref != ""
+}
+
+// git runs a git command against the store repository and returns its standard output
+func (s *memoryStore) git(args ...string) (string, error) {
+	return s.gitWithEnv(nil, args...)
+}
+
+// gitWithEnv runs a git command with additional environment variables
+func (s *memoryStore) gitWithEnv(env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"--git-dir=" + s.gitDir}, args...)...)
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// resolveRevision resolves a workflow run ID or a git revision to a commit of the branch.
+// An empty revision is the latest commit.
+func (s *memoryStore) resolveRevision(revision string) (string, error) {
+	if !s.exists() {
+		return "", fmt.Errorf("memory branch %s does not exist", s.branch)
+	}
+	if revision == "" {
+		revision = s.ref
+	}
+	runID, runIDErr := strconv.ParseInt(revision, 10, 64)
+	if runIDErr == nil {
+		commits, err := s.log("")
+		if err != nil {
+			return "", err
+		}
+		for _, commit := range commits {
+			if commit.RunID == runID {
+				return commit.SHA, nil
+			}
+		}
+	}
+	output, err := s.git("rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	if err != nil {
+		if runIDErr == nil {
+			return "", fmt.Errorf("no commit from workflow run %d on %s", runID, s.branch)
+		}
+		return "", fmt.Errorf("unknown revision '%s' on %s", revision, s.branch)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// files lists the files of a commit
+func (s *memoryStore) files(commit string) ([]memoryFile, error) {
+	output, err := s.git("ls-tree", "-r", "-l", "-z", commit)
+	if err != nil {
+		return nil, err
+	}
+	var files []memoryFile
+	for _, entry := range strings.Split(output, "\x00") {
+		// <mode> <type> <object> <size>\t<path>
+		meta, filePath, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 4 || fields[1] != "blob" {
+			continue
+		}
+		size, _ := strconv.ParseInt(fields[3], 10, 64)
+		files = append(files, memoryFile{Path: filePath, Size: size})
+	}
+	return files, nil
+}
+
+// readFile returns the content of a file at a commit
+func (s *memoryStore) readFile(commit, filePath string) ([]byte, error) {
+	output, err := s.git("show", commit+":"+filePath)
+	if err != nil {
+		return nil, fmt.Errorf("file '%s' not found in %s at %s", filePath, s.branch, shortSHA(commit))
+	}
+	return []byte(output), nil
+}
+
+// log returns the commits of the branch, newest first, optionally limited to a file
+func (s *memoryStore) log(filePath string) ([]memoryCommit, error) {
+	if !s.exists() {
+		return nil, nil
+	}
+	args := []string{"log", "--format=%x1e%H%x1f%ct%x1f%s", "--name-status", s.ref}
+	if filePath != "" {
+		args = append(args, "--", filePath)
+	}
+	output, err := s.git(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []memoryCommit
+	for _, record := range strings.Split(output, "\x1e") {
+		header, changes, _ := strings.Cut(strings.TrimSpace(record), "\n")
+		fields := strings.Split(header, "\x1f")
+		if len(fields) != 3 {
+			continue
+		}
+		timestamp, _ := strconv.ParseInt(fields[1], 10, 64)
+		commit := memoryCommit{SHA: fields[0], Date: time.Unix(timestamp, 0).UTC(), Subject: fields[2]}
+		if match := memoryRunIDPattern.FindStringSubmatch(commit.Subject); match != nil {
+			commit.RunID, _ = strconv.ParseInt(match[1], 10, 64)
+		}
+		for _, change := range strings.Split(changes, "\n") {
+			if change = strings.TrimSpace(change); change != "" {
+				commit.Changes = append(commit.Changes, change)
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// diff returns the unified diff of the memory between two commits, optionally limited to a file
+func (s *memoryStore) diff(from, to, filePath string) (string, error) {
+	args := []string{"diff", "--no-color", from, to}
+	if filePath != "" {
+		args = append(args, "--", filePath)
+	}
+	return s.git(args...)
+}
+
+// lastModified returns when each file present at the head of the branch was last changed
+func (s *memoryStore) lastModified() (map[string]time.Time, error) {
+	commits, err := s.log("")
+	if err != nil {
+		return nil, err
+	}
+	modified := make(map[string]time.Time)
+	for _, commit := range commits {
+		for _, change := range commit.Changes {
+			fields := strings.Split(change, "\t")
+			filePath := fields[len(fields)-1]
+			if _, seen := modified[filePath]; !seen {
+				modified[filePath] = commit.Date
+			}
+		}
+	}
+	return modified, nil
+}
+
+// export writes the files of a commit as a gzip-compressed tarball
+func (s *memoryStore) export(commit string, w io.Writer) error {
+	output, err := s.git("archive", "--format=tar", commit)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write([]byte(output)); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// removeFiles commits the removal of files from the head of the branch and returns the new commit
+func (s *memoryStore) removeFiles(paths []string, message string) (string, error) {
+	indexFile, cleanup, err := s.tempIndex()
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	env := append([]string{"GIT_INDEX_FILE=" + indexFile}, s.identityEnv()...)
+
+	if _, err := s.gitWithEnv(env, "read-tree", s.ref); err != nil {
+		return "", err
+	}
+	args := append([]string{"rm", "--cached", "--quiet", "--"}, paths...)
+	if _, err := s.gitWithEnv(env, args...); err != nil {
+		return "", err
+	}
+	return s.commitIndex(env, message)
+}
+
+// replaceFiles commits the files of a directory as the new content of the branch and returns
+// the new commit, or "" when the content is unchanged. The existing history is kept.
+func (s *memoryStore) replaceFiles(dir, message string) (string, error) {
+	indexFile, cleanup, err := s.tempIndex()
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	env := append([]string{"GIT_INDEX_FILE=" + indexFile}, s.identityEnv()...)
+
+	if _, err := s.gitWithEnv(env, "--work-tree="+dir, "add", "--all", "--force", "."); err != nil {
+		return "", err
+	}
+	return s.commitIndex(env, message)
+}
+
+// commitIndex writes the temporary index as a commit on top of the branch head.
+// It returns "" when the content is unchanged.
+func (s *memoryStore) commitIndex(env []string, message string) (string, error) {
+	output, err := s.gitWithEnv(env, "write-tree")
+	if err != nil {
+		return "", err
+	}
+	tree := strings.TrimSpace(output)
+	args := []string{"commit-tree", tree, "-m", message}
+	if s.exists() {
+		if head, err := s.git("rev-parse", s.ref+"^{tree}"); err == nil && strings.TrimSpace(head) == tree {
+			return "", nil
+		}
+		args = append(args, "-p", s.ref)
+	}
+	commit, err := s.gitWithEnv(env, args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(commit), nil
+}
+
+// update moves the branch to a new commit, pushing it when the branch lives on a remote
+func (s *memoryStore) update(commit string) error {
+	if s.remote != "" {
+		repoMemoryStoreLog.Printf("Pushing %s to %s/%s", shortSHA(commit), s.remote, s.branch)
+		if _, err := s.git("push", "--quiet", s.remote, commit+":refs/heads/"+s.branch); err != nil {
+			return err
+		}
+		if _, err := s.git("update-ref", "refs/remotes/"+s.remote+"/"+s.branch, commit); err != nil && s.tempDir == "" {
+			repoMemoryStoreLog.Printf("Failed to update remote-tracking branch: %v", err)
+		}
+		return nil
+	}
+	repoMemoryStoreLog.Printf("Updating local branch %s to %s", s.branch, shortSHA(commit))
+	_, err := s.git("update-ref", "refs/heads/"+s.branch, commit)
+	return err
+}
+
+// tempIndex returns the path of a temporary git index file
+func (s *memoryStore) tempIndex() (string, func(), error) {
+	dir, err := os.MkdirTemp("", "gh-aw-memory-index-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	return filepath.Join(dir, "index"), func() { _ = os.RemoveAll(dir) }, nil
+}
+
+// identityEnv returns a commit identity when git has none configured (e.g. in a fresh clone on CI)
+func (s *memoryStore) identityEnv() []string {
+	if _, err := s.git("config", "user.email"); err == nil {
+		return nil
+	}
+	return []string{
+		"GIT_AUTHOR_NAME=gh-aw", "GIT_AUTHOR_EMAIL=gh-aw@users.noreply.github.com",
+		"GIT_COMMITTER_NAME=gh-aw", "GIT_COMMITTER_EMAIL=gh-aw@users.noreply.github.com",
+	}
+}
+
+// extractMemoryTarball extracts a gzip-compressed tarball into dir, enforcing the file-glob,
+// max-file-size and max-file-count limits of the memory like push_repo_memory.cjs does
+func extractMemoryTarball(r io.Reader, dir string, entry workflow.RepoMemoryEntry) ([]memoryFile, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a gzip-compressed tarball: %w", err)
+	}
+	defer gz.Close()
+
+	var files []memoryFile
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tarball: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("refusing to import file outside the memory: %s", header.Name)
+		}
+		if err := checkMemoryFile(name, header.Size, entry); err != nil {
+			return nil, err
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return nil, err
+		}
+		// #nosec G110 -- the size of every entry is bounded by max-file-size above
+		_, copyErr := io.Copy(file, reader)
+		closeErr := file.Close()
+		if copyErr != nil {
+			return nil, copyErr
+		}
+		if closeErr != nil {
+			return nil, closeErr
+		}
+		files = append(files, memoryFile{Path: name, Size: header.Size})
+	}
+
+	if entry.MaxFileCount > 0 && len(files) > entry.MaxFileCount {
+		return nil, fmt.Errorf("tarball has %d files, more than the memory max-file-count of %d", len(files), entry.MaxFileCount)
+	}
+	return files, nil
+}
+
+// checkMemoryFile enforces the file-glob and max-file-size limits of a memory on a file
+func checkMemoryFile(name string, size int64, entry workflow.RepoMemoryEntry) error {
+	if !matchesMemoryFileGlob(name, entry.FileGlob) {
+		return fmt.Errorf("file '%s' does not match the memory file-glob %v", name, entry.FileGlob)
+	}
+	if entry.MaxFileSize > 0 && size > int64(entry.MaxFileSize) {
+		return fmt.Errorf("file '%s' exceeds the memory max-file-size (%d bytes > %d bytes)", name, size, entry.MaxFileSize)
+	}
+	return nil
+}
+
+// collectMemoryDirFiles lists the files of a directory, enforcing the limits of a memory on them
+func collectMemoryDirFiles(dir string, entry workflow.RepoMemoryEntry) ([]memoryFile, error) {
+	var files []memoryFile
+	err := filepath.WalkDir(dir, func(filePath string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if err := checkMemoryFile(name, info.Size(), entry); err != nil {
+			return err
+		}
+		files = append(files, memoryFile{Path: name, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if entry.MaxFileCount > 0 && len(files) > entry.MaxFileCount {
+		return nil, fmt.Errorf("%d files is more than the memory max-file-count of %d", len(files), entry.MaxFileCount)
+	}
+	return files, nil
+}
+
+// matchesMemoryFileGlob reports whether a file matches the file-glob patterns of a memory.
+// '*' matches within a path segment and '**' across segments; no patterns match every file.
+func matchesMemoryFileGlob(filePath string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		expr := regexp.QuoteMeta(pattern)
+		expr = strings.ReplaceAll(expr, `\*\*`, "\x00")
+		expr = strings.ReplaceAll(expr, `\*`, "[^/]*")
+		expr = strings.ReplaceAll(expr, "\x00", ".*")
+		if matched, _ := regexp.MatchString("^"+expr+"$", filePath); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
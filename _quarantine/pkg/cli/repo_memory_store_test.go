@@ -0,0 +1,193 @@
+//go:build !integration
+
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupMemoryRepo creates a repository with a memory/default branch written by two workflow runs
+// and changes the working directory to it
+func setupMemoryRepo(t *testing.T) string {
+	t.Helper()
+	dir := testutil.TempDir(t, "test-*")
+	run := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), env...)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	run(nil, "init", "--quiet")
+	run(nil, "config", "user.email", "test@example.com")
+	run(nil, "config", "user.name", "Test")
+	run(nil, "checkout", "--quiet", "--orphan", "memory/default")
+	write("notes.md", "first\n")
+	write("old.md", "stale\n")
+	run(nil, "add", "--all")
+	oldDate := []string{"GIT_AUTHOR_DATE=2024-01-01T00:00:00Z", "GIT_COMMITTER_DATE=2024-01-01T00:00:00Z"}
+	run(oldDate, "commit", "--quiet", "-m", "Update repo memory from workflow run 1111111")
+	write("notes.md", "first\nsecond\n")
+	write("data/metrics.json", `{"runs": 2}`)
+	run(nil, "add", "--all")
+	run(nil, "commit", "--quiet", "-m", "Update repo memory from workflow run 2222222")
+
+	t.Chdir(dir)
+	return dir
+}
+
+func TestMemoryStoreRead(t *testing.T) {
+	setupMemoryRepo(t)
+
+	store, err := openMemoryStore(workflow.RepoMemoryEntry{ID: "default"}, "", false)
+	require.NoError(t, err)
+	defer store.Close()
+	require.True(t, store.exists())
+	assert.Equal(t, "refs/heads/memory/default", store.ref)
+	assert.Empty(t, store.remote, "without origin the local branch is updated")
+
+	commits, err := store.log("")
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, int64(2222222), commits[0].RunID)
+	assert.ElementsMatch(t, []string{"A\tdata/metrics.json", "M\tnotes.md"}, commits[0].Changes)
+
+	oldCommits, err := store.log("old.md")
+	require.NoError(t, err)
+	require.Len(t, oldCommits, 1)
+	assert.Equal(t, int64(1111111), oldCommits[0].RunID)
+
+	first, err := store.resolveRevision("1111111")
+	require.NoError(t, err)
+	assert.Equal(t, commits[1].SHA, first)
+	_, err = store.resolveRevision("9999999")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no commit from workflow run 9999999")
+
+	files, err := store.files(first)
+	require.NoError(t, err)
+	assert.Equal(t, []memoryFile{{Path: "notes.md", Size: 6}, {Path: "old.md", Size: 6}}, files)
+
+	content, err := store.readFile(first, "notes.md")
+	require.NoError(t, err)
+	assert.Equal(t, "first\n", string(content))
+
+	diff, err := store.diff(first, commits[0].SHA, "notes.md")
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+second")
+
+	modified, err := store.lastModified()
+	require.NoError(t, err)
+	assert.Equal(t, 2024, modified["old.md"].Year())
+	assert.True(t, modified["notes.md"].After(modified["old.md"]))
+}
+
+func TestMemoryStoreUpdate(t *testing.T) {
+	setupMemoryRepo(t)
+	entry := workflow.RepoMemoryEntry{ID: "default"}
+
+	store, err := openMemoryStore(entry, "", false)
+	require.NoError(t, err)
+	commit, err := store.removeFiles([]string{"old.md"}, "Prune 1 file(s) from repo memory")
+	require.NoError(t, err)
+	require.NoError(t, store.update(commit))
+
+	store, err = openMemoryStore(entry, "", false)
+	require.NoError(t, err)
+	files, err := store.files(store.ref)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	// Export and import round trip
+	var tarball bytes.Buffer
+	require.NoError(t, store.export(store.ref, &tarball))
+	dir := testutil.TempDir(t, "import-*")
+	imported, err := extractMemoryTarball(&tarball, dir, entry)
+	require.NoError(t, err)
+	assert.Len(t, imported, 2)
+
+	commit, err = store.replaceFiles(dir, "Import repo memory")
+	require.NoError(t, err)
+	assert.Empty(t, commit, "importing identical content makes no commit")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte("seeded\n"), 0644))
+	commit, err = store.replaceFiles(dir, "Import repo memory")
+	require.NoError(t, err)
+	require.NoError(t, store.update(commit))
+	content, err := store.readFile("memory/default", "notes.md")
+	require.NoError(t, err)
+	assert.Equal(t, "seeded\n", string(content))
+
+	commits, err := store.log("")
+	require.NoError(t, err)
+	assert.Len(t, commits, 4, "history is kept")
+}
+
+func TestExtractMemoryTarball(t *testing.T) {
+	tarball := func(files map[string]string) *bytes.Buffer {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for name, content := range files {
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+			_, err := tw.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gz.Close())
+		return &buf
+	}
+	entry := workflow.RepoMemoryEntry{ID: "default", FileGlob: []string{"*.md", "data/**"}, MaxFileSize: 10, MaxFileCount: 2}
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{name: "valid", files: map[string]string{"./notes.md": "hi", "data/a/b.json": "{}"}},
+		{name: "glob", files: map[string]string{"notes.txt": "hi"}, wantErr: "does not match the memory file-glob"},
+		{name: "size", files: map[string]string{"notes.md": "01234567890"}, wantErr: "exceeds the memory max-file-size"},
+		{name: "count", files: map[string]string{"a.md": "", "b.md": "", "c.md": ""}, wantErr: "more than the memory max-file-count"},
+		{name: "traversal", files: map[string]string{"../escape.md": "x"}, wantErr: "outside the memory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := testutil.TempDir(t, "extract-*")
+			files, err := extractMemoryTarball(tarball(tt.files), dir, entry)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, files, len(tt.files))
+			assert.FileExists(t, filepath.Join(dir, "data", "a", "b.json"))
+		})
+	}
+}
+
+func TestMatchesMemoryFileGlob(t *testing.T) {
+	assert.True(t, matchesMemoryFileGlob("anything/at/all.bin", nil))
+	assert.True(t, matchesMemoryFileGlob("notes.md", []string{"*.md"}))
+	assert.False(t, matchesMemoryFileGlob("dir/notes.md", []string{"*.md"}), "* does not cross directories")
+	assert.True(t, matchesMemoryFileGlob("metrics/daily/a.json", []string{"metrics/**"}))
+	assert.False(t, matchesMemoryFileGlob("notesxmd", []string{"notes.md"}), "dots are literal")
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var sarifWriterLog = logger.New("cli:sarif")
+
+const (
+	sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion   = "2.1.0"
+	sarifToolURI   = "https://github.com/github/gh-aw"
+)
+
+// sarifFinding is a single finding reported in a SARIF log
+type sarifFinding struct {
+	RuleID          string
+	RuleDescription string
+	Level           string // error, warning or note
+	Message         string
+	File            string // Path of the file, relative to the repository root when possible
+	Line            int    // 1-based line, 0 when unknown
+	Column          int    // 1-based column, 0 when unknown
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// buildSARIFLog builds a SARIF 2.1.0 log with a single run for the given tool
+func buildSARIFLog(toolName string, findings []sarifFinding) sarifLog {
+	rules := make(map[string]string)
+	results := make([]sarifResult, 0, len(findings))
+	for _, finding := range findings {
+		if rules[finding.RuleID] == "" {
+			rules[finding.RuleID] = finding.RuleDescription
+		}
+		result := sarifResult{
+			RuleID:  finding.RuleID,
+			Level:   finding.Level,
+			Message: sarifMessage{Text: finding.Message},
+		}
+		if result.Level == "" {
+			result.Level = "error"
+		}
+		if finding.File != "" {
+			location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(finding.File)}}
+			if finding.Line > 0 {
+				location.Region = &sarifRegion{StartLine: finding.Line, StartColumn: finding.Column}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: location}}
+		}
+		results = append(results, result)
+	}
+
+	ruleIDs := make([]string, 0, len(rules))
+	for id := range rules {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+	sarifRules := make([]sarifRule, 0, len(ruleIDs))
+	for _, id := range ruleIDs {
+		description := rules[id]
+		if description == "" {
+			description = id
+		}
+		sarifRules = append(sarifRules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: description}})
+	}
+
+	return sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           toolName,
+				Version:        GetVersion(),
+				InformationURI: sarifToolURI,
+				Rules:          sarifRules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// writeSARIFFile writes findings as a SARIF log to a file, or to stdout when path is "-"
+func writeSARIFFile(path, toolName string, findings []sarifFinding) error {
+	data, err := json.MarshalIndent(buildSARIFLog(toolName, findings), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SARIF: %w", err)
+	}
+	data = append(data, '\n')
+
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory for SARIF file: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write SARIF file: %w", err)
+	}
+	sarifWriterLog.Printf("Wrote %d findings to %s", len(findings), path)
+	return nil
+}
+
+// locateFrontmatterPath returns the 1-based line and column in a markdown file of the value at a
+// JSON path (e.g. /permissions/contents) in its frontmatter. When the path is not present, the
+// closest parent that is present is used; when none is, the opening frontmatter line is returned.
+func locateFrontmatterPath(content, jsonPath string) (int, int) {
+	for path := jsonPath; path != ""; path = parentJSONPath(path) {
+		if line, column, ok := locateFrontmatterPathExact(content, path); ok {
+			return line, column
+		}
+	}
+	return 1, 1
+}
+
+// locateFrontmatterPathExact returns the 1-based line and column of the value at a JSON path
+// in the frontmatter of a markdown file, without falling back to parent paths
+func locateFrontmatterPathExact(content, jsonPath string) (int, int, bool) {
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil || result.FrontmatterStart == 0 {
+		return 0, 0, false
+	}
+	location := parser.LocateJSONPathInYAML(strings.Join(result.FrontmatterLines, "\n"), jsonPath)
+	if !location.Found {
+		return 0, 0, false
+	}
+	return result.FrontmatterStart + location.Line - 1, location.Column, true
+}
+
+// parentJSONPath returns the parent of a JSON path ("/a/b" -> "/a", "/a" -> "")
+func parentJSONPath(path string) string {
+	if idx := strings.LastIndex(path, "/"); idx >= 0 {
+		return path[:idx]
+	}
+	return ""
+}
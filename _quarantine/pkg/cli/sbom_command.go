@@ -0,0 +1,450 @@
+package cli
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var sbomCommandLog = logger.New("cli:sbom_command")
+
+// SBOM formats supported by the sbom command
+const (
+	SBOMFormatCycloneDX = "cyclonedx"
+	SBOMFormatSPDX      = "spdx"
+)
+
+// SBOMConfig holds configuration for the sbom command
+type SBOMConfig struct {
+	WorkflowID string
+	Format     string // cyclonedx (default) or spdx
+	Output     string // Output file (default: stdout)
+	Verbose    bool
+}
+
+// sbomComponent is a dependency of a compiled workflow listed in its SBOM
+type sbomComponent struct {
+	Kind    string // library, application (engine CLI), container or action
+	Name    string
+	Version string
+	PURL    string
+	SHA1    string // Commit SHA of a pinned action
+	Digest  string // Image digest (sha256:...)
+}
+
+// NewSBOMCommand creates the sbom command
+func NewSBOMCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sbom <workflow>",
+		Short: "Generate a CycloneDX or SPDX software bill of materials for a compiled workflow",
+		Long: `Generate a software bill of materials (SBOM) listing everything a compiled workflow
+runs: the engine CLI, the npm and Python packages installed by npx, uvx and pip, the
+container images of MCP servers and services, and the GitHub Actions of the lock file.
+
+Versions and digests come from the dependency lock (.github/aw/aw-deps.lock) written by
+'` + string(constants.CLIExtensionPrefix) + ` compile --lock-deps'. Dependencies missing from the lock are listed with the
+version the workflow requests, if it is exact.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` sbom ci-doctor                          # CycloneDX JSON on stdout
+  ` + string(constants.CLIExtensionPrefix) + ` sbom ci-doctor --format spdx            # SPDX JSON on stdout
+  ` + string(constants.CLIExtensionPrefix) + ` sbom ci-doctor -o ci-doctor.cdx.json    # Write to a file`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			output, _ := cmd.Flags().GetString("output")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return RunSBOM(SBOMConfig{
+				WorkflowID: args[0],
+				Format:     format,
+				Output:     output,
+				Verbose:    verbose,
+			})
+		},
+	}
+
+	cmd.Flags().StringP("format", "f", SBOMFormatCycloneDX, "SBOM format: cyclonedx or spdx")
+	cmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
+
+// RunSBOM writes the SBOM of a compiled workflow
+func RunSBOM(config SBOMConfig) error {
+	sbomCommandLog.Printf("Generating SBOM: workflow=%s, format=%s", config.WorkflowID, config.Format)
+
+	format := strings.ToLower(config.Format)
+	if format == "" {
+		format = SBOMFormatCycloneDX
+	}
+	if format != SBOMFormatCycloneDX && format != SBOMFormatSPDX {
+		return fmt.Errorf("unsupported SBOM format '%s', use '%s' or '%s'", config.Format, SBOMFormatCycloneDX, SBOMFormatSPDX)
+	}
+
+	workflowFile, err := resolveWorkflowFile(config.WorkflowID, config.Verbose)
+	if err != nil {
+		return err
+	}
+	lockFile := stringutil.MarkdownToLockFile(workflowFile)
+	lockContent, err := os.ReadFile(lockFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("workflow '%s' has not been compiled, run '%s compile %s' first", config.WorkflowID, constants.CLIExtensionPrefix, config.WorkflowID)
+		}
+		return fmt.Errorf("failed to read %s: %w", lockFile, err)
+	}
+
+	compiler := workflow.NewCompiler(workflow.WithVerbose(config.Verbose))
+	workflowData, err := compiler.ParseWorkflowFile(workflowFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse workflow '%s': %w", config.WorkflowID, err)
+	}
+
+	components := sbomComponentsFromDependencies(compiler.LockedWorkflowDependencies(workflowData))
+	components = append(components, sbomComponentsFromLockFile(string(lockContent))...)
+	sortSBOMComponents(components)
+
+	name := workflowData.WorkflowID
+	now := time.Now().UTC()
+	var document any
+	if format == SBOMFormatSPDX {
+		document = buildSPDXDocument(name, components, GetVersion(), now)
+	} else {
+		document = buildCycloneDXDocument(name, components, GetVersion(), now)
+	}
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SBOM: %w", err)
+	}
+	data = append(data, '\n')
+
+	if config.Output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(config.Output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", config.Output, err)
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Wrote %s SBOM with %d components to %s", format, len(components), config.Output)))
+	return nil
+}
+
+// sbomComponentsFromDependencies converts the locked runtime dependencies of a workflow
+// into SBOM components
+func sbomComponentsFromDependencies(deps []workflow.LockedDependency) []sbomComponent {
+	components := make([]sbomComponent, 0, len(deps))
+	for _, dep := range deps {
+		component := sbomComponent{Kind: "library", Name: dep.Name, Version: dep.Version}
+		switch dep.Ecosystem {
+		case workflow.DepsEcosystemNpm:
+			if dep.Engine != "" {
+				component.Kind = "application"
+			}
+			component.PURL = packageURL("npm", dep.Name, dep.Version, nil)
+		case workflow.DepsEcosystemPyPI:
+			component.PURL = packageURL("pypi", dep.Name, dep.Version, nil)
+		case workflow.DepsEcosystemDocker:
+			component.Kind = "container"
+			component.Digest = dep.Digest
+			component.PURL = imagePackageURL(dep.Name, dep.Version, dep.Digest)
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// actionUsesPattern matches the actions referenced by a lock file, with the version
+// comment added by action pinning (e.g. "uses: actions/checkout@<sha> # v6")
+var actionUsesPattern = regexp.MustCompile(`(?m)^\s*(?:-\s+)?uses:\s+([^\s@#]+)@([^\s#]+)(?:\s+#\s*(\S+))?`)
+
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// sbomComponentsFromLockFile lists the GitHub Actions used by a compiled workflow
+func sbomComponentsFromLockFile(lockContent string) []sbomComponent {
+	seen := make(map[string]bool)
+	var components []sbomComponent
+	for _, match := range actionUsesPattern.FindAllStringSubmatch(lockContent, -1) {
+		action, ref, comment := match[1], match[2], match[3]
+		if strings.HasPrefix(action, "./") || strings.HasPrefix(action, "docker://") || seen[action+"@"+ref] {
+			continue
+		}
+		seen[action+"@"+ref] = true
+
+		component := sbomComponent{Kind: "action", Name: action, Version: ref}
+		if commitSHAPattern.MatchString(ref) {
+			component.SHA1 = ref
+			if comment != "" {
+				component.Version = comment
+			}
+		}
+		// The package URL names the repository; actions in subdirectories keep their path
+		repository, subpath := action, ""
+		if parts := strings.SplitN(action, "/", 3); len(parts) == 3 {
+			repository, subpath = parts[0]+"/"+parts[1], parts[2]
+		}
+		component.PURL = packageURL("github", repository, ref, nil)
+		if subpath != "" {
+			component.PURL += "#" + subpath
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// sortSBOMComponents sorts components by kind and name for a stable output
+func sortSBOMComponents(components []sbomComponent) {
+	kindOrder := map[string]int{"application": 0, "library": 1, "container": 2, "action": 3}
+	sort.SliceStable(components, func(i, j int) bool {
+		if kindOrder[components[i].Kind] != kindOrder[components[j].Kind] {
+			return kindOrder[components[i].Kind] < kindOrder[components[j].Kind]
+		}
+		if components[i].Name != components[j].Name {
+			return components[i].Name < components[j].Name
+		}
+		return components[i].Version < components[j].Version
+	})
+}
+
+// packageURL builds a package URL (purl) of the given type. Scoped npm names keep
+// their scope as the purl namespace.
+func packageURL(purlType, name, version string, qualifiers url.Values) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+	}
+	purl := "pkg:" + purlType + "/" + strings.Join(segments, "/")
+	if version != "" {
+		purl += "@" + strings.ReplaceAll(url.PathEscape(version), ":", "%3A")
+	}
+	if len(qualifiers) > 0 {
+		purl += "?" + qualifiers.Encode()
+	}
+	return purl
+}
+
+// imagePackageURL builds the package URL of a container image: an OCI purl when the
+// digest is known, a docker purl naming the tag otherwise
+func imagePackageURL(name, tag, digest string) string {
+	if digest != "" {
+		qualifiers := url.Values{"repository_url": []string{name}}
+		if tag != "" {
+			qualifiers.Set("tag", tag)
+		}
+		return packageURL("oci", name[strings.LastIndex(name, "/")+1:], digest, qualifiers)
+	}
+	return packageURL("docker", name, tag, nil)
+}
+
+// cycloneDXDocument is a CycloneDX 1.5 JSON document
+type cycloneDXDocument struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components"`
+	Dependencies []cycloneDXDependsOn `json:"dependencies"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     cycloneDXTools     `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type    string          `json:"type"`
+	BOMRef  string          `json:"bom-ref,omitempty"`
+	Name    string          `json:"name"`
+	Version string          `json:"version,omitempty"`
+	PURL    string          `json:"purl,omitempty"`
+	Hashes  []cycloneDXHash `json:"hashes,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXDependsOn struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// buildCycloneDXDocument builds the CycloneDX SBOM of a workflow
+func buildCycloneDXDocument(workflowName string, components []sbomComponent, toolVersion string, timestamp time.Time) cycloneDXDocument {
+	workflowRef := "workflow:" + workflowName
+	doc := cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newSBOMUUID(),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: timestamp.Format(time.RFC3339),
+			Tools: cycloneDXTools{Components: []cycloneDXComponent{
+				{Type: "application", Name: "gh-aw", Version: toolVersion},
+			}},
+			Component: cycloneDXComponent{Type: "application", BOMRef: workflowRef, Name: workflowName},
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	dependsOn := []string{}
+	for _, component := range components {
+		componentType := component.Kind
+		if componentType == "action" {
+			componentType = "library"
+		}
+		cdx := cycloneDXComponent{
+			Type:    componentType,
+			BOMRef:  component.PURL,
+			Name:    component.Name,
+			Version: component.Version,
+			PURL:    component.PURL,
+		}
+		if component.SHA1 != "" {
+			cdx.Hashes = append(cdx.Hashes, cycloneDXHash{Alg: "SHA-1", Content: component.SHA1})
+		}
+		if digest, ok := strings.CutPrefix(component.Digest, "sha256:"); ok {
+			cdx.Hashes = append(cdx.Hashes, cycloneDXHash{Alg: "SHA-256", Content: digest})
+		}
+		doc.Components = append(doc.Components, cdx)
+		dependsOn = append(dependsOn, component.PURL)
+	}
+	doc.Dependencies = []cycloneDXDependsOn{{Ref: workflowRef, DependsOn: dependsOn}}
+	return doc
+}
+
+// spdxDocument is an SPDX 2.3 JSON document
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	PrimaryPurpose   string            `json:"primaryPackagePurpose,omitempty"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxIDPattern matches the characters SPDX identifiers cannot contain
+var spdxIDPattern = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// buildSPDXDocument builds the SPDX SBOM of a workflow
+func buildSPDXDocument(workflowName string, components []sbomComponent, toolVersion string, timestamp time.Time) spdxDocument {
+	workflowID := "SPDXRef-Workflow-" + spdxIDPattern.ReplaceAllString(workflowName, "-")
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              workflowName,
+		DocumentNamespace: "https://spdx.org/spdxdocs/gh-aw-" + spdxIDPattern.ReplaceAllString(workflowName, "-") + "-" + newSBOMUUID(),
+		CreationInfo: spdxCreationInfo{
+			Created:  timestamp.Format(time.RFC3339),
+			Creators: []string{"Tool: gh-aw-" + toolVersion},
+		},
+		Packages: []spdxPackage{{
+			SPDXID:           workflowID,
+			Name:             workflowName,
+			DownloadLocation: "NOASSERTION",
+			PrimaryPurpose:   "APPLICATION",
+		}},
+		Relationships: []spdxRelationship{{
+			SPDXElementID:      "SPDXRef-DOCUMENT",
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: workflowID,
+		}},
+	}
+
+	purposes := map[string]string{"application": "APPLICATION", "library": "LIBRARY", "container": "CONTAINER", "action": "LIBRARY"}
+	for i, component := range components {
+		packageID := fmt.Sprintf("SPDXRef-Package-%d-%s", i+1, strings.Trim(spdxIDPattern.ReplaceAllString(component.Name, "-"), "-"))
+		pkg := spdxPackage{
+			SPDXID:           packageID,
+			Name:             component.Name,
+			VersionInfo:      component.Version,
+			DownloadLocation: "NOASSERTION",
+			PrimaryPurpose:   purposes[component.Kind],
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  component.PURL,
+			}},
+		}
+		if component.SHA1 != "" {
+			pkg.Checksums = append(pkg.Checksums, spdxChecksum{Algorithm: "SHA1", ChecksumValue: component.SHA1})
+		}
+		if digest, ok := strings.CutPrefix(component.Digest, "sha256:"); ok {
+			pkg.Checksums = append(pkg.Checksums, spdxChecksum{Algorithm: "SHA256", ChecksumValue: digest})
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      workflowID,
+			RelationshipType:   "DEPENDS_ON",
+			RelatedSPDXElement: packageID,
+		})
+	}
+	return doc
+}
+
+// newSBOMUUID returns a random (version 4) UUID identifying an SBOM document
+func newSBOMUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
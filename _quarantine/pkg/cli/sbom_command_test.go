@@ -0,0 +1,97 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSBOMComponentsFromLockFile(t *testing.T) {
+	lockContent := `jobs:
+  agent:
+    steps:
+      - name: Checkout
+        uses: actions/checkout@de0fac2e4500dabe0009e67214ff5f5447ce83dd # v6.0.2
+      - uses: ./actions/setup
+      - uses: github/codeql-action/upload-sarif@v3
+      - name: Checkout again
+        uses: actions/checkout@de0fac2e4500dabe0009e67214ff5f5447ce83dd # v6.0.2
+`
+	components := sbomComponentsFromLockFile(lockContent)
+	require.Len(t, components, 2)
+
+	assert.Equal(t, sbomComponent{
+		Kind:    "action",
+		Name:    "actions/checkout",
+		Version: "v6.0.2",
+		PURL:    "pkg:github/actions/checkout@de0fac2e4500dabe0009e67214ff5f5447ce83dd",
+		SHA1:    "de0fac2e4500dabe0009e67214ff5f5447ce83dd",
+	}, components[0])
+	assert.Equal(t, "v3", components[1].Version)
+	assert.Equal(t, "pkg:github/github/codeql-action@v3#upload-sarif", components[1].PURL)
+	assert.Empty(t, components[1].SHA1, "tags are not hashes")
+}
+
+func TestSBOMComponentsFromDependencies(t *testing.T) {
+	components := sbomComponentsFromDependencies([]workflow.LockedDependency{
+		{Ecosystem: workflow.DepsEcosystemNpm, Name: "@github/copilot", Version: "0.0.406", Engine: "copilot"},
+		{Ecosystem: workflow.DepsEcosystemPyPI, Name: "black", Version: "24.1.0"},
+		{Ecosystem: workflow.DepsEcosystemDocker, Name: "ghcr.io/github/github-mcp-server", Version: "v0.30.3", Digest: "sha256:abc"},
+		{Ecosystem: workflow.DepsEcosystemDocker, Name: "node", Version: "lts-alpine"},
+	})
+	require.Len(t, components, 4)
+	assert.Equal(t, "application", components[0].Kind)
+	assert.Equal(t, "pkg:npm/%40github/copilot@0.0.406", components[0].PURL)
+	assert.Equal(t, "pkg:pypi/black@24.1.0", components[1].PURL)
+	assert.Equal(t, "container", components[2].Kind)
+	assert.Equal(t, "pkg:oci/github-mcp-server@sha256%3Aabc?repository_url=ghcr.io%2Fgithub%2Fgithub-mcp-server&tag=v0.30.3", components[2].PURL)
+	assert.Equal(t, "pkg:docker/node@lts-alpine", components[3].PURL)
+}
+
+func TestBuildSBOMDocuments(t *testing.T) {
+	components := []sbomComponent{
+		{Kind: "application", Name: "@github/copilot", Version: "0.0.406", PURL: "pkg:npm/%40github/copilot@0.0.406"},
+		{Kind: "container", Name: "node", Version: "lts-alpine", PURL: "pkg:oci/node@sha256:abc", Digest: "sha256:abc"},
+		{Kind: "action", Name: "actions/checkout", Version: "v6", PURL: "pkg:github/actions/checkout@de0f", SHA1: "de0f"},
+	}
+	timestamp := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	cdx := buildCycloneDXDocument("ci-doctor", components, "v1.2.3", timestamp)
+	assert.Equal(t, "CycloneDX", cdx.BOMFormat)
+	assert.Equal(t, "1.5", cdx.SpecVersion)
+	assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, cdx.SerialNumber)
+	assert.Equal(t, "2026-03-02T09:00:00Z", cdx.Metadata.Timestamp)
+	assert.Equal(t, "ci-doctor", cdx.Metadata.Component.Name)
+	require.Len(t, cdx.Components, 3)
+	assert.Equal(t, "library", cdx.Components[2].Type, "actions are libraries in CycloneDX")
+	assert.Equal(t, []cycloneDXHash{{Alg: "SHA-256", Content: "abc"}}, cdx.Components[1].Hashes)
+	require.Len(t, cdx.Dependencies, 1)
+	assert.Len(t, cdx.Dependencies[0].DependsOn, 3)
+
+	spdx := buildSPDXDocument("ci-doctor", components, "v1.2.3", timestamp)
+	assert.Equal(t, "SPDX-2.3", spdx.SPDXVersion)
+	assert.Equal(t, []string{"Tool: gh-aw-v1.2.3"}, spdx.CreationInfo.Creators)
+	require.Len(t, spdx.Packages, 4, "the workflow and its components")
+	assert.Equal(t, "SPDXRef-Package-1-github-copilot", spdx.Packages[1].SPDXID)
+	assert.Equal(t, "CONTAINER", spdx.Packages[2].PrimaryPurpose)
+	assert.Equal(t, []spdxChecksum{{Algorithm: "SHA1", ChecksumValue: "de0f"}}, spdx.Packages[3].Checksums)
+	require.Len(t, spdx.Relationships, 4)
+	assert.Equal(t, "DESCRIBES", spdx.Relationships[0].RelationshipType)
+	assert.Equal(t, "DEPENDS_ON", spdx.Relationships[1].RelationshipType)
+
+	data, err := json.Marshal(spdx)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"referenceType":"purl"`)
+}
+
+func TestRunSBOMUnsupportedFormat(t *testing.T) {
+	err := RunSBOM(SBOMConfig{WorkflowID: "ci-doctor", Format: "xml"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported SBOM format 'xml'")
+}
@@ -0,0 +1,273 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/spf13/cobra"
+)
+
+var scheduleCommandLog = logger.New("cli:schedule_command")
+
+// ScheduleConfig holds configuration for the schedule command
+type ScheduleConfig struct {
+	WorkflowDir string
+	Timezone    string    // IANA timezone shown next to UTC (default: local timezone)
+	Week        bool      // Show 7 days instead of 24 hours
+	Start       time.Time // Start of the timeline (default: now)
+	JSONOutput  bool
+	Verbose     bool
+}
+
+// ScheduleReport is the timeline of the scheduled workflows of a repository
+type ScheduleReport struct {
+	Start     string              `json:"start"`
+	End       string              `json:"end"`
+	Timezone  string              `json:"timezone"`
+	Workflows []ScheduledWorkflow `json:"workflows"`
+	Runs      []ScheduledRun      `json:"runs"`
+	Overlaps  int                 `json:"overlaps"`
+}
+
+// ScheduledWorkflow is a schedule of a workflow with its resolved cron expression
+type ScheduledWorkflow struct {
+	Workflow string `json:"workflow"`
+	Schedule string `json:"schedule"`
+	Cron     string `json:"cron"` // UTC cron expressions, comma separated when a timezone needs several
+	Runs     int    `json:"runs"`
+}
+
+// ScheduledRun is a minute of the timeline at which one or more workflows fire
+type ScheduledRun struct {
+	Time      string   `json:"time"`
+	LocalTime string   `json:"local_time"`
+	Workflows []string `json:"workflows"`
+}
+
+// NewScheduleCommand creates the schedule command
+func NewScheduleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Show when the scheduled workflows of the repository fire",
+		Long: `Show a timeline of when each scheduled workflow of the repository fires.
+
+Schedules are resolved the way compile resolves them: fuzzy schedules (daily, hourly,
+weekly, "daily around 9:00", "daily between 9:00 and 17:00", ...) are planned across all
+workflows of the repository so that no two workflows fire at the same minute when their
+windows allow it. Times are shown in UTC, which GitHub Actions uses for cron schedules,
+and in a chosen timezone. Schedules given in a timezone ("daily around 09:00 Europe/Kyiv")
+or with a calendar rule ("first monday of month") only list the runs their guard allows.
+
+The timeline covers the next 24 hours, or the next 7 days with --week. Minutes at which
+more than one workflow fires are reported as overlaps.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` schedule                              # Next 24 hours in UTC and local time
+  ` + string(constants.CLIExtensionPrefix) + ` schedule --week                       # Next 7 days
+  ` + string(constants.CLIExtensionPrefix) + ` schedule --timezone Europe/Berlin     # Show Berlin time next to UTC
+  ` + string(constants.CLIExtensionPrefix) + ` schedule --json                       # Output the timeline as JSON`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, _ := cmd.Flags().GetString("dir")
+			timezone, _ := cmd.Flags().GetString("timezone")
+			week, _ := cmd.Flags().GetBool("week")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+
+			return RunSchedule(ScheduleConfig{
+				WorkflowDir: dir,
+				Timezone:    timezone,
+				Week:        week,
+				JSONOutput:  jsonOutput,
+				Verbose:     verbose,
+			})
+		},
+	}
+
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	cmd.Flags().String("timezone", "", "IANA timezone shown next to UTC, e.g. America/New_York (default: local timezone)")
+	cmd.Flags().Bool("week", false, "Show the next 7 days instead of the next 24 hours")
+	addJSONFlag(cmd)
+
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// RunSchedule prints the schedule timeline of the repository's workflows
+func RunSchedule(config ScheduleConfig) error {
+	scheduleCommandLog.Printf("Showing schedule: dir=%s, timezone=%s, week=%v", config.WorkflowDir, config.Timezone, config.Week)
+
+	location := time.Local
+	if config.Timezone != "" {
+		loc, err := time.LoadLocation(config.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone '%s': %w", config.Timezone, err)
+		}
+		location = loc
+	}
+
+	mdFiles, err := findRepositoryWorkflowFiles(config.WorkflowDir)
+	if err != nil {
+		return fmt.Errorf("failed to find workflows: %w", err)
+	}
+	schedules := collectWorkflowSchedules(mdFiles)
+	if len(schedules) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No scheduled workflows found"))
+		return nil
+	}
+
+	start := config.Start
+	if start.IsZero() {
+		start = time.Now()
+	}
+	start = start.UTC().Truncate(time.Minute)
+	end := start.Add(24 * time.Hour)
+	if config.Week {
+		end = start.Add(7 * 24 * time.Hour)
+	}
+
+	report, err := buildScheduleReport(schedules, start, end, location)
+	if err != nil {
+		return err
+	}
+
+	if config.JSONOutput {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	displayScheduleReport(report, config.Week, location)
+	return nil
+}
+
+// buildScheduleReport resolves the schedules and lists the runs in [start, end)
+func buildScheduleReport(schedules []workflowSchedule, start, end time.Time, location *time.Location) (*ScheduleReport, error) {
+	plan := planSchedules(schedules)
+	report := &ScheduleReport{
+		Start:    start.Format(time.RFC3339),
+		End:      end.Format(time.RFC3339),
+		Timezone: location.String(),
+	}
+
+	runs := make(map[time.Time][]string)
+	for _, schedule := range schedules {
+		cron := schedule.Cron
+		if parser.IsFuzzyCron(cron) {
+			scattered, ok := plan.Lookup(schedule.Workflow, cron)
+			if !ok {
+				var err error
+				if scattered, err = parser.ScatterSchedule(cron, schedule.Seed); err != nil {
+					return nil, fmt.Errorf("failed to resolve schedule '%s' of %s: %w", schedule.Expression, schedule.Workflow, err)
+				}
+			}
+			cron = scattered
+		}
+
+		// Schedules in a timezone or with a calendar rule fire at the times of one or more
+		// guarded UTC cron expressions
+		expanded, err := parser.ExpandSchedule(cron)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve schedule '%s' of %s: %w", schedule.Expression, schedule.Workflow, err)
+		}
+		utcCrons := make([]string, 0, len(expanded))
+		for _, expandedCron := range expanded {
+			utcCrons = append(utcCrons, expandedCron.Cron)
+		}
+
+		times, err := parser.CronFireTimes(cron, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve schedule '%s' of %s: %w", schedule.Expression, schedule.Workflow, err)
+		}
+		for _, t := range times {
+			if !slices.Contains(runs[t], schedule.Name) {
+				runs[t] = append(runs[t], schedule.Name)
+			}
+		}
+		report.Workflows = append(report.Workflows, ScheduledWorkflow{
+			Workflow: schedule.Name,
+			Schedule: schedule.Expression,
+			Cron:     strings.Join(utcCrons, ", "),
+			Runs:     len(times),
+		})
+	}
+
+	times := make([]time.Time, 0, len(runs))
+	for t := range runs {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	for _, t := range times {
+		workflows := runs[t]
+		sort.Strings(workflows)
+		if len(workflows) > 1 {
+			report.Overlaps++
+		}
+		report.Runs = append(report.Runs, ScheduledRun{
+			Time:      t.Format(time.RFC3339),
+			LocalTime: t.In(location).Format(time.RFC3339),
+			Workflows: workflows,
+		})
+	}
+
+	scheduleCommandLog.Printf("Built schedule report: workflows=%d, runs=%d, overlaps=%d", len(report.Workflows), len(report.Runs), report.Overlaps)
+	return report, nil
+}
+
+// displayScheduleReport renders the schedules and the timeline as tables
+func displayScheduleReport(report *ScheduleReport, week bool, location *time.Location) {
+	start, _ := time.Parse(time.RFC3339, report.Start)
+	period := "next 24 hours"
+	timeLayout := "15:04"
+	if week {
+		period = "next 7 days"
+		timeLayout = "Mon 15:04"
+	}
+
+	workflowRows := make([][]string, 0, len(report.Workflows))
+	for _, w := range report.Workflows {
+		workflowRows = append(workflowRows, []string{w.Workflow, w.Schedule, w.Cron, strconv.Itoa(w.Runs)})
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   fmt.Sprintf("Scheduled workflows (%s from %s)", period, start.Format("2006-01-02 15:04 MST")),
+		Headers: []string{"Workflow", "Schedule", "Cron (UTC)", "Runs"},
+		Rows:    workflowRows,
+	}))
+	fmt.Fprintln(os.Stderr)
+
+	runRows := make([][]string, 0, len(report.Runs))
+	for _, run := range report.Runs {
+		t, _ := time.Parse(time.RFC3339, run.Time)
+		workflows := strings.Join(run.Workflows, ", ")
+		if len(run.Workflows) > 1 {
+			workflows += " (overlap)"
+		}
+		runRows = append(runRows, []string{t.UTC().Format(timeLayout), t.In(location).Format(timeLayout + " MST"), workflows})
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   "Timeline",
+		Headers: []string{"UTC", location.String(), "Workflows"},
+		Rows:    runRows,
+	}))
+	fmt.Fprintln(os.Stderr)
+
+	if report.Overlaps > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%d minute(s) with more than one workflow firing; consider fuzzy schedules (e.g. 'daily') so they can be spread out", report.Overlaps)))
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("No two workflows fire at the same minute"))
+	}
+}
@@ -0,0 +1,88 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectWorkflowSchedules(t *testing.T) {
+	dir := testutil.TempDir(t, "schedules-*")
+	files := map[string]string{
+		"report.md":  "---\non: daily\n---\n# Report\n",
+		"nightly.md": "---\non:\n  schedule:\n    - cron: \"0 3 * * *\"\n    - cron: weekly on monday\n---\n# Nightly\n",
+		"triage.md":  "---\non: /triage\n---\n# Triage\n",
+		"broken.md":  "---\non: [\n---\n",
+	}
+	var mdFiles []string
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		mdFiles = append(mdFiles, path)
+	}
+	lock := "name: Report\n\"on\":\n  schedule:\n    - cron: \"17 6 * * *\"\n  workflow_dispatch:\njobs: {}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.lock.yml"), []byte(lock), 0644))
+
+	schedules := collectWorkflowSchedules(mdFiles)
+	require.Len(t, schedules, 3)
+	assert.Equal(t, "nightly", schedules[0].Name)
+	assert.Equal(t, "0 3 * * *", schedules[0].Cron)
+	assert.Equal(t, "weekly on monday", schedules[1].Expression)
+	assert.Equal(t, "FUZZY:WEEKLY:1 * * *", schedules[1].Cron)
+	assert.Equal(t, "report", schedules[2].Name)
+	assert.Equal(t, "FUZZY:DAILY * * *", schedules[2].Cron)
+	assert.Equal(t, []string{"17 6 * * *"}, schedules[2].Compiled, "compiled crons are read from the lock file")
+	assert.Empty(t, schedules[0].Compiled, "workflows without a lock file have no compiled crons")
+}
+
+func TestBuildScheduleReport(t *testing.T) {
+	schedules := []workflowSchedule{
+		{Workflow: "a.md", Name: "a", Expression: "0 9 * * *", Cron: "0 9 * * *", Seed: "a.md"},
+		{Workflow: "b.md", Name: "b", Expression: "0 9 * * *", Cron: "0 9 * * *", Seed: "b.md"},
+		{Workflow: "c.md", Name: "c", Expression: "every 6 hours", Cron: "0 */6 * * *", Seed: "c.md"},
+		{Workflow: "d.md", Name: "d", Expression: "daily around 9:00", Cron: "FUZZY:DAILY_AROUND:9:0 * * *", Seed: "d.md"},
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	report, err := buildScheduleReport(schedules, start, start.Add(24*time.Hour), berlin)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", report.Timezone)
+	require.Len(t, report.Workflows, 4)
+	assert.Equal(t, 1, report.Workflows[0].Runs)
+	assert.Equal(t, 4, report.Workflows[2].Runs)
+	assert.NotContains(t, report.Workflows[3].Cron, "FUZZY", "fuzzy schedules are resolved")
+	assert.NotEqual(t, "0 9 * * *", report.Workflows[3].Cron, "the fuzzy schedule avoids the fixed ones")
+
+	// a and b overlap at 09:00 UTC, which is 10:00 in Berlin
+	assert.Equal(t, 1, report.Overlaps)
+	var overlap *ScheduledRun
+	for i := range report.Runs {
+		if len(report.Runs[i].Workflows) > 1 {
+			overlap = &report.Runs[i]
+		}
+	}
+	require.NotNil(t, overlap)
+	assert.Equal(t, []string{"a", "b"}, overlap.Workflows)
+	assert.Equal(t, "2026-03-02T09:00:00Z", overlap.Time)
+	assert.Equal(t, "2026-03-02T10:00:00+01:00", overlap.LocalTime)
+
+	week, err := buildScheduleReport(schedules, start, start.Add(7*24*time.Hour), time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, 28, week.Workflows[2].Runs)
+	assert.Equal(t, 7, week.Overlaps)
+}
+
+func TestRunScheduleInvalidTimezone(t *testing.T) {
+	err := RunSchedule(ScheduleConfig{Timezone: "Mars/Olympus"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid timezone 'Mars/Olympus'")
+}
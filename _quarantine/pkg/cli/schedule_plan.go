@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+var schedulePlanLog = logger.New("cli:schedule_plan")
+
+// workflowSchedule is a schedule declared by a workflow of the repository
+type workflowSchedule struct {
+	Workflow   string   // Workflow identifier (repository-relative path), as used for scattering
+	Name       string   // Workflow name (file name without .md)
+	Expression string   // Schedule as written in the frontmatter
+	Cron       string   // Parsed cron expression or fuzzy schedule placeholder
+	Seed       string   // Scattering seed (repository slug and workflow identifier)
+	Compiled   []string // Cron expressions currently compiled into the workflow's lock file
+}
+
+// collectWorkflowSchedules reads the schedules declared by the given workflow files.
+// Files that cannot be read or parsed are skipped; compilation reports their errors.
+func collectWorkflowSchedules(mdFiles []string) []workflowSchedule {
+	var repoSlug string
+	if len(mdFiles) > 0 {
+		repoSlug = getRepositorySlugFromRemoteForPath(mdFiles[0])
+	}
+
+	var schedules []workflowSchedule
+	for _, file := range mdFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			schedulePlanLog.Printf("Skipping %s: %v", file, err)
+			continue
+		}
+		result, err := parser.ExtractFrontmatterFromContent(string(content))
+		if err != nil || result.Frontmatter == nil {
+			continue
+		}
+		expressions := workflow.ExtractScheduleExpressions(result.Frontmatter)
+		if len(expressions) == 0 {
+			continue
+		}
+
+		// Use the same identifier and seed as configureCompilerForFile and the compiler
+		relPath, err := getRepositoryRelativePath(file)
+		if err != nil {
+			relPath = filepath.Base(file)
+		}
+		seed := relPath
+		if repoSlug != "" {
+			seed = repoSlug + "/" + relPath
+		}
+		compiled := readCompiledCrons(stringutil.MarkdownToLockFile(file))
+		for _, expression := range expressions {
+			cron, _, err := parser.ParseSchedule(expression)
+			if err != nil {
+				schedulePlanLog.Printf("Skipping schedule %q of %s: %v", expression, relPath, err)
+				continue
+			}
+			schedules = append(schedules, workflowSchedule{
+				Workflow:   relPath,
+				Name:       normalizeWorkflowID(file),
+				Expression: expression,
+				Cron:       cron,
+				Seed:       seed,
+				Compiled:   compiled,
+			})
+		}
+	}
+
+	sort.SliceStable(schedules, func(i, j int) bool { return schedules[i].Workflow < schedules[j].Workflow })
+	return schedules
+}
+
+// readCompiledCrons returns the schedule cron expressions of a lock file, or nil when the
+// workflow has not been compiled. The planner keeps these placements so that compiling one
+// workflow does not move the schedules of the others.
+func readCompiledCrons(lockFile string) []string {
+	content, err := os.ReadFile(lockFile)
+	if err != nil {
+		return nil
+	}
+	var lock struct {
+		On any `yaml:"on"`
+	}
+	if err := yaml.Unmarshal(content, &lock); err != nil {
+		schedulePlanLog.Printf("Ignoring schedules of %s: %v", lockFile, err)
+		return nil
+	}
+	triggers, ok := lock.On.(map[string]any)
+	if !ok {
+		return nil
+	}
+	items, _ := triggers["schedule"].([]any)
+	var crons []string
+	for _, item := range items {
+		if entry, ok := item.(map[string]any); ok {
+			if cron, ok := entry["cron"].(string); ok {
+				crons = append(crons, cron)
+			}
+		}
+	}
+	return crons
+}
+
+// planSchedules plans the fuzzy schedules of the collected workflow schedules
+func planSchedules(schedules []workflowSchedule) *parser.SchedulePlan {
+	entries := make([]parser.ScheduleEntry, 0, len(schedules))
+	for _, schedule := range schedules {
+		entries = append(entries, parser.ScheduleEntry{Workflow: schedule.Workflow, Seed: schedule.Seed, Cron: schedule.Cron, Current: schedule.Compiled})
+	}
+	return parser.PlanSchedules(entries)
+}
+
+// findRepositoryWorkflowFiles returns the workflow markdown files of the repository's
+// workflow directory (default .github/workflows). Relative directories are resolved
+// against the repository root.
+func findRepositoryWorkflowFiles(workflowDir string) ([]string, error) {
+	if workflowDir == "" {
+		workflowDir = getWorkflowsDir()
+	}
+	if !filepath.IsAbs(workflowDir) {
+		gitRoot, err := findGitRoot()
+		if err != nil {
+			return nil, err
+		}
+		workflowDir = filepath.Join(gitRoot, filepath.Clean(workflowDir))
+	}
+	mdFiles, err := filepath.Glob(filepath.Join(workflowDir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	return filterWorkflowFiles(mdFiles), nil
+}
+
+// setupSchedulePlan plans the fuzzy schedules of every workflow in the repository so that
+// compiling any subset of the workflows scatters them consistently and without collisions
+func setupSchedulePlan(compiler *workflow.Compiler, workflowDir string) {
+	mdFiles, err := findRepositoryWorkflowFiles(workflowDir)
+	if err != nil {
+		schedulePlanLog.Printf("Not planning schedules: %v", err)
+		return
+	}
+	schedules := collectWorkflowSchedules(mdFiles)
+	if len(schedules) == 0 {
+		return
+	}
+	plan := planSchedules(schedules)
+	schedulePlanLog.Printf("Planned %d fuzzy schedule(s) across %d workflow file(s)", plan.Len(), len(mdFiles))
+	compiler.SetSchedulePlan(plan)
+}
@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+)
+
+var simulateLog = logger.New("cli:simulate_command")
+
+// SimulateConfig holds configuration for the simulate command
+type SimulateConfig struct {
+	WorkflowName    string
+	EventName       string
+	Action          string
+	Actor           string
+	ActorPermission string
+	Repository      string
+	Body            string
+	PayloadFile     string
+	AgentOutputFile string
+	Inputs          []string
+	ThreatDetected  bool
+	Verbose         bool
+	JSONOutput      bool
+}
+
+// NewSimulateCommand creates the simulate command
+func NewSimulateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate <workflow>",
+		Short: "Simulate a workflow run locally against a synthetic event",
+		Long: `Simulate a workflow run locally without pushing it to GitHub.
+
+The workflow is compiled in memory and the resulting jobs are interpreted against a
+synthetic event payload. Job and step 'if:' conditions are evaluated with GitHub Actions
+semantics, and the agentic engine step is replaced by a stub that emits the agent output
+given with --agent-output (agent_output.json or safe outputs JSONL format).
+
+The report shows which jobs would run, which would be skipped and why, including the
+activation chain, threat detection and safe-output jobs.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` simulate issue-triage                                  # Simulate the default trigger
+  ` + string(constants.CLIExtensionPrefix) + ` simulate issue-triage --event issues --action opened   # Simulate an opened issue
+  ` + string(constants.CLIExtensionPrefix) + ` simulate pr-review --event pull_request --actor-permission read
+  ` + string(constants.CLIExtensionPrefix) + ` simulate bot --event issue_comment --body "/bot help"  # Simulate a slash command
+  ` + string(constants.CLIExtensionPrefix) + ` simulate issue-triage --agent-output agent_output.json # Stub agent output
+  ` + string(constants.CLIExtensionPrefix) + ` simulate release-notes --event workflow_dispatch -F version=1.2.3
+  ` + string(constants.CLIExtensionPrefix) + ` simulate issue-triage --json                           # Output report as JSON`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			eventName, _ := cmd.Flags().GetString("event")
+			action, _ := cmd.Flags().GetString("action")
+			actor, _ := cmd.Flags().GetString("actor")
+			actorPermission, _ := cmd.Flags().GetString("actor-permission")
+			repository, _ := cmd.Flags().GetString("repo")
+			body, _ := cmd.Flags().GetString("body")
+			payloadFile, _ := cmd.Flags().GetString("payload")
+			agentOutputFile, _ := cmd.Flags().GetString("agent-output")
+			inputs, _ := cmd.Flags().GetStringArray("raw-field")
+			threatDetected, _ := cmd.Flags().GetBool("threat-detected")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			return RunSimulate(SimulateConfig{
+				WorkflowName:    args[0],
+				EventName:       eventName,
+				Action:          action,
+				Actor:           actor,
+				ActorPermission: actorPermission,
+				Repository:      repository,
+				Body:            body,
+				PayloadFile:     payloadFile,
+				AgentOutputFile: agentOutputFile,
+				Inputs:          inputs,
+				ThreatDetected:  threatDetected,
+				Verbose:         verbose,
+				JSONOutput:      jsonOutput,
+			})
+		},
+	}
+
+	cmd.Flags().String("event", "", "Event to simulate (issues, pull_request, issue_comment, schedule, workflow_dispatch, ...). Defaults to workflow_dispatch or the first trigger")
+	cmd.Flags().String("action", "", "Event activity type (e.g. opened, created, labeled). Defaults per event")
+	cmd.Flags().String("actor", "octocat", "Login of the user triggering the event")
+	cmd.Flags().String("actor-permission", "admin", "Repository permission of the actor (admin, maintain, write, triage, read)")
+	cmd.Flags().String("repo", defaultSimulatedRepository, "Repository (owner/repo) used in the synthetic event payload")
+	cmd.Flags().String("body", "", "Body of the issue, pull request or comment in the synthetic payload")
+	cmd.Flags().String("payload", "", "JSON file whose top-level keys override the synthetic event payload")
+	cmd.Flags().String("agent-output", "", "Agent output file emitted by the stub engine (agent_output.json or safe outputs JSONL)")
+	cmd.Flags().StringArrayP("raw-field", "F", []string{}, "Add a workflow input in key=value format (can be used multiple times)")
+	cmd.Flags().Bool("threat-detected", false, "Simulate threat detection flagging the agent output")
+	addJSONFlag(cmd)
+
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
+
+// RunSimulate compiles a workflow in memory and simulates a run against a synthetic event
+func RunSimulate(config SimulateConfig) error {
+	simulateLog.Printf("Simulating workflow: %s (event=%s)", config.WorkflowName, config.EventName)
+
+	inputs, err := parseSimulationInputs(config.Inputs)
+	if err != nil {
+		return err
+	}
+
+	markdownPath, err := resolveWorkflowFile(config.WorkflowName, config.Verbose)
+	if err != nil {
+		return err
+	}
+
+	compiler := workflow.NewCompiler(workflow.WithVerbose(config.Verbose), workflow.WithNoEmit(true))
+	compiler.SetQuiet(true)
+	workflowData, lockContent, err := compiler.CompileToYAML(markdownPath)
+	if err != nil {
+		return err
+	}
+
+	agentOutput := &SimulatedAgentOutput{}
+	if config.AgentOutputFile != "" {
+		if agentOutput, err = loadSimulatedAgentOutput(config.AgentOutputFile); err != nil {
+			return err
+		}
+	}
+
+	eventName := config.EventName
+	if eventName == "" {
+		var wf simulatedWorkflowFile
+		if err := yaml.Unmarshal([]byte(lockContent), &wf); err != nil {
+			return fmt.Errorf("failed to parse compiled workflow: %w", err)
+		}
+		eventName = defaultSimulatedEvent(wf.On)
+	}
+
+	github, err := buildSimulatedGitHubContext(SimulatedEventOptions{
+		EventName:   eventName,
+		Action:      config.Action,
+		Actor:       config.Actor,
+		Repository:  config.Repository,
+		Body:        config.Body,
+		Inputs:      inputs,
+		PayloadFile: config.PayloadFile,
+	})
+	if err != nil {
+		return err
+	}
+
+	result, err := simulateWorkflow(lockContent, simulationOptions{
+		GitHub:          github,
+		Inputs:          inputs,
+		AgentOutput:     agentOutput,
+		ActorPermission: config.ActorPermission,
+		ThreatDetected:  config.ThreatDetected,
+		EngineSteps:     engineExecutionStepNames(workflowData),
+	})
+	if err != nil {
+		return err
+	}
+
+	if config.JSONOutput {
+		jsonBytes, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	renderSimulationResult(result, config.Verbose)
+	return nil
+}
+
+// parseSimulationInputs parses key=value workflow inputs
+func parseSimulationInputs(inputs []string) (map[string]any, error) {
+	result := make(map[string]any, len(inputs))
+	for _, input := range inputs {
+		key, value, ok := strings.Cut(input, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid input format '%s': expected key=value", input)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("invalid input format '%s': key cannot be empty", input)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// engineExecutionStepNames returns the names of the steps the engine generates to run the
+// agent so the simulator can replace them with the stub engine
+func engineExecutionStepNames(data *workflow.WorkflowData) map[string]bool {
+	names := make(map[string]bool)
+	engine, err := workflow.GetRepositoryEngineRegistry().GetEngine(data.AI)
+	if err != nil {
+		simulateLog.Printf("Could not resolve engine %q: %v", data.AI, err)
+		return names
+	}
+	for _, step := range engine.GetExecutionSteps(data, "/tmp/gh-aw/agent-stdio.log") {
+		for _, line := range step {
+			trimmed := strings.TrimSpace(line)
+			if name, ok := strings.CutPrefix(trimmed, "- name: "); ok {
+				names[strings.Trim(name, `"'`)] = true
+				break
+			}
+		}
+	}
+	simulateLog.Printf("Engine %s execution steps: %d", data.AI, len(names))
+	return names
+}
+
+// renderSimulationResult prints the simulation report to stderr
+func renderSimulationResult(result *SimulationResult, verbose bool) {
+	header := fmt.Sprintf("Simulating '%s' on %s", result.Workflow, result.Event)
+	if result.Action != "" {
+		header += fmt.Sprintf(" (%s)", result.Action)
+	}
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(header))
+
+	if !result.Triggered {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Workflow would not run: "+result.TriggerReason))
+		return
+	}
+
+	if len(result.OutputTypes) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Stub agent output types: "+strings.Join(result.OutputTypes, ", ")))
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Stub agent produced no safe outputs"))
+	}
+	fmt.Fprintln(os.Stderr, "")
+
+	config := console.TableConfig{
+		Title:   "Jobs",
+		Headers: []string{"Job", "Needs", "Result", "Reason"},
+	}
+	ran := 0
+	for _, job := range result.Jobs {
+		if job.Result == simulatedResultSuccess {
+			ran++
+		}
+		config.Rows = append(config.Rows, []string{job.Name, strings.Join(job.Needs, ", "), job.Result, job.Reason})
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(config))
+
+	if verbose {
+		for _, job := range result.Jobs {
+			if len(job.Steps) == 0 {
+				continue
+			}
+			fmt.Fprintln(os.Stderr, "")
+			fmt.Fprintln(os.Stderr, console.FormatListHeader(fmt.Sprintf("Steps in %s", job.Name)))
+			for _, step := range job.Steps {
+				line := fmt.Sprintf("[%s] %s", step.Result, step.Name)
+				if step.Reason != "" {
+					line += " - " + step.Reason
+				}
+				fmt.Fprintln(os.Stderr, console.FormatListItem(line))
+			}
+			if len(job.Outputs) > 0 {
+				keys := make([]string, 0, len(job.Outputs))
+				for k := range job.Outputs {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("output %s = %q", k, job.Outputs[k])))
+				}
+			}
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%d of %d jobs would run", ran, len(result.Jobs))))
+}
@@ -0,0 +1,219 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const simulateTestWorkflow = `---
+on:
+  issues:
+    types: [opened]
+permissions:
+  contents: read
+engine: claude
+safe-outputs:
+  add-comment:
+---
+
+# Triage
+
+Triage the issue.
+`
+
+// compileSimulateTestWorkflow compiles the test workflow in memory and returns the lock content
+func compileSimulateTestWorkflow(t *testing.T) (*workflow.WorkflowData, string) {
+	t.Helper()
+	tmpDir := testutil.TempDir(t, "test-*")
+	markdownPath := filepath.Join(tmpDir, "triage.md")
+	require.NoError(t, os.WriteFile(markdownPath, []byte(simulateTestWorkflow), 0644))
+
+	compiler := workflow.NewCompiler(workflow.WithNoEmit(true))
+	compiler.SetQuiet(true)
+	data, lockContent, err := compiler.CompileToYAML(markdownPath)
+	require.NoError(t, err, "workflow should compile")
+
+	_, err = os.Stat(filepath.Join(tmpDir, "triage.lock.yml"))
+	assert.True(t, os.IsNotExist(err), "CompileToYAML should not write the lock file")
+	return data, lockContent
+}
+
+func simulatedJobsByName(result *SimulationResult) map[string]SimulatedJob {
+	jobs := make(map[string]SimulatedJob, len(result.Jobs))
+	for _, job := range result.Jobs {
+		jobs[job.Name] = job
+	}
+	return jobs
+}
+
+func TestSimulateWorkflow(t *testing.T) {
+	data, lockContent := compileSimulateTestWorkflow(t)
+
+	tests := []struct {
+		name            string
+		eventName       string
+		actorPermission string
+		agentOutput     *SimulatedAgentOutput
+		triggered       bool
+		expectedResults map[string]string
+	}{
+		{
+			name:            "no agent output skips safe outputs",
+			eventName:       "issues",
+			actorPermission: "admin",
+			triggered:       true,
+			expectedResults: map[string]string{
+				"pre_activation": simulatedResultSuccess,
+				"activation":     simulatedResultSuccess,
+				"agent":          simulatedResultSuccess,
+				"detection":      simulatedResultSkipped,
+				"safe_outputs":   simulatedResultSkipped,
+			},
+		},
+		{
+			name:            "agent output runs detection and safe outputs",
+			eventName:       "issues",
+			actorPermission: "write",
+			agentOutput: &SimulatedAgentOutput{Items: []map[string]any{
+				{"type": "add_comment", "body": "Thanks!"},
+			}},
+			triggered: true,
+			expectedResults: map[string]string{
+				"activation":   simulatedResultSuccess,
+				"agent":        simulatedResultSuccess,
+				"detection":    simulatedResultSuccess,
+				"safe_outputs": simulatedResultSuccess,
+			},
+		},
+		{
+			name:            "read permission skips activation",
+			eventName:       "issues",
+			actorPermission: "read",
+			triggered:       true,
+			expectedResults: map[string]string{
+				"pre_activation": simulatedResultSuccess,
+				"activation":     simulatedResultSkipped,
+				"agent":          simulatedResultSkipped,
+			},
+		},
+		{
+			name:      "untriggered event",
+			eventName: "pull_request",
+			triggered: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			github, err := buildSimulatedGitHubContext(SimulatedEventOptions{EventName: tt.eventName})
+			require.NoError(t, err)
+
+			result, err := simulateWorkflow(lockContent, simulationOptions{
+				GitHub:          github,
+				AgentOutput:     tt.agentOutput,
+				ActorPermission: tt.actorPermission,
+				EngineSteps:     engineExecutionStepNames(data),
+			})
+			require.NoError(t, err, "simulation should succeed")
+			assert.Equal(t, tt.triggered, result.Triggered, "trigger mismatch: %s", result.TriggerReason)
+			if !tt.triggered {
+				assert.NotEmpty(t, result.TriggerReason, "untriggered workflow should explain why")
+				assert.Empty(t, result.Jobs, "no jobs should be simulated")
+				return
+			}
+
+			jobs := simulatedJobsByName(result)
+			for jobName, expected := range tt.expectedResults {
+				job, ok := jobs[jobName]
+				require.True(t, ok, "job %s should be simulated", jobName)
+				assert.Equal(t, expected, job.Result, "job %s: %s", jobName, job.Reason)
+				assert.NotEmpty(t, job.Reason, "job %s should report a reason", jobName)
+			}
+		})
+	}
+}
+
+func TestSimulateWorkflowStubsEngineStep(t *testing.T) {
+	data, lockContent := compileSimulateTestWorkflow(t)
+	github, err := buildSimulatedGitHubContext(SimulatedEventOptions{EventName: "issues"})
+	require.NoError(t, err)
+
+	result, err := simulateWorkflow(lockContent, simulationOptions{
+		GitHub:          github,
+		ActorPermission: "admin",
+		EngineSteps:     engineExecutionStepNames(data),
+	})
+	require.NoError(t, err)
+
+	agent := simulatedJobsByName(result)["agent"]
+	stubbed := 0
+	for _, step := range agent.Steps {
+		if step.Stubbed {
+			stubbed++
+		}
+	}
+	assert.Positive(t, stubbed, "the engine execution step should be replaced by the stub engine")
+}
+
+func TestCheckWorkflowTriggered(t *testing.T) {
+	on := map[string]any{
+		"issues":            map[string]any{"types": []any{"opened", "edited"}},
+		"workflow_dispatch": nil,
+	}
+
+	triggered, _ := checkWorkflowTriggered(on, "issues", "opened")
+	assert.True(t, triggered, "opened issues should trigger")
+
+	triggered, reason := checkWorkflowTriggered(on, "issues", "closed")
+	assert.False(t, triggered, "closed issues should not trigger")
+	assert.Contains(t, reason, "closed")
+
+	triggered, reason = checkWorkflowTriggered(on, "push", "")
+	assert.False(t, triggered, "push should not trigger")
+	assert.Contains(t, reason, "push")
+
+	assert.Equal(t, "workflow_dispatch", defaultSimulatedEvent(on), "workflow_dispatch should be preferred")
+	assert.Equal(t, "issues", defaultSimulatedEvent(map[string]any{"issues": nil}))
+}
+
+func TestLoadSimulatedAgentOutput(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "test-*")
+
+	jsonPath := filepath.Join(tmpDir, "agent_output.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"items":[{"type":"create_issue","title":"x"}],"errors":[]}`), 0644))
+	output, err := loadSimulatedAgentOutput(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"create_issue"}, output.outputTypes())
+	assert.False(t, output.hasPatch())
+
+	jsonlPath := filepath.Join(tmpDir, "outputs.jsonl")
+	require.NoError(t, os.WriteFile(jsonlPath, []byte("{\"type\":\"add_comment\"}\n{\"type\":\"create_pull_request\"}\n"), 0644))
+	output, err = loadSimulatedAgentOutput(jsonlPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"add_comment", "create_pull_request"}, output.outputTypes())
+	assert.True(t, output.hasPatch())
+
+	badPath := filepath.Join(tmpDir, "bad.jsonl")
+	require.NoError(t, os.WriteFile(badPath, []byte("not json\n"), 0644))
+	_, err = loadSimulatedAgentOutput(badPath)
+	assert.Error(t, err, "invalid JSONL should fail")
+}
+
+func TestParseSimulationInputs(t *testing.T) {
+	inputs, err := parseSimulationInputs([]string{"version=1.2.3", "note=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"version": "1.2.3", "note": "a=b"}, inputs)
+
+	_, err = parseSimulationInputs([]string{"missing"})
+	assert.Error(t, err)
+	_, err = parseSimulationInputs([]string{"=value"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var simulateEventLog = logger.New("cli:simulate_event")
+
+// defaultSimulatedRepository is the repository slug used in synthetic payloads when none is given
+const defaultSimulatedRepository = "octo-org/octo-repo"
+
+// defaultEventActions maps event names to the activity type used when --action is not specified
+var defaultEventActions = map[string]string{
+	"issues":                      "opened",
+	"issue_comment":               "created",
+	"pull_request":                "opened",
+	"pull_request_target":         "opened",
+	"pull_request_review":         "submitted",
+	"pull_request_review_comment": "created",
+	"discussion":                  "created",
+	"discussion_comment":          "created",
+	"release":                     "published",
+}
+
+// SimulatedEventOptions describes the synthetic event a workflow is simulated against
+type SimulatedEventOptions struct {
+	EventName   string         // GitHub event name (issues, pull_request, issue_comment, schedule, ...)
+	Action      string         // Activity type (opened, created, ...); defaults per event
+	Actor       string         // Login of the user triggering the event
+	Repository  string         // owner/repo slug used in the payload
+	Body        string         // Body of the issue, pull request or comment
+	Inputs      map[string]any // workflow_dispatch inputs
+	PayloadFile string         // Optional JSON file whose top-level keys override the generated payload
+}
+
+// buildSimulatedGitHubContext builds the github context for a synthetic event
+func buildSimulatedGitHubContext(opts SimulatedEventOptions) (map[string]any, error) {
+	simulateEventLog.Printf("Building synthetic event: event=%s, action=%s", opts.EventName, opts.Action)
+
+	repoSlug := opts.Repository
+	if repoSlug == "" {
+		repoSlug = defaultSimulatedRepository
+	}
+	owner, repoName, ok := strings.Cut(repoSlug, "/")
+	if !ok || owner == "" || repoName == "" {
+		return nil, fmt.Errorf("invalid repository '%s': expected owner/repo format", repoSlug)
+	}
+	actor := opts.Actor
+	if actor == "" {
+		actor = "octocat"
+	}
+	action := opts.Action
+	if action == "" {
+		action = defaultEventActions[opts.EventName]
+	}
+
+	user := map[string]any{"login": actor, "type": "User"}
+	repository := map[string]any{
+		"name":           repoName,
+		"full_name":      repoSlug,
+		"owner":          map[string]any{"login": owner},
+		"default_branch": "main",
+		"private":        false,
+	}
+	issue := map[string]any{
+		"number": 1,
+		"title":  "Simulated issue",
+		"body":   opts.Body,
+		"state":  "open",
+		"user":   user,
+		"labels": []any{},
+	}
+	pullRequest := map[string]any{
+		"number": 2,
+		"title":  "Simulated pull request",
+		"body":   opts.Body,
+		"state":  "open",
+		"draft":  false,
+		"user":   user,
+		"labels": []any{},
+		"head":   map[string]any{"ref": "feature", "sha": "0000000000000000000000000000000000000002", "repo": map[string]any{"full_name": repoSlug}},
+		"base":   map[string]any{"ref": "main", "sha": "0000000000000000000000000000000000000001", "repo": map[string]any{"full_name": repoSlug}},
+	}
+	comment := map[string]any{"id": 3, "body": opts.Body, "user": user}
+	discussion := map[string]any{"number": 4, "title": "Simulated discussion", "body": opts.Body, "user": user}
+
+	event := map[string]any{
+		"repository": repository,
+		"sender":     user,
+	}
+	if action != "" {
+		event["action"] = action
+	}
+
+	ref := "refs/heads/main"
+	switch opts.EventName {
+	case "issues":
+		event["issue"] = issue
+	case "issue_comment":
+		event["issue"] = issue
+		event["comment"] = comment
+	case "pull_request", "pull_request_target":
+		event["number"] = pullRequest["number"]
+		event["pull_request"] = pullRequest
+		ref = "refs/pull/2/merge"
+	case "pull_request_review":
+		event["pull_request"] = pullRequest
+		event["review"] = map[string]any{"id": 5, "body": opts.Body, "state": "approved", "user": user}
+		ref = "refs/pull/2/merge"
+	case "pull_request_review_comment":
+		event["pull_request"] = pullRequest
+		event["comment"] = comment
+		ref = "refs/pull/2/merge"
+	case "discussion":
+		event["discussion"] = discussion
+	case "discussion_comment":
+		event["discussion"] = discussion
+		event["comment"] = comment
+	case "release":
+		event["release"] = map[string]any{"tag_name": "v1.0.0", "name": "v1.0.0", "body": opts.Body}
+	case "push":
+		event["ref"] = ref
+		event["commits"] = []any{}
+	case "schedule":
+		delete(event, "sender")
+	case "workflow_dispatch":
+		inputs := make(map[string]any, len(opts.Inputs))
+		for k, v := range opts.Inputs {
+			inputs[k] = v
+		}
+		event["inputs"] = inputs
+	}
+
+	if opts.PayloadFile != "" {
+		if err := mergeSimulatedPayloadFile(event, opts.PayloadFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]any{
+		"event_name":       opts.EventName,
+		"event":            event,
+		"actor":            actor,
+		"triggering_actor": actor,
+		"repository":       repoSlug,
+		"repository_owner": owner,
+		"ref":              ref,
+		"ref_name":         strings.TrimPrefix(ref, "refs/heads/"),
+		"sha":              "0000000000000000000000000000000000000001",
+		"workflow":         "simulated",
+		"run_id":           "1",
+		"run_number":       "1",
+		"run_attempt":      "1",
+		"server_url":       "https://github.com",
+		"api_url":          "https://api.github.com",
+		"graphql_url":      "https://api.github.com/graphql",
+		"workspace":        "/home/runner/work/" + repoName + "/" + repoName,
+		"head_ref":         headRefForEvent(opts.EventName),
+		"base_ref":         baseRefForEvent(opts.EventName),
+	}, nil
+}
+
+func headRefForEvent(eventName string) string {
+	if strings.HasPrefix(eventName, "pull_request") {
+		return "feature"
+	}
+	return ""
+}
+
+func baseRefForEvent(eventName string) string {
+	if strings.HasPrefix(eventName, "pull_request") {
+		return "main"
+	}
+	return ""
+}
+
+// mergeSimulatedPayloadFile overrides top-level payload keys with those from a JSON file
+func mergeSimulatedPayloadFile(event map[string]any, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read event payload file: %w", err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return fmt.Errorf("failed to parse event payload file %s: %w", path, err)
+	}
+	for k, v := range payload {
+		event[k] = v
+	}
+	simulateEventLog.Printf("Merged %d payload keys from %s", len(payload), path)
+	return nil
+}
+
+// workflowTriggerEvents returns the event names a compiled workflow's `on:` section listens to
+func workflowTriggerEvents(on any) []string {
+	var events []string
+	switch v := on.(type) {
+	case string:
+		events = append(events, v)
+	case []any:
+		for _, item := range v {
+			if name, ok := item.(string); ok {
+				events = append(events, name)
+			}
+		}
+	case map[string]any:
+		for name := range v {
+			events = append(events, name)
+		}
+	}
+	sort.Strings(events)
+	return events
+}
+
+// defaultSimulatedEvent picks the event to simulate when none is given: workflow_dispatch
+// when available (it needs no payload), otherwise the first trigger alphabetically
+func defaultSimulatedEvent(on any) string {
+	events := workflowTriggerEvents(on)
+	for _, event := range events {
+		if event == "workflow_dispatch" {
+			return event
+		}
+	}
+	if len(events) > 0 {
+		return events[0]
+	}
+	return "workflow_dispatch"
+}
+
+// checkWorkflowTriggered reports whether the workflow's `on:` section accepts the event
+// and activity type, returning a human-readable reason when it does not
+func checkWorkflowTriggered(on any, eventName, action string) (bool, string) {
+	events := workflowTriggerEvents(on)
+	found := false
+	for _, event := range events {
+		if event == eventName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false, fmt.Sprintf("workflow does not trigger on '%s' (triggers: %s)", eventName, strings.Join(events, ", "))
+	}
+
+	onMap, ok := on.(map[string]any)
+	if !ok {
+		return true, ""
+	}
+	config, ok := onMap[eventName].(map[string]any)
+	if !ok {
+		return true, ""
+	}
+	types, ok := config["types"].([]any)
+	if !ok || len(types) == 0 || action == "" {
+		return true, ""
+	}
+	var typeNames []string
+	for _, t := range types {
+		name := fmt.Sprint(t)
+		if name == action {
+			return true, ""
+		}
+		typeNames = append(typeNames, name)
+	}
+	return false, fmt.Sprintf("workflow triggers on '%s' only for types [%s], not '%s'", eventName, strings.Join(typeNames, ", "), action)
+}
@@ -0,0 +1,536 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+var simulateRunnerLog = logger.New("cli:simulate_runner")
+
+// Job and step results reported by the simulator
+const (
+	simulatedResultSuccess = "success"
+	simulatedResultSkipped = "skipped"
+	simulatedResultError   = "error"
+)
+
+// simulatedAgentOutputPath is where the stub engine pretends to write the validated agent output
+const simulatedAgentOutputPath = "/tmp/gh-aw/" + constants.AgentOutputFilename
+
+// expressionTemplatePattern matches ${{ ... }} templates in output and env values
+var expressionTemplatePattern = regexp.MustCompile(`\$\{\{\s*(.*?)\s*\}\}`)
+
+// simulatedWorkflowFile is the subset of a compiled lock file needed for simulation
+type simulatedWorkflowFile struct {
+	Name string                      `yaml:"name"`
+	On   any                         `yaml:"on"`
+	Env  map[string]any              `yaml:"env"`
+	Jobs map[string]simulatedJobSpec `yaml:"jobs"`
+}
+
+// simulatedJobSpec is a compiled job as read from the lock file
+type simulatedJobSpec struct {
+	Needs   any                 `yaml:"needs"`
+	If      any                 `yaml:"if"`
+	Env     map[string]any      `yaml:"env"`
+	Outputs map[string]any      `yaml:"outputs"`
+	Steps   []simulatedStepSpec `yaml:"steps"`
+}
+
+// simulatedStepSpec is a compiled step as read from the lock file
+type simulatedStepSpec struct {
+	Name string         `yaml:"name"`
+	ID   string         `yaml:"id"`
+	If   any            `yaml:"if"`
+	Uses string         `yaml:"uses"`
+	Run  string         `yaml:"run"`
+	Env  map[string]any `yaml:"env"`
+}
+
+// needsList returns the job's dependencies as a sorted list
+func (j simulatedJobSpec) needsList() []string {
+	var needs []string
+	switch v := j.Needs.(type) {
+	case string:
+		needs = append(needs, v)
+	case []any:
+		for _, item := range v {
+			needs = append(needs, fmt.Sprint(item))
+		}
+	}
+	sort.Strings(needs)
+	return needs
+}
+
+// SimulatedAgentOutput is the agent output emitted by the stub engine, in the same
+// format as the agent_output.json produced by the real agent job
+type SimulatedAgentOutput struct {
+	Items  []map[string]any `json:"items"`
+	Errors []string         `json:"errors"`
+}
+
+// outputTypes returns the distinct safe output types in emission order
+func (o *SimulatedAgentOutput) outputTypes() []string {
+	var types []string
+	seen := make(map[string]bool)
+	for _, item := range o.Items {
+		itemType, _ := item["type"].(string)
+		if itemType != "" && !seen[itemType] {
+			seen[itemType] = true
+			types = append(types, itemType)
+		}
+	}
+	return types
+}
+
+// hasPatch reports whether the agent output would have produced a git patch
+func (o *SimulatedAgentOutput) hasPatch() bool {
+	for _, itemType := range o.outputTypes() {
+		if itemType == "create_pull_request" || itemType == "push_to_pull_request_branch" {
+			return true
+		}
+	}
+	return false
+}
+
+// loadSimulatedAgentOutput reads a stub agent output file. Both the agent_output.json
+// format ({"items": [...]}) and the raw safe outputs JSONL format are accepted.
+func loadSimulatedAgentOutput(path string) (*SimulatedAgentOutput, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent output file: %w", err)
+	}
+
+	output := &SimulatedAgentOutput{}
+	trimmed := strings.TrimSpace(string(content))
+	if trimmed == "" {
+		return output, nil
+	}
+	if err := json.Unmarshal([]byte(trimmed), output); err == nil && output.Items != nil {
+		return output, nil
+	}
+
+	output = &SimulatedAgentOutput{}
+	for i, line := range strings.Split(trimmed, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var item map[string]any
+		if err := json.Unmarshal([]byte(line), &item); err != nil {
+			return nil, fmt.Errorf("failed to parse agent output %s line %d: %w", path, i+1, err)
+		}
+		output.Items = append(output.Items, item)
+	}
+	return output, nil
+}
+
+// simulationOptions configures a single simulation run
+type simulationOptions struct {
+	GitHub          map[string]any        // github context for the synthetic event
+	Inputs          map[string]any        // inputs context
+	AgentOutput     *SimulatedAgentOutput // output emitted by the stub engine
+	ActorPermission string                // repository permission of the actor (admin, maintain, write, ...)
+	ThreatDetected  bool                  // whether threat detection flags the agent output
+	EngineSteps     map[string]bool       // names of the engine execution steps replaced by the stub
+}
+
+// SimulatedStep is the simulated outcome of a single step
+type SimulatedStep struct {
+	Name    string `json:"name"`
+	ID      string `json:"id,omitempty"`
+	Result  string `json:"result"`
+	Stubbed bool   `json:"stubbed,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// SimulatedJob is the simulated outcome of a single job
+type SimulatedJob struct {
+	Name      string            `json:"name"`
+	Needs     []string          `json:"needs,omitempty"`
+	Condition string            `json:"condition,omitempty"`
+	Result    string            `json:"result"`
+	Reason    string            `json:"reason"`
+	Outputs   map[string]string `json:"outputs,omitempty"`
+	Steps     []SimulatedStep   `json:"steps,omitempty"`
+}
+
+// SimulationResult is the outcome of simulating a workflow against a synthetic event
+type SimulationResult struct {
+	Workflow      string         `json:"workflow"`
+	Event         string         `json:"event"`
+	Action        string         `json:"action,omitempty"`
+	Triggered     bool           `json:"triggered"`
+	TriggerReason string         `json:"trigger_reason,omitempty"`
+	OutputTypes   []string       `json:"output_types,omitempty"`
+	Jobs          []SimulatedJob `json:"jobs"`
+}
+
+// simulateWorkflow interprets a compiled lock file job by job
+func simulateWorkflow(lockContent string, opts simulationOptions) (*SimulationResult, error) {
+	var wf simulatedWorkflowFile
+	if err := yaml.Unmarshal([]byte(lockContent), &wf); err != nil {
+		return nil, fmt.Errorf("failed to parse compiled workflow: %w", err)
+	}
+
+	eventName, _ := opts.GitHub["event_name"].(string)
+	action := ""
+	if event, ok := opts.GitHub["event"].(map[string]any); ok {
+		action, _ = event["action"].(string)
+	}
+	result := &SimulationResult{
+		Workflow: wf.Name,
+		Event:    eventName,
+		Action:   action,
+	}
+	if opts.AgentOutput == nil {
+		opts.AgentOutput = &SimulatedAgentOutput{}
+	}
+	if opts.AgentOutput.Errors == nil {
+		opts.AgentOutput.Errors = []string{}
+	}
+	result.OutputTypes = opts.AgentOutput.outputTypes()
+
+	result.Triggered, result.TriggerReason = checkWorkflowTriggered(wf.On, eventName, action)
+	if !result.Triggered {
+		simulateRunnerLog.Printf("Workflow not triggered: %s", result.TriggerReason)
+		return result, nil
+	}
+
+	order, err := simulatedJobOrder(wf.Jobs)
+	if err != nil {
+		return nil, err
+	}
+	simulateRunnerLog.Printf("Simulating %d jobs in order: %v", len(order), order)
+
+	needsContext := make(map[string]any)
+	results := make(map[string]string)
+	for _, jobName := range order {
+		job := simulateJob(jobName, wf, results, needsContext, opts)
+		results[jobName] = job.Result
+
+		outputs := make(map[string]any, len(job.Outputs))
+		for k, v := range job.Outputs {
+			outputs[k] = v
+		}
+		needsResult := job.Result
+		if needsResult == simulatedResultError {
+			needsResult = "failure"
+		}
+		needsContext[jobName] = map[string]any{"result": needsResult, "outputs": outputs}
+		result.Jobs = append(result.Jobs, job)
+	}
+
+	return result, nil
+}
+
+// simulatedJobOrder returns the jobs in dependency order, alphabetically within a level
+func simulatedJobOrder(jobs map[string]simulatedJobSpec) ([]string, error) {
+	remaining := make(map[string][]string, len(jobs))
+	for name, job := range jobs {
+		remaining[name] = job.needsList()
+	}
+
+	var order []string
+	done := make(map[string]bool)
+	for len(remaining) > 0 {
+		var ready []string
+		for name, needs := range remaining {
+			satisfied := true
+			for _, need := range needs {
+				if _, exists := jobs[need]; !exists {
+					return nil, fmt.Errorf("job '%s' depends on unknown job '%s'", name, need)
+				}
+				if !done[need] {
+					satisfied = false
+					break
+				}
+			}
+			if satisfied {
+				ready = append(ready, name)
+			}
+		}
+		if len(ready) == 0 {
+			return nil, fmt.Errorf("circular job dependencies detected")
+		}
+		sort.Strings(ready)
+		for _, name := range ready {
+			done[name] = true
+			delete(remaining, name)
+		}
+		order = append(order, ready...)
+	}
+	return order, nil
+}
+
+// simulateJob evaluates a job's condition and, when it runs, its steps and outputs
+func simulateJob(name string, wf simulatedWorkflowFile, results map[string]string, needsContext map[string]any, opts simulationOptions) SimulatedJob {
+	spec := wf.Jobs[name]
+	job := SimulatedJob{
+		Name:      name,
+		Needs:     spec.needsList(),
+		Condition: conditionString(spec.If),
+	}
+
+	// Only direct dependencies are visible in the needs context
+	visibleNeeds := make(map[string]any, len(job.Needs))
+	for _, need := range job.Needs {
+		visibleNeeds[need] = needsContext[need]
+	}
+
+	ctx := workflow.EvalContext{
+		GitHub:    opts.GitHub,
+		Needs:     visibleNeeds,
+		Inputs:    opts.Inputs,
+		Env:       copyAnyMap(wf.Env),
+		Secrets:   map[string]any{},
+		Vars:      map[string]any{},
+		JobStatus: ancestorStatus(name, wf.Jobs, results),
+	}
+
+	shouldRun, err := workflow.EvaluateJobCondition(job.Condition, ctx)
+	if err != nil {
+		job.Result = simulatedResultError
+		job.Reason = fmt.Sprintf("could not evaluate condition: %v", err)
+		return job
+	}
+	if !shouldRun {
+		job.Result = simulatedResultSkipped
+		job.Reason = skipReason(job, ctx, results)
+		return job
+	}
+
+	job.Result = simulatedResultSuccess
+	switch {
+	case job.Condition != "":
+		job.Reason = "condition evaluated to true"
+	case len(job.Needs) > 0:
+		job.Reason = "all dependencies succeeded"
+	default:
+		job.Reason = "no condition"
+	}
+
+	// Run the steps with a success job status so step-level success() holds
+	ctx.JobStatus = ""
+	ctx.Steps = make(map[string]any)
+	for k, v := range spec.Env {
+		ctx.Env[k] = interpolateExpressions(fmt.Sprint(v), ctx)
+	}
+	for _, step := range spec.Steps {
+		job.Steps = append(job.Steps, simulateStep(name, step, &ctx, opts))
+	}
+
+	if len(spec.Outputs) > 0 {
+		job.Outputs = make(map[string]string, len(spec.Outputs))
+		for key, value := range spec.Outputs {
+			job.Outputs[key] = interpolateExpressions(fmt.Sprint(value), ctx)
+		}
+	}
+	return job
+}
+
+// simulateStep evaluates a step's condition and records the outputs of its stub
+func simulateStep(jobName string, step simulatedStepSpec, ctx *workflow.EvalContext, opts simulationOptions) SimulatedStep {
+	result := SimulatedStep{Name: step.Name, ID: step.ID}
+	if result.Name == "" {
+		result.Name = firstNonEmpty(step.Uses, firstLine(step.Run))
+	}
+
+	stepCtx := *ctx
+	stepCtx.Env = copyAnyMap(ctx.Env)
+	for k, v := range step.Env {
+		stepCtx.Env[k] = interpolateExpressions(fmt.Sprint(v), stepCtx)
+	}
+
+	shouldRun, err := workflow.EvaluateJobCondition(conditionString(step.If), stepCtx)
+	if err != nil {
+		result.Result = simulatedResultError
+		result.Reason = fmt.Sprintf("could not evaluate condition: %v", err)
+		return result
+	}
+	if !shouldRun {
+		result.Result = simulatedResultSkipped
+		result.Reason = "condition evaluated to false: " + conditionString(step.If)
+		if step.ID != "" {
+			ctx.Steps[step.ID] = map[string]any{"outcome": "skipped", "conclusion": "skipped", "outputs": map[string]any{}}
+		}
+		return result
+	}
+
+	result.Result = simulatedResultSuccess
+	if jobName == string(constants.AgentJobName) && isSimulatedEngineStep(step, opts.EngineSteps) {
+		result.Stubbed = true
+		result.Reason = fmt.Sprintf("stub engine emitted %d safe output item(s)", len(opts.AgentOutput.Items))
+	}
+
+	outputs := simulatedStepOutputs(step, stepCtx, opts)
+	if step.ID == "collect_output" && len(opts.AgentOutput.Items) > 0 {
+		ctx.Env["GH_AW_AGENT_OUTPUT"] = simulatedAgentOutputPath
+	}
+	if step.ID != "" {
+		ctx.Steps[step.ID] = map[string]any{"outcome": "success", "conclusion": "success", "outputs": outputs}
+	}
+	return result
+}
+
+// isSimulatedEngineStep reports whether the step executes the agentic engine
+func isSimulatedEngineStep(step simulatedStepSpec, engineSteps map[string]bool) bool {
+	return step.ID == "agentic_execution" || engineSteps[step.Name]
+}
+
+// simulatedStepOutputs returns the outputs a known gh-aw step would produce for the
+// simulated event. Unknown steps produce no outputs.
+func simulatedStepOutputs(step simulatedStepSpec, ctx workflow.EvalContext, opts simulationOptions) map[string]any {
+	switch constants.StepID(step.ID) {
+	case constants.CheckMembershipStepID:
+		return map[string]any{constants.IsTeamMemberOutput: boolString(actorHasRequiredRole(fmt.Sprint(ctx.Env["GH_AW_REQUIRED_ROLES"]), opts.ActorPermission))}
+	case constants.CheckStopTimeStepID:
+		return map[string]any{constants.StopTimeOkOutput: "true"}
+	case constants.CheckSkipIfMatchStepID:
+		return map[string]any{constants.SkipCheckOkOutput: "true"}
+	case constants.CheckSkipIfNoMatchStepID:
+		return map[string]any{constants.SkipNoMatchCheckOkOutput: "true"}
+	case constants.CheckCommandPositionStepID:
+		return map[string]any{constants.CommandPositionOkOutput: "true"}
+	}
+
+	switch step.ID {
+	case "collect_output":
+		outputJSON, _ := json.Marshal(opts.AgentOutput)
+		return map[string]any{
+			"output":       string(outputJSON),
+			"output_types": strings.Join(opts.AgentOutput.outputTypes(), ","),
+			"has_patch":    boolString(opts.AgentOutput.hasPatch()),
+		}
+	case "parse_results":
+		return map[string]any{"success": boolString(!opts.ThreatDetected)}
+	}
+	return map[string]any{}
+}
+
+// actorHasRequiredRole reports whether the actor permission satisfies the role list
+// configured for the membership check
+func actorHasRequiredRole(requiredRoles, permission string) bool {
+	for _, role := range strings.Split(requiredRoles, ",") {
+		role = strings.TrimSpace(role)
+		if role == "all" || role == permission || (role == "maintainer" && permission == "maintain") {
+			return true
+		}
+	}
+	return false
+}
+
+// ancestorStatus computes the status seen by status check functions in a job's
+// condition: failure if any ancestor failed, success if all succeeded, skipped otherwise
+func ancestorStatus(name string, jobs map[string]simulatedJobSpec, results map[string]string) string {
+	status := "success"
+	visited := make(map[string]bool)
+	var visit func(string)
+	visit = func(job string) {
+		for _, need := range jobs[job].needsList() {
+			if visited[need] {
+				continue
+			}
+			visited[need] = true
+			switch results[need] {
+			case simulatedResultError:
+				status = "failure"
+			case simulatedResultSkipped:
+				if status == "success" {
+					status = simulatedResultSkipped
+				}
+			}
+			visit(need)
+		}
+	}
+	visit(name)
+	return status
+}
+
+// skipReason explains why a job was skipped. A condition without status check
+// functions is implicitly guarded by success(), so when it would have passed with
+// all upstream jobs succeeding the skip is attributed to the dependencies.
+func skipReason(job SimulatedJob, ctx workflow.EvalContext, results map[string]string) string {
+	if job.Condition != "" && ctx.JobStatus == "success" {
+		return "condition evaluated to false: " + job.Condition
+	}
+	if job.Condition != "" {
+		ctx.JobStatus = "success"
+		if passes, err := workflow.EvaluateJobCondition(job.Condition, ctx); err != nil || !passes {
+			return "condition evaluated to false: " + job.Condition
+		}
+	}
+	for _, need := range job.Needs {
+		if results[need] != simulatedResultSuccess {
+			return fmt.Sprintf("dependency '%s' did not succeed (%s)", need, results[need])
+		}
+	}
+	return "an upstream job did not succeed"
+}
+
+// interpolateExpressions replaces every ${{ }} template in value with its evaluated result
+func interpolateExpressions(value string, ctx workflow.EvalContext) string {
+	return expressionTemplatePattern.ReplaceAllStringFunc(value, func(match string) string {
+		expr := expressionTemplatePattern.FindStringSubmatch(match)[1]
+		node, err := workflow.ParseExpression(expr)
+		if err != nil {
+			return ""
+		}
+		evaluated, err := workflow.EvaluateExpressionString(node, ctx)
+		if err != nil {
+			simulateRunnerLog.Printf("Could not interpolate %q: %v", expr, err)
+			return ""
+		}
+		return evaluated
+	})
+}
+
+// conditionString normalizes an `if:` value read from YAML into a condition string
+func conditionString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func copyAnyMap(m map[string]any) map[string]any {
+	result := make(map[string]any, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
@@ -24,9 +24,11 @@ var trialLog = logger.New("cli:trial_command")
 
 // WorkflowTrialResult represents the result of running a single workflow trial
 type WorkflowTrialResult struct {
-	WorkflowName string         `json:"workflow_name"`
-	RunID        string         `json:"run_id"`
-	SafeOutputs  map[string]any `json:"safe_outputs"`
+	WorkflowName string           `json:"workflow_name"`
+	Engine       string           `json:"engine,omitempty"`
+	RunID        string           `json:"run_id"`
+	SafeOutputs  map[string]any   `json:"safe_outputs"`
+	Metrics      *TrialRunMetrics `json:"metrics,omitempty"`
 	//AgentStdioLogs      []string               `json:"agent_stdio_logs,omitempty"`
 	AgenticRunInfo      map[string]any `json:"agentic_run_info,omitempty"`
 	AdditionalArtifacts map[string]any `json:"additional_artifacts,omitempty"`
@@ -59,6 +61,7 @@ type TrialOptions struct {
 	RepeatCount    int
 	AutoMergePRs   bool
 	EngineOverride string
+	Engines        []string // Run each workflow with one agent job per engine in a single run and compare the results
 	AppendText     string
 	PushSecrets    bool
 	Verbose        bool
@@ -97,6 +100,9 @@ Repeat and cleanup examples:
   ` + string(constants.CLIExtensionPrefix) + ` trial githubnext/agentics/my-workflow --quiet --host-repo my-trial # Custom host repo
   ` + string(constants.CLIExtensionPrefix) + ` trial githubnext/agentics/my-workflow --dry-run                 # Show what would be done without changes
 
+Engine comparison examples:
+  ` + string(constants.CLIExtensionPrefix) + ` trial githubnext/agentics/my-workflow --engines claude,codex,copilot  # Run all engines in one run and compare
+
 Auto-merge examples:
   ` + string(constants.CLIExtensionPrefix) + ` trial githubnext/agentics/my-workflow --auto-merge-prs          # Auto-merge any PRs created during trial
 
@@ -110,6 +116,12 @@ Repository modes:
 - --repo REPO: Runs directly in the specified repository (no simulation, workflows installed and executed in REPO)
 - --clone-repo REPO: Clones the specified repository's contents into the trial repository before execution (useful for testing against actual repository state)
 
+With --engines, each workflow is compiled once per engine with trial (staged) safe outputs and the
+results are combined into a single workflow with one agent job per engine, so that one run gives
+every engine the same triggering event and repository state. A comparison of turns, tokens, cost,
+tool calls, agent job duration and the safe outputs produced by each engine is displayed and saved
+to trials/WORKFLOW-engines-TARGET.DATETIME-ID.json.
+
 All workflows must support workflow_dispatch trigger to be used in trial mode.
 The host repository will be created as private and kept by default unless --delete-host-repo-after is specified.
 Trial results are saved both locally (in trials/ directory) and in the host repository for future reference.`,
@@ -129,6 +141,7 @@ Trial results are saved both locally (in trials/ directory) and in the host repo
 			repeatCount, _ := cmd.Flags().GetInt("repeat")
 			autoMergePRs, _ := cmd.Flags().GetBool("auto-merge-prs")
 			engineOverride, _ := cmd.Flags().GetString("engine")
+			engineList, _ := cmd.Flags().GetStringSlice("engines")
 			appendText, _ := cmd.Flags().GetString("append")
 			pushSecrets, _ := cmd.Flags().GetBool("use-local-secrets")
 			verbose, _ := cmd.Root().PersistentFlags().GetBool("verbose")
@@ -136,6 +149,10 @@ Trial results are saved both locally (in trials/ directory) and in the host repo
 			if err := validateEngine(engineOverride); err != nil {
 				return err
 			}
+			engines, err := parseTrialEngines(engineList, validateEngine)
+			if err != nil {
+				return err
+			}
 			// If --repo was used instead of --host-repo, use its value
 			if repoSpec != "" {
 				hostRepoSpec = repoSpec
@@ -156,6 +173,7 @@ Trial results are saved both locally (in trials/ directory) and in the host repo
 				RepeatCount:    repeatCount,
 				AutoMergePRs:   autoMergePRs,
 				EngineOverride: engineOverride,
+				Engines:        engines,
 				AppendText:     appendText,
 				PushSecrets:    pushSecrets,
 				Verbose:        verbose,
@@ -190,10 +208,12 @@ Trial results are saved both locally (in trials/ directory) and in the host repo
 	cmd.Flags().Int("repeat", 0, "Number of times to repeat running workflows (0 = run once)")
 	cmd.Flags().Bool("auto-merge-prs", false, "Auto-merge any pull requests created during trial execution")
 	addEngineFlag(cmd)
+	cmd.Flags().StringSlice("engines", nil, "Run each workflow with one agent job per engine in a single run and compare the results (comma-separated, e.g., claude,codex,copilot)")
 	cmd.Flags().String("append", "", "Append extra content to the end of agentic workflow on installation")
 	cmd.Flags().Bool("use-local-secrets", false, "Use local environment API key secrets for trial execution (pushes and cleans up secrets in repository)")
 	cmd.MarkFlagsMutuallyExclusive("host-repo", "repo")
 	cmd.MarkFlagsMutuallyExclusive("logical-repo", "clone-repo")
+	cmd.MarkFlagsMutuallyExclusive("engine", "engines")
 
 	return cmd
 }
@@ -435,27 +455,67 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 
 		// Step 5: Run trials for each workflow
 		var workflowResults []WorkflowTrialResult
+		var resultFiles []string
+		sanitizedTargetRepo := repoutil.SanitizeForFilename(targetRepoForFilename)
+
+		// In engine matrix mode every workflow runs with one agent job per engine; otherwise with the engine override
+		engineMatrix := len(opts.Engines) > 0
+		engines := []string{opts.EngineOverride}
+		if engineMatrix {
+			engines = opts.Engines
+		}
 
 		for i, parsedSpec := range parsedSpecs {
 			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Running trial for workflow: %s ===", parsedSpec.WorkflowName)))
 
-			// Install workflow with trial mode compilation
-			if err := installWorkflowInTrialMode(ctx, tempDir, parsedSpec, logicalRepoSlug, cloneRepoSlug, hostRepoSlug, secretTracker, opts.EngineOverride, opts.AppendText, opts.PushSecrets, directTrialMode, opts.Verbose); err != nil {
-				return fmt.Errorf("failed to install workflow '%s' in trial mode: %w", parsedSpec.WorkflowName, err)
-			}
+			// Install workflow with trial mode compilation. In engine matrix mode the workflow is
+			// compiled once per engine and the lock files are merged into one workflow with a copy
+			// of the jobs per engine, so that a single run gives every engine the same event and state.
+			lockPath := filepath.Join(tempDir, ".github/workflows", parsedSpec.WorkflowName+".lock.yml")
+			lockFiles := make(map[string][]byte)
+			for j, engine := range engines {
+				if engineMatrix {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("--- Compiling %s with engine: %s ---", parsedSpec.WorkflowName, engine)))
+				}
 
-			// Display workflow description if present
-			workflowPath := filepath.Join(tempDir, ".github/workflows", parsedSpec.WorkflowName+".md")
-			if description := ExtractWorkflowDescriptionFromFile(workflowPath); description != "" {
-				fmt.Fprintln(os.Stderr, "")
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(description))
-				fmt.Fprintln(os.Stderr, "")
+				if err := installWorkflowInTrialMode(ctx, tempDir, parsedSpec, logicalRepoSlug, cloneRepoSlug, hostRepoSlug, secretTracker, engine, opts.AppendText, opts.PushSecrets, directTrialMode, opts.Verbose); err != nil {
+					return fmt.Errorf("failed to install workflow '%s' in trial mode: %w", parsedSpec.WorkflowName, err)
+				}
+
+				// Display workflow description if present
+				workflowPath := filepath.Join(tempDir, ".github/workflows", parsedSpec.WorkflowName+".md")
+				if description := ExtractWorkflowDescriptionFromFile(workflowPath); j == 0 && description != "" {
+					fmt.Fprintln(os.Stderr, "")
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(description))
+					fmt.Fprintln(os.Stderr, "")
+				}
+
+				// Add user's PAT as repository secret (only once)
+				if i == 0 && j == 0 && opts.PushSecrets {
+					if err := addGitHubTokenSecret(hostRepoSlug, secretTracker, opts.Verbose); err != nil {
+						return fmt.Errorf("failed to add GitHub token secret: %w", err)
+					}
+				}
+
+				if engineMatrix {
+					content, err := os.ReadFile(lockPath)
+					if err != nil {
+						return fmt.Errorf("failed to read lock file compiled for engine %s: %w", engine, err)
+					}
+					lockFiles[engine] = content
+				}
 			}
 
-			// Add user's PAT as repository secret (only once)
-			if i == 0 && opts.PushSecrets {
-				if err := addGitHubTokenSecret(hostRepoSlug, secretTracker, opts.Verbose); err != nil {
-					return fmt.Errorf("failed to add GitHub token secret: %w", err)
+			if engineMatrix {
+				merged, err := mergeEngineLockFiles(engines, lockFiles)
+				if err != nil {
+					return fmt.Errorf("failed to build engine matrix workflow for '%s': %w", parsedSpec.WorkflowName, err)
+				}
+				if err := os.WriteFile(lockPath, merged, 0644); err != nil {
+					return fmt.Errorf("failed to write engine matrix workflow: %w", err)
+				}
+				if err := commitAndPushWorkflow(tempDir, parsedSpec.WorkflowName+" (engines: "+strings.Join(engines, ", ")+")", opts.Verbose); err != nil {
+					return fmt.Errorf("failed to commit and push engine matrix workflow: %w", err)
 				}
 			}
 
@@ -481,50 +541,86 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 				}
 			}
 
-			// Download and process all artifacts
-			artifacts, err := downloadAllArtifacts(hostRepoSlug, runID, opts.Verbose)
-			if err != nil {
-				return fmt.Errorf("failed to download artifacts for '%s': %w", parsedSpec.WorkflowName, err)
-			}
+			var engineResults []WorkflowTrialResult
+			for _, engine := range engines {
+				runLabel := parsedSpec.WorkflowName
+				artifactPrefix := ""
+				if engineMatrix {
+					runLabel = fmt.Sprintf("%s (engine: %s)", parsedSpec.WorkflowName, engine)
+					artifactPrefix = engineMatrixPrefix(engine)
+				}
 
-			// Save individual workflow results
-			result := WorkflowTrialResult{
-				WorkflowName: parsedSpec.WorkflowName,
-				RunID:        runID,
-				SafeOutputs:  artifacts.SafeOutputs,
-				//AgentStdioLogs:      artifacts.AgentStdioLogs,
-				AgenticRunInfo:      artifacts.AgenticRunInfo,
-				AdditionalArtifacts: artifacts.AdditionalArtifacts,
-				Timestamp:           time.Now(),
-			}
-			workflowResults = append(workflowResults, result)
+				// Download and process all artifacts
+				artifacts, err := downloadAllArtifacts(hostRepoSlug, runID, artifactPrefix, opts.Verbose)
+				if err != nil {
+					return fmt.Errorf("failed to download artifacts for '%s': %w", runLabel, err)
+				}
 
-			// Save individual trial file
-			sanitizedTargetRepo := repoutil.SanitizeForFilename(targetRepoForFilename)
-			individualFilename := fmt.Sprintf("trials/%s-%s.%s.json", parsedSpec.WorkflowName, sanitizedTargetRepo, dateTimeID)
-			if err := saveTrialResult(individualFilename, result, opts.Verbose); err != nil {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save individual trial result: %v", err)))
-			}
+				// Collect run metrics so engines can be compared
+				var metrics *TrialRunMetrics
+				if engineMatrix {
+					metrics, err = collectTrialRunMetrics(hostRepoSlug, runID, artifactPrefix, opts.Verbose)
+					if err != nil {
+						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to collect metrics for %s: %v", runLabel, err)))
+					}
+				}
 
-			// Display safe outputs to stdout
-			if len(artifacts.SafeOutputs) > 0 {
-				outputBytes, _ := json.MarshalIndent(artifacts.SafeOutputs, "", "  ")
-				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("=== Safe Outputs from %s ===", parsedSpec.WorkflowName)))
-				fmt.Println(string(outputBytes))
-				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("=== End of Safe Outputs ==="))
-			} else {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== No Safe Outputs Generated by %s ===", parsedSpec.WorkflowName)))
-			}
+				// Save individual workflow results
+				result := WorkflowTrialResult{
+					WorkflowName: parsedSpec.WorkflowName,
+					Engine:       engine,
+					RunID:        runID,
+					SafeOutputs:  artifacts.SafeOutputs,
+					Metrics:      metrics,
+					//AgentStdioLogs:      artifacts.AgentStdioLogs,
+					AgenticRunInfo:      artifacts.AgenticRunInfo,
+					AdditionalArtifacts: artifacts.AdditionalArtifacts,
+					Timestamp:           time.Now(),
+				}
+				workflowResults = append(workflowResults, result)
+				engineResults = append(engineResults, result)
+
+				// Save individual trial file
+				individualFilename := fmt.Sprintf("trials/%s-%s.%s.json", trialResultName(parsedSpec.WorkflowName, engine, engineMatrix), sanitizedTargetRepo, dateTimeID)
+				if err := saveTrialResult(individualFilename, result, opts.Verbose); err != nil {
+					fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save individual trial result: %v", err)))
+				} else {
+					resultFiles = append(resultFiles, individualFilename)
+				}
+
+				// Display safe outputs to stdout
+				if len(artifacts.SafeOutputs) > 0 {
+					outputBytes, _ := json.MarshalIndent(artifacts.SafeOutputs, "", "  ")
+					fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("=== Safe Outputs from %s ===", runLabel)))
+					fmt.Println(string(outputBytes))
+					fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("=== End of Safe Outputs ==="))
+				} else {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== No Safe Outputs Generated by %s ===", runLabel)))
+				}
 
-			// Display additional artifact information if available
-			// if len(artifacts.AgentStdioLogs) > 0 {
-			// 	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Agent Stdio Logs Available from %s (%d files) ===", parsedSpec.WorkflowName, len(artifacts.AgentStdioLogs))))
-			// }
-			if len(artifacts.AgenticRunInfo) > 0 {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Agentic Run Information Available from %s ===", parsedSpec.WorkflowName)))
+				// Display additional artifact information if available
+				// if len(artifacts.AgentStdioLogs) > 0 {
+				// 	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Agent Stdio Logs Available from %s (%d files) ===", parsedSpec.WorkflowName, len(artifacts.AgentStdioLogs))))
+				// }
+				if len(artifacts.AgenticRunInfo) > 0 {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Agentic Run Information Available from %s ===", runLabel)))
+				}
+				if len(artifacts.AdditionalArtifacts) > 0 {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Additional Artifacts Available from %s (%d files) ===", runLabel, len(artifacts.AdditionalArtifacts))))
+				}
 			}
-			if len(artifacts.AdditionalArtifacts) > 0 {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Additional Artifacts Available from %s (%d files) ===", parsedSpec.WorkflowName, len(artifacts.AdditionalArtifacts))))
+
+			// Compare the engines side by side
+			if engineMatrix {
+				comparison := buildEngineComparison(parsedSpec.WorkflowName, engineResults)
+				displayEngineComparison(comparison)
+				comparisonFilename := fmt.Sprintf("trials/%s-engines-%s.%s.json", parsedSpec.WorkflowName, sanitizedTargetRepo, dateTimeID)
+				if err := saveTrialResult(comparisonFilename, comparison, opts.Verbose); err != nil {
+					fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save engine comparison: %v", err)))
+				} else {
+					resultFiles = append(resultFiles, comparisonFilename)
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Engine comparison saved to: %s", comparisonFilename)))
+				}
 			}
 
 			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Trial completed for workflow: %s", parsedSpec.WorkflowName)))
@@ -537,7 +633,6 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 				workflowNames[i] = spec.WorkflowName
 			}
 			workflowNamesStr := strings.Join(workflowNames, "-")
-			sanitizedTargetRepo := repoutil.SanitizeForFilename(targetRepoForFilename)
 			combinedFilename := fmt.Sprintf("trials/%s-%s.%s.json", workflowNamesStr, sanitizedTargetRepo, dateTimeID)
 			combinedResult := CombinedTrialResult{
 				WorkflowNames: workflowNames,
@@ -546,6 +641,8 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 			}
 			if err := saveTrialResult(combinedFilename, combinedResult, opts.Verbose); err != nil {
 				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save combined trial result: %v", err)))
+			} else {
+				resultFiles = append(resultFiles, combinedFilename)
 			}
 			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Combined results saved to: %s", combinedFilename)))
 		}
@@ -555,7 +652,7 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 		for i, spec := range parsedSpecs {
 			workflowNames[i] = spec.WorkflowName
 		}
-		if err := copyTrialResultsToHostRepo(tempDir, dateTimeID, workflowNames, targetRepoForFilename, opts.Verbose); err != nil {
+		if err := copyTrialResultsToHostRepo(tempDir, dateTimeID, workflowNames, resultFiles, opts.Verbose); err != nil {
 			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to copy trial results to repository: %v", err)))
 		}
 
@@ -911,8 +1008,8 @@ func saveTrialResult(filename string, result any, verbose bool) error {
 	return nil
 }
 
-// copyTrialResultsToHostRepo copies trial result files to the host repository and commits them
-func copyTrialResultsToHostRepo(tempDir, dateTimeID string, workflowNames []string, targetRepoSlug string, verbose bool) error {
+// copyTrialResultsToHostRepo copies the saved trial result files to the host repository and commits them
+func copyTrialResultsToHostRepo(tempDir, dateTimeID string, workflowNames []string, resultFiles []string, verbose bool) error {
 	if verbose {
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Copying trial results to host repository"))
 	}
@@ -923,11 +1020,9 @@ func copyTrialResultsToHostRepo(tempDir, dateTimeID string, workflowNames []stri
 		return fmt.Errorf("failed to create trials directory in repository: %w", err)
 	}
 
-	// Copy individual workflow result files
-	sanitizedTargetRepo := repoutil.SanitizeForFilename(targetRepoSlug)
-	for _, workflowName := range workflowNames {
-		sourceFile := fmt.Sprintf("trials/%s-%s.%s.json", workflowName, sanitizedTargetRepo, dateTimeID)
-		destFile := filepath.Join(trialsDir, fmt.Sprintf("%s-%s.%s.json", workflowName, sanitizedTargetRepo, dateTimeID))
+	// Copy individual, combined and engine comparison result files
+	for _, sourceFile := range resultFiles {
+		destFile := filepath.Join(trialsDir, filepath.Base(sourceFile))
 
 		if err := fileutil.CopyFile(sourceFile, destFile); err != nil {
 			if verbose {
@@ -941,21 +1036,6 @@ func copyTrialResultsToHostRepo(tempDir, dateTimeID string, workflowNames []stri
 		}
 	}
 
-	// Copy combined results file if it exists (for multi-workflow trials)
-	if len(workflowNames) > 1 {
-		workflowNamesStr := strings.Join(workflowNames, "-")
-		combinedSourceFile := fmt.Sprintf("trials/%s-%s.%s.json", workflowNamesStr, sanitizedTargetRepo, dateTimeID)
-		combinedDestFile := filepath.Join(trialsDir, fmt.Sprintf("%s-%s.%s.json", workflowNamesStr, sanitizedTargetRepo, dateTimeID))
-
-		if err := fileutil.CopyFile(combinedSourceFile, combinedDestFile); err != nil {
-			if verbose {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to copy combined results: %v", err)))
-			}
-		} else if verbose {
-			fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Copied %s to repository", combinedSourceFile)))
-		}
-	}
-
 	// Change to temp directory to commit the changes
 	originalDir, err := os.Getwd()
 	if err != nil {
@@ -0,0 +1,387 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/sliceutil"
+	"github.com/github/gh-aw/pkg/timeutil"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var trialEngineMatrixLog = logger.New("cli:trial_engine_matrix")
+
+// TrialRunMetrics holds the metrics of a trial run used to compare engines
+type TrialRunMetrics struct {
+	Conclusion    string        `json:"conclusion,omitempty"`
+	Duration      time.Duration `json:"duration,omitempty"`
+	Turns         int           `json:"turns"`
+	TokenUsage    int           `json:"token_usage"`
+	EstimatedCost float64       `json:"estimated_cost"`
+	ToolCalls     int           `json:"tool_calls"`
+}
+
+// EngineComparisonRow is one engine's line in the engine comparison table
+type EngineComparisonRow struct {
+	Engine        string  `json:"engine" console:"header:Engine"`
+	RunID         string  `json:"run_id" console:"header:Run ID"`
+	Conclusion    string  `json:"conclusion,omitempty" console:"header:Conclusion,omitempty"`
+	Duration      string  `json:"duration,omitempty" console:"header:Duration,omitempty"`
+	Turns         int     `json:"turns" console:"header:Turns"`
+	TokenUsage    int     `json:"token_usage" console:"header:Tokens,format:number"`
+	EstimatedCost float64 `json:"estimated_cost" console:"header:Cost ($),format:cost"`
+	ToolCalls     int     `json:"tool_calls" console:"header:Tool Calls"`
+	SafeOutputs   string  `json:"safe_outputs" console:"header:Safe Outputs,maxlen:60"`
+}
+
+// EngineComparisonReport compares the trial runs of one workflow across engines
+type EngineComparisonReport struct {
+	WorkflowName string                    `json:"workflow_name"`
+	Engines      []string                  `json:"engines"`
+	Rows         []EngineComparisonRow     `json:"comparison"`
+	SafeOutputs  map[string]map[string]any `json:"safe_outputs"` // Safe outputs produced by each engine, keyed by engine
+	Timestamp    time.Time                 `json:"timestamp"`
+}
+
+// parseTrialEngines splits and validates the --engines flag value, dropping duplicates
+func parseTrialEngines(engines []string, validateEngine func(string) error) ([]string, error) {
+	var result []string
+	for _, engine := range engines {
+		engine = strings.TrimSpace(engine)
+		if engine == "" || sliceutil.Contains(result, engine) {
+			continue
+		}
+		if err := validateEngine(engine); err != nil {
+			return nil, err
+		}
+		result = append(result, engine)
+	}
+	if len(engines) > 0 && len(result) < 2 {
+		return nil, fmt.Errorf("--engines requires at least two different engines, got %d", len(result))
+	}
+	return result, nil
+}
+
+// trialResultName returns the name used for the result file of a trial run. In engine matrix
+// mode every engine gets its own result file.
+func trialResultName(workflowName, engine string, engineMatrix bool) string {
+	if engineMatrix {
+		return fmt.Sprintf("%s-%s", workflowName, engine)
+	}
+	return workflowName
+}
+
+// engineMatrixPrefix returns the prefix of the jobs and artifacts of an engine in the
+// engine matrix workflow
+func engineMatrixPrefix(engine string) string {
+	return engineMatrixPrefixPattern.ReplaceAllString(engine, "_") + "_"
+}
+
+var engineMatrixPrefixPattern = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// needsReferencePattern matches references to the outputs or result of a job in expressions
+var needsReferencePattern = regexp.MustCompile(`\bneeds\.([A-Za-z_][A-Za-z0-9_-]*)`)
+
+// mergeEngineLockFiles combines the lock files compiled for each engine into a single
+// workflow with one copy of the jobs per engine, so that one run gives every engine the
+// same triggering event and repository state. Job IDs and artifact names are prefixed
+// with the engine (see engineMatrixPrefix) so the copies do not collide. Workflow-level
+// settings such as the triggers are taken from the first engine's lock file.
+func mergeEngineLockFiles(engines []string, lockFiles map[string][]byte) ([]byte, error) {
+	trialEngineMatrixLog.Printf("Merging lock files of %d engines", len(engines))
+
+	var merged yaml.MapSlice
+	var mergedJobs yaml.MapSlice
+	for i, engine := range engines {
+		var lock yaml.MapSlice
+		if err := yaml.UnmarshalWithOptions(lockFiles[engine], &lock, yaml.UseOrderedMap()); err != nil {
+			return nil, fmt.Errorf("failed to parse lock file compiled for engine %s: %w", engine, err)
+		}
+		var jobs yaml.MapSlice
+		for _, item := range lock {
+			if item.Key == "jobs" {
+				jobs, _ = item.Value.(yaml.MapSlice)
+			} else if i == 0 {
+				merged = append(merged, item)
+			}
+		}
+		if len(jobs) == 0 {
+			return nil, fmt.Errorf("lock file compiled for engine %s has no jobs", engine)
+		}
+		mergedJobs = append(mergedJobs, prefixEngineJobs(engineMatrixPrefix(engine), jobs)...)
+	}
+	merged = append(merged, yaml.MapItem{Key: "jobs", Value: mergedJobs})
+
+	content, err := yaml.MarshalWithOptions(merged, workflow.DefaultMarshalOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal engine matrix workflow: %w", err)
+	}
+	header := fmt.Sprintf("# Engine matrix trial workflow: one copy of the jobs per engine (%s)\n", strings.Join(engines, ", "))
+	return append([]byte(header), content...), nil
+}
+
+// prefixEngineJobs prefixes the IDs of the jobs, the jobs they need and the artifacts they
+// upload and download
+func prefixEngineJobs(prefix string, jobs yaml.MapSlice) yaml.MapSlice {
+	jobIDs := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		jobIDs[fmt.Sprint(job.Key)] = true
+	}
+	prefixJobID := func(id string) string {
+		if jobIDs[id] {
+			return prefix + id
+		}
+		return id
+	}
+
+	var result yaml.MapSlice
+	for _, job := range jobs {
+		body, _ := job.Value.(yaml.MapSlice)
+		var prefixed yaml.MapSlice
+		for _, item := range body {
+			switch {
+			case item.Key == "needs":
+				switch needs := item.Value.(type) {
+				case string:
+					item.Value = prefixJobID(needs)
+				case []any:
+					renamed := make([]any, len(needs))
+					for i, need := range needs {
+						renamed[i] = prefixJobID(fmt.Sprint(need))
+					}
+					item.Value = renamed
+				}
+			case item.Key == "steps":
+				item.Value = prefixStepArtifacts(prefix, rewriteNeedsReferences(item.Value, prefixJobID))
+			default:
+				item.Value = rewriteNeedsReferences(item.Value, prefixJobID)
+			}
+			prefixed = append(prefixed, item)
+		}
+		result = append(result, yaml.MapItem{Key: prefix + fmt.Sprint(job.Key), Value: prefixed})
+	}
+	return result
+}
+
+// rewriteNeedsReferences renames the jobs referenced as needs.<job> in every string of a value
+func rewriteNeedsReferences(value any, prefixJobID func(string) string) any {
+	switch v := value.(type) {
+	case string:
+		return needsReferencePattern.ReplaceAllStringFunc(v, func(match string) string {
+			return "needs." + prefixJobID(strings.TrimPrefix(match, "needs."))
+		})
+	case yaml.MapSlice:
+		result := make(yaml.MapSlice, len(v))
+		for i, item := range v {
+			result[i] = yaml.MapItem{Key: item.Key, Value: rewriteNeedsReferences(item.Value, prefixJobID)}
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = rewriteNeedsReferences(item, prefixJobID)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// prefixStepArtifacts prefixes the artifact names of upload-artifact and download-artifact steps
+func prefixStepArtifacts(prefix string, steps any) any {
+	list, ok := steps.([]any)
+	if !ok {
+		return steps
+	}
+	for _, rawStep := range list {
+		step, ok := rawStep.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+		uses := ""
+		for _, item := range step {
+			if item.Key == "uses" {
+				uses = fmt.Sprint(item.Value)
+			}
+		}
+		if !strings.HasPrefix(uses, "actions/upload-artifact@") && !strings.HasPrefix(uses, "actions/download-artifact@") {
+			continue
+		}
+		for i, item := range step {
+			with, ok := item.Value.(yaml.MapSlice)
+			if item.Key != "with" || !ok {
+				continue
+			}
+			for j, input := range with {
+				if input.Key == "name" || input.Key == "pattern" {
+					with[j].Value = prefix + fmt.Sprint(input.Value)
+				}
+			}
+			step[i].Value = with
+		}
+	}
+	return list
+}
+
+// fetchTrialJob returns the job of a trial run with the given name
+func fetchTrialJob(hostRepoSlug, runID, jobName string) (*JobInfo, error) {
+	output, err := workflow.RunGHCombined("Fetching job details...", "api", "--paginate",
+		fmt.Sprintf("repos/%s/actions/runs/%s/jobs", hostRepoSlug, runID),
+		"--jq", ".jobs[] | {name: .name, status: .status, conclusion: .conclusion, started_at: .started_at, completed_at: .completed_at}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jobs of run %s: %s", runID, strings.TrimSpace(string(output)))
+	}
+	for line := range strings.SplitSeq(strings.TrimSpace(string(output)), "\n") {
+		var job JobInfo
+		if err := json.Unmarshal([]byte(line), &job); err == nil && job.Name == jobName {
+			return &job, nil
+		}
+	}
+	return nil, fmt.Errorf("run %s has no job %s", runID, jobName)
+}
+
+// collectTrialRunMetrics downloads the artifacts of a completed trial run and extracts its
+// metrics. In engine matrix mode artifactPrefix selects the engine's artifacts, and the
+// conclusion and duration are those of the engine's agent job.
+func collectTrialRunMetrics(hostRepoSlug, runID, artifactPrefix string, verbose bool) (*TrialRunMetrics, error) {
+	trialEngineMatrixLog.Printf("Collecting metrics for trial run %s in %s (prefix=%q)", runID, hostRepoSlug, artifactPrefix)
+
+	databaseID, err := strconv.ParseInt(runID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run ID %q: %w", runID, err)
+	}
+
+	metrics := &TrialRunMetrics{}
+	if artifactPrefix != "" {
+		if job, err := fetchTrialJob(hostRepoSlug, runID, artifactPrefix+string(constants.AgentJobName)); err == nil {
+			metrics.Conclusion = job.Conclusion
+			if !job.StartedAt.IsZero() && !job.CompletedAt.IsZero() {
+				metrics.Duration = job.CompletedAt.Sub(job.StartedAt)
+			}
+		} else if verbose {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(err.Error()))
+		}
+	} else {
+		owner, repo, _ := strings.Cut(hostRepoSlug, "/")
+		if run, err := fetchWorkflowRunMetadata(databaseID, owner, repo, "", verbose); err == nil {
+			metrics.Conclusion = run.Conclusion
+			if !run.StartedAt.IsZero() && !run.UpdatedAt.IsZero() {
+				metrics.Duration = run.UpdatedAt.Sub(run.StartedAt)
+			}
+		} else if verbose {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to fetch metadata for run %s: %v", runID, err)))
+		}
+	}
+
+	tempDir, err := os.MkdirTemp("", "trial-metrics-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if output, err := downloadTrialRunArtifacts("Downloading run logs...", hostRepoSlug, runID, artifactPrefix, tempDir); err != nil {
+		if verbose {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No artifacts found for run %s: %s", runID, string(output))))
+		}
+		return metrics, nil
+	}
+
+	// Use the same artifact layout as the logs command so metrics extraction finds the agent logs
+	if err := flattenSingleFileArtifacts(tempDir, verbose); err != nil {
+		return metrics, fmt.Errorf("failed to flatten artifacts: %w", err)
+	}
+	if err := flattenUnifiedArtifact(tempDir, verbose); err != nil {
+		return metrics, fmt.Errorf("failed to flatten unified artifact: %w", err)
+	}
+	if err := flattenAgentOutputsArtifact(tempDir, verbose); err != nil {
+		return metrics, fmt.Errorf("failed to flatten agent_outputs artifact: %w", err)
+	}
+
+	logMetrics, err := extractLogMetrics(tempDir, verbose)
+	if err != nil {
+		return metrics, fmt.Errorf("failed to extract log metrics: %w", err)
+	}
+	metrics.Turns = logMetrics.Turns
+	metrics.TokenUsage = logMetrics.TokenUsage
+	metrics.EstimatedCost = logMetrics.EstimatedCost
+	for _, toolCall := range logMetrics.ToolCalls {
+		metrics.ToolCalls += toolCall.CallCount
+	}
+	return metrics, nil
+}
+
+// buildEngineComparison builds the side-by-side comparison of the trial runs of one workflow
+func buildEngineComparison(workflowName string, results []WorkflowTrialResult) EngineComparisonReport {
+	report := EngineComparisonReport{
+		WorkflowName: workflowName,
+		SafeOutputs:  make(map[string]map[string]any),
+		Timestamp:    time.Now(),
+	}
+	for _, result := range results {
+		report.Engines = append(report.Engines, result.Engine)
+		report.SafeOutputs[result.Engine] = result.SafeOutputs
+
+		row := EngineComparisonRow{
+			Engine:      result.Engine,
+			RunID:       result.RunID,
+			SafeOutputs: summarizeSafeOutputItems(result.SafeOutputs),
+		}
+		if m := result.Metrics; m != nil {
+			row.Conclusion = m.Conclusion
+			if m.Duration > 0 {
+				row.Duration = timeutil.FormatDuration(m.Duration)
+			}
+			row.Turns = m.Turns
+			row.TokenUsage = m.TokenUsage
+			row.EstimatedCost = m.EstimatedCost
+			row.ToolCalls = m.ToolCalls
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report
+}
+
+// summarizeSafeOutputItems counts the safe output items of an agent output by type,
+// e.g. "add_comment: 1, create_issue: 2"
+func summarizeSafeOutputItems(safeOutputs map[string]any) string {
+	items, _ := safeOutputs["items"].([]any)
+	counts := make(map[string]int)
+	for _, item := range items {
+		if itemMap, ok := item.(map[string]any); ok {
+			if itemType, ok := itemMap["type"].(string); ok {
+				counts[itemType]++
+			}
+		}
+	}
+	if len(counts) == 0 {
+		return "none"
+	}
+
+	types := make([]string, 0, len(counts))
+	for itemType := range counts {
+		types = append(types, itemType)
+	}
+	sort.Strings(types)
+	parts := make([]string, 0, len(types))
+	for _, itemType := range types {
+		parts = append(parts, fmt.Sprintf("%s: %d", itemType, counts[itemType]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// displayEngineComparison renders the engine comparison table to stderr
+func displayEngineComparison(report EngineComparisonReport) {
+	fmt.Fprintln(os.Stderr, console.FormatSectionHeader(fmt.Sprintf("Engine Comparison: %s", report.WorkflowName)))
+	fmt.Fprint(os.Stderr, console.RenderStruct(report.Rows))
+}
@@ -0,0 +1,191 @@
+//go:build !integration
+
+package cli
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrialEngines(t *testing.T) {
+	validateEngine := func(engine string) error {
+		if engine == "unknown" {
+			return fmt.Errorf("invalid engine value '%s'", engine)
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		engines []string
+		want    []string
+		wantErr string
+	}{
+		{name: "not set", engines: nil, want: nil},
+		{name: "multiple engines", engines: []string{"claude", " codex", "copilot"}, want: []string{"claude", "codex", "copilot"}},
+		{name: "duplicates dropped", engines: []string{"claude", "codex", "claude"}, want: []string{"claude", "codex"}},
+		{name: "single engine", engines: []string{"claude", "claude"}, wantErr: "at least two different engines"},
+		{name: "invalid engine", engines: []string{"claude", "unknown"}, wantErr: "invalid engine value 'unknown'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTrialEngines(tt.engines, validateEngine)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTrialResultName(t *testing.T) {
+	assert.Equal(t, "daily-plan", trialResultName("daily-plan", "", false))
+	assert.Equal(t, "daily-plan", trialResultName("daily-plan", "codex", false))
+	assert.Equal(t, "daily-plan-codex", trialResultName("daily-plan", "codex", true))
+}
+
+func TestSummarizeSafeOutputItems(t *testing.T) {
+	assert.Equal(t, "none", summarizeSafeOutputItems(nil))
+	assert.Equal(t, "none", summarizeSafeOutputItems(map[string]any{"items": []any{}}))
+
+	safeOutputs := map[string]any{
+		"items": []any{
+			map[string]any{"type": "create_issue", "title": "First"},
+			map[string]any{"type": "add_comment", "body": "Hello"},
+			map[string]any{"type": "create_issue", "title": "Second"},
+		},
+	}
+	assert.Equal(t, "add_comment: 1, create_issue: 2", summarizeSafeOutputItems(safeOutputs))
+}
+
+func TestBuildEngineComparison(t *testing.T) {
+	claudeOutputs := map[string]any{"items": []any{map[string]any{"type": "create_issue"}}}
+	results := []WorkflowTrialResult{
+		{
+			WorkflowName: "daily-plan",
+			Engine:       "claude",
+			RunID:        "101",
+			SafeOutputs:  claudeOutputs,
+			Metrics: &TrialRunMetrics{
+				Conclusion:    "success",
+				Duration:      90 * time.Second,
+				Turns:         12,
+				TokenUsage:    45000,
+				EstimatedCost: 0.42,
+				ToolCalls:     18,
+			},
+		},
+		{
+			WorkflowName: "daily-plan",
+			Engine:       "codex",
+			RunID:        "102",
+		},
+	}
+
+	report := buildEngineComparison("daily-plan", results)
+	assert.Equal(t, "daily-plan", report.WorkflowName)
+	assert.Equal(t, []string{"claude", "codex"}, report.Engines)
+	assert.Equal(t, claudeOutputs, report.SafeOutputs["claude"], "safe outputs should be kept side by side per engine")
+
+	require.Len(t, report.Rows, 2)
+	assert.Equal(t, EngineComparisonRow{
+		Engine:        "claude",
+		RunID:         "101",
+		Conclusion:    "success",
+		Duration:      "1.5m",
+		Turns:         12,
+		TokenUsage:    45000,
+		EstimatedCost: 0.42,
+		ToolCalls:     18,
+		SafeOutputs:   "create_issue: 1",
+	}, report.Rows[0])
+	assert.Equal(t, EngineComparisonRow{Engine: "codex", RunID: "102", SafeOutputs: "none"}, report.Rows[1], "runs without metrics should still be listed")
+}
+
+func TestMergeEngineLockFiles(t *testing.T) {
+	lockFile := func(engine string) []byte {
+		return []byte(`name: "Demo"
+"on": workflow_dispatch
+permissions: {}
+jobs:
+  activation:
+    runs-on: ubuntu-slim
+    outputs:
+      comment_id: ${{ steps.react.outputs.comment-id }}
+    steps:
+      - run: echo activate
+  agent:
+    needs: activation
+    runs-on: ubuntu-latest
+    concurrency:
+      group: "gh-aw-` + engine + `-${{ github.workflow }}"
+    steps:
+      - name: Upload agent output
+        uses: actions/upload-artifact@v4
+        with:
+          name: agent-output
+          path: /tmp/gh-aw/agent-output.json
+  conclusion:
+    needs:
+      - activation
+      - agent
+    if: needs.agent.result != 'skipped'
+    runs-on: ubuntu-slim
+    steps:
+      - name: Download agent output
+        uses: actions/download-artifact@v4
+        with:
+          name: agent-output
+      - env:
+          COMMENT_ID: ${{ needs.activation.outputs.comment_id }}
+          OTHER: ${{ needs.other.result }}
+        run: |
+          echo "` + engine + `"
+`)
+	}
+
+	merged, err := mergeEngineLockFiles([]string{"claude", "copilot"}, map[string][]byte{
+		"claude":  lockFile("claude"),
+		"copilot": lockFile("copilot"),
+	})
+	require.NoError(t, err)
+
+	var workflow map[string]any
+	require.NoError(t, yaml.Unmarshal(merged, &workflow), "merged workflow should be valid YAML")
+	assert.Equal(t, "workflow_dispatch", workflow["on"], "triggers should be kept")
+
+	jobs, ok := workflow["jobs"].(map[string]any)
+	require.True(t, ok)
+	assert.Len(t, jobs, 6, "each engine should get its own copy of every job")
+
+	agent := jobs["copilot_agent"].(map[string]any)
+	assert.Equal(t, "copilot_activation", agent["needs"])
+	assert.Equal(t, "gh-aw-copilot-${{ github.workflow }}", agent["concurrency"].(map[string]any)["group"])
+	upload := agent["steps"].([]any)[0].(map[string]any)["with"].(map[string]any)
+	assert.Equal(t, "copilot_agent-output", upload["name"])
+
+	conclusion := jobs["claude_conclusion"].(map[string]any)
+	assert.Equal(t, []any{"claude_activation", "claude_agent"}, conclusion["needs"])
+	assert.Equal(t, "needs.claude_agent.result != 'skipped'", conclusion["if"])
+	steps := conclusion["steps"].([]any)
+	assert.Equal(t, "claude_agent-output", steps[0].(map[string]any)["with"].(map[string]any)["name"])
+	env := steps[1].(map[string]any)["env"].(map[string]any)
+	assert.Equal(t, "${{ needs.claude_activation.outputs.comment_id }}", env["COMMENT_ID"])
+	assert.Equal(t, "${{ needs.other.result }}", env["OTHER"], "references to unknown jobs should be left alone")
+	assert.Equal(t, "echo \"claude\"\n", steps[1].(map[string]any)["run"])
+}
+
+func TestEngineMatrixPrefix(t *testing.T) {
+	assert.Equal(t, "claude_", engineMatrixPrefix("claude"))
+	assert.Equal(t, "my-engine_", engineMatrixPrefix("my-engine"))
+	assert.Equal(t, "org_engine_", engineMatrixPrefix("org/engine"))
+}
@@ -462,6 +462,7 @@ func commitAndPushWorkflow(tempDir, workflowName string, verbose bool) error {
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Pulling latest changes from main branch"))
 	}
 	cmd = exec.Command("git", "pull", "origin", "main")
+	cmd.Dir = tempDir
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to pull latest changes: %w (output: %s)", err, string(output))
 	}
@@ -271,8 +271,35 @@ type TrialArtifacts struct {
 	AdditionalArtifacts map[string]any `json:"additional_artifacts,omitempty"`
 }
 
+// downloadTrialRunArtifacts downloads the artifacts of a trial run to dir. With an artifact
+// prefix only the artifacts of one engine of an engine matrix run are downloaded, and the
+// prefix is removed from their directory names so they have the layout of a single-engine run.
+func downloadTrialRunArtifacts(spinnerMessage, hostRepoSlug, runID, artifactPrefix, dir string) ([]byte, error) {
+	args := []string{"run", "download", runID, "--repo", hostRepoSlug, "--dir", dir}
+	if artifactPrefix != "" {
+		args = append(args, "--pattern", artifactPrefix+"*")
+	}
+	output, err := workflow.RunGHCombined(spinnerMessage, args...)
+	if err != nil || artifactPrefix == "" {
+		return output, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifacts directory: %w", err)
+	}
+	for _, entry := range entries {
+		if name, ok := strings.CutPrefix(entry.Name(), artifactPrefix); ok && entry.IsDir() {
+			if err := os.Rename(filepath.Join(dir, entry.Name()), filepath.Join(dir, name)); err != nil {
+				return nil, fmt.Errorf("failed to rename artifact %s: %w", entry.Name(), err)
+			}
+		}
+	}
+	return output, nil
+}
+
 // downloadAllArtifacts downloads and parses all available artifacts from a workflow run
-func downloadAllArtifacts(hostRepoSlug, runID string, verbose bool) (*TrialArtifacts, error) {
+func downloadAllArtifacts(hostRepoSlug, runID, artifactPrefix string, verbose bool) (*TrialArtifacts, error) {
 	// Use the repository slug directly (should already be in user/repo format)
 	repoSlug := hostRepoSlug
 
@@ -284,7 +311,7 @@ func downloadAllArtifacts(hostRepoSlug, runID string, verbose bool) (*TrialArtif
 	defer os.RemoveAll(tempDir)
 
 	// Download all artifacts for this run
-	output, err := workflow.RunGHCombined("Downloading artifacts...", "run", "download", runID, "--repo", repoSlug, "--dir", tempDir)
+	output, err := downloadTrialRunArtifacts("Downloading artifacts...", repoSlug, runID, artifactPrefix, tempDir)
 	if err != nil {
 		// If no artifacts exist, that's okay - some workflows don't generate artifacts
 		if verbose {
@@ -0,0 +1,403 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	udiff "github.com/aymanbagabas/go-udiff"
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var verifyLog = logger.New("cli:verify_command")
+
+// Lock file verification statuses
+const (
+	VerifyStatusUpToDate = "up-to-date"
+	VerifyStatusOutdated = "outdated"
+	VerifyStatusMissing  = "missing"
+	VerifyStatusOrphaned = "orphaned"
+	VerifyStatusError    = "error"
+	VerifyStatusShared   = "shared"
+)
+
+// generatedByVersionPattern extracts the gh-aw version from the lock file header
+var generatedByVersionPattern = regexp.MustCompile(`(?m)^# This file was automatically generated by gh-aw \(([^)]+)\)\. DO NOT EDIT\.$`)
+
+// VerifyConfig holds configuration for the verify command
+type VerifyConfig struct {
+	WorkflowNames []string
+	WorkflowDir   string
+	ActionMode    string
+	ActionTag     string
+	JSONOutput    bool
+	Verbose       bool
+}
+
+// VerifyResult is the verification result for a single workflow or lock file
+type VerifyResult struct {
+	Workflow string   `json:"workflow"`
+	LockFile string   `json:"lock_file"`
+	Status   string   `json:"status"`
+	Reasons  []string `json:"reasons,omitempty"`
+	Imports  []string `json:"imports,omitempty"`
+	Diff     string   `json:"diff,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// VerifySummary counts verification results by status
+type VerifySummary struct {
+	Total    int `json:"total"`
+	UpToDate int `json:"up_to_date"`
+	Outdated int `json:"outdated"`
+	Missing  int `json:"missing"`
+	Orphaned int `json:"orphaned"`
+	Errors   int `json:"errors"`
+}
+
+// VerifyReport is the complete verification report
+type VerifyReport struct {
+	Version string         `json:"version"`
+	OK      bool           `json:"ok"`
+	Summary VerifySummary  `json:"summary"`
+	Results []VerifyResult `json:"results"`
+}
+
+// NewVerifyCommand creates the verify command
+func NewVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [workflow]...",
+		Short: "Verify that compiled lock files are up to date with their workflow sources",
+		Long: `Verify that every .lock.yml file is current relative to its .md source.
+
+Each workflow is recompiled in memory (nothing is written) with the current gh-aw version,
+using its imports and the cached remote imports, and the result is compared byte-for-byte
+with the committed lock file. For each out-of-date workflow a unified diff is reported,
+along with the likely cause (frontmatter or imports changed, different gh-aw version).
+
+When no workflows are specified, all workflows in the workflow directory are verified and
+lock files without a corresponding .md file are reported as orphaned.
+
+The command exits with a non-zero status when any lock file is outdated, missing or
+orphaned, or a workflow fails to compile, which makes it suitable for CI and pull request
+checks.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` verify                         # Verify all workflows
+  ` + string(constants.CLIExtensionPrefix) + ` verify ci-doctor daily-plan    # Verify specific workflows
+  ` + string(constants.CLIExtensionPrefix) + ` verify --dir custom/workflows  # Verify workflows in a custom directory
+  ` + string(constants.CLIExtensionPrefix) + ` verify --json                  # Output a JSON report for PR checks`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, _ := cmd.Flags().GetString("dir")
+			actionMode, _ := cmd.Flags().GetString("action-mode")
+			actionTag, _ := cmd.Flags().GetString("action-tag")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+
+			return RunVerify(VerifyConfig{
+				WorkflowNames: args,
+				WorkflowDir:   dir,
+				ActionMode:    actionMode,
+				ActionTag:     actionTag,
+				JSONOutput:    jsonOutput,
+				Verbose:       verbose,
+			})
+		},
+	}
+
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	cmd.Flags().String("action-mode", "", "Action script inlining mode the lock files were compiled with (inline, dev, release). Auto-detected if not specified")
+	cmd.Flags().String("action-tag", "", "Action SHA or tag the lock files were compiled with (overrides action-mode to release)")
+	addJSONFlag(cmd)
+
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// RunVerify recompiles workflows without emitting lock files and reports drift
+func RunVerify(config VerifyConfig) error {
+	verifyLog.Printf("Verifying lock files: workflows=%v, dir=%s", config.WorkflowNames, config.WorkflowDir)
+
+	if err := validateActionModeConfig(config.ActionMode); err != nil {
+		return err
+	}
+
+	report, err := verifyWorkflows(config)
+	if err != nil {
+		return err
+	}
+
+	if config.JSONOutput {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+	} else {
+		displayVerifyReport(report, config.Verbose)
+	}
+
+	if !report.OK {
+		return fmt.Errorf("%s. Run '%s compile%s' to update the lock files",
+			describeVerifyFailures(report.Summary), string(constants.CLIExtensionPrefix), purgeHint(report.Summary))
+	}
+	return nil
+}
+
+// verifyWorkflows builds the verification report for the configured workflows
+func verifyWorkflows(config VerifyConfig) (*VerifyReport, error) {
+	markdownFiles, workflowsDir, err := resolveVerifyFiles(config)
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := createAndConfigureCompiler(CompileConfig{
+		NoEmit:      true,
+		WorkflowDir: workflowsDir,
+		ActionMode:  config.ActionMode,
+		ActionTag:   config.ActionTag,
+		Verbose:     config.Verbose,
+	})
+	compiler.SetQuiet(true)
+
+	report := &VerifyReport{Version: GetVersion()}
+	var expectedLockFiles []string
+	for _, markdownFile := range markdownFiles {
+		expectedLockFiles = append(expectedLockFiles, stringutil.MarkdownToLockFile(markdownFile))
+		report.Results = append(report.Results, verifyWorkflowFile(compiler, markdownFile))
+	}
+
+	// Orphaned lock files are only detected when verifying the whole directory
+	if len(config.WorkflowNames) == 0 {
+		existingLockFiles, err := filepath.Glob(filepath.Join(workflowsDir, "*.lock.yml"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find existing lock files: %w", err)
+		}
+		for _, orphan := range findOrphanedLockFiles(existingLockFiles, expectedLockFiles) {
+			report.Results = append(report.Results, VerifyResult{
+				Workflow: strings.TrimSuffix(filepath.Base(orphan), ".lock.yml"),
+				LockFile: console.ToRelativePath(orphan),
+				Status:   VerifyStatusOrphaned,
+				Reasons:  []string{fmt.Sprintf("no %s.md source file", strings.TrimSuffix(filepath.Base(orphan), ".lock.yml"))},
+			})
+		}
+	}
+
+	report.Summary = summarizeVerifyResults(report.Results)
+	report.OK = report.Summary.Outdated == 0 && report.Summary.Missing == 0 &&
+		report.Summary.Orphaned == 0 && report.Summary.Errors == 0
+	verifyLog.Printf("Verification complete: %+v", report.Summary)
+	return report, nil
+}
+
+// resolveVerifyFiles returns the markdown files to verify and the workflow directory
+func resolveVerifyFiles(config VerifyConfig) ([]string, string, error) {
+	workflowDir := config.WorkflowDir
+	if workflowDir == "" {
+		workflowDir = ".github/workflows"
+	}
+	workflowsDir := workflowDir
+	if !filepath.IsAbs(workflowsDir) {
+		gitRoot, err := findGitRoot()
+		if err != nil {
+			return nil, "", fmt.Errorf("verify requires being in a git repository: %w", err)
+		}
+		workflowsDir = filepath.Join(gitRoot, workflowDir)
+	}
+
+	if len(config.WorkflowNames) > 0 {
+		var files []string
+		for _, name := range config.WorkflowNames {
+			file, err := resolveWorkflowFileInDir(name, config.Verbose, workflowsDir)
+			if err != nil {
+				return nil, "", err
+			}
+			files = append(files, file)
+		}
+		return files, workflowsDir, nil
+	}
+
+	files, err := getMarkdownWorkflowFiles(workflowsDir)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(files) == 0 {
+		return nil, "", fmt.Errorf("no markdown files found in %s", workflowsDir)
+	}
+	return files, workflowsDir, nil
+}
+
+// verifyWorkflowFile recompiles a single workflow in memory and compares the result with
+// its committed lock file
+func verifyWorkflowFile(compiler *workflow.Compiler, markdownFile string) VerifyResult {
+	lockFile := stringutil.MarkdownToLockFile(markdownFile)
+	result := VerifyResult{
+		Workflow: strings.TrimSuffix(filepath.Base(markdownFile), ".md"),
+		LockFile: console.ToRelativePath(lockFile),
+	}
+
+	configureCompilerForFile(compiler, markdownFile)
+	workflowData, expected, err := compiler.CompileToYAML(markdownFile)
+	if err != nil {
+		var sharedErr *workflow.SharedWorkflowError
+		if errors.As(err, &sharedErr) {
+			result.Status = VerifyStatusShared
+			return result
+		}
+		result.Status = VerifyStatusError
+		result.Error = err.Error()
+		return result
+	}
+	result.Imports = workflowData.ImportedFiles
+
+	existing, err := os.ReadFile(lockFile)
+	if os.IsNotExist(err) {
+		result.Status = VerifyStatusMissing
+		result.Reasons = []string{"lock file has not been compiled"}
+		return result
+	} else if err != nil {
+		result.Status = VerifyStatusError
+		result.Error = fmt.Sprintf("failed to read lock file: %v", err)
+		return result
+	}
+
+	if string(existing) == expected {
+		result.Status = VerifyStatusUpToDate
+		return result
+	}
+
+	result.Status = VerifyStatusOutdated
+	result.Reasons = explainLockFileDrift(string(existing), expected)
+	result.Diff = udiff.Unified(result.LockFile+" (committed)", result.LockFile+" (recompiled)", string(existing), expected)
+	verifyLog.Printf("Lock file %s is outdated: %v", lockFile, result.Reasons)
+	return result
+}
+
+// explainLockFileDrift describes the likely causes of a difference between a committed
+// lock file and a freshly compiled one, using the metadata embedded in the lock file header
+func explainLockFileDrift(existing, expected string) []string {
+	var reasons []string
+
+	if existingHash, expectedHash := extractHashFromLockFile(existing), extractHashFromLockFile(expected); existingHash != expectedHash {
+		reasons = append(reasons, "frontmatter or imports changed")
+	}
+
+	existingVersion, expectedVersion := extractLockFileVersion(existing), extractLockFileVersion(expected)
+	if existingVersion != expectedVersion {
+		reasons = append(reasons, fmt.Sprintf("compiled with gh-aw %s, current version is %s",
+			versionOrUnknown(existingVersion), versionOrUnknown(expectedVersion)))
+	}
+
+	if len(reasons) == 0 {
+		reasons = append(reasons, "generated content differs (compiler output or inlined content changed)")
+	}
+	return reasons
+}
+
+// extractLockFileVersion returns the gh-aw version recorded in a lock file header, or an
+// empty string for lock files generated by development builds
+func extractLockFileVersion(content string) string {
+	if match := generatedByVersionPattern.FindStringSubmatch(content); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+func versionOrUnknown(version string) string {
+	if version == "" {
+		return "a development build"
+	}
+	return version
+}
+
+// summarizeVerifyResults counts the results by status
+func summarizeVerifyResults(results []VerifyResult) VerifySummary {
+	var summary VerifySummary
+	for _, result := range results {
+		switch result.Status {
+		case VerifyStatusShared:
+			continue
+		case VerifyStatusUpToDate:
+			summary.UpToDate++
+		case VerifyStatusOutdated:
+			summary.Outdated++
+		case VerifyStatusMissing:
+			summary.Missing++
+		case VerifyStatusOrphaned:
+			summary.Orphaned++
+		case VerifyStatusError:
+			summary.Errors++
+		}
+		summary.Total++
+	}
+	return summary
+}
+
+// describeVerifyFailures formats the failing counts of a summary
+func describeVerifyFailures(summary VerifySummary) string {
+	var parts []string
+	if summary.Outdated > 0 {
+		parts = append(parts, fmt.Sprintf("%d outdated", summary.Outdated))
+	}
+	if summary.Missing > 0 {
+		parts = append(parts, fmt.Sprintf("%d missing", summary.Missing))
+	}
+	if summary.Orphaned > 0 {
+		parts = append(parts, fmt.Sprintf("%d orphaned", summary.Orphaned))
+	}
+	if summary.Errors > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed to compile", summary.Errors))
+	}
+	return "lock file verification failed: " + strings.Join(parts, ", ")
+}
+
+func purgeHint(summary VerifySummary) string {
+	if summary.Orphaned > 0 {
+		return " --purge"
+	}
+	return ""
+}
+
+// displayVerifyReport prints the verification report to stderr
+func displayVerifyReport(report *VerifyReport, verbose bool) {
+	for _, result := range report.Results {
+		switch result.Status {
+		case VerifyStatusUpToDate:
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(result.LockFile+" is up to date"))
+			}
+		case VerifyStatusShared:
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(result.Workflow+".md is a shared workflow component, skipped"))
+			}
+		case VerifyStatusOutdated:
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s is out of date (%s)", result.LockFile, strings.Join(result.Reasons, "; "))))
+			fmt.Fprintln(os.Stderr, result.Diff)
+		case VerifyStatusMissing:
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(result.LockFile+" is missing"))
+		case VerifyStatusOrphaned:
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s is orphaned (%s)", result.LockFile, strings.Join(result.Reasons, "; "))))
+		case VerifyStatusError:
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s failed to compile: %s", result.Workflow, result.Error)))
+		}
+	}
+
+	if report.OK {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("All lock files are up to date (%d verified)", report.Summary.UpToDate)))
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("%d of %d lock files up to date", report.Summary.UpToDate, report.Summary.Total)))
+	}
+}
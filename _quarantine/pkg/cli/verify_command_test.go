@@ -0,0 +1,231 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const verifyTestWorkflow = `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: copilot
+---
+
+# Verify Test
+
+Summarize the repository.
+`
+
+// compileForVerifyTest compiles a workflow the same way the compile command does
+func compileForVerifyTest(t *testing.T, markdownPath string) {
+	t.Helper()
+	compiler := createAndConfigureCompiler(CompileConfig{})
+	compiler.SetQuiet(true)
+	configureCompilerForFile(compiler, markdownPath)
+	require.NoError(t, compiler.CompileWorkflow(markdownPath))
+}
+
+func findVerifyResult(t *testing.T, report *VerifyReport, workflowName string) VerifyResult {
+	t.Helper()
+	for _, result := range report.Results {
+		if result.Workflow == workflowName {
+			return result
+		}
+	}
+	require.Failf(t, "result not found", "no verification result for %s", workflowName)
+	return VerifyResult{}
+}
+
+func TestVerifyWorkflows(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "test-*")
+	workflowPath := filepath.Join(tmpDir, "verify-test.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(verifyTestWorkflow), 0644))
+	compileForVerifyTest(t, workflowPath)
+
+	sharedPath := filepath.Join(tmpDir, "shared.md")
+	require.NoError(t, os.WriteFile(sharedPath, []byte("---\ntools:\n  github:\n---\n\nShared instructions.\n"), 0644))
+
+	config := VerifyConfig{WorkflowDir: tmpDir}
+
+	t.Run("up to date", func(t *testing.T) {
+		report, err := verifyWorkflows(config)
+		require.NoError(t, err)
+		assert.True(t, report.OK)
+		assert.Equal(t, VerifySummary{Total: 1, UpToDate: 1}, report.Summary, "shared workflows should not be counted")
+		assert.Equal(t, VerifyStatusUpToDate, findVerifyResult(t, report, "verify-test").Status)
+		assert.Equal(t, VerifyStatusShared, findVerifyResult(t, report, "shared").Status)
+	})
+
+	t.Run("outdated", func(t *testing.T) {
+		modified := strings.Replace(verifyTestWorkflow, "engine: copilot\n", "engine: copilot\ntimeout-minutes: 42\n", 1)
+		require.NoError(t, os.WriteFile(workflowPath, []byte(modified), 0644))
+		defer func() {
+			require.NoError(t, os.WriteFile(workflowPath, []byte(verifyTestWorkflow), 0644))
+		}()
+
+		report, err := verifyWorkflows(config)
+		require.NoError(t, err)
+		assert.False(t, report.OK)
+		result := findVerifyResult(t, report, "verify-test")
+		assert.Equal(t, VerifyStatusOutdated, result.Status)
+		assert.Contains(t, result.Diff, "(committed)")
+		assert.Contains(t, result.Diff, "+        timeout-minutes: 42")
+		assert.Equal(t, []string{"frontmatter or imports changed"}, result.Reasons)
+	})
+
+	t.Run("orphaned", func(t *testing.T) {
+		orphan := filepath.Join(tmpDir, "removed.lock.yml")
+		require.NoError(t, os.WriteFile(orphan, []byte("name: removed\n"), 0644))
+		defer os.Remove(orphan)
+
+		report, err := verifyWorkflows(config)
+		require.NoError(t, err)
+		assert.False(t, report.OK)
+		assert.Equal(t, VerifyStatusOrphaned, findVerifyResult(t, report, "removed").Status)
+		assert.Equal(t, 1, report.Summary.Orphaned)
+
+		// Orphans are not reported when verifying specific workflows
+		report, err = verifyWorkflows(VerifyConfig{WorkflowDir: tmpDir, WorkflowNames: []string{"verify-test"}})
+		require.NoError(t, err)
+		assert.True(t, report.OK)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		lockPath := filepath.Join(tmpDir, "verify-test.lock.yml")
+		content, err := os.ReadFile(lockPath)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(lockPath))
+		defer func() {
+			require.NoError(t, os.WriteFile(lockPath, content, 0644))
+		}()
+
+		report, err := verifyWorkflows(config)
+		require.NoError(t, err)
+		assert.False(t, report.OK)
+		assert.Equal(t, VerifyStatusMissing, findVerifyResult(t, report, "verify-test").Status)
+	})
+
+	t.Run("compile error", func(t *testing.T) {
+		brokenPath := filepath.Join(tmpDir, "broken.md")
+		require.NoError(t, os.WriteFile(brokenPath, []byte("---\non: workflow_dispatch\nengine: not-an-engine\n---\n\n# Broken\n"), 0644))
+		defer os.Remove(brokenPath)
+
+		report, err := verifyWorkflows(config)
+		require.NoError(t, err)
+		assert.False(t, report.OK)
+		result := findVerifyResult(t, report, "broken")
+		assert.Equal(t, VerifyStatusError, result.Status)
+		assert.NotEmpty(t, result.Error)
+	})
+}
+
+func TestExplainLockFileDrift(t *testing.T) {
+	header := func(version, hash string) string {
+		var b strings.Builder
+		if version != "" {
+			b.WriteString("# This file was automatically generated by gh-aw (" + version + "). DO NOT EDIT.\n")
+		} else {
+			b.WriteString("# This file was automatically generated by gh-aw. DO NOT EDIT.\n")
+		}
+		b.WriteString("# frontmatter-hash: " + hash + "\n")
+		return b.String()
+	}
+
+	tests := []struct {
+		name     string
+		existing string
+		expected string
+		want     []string
+	}{
+		{
+			name:     "frontmatter changed",
+			existing: header("v1.0.0", "aaa"),
+			expected: header("v1.0.0", "bbb"),
+			want:     []string{"frontmatter or imports changed"},
+		},
+		{
+			name:     "version changed",
+			existing: header("v1.0.0", "aaa") + "old",
+			expected: header("v1.1.0", "aaa") + "new",
+			want:     []string{"compiled with gh-aw v1.0.0, current version is v1.1.0"},
+		},
+		{
+			name:     "compiled by development build",
+			existing: header("", "aaa"),
+			expected: header("v1.1.0", "aaa"),
+			want:     []string{"compiled with gh-aw a development build, current version is v1.1.0"},
+		},
+		{
+			name:     "body changed",
+			existing: header("v1.0.0", "aaa") + "old",
+			expected: header("v1.0.0", "aaa") + "new",
+			want:     []string{"generated content differs (compiler output or inlined content changed)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, explainLockFileDrift(tt.existing, tt.expected))
+		})
+	}
+}
+
+func TestDescribeVerifyFailures(t *testing.T) {
+	summary := VerifySummary{Total: 5, UpToDate: 1, Outdated: 2, Orphaned: 1, Errors: 1}
+	assert.Equal(t, "lock file verification failed: 2 outdated, 1 orphaned, 1 failed to compile", describeVerifyFailures(summary))
+	assert.Equal(t, " --purge", purgeHint(summary))
+	assert.Empty(t, purgeHint(VerifySummary{Outdated: 1}))
+}
+
+func TestFindOrphanedLockFiles(t *testing.T) {
+	existing := []string{"/w/a.lock.yml", "/w/b.lock.yml", "/w/c.campaign.lock.yml"}
+	expected := []string{"/w/a.lock.yml"}
+	assert.Equal(t, []string{"/w/b.lock.yml"}, findOrphanedLockFiles(existing, expected))
+}
+
+func TestVerifyWorkflowsPlansSchedulesInWorkflowDir(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "test-*")
+	compileAll := func() {
+		compiler := createAndConfigureCompiler(CompileConfig{WorkflowDir: tmpDir})
+		compiler.SetQuiet(true)
+		files, err := getMarkdownWorkflowFiles(tmpDir)
+		require.NoError(t, err)
+		for _, markdownPath := range files {
+			configureCompilerForFile(compiler, markdownPath)
+			require.NoError(t, compiler.CompileWorkflow(markdownPath))
+		}
+	}
+
+	fuzzy := strings.Replace(verifyTestWorkflow, "on: workflow_dispatch\n", "on:\n  schedule: daily\n", 1)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "nightly.md"), []byte(fuzzy), 0644))
+	compileAll()
+
+	// A fixed schedule at the fuzzy schedule's hashed time makes the planner move it
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "nightly.lock.yml"))
+	require.NoError(t, err)
+	match := regexp.MustCompile(`cron: "([^"]+)"`).FindSubmatch(lockContent)
+	require.NotNil(t, match, "lock file should contain the scattered cron")
+	hashedCron := string(match[1])
+	fixed := strings.Replace(verifyTestWorkflow, "on: workflow_dispatch\n", "on:\n  schedule:\n    - cron: \""+hashedCron+"\"\n", 1)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "fixed.md"), []byte(fixed), 0644))
+	compileAll()
+
+	lockContent, err = os.ReadFile(filepath.Join(tmpDir, "nightly.lock.yml"))
+	require.NoError(t, err)
+	require.NotContains(t, string(lockContent), `cron: "`+hashedCron+`"`, "planner should move the colliding schedule")
+
+	report, err := verifyWorkflows(VerifyConfig{WorkflowDir: tmpDir})
+	require.NoError(t, err)
+	assert.True(t, report.OK, "schedules should be planned against the verified workflow directory")
+	assert.Equal(t, 2, report.Summary.UpToDate)
+}
@@ -288,6 +288,12 @@ func parseAndDisplayZizmorOutput(stdout, stderr string, verbose bool) (int, erro
 				}
 
 				fmt.Fprint(os.Stderr, console.FormatError(compilerErr))
+
+				sarifMessage := desc
+				if url != "" {
+					sarifMessage = fmt.Sprintf("%s (%s)", desc, url)
+				}
+				recordCompileSARIFLockFinding(filePath, lineNum, "zizmor/"+ident, desc, errorType, sarifMessage)
 			}
 		}
 	}
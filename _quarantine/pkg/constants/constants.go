@@ -289,6 +289,9 @@ const (
 	// DefaultMCPInspectorPort is the default port for the MCP inspector (safe-outputs server)
 	DefaultMCPInspectorPort = 3001
 
+	// DefaultRepoMemoryMCPPort is the default port for the repo-memory MCP server
+	DefaultRepoMemoryMCPPort = 3002
+
 	// MinNetworkPort is the minimum valid network port number
 	MinNetworkPort = 1
 
@@ -352,6 +355,12 @@ const DefaultMCPGatewayContainer = "ghcr.io/github/gh-aw-mcpg"
 // This directory is shared between the agent container and MCP gateway for large payload exchange
 const DefaultMCPGatewayPayloadDir = "/tmp/gh-aw/mcp-payloads"
 
+// MCPGatewayRecordDir is the directory where the recording proxy in front of the MCP gateway
+// (actions/setup/js/mcp_recording_proxy.cjs) records MCP request and response payloads when
+// sandbox.mcp.record is enabled. It is under /tmp/gh-aw so the recordings are redacted by the
+// secret redaction step and uploaded with the mcp-logs artifact.
+const MCPGatewayRecordDir = "/tmp/gh-aw/mcp-logs/recordings"
+
 // DefaultFirewallRegistry is the container image registry for AWF (gh-aw-firewall) Docker images
 const DefaultFirewallRegistry = "ghcr.io/github/gh-aw-firewall"
 
@@ -583,6 +592,9 @@ const SafeOutputsMCPServerID = "safeoutputs"
 // SafeInputsMCPServerID is the identifier for the safe-inputs MCP server
 const SafeInputsMCPServerID = "safeinputs"
 
+// RepoMemoryMCPServerID is the identifier for the repo-memory MCP server
+const RepoMemoryMCPServerID = "repomemory"
+
 // SafeInputsMCPVersion is the version of the safe-inputs MCP server
 const SafeInputsMCPVersion = "1.0.0"
 
@@ -607,6 +619,8 @@ const CheckStopTimeStepID StepID = "check_stop_time"
 const CheckSkipIfMatchStepID StepID = "check_skip_if_match"
 const CheckSkipIfNoMatchStepID StepID = "check_skip_if_no_match"
 const CheckCommandPositionStepID StepID = "check_command_position"
+const CheckBudgetStepID StepID = "check_budget"
+const CheckScheduleStepID StepID = "check_schedule"
 
 // Output names for pre-activation job steps
 const IsTeamMemberOutput = "is_team_member"
@@ -614,6 +628,8 @@ const StopTimeOkOutput = "stop_time_ok"
 const SkipCheckOkOutput = "skip_check_ok"
 const SkipNoMatchCheckOkOutput = "skip_no_match_check_ok"
 const CommandPositionOkOutput = "command_position_ok"
+const BudgetOkOutput = "budget_ok"
+const ScheduleOkOutput = "schedule_ok"
 const MatchedCommandOutput = "matched_command"
 const ActivatedOutput = "activated"
 
@@ -787,7 +803,7 @@ var IgnoredFrontmatterFields = []string{}
 //
 // Forbidden fields fall into these categories:
 //   - Workflow triggers: on (defines it as a main workflow)
-//   - Workflow execution: command, run-name, runs-on, concurrency, if, timeout-minutes, timeout_minutes
+//   - Workflow execution: command, run-name, runs-on, concurrency, if, timeout-minutes, timeout_minutes, budget
 //   - Workflow metadata: name, tracker-id, strict
 //   - Workflow features: container, env, environment, sandbox, features
 //   - Access control: roles, github-token
@@ -796,6 +812,7 @@ var IgnoredFrontmatterFields = []string{}
 // and will be properly imported and merged when the shared workflow is imported.
 var SharedWorkflowForbiddenFields = []string{
 	"on",              // Trigger field - only for main workflows
+	"budget",          // Token and cost limits
 	"command",         // Command for workflow execution
 	"concurrency",     // Concurrency control
 	"container",       // Container configuration
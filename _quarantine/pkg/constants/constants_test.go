@@ -239,11 +239,15 @@ func TestConstantValues(t *testing.T) {
 		{"CheckSkipIfMatchStepID", string(CheckSkipIfMatchStepID), "check_skip_if_match"},
 		{"CheckSkipIfNoMatchStepID", string(CheckSkipIfNoMatchStepID), "check_skip_if_no_match"},
 		{"CheckCommandPositionStepID", string(CheckCommandPositionStepID), "check_command_position"},
+		{"CheckBudgetStepID", string(CheckBudgetStepID), "check_budget"},
+		{"CheckScheduleStepID", string(CheckScheduleStepID), "check_schedule"},
 		{"IsTeamMemberOutput", IsTeamMemberOutput, "is_team_member"},
 		{"StopTimeOkOutput", StopTimeOkOutput, "stop_time_ok"},
 		{"SkipCheckOkOutput", SkipCheckOkOutput, "skip_check_ok"},
 		{"SkipNoMatchCheckOkOutput", SkipNoMatchCheckOkOutput, "skip_no_match_check_ok"},
 		{"CommandPositionOkOutput", CommandPositionOkOutput, "command_position_ok"},
+		{"BudgetOkOutput", BudgetOkOutput, "budget_ok"},
+		{"ScheduleOkOutput", ScheduleOkOutput, "schedule_ok"},
 		{"ActivatedOutput", ActivatedOutput, "activated"},
 		{"DefaultActivationJobRunnerImage", DefaultActivationJobRunnerImage, "ubuntu-slim"},
 	}
@@ -246,8 +246,18 @@ func ExtractYamlChunk(yamlContent, key string) (string, error) {
 func ExtractWorkflowNameFromMarkdown(filePath string) (string, error) {
 	log.Printf("Extracting workflow name from markdown: file=%s", filePath)
 
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+	return ExtractWorkflowNameFromContent(string(content), filePath)
+}
+
+// ExtractWorkflowNameFromContent extracts the workflow name from the first H1 header of
+// workflow file content, falling back to a name derived from filePath
+func ExtractWorkflowNameFromContent(content string, filePath string) (string, error) {
 	// First extract markdown content (excluding frontmatter)
-	markdownContent, err := ExtractMarkdown(filePath)
+	markdownContent, err := ExtractMarkdownContent(content)
 	if err != nil {
 		return "", err
 	}
@@ -92,6 +92,7 @@ func buildCanonicalFrontmatter(frontmatter map[string]any, result *ImportsResult
 	addField("on")
 	addField("permissions")
 	addField("tracker-id")
+	addField("budget")
 
 	// Tool and integration fields
 	addField("tools")
@@ -114,9 +114,11 @@ func IsWeeklyCron(cron string) bool {
 	return true
 }
 
-// IsFuzzyCron checks if a cron expression is a fuzzy schedule placeholder
+// IsFuzzyCron checks if a cron expression is a fuzzy schedule placeholder,
+// including placeholders with a timezone or calendar rule
 func IsFuzzyCron(cron string) bool {
-	return strings.HasPrefix(cron, "FUZZY:")
+	_, body, _ := splitScheduleExtensions(cron)
+	return strings.HasPrefix(body, "FUZZY:")
 }
 
 // IsCronExpression checks if the input looks like a valid cron expression
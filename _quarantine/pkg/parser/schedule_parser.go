@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/github/gh-aw/pkg/logger"
 )
@@ -13,9 +14,12 @@ var scheduleLog = logger.New("parser:schedule_parser")
 
 // ScheduleParser parses human-friendly schedule expressions into cron expressions
 type ScheduleParser struct {
-	input  string
-	tokens []string
-	pos    int
+	input     string
+	tokens    []string
+	rawTokens []string // tokens with their original case, for timezone names
+	pos       int
+	timezone  string // IANA timezone the schedule's times are given in
+	rule      string // calendar rule cron cannot express (e.g. "nth=1", "weeks=2")
 }
 
 // ParseSchedule converts a human-friendly schedule expression into a cron expression
@@ -33,6 +37,17 @@ func ParseSchedule(input string) (cron string, original string, err error) {
 		return input, "", nil
 	}
 
+	// A cron expression in a timezone (e.g. "CRON_TZ=Europe/Berlin 0 9 * * 1-5") is kept
+	// with its timezone and converted to UTC when the workflow is compiled
+	if isTimezoneCronExpression(input) {
+		timezone, body, _ := splitScheduleExtensions(input)
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return "", "", fmt.Errorf("unknown timezone '%s'", timezone)
+		}
+		scheduleLog.Printf("Input is a cron expression in timezone %s: %s", timezone, body)
+		return joinScheduleExtensions(timezone, body, ""), input, nil
+	}
+
 	parser := &ScheduleParser{
 		input: input,
 	}
@@ -66,6 +81,7 @@ func (p *ScheduleParser) tokenize() error {
 	}
 
 	p.tokens = tokens
+	p.rawTokens = strings.Fields(strings.TrimSpace(p.input))
 	p.pos = 0
 	return nil
 }
@@ -76,13 +92,22 @@ func (p *ScheduleParser) parse() (string, error) {
 		return "", fmt.Errorf("no tokens to parse")
 	}
 
-	// Check for interval-based schedules: "every N minutes|hours"
+	var cronExpr string
+	var err error
 	if p.tokens[0] == "every" {
-		return p.parseInterval()
+		// Check for interval-based schedules: "every N minutes|hours"
+		cronExpr, err = p.parseInterval()
+	} else {
+		// Otherwise, parse as base schedule (daily, weekly, monthly, yearly)
+		cronExpr, err = p.parseBase()
+	}
+	if err != nil {
+		return "", err
 	}
 
-	// Otherwise, parse as base schedule (daily, weekly, monthly, yearly)
-	return p.parseBase()
+	// Keep the timezone and calendar rule with the schedule; they are applied when the
+	// scattered schedule is converted to UTC cron expressions
+	return joinScheduleExtensions(p.timezone, cronExpr, p.rule), nil
 }
 
 // parseInterval parses interval-based schedules like "every 10 minutes" or "every 2h"
@@ -91,6 +116,11 @@ func (p *ScheduleParser) parseInterval() (string, error) {
 		return "", fmt.Errorf("invalid interval format, expected 'every N unit' or 'every Nunit'")
 	}
 
+	// Check for week intervals on a weekday: "every 2 weeks on friday"
+	if len(p.tokens) > 2 && (p.tokens[2] == "weeks" || p.tokens[2] == "week") {
+		return p.parseWeekInterval()
+	}
+
 	// Check if the second token is a duration format like "2h", "30m", "1d"
 	if len(p.tokens) == 2 || (len(p.tokens) > 2 && p.tokens[2] != "minutes" && p.tokens[2] != "hours" && p.tokens[2] != "minute" && p.tokens[2] != "hour") {
 		// Try to parse as short duration format: "every 2h", "every 30m", "every 1d"
@@ -245,88 +275,22 @@ func (p *ScheduleParser) parseBase() (string, error) {
 	}
 
 	baseType := p.tokens[0]
-	var minute, hour, day, month, weekday string
-
-	// Default time is 00:00
-	minute = "0"
-	hour = "0"
-	day = "*"
-	month = "*"
-	weekday = "*"
+	var minute, hour, day, weekday string
 
 	switch baseType {
 	case "daily":
-		// daily -> FUZZY:DAILY (fuzzy schedule, time will be scattered)
-		// daily at HH:MM -> MM HH * * *
-		// daily around HH:MM -> FUZZY:DAILY_AROUND:HH:MM (fuzzy schedule with target time)
-		// daily between HH:MM and HH:MM -> FUZZY:DAILY_BETWEEN:START_H:START_M:END_H:END_M (fuzzy schedule within time range)
-		if len(p.tokens) == 1 {
-			// Just "daily" with no time - this is a fuzzy schedule
-			return "FUZZY:DAILY * * *", nil
-		}
-		if len(p.tokens) > 1 {
-			// Check if "between" keyword is used
-			if p.tokens[1] == "between" {
-				// Parse: "daily between START and END"
-				// We need at least: daily between TIME and TIME (5 tokens minimum)
-				if len(p.tokens) < 5 {
-					return "", fmt.Errorf("invalid 'between' format, expected 'daily between START and END'")
-				}
-
-				// Find the "and" keyword to split start and end times
-				andIndex := -1
-				for i := 2; i < len(p.tokens); i++ {
-					if p.tokens[i] == "and" {
-						andIndex = i
-						break
-					}
-				}
-				if andIndex == -1 {
-					return "", fmt.Errorf("missing 'and' keyword in 'between' clause")
-				}
-
-				// Extract start time (tokens between "between" and "and")
-				startTimeStr, err := p.extractTimeBetween(2, andIndex)
-				if err != nil {
-					return "", fmt.Errorf("invalid start time in 'between' clause: %w", err)
-				}
-				startMinute, startHour := parseTime(startTimeStr)
-
-				// Extract end time (tokens after "and")
-				endTimeStr, err := p.extractTimeAfter(andIndex + 1)
-				if err != nil {
-					return "", fmt.Errorf("invalid end time in 'between' clause: %w", err)
-				}
-				endMinute, endHour := parseTime(endTimeStr)
+		return p.parseDaily(baseType, "*")
 
-				// Validate that start is before end (in minutes since midnight)
-				startMinutes := parseTimeToMinutes(startHour, startMinute)
-				endMinutes := parseTimeToMinutes(endHour, endMinute)
+	case "weekdays":
+		// weekdays [around|between ...] -> daily schedule restricted to Monday-Friday
+		return p.parseDaily(baseType, "1-5")
 
-				// Allow ranges that cross midnight (e.g., 22:00 to 02:00)
-				// We'll handle this in the scattering logic
-				if startMinutes == endMinutes {
-					return "", fmt.Errorf("start and end times cannot be the same in 'between' clause")
-				}
+	case "weekends":
+		// weekends [around|between ...] -> daily schedule restricted to Saturday and Sunday
+		return p.parseDaily(baseType, "0,6")
 
-				// Return fuzzy between format: FUZZY:DAILY_BETWEEN:START_H:START_M:END_H:END_M
-				return fmt.Sprintf("FUZZY:DAILY_BETWEEN:%s:%s:%s:%s * * *", startHour, startMinute, endHour, endMinute), nil
-			}
-			// Check if "around" keyword is used
-			if p.tokens[1] == "around" {
-				// Extract time after "around"
-				timeStr, err := p.extractTime(2)
-				if err != nil {
-					return "", err
-				}
-				// Parse the time to validate it
-				minute, hour = parseTime(timeStr)
-				// Return fuzzy around format: FUZZY:DAILY_AROUND:HH:MM
-				return fmt.Sprintf("FUZZY:DAILY_AROUND:%s:%s * * *", hour, minute), nil
-			}
-			// Reject "daily at TIME" pattern - use cron directly for fixed times
-			return "", fmt.Errorf("'daily at <time>' syntax is not supported. Use fuzzy schedules like 'daily' (scattered), 'daily around <time>', or 'daily between <start> and <end>' for load distribution. For fixed times, use standard cron syntax (e.g., '0 14 * * *')")
-		}
+	case "first", "second", "third", "fourth", "last", "1st", "2nd", "3rd", "4th":
+		return p.parseNthWeekday()
 
 	case "hourly":
 		// hourly -> FUZZY:HOURLY/1 (fuzzy hourly schedule, equivalent to "every 1h")
@@ -414,11 +378,182 @@ func (p *ScheduleParser) parseBase() (string, error) {
 		return "", fmt.Errorf("'monthly on <day>' syntax is not supported. Use standard cron syntax for monthly schedules (e.g., '0 0 %s * *' for the %sth at midnight)", day, day)
 
 	default:
-		return "", fmt.Errorf("unsupported schedule type '%s', use 'daily', 'weekly', 'bi-weekly', 'tri-weekly', or 'monthly'", baseType)
+		return "", fmt.Errorf("unsupported schedule type '%s', use 'daily', 'weekdays', 'weekends', 'weekly', 'bi-weekly', 'tri-weekly', 'monthly', or '<first|last> <weekday> of month'", baseType)
+	}
+}
+
+// parseDaily parses daily schedules, optionally restricted to some days of the week
+// (baseType is "daily", "weekdays" or "weekends" and dayOfWeek its cron day-of-week field)
+func (p *ScheduleParser) parseDaily(baseType, dayOfWeek string) (string, error) {
+	// daily -> FUZZY:DAILY (fuzzy schedule, time will be scattered)
+	// daily at HH:MM -> rejected (use cron directly)
+	// daily around HH:MM -> FUZZY:DAILY_AROUND:HH:MM (fuzzy schedule with target time)
+	// daily between HH:MM and HH:MM -> FUZZY:DAILY_BETWEEN:START_H:START_M:END_H:END_M (fuzzy schedule within time range)
+	// weekdays and weekends use the same forms with the day-of-week field set
+	if len(p.tokens) == 1 {
+		// Just "daily" with no time - this is a fuzzy schedule
+		return fmt.Sprintf("FUZZY:DAILY * * %s", dayOfWeek), nil
+	}
+
+	// Check if "between" keyword is used
+	if p.tokens[1] == "between" {
+		// Parse: "daily between START and END"
+		// We need at least: daily between TIME and TIME (5 tokens minimum)
+		if len(p.tokens) < 5 {
+			return "", fmt.Errorf("invalid 'between' format, expected '%s between START and END'", baseType)
+		}
+
+		// Find the "and" keyword to split start and end times
+		andIndex := -1
+		for i := 2; i < len(p.tokens); i++ {
+			if p.tokens[i] == "and" {
+				andIndex = i
+				break
+			}
+		}
+		if andIndex == -1 {
+			return "", fmt.Errorf("missing 'and' keyword in 'between' clause")
+		}
+
+		// Extract start time (tokens between "between" and "and")
+		startTimeStr, err := p.extractTimeBetween(2, andIndex)
+		if err != nil {
+			return "", fmt.Errorf("invalid start time in 'between' clause: %w", err)
+		}
+		startMinute, startHour := parseTime(startTimeStr)
+
+		// Extract end time (tokens after "and")
+		endTimeStr, err := p.extractTimeAfter(andIndex + 1)
+		if err != nil {
+			return "", fmt.Errorf("invalid end time in 'between' clause: %w", err)
+		}
+		endMinute, endHour := parseTime(endTimeStr)
+
+		// Validate that start is before end (in minutes since midnight)
+		startMinutes := parseTimeToMinutes(startHour, startMinute)
+		endMinutes := parseTimeToMinutes(endHour, endMinute)
+
+		// Allow ranges that cross midnight (e.g., 22:00 to 02:00)
+		// We'll handle this in the scattering logic
+		if startMinutes == endMinutes {
+			return "", fmt.Errorf("start and end times cannot be the same in 'between' clause")
+		}
+
+		// Return fuzzy between format: FUZZY:DAILY_BETWEEN:START_H:START_M:END_H:END_M
+		return fmt.Sprintf("FUZZY:DAILY_BETWEEN:%s:%s:%s:%s * * %s", startHour, startMinute, endHour, endMinute, dayOfWeek), nil
+	}
+
+	// Check if "around" keyword is used
+	if p.tokens[1] == "around" {
+		// Extract time after "around"
+		timeStr, err := p.extractTime(2)
+		if err != nil {
+			return "", err
+		}
+		// Parse the time to validate it
+		minute, hour := parseTime(timeStr)
+		// Return fuzzy around format: FUZZY:DAILY_AROUND:HH:MM
+		return fmt.Sprintf("FUZZY:DAILY_AROUND:%s:%s * * %s", hour, minute, dayOfWeek), nil
+	}
+
+	// Reject "daily at TIME" pattern - use cron directly for fixed times
+	return "", fmt.Errorf("'%s at <time>' syntax is not supported. Use fuzzy schedules like '%s' (scattered), '%s around <time>', or '%s between <start> and <end>' for load distribution. For fixed times, use standard cron syntax (e.g., '0 14 * * %s')", baseType, baseType, baseType, baseType, dayOfWeek)
+}
+
+// parseNthWeekday parses schedules on a weekday of a given week of the month, like
+// "first monday of month" or "last friday of the month around 16:00"
+func (p *ScheduleParser) parseNthWeekday() (string, error) {
+	nth := map[string]int{"first": 1, "1st": 1, "second": 2, "2nd": 2, "third": 3, "3rd": 3, "fourth": 4, "4th": 4, "last": -1}[p.tokens[0]]
+
+	// <ordinal> <weekday> of [the] month [around HH:MM]
+	pos := 2
+	if pos < len(p.tokens) && p.tokens[pos] == "of" {
+		pos++
+	}
+	if pos < len(p.tokens) && p.tokens[pos] == "the" {
+		pos++
+	}
+	if len(p.tokens) < 2 || pos == 2 || pos >= len(p.tokens) || p.tokens[pos] != "month" {
+		return "", fmt.Errorf("invalid format, expected '%s <weekday> of month'", p.tokens[0])
+	}
+
+	cronExpr, err := p.parseWeekdaySchedule(1, pos+1)
+	if err != nil {
+		return "", err
+	}
+	// Cron cannot combine a weekday with a week of the month, so the week is checked at run time
+	p.rule = fmt.Sprintf("nth=%d", nth)
+	return cronExpr, nil
+}
+
+// parseWeekInterval parses week intervals like "every 2 weeks on friday"
+func (p *ScheduleParser) parseWeekInterval() (string, error) {
+	interval, err := strconv.Atoi(p.tokens[1])
+	if err != nil || interval < 1 {
+		return "", fmt.Errorf("invalid interval '%s', must be a positive integer", p.tokens[1])
+	}
+
+	// every N weeks [on <weekday> [around HH:MM]]
+	var cronExpr string
+	if len(p.tokens) == 3 {
+		cronExpr = "FUZZY:WEEKLY * * *"
+	} else {
+		if p.tokens[3] != "on" || len(p.tokens) < 5 {
+			return "", fmt.Errorf("invalid week interval format, expected 'every %d weeks on <weekday>'", interval)
+		}
+		cronExpr, err = p.parseWeekdaySchedule(4, 5)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Cron cannot count weeks, so the interval is checked at run time
+	if interval > 1 {
+		p.rule = fmt.Sprintf("weeks=%d", interval)
+	}
+	return cronExpr, nil
+}
+
+// parseWeekdaySchedule parses the weekday at weekdayPos and an optional "around <time>"
+// starting at timePos into a fuzzy weekly schedule
+func (p *ScheduleParser) parseWeekdaySchedule(weekdayPos, timePos int) (string, error) {
+	weekdayStr := p.tokens[weekdayPos]
+	weekday := mapWeekday(weekdayStr)
+	if weekday == "" {
+		return "", fmt.Errorf("invalid weekday '%s'", weekdayStr)
 	}
+	if timePos >= len(p.tokens) {
+		return fmt.Sprintf("FUZZY:WEEKLY:%s * * *", weekday), nil
+	}
+	if p.tokens[timePos] != "around" {
+		return "", fmt.Errorf("'%s' is not supported after the weekday, use 'around <time>' for a time of day", p.tokens[timePos])
+	}
+	timeStr, err := p.extractTime(timePos + 1)
+	if err != nil {
+		return "", err
+	}
+	minute, hour := parseTime(timeStr)
+	return fmt.Sprintf("FUZZY:WEEKLY_AROUND:%s:%s:%s * * *", weekday, hour, minute), nil
+}
 
-	// Build cron expression: MIN HOUR DOM MONTH DOW
-	return fmt.Sprintf("%s %s %s %s %s", minute, hour, day, month, weekday), nil
+// parseTimezone records the IANA timezone (e.g. "Europe/Kyiv") at the given token position.
+// It reports whether the token is a timezone name.
+func (p *ScheduleParser) parseTimezone(pos int) (bool, error) {
+	if pos >= len(p.tokens) || !strings.Contains(p.tokens[pos], "/") {
+		return false, nil
+	}
+	timezone := p.tokens[pos]
+	if len(p.rawTokens) == len(p.tokens) {
+		timezone = p.rawTokens[pos]
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return true, fmt.Errorf("unknown timezone '%s', use an IANA timezone name like 'Europe/Kyiv'", timezone)
+	}
+	if p.timezone != "" && p.timezone != timezone {
+		return true, fmt.Errorf("conflicting timezones '%s' and '%s'", p.timezone, timezone)
+	}
+	p.timezone = timezone
+	return true, nil
 }
 
 // extractTime extracts the time specification from tokens starting at startPos
@@ -448,6 +583,8 @@ func (p *ScheduleParser) extractTime(startPos int) (string, error) {
 			timeTokens = append(timeTokens, timezoneToken)
 		} else if normalized, ok := normalizeTimezoneAbbreviation(timezoneToken); ok {
 			timeTokens = append(timeTokens, normalized)
+		} else if _, err := p.parseTimezone(nextIndex); err != nil {
+			return "", err
 		}
 	}
 
@@ -465,7 +602,13 @@ func (p *ScheduleParser) extractTimeBetween(startPos, endPos int) (string, error
 	// It might be a single token (e.g., "9am") or multiple tokens (e.g., "14:00 utc+9")
 	timeTokens := []string{}
 	for i := startPos; i < endPos && i < len(p.tokens); i++ {
-		timeTokens = append(timeTokens, p.tokens[i])
+		isTimezone, err := p.parseTimezone(i)
+		if err != nil {
+			return "", err
+		}
+		if !isTimezone {
+			timeTokens = append(timeTokens, p.tokens[i])
+		}
 	}
 
 	if len(timeTokens) == 0 {
@@ -497,6 +640,8 @@ func (p *ScheduleParser) extractTimeAfter(startPos int) (string, error) {
 			timeTokens = append(timeTokens, timezoneToken)
 		} else if normalized, ok := normalizeTimezoneAbbreviation(timezoneToken); ok {
 			timeTokens = append(timeTokens, normalized)
+		} else if _, err := p.parseTimezone(nextIndex); err != nil {
+			return "", err
 		}
 	}
 
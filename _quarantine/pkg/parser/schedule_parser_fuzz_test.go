@@ -355,7 +355,9 @@ func FuzzScheduleParser(f *testing.F) {
 		}
 
 		// 4. Validate cron expression format if successful
+		// (the timezone prefix and calendar rule suffix are validated by ExpandSchedule)
 		if err == nil && cron != "" {
+			_, cron, _ := splitScheduleExtensions(cron)
 			// Allow fuzzy schedules (FUZZY:*) which have 4 fields
 			if strings.HasPrefix(cron, "FUZZY:") {
 				// Fuzzy schedules have the format:
@@ -901,6 +901,117 @@ func TestParseSchedule(t *testing.T) {
 			expectedCron: "*/5 * * * *",
 			expectedOrig: "every 5 minutes",
 		},
+		// Timezones
+		{
+			name:         "daily around with IANA timezone",
+			input:        "daily around 09:00 Europe/Kyiv",
+			expectedCron: "CRON_TZ=Europe/Kyiv FUZZY:DAILY_AROUND:9:0 * * *",
+			expectedOrig: "daily around 09:00 Europe/Kyiv",
+		},
+		{
+			name:         "daily between with IANA timezone",
+			input:        "daily between 9am and 5pm America/New_York",
+			expectedCron: "CRON_TZ=America/New_York FUZZY:DAILY_BETWEEN:9:0:17:0 * * *",
+			expectedOrig: "daily between 9am and 5pm America/New_York",
+		},
+		{
+			name:         "weekly around with IANA timezone",
+			input:        "weekly on friday around 16:00 Europe/Berlin",
+			expectedCron: "CRON_TZ=Europe/Berlin FUZZY:WEEKLY_AROUND:5:16:0 * * *",
+			expectedOrig: "weekly on friday around 16:00 Europe/Berlin",
+		},
+		{
+			name:         "cron expression with timezone",
+			input:        "TZ=Europe/Kyiv 0 9 * * 1-5",
+			expectedCron: "CRON_TZ=Europe/Kyiv 0 9 * * 1-5",
+			expectedOrig: "TZ=Europe/Kyiv 0 9 * * 1-5",
+		},
+		{
+			name:           "unknown timezone",
+			input:          "daily around 09:00 Mars/Olympus",
+			shouldError:    true,
+			errorSubstring: "unknown timezone 'Mars/Olympus'",
+		},
+		{
+			name:           "conflicting timezones",
+			input:          "daily between 09:00 Europe/Kyiv and 10:00 Europe/Berlin",
+			shouldError:    true,
+			errorSubstring: "conflicting timezones",
+		},
+		// Weekdays and weekends
+		{
+			name:         "weekdays",
+			input:        "weekdays",
+			expectedCron: "FUZZY:DAILY * * 1-5",
+			expectedOrig: "weekdays",
+		},
+		{
+			name:         "weekdays between",
+			input:        "weekdays between 08:00 and 10:00",
+			expectedCron: "FUZZY:DAILY_BETWEEN:8:0:10:0 * * 1-5",
+			expectedOrig: "weekdays between 08:00 and 10:00",
+		},
+		{
+			name:         "weekends around",
+			input:        "weekends around 10am",
+			expectedCron: "FUZZY:DAILY_AROUND:10:0 * * 0,6",
+			expectedOrig: "weekends around 10am",
+		},
+		{
+			name:           "weekdays at time",
+			input:          "weekdays at 09:00",
+			shouldError:    true,
+			errorSubstring: "'weekdays at <time>' syntax is not supported",
+		},
+		// Calendar rules
+		{
+			name:         "first weekday of month",
+			input:        "first monday of month",
+			expectedCron: "FUZZY:WEEKLY:1 * * * [nth=1]",
+			expectedOrig: "first monday of month",
+		},
+		{
+			name:         "last weekday of the month around time in timezone",
+			input:        "last friday of the month around 16:00 Europe/Kyiv",
+			expectedCron: "CRON_TZ=Europe/Kyiv FUZZY:WEEKLY_AROUND:5:16:0 * * * [nth=-1]",
+			expectedOrig: "last friday of the month around 16:00 Europe/Kyiv",
+		},
+		{
+			name:         "every 2 weeks on weekday",
+			input:        "every 2 weeks on friday",
+			expectedCron: "FUZZY:WEEKLY:5 * * * [weeks=2]",
+			expectedOrig: "every 2 weeks on friday",
+		},
+		{
+			name:         "every 1 week is weekly",
+			input:        "every 1 week on monday",
+			expectedCron: "FUZZY:WEEKLY:1 * * *",
+			expectedOrig: "every 1 week on monday",
+		},
+		{
+			name:         "every 3 weeks",
+			input:        "every 3 weeks",
+			expectedCron: "FUZZY:WEEKLY * * * [weeks=3]",
+			expectedOrig: "every 3 weeks",
+		},
+		{
+			name:           "nth weekday without month",
+			input:          "first monday",
+			shouldError:    true,
+			errorSubstring: "expected 'first <weekday> of month'",
+		},
+		{
+			name:           "nth weekday with invalid weekday",
+			input:          "second funday of month",
+			shouldError:    true,
+			errorSubstring: "invalid weekday 'funday'",
+		},
+		{
+			name:           "week interval with fixed time",
+			input:          "every 2 weeks on friday at 09:00",
+			shouldError:    true,
+			errorSubstring: "use 'around <time>'",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,317 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/robfig/cron/v3"
+)
+
+var schedulePlannerLog = logger.New("parser:schedule_planner")
+
+// This file contains the repository-wide schedule planner. ScatterSchedule places each
+// fuzzy schedule independently, so two workflows of a repository can still be scattered
+// to the same minute. The planner considers the schedules of every workflow in the
+// repository and moves a fuzzy schedule away from its hashed time when that time is taken.
+// Schedules already compiled into a lock file keep their placement, so planning a new
+// workflow never changes the lock files of the others.
+
+// minutesPerWeek is the number of minute slots the planner models
+const minutesPerWeek = 7 * 1440
+
+// ScheduleEntry is a schedule of a workflow considered by the planner
+type ScheduleEntry struct {
+	Workflow string   // Workflow identifier (repository-relative path of the markdown file)
+	Seed     string   // Scattering seed passed to ScatterSchedule
+	Cron     string   // Cron expression or fuzzy schedule placeholder, as returned by ParseSchedule
+	Current  []string // Cron expressions currently compiled into the workflow's lock file
+}
+
+// SchedulePlan holds the scattered cron expressions chosen for fuzzy schedules
+type SchedulePlan struct {
+	scattered map[schedulePlanKey]string
+}
+
+type schedulePlanKey struct {
+	workflow string
+	cron     string
+}
+
+// Lookup returns the planned cron expression for a fuzzy schedule of a workflow
+func (p *SchedulePlan) Lookup(workflow, fuzzyCron string) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	scattered, ok := p.scattered[schedulePlanKey{workflow: workflow, cron: fuzzyCron}]
+	return scattered, ok
+}
+
+// Len returns the number of planned fuzzy schedules
+func (p *SchedulePlan) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.scattered)
+}
+
+// PlanSchedules scatters the fuzzy schedules of a repository so that workflows fire at
+// distinct minutes where their windows allow.
+//
+// Placement is stable. A fuzzy schedule whose current cron expressions (from its lock file)
+// match a time of its window keeps that time unless it collides with a fixed cron expression
+// or with a compiled schedule placed before it, so compiling a new workflow never moves the
+// schedules of workflows that are already compiled. Other fuzzy schedules keep the time
+// ScatterSchedule picks from their own seed unless that time is taken or collides with the
+// hashed time of another new schedule; of those, the first in workflow order keeps its hashed
+// time. The others move to the nearest time within their window (around or between bounds
+// included) that no schedule fires at or hashes to. When the window has no such time, the
+// time with the fewest overlapping runs is used.
+func PlanSchedules(entries []ScheduleEntry) *SchedulePlan {
+	schedulePlannerLog.Printf("Planning %d schedule(s)", len(entries))
+	plan := &SchedulePlan{scattered: make(map[schedulePlanKey]string)}
+	// load counts the runs placed at each minute, reserved the hashed times of fuzzy schedules
+	load := make([]int, minutesPerWeek)
+	reserved := make([]int, minutesPerWeek)
+
+	var fuzzy []ScheduleEntry
+	for _, entry := range entries {
+		if IsFuzzyCron(entry.Cron) {
+			fuzzy = append(fuzzy, entry)
+			continue
+		}
+		for _, minute := range cronWeekMinutes(entry.Cron) {
+			load[minute]++
+		}
+	}
+
+	sort.SliceStable(fuzzy, func(i, j int) bool {
+		if fuzzy[i].Workflow != fuzzy[j].Workflow {
+			return fuzzy[i].Workflow < fuzzy[j].Workflow
+		}
+		return fuzzy[i].Cron < fuzzy[j].Cron
+	})
+
+	type hashedSchedule struct {
+		key     schedulePlanKey
+		window  *scatterWindow
+		offset  int
+		minutes []int
+		current int // Offset of the compiled placement, or -1 when the schedule is not compiled
+	}
+	var hashed []hashedSchedule
+	seen := make(map[schedulePlanKey]bool)
+	for _, entry := range fuzzy {
+		key := schedulePlanKey{workflow: entry.Workflow, cron: entry.Cron}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		window, err := fuzzyScatterWindow(entry.Cron)
+		if err != nil {
+			schedulePlannerLog.Printf("Skipping %s for %s: %v", entry.Cron, entry.Workflow, err)
+			continue
+		}
+		offset := stableHash(entry.Seed, window.size)
+		minutes := cronWeekMinutes(window.cron(offset))
+		for _, minute := range minutes {
+			reserved[minute]++
+		}
+		current := compiledWindowOffset(window, offset, entry.Current)
+		hashed = append(hashed, hashedSchedule{key: key, window: window, offset: offset, minutes: minutes, current: current})
+	}
+
+	// place assigns a time to a schedule unless one of its minutes is already taken
+	place := func(schedule hashedSchedule, offset int) bool {
+		minutes := schedule.minutes
+		if offset != schedule.offset {
+			minutes = cronWeekMinutes(schedule.window.cron(offset))
+		}
+		for _, minute := range minutes {
+			if load[minute] > 0 {
+				return false
+			}
+		}
+		for _, minute := range minutes {
+			load[minute]++
+		}
+		plan.scattered[schedule.key] = schedule.window.cron(offset)
+		return true
+	}
+
+	// Keep the compiled time of every fuzzy schedule that does not collide with one already placed
+	var pending []hashedSchedule
+	for _, schedule := range hashed {
+		if schedule.current >= 0 && place(schedule, schedule.current) {
+			continue
+		}
+		if schedule.current >= 0 {
+			schedulePlannerLog.Printf("Compiled time of %s for %s collides with another schedule", schedule.key.cron, schedule.key.workflow)
+		}
+		pending = append(pending, schedule)
+	}
+
+	// Keep the hashed time of every other fuzzy schedule that does not collide with one already placed
+	var colliding []hashedSchedule
+	for _, schedule := range pending {
+		if !place(schedule, schedule.offset) {
+			colliding = append(colliding, schedule)
+		}
+	}
+
+	// Move the colliding schedules to the nearest time nothing fires at or hashes to
+	for _, schedule := range colliding {
+		best, bestMinutes, bestCost := "", []int(nil), -1
+		for _, offset := range windowOffsetsByDistance(schedule.offset, schedule.window.size) {
+			candidate := schedule.window.cron(offset)
+			minutes := cronWeekMinutes(candidate)
+			cost := 0
+			for _, minute := range minutes {
+				cost += load[minute] + reserved[minute]
+			}
+			if bestCost < 0 || cost < bestCost {
+				best, bestMinutes, bestCost = candidate, minutes, cost
+			}
+			if cost == 0 {
+				break
+			}
+		}
+
+		for _, minute := range bestMinutes {
+			load[minute]++
+		}
+		plan.scattered[schedule.key] = best
+		schedulePlannerLog.Printf("Moved %s for %s from its hashed time to %s (overlaps=%d)", schedule.key.cron, schedule.key.workflow, best, bestCost)
+	}
+
+	return plan
+}
+
+// compiledWindowOffset returns the offset of the window whose cron expressions are all among
+// the compiled cron expressions, preferring the offsets nearest the hashed one, or -1 when
+// the schedule is not compiled at a time of its window
+func compiledWindowOffset(window *scatterWindow, hashed int, compiled []string) int {
+	if len(compiled) == 0 {
+		return -1
+	}
+	compiledSet := make(map[string]bool, len(compiled))
+	for _, expr := range compiled {
+		compiledSet[expr] = true
+	}
+	for _, offset := range windowOffsetsByDistance(hashed, window.size) {
+		crons, err := ExpandSchedule(window.cron(offset))
+		if err != nil || len(crons) == 0 {
+			continue
+		}
+		matches := true
+		for _, c := range crons {
+			if !compiledSet[c.Cron] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return offset
+		}
+	}
+	return -1
+}
+
+// windowOffsetsByDistance returns the offsets of a window ordered by their distance from
+// the preferred offset, alternating after and before it
+func windowOffsetsByDistance(preferred, size int) []int {
+	offsets := make([]int, 0, size)
+	offsets = append(offsets, preferred)
+	for distance := 1; len(offsets) < size; distance++ {
+		if after := preferred + distance; after < size {
+			offsets = append(offsets, after)
+		}
+		if before := preferred - distance; before >= 0 {
+			offsets = append(offsets, before)
+		}
+	}
+	return offsets
+}
+
+// cronWeekMinutes returns the minutes of the week (0 = Sunday 00:00 UTC) at which a cron
+// expression fires. Schedules restricted by day of month are modelled as firing every day,
+// and the guards of extended expressions (timezone, calendar rules) are ignored.
+func cronWeekMinutes(expr string) []int {
+	crons, err := ExpandSchedule(expr)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[int]bool)
+	var minutes []int
+	for _, c := range crons {
+		spec, ok := parseCronSpec(c.Cron)
+		if !ok {
+			continue
+		}
+		for day := 0; day < 7; day++ {
+			if spec.Dom&cronStarBit != 0 && spec.Dow&(1<<uint(day)) == 0 {
+				continue
+			}
+			for hour := 0; hour < 24; hour++ {
+				if spec.Hour&(1<<uint(hour)) == 0 {
+					continue
+				}
+				for minute := 0; minute < 60; minute++ {
+					weekMinute := day*1440 + hour*60 + minute
+					if spec.Minute&(1<<uint(minute)) != 0 && !seen[weekMinute] {
+						seen[weekMinute] = true
+						minutes = append(minutes, weekMinute)
+					}
+				}
+			}
+		}
+	}
+	sort.Ints(minutes)
+	return minutes
+}
+
+// cronStarBit marks a cron field given as "*" in a parsed cron.SpecSchedule
+const cronStarBit = 1 << 63
+
+// parseCronSpec parses a standard 5-field cron expression
+func parseCronSpec(expr string) (*cron.SpecSchedule, bool) {
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, false
+	}
+	spec, ok := schedule.(*cron.SpecSchedule)
+	if ok {
+		spec.Location = time.UTC
+	}
+	return spec, ok
+}
+
+// CronFireTimes returns the times in [from, to) at which a cron expression fires,
+// evaluated in UTC like GitHub Actions schedules. Extended expressions fire at the times
+// of their UTC cron expressions allowed by the guards.
+func CronFireTimes(expr string, from, to time.Time) ([]time.Time, error) {
+	if IsFuzzyCron(expr) {
+		return nil, fmt.Errorf("invalid cron expression: %s", expr)
+	}
+	crons, err := ExpandSchedule(expr)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[time.Time]bool)
+	var times []time.Time
+	for _, c := range crons {
+		spec, ok := parseCronSpec(c.Cron)
+		if !ok {
+			return nil, fmt.Errorf("invalid cron expression: %s", c.Cron)
+		}
+		for next := spec.Next(from.UTC().Add(-time.Second)); !next.IsZero() && next.Before(to); next = spec.Next(next) {
+			if c.Guard.Allows(next) && !seen[next] {
+				seen[next] = true
+				times = append(times, next)
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times, nil
+}
@@ -0,0 +1,285 @@
+//go:build !integration
+
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlanSchedulesKeepsHashedTimeWithoutCollisions(t *testing.T) {
+	entries := []ScheduleEntry{
+		{Workflow: ".github/workflows/a.md", Seed: "org/repo/.github/workflows/a.md", Cron: "FUZZY:DAILY * * *"},
+		{Workflow: ".github/workflows/b.md", Seed: "org/repo/.github/workflows/b.md", Cron: "FUZZY:HOURLY/2 * * *"},
+	}
+	plan := PlanSchedules(entries)
+	if plan.Len() != 2 {
+		t.Fatalf("expected 2 planned schedules, got %d", plan.Len())
+	}
+	for _, entry := range entries {
+		expected, err := ScatterSchedule(entry.Cron, entry.Seed)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, ok := plan.Lookup(entry.Workflow, entry.Cron)
+		if !ok || got != expected {
+			t.Errorf("expected %s to keep its hashed time %q, got %q", entry.Workflow, expected, got)
+		}
+	}
+
+	if _, ok := plan.Lookup(".github/workflows/c.md", "FUZZY:DAILY * * *"); ok {
+		t.Error("expected no plan for an unknown workflow")
+	}
+	var nilPlan *SchedulePlan
+	if _, ok := nilPlan.Lookup("a", "FUZZY:DAILY * * *"); ok {
+		t.Error("expected a nil plan to have no entries")
+	}
+}
+
+func TestPlanSchedulesAvoidsFixedCron(t *testing.T) {
+	seed := "org/repo/.github/workflows/report.md"
+	hashed, err := ScatterSchedule("FUZZY:DAILY * * *", seed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plan := PlanSchedules([]ScheduleEntry{
+		{Workflow: ".github/workflows/report.md", Seed: seed, Cron: "FUZZY:DAILY * * *"},
+		{Workflow: ".github/workflows/nightly.md", Seed: "org/repo/.github/workflows/nightly.md", Cron: hashed},
+	})
+	got, _ := plan.Lookup(".github/workflows/report.md", "FUZZY:DAILY * * *")
+	if got == hashed {
+		t.Fatalf("expected the fuzzy schedule to move away from %q", hashed)
+	}
+
+	// The nearest free minute is used
+	hashedMinutes := cronWeekMinutes(hashed)[0]
+	gotMinutes := cronWeekMinutes(got)[0]
+	if diff := gotMinutes - hashedMinutes; diff != 1 && diff != -1 {
+		t.Errorf("expected %q to be one minute from %q", got, hashed)
+	}
+}
+
+func TestPlanSchedulesSpreadsWithinWindow(t *testing.T) {
+	var entries []ScheduleEntry
+	for i := 0; i < 5; i++ {
+		workflow := fmt.Sprintf(".github/workflows/w%d.md", i)
+		entries = append(entries, ScheduleEntry{Workflow: workflow, Seed: "org/repo/" + workflow, Cron: "FUZZY:DAILY_BETWEEN:9:00:9:05 * * *"})
+	}
+	plan := PlanSchedules(entries)
+
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		got, ok := plan.Lookup(entry.Workflow, entry.Cron)
+		if !ok {
+			t.Fatalf("expected a plan for %s", entry.Workflow)
+		}
+		if !strings.HasSuffix(got, " 9 * * *") || got[0] < '0' || got[0] > '4' || got[1] != ' ' {
+			t.Errorf("expected %s to fire between 9:00 and 9:05, got %q", entry.Workflow, got)
+		}
+		if other, ok := seen[got]; ok {
+			t.Errorf("expected %s and %s to fire at different minutes, both got %q", entry.Workflow, other, got)
+		}
+		seen[got] = entry.Workflow
+	}
+
+	// A sixth workflow cannot avoid an overlap but stays within its window
+	entries = append(entries, ScheduleEntry{Workflow: ".github/workflows/w5.md", Seed: "org/repo/.github/workflows/w5.md", Cron: "FUZZY:DAILY_BETWEEN:9:00:9:05 * * *"})
+	got, _ := PlanSchedules(entries).Lookup(".github/workflows/w5.md", "FUZZY:DAILY_BETWEEN:9:00:9:05 * * *")
+	if !strings.HasSuffix(got, " 9 * * *") {
+		t.Errorf("expected the sixth workflow to stay within its window, got %q", got)
+	}
+}
+
+func TestPlanSchedulesHourly(t *testing.T) {
+	var entries []ScheduleEntry
+	for i := 0; i < 60; i++ {
+		workflow := fmt.Sprintf(".github/workflows/hourly-%d.md", i)
+		entries = append(entries, ScheduleEntry{Workflow: workflow, Seed: workflow, Cron: "FUZZY:HOURLY/1 * * *"})
+	}
+	plan := PlanSchedules(entries)
+
+	minutes := make(map[string]bool)
+	for _, entry := range entries {
+		got, _ := plan.Lookup(entry.Workflow, entry.Cron)
+		minutes[strings.Fields(got)[0]] = true
+	}
+	if len(minutes) != 60 {
+		t.Errorf("expected 60 hourly workflows to use every minute of the hour, got %d distinct minutes", len(minutes))
+	}
+}
+
+func TestPlanSchedulesIsOrderIndependent(t *testing.T) {
+	entries := []ScheduleEntry{
+		{Workflow: "a.md", Seed: "a.md", Cron: "FUZZY:DAILY_AROUND:9:00 * * *"},
+		{Workflow: "b.md", Seed: "b.md", Cron: "FUZZY:DAILY_AROUND:9:00 * * *"},
+		{Workflow: "c.md", Seed: "c.md", Cron: "FUZZY:WEEKLY:1 * * *"},
+	}
+	reversed := []ScheduleEntry{entries[2], entries[1], entries[0]}
+	plan, reversedPlan := PlanSchedules(entries), PlanSchedules(reversed)
+	for _, entry := range entries {
+		got, _ := plan.Lookup(entry.Workflow, entry.Cron)
+		gotReversed, _ := reversedPlan.Lookup(entry.Workflow, entry.Cron)
+		if got != gotReversed {
+			t.Errorf("expected the same plan for %s regardless of order, got %q and %q", entry.Workflow, got, gotReversed)
+		}
+	}
+}
+
+func TestPlanSchedulesIsStableWhenWorkflowsChange(t *testing.T) {
+	const fuzzyCron = "FUZZY:DAILY_BETWEEN:9:00:9:10 * * *"
+	window, err := fuzzyScatterWindow(fuzzyCron)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entry := func(name string) ScheduleEntry {
+		workflow := ".github/workflows/" + name + ".md"
+		return ScheduleEntry{Workflow: workflow, Seed: "org/repo/" + workflow, Cron: fuzzyCron}
+	}
+	offset := func(e ScheduleEntry) int { return stableHash(e.Seed, window.size) }
+
+	// Find an added workflow "a-*" hashing to the same time as "m-*", and a workflow "z-*"
+	// hashing to the next minute, where "m-*" would move to when the new workflow takes its time
+	var added, moved, neighbour ScheduleEntry
+	found := false
+	for i := 0; i < 1000 && !found; i++ {
+		added = entry(fmt.Sprintf("a-%d", i))
+		for j := 0; j < 1000 && !found; j++ {
+			moved = entry(fmt.Sprintf("m-%d", j))
+			if offset(moved) != offset(added) || offset(moved)+1 >= window.size {
+				continue
+			}
+			for k := 0; k < 1000 && !found; k++ {
+				neighbour = entry(fmt.Sprintf("z-%d", k))
+				found = offset(neighbour) == offset(moved)+1
+			}
+		}
+	}
+	if !found {
+		t.Fatal("no colliding workflow names found")
+	}
+
+	before := PlanSchedules([]ScheduleEntry{moved, neighbour})
+	after := PlanSchedules([]ScheduleEntry{added, moved, neighbour})
+
+	neighbourBefore, _ := before.Lookup(neighbour.Workflow, fuzzyCron)
+	neighbourAfter, _ := after.Lookup(neighbour.Workflow, fuzzyCron)
+	if neighbourAfter != neighbourBefore {
+		t.Errorf("expected %s to keep %q when an unrelated workflow is added, got %q", neighbour.Workflow, neighbourBefore, neighbourAfter)
+	}
+	addedTime, _ := after.Lookup(added.Workflow, fuzzyCron)
+	movedTime, _ := after.Lookup(moved.Workflow, fuzzyCron)
+	if addedTime == movedTime || movedTime == neighbourAfter {
+		t.Errorf("expected distinct times, got %q, %q and %q", addedTime, movedTime, neighbourAfter)
+	}
+
+	// Renaming the moved workflow to a name that collides with nothing keeps the others in place
+	if renamed := entry("renamed"); offset(renamed) != offset(neighbour) {
+		got, _ := PlanSchedules([]ScheduleEntry{renamed, neighbour}).Lookup(neighbour.Workflow, fuzzyCron)
+		if got != neighbourBefore {
+			t.Errorf("expected %s to keep %q when another workflow is renamed, got %q", neighbour.Workflow, neighbourBefore, got)
+		}
+	}
+}
+
+func TestPlanSchedulesKeepsCompiledPlacements(t *testing.T) {
+	const fuzzyCron = "FUZZY:DAILY_BETWEEN:9:00:9:10 * * *"
+	window, err := fuzzyScatterWindow(fuzzyCron)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entry := func(name string) ScheduleEntry {
+		workflow := ".github/workflows/" + name + ".md"
+		return ScheduleEntry{Workflow: workflow, Seed: "org/repo/" + workflow, Cron: fuzzyCron}
+	}
+
+	// Find a new workflow "a-*" sorting before and hashing to the same time as the compiled "m"
+	compiled := entry("m")
+	var added ScheduleEntry
+	found := false
+	for i := 0; i < 1000 && !found; i++ {
+		added = entry(fmt.Sprintf("a-%d", i))
+		found = stableHash(added.Seed, window.size) == stableHash(compiled.Seed, window.size)
+	}
+	if !found {
+		t.Fatal("no colliding workflow name found")
+	}
+
+	compiledTime, _ := PlanSchedules([]ScheduleEntry{compiled}).Lookup(compiled.Workflow, fuzzyCron)
+	compiled.Current = []string{compiledTime}
+	plan := PlanSchedules([]ScheduleEntry{added, compiled})
+	if got, _ := plan.Lookup(compiled.Workflow, fuzzyCron); got != compiledTime {
+		t.Errorf("expected the compiled workflow to keep %q, got %q", compiledTime, got)
+	}
+	if got, _ := plan.Lookup(added.Workflow, fuzzyCron); got == compiledTime {
+		t.Errorf("expected the new workflow to move away from %q", compiledTime)
+	}
+
+	// A compiled placement that is no longer a time of the window is planned again
+	compiled.Current = []string{"0 3 * * *"}
+	if got, _ := PlanSchedules([]ScheduleEntry{compiled}).Lookup(compiled.Workflow, fuzzyCron); got != compiledTime {
+		t.Errorf("expected a stale placement to be replaced by %q, got %q", compiledTime, got)
+	}
+
+	// A compiled placement colliding with a fixed cron expression moves
+	fixed := ScheduleEntry{Workflow: ".github/workflows/fixed.md", Cron: compiledTime}
+	compiled.Current = []string{compiledTime}
+	if got, _ := PlanSchedules([]ScheduleEntry{compiled, fixed}).Lookup(compiled.Workflow, fuzzyCron); got == compiledTime {
+		t.Errorf("expected the compiled workflow to move away from the fixed cron %q", compiledTime)
+	}
+}
+
+func TestWindowOffsetsByDistance(t *testing.T) {
+	got := fmt.Sprint(windowOffsetsByDistance(1, 5))
+	if got != "[1 2 0 3 4]" {
+		t.Errorf("unexpected offsets: %s", got)
+	}
+}
+
+func TestCronWeekMinutes(t *testing.T) {
+	tests := []struct {
+		cron     string
+		expected int
+	}{
+		{"30 9 * * *", 7},
+		{"30 9 * * 1", 1},
+		{"0 */6 * * *", 28},
+		{"5 3 */14 * *", 7},
+		{"not a cron", 0},
+	}
+	for _, tt := range tests {
+		if got := len(cronWeekMinutes(tt.cron)); got != tt.expected {
+			t.Errorf("cronWeekMinutes(%q): expected %d minutes, got %d", tt.cron, tt.expected, got)
+		}
+	}
+	if got := cronWeekMinutes("30 9 * * 1")[0]; got != 1440+9*60+30 {
+		t.Errorf("expected Monday 09:30 to be minute %d of the week, got %d", 1440+9*60+30, got)
+	}
+}
+
+func TestCronFireTimes(t *testing.T) {
+	start := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	times, err := CronFireTimes("0 */6 * * *", start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, fire := range times {
+		got = append(got, fire.Format("02 15:04"))
+	}
+	if strings.Join(got, ",") != "02 12:00,02 18:00,03 00:00,03 06:00" {
+		t.Errorf("unexpected fire times: %v", got)
+	}
+
+	// The start is inclusive
+	times, _ = CronFireTimes("0 8 * * *", start, start.Add(time.Hour))
+	if len(times) != 1 || !times[0].Equal(start) {
+		t.Errorf("expected the start time to be included, got %v", times)
+	}
+
+	if _, err := CronFireTimes("FUZZY:DAILY * * *", start, start.Add(time.Hour)); err == nil {
+		t.Error("expected an error for a fuzzy schedule")
+	}
+}
@@ -0,0 +1,324 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var scheduleTimezoneLog = logger.New("parser:schedule_timezone")
+
+// This file contains timezone and calendar support for schedule expressions.
+//
+// GitHub Actions evaluates cron schedules in UTC and has no notion of "the first Monday
+// of the month" or "every other week". Schedules that need either are encoded as an
+// extended expression around the cron body (or fuzzy placeholder):
+//
+//	CRON_TZ=Europe/Kyiv FUZZY:DAILY_AROUND:9:0 * * *   (local time in an IANA timezone)
+//	FUZZY:WEEKLY:1 * * * [nth=1]                        (first Monday of the month)
+//	FUZZY:WEEKLY:5 * * * [weeks=2]                      (every 2 weeks on Friday)
+//
+// ExpandSchedule turns a scattered extended expression into UTC cron expressions. When a
+// timezone observes daylight saving time, one cron expression is emitted per UTC offset,
+// restricted to the months in which that offset occurs. Rules that cron cannot represent
+// (the offset of a month with a transition, the nth weekday, week intervals) are carried
+// by a ScheduleGuard that the pre-activation job evaluates at run time.
+
+// scheduleTimezonePrefix is the prefix naming the timezone of a schedule (robfig/cron convention)
+const scheduleTimezonePrefix = "CRON_TZ="
+
+// ScheduleGuard is the runtime check of a cron expression emitted for a schedule that cron
+// cannot represent exactly. Zero fields are not checked.
+type ScheduleGuard struct {
+	Timezone   string `json:"timezone,omitempty"`    // IANA timezone the rules are evaluated in (default: UTC)
+	UTCOffset  *int   `json:"utc_offset,omitempty"`  // Required UTC offset of the timezone, in minutes
+	Nth        int    `json:"nth,omitempty"`         // Required week of the month (1-4, or -1 for the last)
+	EveryWeeks int    `json:"every_weeks,omitempty"` // Required interval in weeks, counted from Monday 1970-01-05
+}
+
+// ScheduleCron is a UTC cron expression emitted for a schedule, with its optional guard
+type ScheduleCron struct {
+	Cron  string
+	Guard *ScheduleGuard
+}
+
+// splitScheduleExtensions splits an extended schedule expression into its timezone, its
+// cron body and its calendar rule (without brackets)
+func splitScheduleExtensions(expr string) (timezone, body, rule string) {
+	body = strings.TrimSpace(expr)
+	if strings.HasPrefix(body, scheduleTimezonePrefix) || strings.HasPrefix(body, "TZ=") {
+		prefix, rest, _ := strings.Cut(body, " ")
+		_, timezone, _ = strings.Cut(prefix, "=")
+		body = strings.TrimSpace(rest)
+	}
+	if strings.HasSuffix(body, "]") {
+		if open := strings.LastIndex(body, " ["); open >= 0 {
+			rule = body[open+2 : len(body)-1]
+			body = strings.TrimSpace(body[:open])
+		}
+	}
+	return timezone, body, rule
+}
+
+// joinScheduleExtensions is the inverse of splitScheduleExtensions
+func joinScheduleExtensions(timezone, body, rule string) string {
+	if timezone != "" {
+		body = scheduleTimezonePrefix + timezone + " " + body
+	}
+	if rule != "" {
+		body += " [" + rule + "]"
+	}
+	return body
+}
+
+// isTimezoneCronExpression checks if the input is a cron expression with a timezone prefix
+// (e.g., "CRON_TZ=Europe/Berlin 0 9 * * 1-5")
+func isTimezoneCronExpression(input string) bool {
+	timezone, body, rule := splitScheduleExtensions(input)
+	return timezone != "" && rule == "" && IsCronExpression(body)
+}
+
+// parseScheduleRule parses the calendar rule of an extended schedule expression
+func parseScheduleRule(rule string) (ScheduleGuard, error) {
+	var guard ScheduleGuard
+	if rule == "" {
+		return guard, nil
+	}
+	key, value, _ := strings.Cut(rule, "=")
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return guard, fmt.Errorf("invalid schedule rule '%s'", rule)
+	}
+	switch key {
+	case "nth":
+		if n == 0 || n < -1 || n > 4 {
+			return guard, fmt.Errorf("invalid week of the month in schedule rule '%s', must be 1-4 or -1 (last)", rule)
+		}
+		guard.Nth = n
+	case "weeks":
+		if n < 2 {
+			return guard, fmt.Errorf("invalid week interval in schedule rule '%s', must be at least 2", rule)
+		}
+		guard.EveryWeeks = n
+	default:
+		return guard, fmt.Errorf("unsupported schedule rule '%s'", rule)
+	}
+	return guard, nil
+}
+
+// ExpandSchedule converts a scattered schedule expression into the UTC cron expressions
+// to emit. Plain cron expressions are returned as-is; extended expressions with a timezone
+// or a calendar rule may expand into several guarded cron expressions.
+func ExpandSchedule(expr string) ([]ScheduleCron, error) {
+	timezone, body, rule := splitScheduleExtensions(expr)
+	if IsFuzzyCron(body) {
+		return nil, fmt.Errorf("fuzzy schedule '%s' must be scattered before it is expanded", expr)
+	}
+	if !IsCronExpression(body) {
+		return nil, fmt.Errorf("invalid cron expression '%s': must have exactly 5 fields (minute hour day-of-month month day-of-week)", body)
+	}
+	guard, err := parseScheduleRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	if timezone == "" && rule == "" {
+		return []ScheduleCron{{Cron: body}}, nil
+	}
+
+	fields := strings.Fields(body)
+	minute, minuteErr := strconv.Atoi(fields[0])
+	hour, hourErr := strconv.Atoi(fields[1])
+	if minuteErr != nil || hourErr != nil || minute > 59 || hour > 23 {
+		return nil, fmt.Errorf("schedule '%s' needs a fixed time of day to use a timezone or calendar rule", expr)
+	}
+
+	if timezone == "" {
+		return []ScheduleCron{{Cron: body, Guard: &guard}}, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone '%s' in schedule '%s'", timezone, expr)
+	}
+	guard.Timezone = timezone
+
+	offsets, months := zoneOffsetMonths(location, hour, minute, scheduleReferenceYear, scheduleReferenceYears)
+	scheduleTimezoneLog.Printf("Expanding %s: %d UTC offset(s) in %s", expr, len(offsets), timezone)
+
+	var crons []ScheduleCron
+	for _, offset := range offsets {
+		utcMinutes := hour*60 + minute - offset
+		dayShift := 0
+		if utcMinutes < 0 {
+			dayShift = -1
+		} else if utcMinutes >= 1440 {
+			dayShift = 1
+		}
+		utcMinutes -= dayShift * 1440
+		if dayShift != 0 && (fields[2] != "*" || fields[3] != "*") {
+			return nil, fmt.Errorf("schedule '%s' cannot be converted to UTC: day-of-month and month schedules in %s must not cross midnight UTC", expr, timezone)
+		}
+
+		dayOfWeek, err := shiftCronWeekdays(fields[4], dayShift)
+		if err != nil {
+			return nil, fmt.Errorf("invalid day-of-week in schedule '%s': %w", expr, err)
+		}
+
+		month := fields[3]
+		cronGuard := guard
+		if len(offsets) > 1 {
+			// Months in which the offset occurs, plus the adjacent month the UTC time falls
+			// in when it crosses midnight at a month boundary
+			active := make(map[int]bool)
+			for _, m := range months[offset] {
+				active[m] = true
+				if dayShift != 0 {
+					active[(m+dayShift+11)%12+1] = true
+				}
+			}
+			month = formatCronValues(active, 1, 12)
+			utcOffset := offset
+			cronGuard.UTCOffset = &utcOffset
+		}
+
+		var cronGuardPtr *ScheduleGuard
+		if cronGuard != (ScheduleGuard{Timezone: timezone}) {
+			cronGuardPtr = &cronGuard
+		}
+		crons = append(crons, ScheduleCron{
+			Cron:  fmt.Sprintf("%d %d %s %s %s", utcMinutes%60, utcMinutes/60, fields[2], month, dayOfWeek),
+			Guard: cronGuardPtr,
+		})
+	}
+	return crons, nil
+}
+
+// scheduleReferenceYear and scheduleReferenceYears are the years whose timezone transitions
+// are used to convert schedules to UTC. Fixed years keep compiled cron expressions independent
+// of the current date, so lock files do not change when the year does. Weekday-based daylight
+// saving time rules repeat every 28 years, so the months of each UTC offset over one cycle
+// cover every year; the UTC offset guard selects the right cron expression at run time.
+const (
+	scheduleReferenceYear  = 2025
+	scheduleReferenceYears = 28
+)
+
+// zoneOffsetMonths returns the UTC offsets (in minutes) a timezone has at the given local
+// time of day over the given years, in order of first occurrence, with the months in which
+// each offset occurs in any of the years
+func zoneOffsetMonths(location *time.Location, hour, minute, firstYear, years int) ([]int, map[int][]int) {
+	var offsets []int
+	monthSets := make(map[int]map[int]bool)
+	start := time.Date(firstYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(years, 0, 0)
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		_, seconds := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, location).Zone()
+		offset := seconds / 60
+		if monthSets[offset] == nil {
+			offsets = append(offsets, offset)
+			monthSets[offset] = make(map[int]bool)
+		}
+		monthSets[offset][int(day.Month())] = true
+	}
+
+	months := make(map[int][]int, len(monthSets))
+	for offset, set := range monthSets {
+		for month := 1; month <= 12; month++ {
+			if set[month] {
+				months[offset] = append(months[offset], month)
+			}
+		}
+	}
+	return offsets, months
+}
+
+// shiftCronWeekdays shifts the values of a cron day-of-week field by the given number of days
+func shiftCronWeekdays(field string, shift int) (string, error) {
+	if field == "*" || shift == 0 {
+		return field, nil
+	}
+	spec, ok := parseCronSpec("0 0 * * " + field)
+	if !ok {
+		return "", fmt.Errorf("unsupported day-of-week '%s'", field)
+	}
+	days := make(map[int]bool)
+	for day := 0; day < 7; day++ {
+		if spec.Dow&(1<<uint(day)) != 0 {
+			days[(day+shift+7)%7] = true
+		}
+	}
+	return formatCronValues(days, 0, 6), nil
+}
+
+// formatCronValues renders a set of cron field values as ranges and lists (e.g. "1-3,10-12"),
+// or "*" when every value from min to max is included
+func formatCronValues(values map[int]bool, min, max int) string {
+	sorted := make([]int, 0, len(values))
+	for value := range values {
+		sorted = append(sorted, value)
+	}
+	sort.Ints(sorted)
+	if len(sorted) == max-min+1 {
+		return "*"
+	}
+
+	var parts []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		switch {
+		case j == i:
+			parts = append(parts, strconv.Itoa(sorted[i]))
+		case j == i+1:
+			parts = append(parts, strconv.Itoa(sorted[i]), strconv.Itoa(sorted[j]))
+		default:
+			parts = append(parts, fmt.Sprintf("%d-%d", sorted[i], sorted[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// Allows reports whether a run scheduled at the given time satisfies the guard
+func (g *ScheduleGuard) Allows(scheduled time.Time) bool {
+	if g == nil {
+		return true
+	}
+	location := time.UTC
+	if g.Timezone != "" {
+		loc, err := time.LoadLocation(g.Timezone)
+		if err != nil {
+			return false
+		}
+		location = loc
+	}
+	local := scheduled.In(location)
+
+	if g.UTCOffset != nil {
+		if _, seconds := local.Zone(); seconds/60 != *g.UTCOffset {
+			return false
+		}
+	}
+	if g.Nth > 0 && (local.Day()-1)/7+1 != g.Nth {
+		return false
+	}
+	if g.Nth == -1 && local.AddDate(0, 0, 7).Month() == local.Month() {
+		return false
+	}
+	if g.EveryWeeks > 1 {
+		date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+		weeks := int(date.Sub(scheduleGuardEpoch).Hours()/24) / 7
+		if weeks%g.EveryWeeks != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// scheduleGuardEpoch is the Monday week intervals are counted from
+var scheduleGuardEpoch = time.Date(1970, time.January, 5, 0, 0, 0, 0, time.UTC)
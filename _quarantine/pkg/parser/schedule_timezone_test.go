@@ -0,0 +1,280 @@
+//go:build !integration
+
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitScheduleExtensions(t *testing.T) {
+	tests := []struct {
+		expr     string
+		timezone string
+		body     string
+		rule     string
+	}{
+		{"0 9 * * *", "", "0 9 * * *", ""},
+		{"CRON_TZ=Europe/Kyiv FUZZY:DAILY * * *", "Europe/Kyiv", "FUZZY:DAILY * * *", ""},
+		{"TZ=Europe/Kyiv 0 9 * * *", "Europe/Kyiv", "0 9 * * *", ""},
+		{"FUZZY:WEEKLY:1 * * * [nth=1]", "", "FUZZY:WEEKLY:1 * * *", "nth=1"},
+		{"CRON_TZ=Europe/Berlin 30 8 * * 5 [weeks=2]", "Europe/Berlin", "30 8 * * 5", "weeks=2"},
+	}
+	for _, tt := range tests {
+		timezone, body, rule := splitScheduleExtensions(tt.expr)
+		if timezone != tt.timezone || body != tt.body || rule != tt.rule {
+			t.Errorf("splitScheduleExtensions(%q) = (%q, %q, %q), expected (%q, %q, %q)", tt.expr, timezone, body, rule, tt.timezone, tt.body, tt.rule)
+		}
+	}
+}
+
+func TestIsFuzzyCronWithExtensions(t *testing.T) {
+	if !IsFuzzyCron("CRON_TZ=Europe/Kyiv FUZZY:DAILY_AROUND:9:0 * * *") {
+		t.Error("expected a fuzzy schedule in a timezone to be fuzzy")
+	}
+	if !IsFuzzyCron("FUZZY:WEEKLY:1 * * * [nth=1]") {
+		t.Error("expected a fuzzy schedule with a calendar rule to be fuzzy")
+	}
+	if IsFuzzyCron("CRON_TZ=Europe/Kyiv 0 9 * * *") {
+		t.Error("expected a cron expression in a timezone not to be fuzzy")
+	}
+}
+
+func TestScatterScheduleKeepsExtensions(t *testing.T) {
+	plain, err := ScatterSchedule("FUZZY:DAILY_AROUND:9:0 * * *", "org/repo/workflow.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := ScatterSchedule("CRON_TZ=Europe/Kyiv FUZZY:DAILY_AROUND:9:0 * * *", "org/repo/workflow.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "CRON_TZ=Europe/Kyiv "+plain {
+		t.Errorf("expected the timezone to be kept around %q, got %q", plain, got)
+	}
+
+	got, err = ScatterSchedule("FUZZY:WEEKLY:1 * * * [nth=1]", "org/repo/workflow.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(got, " * * 1 [nth=1]") {
+		t.Errorf("expected a Monday cron expression with the rule, got %q", got)
+	}
+
+	got, err = ScatterSchedule("FUZZY:DAILY_BETWEEN:8:0:10:0 * * 1-5", "org/repo/workflow.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields := strings.Fields(got)
+	if len(fields) != 5 || fields[4] != "1-5" || (fields[1] != "8" && fields[1] != "9") {
+		t.Errorf("expected a weekday cron expression between 8:00 and 10:00, got %q", got)
+	}
+}
+
+func TestExpandSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		crons   []string
+		guarded bool
+	}{
+		{
+			name:  "plain cron expression",
+			expr:  "30 9 * * 1-5",
+			crons: []string{"30 9 * * 1-5"},
+		},
+		{
+			name:    "timezone with daylight saving time",
+			expr:    "CRON_TZ=Europe/Kyiv 30 9 * * 1-5",
+			crons:   []string{"30 7 * 1-3,10-12 1-5", "30 6 * 3-10 1-5"},
+			guarded: true,
+		},
+		{
+			name:  "timezone without daylight saving time",
+			expr:  "CRON_TZ=Asia/Tokyo 30 9 * * *",
+			crons: []string{"30 0 * * *"},
+		},
+		{
+			name:  "timezone crossing midnight",
+			expr:  "CRON_TZ=Asia/Tokyo 30 5 * * 1",
+			crons: []string{"30 20 * * 0"},
+		},
+		{
+			name:    "calendar rule",
+			expr:    "15 8 * * 1 [nth=1]",
+			crons:   []string{"15 8 * * 1"},
+			guarded: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expanded, err := ExpandSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var crons []string
+			for _, c := range expanded {
+				crons = append(crons, c.Cron)
+				if (c.Guard != nil) != tt.guarded {
+					t.Errorf("expected guarded=%v for %q, got guard %+v", tt.guarded, c.Cron, c.Guard)
+				}
+			}
+			if strings.Join(crons, "|") != strings.Join(tt.crons, "|") {
+				t.Errorf("expected %v, got %v", tt.crons, crons)
+			}
+		})
+	}
+
+	expanded, _ := ExpandSchedule("CRON_TZ=Europe/Kyiv 30 9 * * 1-5")
+	if offset := expanded[0].Guard.UTCOffset; offset == nil || *offset != 120 {
+		t.Errorf("expected the winter cron expression to require UTC+2, got %v", offset)
+	}
+	if offset := expanded[1].Guard.UTCOffset; offset == nil || *offset != 180 {
+		t.Errorf("expected the summer cron expression to require UTC+3, got %v", offset)
+	}
+}
+
+func TestExpandScheduleIsYearIndependent(t *testing.T) {
+	for _, timezone := range []string{"Europe/Kyiv", "America/New_York", "Australia/Sydney", "Asia/Tokyo"} {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// The months of the reference years cover the months of every single year, including
+		// years where a transition falls on the first day of a month
+		_, refMonths := zoneOffsetMonths(location, 9, 30, scheduleReferenceYear, scheduleReferenceYears)
+		for year := 2020; year <= 2060; year++ {
+			offsets, months := zoneOffsetMonths(location, 9, 30, year, 1)
+			for _, offset := range offsets {
+				ref := make(map[int]bool)
+				for _, month := range refMonths[offset] {
+					ref[month] = true
+				}
+				for _, month := range months[offset] {
+					if !ref[month] {
+						t.Errorf("%s has UTC offset %d in month %d of %d, which the reference years do not cover", timezone, offset, month, year)
+					}
+				}
+			}
+		}
+
+		expr := "CRON_TZ=" + timezone + " 30 9 * * 1-5"
+		first, err := ExpandSchedule(expr)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second, _ := ExpandSchedule(expr)
+		if describeScheduleCrons(first) != describeScheduleCrons(second) {
+			t.Errorf("expected %s to expand identically, got %s and %s", expr, describeScheduleCrons(first), describeScheduleCrons(second))
+		}
+	}
+
+	// Sydney leaves daylight saving time on the first Sunday of April, which is April 1 in some years
+	expanded, err := ExpandSchedule("CRON_TZ=Australia/Sydney 30 9 * * 1-5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := describeScheduleCrons(expanded); got != "30 22 * 1-4,9-12 0-4 (+660) | 30 23 * 3-10 0-4 (+600)" {
+		t.Errorf("unexpected Sydney expansion: %s", got)
+	}
+}
+
+// describeScheduleCrons formats expanded cron expressions with their UTC offset guards
+func describeScheduleCrons(crons []ScheduleCron) string {
+	parts := make([]string, 0, len(crons))
+	for _, c := range crons {
+		part := c.Cron
+		if c.Guard != nil && c.Guard.UTCOffset != nil {
+			part += fmt.Sprintf(" (%+d)", *c.Guard.UTCOffset)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " | ")
+}
+
+func TestExpandScheduleErrors(t *testing.T) {
+	tests := []struct {
+		expr           string
+		errorSubstring string
+	}{
+		{"FUZZY:DAILY * * *", "must be scattered"},
+		{"CRON_TZ=Mars/Olympus 0 9 * * *", "unknown timezone 'Mars/Olympus'"},
+		{"CRON_TZ=Europe/Kyiv */5 * * * *", "needs a fixed time of day"},
+		{"CRON_TZ=Asia/Tokyo 30 5 1 * *", "must not cross midnight UTC"},
+		{"0 9 * * 1 [weeks=1]", "must be at least 2"},
+		{"0 9 * * 1 [yearly=1]", "unsupported schedule rule"},
+	}
+	for _, tt := range tests {
+		_, err := ExpandSchedule(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.errorSubstring) {
+			t.Errorf("ExpandSchedule(%q): expected error containing %q, got %v", tt.expr, tt.errorSubstring, err)
+		}
+	}
+}
+
+func TestScheduleGuardAllows(t *testing.T) {
+	winter, summer := 120, 180
+	tests := []struct {
+		name      string
+		guard     *ScheduleGuard
+		scheduled string
+		allowed   bool
+	}{
+		{"nil guard", nil, "2026-03-09T09:00:00Z", true},
+		{"offset matches", &ScheduleGuard{Timezone: "Europe/Kyiv", UTCOffset: &winter}, "2026-01-12T07:30:00Z", true},
+		{"offset differs", &ScheduleGuard{Timezone: "Europe/Kyiv", UTCOffset: &winter}, "2026-07-13T07:30:00Z", false},
+		{"summer offset", &ScheduleGuard{Timezone: "Europe/Kyiv", UTCOffset: &summer}, "2026-07-13T06:30:00Z", true},
+		{"first week", &ScheduleGuard{Nth: 1}, "2026-03-02T09:00:00Z", true},
+		{"second week", &ScheduleGuard{Nth: 1}, "2026-03-09T09:00:00Z", false},
+		{"last week", &ScheduleGuard{Nth: -1}, "2026-03-30T09:00:00Z", true},
+		{"not last week", &ScheduleGuard{Nth: -1}, "2026-03-23T09:00:00Z", false},
+		{"first week in local date", &ScheduleGuard{Timezone: "Europe/Kyiv", Nth: 1}, "2026-02-28T23:30:00Z", true},
+		{"even week", &ScheduleGuard{EveryWeeks: 2}, "2026-01-09T09:00:00Z", true},
+		{"odd week", &ScheduleGuard{EveryWeeks: 2}, "2026-01-16T09:00:00Z", false},
+	}
+	for _, tt := range tests {
+		scheduled, err := time.Parse(time.RFC3339, tt.scheduled)
+		if err != nil {
+			t.Fatalf("invalid test time: %v", err)
+		}
+		if got := tt.guard.Allows(scheduled); got != tt.allowed {
+			t.Errorf("%s: expected Allows(%s) = %v, got %v", tt.name, tt.scheduled, tt.allowed, got)
+		}
+	}
+}
+
+func TestCronFireTimesWithGuards(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// Only the first Monday of March (the 2nd) is allowed
+	times, err := CronFireTimes("0 9 * * 1 [nth=1]", start, start.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(times) != 1 || times[0].Day() != 2 {
+		t.Errorf("expected a single run on March 2nd, got %v", times)
+	}
+
+	// 09:30 in Kyiv is 07:30 UTC before the switch to summer time (March 29th) and 06:30 after
+	times, err = CronFireTimes("CRON_TZ=Europe/Kyiv 30 9 * * *", time.Date(2026, 3, 27, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, fire := range times {
+		got = append(got, fire.Format("02 15:04"))
+	}
+	if strings.Join(got, ",") != "27 07:30,28 07:30,29 06:30,30 06:30" {
+		t.Errorf("unexpected fire times: %v", got)
+	}
+}
+
+func TestCronWeekMinutesWithTimezone(t *testing.T) {
+	// Both UTC offsets of a daylight saving timezone are modelled
+	if got := len(cronWeekMinutes("CRON_TZ=Europe/Kyiv 30 9 * * 1")); got != 2 {
+		t.Errorf("expected 2 minutes of the week, got %d", got)
+	}
+}
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var schemaDiagnosticsLog = logger.New("parser:schema_diagnostics")
+
+// SchemaDiagnostic is a single frontmatter validation problem with its location in the
+// frontmatter YAML. Line and Column are 1-based and relative to the frontmatter content;
+// a zero Line means the problem could not be located.
+type SchemaDiagnostic struct {
+	Line    int
+	Column  int
+	Path    string // JSON path of the invalid value (e.g., "/tools/github")
+	Message string
+}
+
+// ValidateMainWorkflowFrontmatterDiagnostics validates main workflow frontmatter like
+// ValidateMainWorkflowFrontmatterWithSchema but reports every schema violation located in
+// frontmatterYAML instead of a single formatted error. Used by editors to show all
+// problems at once without reading the file from disk.
+func ValidateMainWorkflowFrontmatterDiagnostics(frontmatter map[string]any, frontmatterYAML string) []SchemaDiagnostic {
+	filtered := filterIgnoredFields(frontmatter)
+
+	if err := validateCommandTriggerConflicts(filtered); err != nil {
+		return []SchemaDiagnostic{{Message: err.Error()}}
+	}
+
+	if err := validateWithSchema(filtered, mainWorkflowSchema, "main workflow file"); err != nil {
+		jsonPaths := ExtractJSONPathFromValidationError(err)
+		if len(jsonPaths) == 0 {
+			return []SchemaDiagnostic{{Message: cleanJSONSchemaErrorMessage(err.Error())}}
+		}
+
+		schemaDiagnosticsLog.Printf("Schema validation found %d problems", len(jsonPaths))
+		diagnostics := make([]SchemaDiagnostic, 0, len(jsonPaths))
+		for _, pathInfo := range jsonPaths {
+			message := rewriteAdditionalPropertiesError(pathInfo.Message)
+			if suggestions := generateSchemaBasedSuggestions(mainWorkflowSchema, pathInfo.Message, pathInfo.Path); suggestions != "" {
+				message = message + ". " + suggestions
+			}
+
+			diagnostic := SchemaDiagnostic{Path: pathInfo.Path, Message: message}
+			if location := LocateJSONPathInYAMLWithAdditionalProperties(frontmatterYAML, pathInfo.Path, pathInfo.Message); location.Found {
+				diagnostic.Line = location.Line
+				diagnostic.Column = location.Column
+			}
+			diagnostics = append(diagnostics, diagnostic)
+		}
+		return diagnostics
+	}
+
+	if err := validateEngineSpecificRules(filtered); err != nil {
+		return []SchemaDiagnostic{{Message: err.Error()}}
+	}
+	return nil
+}
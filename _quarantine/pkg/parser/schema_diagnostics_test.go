@@ -0,0 +1,37 @@
+//go:build !integration
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMainWorkflowFrontmatterDiagnostics(t *testing.T) {
+	frontmatterYAML := "on: issues\ntools:\n  github:\n    toolset: [repos]\ntimeout-minutes: soon"
+	frontmatter := map[string]any{
+		"on":              "issues",
+		"tools":           map[string]any{"github": map[string]any{"toolset": []any{"repos"}}},
+		"timeout-minutes": "soon",
+	}
+
+	diagnostics := ValidateMainWorkflowFrontmatterDiagnostics(frontmatter, frontmatterYAML)
+	require.Len(t, diagnostics, 2, "every schema violation should be reported")
+
+	byPath := make(map[string]SchemaDiagnostic)
+	for _, d := range diagnostics {
+		byPath[d.Path] = d
+	}
+	toolset := byPath["/tools/github"]
+	assert.Equal(t, 4, toolset.Line)
+	assert.Equal(t, 5, toolset.Column)
+	assert.Contains(t, toolset.Message, "Did you mean 'toolsets'?")
+	assert.Equal(t, 5, byPath["/timeout-minutes"].Line)
+}
+
+func TestValidateMainWorkflowFrontmatterDiagnosticsValid(t *testing.T) {
+	frontmatter := map[string]any{"on": "issues", "engine": "claude"}
+	assert.Empty(t, ValidateMainWorkflowFrontmatterDiagnostics(frontmatter, "on: issues\nengine: claude"))
+}
@@ -25,11 +25,19 @@ type ActionCacheEntry struct {
 	SHA     string `json:"sha"`
 }
 
+// ContainerCacheEntry represents a cached container image digest resolution.
+type ContainerCacheEntry struct {
+	Image  string `json:"image"`
+	Tag    string `json:"tag"`
+	Digest string `json:"digest"`
+}
+
 // ActionCache manages cached action pin resolutions.
 type ActionCache struct {
-	Entries map[string]ActionCacheEntry `json:"entries"` // key: "repo@version"
-	path    string
-	dirty   bool // tracks if cache has unsaved changes
+	Entries    map[string]ActionCacheEntry    `json:"entries"`              // key: "repo@version"
+	Containers map[string]ContainerCacheEntry `json:"containers,omitempty"` // key: "image:tag"
+	path       string
+	dirty      bool // tracks if cache has unsaved changes
 }
 
 // NewActionCache creates a new action cache instance
@@ -37,8 +45,9 @@ func NewActionCache(repoRoot string) *ActionCache {
 	cachePath := filepath.Join(repoRoot, ".github", "aw", CacheFileName)
 	actionCacheLog.Printf("Creating action cache with path: %s", cachePath)
 	return &ActionCache{
-		Entries: make(map[string]ActionCacheEntry),
-		path:    cachePath,
+		Entries:    make(map[string]ActionCacheEntry),
+		Containers: make(map[string]ContainerCacheEntry),
+		path:       cachePath,
 		// dirty is initialized to false (zero value)
 	}
 }
@@ -62,10 +71,17 @@ func (c *ActionCache) Load() error {
 		return err
 	}
 
+	if c.Entries == nil {
+		c.Entries = make(map[string]ActionCacheEntry)
+	}
+	if c.Containers == nil {
+		c.Containers = make(map[string]ContainerCacheEntry)
+	}
+
 	// Mark cache as clean after successful load (it matches disk state)
 	c.dirty = false
 
-	actionCacheLog.Printf("Successfully loaded cache with %d entries", len(c.Entries))
+	actionCacheLog.Printf("Successfully loaded cache with %d entries and %d container digests", len(c.Entries), len(c.Containers))
 	return nil
 }
 
@@ -83,7 +99,7 @@ func (c *ActionCache) Save() error {
 	actionCacheLog.Printf("Saving action cache to: %s with %d entries", c.path, len(c.Entries))
 
 	// If cache is empty, skip saving and delete the file if it exists
-	if len(c.Entries) == 0 {
+	if len(c.Entries) == 0 && len(c.Containers) == 0 {
 		actionCacheLog.Print("Cache is empty, skipping file creation")
 		// Remove the file if it exists
 		if _, err := os.Stat(c.path); err == nil {
@@ -160,7 +176,33 @@ func (c *ActionCache) marshalSorted() ([]byte, error) {
 		result = append(result, '\n')
 	}
 
-	result = append(result, []byte("  }\n}")...)
+	result = append(result, []byte("  }")...)
+
+	// Container digests are only written when present so existing cache files are unchanged
+	if len(c.Containers) > 0 {
+		containerKeys := make([]string, 0, len(c.Containers))
+		for key := range c.Containers {
+			containerKeys = append(containerKeys, key)
+		}
+		sort.Strings(containerKeys)
+
+		result = append(result, []byte(",\n  \"containers\": {\n")...)
+		for i, key := range containerKeys {
+			entryJSON, err := json.MarshalIndent(c.Containers[key], "    ", "  ")
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, []byte("    \""+key+"\": ")...)
+			result = append(result, entryJSON...)
+			if i < len(containerKeys)-1 {
+				result = append(result, ',')
+			}
+			result = append(result, '\n')
+		}
+		result = append(result, []byte("  }")...)
+	}
+
+	result = append(result, []byte("\n}")...)
 	return result, nil
 }
 
@@ -216,6 +258,32 @@ func (c *ActionCache) Set(repo, version, sha string) {
 	c.dirty = true // Mark cache as modified
 }
 
+// GetContainerDigest retrieves the cached digest of a container image reference
+func (c *ActionCache) GetContainerDigest(reference string) (string, bool) {
+	entry, exists := c.Containers[reference]
+	if !exists {
+		actionCacheLog.Printf("Container cache miss for %s", reference)
+		return "", false
+	}
+	actionCacheLog.Printf("Container cache hit for %s, digest=%s", reference, entry.Digest)
+	return entry.Digest, true
+}
+
+// SetContainerDigest stores the digest a container image reference resolved to
+func (c *ActionCache) SetContainerDigest(reference, digest string) {
+	image, tag := splitImageReference(reference)
+	if tag == "" {
+		tag = "latest"
+	}
+	actionCacheLog.Printf("Setting container cache entry: %s, digest=%s", reference, digest)
+	c.Containers[reference] = ContainerCacheEntry{
+		Image:  image,
+		Tag:    tag,
+		Digest: digest,
+	}
+	c.dirty = true // Mark cache as modified
+}
+
 // GetCachePath returns the path to the cache file
 func (c *ActionCache) GetCachePath() string {
 	return c.path
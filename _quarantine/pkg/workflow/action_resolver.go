@@ -13,7 +13,8 @@ var resolverLog = logger.New("workflow:action_resolver")
 
 // ActionResolver handles resolving action SHAs using GitHub CLI
 type ActionResolver struct {
-	cache *ActionCache
+	cache        *ActionCache
+	failedImages map[string]bool // container images whose digest could not be resolved in this run
 }
 
 // NewActionResolver creates a new action resolver
@@ -7,6 +7,7 @@ import (
 
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
 	"github.com/goccy/go-yaml"
 )
 
@@ -328,7 +329,7 @@ func (r *EngineRegistry) GetEngine(id string) (CodingAgentEngine, error) {
 	engine, exists := r.engines[id]
 	if !exists {
 		agenticEngineLog.Printf("Engine not found: id=%s", id)
-		return nil, fmt.Errorf("unknown engine: %s", id)
+		return nil, fmt.Errorf("unknown engine: %s%s", id, r.suggestEngine(id))
 	}
 	agenticEngineLog.Printf("Found engine: id=%s, name=%s", id, engine.GetDisplayName())
 	return engine, nil
@@ -362,7 +363,17 @@ func (r *EngineRegistry) GetEngineByPrefix(prefix string) (CodingAgentEngine, er
 			return engine, nil
 		}
 	}
-	return nil, fmt.Errorf("no engine found matching prefix: %s", prefix)
+	return nil, fmt.Errorf("no engine found matching prefix: %s%s", prefix, r.suggestEngine(prefix))
+}
+
+// suggestEngine returns a "did you mean" hint naming the registered engine closest
+// to the given ID, or an empty string when no engine is close enough
+func (r *EngineRegistry) suggestEngine(id string) string {
+	matches := parser.FindClosestMatches(id, r.GetSupportedEngines(), 1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(". Did you mean: %s?", matches[0])
 }
 
 // GenerateSecretValidationStep creates a GitHub Actions step that validates required secrets are available
@@ -0,0 +1,295 @@
+// This file implements the budget: frontmatter section, which limits the tokens and
+// cost an agentic workflow may consume.
+//
+// Example:
+//
+//	budget:
+//	  max-tokens: 500000   # stop the agent when a run uses more tokens
+//	  max-cost: 2.50       # stop the agent when a run costs more (USD)
+//	  max-cost-7d: 20      # skip runs once the last 7 days cost more (USD)
+//	  max-cost-30d: 60     # skip runs once the last 30 days cost more (USD)
+//
+// Per-run limits are enforced by a guard that runs in the background of the agent
+// job and stops the agent when a limit is crossed. Each run uploads its usage as an
+// artifact, and rolling limits are enforced in the pre-activation job by summing the
+// cost recorded in the usage artifacts of the workflow's recent runs, read through the
+// Actions runs and artifacts API. The check fails closed: when the recent runs cannot
+// be read, the run is skipped.
+
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var budgetLog = logger.New("workflow:budget")
+
+const (
+	// budgetDir holds the per-run budget state (usage, guard log, exceeded marker)
+	budgetDir = "/tmp/gh-aw/budget"
+	// budgetUsageArtifactName is the artifact each run uploads its usage.json under
+	budgetUsageArtifactName = "budget-usage"
+	// budgetUsageRetentionDays keeps usage artifacts for the longest rolling window
+	budgetUsageRetentionDays = 31
+)
+
+// BudgetConfig holds the token and cost limits from the budget: frontmatter section.
+// Zero values mean the limit is not configured.
+type BudgetConfig struct {
+	MaxTokens  int     `json:"max-tokens,omitempty" yaml:"max-tokens,omitempty"`     // Maximum tokens per run
+	MaxCost    float64 `json:"max-cost,omitempty" yaml:"max-cost,omitempty"`         // Maximum cost per run (USD)
+	MaxCost7d  float64 `json:"max-cost-7d,omitempty" yaml:"max-cost-7d,omitempty"`   // Maximum cost over a rolling 7 days (USD)
+	MaxCost30d float64 `json:"max-cost-30d,omitempty" yaml:"max-cost-30d,omitempty"` // Maximum cost over a rolling 30 days (USD)
+}
+
+// HasRunLimit reports whether a per-run token or cost limit is configured
+func (b *BudgetConfig) HasRunLimit() bool {
+	return b != nil && (b.MaxTokens > 0 || b.MaxCost > 0)
+}
+
+// HasRollingLimit reports whether a rolling 7 or 30 day cost limit is configured
+func (b *BudgetConfig) HasRollingLimit() bool {
+	return b != nil && (b.MaxCost7d > 0 || b.MaxCost30d > 0)
+}
+
+// HasCostLimit reports whether any cost limit is configured
+func (b *BudgetConfig) HasCostLimit() bool {
+	return b != nil && (b.MaxCost > 0 || b.HasRollingLimit())
+}
+
+// ExtractBudgetConfig parses the budget: section of the frontmatter.
+// Returns nil when the section is absent.
+func ExtractBudgetConfig(frontmatter map[string]any) (*BudgetConfig, error) {
+	value, exists := frontmatter["budget"]
+	if !exists || value == nil {
+		return nil, nil
+	}
+
+	budgetMap, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("budget must be an object, got %T. Example:\n  budget:\n    max-tokens: 500000\n    max-cost: 2.50", value)
+	}
+
+	keys := make([]string, 0, len(budgetMap))
+	for key := range budgetMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	config := &BudgetConfig{}
+	for _, key := range keys {
+		limit, err := parseBudgetLimit(key, budgetMap[key])
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "max-tokens":
+			if limit != float64(int(limit)) {
+				return nil, fmt.Errorf("budget 'max-tokens' must be an integer, got %v", budgetMap[key])
+			}
+			config.MaxTokens = int(limit)
+		case "max-cost":
+			config.MaxCost = limit
+		case "max-cost-7d":
+			config.MaxCost7d = limit
+		case "max-cost-30d":
+			config.MaxCost30d = limit
+		default:
+			return nil, fmt.Errorf("unknown budget field '%s'. Valid fields are: max-tokens, max-cost, max-cost-7d, max-cost-30d", key)
+		}
+	}
+
+	if !config.HasRunLimit() && !config.HasRollingLimit() {
+		return nil, fmt.Errorf("budget must configure at least one of: max-tokens, max-cost, max-cost-7d, max-cost-30d")
+	}
+
+	budgetLog.Printf("Extracted budget: max-tokens=%d, max-cost=%g, max-cost-7d=%g, max-cost-30d=%g",
+		config.MaxTokens, config.MaxCost, config.MaxCost7d, config.MaxCost30d)
+	return config, nil
+}
+
+// parseBudgetLimit converts a budget limit to a positive number
+func parseBudgetLimit(key string, value any) (float64, error) {
+	var limit float64
+	switch v := value.(type) {
+	case int:
+		limit = float64(v)
+	case int64:
+		limit = float64(v)
+	case uint64:
+		limit = float64(v)
+	case float64:
+		limit = v
+	default:
+		return 0, fmt.Errorf("budget '%s' must be a number, got %T", key, value)
+	}
+	if limit <= 0 {
+		return 0, fmt.Errorf("budget '%s' must be greater than 0, got %v", key, value)
+	}
+	return limit, nil
+}
+
+// processBudgetConfiguration extracts the budget: section and warns when cost limits
+// are configured for an engine that does not report cost
+func (c *Compiler) processBudgetConfiguration(frontmatter map[string]any, workflowData *WorkflowData, markdownPath string) error {
+	budget, err := ExtractBudgetConfig(frontmatter)
+	if err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+	workflowData.Budget = budget
+	if budget == nil {
+		return nil
+	}
+
+	if budget.HasCostLimit() {
+		engine, err := c.getAgenticEngine(workflowData.AI)
+		if err == nil && !engineReportsCost(engine) {
+			message := fmt.Sprintf("engine '%s' does not report cost in its logs; budget cost limits only apply to runs that report cost. Use max-tokens to limit this engine.", engine.GetID())
+			fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", message))
+			c.IncrementWarningCount()
+		}
+	}
+
+	if c.verbose {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Budget configured: %s", budget.describe())))
+	}
+	return nil
+}
+
+// engineReportsCost reports whether the engine's logs include the cost of a run
+func engineReportsCost(engine CodingAgentEngine) bool {
+	if manifestEngine, ok := engine.(*ManifestEngine); ok {
+		return manifestEngine.Manifest().Logs.Cost != ""
+	}
+	return engine.GetID() == string(constants.ClaudeEngine)
+}
+
+// describe returns a short human-readable summary of the configured limits
+func (b *BudgetConfig) describe() string {
+	var parts []string
+	if b.MaxTokens > 0 {
+		parts = append(parts, fmt.Sprintf("max-tokens=%d", b.MaxTokens))
+	}
+	if b.MaxCost > 0 {
+		parts = append(parts, "max-cost=$"+formatBudgetNumber(b.MaxCost))
+	}
+	if b.MaxCost7d > 0 {
+		parts = append(parts, "max-cost-7d=$"+formatBudgetNumber(b.MaxCost7d))
+	}
+	if b.MaxCost30d > 0 {
+		parts = append(parts, "max-cost-30d=$"+formatBudgetNumber(b.MaxCost30d))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatBudgetNumber formats a limit without trailing zeros
+func formatBudgetNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// budgetLimitEnv returns the environment variable lines passing the budget limits to the budget scripts
+func budgetLimitEnv(budget *BudgetConfig) []string {
+	var lines []string
+	if budget.MaxTokens > 0 {
+		lines = append(lines, fmt.Sprintf("          GH_AW_BUDGET_MAX_TOKENS: \"%d\"\n", budget.MaxTokens))
+	}
+	if budget.MaxCost > 0 {
+		lines = append(lines, fmt.Sprintf("          GH_AW_BUDGET_MAX_COST: \"%s\"\n", formatBudgetNumber(budget.MaxCost)))
+	}
+	if budget.MaxCost7d > 0 {
+		lines = append(lines, fmt.Sprintf("          GH_AW_BUDGET_MAX_COST_7D: \"%s\"\n", formatBudgetNumber(budget.MaxCost7d)))
+	}
+	if budget.MaxCost30d > 0 {
+		lines = append(lines, fmt.Sprintf("          GH_AW_BUDGET_MAX_COST_30D: \"%s\"\n", formatBudgetNumber(budget.MaxCost30d)))
+	}
+	return lines
+}
+
+// budgetLogPaths returns the comma-separated agent log paths the budget scripts read usage from
+func budgetLogPaths(engine CodingAgentEngine, logFile string) string {
+	paths := []string{logFile}
+	if parsingLog := engine.GetLogFileForParsing(); parsingLog != "" && parsingLog != logFile {
+		paths = append(paths, parsingLog)
+	}
+	return strings.Join(paths, ",")
+}
+
+// generateBudgetCheckSteps returns the pre-activation steps that skip the run when a
+// rolling cost limit has been reached
+func generateBudgetCheckSteps(data *WorkflowData) []string {
+	var steps []string
+	steps = append(steps, "      - name: Check budget\n")
+	steps = append(steps, fmt.Sprintf("        id: %s\n", constants.CheckBudgetStepID))
+	steps = append(steps, fmt.Sprintf("        uses: %s\n", GetActionPin("actions/github-script")))
+	steps = append(steps, "        env:\n")
+	steps = append(steps, fmt.Sprintf("          GH_AW_WORKFLOW_NAME: %q\n", data.Name))
+	steps = append(steps, budgetLimitEnv(data.Budget)...)
+	steps = append(steps, "        with:\n")
+	steps = append(steps, "          script: |\n")
+	steps = append(steps, generateGitHubScriptWithRequire("check_budget.cjs"))
+	return steps
+}
+
+// generateBudgetGuardStep starts the background guard that stops the agent when a
+// per-run limit is crossed. Must be generated right before agent execution.
+func (c *Compiler) generateBudgetGuardStep(yaml *strings.Builder, data *WorkflowData, engine CodingAgentEngine, logFile string) {
+	if !data.Budget.HasRunLimit() {
+		return
+	}
+	budgetLog.Printf("Generating budget guard step: %s", data.Budget.describe())
+
+	yaml.WriteString("      - name: Start budget guard\n")
+	yaml.WriteString("        env:\n")
+	fmt.Fprintf(yaml, "          GH_AW_BUDGET_AGENT_LOG: %s\n", logFile)
+	fmt.Fprintf(yaml, "          GH_AW_BUDGET_LOG_PATHS: %s\n", budgetLogPaths(engine, logFile))
+	for _, line := range budgetLimitEnv(data.Budget) {
+		yaml.WriteString(line)
+	}
+	yaml.WriteString("        run: bash /opt/gh-aw/actions/start_budget_guard.sh\n")
+}
+
+// generateBudgetUsageSteps records the usage of the run after agent execution: it stops
+// the guard, writes the run's usage, fails the job when a per-run limit was exceeded and,
+// when rolling limits are configured, uploads the usage for the pre-activation check of
+// later runs.
+func (c *Compiler) generateBudgetUsageSteps(yaml *strings.Builder, data *WorkflowData, engine CodingAgentEngine, logFile string) {
+	if data.Budget == nil {
+		return
+	}
+	budgetLog.Printf("Generating budget usage steps: rolling=%v", data.Budget.HasRollingLimit())
+
+	yaml.WriteString("      - name: Record budget usage\n")
+	yaml.WriteString("        if: always()\n")
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/github-script"))
+	yaml.WriteString("        env:\n")
+	fmt.Fprintf(yaml, "          GH_AW_BUDGET_LOG_PATHS: %s\n", budgetLogPaths(engine, logFile))
+	for _, line := range budgetLimitEnv(data.Budget) {
+		yaml.WriteString(line)
+	}
+	yaml.WriteString("        with:\n")
+	yaml.WriteString("          script: |\n")
+	yaml.WriteString("            const { setupGlobals } = require('" + SetupActionDestination + "/setup_globals.cjs');\n")
+	yaml.WriteString("            setupGlobals(core, github, context, exec, io);\n")
+	yaml.WriteString("            const { main } = require('/opt/gh-aw/actions/record_budget_usage.cjs');\n")
+	yaml.WriteString("            await main();\n")
+
+	if data.Budget.HasRollingLimit() {
+		yaml.WriteString("      - name: Upload budget usage\n")
+		yaml.WriteString("        if: always()\n")
+		fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/upload-artifact"))
+		yaml.WriteString("        with:\n")
+		fmt.Fprintf(yaml, "          name: %s\n", budgetUsageArtifactName)
+		fmt.Fprintf(yaml, "          path: %s/usage.json\n", budgetDir)
+		fmt.Fprintf(yaml, "          retention-days: %d\n", budgetUsageRetentionDays)
+		yaml.WriteString("          overwrite: true\n")
+		yaml.WriteString("          if-no-files-found: ignore\n")
+	}
+}
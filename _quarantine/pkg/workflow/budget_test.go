@@ -0,0 +1,168 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractBudgetConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontmatter map[string]any
+		want        *BudgetConfig
+		wantErr     string
+	}{
+		{
+			name:        "no budget section",
+			frontmatter: map[string]any{"on": "push"},
+			want:        nil,
+		},
+		{
+			name: "all limits",
+			frontmatter: map[string]any{"budget": map[string]any{
+				"max-tokens":   uint64(500000),
+				"max-cost":     2.5,
+				"max-cost-7d":  uint64(20),
+				"max-cost-30d": 60.0,
+			}},
+			want: &BudgetConfig{MaxTokens: 500000, MaxCost: 2.5, MaxCost7d: 20, MaxCost30d: 60},
+		},
+		{
+			name:        "rolling limit only",
+			frontmatter: map[string]any{"budget": map[string]any{"max-cost-30d": 100}},
+			want:        &BudgetConfig{MaxCost30d: 100},
+		},
+		{
+			name:        "not an object",
+			frontmatter: map[string]any{"budget": 100},
+			wantErr:     "budget must be an object",
+		},
+		{
+			name:        "empty object",
+			frontmatter: map[string]any{"budget": map[string]any{}},
+			wantErr:     "at least one of",
+		},
+		{
+			name:        "unknown field",
+			frontmatter: map[string]any{"budget": map[string]any{"max-calls": 10}},
+			wantErr:     "unknown budget field 'max-calls'",
+		},
+		{
+			name:        "non-numeric limit",
+			frontmatter: map[string]any{"budget": map[string]any{"max-cost": "$5"}},
+			wantErr:     "budget 'max-cost' must be a number",
+		},
+		{
+			name:        "zero limit",
+			frontmatter: map[string]any{"budget": map[string]any{"max-cost-7d": 0}},
+			wantErr:     "budget 'max-cost-7d' must be greater than 0",
+		},
+		{
+			name:        "fractional token limit",
+			frontmatter: map[string]any{"budget": map[string]any{"max-tokens": 1000.5}},
+			wantErr:     "budget 'max-tokens' must be an integer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractBudgetConfig(tt.frontmatter)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBudgetCompilation(t *testing.T) {
+	tests := []struct {
+		name         string
+		budget       string
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name:   "per-run limits only",
+			budget: "budget:\n  max-tokens: 500000\n  max-cost: 2.5\n",
+			wantContains: []string{
+				"GH_AW_BUDGET_MAX_TOKENS: \"500000\"",
+				"GH_AW_BUDGET_MAX_COST: \"2.5\"",
+				"name: Start budget guard",
+				"start_budget_guard.sh",
+				"name: Record budget usage",
+				"record_budget_usage.cjs",
+				"/tmp/gh-aw/budget/",
+			},
+			wantMissing: []string{
+				"name: Check budget",
+				"name: Upload budget usage",
+			},
+		},
+		{
+			name:   "rolling limits",
+			budget: "budget:\n  max-cost-7d: 20\n",
+			wantContains: []string{
+				"GH_AW_BUDGET_MAX_COST_7D: \"20\"",
+				"name: Check budget",
+				"check_budget.cjs",
+				"steps.check_budget.outputs.budget_ok == 'true'",
+				"actions: read",
+				"name: Upload budget usage",
+				"name: budget-usage",
+				"path: /tmp/gh-aw/budget/usage.json",
+				"record_budget_usage.cjs",
+			},
+			wantMissing: []string{
+				"name: Start budget guard",
+				"actions/cache",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := testutil.TempDir(t, "budget-test")
+			content := "---\non: workflow_dispatch\npermissions:\n  contents: read\nengine: claude\n" + tt.budget + "---\n\n# Budgeted\n\nDo the work.\n"
+			workflowPath := filepath.Join(tmpDir, "budgeted.md")
+			require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+			compiler := NewCompiler()
+			require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+			lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+			require.NoError(t, err)
+			lock := string(lockContent)
+
+			for _, want := range tt.wantContains {
+				assert.Contains(t, lock, want, "lock file should contain %q", want)
+			}
+			for _, missing := range tt.wantMissing {
+				assert.NotContains(t, lock, missing, "lock file should not contain %q", missing)
+			}
+			assert.Contains(t, lock, "budget: {", "aw_info should record the budget")
+		})
+	}
+}
+
+func TestBudgetCostWarningForEngineWithoutCost(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "budget-test")
+	content := "---\non: workflow_dispatch\npermissions:\n  contents: read\n  issues: read\n  pull-requests: read\nengine: copilot\nbudget:\n  max-cost: 1\n---\n\n# Budgeted\n"
+	workflowPath := filepath.Join(tmpDir, "budgeted.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+	assert.Equal(t, 1, compiler.GetWarningCount(), "cost limits on an engine without cost reporting should warn")
+}
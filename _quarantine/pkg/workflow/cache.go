@@ -33,6 +33,21 @@ func generateDefaultCacheKey(cacheID string) string {
 	return fmt.Sprintf("memory-%s-${{ github.workflow }}-${{ github.run_id }}", cacheID)
 }
 
+// CacheMemoryKeyPrefix returns the part of a cache-memory key shared by every run of a workflow,
+// resolving ${{ github.workflow }} to the workflow name and stopping at the first other expression
+// (normally the trailing ${{ github.run_id }})
+func CacheMemoryKeyPrefix(entry CacheMemoryEntry, workflowName string) string {
+	key := entry.Key
+	if key == "" {
+		key = generateDefaultCacheKey(entry.ID)
+	}
+	key = strings.ReplaceAll(key, "${{ github.workflow }}", workflowName)
+	if idx := strings.Index(key, "${{"); idx >= 0 {
+		key = key[:idx]
+	}
+	return key
+}
+
 // extractCacheMemoryConfig extracts cache-memory configuration from tools section
 // Updated to use ToolsConfig instead of map[string]any
 func (c *Compiler) extractCacheMemoryConfig(toolsConfig *ToolsConfig) (*CacheMemoryConfig, error) {
@@ -0,0 +1,44 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheMemoryKeyPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    CacheMemoryEntry
+		expected string
+	}{
+		{
+			name:     "default cache",
+			entry:    CacheMemoryEntry{ID: "default"},
+			expected: "memory-Daily Report-",
+		},
+		{
+			name:     "named cache",
+			entry:    CacheMemoryEntry{ID: "notes"},
+			expected: "memory-notes-Daily Report-",
+		},
+		{
+			name:     "custom key",
+			entry:    CacheMemoryEntry{ID: "default", Key: "reports-${{ github.workflow }}-${{ github.run_id }}"},
+			expected: "reports-Daily Report-",
+		},
+		{
+			name:     "custom key with other expressions",
+			entry:    CacheMemoryEntry{ID: "default", Key: "reports-${{ github.ref_name }}-${{ github.run_id }}"},
+			expected: "reports-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CacheMemoryKeyPrefix(tt.entry, "Daily Report"))
+		})
+	}
+}
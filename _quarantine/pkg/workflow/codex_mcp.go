@@ -24,6 +24,7 @@ func (e *CodexEngine) RenderMCPConfig(yaml *strings.Builder, tools map[string]an
 			Format:               "toml",
 			IsLast:               isLast,
 			ActionMode:           GetActionModeFromWorkflowData(workflowData),
+			ImagePins:            getImagePinsFromWorkflowData(workflowData),
 		})
 	}
 
@@ -67,6 +68,10 @@ func (e *CodexEngine) RenderMCPConfig(yaml *strings.Builder, tools map[string]an
 			if hasSafeInputs {
 				renderer.RenderSafeInputsMCP(yaml, workflowData.SafeInputs, workflowData)
 			}
+		case "repo-memory":
+			if hasRepoMemoryMCP(workflowData) {
+				renderer.RenderRepoMemoryMCP(yaml, workflowData)
+			}
 		case "web-fetch":
 			renderMCPFetchServerConfig(yaml, "toml", "          ", false, false)
 		default:
@@ -115,6 +120,7 @@ func (e *CodexEngine) RenderMCPConfig(yaml *strings.Builder, tools map[string]an
 			Format:               "json",
 			IsLast:               isLast,
 			ActionMode:           actionMode,
+			ImagePins:            getImagePinsFromWorkflowData(workflowData),
 		})
 	}
 
@@ -149,6 +155,10 @@ func (e *CodexEngine) RenderMCPConfig(yaml *strings.Builder, tools map[string]an
 				renderer := createJSONRenderer(isLast)
 				renderer.RenderSafeInputsMCP(yaml, safeInputs, workflowData)
 			},
+			RenderRepoMemory: func(yaml *strings.Builder, isLast bool, workflowData *WorkflowData) {
+				renderer := createJSONRenderer(isLast)
+				renderer.RenderRepoMemoryMCP(yaml, workflowData)
+			},
 			RenderWebFetch: func(yaml *strings.Builder, isLast bool) {
 				renderMCPFetchServerConfig(yaml, "json", "              ", isLast, false)
 			},
@@ -176,6 +186,7 @@ func (e *CodexEngine) renderCodexMCPConfigWithContext(yaml *strings.Builder, too
 		IndentLevel:              "          ",
 		Format:                   "toml",
 		RewriteLocalhostToDocker: rewriteLocalhost,
+		ImagePins:                getImagePinsFromWorkflowData(workflowData),
 	}
 
 	err := renderSharedMCPConfig(yaml, toolName, toolConfig, renderer)
@@ -199,6 +210,7 @@ func (e *CodexEngine) renderCodexJSONMCPConfigWithContext(yaml *strings.Builder,
 		Format:                   "json",
 		IndentLevel:              "              ",
 		RewriteLocalhostToDocker: rewriteLocalhost,
+		ImagePins:                getImagePinsFromWorkflowData(workflowData),
 	}
 
 	yaml.WriteString("              \"" + toolName + "\": {\n")
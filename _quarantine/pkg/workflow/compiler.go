@@ -156,6 +156,12 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		return formatCompilerError(markdownPath, "error", err.Error(), err)
 	}
 
+	// Validate against the organization policy
+	log.Printf("Validating organization policy")
+	if err := c.validatePolicy(workflowData); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
 	// Validate agent file exists if specified in engine config
 	log.Printf("Validating agent file if specified")
 	if err := c.validateAgentFile(workflowData, markdownPath); err != nil {
@@ -174,6 +180,12 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		return formatCompilerError(markdownPath, "error", err.Error(), err)
 	}
 
+	// Validate safe-outputs cross-run quotas
+	log.Printf("Validating safe-outputs quotas")
+	if err := validateSafeOutputQuotas(workflowData); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
 	// Validate safe-outputs allowed-domains configuration
 	log.Printf("Validating safe-outputs allowed-domains")
 	if err := c.validateSafeOutputsAllowedDomains(workflowData.SafeOutputs); err != nil {
@@ -380,6 +392,17 @@ func (c *Compiler) generateAndValidateYAML(workflowData *WorkflowData, markdownP
 			return "", formatCompilerError(markdownPath, "error", fmt.Sprintf("firewall configuration validation failed: %v", err), err)
 		}
 
+		// Validate job and step conditions (flag conditions that can never be true)
+		log.Print("Validating job conditions")
+		if warnings, err := validateStaticConditions(yamlContent); err != nil {
+			log.Printf("Skipping condition validation: %v", err)
+		} else {
+			for _, warning := range warnings {
+				fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", warning))
+				c.IncrementWarningCount()
+			}
+		}
+
 		// Validate repository features (discussions, issues)
 		log.Print("Validating repository features")
 		if err := c.validateRepositoryFeatures(workflowData); err != nil {
@@ -463,6 +486,40 @@ func (c *Compiler) writeWorkflowOutput(lockFile, yamlContent string, markdownPat
 // making it efficient for scenarios where the same workflow is compiled multiple times
 // or when workflow data comes from a non-file source.
 func (c *Compiler) CompileWorkflowData(workflowData *WorkflowData, markdownPath string) error {
+	yamlContent, lockFile, err := c.compileWorkflowDataToYAML(workflowData, markdownPath)
+	if err != nil {
+		return err
+	}
+
+	// Write output
+	return c.writeWorkflowOutput(lockFile, yamlContent, markdownPath)
+}
+
+// CompileToYAML parses, validates and compiles a workflow markdown file entirely in memory.
+// It returns the parsed workflow data together with the generated lock file content and
+// never writes the lock file, which makes it suitable for tooling that needs to inspect
+// the compiled workflow (simulation, verification, diffing).
+func (c *Compiler) CompileToYAML(markdownPath string) (*WorkflowData, string, error) {
+	c.markdownPath = markdownPath
+
+	workflowData, err := c.ParseWorkflowFile(markdownPath)
+	if err != nil {
+		if strings.Contains(err.Error(), ":") && (strings.Contains(err.Error(), "error:") || strings.Contains(err.Error(), "warning:")) {
+			return nil, "", err
+		}
+		return nil, "", formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	yamlContent, _, err := c.compileWorkflowDataToYAML(workflowData, markdownPath)
+	if err != nil {
+		return nil, "", err
+	}
+	return workflowData, yamlContent, nil
+}
+
+// compileWorkflowDataToYAML validates the workflow data and generates the lock file
+// content, returning it along with the lock file path it belongs to.
+func (c *Compiler) compileWorkflowDataToYAML(workflowData *WorkflowData, markdownPath string) (string, string, error) {
 	// Store markdownPath for use in dynamic tool generation and prompt generation
 	c.markdownPath = markdownPath
 
@@ -477,8 +534,9 @@ func (c *Compiler) CompileWorkflowData(workflowData *WorkflowData, markdownPath
 	// Reset the step order tracker for this compilation
 	c.stepOrderTracker = NewStepOrderTracker()
 
-	// Reset schedule friendly formats for this compilation
+	// Reset schedule friendly formats and guards for this compilation
 	c.scheduleFriendlyFormats = nil
+	c.scheduleGuards = nil
 
 	// Reset the artifact manager for this compilation
 	if c.artifactManager == nil {
@@ -497,7 +555,7 @@ func (c *Compiler) CompileWorkflowData(workflowData *WorkflowData, markdownPath
 
 	// Validate workflow data
 	if err := c.validateWorkflowData(workflowData, markdownPath); err != nil {
-		return err
+		return "", "", err
 	}
 
 	// Note: Markdown content size is now handled by splitting into multiple steps in generatePrompt
@@ -509,11 +567,10 @@ func (c *Compiler) CompileWorkflowData(workflowData *WorkflowData, markdownPath
 	// Generate and validate YAML
 	yamlContent, err := c.generateAndValidateYAML(workflowData, markdownPath, lockFile)
 	if err != nil {
-		return err
+		return "", "", err
 	}
 
-	// Write output
-	return c.writeWorkflowOutput(lockFile, yamlContent, markdownPath)
+	return yamlContent, lockFile, nil
 }
 
 // ParseWorkflowFile parses a markdown workflow file and extracts all necessary data
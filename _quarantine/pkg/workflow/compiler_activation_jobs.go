@@ -56,6 +56,14 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		perms.Set(PermissionDiscussions, PermissionWrite)
 	}
 
+	// The rolling budget check reads the usage artifacts of recent runs
+	if data.Budget.HasRollingLimit() {
+		if perms == nil {
+			perms = NewPermissions()
+		}
+		perms.Set(PermissionActions, PermissionRead)
+	}
+
 	// Set permissions if any were configured
 	if perms != nil {
 		permissions = perms.RenderToYAML()
@@ -107,6 +115,11 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		steps = append(steps, generateGitHubScriptWithRequire("check_stop_time.cjs"))
 	}
 
+	// Add schedule guard if a schedule needs a runtime check (timezones, calendar rules)
+	if len(data.ScheduleGuards) > 0 {
+		steps = append(steps, generateScheduleGuardStep(data)...)
+	}
+
 	// Add skip-if-match check if configured
 	if data.SkipIfMatch != nil {
 		// Extract workflow name for the skip-if-match check
@@ -141,6 +154,11 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		steps = append(steps, generateGitHubScriptWithRequire("check_skip_if_no_match.cjs"))
 	}
 
+	// Add rolling budget check if configured
+	if data.Budget.HasRollingLimit() {
+		steps = append(steps, generateBudgetCheckSteps(data)...)
+	}
+
 	// Add command position check if this is a command workflow
 	if len(data.Command) > 0 {
 		steps = append(steps, "      - name: Check command position\n")
@@ -187,6 +205,16 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		conditions = append(conditions, stopTimeCheck)
 	}
 
+	if len(data.ScheduleGuards) > 0 {
+		// Add schedule guard condition
+		scheduleCheck := BuildComparison(
+			BuildPropertyAccess(fmt.Sprintf("steps.%s.outputs.%s", constants.CheckScheduleStepID, constants.ScheduleOkOutput)),
+			"==",
+			BuildStringLiteral("true"),
+		)
+		conditions = append(conditions, scheduleCheck)
+	}
+
 	if data.SkipIfMatch != nil {
 		// Add skip-if-match check condition
 		skipCheckOk := BuildComparison(
@@ -207,6 +235,16 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		conditions = append(conditions, skipNoMatchCheckOk)
 	}
 
+	if data.Budget.HasRollingLimit() {
+		// Add rolling budget check condition
+		budgetCheck := BuildComparison(
+			BuildPropertyAccess(fmt.Sprintf("steps.%s.outputs.%s", constants.CheckBudgetStepID, constants.BudgetOkOutput)),
+			"==",
+			BuildStringLiteral("true"),
+		)
+		conditions = append(conditions, budgetCheck)
+	}
+
 	if len(data.Command) > 0 {
 		// Add command position check condition
 		commandPositionCheck := BuildComparison(
@@ -813,8 +851,8 @@ func (c *Compiler) buildMainJob(data *WorkflowData, activationJobCreated bool) (
 		If:          jobCondition,
 		RunsOn:      c.indentYAMLLines(data.RunsOn, "    "),
 		Environment: c.indentYAMLLines(data.Environment, "    "),
-		Container:   c.indentYAMLLines(data.Container, "    "),
-		Services:    c.indentYAMLLines(data.Services, "    "),
+		Container:   c.indentYAMLLines(renderJobContainerSection(data.Container, "container", data.ImagePins), "    "),
+		Services:    c.indentYAMLLines(renderJobContainerSection(data.Services, "services", data.ImagePins), "    "),
 		Permissions: c.indentYAMLLines(permissions, "    "),
 		Concurrency: c.indentYAMLLines(agentConcurrency, "    "),
 		Env:         env,
@@ -2,7 +2,6 @@ package workflow
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -116,7 +115,7 @@ func (c *Compiler) buildJobs(data *WorkflowData, markdownPath string) error {
 
 	// Try to read frontmatter to determine event types for safe events check
 	var frontmatter map[string]any
-	if content, err := os.ReadFile(markdownPath); err == nil {
+	if content, err := c.readMarkdownFile(markdownPath); err == nil {
 		if result, err := parser.ExtractFrontmatterFromContent(string(content)); err == nil {
 			frontmatter = result.Frontmatter
 		}
@@ -167,10 +166,12 @@ func (c *Compiler) buildPreActivationAndActivationJobs(data *WorkflowData, front
 	hasSkipIfMatch := data.SkipIfMatch != nil
 	hasSkipIfNoMatch := data.SkipIfNoMatch != nil
 	hasCommandTrigger := len(data.Command) > 0
-	compilerJobsLog.Printf("Job configuration: needsPermissionCheck=%v, hasStopTime=%v, hasSkipIfMatch=%v, hasSkipIfNoMatch=%v, hasCommand=%v", needsPermissionCheck, hasStopTime, hasSkipIfMatch, hasSkipIfNoMatch, hasCommandTrigger)
+	hasRollingBudget := data.Budget.HasRollingLimit()
+	hasScheduleGuard := len(data.ScheduleGuards) > 0
+	compilerJobsLog.Printf("Job configuration: needsPermissionCheck=%v, hasStopTime=%v, hasSkipIfMatch=%v, hasSkipIfNoMatch=%v, hasCommand=%v, hasRollingBudget=%v, hasScheduleGuard=%v", needsPermissionCheck, hasStopTime, hasSkipIfMatch, hasSkipIfNoMatch, hasCommandTrigger, hasRollingBudget, hasScheduleGuard)
 
-	// Build pre-activation job if needed (combines membership checks, stop-time validation, skip-if-match check, skip-if-no-match check, and command position check)
-	if needsPermissionCheck || hasStopTime || hasSkipIfMatch || hasSkipIfNoMatch || hasCommandTrigger {
+	// Build pre-activation job if needed (combines membership checks, stop-time validation, schedule guard, skip-if-match check, skip-if-no-match check, command position check, and budget check)
+	if needsPermissionCheck || hasStopTime || hasSkipIfMatch || hasSkipIfNoMatch || hasCommandTrigger || hasRollingBudget || hasScheduleGuard {
 		compilerJobsLog.Print("Building pre-activation job")
 		preActivationJob, err := c.buildPreActivationJob(data, needsPermissionCheck)
 		if err != nil {
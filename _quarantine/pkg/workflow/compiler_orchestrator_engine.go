@@ -31,6 +31,11 @@ type engineSetupResult struct {
 func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, cleanPath string, content []byte, markdownDir string) (*engineSetupResult, error) {
 	orchestratorEngineLog.Printf("Setting up engine and processing imports")
 
+	// Register engines declared in the repository's engine manifests
+	if err := c.loadEngineManifests(cleanPath); err != nil {
+		return nil, formatCompilerError(cleanPath, "error", err.Error(), err)
+	}
+
 	// Extract AI engine setting from frontmatter
 	engineSetting, engineConfig := c.ExtractEngineConfig(result.Frontmatter)
 
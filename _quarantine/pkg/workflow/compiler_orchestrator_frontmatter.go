@@ -2,7 +2,6 @@ package workflow
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/github/gh-aw/pkg/logger"
@@ -33,7 +32,7 @@ func (c *Compiler) parseFrontmatterSection(markdownPath string) (*frontmatterPar
 	cleanPath := filepath.Clean(markdownPath)
 
 	// Read the file
-	content, err := os.ReadFile(cleanPath)
+	content, err := c.readMarkdownFile(cleanPath)
 	if err != nil {
 		orchestratorFrontmatterLog.Printf("Failed to read file: %s, error: %v", cleanPath, err)
 		// Don't wrap os.PathError - format it instead to avoid exposing internals
@@ -271,7 +271,11 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 	sort.Strings(allIncludedFiles)
 
 	// Extract workflow name
-	workflowName, err := parser.ExtractWorkflowNameFromMarkdown(cleanPath)
+	workflowContent, err := c.readMarkdownFile(cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract workflow name: %w", err)
+	}
+	workflowName, err := parser.ExtractWorkflowNameFromContent(string(workflowContent), cleanPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract workflow name: %w", err)
 	}
@@ -93,6 +93,11 @@ func (c *Compiler) ParseWorkflowFile(markdownPath string) (*WorkflowData, error)
 		return nil, err
 	}
 
+	// Pin runtime dependencies to the versions of the dependency lock
+	if err := c.applyDepsLock(workflowData); err != nil {
+		return nil, fmt.Errorf("%s: %w", cleanPath, err)
+	}
+
 	orchestratorWorkflowLog.Printf("Workflow file parsing completed successfully: %s", markdownPath)
 	return workflowData, nil
 }
@@ -156,6 +161,7 @@ func (c *Compiler) extractYAMLSections(frontmatter map[string]any, workflowData
 	workflowData.Env = c.extractTopLevelYAMLSection(frontmatter, "env")
 	workflowData.Features = c.extractFeatures(frontmatter)
 	workflowData.If = c.extractIfCondition(frontmatter)
+	workflowData.If = c.combineJobIfConditions(workflowData.If, c.triggerCondition)
 
 	// Extract timeout-minutes (canonical form)
 	workflowData.TimeoutMinutes = c.extractTopLevelYAMLSection(frontmatter, "timeout-minutes")
@@ -431,6 +437,11 @@ func (c *Compiler) extractAdditionalConfigurations(
 		workflowData.SafeInputs = c.mergeSafeInputs(workflowData.SafeInputs, importsResult.MergedSafeInputs)
 	}
 
+	// Check TypeScript tools against their inputs and the configured Node.js version
+	if err := validateSafeInputs(workflowData.SafeInputs, workflowData.Runtimes); err != nil {
+		return err
+	}
+
 	// Extract safe-jobs from safe-outputs.jobs location
 	topSafeJobs := extractSafeJobsFromFrontmatter(frontmatter)
 
@@ -513,6 +524,14 @@ func (c *Compiler) processOnSectionAndFilters(
 		return err
 	}
 
+	// Process budget configuration (per-run and rolling token/cost limits)
+	if err := c.processBudgetConfiguration(frontmatter, workflowData, cleanPath); err != nil {
+		return err
+	}
+
+	// Schedule guards were recorded while preprocessing the schedule expressions
+	workflowData.ScheduleGuards = c.scheduleGuardsForWorkflow()
+
 	// Parse the "on" section for command triggers, reactions, and other events
 	if err := c.parseOnSection(frontmatter, workflowData, cleanPath); err != nil {
 		return err
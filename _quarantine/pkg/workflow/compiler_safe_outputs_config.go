@@ -85,6 +85,13 @@ func (b *handlerConfigBuilder) AddDefault(key string, value any) *handlerConfigB
 	return b
 }
 
+// AddQuota adds the cross-run quota fields of a safe output
+func (b *handlerConfigBuilder) AddQuota(quota SafeOutputQuotaConfig) *handlerConfigBuilder {
+	return b.
+		AddIfPositive("max_per_day", quota.MaxPerDay).
+		AddIfPositive("max_open", quota.MaxOpen)
+}
+
 // Build returns the built configuration map
 func (b *handlerConfigBuilder) Build() map[string]any {
 	return b.config
@@ -102,6 +109,7 @@ var handlerRegistry = map[string]handlerBuilder{
 		c := cfg.CreateIssues
 		return newHandlerConfigBuilder().
 			AddIfPositive("max", c.Max).
+			AddQuota(c.SafeOutputQuotaConfig).
 			AddStringSlice("allowed_labels", c.AllowedLabels).
 			AddStringSlice("allowed_repos", c.AllowedRepos).
 			AddIfPositive("expires", c.Expires).
@@ -120,6 +128,7 @@ var handlerRegistry = map[string]handlerBuilder{
 		c := cfg.AddComments
 		return newHandlerConfigBuilder().
 			AddIfPositive("max", c.Max).
+			AddQuota(c.SafeOutputQuotaConfig).
 			AddIfNotEmpty("target", c.Target).
 			AddIfTrue("hide_older_comments", c.HideOlderComments).
 			AddIfNotEmpty("target-repo", c.TargetRepoSlug).
@@ -133,6 +142,7 @@ var handlerRegistry = map[string]handlerBuilder{
 		c := cfg.CreateDiscussions
 		return newHandlerConfigBuilder().
 			AddIfPositive("max", c.Max).
+			AddQuota(c.SafeOutputQuotaConfig).
 			AddIfNotEmpty("category", c.Category).
 			AddIfNotEmpty("title_prefix", c.TitlePrefix).
 			AddStringSlice("labels", c.Labels).
@@ -302,6 +312,7 @@ var handlerRegistry = map[string]handlerBuilder{
 		}
 		return newHandlerConfigBuilder().
 			AddIfPositive("max", c.Max).
+			AddQuota(c.SafeOutputQuotaConfig).
 			AddIfNotEmpty("title_prefix", c.TitlePrefix).
 			AddStringSlice("labels", c.Labels).
 			AddBoolPtr("draft", c.Draft).
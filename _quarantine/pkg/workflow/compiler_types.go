@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"os"
+	"path/filepath"
 
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
@@ -102,35 +103,45 @@ type Compiler struct {
 	verbose                 bool
 	quiet                   bool // If true, suppress success messages (for interactive mode)
 	engineOverride          string
-	customOutput            string              // If set, output will be written to this path instead of default location
-	version                 string              // Version of the extension
-	skipValidation          bool                // If true, skip schema validation
-	noEmit                  bool                // If true, validate without generating lock files
-	strictMode              bool                // If true, enforce strict validation requirements
-	trialMode               bool                // If true, suppress safe outputs for trial mode execution
-	trialLogicalRepoSlug    string              // If set in trial mode, the logical repository to checkout
-	refreshStopTime         bool                // If true, regenerate stop-after times instead of preserving existing ones
-	forceRefreshActionPins  bool                // If true, clear action cache and resolve all actions from GitHub API
-	failFast                bool                // If true, stop at first validation error instead of collecting all errors
-	actionCacheCleared      bool                // Tracks if action cache has already been cleared (for forceRefreshActionPins)
-	markdownPath            string              // Path to the markdown file being compiled (for context in dynamic tool generation)
-	actionMode              ActionMode          // Mode for generating JavaScript steps (inline vs custom actions)
-	actionTag               string              // Override action SHA or tag for actions/setup (when set, overrides actionMode to release)
-	jobManager              *JobManager         // Manages jobs and dependencies
-	engineRegistry          *EngineRegistry     // Registry of available agentic engines
-	fileTracker             FileTracker         // Optional file tracker for tracking created files
-	warningCount            int                 // Number of warnings encountered during compilation
-	stepOrderTracker        *StepOrderTracker   // Tracks step ordering for validation
-	actionCache             *ActionCache        // Shared cache for action pin resolutions across all workflows
-	actionResolver          *ActionResolver     // Shared resolver for action pins across all workflows
-	actionPinWarnings       map[string]bool     // Shared cache of already-warned action pin failures (key: "repo@version")
-	importCache             *parser.ImportCache // Shared cache for imported workflow files
-	workflowIdentifier      string              // Identifier for the current workflow being compiled (for schedule scattering)
-	scheduleWarnings        []string            // Accumulated schedule warnings for this compiler instance
-	repositorySlug          string              // Repository slug (owner/repo) used as seed for scattering
-	artifactManager         *ArtifactManager    // Tracks artifact uploads/downloads for validation
-	scheduleFriendlyFormats map[int]string      // Maps schedule item index to friendly format string for current workflow
-	gitRoot                 string              // Git repository root directory (if set, used for action cache path)
+	customOutput            string               // If set, output will be written to this path instead of default location
+	version                 string               // Version of the extension
+	skipValidation          bool                 // If true, skip schema validation
+	noEmit                  bool                 // If true, validate without generating lock files
+	strictMode              bool                 // If true, enforce strict validation requirements
+	trialMode               bool                 // If true, suppress safe outputs for trial mode execution
+	trialLogicalRepoSlug    string               // If set in trial mode, the logical repository to checkout
+	refreshStopTime         bool                 // If true, regenerate stop-after times instead of preserving existing ones
+	forceRefreshActionPins  bool                 // If true, clear action cache and resolve all actions from GitHub API
+	failFast                bool                 // If true, stop at first validation error instead of collecting all errors
+	actionCacheCleared      bool                 // Tracks if action cache has already been cleared (for forceRefreshActionPins)
+	markdownPath            string               // Path to the markdown file being compiled (for context in dynamic tool generation)
+	actionMode              ActionMode           // Mode for generating JavaScript steps (inline vs custom actions)
+	actionTag               string               // Override action SHA or tag for actions/setup (when set, overrides actionMode to release)
+	jobManager              *JobManager          // Manages jobs and dependencies
+	engineRegistry          *EngineRegistry      // Registry of available agentic engines
+	fileTracker             FileTracker          // Optional file tracker for tracking created files
+	warningCount            int                  // Number of warnings encountered during compilation
+	stepOrderTracker        *StepOrderTracker    // Tracks step ordering for validation
+	actionCache             *ActionCache         // Shared cache for action pin resolutions across all workflows
+	actionResolver          *ActionResolver      // Shared resolver for action pins across all workflows
+	actionPinWarnings       map[string]bool      // Shared cache of already-warned action pin failures (key: "repo@version")
+	importCache             *parser.ImportCache  // Shared cache for imported workflow files
+	workflowIdentifier      string               // Identifier for the current workflow being compiled (for schedule scattering)
+	scheduleWarnings        []string             // Accumulated schedule warnings for this compiler instance
+	repositorySlug          string               // Repository slug (owner/repo) used as seed for scattering
+	schedulePlan            *parser.SchedulePlan // Repository-wide placement of fuzzy schedules (nil scatters each workflow independently)
+	artifactManager         *ArtifactManager     // Tracks artifact uploads/downloads for validation
+	scheduleFriendlyFormats map[int]string       // Maps schedule item index to friendly format string for current workflow
+	scheduleGuards          ScheduleGuards       // Runtime guards of the emitted schedule cron expressions for current workflow
+	triggerCondition        string               // Job condition of the trigger shorthand of the current workflow
+	gitRoot                 string               // Git repository root directory (if set, used for action cache path)
+	engineManifestsDir      string               // Engine manifests directory already loaded into engineRegistry
+	markdownContent         map[string][]byte    // Content used instead of reading a markdown file from disk (e.g., unsaved editor buffers)
+	policy                  *Policy              // Organization policy evaluated against every compiled workflow
+	depsLock                *DepsLock            // Dependency lock (aw-deps.lock) shared across all workflows
+	lockDeps                bool                 // If true, resolve dependencies missing from the dependency lock
+	refreshDepsLock         bool                 // If true, ignore the existing dependency lock and resolve every dependency again
+	resolveImages           bool                 // If true, resolve container image digests missing from the action cache
 }
 
 // NewCompiler creates a new workflow compiler with functional options.
@@ -193,6 +204,28 @@ func (c *Compiler) SetNoEmit(noEmit bool) {
 	c.noEmit = noEmit
 }
 
+// SetMarkdownContent makes the compiler use content instead of reading markdownPath from disk.
+// This lets editors validate unsaved buffers while imports still resolve relative to markdownPath.
+func (c *Compiler) SetMarkdownContent(markdownPath string, content []byte) {
+	if c.markdownContent == nil {
+		c.markdownContent = make(map[string][]byte)
+	}
+	c.markdownContent[filepath.Clean(markdownPath)] = content
+}
+
+// readMarkdownFile reads a workflow markdown file, preferring content set with SetMarkdownContent
+func (c *Compiler) readMarkdownFile(markdownPath string) ([]byte, error) {
+	if content, ok := c.markdownContent[filepath.Clean(markdownPath)]; ok {
+		return content, nil
+	}
+	return os.ReadFile(markdownPath)
+}
+
+// SetPolicy sets the organization policy evaluated against every compiled workflow
+func (c *Compiler) SetPolicy(policy *Policy) {
+	c.policy = policy
+}
+
 // SetFileTracker sets the file tracker for tracking created files
 func (c *Compiler) SetFileTracker(tracker FileTracker) {
 	c.fileTracker = tracker
@@ -223,6 +256,19 @@ func (c *Compiler) SetForceRefreshActionPins(force bool) {
 	c.forceRefreshActionPins = force
 }
 
+// SetLockDeps configures whether runtime dependencies are resolved into the dependency
+// lock. With refresh, the existing lock is ignored and every dependency is resolved again.
+func (c *Compiler) SetLockDeps(lockDeps bool, refresh bool) {
+	c.lockDeps = lockDeps
+	c.refreshDepsLock = lockDeps && refresh
+}
+
+// SetResolveImageDigests configures whether container image digests missing from the
+// action cache are resolved using the local docker daemon or the registry API.
+func (c *Compiler) SetResolveImageDigests(resolve bool) {
+	c.resolveImages = resolve
+}
+
 // SetActionMode configures the action mode for JavaScript step generation
 func (c *Compiler) SetActionMode(mode ActionMode) {
 	c.actionMode = mode
@@ -284,6 +330,11 @@ func (c *Compiler) GetRepositorySlug() string {
 	return c.repositorySlug
 }
 
+// SetSchedulePlan sets the repository-wide schedule plan used to scatter fuzzy schedules
+func (c *Compiler) SetSchedulePlan(plan *parser.SchedulePlan) {
+	c.schedulePlan = plan
+}
+
 // GetScheduleWarnings returns all accumulated schedule warnings for this compiler instance
 func (c *Compiler) GetScheduleWarnings() []string {
 	return c.scheduleWarnings
@@ -320,6 +371,7 @@ func (c *Compiler) getSharedActionResolver() (*ActionCache, *ActionResolver) {
 		// If cache already exists but force refresh is set and we haven't cleared it yet, clear it once
 		logTypes.Print("Force refresh action pins: clearing existing cache once for this run")
 		c.actionCache.Entries = make(map[string]ActionCacheEntry)
+		c.actionCache.Containers = make(map[string]ContainerCacheEntry)
 		c.actionCacheCleared = true
 	}
 	return c.actionCache, c.actionResolver
@@ -402,9 +454,10 @@ type WorkflowData struct {
 	CustomSteps          string
 	PostSteps            string // steps to run after AI execution
 	RunsOn               string
-	Environment          string // environment setting for the main job
-	Container            string // container setting for the main job
-	Services             string // services setting for the main job
+	Environment          string            // environment setting for the main job
+	Container            string            // container setting for the main job
+	Services             string            // services setting for the main job
+	ImagePins            map[string]string // digests of the container images pinned in the generated workflow, by image reference
 	Tools                map[string]any
 	ParsedTools          *Tools // Structured tools configuration (NEW: parsed from Tools map)
 	MarkdownContent      string
@@ -416,6 +469,8 @@ type WorkflowData struct {
 	StopTime             string
 	SkipIfMatch          *SkipIfMatchConfig   // skip-if-match configuration with query and max threshold
 	SkipIfNoMatch        *SkipIfNoMatchConfig // skip-if-no-match configuration with query and min threshold
+	Budget               *BudgetConfig        // token and cost limits from the budget: section
+	ScheduleGuards       ScheduleGuards       // runtime checks of schedule cron expressions that cron cannot represent (timezones, calendar rules)
 	ManualApproval       string               // environment name for manual approval from on: section
 	Command              []string             // for /command trigger support - multiple command names
 	CommandEvents        []string             // events where command should be active (nil = all events)
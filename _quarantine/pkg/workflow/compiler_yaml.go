@@ -159,6 +159,9 @@ func (c *Compiler) generateWorkflowBody(yaml *strings.Builder, data *WorkflowDat
 func (c *Compiler) generateYAML(data *WorkflowData, markdownPath string) (string, error) {
 	compilerYamlLog.Printf("Generating YAML for workflow: %s", data.Name)
 
+	// Resolve container image digests before building the jobs that reference the images
+	c.resolveContainerImagePins(data)
+
 	// Build all jobs and validate dependencies
 	if err := c.buildJobsAndValidate(data, markdownPath); err != nil {
 		return "", fmt.Errorf("failed to build and validate jobs: %w", err)
@@ -531,6 +534,32 @@ func (c *Compiler) generateCreateAwInfo(yaml *strings.Builder, data *WorkflowDat
 
 	yaml.WriteString("              },\n")
 
+	// Budget limits (used by the logs and health commands to report budget consumption)
+	if data.Budget != nil {
+		yaml.WriteString("              budget: {\n")
+		var budgetFields []string
+		if data.Budget.MaxTokens > 0 {
+			budgetFields = append(budgetFields, fmt.Sprintf("max_tokens: %d", data.Budget.MaxTokens))
+		}
+		if data.Budget.MaxCost > 0 {
+			budgetFields = append(budgetFields, "max_cost: "+formatBudgetNumber(data.Budget.MaxCost))
+		}
+		if data.Budget.MaxCost7d > 0 {
+			budgetFields = append(budgetFields, "max_cost_7d: "+formatBudgetNumber(data.Budget.MaxCost7d))
+		}
+		if data.Budget.MaxCost30d > 0 {
+			budgetFields = append(budgetFields, "max_cost_30d: "+formatBudgetNumber(data.Budget.MaxCost30d))
+		}
+		for i, field := range budgetFields {
+			separator := ","
+			if i == len(budgetFields)-1 {
+				separator = ""
+			}
+			fmt.Fprintf(yaml, "                %s%s\n", field, separator)
+		}
+		yaml.WriteString("              },\n")
+	}
+
 	yaml.WriteString("              created_at: new Date().toISOString()\n")
 
 	yaml.WriteString("            };\n")
@@ -242,6 +242,9 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 		yaml.WriteString(line)
 	}
 
+	// Start the budget guard that stops the agent when a per-run limit is crossed
+	c.generateBudgetGuardStep(yaml, data, engine, logFileFull)
+
 	// Add AI execution step using the agentic engine
 	compilerYamlLog.Printf("Generating engine execution steps for %s", engine.GetID())
 	c.generateEngineExecutionSteps(yaml, data, engine, logFileFull)
@@ -328,6 +331,12 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 	// parse agent logs for GITHUB_STEP_SUMMARY
 	c.generateLogParsing(yaml, engine)
 
+	// Record budget usage and upload it for the rolling budget check (if budget is configured)
+	if data.Budget != nil {
+		c.generateBudgetUsageSteps(yaml, data, engine, logFileFull)
+		artifactPaths = append(artifactPaths, budgetDir+"/")
+	}
+
 	// parse safe-inputs logs for GITHUB_STEP_SUMMARY (if safe-inputs is enabled)
 	if IsSafeInputsEnabled(data.SafeInputs, data) {
 		c.generateSafeInputsLogParsing(yaml)
@@ -0,0 +1,125 @@
+// This file provides static analysis of job and step conditions for agentic workflow compilation.
+//
+// This file contains validation functions that evaluate the `if:` conditions of the
+// compiled workflow against each configured trigger:
+//   - validateStaticConditions() - Reports conditions that can never be true
+//
+// Conditions are evaluated with a partial context in which only github.event_name is
+// known. A condition is flagged when it evaluates to a known false value for every
+// trigger, e.g. a pull request check in a workflow that only runs on issues.
+// See validation.go for the complete validation architecture documentation.
+
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/goccy/go-yaml"
+)
+
+var conditionValidationLog = logger.New("workflow:condition_validation")
+
+// conditionValidationWorkflow is the subset of the compiled workflow needed to check conditions
+type conditionValidationWorkflow struct {
+	On   any `yaml:"on"`
+	Jobs map[string]struct {
+		If    any `yaml:"if"`
+		Steps []struct {
+			Name string `yaml:"name"`
+			ID   string `yaml:"id"`
+			If   any    `yaml:"if"`
+		} `yaml:"steps"`
+	} `yaml:"jobs"`
+}
+
+// validateStaticConditions returns a warning for every job or step condition in the
+// compiled workflow that evaluates to false for all of the workflow's triggers
+func validateStaticConditions(yamlContent string) ([]string, error) {
+	var wf conditionValidationWorkflow
+	if err := yaml.Unmarshal([]byte(yamlContent), &wf); err != nil {
+		return nil, fmt.Errorf("failed to parse compiled workflow: %w", err)
+	}
+
+	events := conditionTriggerEvents(wf.On)
+	if len(events) == 0 {
+		conditionValidationLog.Print("No triggers found, skipping condition validation")
+		return nil, nil
+	}
+	for _, event := range events {
+		// Reusable workflows inherit github.event_name from the caller
+		if event == "workflow_call" {
+			conditionValidationLog.Print("Workflow is reusable, skipping condition validation")
+			return nil, nil
+		}
+	}
+	conditionValidationLog.Printf("Validating conditions for triggers: %v", events)
+
+	jobNames := make([]string, 0, len(wf.Jobs))
+	for name := range wf.Jobs {
+		jobNames = append(jobNames, name)
+	}
+	sort.Strings(jobNames)
+
+	var warnings []string
+	for _, jobName := range jobNames {
+		job := wf.Jobs[jobName]
+		if condition, ok := job.If.(string); ok && isConditionAlwaysFalse(condition, events) {
+			warnings = append(warnings, fmt.Sprintf("job '%s' condition can never be true for the configured triggers (%s): %s",
+				jobName, strings.Join(events, ", "), strings.TrimSpace(condition)))
+		}
+		for _, step := range job.Steps {
+			condition, ok := step.If.(string)
+			if !ok || !isConditionAlwaysFalse(condition, events) {
+				continue
+			}
+			stepName := step.Name
+			if stepName == "" {
+				stepName = step.ID
+			}
+			warnings = append(warnings, fmt.Sprintf("step '%s' in job '%s' condition can never be true for the configured triggers (%s): %s",
+				stepName, jobName, strings.Join(events, ", "), strings.TrimSpace(condition)))
+		}
+	}
+	return warnings, nil
+}
+
+// isConditionAlwaysFalse reports whether the condition is known to be false for every
+// trigger event. Conditions that fail to parse or evaluate are never flagged.
+func isConditionAlwaysFalse(condition string, events []string) bool {
+	for _, event := range events {
+		ctx := EvalContext{GitHub: map[string]any{"event_name": event}}
+		result, known, err := EvaluateConditionStatically(condition, ctx)
+		if err != nil {
+			conditionValidationLog.Printf("Skipping condition that cannot be evaluated: %v", err)
+			return false
+		}
+		if !known || result {
+			return false
+		}
+	}
+	return true
+}
+
+// conditionTriggerEvents returns the sorted event names of the workflow's `on:` section
+func conditionTriggerEvents(on any) []string {
+	var events []string
+	switch v := on.(type) {
+	case string:
+		events = append(events, v)
+	case []any:
+		for _, item := range v {
+			if name, ok := item.(string); ok {
+				events = append(events, name)
+			}
+		}
+	case map[string]any:
+		for name := range v {
+			events = append(events, name)
+		}
+	}
+	sort.Strings(events)
+	return events
+}
@@ -0,0 +1,97 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStaticConditions(t *testing.T) {
+	tests := []struct {
+		name             string
+		yaml             string
+		expectedWarnings []string
+	}{
+		{
+			name: "pull request condition on issues trigger",
+			yaml: `on:
+  issues:
+    types: [opened]
+jobs:
+  agent:
+    if: github.event_name == 'pull_request' && github.event.pull_request.draft == false
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hi
+`,
+			expectedWarnings: []string{"job 'agent'"},
+		},
+		{
+			name: "condition true for one of the triggers",
+			yaml: `on:
+  issues:
+  pull_request:
+jobs:
+  agent:
+    if: github.event_name == 'pull_request'
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hi
+`,
+		},
+		{
+			name: "condition depending on runtime values",
+			yaml: `on: push
+jobs:
+  agent:
+    if: needs.activation.outputs.activated == 'true'
+    runs-on: ubuntu-latest
+    steps:
+      - name: Comment
+        if: github.event_name == 'issue_comment'
+        run: echo hi
+      - name: Always
+        if: always()
+        run: echo hi
+`,
+			expectedWarnings: []string{"step 'Comment' in job 'agent'"},
+		},
+		{
+			name: "reusable workflow inherits event name",
+			yaml: `on:
+  workflow_call:
+jobs:
+  agent:
+    if: github.event_name == 'issues'
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hi
+`,
+		},
+		{
+			name: "unsupported expression is not flagged",
+			yaml: `on: push
+jobs:
+  agent:
+    if: hashFiles('go.sum') != ''
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hi
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := validateStaticConditions(tt.yaml)
+			require.NoError(t, err)
+			require.Len(t, warnings, len(tt.expectedWarnings), "unexpected warnings: %v", warnings)
+			for i, expected := range tt.expectedWarnings {
+				assert.Contains(t, warnings[i], expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,394 @@
+// This file implements digest pinning of container images.
+//
+// # Container Image Pinning
+//
+// MCP servers, the MCP gateway, and the job container and services run container images
+// referenced by tag. Tags are mutable, so the compiler pins them the same way it pins
+// actions: each image:tag is resolved to its sha256 digest, recorded next to the action
+// pins in .github/aw/actions-lock.json, and the lock file references image@sha256:...
+// with the tag kept as a comment.
+//
+// Digests are resolved from the local docker daemon when it has the image, otherwise
+// from the registry API using an anonymous token. Only `gh aw compile` resolves digests;
+// other compilations use the digests already in the cache. Cached digests are refreshed
+// with --force-refresh-action-pins.
+//
+// The firewall images are the exception: AWF pulls the squid and agent images itself and only
+// accepts a tag (--image-tag), so they are downloaded and run by tag and are not recorded in
+// the action cache.
+
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/goccy/go-yaml"
+)
+
+var containerPinsLog = logger.New("workflow:container_pins")
+
+const dockerHubRegistry = "registry-1.docker.io"
+
+// registryManifestMediaTypes lists the manifest types accepted when resolving a tag, so
+// multi-platform images resolve to the digest of their index rather than of one platform
+var registryManifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// collectPinnableImages returns the container images of a workflow that are pinned to digests
+func collectPinnableImages(data *WorkflowData, actionMode ActionMode) []string {
+	var images []string
+	seen := make(map[string]bool)
+	add := func(image string) {
+		if image == "" || seen[image] || strings.Contains(image, "@") || strings.Contains(image, "${{") {
+			return
+		}
+		seen[image] = true
+		images = append(images, image)
+	}
+
+	for _, image := range collectDockerImages(data.Tools, data, actionMode) {
+		if strings.HasPrefix(image, constants.DefaultFirewallRegistry+"/") {
+			continue
+		}
+		add(image)
+	}
+	for _, image := range jobContainerImages(data.Container, "container") {
+		add(image)
+	}
+	for _, image := range jobContainerImages(data.Services, "services") {
+		add(image)
+	}
+
+	sort.Strings(images)
+	return images
+}
+
+// jobContainerImages extracts the images of a top-level container or services section
+func jobContainerImages(section, key string) []string {
+	if section == "" {
+		return nil
+	}
+	var parsed map[string]any
+	if err := yaml.Unmarshal([]byte(section), &parsed); err != nil {
+		containerPinsLog.Printf("Failed to parse %s section: %v", key, err)
+		return nil
+	}
+
+	imageOf := func(value any) string {
+		switch v := value.(type) {
+		case string:
+			return v
+		case map[string]any:
+			image, _ := v["image"].(string)
+			return image
+		}
+		return ""
+	}
+
+	if key == "container" {
+		if image := imageOf(parsed[key]); image != "" {
+			return []string{image}
+		}
+		return nil
+	}
+
+	services, _ := parsed[key].(map[string]any)
+	var images []string
+	for _, service := range services {
+		if image := imageOf(service); image != "" {
+			images = append(images, image)
+		}
+	}
+	return images
+}
+
+// resolveContainerImagePins resolves the container images of a workflow to digests and
+// records them on the workflow data, where the renderers of the MCP containers, the job
+// container and services, and the image download step pick them up. Images already pinned
+// by the dependency lock keep their locked digest. In strict mode, images whose digest
+// cannot be resolved produce a warning; without digest resolution, images missing from the
+// cache are left as they are.
+func (c *Compiler) resolveContainerImagePins(data *WorkflowData) {
+	images := collectPinnableImages(data, c.actionMode)
+	if len(images) == 0 {
+		return
+	}
+
+	_, resolver := c.getSharedActionResolver()
+	pins := make(map[string]string)
+	for image, digest := range data.ImagePins {
+		pins[image] = digest
+	}
+	for _, image := range images {
+		if _, ok := pins[image]; ok {
+			continue
+		}
+		digest, err := resolver.ResolveImageDigest(image, c.resolveImages)
+		if err != nil {
+			containerPinsLog.Printf("Container image %s is not pinned: %v", image, err)
+			if data.StrictMode && c.resolveImages {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Container image %s is not pinned to a digest: %v", image, err)))
+				c.IncrementWarningCount()
+			}
+			continue
+		}
+		pins[image] = digest
+	}
+
+	containerPinsLog.Printf("Pinned %d of %d container images", len(pins), len(images))
+	if len(pins) > 0 {
+		data.ImagePins = pins
+	}
+}
+
+// ResolveImageDigest resolves a container image reference to its sha256 digest.
+// Without lookup, only digests already in the cache are returned.
+func (r *ActionResolver) ResolveImageDigest(reference string, lookup bool) (string, error) {
+	if digest, found := r.cache.GetContainerDigest(reference); found {
+		return digest, nil
+	}
+	if !lookup {
+		return "", fmt.Errorf("no digest in %s", CacheFileName)
+	}
+	if r.failedImages[reference] {
+		return "", fmt.Errorf("digest resolution failed")
+	}
+
+	digest := resolveLocalImageDigest(reference)
+	if digest == "" {
+		var err error
+		if digest, err = resolveRegistryDigest(reference); err != nil {
+			if r.failedImages == nil {
+				r.failedImages = make(map[string]bool)
+			}
+			r.failedImages[reference] = true
+			return "", err
+		}
+	}
+
+	resolverLog.Printf("Resolved container image %s to %s", reference, digest)
+	r.cache.SetContainerDigest(reference, digest)
+	return digest, nil
+}
+
+// registryRepository splits an image reference into its registry host, repository, and tag,
+// applying the Docker Hub defaults for short names
+func registryRepository(reference string) (registry, repository, tag string) {
+	name, tag := splitImageReference(reference)
+	if tag == "" {
+		tag = "latest"
+	}
+
+	registry, repository = dockerHubRegistry, name
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, repository = first, rest
+	}
+	if registry == "docker.io" || registry == "index.docker.io" {
+		registry = dockerHubRegistry
+	}
+	if registry == dockerHubRegistry && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	return registry, repository, tag
+}
+
+// resolveRegistryDigest asks the registry for the manifest digest of an image tag
+func resolveRegistryDigest(reference string) (string, error) {
+	registry, repository, tag := registryRepository(reference)
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", registry, repository, tag)
+	containerPinsLog.Printf("Resolving %s from %s", reference, manifestURL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	resp, err := headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		token, err := fetchRegistryToken(ctx, challenge)
+		if err != nil {
+			return "", err
+		}
+		if resp, err = headManifest(ctx, manifestURL, token); err != nil {
+			return "", err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry %s returned %s for %s:%s", registry, resp.Status, repository, tag)
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if !strings.HasPrefix(digest, "sha256:") {
+		return "", fmt.Errorf("registry %s returned no digest for %s:%s", registry, repository, tag)
+	}
+	return digest, nil
+}
+
+// headManifest requests the headers of an image manifest
+func headManifest(ctx context.Context, manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(registryManifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return http.DefaultClient.Do(req)
+}
+
+var bearerChallengeParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// fetchRegistryToken requests an anonymous pull token for the scope of a bearer challenge
+func fetchRegistryToken(ctx context.Context, challenge string) (string, error) {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return "", fmt.Errorf("registry requires authentication")
+	}
+	params := make(map[string]string)
+	for _, match := range bearerChallengeParamPattern.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("registry authentication challenge has no realm")
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", fmt.Errorf("invalid registry authentication realm: %w", err)
+	}
+	query := tokenURL.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token request returned %s", resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid registry token response: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", fmt.Errorf("registry token response has no token")
+}
+
+// pinnedImage returns the digest reference of a pinned image, or the image itself
+func pinnedImage(image string, pins map[string]string) string {
+	digest, ok := pins[image]
+	if !ok {
+		return image
+	}
+	name, _ := splitImageReference(image)
+	return name + "@" + digest
+}
+
+// getImagePinsFromWorkflowData returns the container image pins of a workflow, or nil if there is no workflow data
+func getImagePinsFromWorkflowData(workflowData *WorkflowData) map[string]string {
+	if workflowData != nil {
+		return workflowData.ImagePins
+	}
+	return nil
+}
+
+// imageTag returns the tag an image reference is pulled by
+func imageTag(image string) string {
+	if _, tag := splitImageReference(image); tag != "" {
+		return tag
+	}
+	return "latest"
+}
+
+// renderJobContainerSection renders a top-level container or services section with its pinned
+// images referenced by digest, keeping the tags as comments
+func renderJobContainerSection(section, key string, pins map[string]string) string {
+	if section == "" || len(pins) == 0 {
+		return section
+	}
+	var parsed map[string]any
+	if err := yaml.Unmarshal([]byte(section), &parsed); err != nil {
+		containerPinsLog.Printf("Failed to parse %s section: %v", key, err)
+		return section
+	}
+
+	comments := yaml.CommentMap{}
+	pin := func(image, path string) (string, bool) {
+		if _, ok := pins[image]; !ok {
+			return image, false
+		}
+		comments[path] = []*yaml.Comment{yaml.LineComment(" " + imageTag(image))}
+		return pinnedImage(image, pins), true
+	}
+	pinEntry := func(value any, path string) any {
+		switch v := value.(type) {
+		case string:
+			if image, ok := pin(v, path); ok {
+				return image
+			}
+		case map[string]any:
+			if image, ok := v["image"].(string); ok {
+				v["image"], _ = pin(image, path+".image")
+			}
+		}
+		return value
+	}
+
+	if key == "container" {
+		parsed[key] = pinEntry(parsed[key], "$."+key)
+	} else if services, ok := parsed[key].(map[string]any); ok {
+		for name, service := range services {
+			services[name] = pinEntry(service, "$."+key+"."+name)
+		}
+	}
+	if len(comments) == 0 {
+		return section
+	}
+
+	options := append([]yaml.EncodeOption{yaml.WithComment(comments)}, DefaultMarshalOptions...)
+	rendered, err := yaml.MarshalWithOptions(OrderMapFields(parsed, []string{}), options...)
+	if err != nil {
+		containerPinsLog.Printf("Failed to render %s section: %v", key, err)
+		return section
+	}
+	return CleanYAMLNullValues(strings.TrimSuffix(string(rendered), "\n"))
+}
@@ -0,0 +1,222 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryRepository(t *testing.T) {
+	tests := []struct{ image, registry, repository, tag string }{
+		{"node:lts-alpine", "registry-1.docker.io", "library/node", "lts-alpine"},
+		{"docker.io/mcp/fetch", "registry-1.docker.io", "mcp/fetch", "latest"},
+		{"mcp/fetch:1.0", "registry-1.docker.io", "mcp/fetch", "1.0"},
+		{"ghcr.io/github/github-mcp-server:v0.30.3", "ghcr.io", "github/github-mcp-server", "v0.30.3"},
+		{"localhost:5000/tool:dev", "localhost:5000", "tool", "dev"},
+	}
+	for _, tt := range tests {
+		registry, repository, tag := registryRepository(tt.image)
+		assert.Equal(t, tt.registry, registry, tt.image)
+		assert.Equal(t, tt.repository, repository, tt.image)
+		assert.Equal(t, tt.tag, tag, tt.image)
+	}
+}
+
+func TestFetchRegistryToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ghcr.io", r.URL.Query().Get("service"))
+		assert.Equal(t, "repository:github/github-mcp-server:pull", r.URL.Query().Get("scope"))
+		_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+	}))
+	defer server.Close()
+
+	challenge := `Bearer realm="` + server.URL + `/token",service="ghcr.io",scope="repository:github/github-mcp-server:pull"`
+	token, err := fetchRegistryToken(t.Context(), challenge)
+	require.NoError(t, err)
+	assert.Equal(t, "anonymous", token)
+
+	_, err = fetchRegistryToken(t.Context(), `Basic realm="registry"`)
+	require.ErrorContains(t, err, "requires authentication")
+}
+
+func TestJobContainerImages(t *testing.T) {
+	assert.Equal(t, []string{"node:20"}, jobContainerImages("container: node:20\n", "container"))
+	assert.Equal(t, []string{"node:20"}, jobContainerImages("container:\n  image: node:20\n  options: --cpus 1\n", "container"))
+	assert.Empty(t, jobContainerImages("", "container"))
+
+	images := jobContainerImages("services:\n  postgres:\n    image: postgres:16\n  redis:\n    image: redis\n", "services")
+	assert.ElementsMatch(t, []string{"postgres:16", "redis"}, images)
+}
+
+func TestRenderJobContainerSection(t *testing.T) {
+	pins := map[string]string{"postgres:16": "sha256:abc", "node:20": "sha256:def"}
+
+	assert.Equal(t, "container: node@sha256:def # 20", renderJobContainerSection("container: node:20", "container", pins))
+	assert.Equal(t, "container:\n  env:\n    CI: \"true\"\n  image: node@sha256:def # 20",
+		renderJobContainerSection("container:\n  image: node:20\n  env:\n    CI: \"true\"", "container", pins))
+	assert.Equal(t, "services:\n  my-db:\n    image: postgres@sha256:abc # 16\n    options: --health-cmd \"pg_isready -d postgres:16\"\n  redis:\n    image: redis",
+		renderJobContainerSection("services:\n  my-db:\n    image: postgres:16\n    options: --health-cmd \"pg_isready -d postgres:16\"\n  redis:\n    image: redis", "services", pins))
+	assert.Equal(t, "container:\n  image: node:20", renderJobContainerSection("container:\n  image: node:20", "container", nil))
+	assert.Equal(t, "services:\n  redis:\n    image: redis", renderJobContainerSection("services:\n  redis:\n    image: redis", "services", pins))
+}
+
+func TestMCPRenderersPinContainerImages(t *testing.T) {
+	pins := map[string]string{
+		"ghcr.io/github/github-mcp-server:v1": "sha256:1111",
+		"mcr.microsoft.com/playwright/mcp":    "sha256:2222",
+		"node:lts-alpine":                     "sha256:3333",
+	}
+
+	var github strings.Builder
+	RenderGitHubMCPDockerConfig(&github, GitHubMCPDockerOptions{DockerImageVersion: "v1", ImagePins: pins})
+	assert.Contains(t, github.String(), `"container": "ghcr.io/github/github-mcp-server@sha256:1111",`)
+
+	var playwright strings.Builder
+	renderPlaywrightMCPConfigWithOptions(&playwright, nil, true, false, false, pins)
+	assert.Contains(t, playwright.String(), `"container": "mcr.microsoft.com/playwright/mcp@sha256:2222",`)
+
+	var toml strings.Builder
+	NewMCPConfigRenderer(MCPRendererOptions{Format: "toml", ImagePins: pins}).RenderPlaywrightMCP(&toml, nil)
+	assert.Contains(t, toml.String(), `container = "mcr.microsoft.com/playwright/mcp@sha256:2222"`)
+
+	var custom strings.Builder
+	err := renderSharedMCPConfig(&custom, "tool", map[string]any{"container": "node:lts-alpine", "args": []any{"node:lts-alpine"}},
+		MCPConfigRenderer{Format: "json", IndentLevel: "  ", ImagePins: pins})
+	require.NoError(t, err)
+	assert.Contains(t, custom.String(), `"container": "node@sha256:3333"`)
+	assert.Contains(t, custom.String(), `"node:lts-alpine"`)
+}
+
+func TestGenerateDownloadDockerImagesStepPinsImages(t *testing.T) {
+	var yaml strings.Builder
+	generateDownloadDockerImagesStep(&yaml, []string{"ghcr.io/github/gh-aw-firewall/agent:0.13.14", "postgres:16"}, map[string]string{"postgres:16": "sha256:abc"})
+
+	assert.Equal(t, strings.Join([]string{
+		"      - name: Download container images",
+		"        # postgres@sha256:abc # 16",
+		"        run: bash /opt/gh-aw/actions/download_docker_images.sh ghcr.io/github/gh-aw-firewall/agent:0.13.14 postgres@sha256:abc",
+		"",
+	}, "\n"), yaml.String())
+}
+
+func TestActionCacheContainerDigests(t *testing.T) {
+	dir := testutil.TempDir(t, "container-cache-*")
+	cache := NewActionCache(dir)
+	cache.SetContainerDigest("mcr.microsoft.com/playwright/mcp", "sha256:abc")
+	require.NoError(t, cache.Save())
+
+	data, err := os.ReadFile(cache.GetCachePath())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"containers": {`)
+	assert.Contains(t, string(data), `"tag": "latest"`)
+
+	reloaded := NewActionCache(dir)
+	require.NoError(t, reloaded.Load())
+	digest, ok := reloaded.GetContainerDigest("mcr.microsoft.com/playwright/mcp")
+	require.True(t, ok)
+	assert.Equal(t, "sha256:abc", digest)
+	assert.Empty(t, reloaded.Entries)
+}
+
+func TestContainerImagesPinnedFromCache(t *testing.T) {
+	dir := testutil.TempDir(t, "container-pins-*")
+	githubImage := "ghcr.io/github/github-mcp-server:" + string(constants.DefaultGitHubMCPServerVersion)
+	cache := NewActionCache(dir)
+	cache.SetContainerDigest(githubImage, "sha256:1111")
+	cache.SetContainerDigest("postgres:16", "sha256:2222")
+	require.NoError(t, cache.Save())
+
+	workflowPath := filepath.Join(dir, "pins.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(`---
+on: workflow_dispatch
+permissions:
+  contents: read
+  issues: read
+engine: copilot
+tools:
+  github:
+    mode: local
+    toolsets: [issues]
+services:
+  postgres:
+    image: postgres:16
+---
+# Pins
+`), 0644))
+
+	compiler := NewCompiler(WithGitRoot(dir))
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+	content, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err)
+	lockContent := string(content)
+
+	assert.Contains(t, lockContent, "ghcr.io/github/github-mcp-server@sha256:1111")
+	assert.NotContains(t, lockContent, `"`+githubImage+`"`)
+	assert.Contains(t, lockContent, "# ghcr.io/github/github-mcp-server@sha256:1111 # "+string(constants.DefaultGitHubMCPServerVersion))
+	assert.Contains(t, lockContent, "image: postgres@sha256:2222 # 16")
+	assert.Contains(t, lockContent, constants.DefaultFirewallRegistry+"/agent:", "firewall images are pulled by tag")
+}
+
+func TestContainerImagePinsLeavePromptAndScriptsUnchanged(t *testing.T) {
+	dir := testutil.TempDir(t, "container-pins-prompt-*")
+	cache := NewActionCache(dir)
+	cache.SetContainerDigest("node:lts-alpine", "sha256:3333")
+	cache.SetContainerDigest("alpine", "sha256:4444")
+	require.NoError(t, cache.Save())
+
+	// Imported markdown with inputs is inlined into the prompt heredoc of the lock file
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "shared"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shared", "images.md"), []byte(`---
+inputs:
+  topic:
+    type: string
+    default: releases
+---
+Check whether the node:lts-alpine image is up to date and report on alpine ${{ github.aw.inputs.topic }}.
+`), 0644))
+
+	workflowPath := filepath.Join(dir, "prompt.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(`---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: copilot
+container:
+  image: alpine
+imports:
+  - path: shared/images.md
+    inputs:
+      topic: releases
+steps:
+  - name: Show image
+    run: echo "building on node:lts-alpine and alpine"
+safe-outputs:
+  create-issue:
+---
+# Prompt
+`), 0644))
+
+	compiler := NewCompiler(WithGitRoot(dir))
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+	content, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err)
+	lockContent := string(content)
+
+	assert.Contains(t, lockContent, "Check whether the node:lts-alpine image is up to date and report on alpine releases.", "prompt text must not be rewritten")
+	assert.Contains(t, lockContent, `echo "building on node:lts-alpine and alpine"`, "run scripts must not be rewritten")
+	assert.Contains(t, lockContent, "image: alpine@sha256:4444 # latest", "the job container image should be pinned")
+	assert.Contains(t, lockContent, "download_docker_images.sh", "the download step should be generated")
+	assert.Contains(t, lockContent, " node@sha256:3333", "the safe outputs image should be downloaded by digest")
+	assert.NotContains(t, lockContent, "Check whether the node@sha256", "prompt text must not be rewritten")
+}
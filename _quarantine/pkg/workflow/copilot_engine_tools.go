@@ -86,6 +86,11 @@ func (e *CopilotEngine) computeCopilotToolArguments(tools map[string]any, safeOu
 		args = append(args, "--allow-tool", constants.SafeInputsMCPServerID)
 	}
 
+	// Handle repo-memory MCP server - allow the server if any memory enables mcp
+	if hasRepoMemoryMCP(workflowData) {
+		args = append(args, "--allow-tool", constants.RepoMemoryMCPServerID)
+	}
+
 	// Handle web-fetch builtin tool (Copilot CLI uses web_fetch with underscore)
 	if _, hasWebFetch := tools["web-fetch"]; hasWebFetch {
 		// web-fetch -> web_fetch
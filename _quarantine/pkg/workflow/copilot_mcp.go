@@ -24,6 +24,7 @@ func (e *CopilotEngine) RenderMCPConfig(yaml *strings.Builder, tools map[string]
 			Format:               "json",
 			IsLast:               isLast,
 			ActionMode:           GetActionModeFromWorkflowData(workflowData),
+			ImagePins:            getImagePinsFromWorkflowData(workflowData),
 		})
 	}
 
@@ -63,6 +64,10 @@ func (e *CopilotEngine) RenderMCPConfig(yaml *strings.Builder, tools map[string]
 				renderer := createRenderer(isLast)
 				renderer.RenderSafeInputsMCP(yaml, safeInputs, workflowData)
 			},
+			RenderRepoMemory: func(yaml *strings.Builder, isLast bool, workflowData *WorkflowData) {
+				renderer := createRenderer(isLast)
+				renderer.RenderRepoMemoryMCP(yaml, workflowData)
+			},
 			RenderWebFetch: func(yaml *strings.Builder, isLast bool) {
 				renderMCPFetchServerConfig(yaml, "json", "              ", isLast, true)
 			},
@@ -97,6 +102,7 @@ func (e *CopilotEngine) renderCopilotMCPConfigWithContext(yaml *strings.Builder,
 		IndentLevel:              "                ",
 		RequiresCopilotFields:    true,
 		RewriteLocalhostToDocker: rewriteLocalhost,
+		ImagePins:                getImagePinsFromWorkflowData(workflowData),
 	}
 
 	yaml.WriteString("              \"" + toolName + "\": {\n")
@@ -13,6 +13,7 @@ var discussionLog = logger.New("workflow:create_discussion")
 // CreateDiscussionsConfig holds configuration for creating GitHub discussions from agent output
 type CreateDiscussionsConfig struct {
 	BaseSafeOutputConfig  `yaml:",inline"`
+	SafeOutputQuotaConfig `yaml:",inline"`
 	TitlePrefix           string   `yaml:"title-prefix,omitempty"`
 	Category              string   `yaml:"category,omitempty"`                // Discussion category ID or name
 	Labels                []string `yaml:"labels,omitempty"`                  // Labels to attach to discussions and match when closing older ones
@@ -11,18 +11,19 @@ var createPRLog = logger.New("workflow:create_pull_request")
 
 // CreatePullRequestsConfig holds configuration for creating GitHub pull requests from agent output
 type CreatePullRequestsConfig struct {
-	BaseSafeOutputConfig `yaml:",inline"`
-	TitlePrefix          string   `yaml:"title-prefix,omitempty"`
-	Labels               []string `yaml:"labels,omitempty"`
-	AllowedLabels        []string `yaml:"allowed-labels,omitempty"` // Optional list of allowed labels. If omitted, any labels are allowed (including creating new ones).
-	Reviewers            []string `yaml:"reviewers,omitempty"`      // List of users/bots to assign as reviewers to the pull request
-	Draft                *bool    `yaml:"draft,omitempty"`          // Pointer to distinguish between unset (nil) and explicitly false
-	IfNoChanges          string   `yaml:"if-no-changes,omitempty"`  // Behavior when no changes to push: "warn" (default), "error", or "ignore"
-	AllowEmpty           bool     `yaml:"allow-empty,omitempty"`    // Allow creating PR without patch file or with empty patch (useful for preparing feature branches)
-	TargetRepoSlug       string   `yaml:"target-repo,omitempty"`    // Target repository in format "owner/repo" for cross-repository pull requests
-	AllowedRepos         []string `yaml:"allowed-repos,omitempty"`  // List of additional repositories that pull requests can be created in (additionally to the target-repo)
-	Expires              int      `yaml:"expires,omitempty"`        // Hours until the pull request expires and should be automatically closed (only for same-repo PRs)
-	AutoMerge            bool     `yaml:"auto-merge,omitempty"`     // Enable auto-merge for the pull request when all required checks pass
+	BaseSafeOutputConfig  `yaml:",inline"`
+	SafeOutputQuotaConfig `yaml:",inline"`
+	TitlePrefix           string   `yaml:"title-prefix,omitempty"`
+	Labels                []string `yaml:"labels,omitempty"`
+	AllowedLabels         []string `yaml:"allowed-labels,omitempty"` // Optional list of allowed labels. If omitted, any labels are allowed (including creating new ones).
+	Reviewers             []string `yaml:"reviewers,omitempty"`      // List of users/bots to assign as reviewers to the pull request
+	Draft                 *bool    `yaml:"draft,omitempty"`          // Pointer to distinguish between unset (nil) and explicitly false
+	IfNoChanges           string   `yaml:"if-no-changes,omitempty"`  // Behavior when no changes to push: "warn" (default), "error", or "ignore"
+	AllowEmpty            bool     `yaml:"allow-empty,omitempty"`    // Allow creating PR without patch file or with empty patch (useful for preparing feature branches)
+	TargetRepoSlug        string   `yaml:"target-repo,omitempty"`    // Target repository in format "owner/repo" for cross-repository pull requests
+	AllowedRepos          []string `yaml:"allowed-repos,omitempty"`  // List of additional repositories that pull requests can be created in (additionally to the target-repo)
+	Expires               int      `yaml:"expires,omitempty"`        // Hours until the pull request expires and should be automatically closed (only for same-repo PRs)
+	AutoMerge             bool     `yaml:"auto-merge,omitempty"`     // Enable auto-merge for the pull request when all required checks pass
 }
 
 // buildCreateOutputPullRequestJob creates the create_pull_request job
@@ -259,6 +259,7 @@ func (e *CustomEngine) RenderMCPConfig(yaml *strings.Builder, tools map[string]a
 			Format:               "json",
 			IsLast:               isLast,
 			ActionMode:           GetActionModeFromWorkflowData(workflowData),
+			ImagePins:            getImagePinsFromWorkflowData(workflowData),
 		})
 	}
 
@@ -292,6 +293,10 @@ func (e *CustomEngine) RenderMCPConfig(yaml *strings.Builder, tools map[string]a
 				renderer := createRenderer(isLast)
 				renderer.RenderSafeInputsMCP(yaml, safeInputs, workflowData)
 			},
+			RenderRepoMemory: func(yaml *strings.Builder, isLast bool, workflowData *WorkflowData) {
+				renderer := createRenderer(isLast)
+				renderer.RenderRepoMemoryMCP(yaml, workflowData)
+			},
 			RenderWebFetch: func(yaml *strings.Builder, isLast bool) {
 				renderMCPFetchServerConfig(yaml, "json", "              ", isLast, false)
 			},
@@ -47,7 +47,15 @@ func validateDangerousPermissions(workflowData *WorkflowData) error {
 	writePermissions := findWritePermissions(permissions)
 	if len(writePermissions) > 0 {
 		dangerousPermissionsLog.Printf("Found %d write permissions without feature flag", len(writePermissions))
-		return formatDangerousPermissionsError(writePermissions)
+		locations := make([]SourceLocation, 0, len(writePermissions))
+		for _, scope := range writePermissions {
+			locations = append(locations, SourceLocation{
+				Rule:    "dangerous-permissions",
+				Path:    "/permissions/" + string(scope),
+				Message: fmt.Sprintf("write permission '%s: write' requires the %s feature", scope, constants.DangerousPermissionsWriteFeatureFlag),
+			})
+		}
+		return withSourceLocations(formatDangerousPermissionsError(writePermissions), locations...)
 	}
 
 	dangerousPermissionsLog.Print("No write permissions found, validation passed")
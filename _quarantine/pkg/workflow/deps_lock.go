@@ -0,0 +1,714 @@
+// This file implements the dependency lock file of agentic workflows.
+//
+// # Dependency Lock File
+//
+// Workflows install packages at run time: engine CLIs through npm, MCP servers through
+// npx and uvx, tools through pip, and container images through docker. Unless a workflow
+// spells out an exact version, each run fetches whatever the registry serves that day.
+//
+// `gh aw compile --lock-deps` resolves every such dependency to an exact version (or
+// image digest) and records it in .github/aw/aw-deps.lock, next to the action pin cache.
+// The file is committed with the workflows. Whenever it exists, the compiler rewrites
+// unversioned or ranged package references in the generated steps to the locked versions,
+// so runs are reproducible until the lock is refreshed with `--lock-deps --force`.
+//
+// Container images are recorded with the digest the local docker daemon knows for them,
+// or otherwise the digest the registry serves for their tag, and the generated steps
+// reference them by that digest. An image whose digest cannot be found is not locked: a
+// warning is shown, or compilation fails in strict mode. The firewall images are recorded
+// but keep their tag, since AWF pulls them itself by tag.
+//
+// For the SBOM built from the locked dependencies, see pkg/cli/sbom_command.go.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var depsLockLog = logger.New("workflow:deps_lock")
+
+const (
+	// DepsLockFileName is the name of the dependency lock file in .github/aw/.
+	DepsLockFileName = "aw-deps.lock"
+
+	// DepsEcosystemNpm is the ecosystem of npm packages (engine CLIs, npx)
+	DepsEcosystemNpm = "npm"
+	// DepsEcosystemPyPI is the ecosystem of Python packages (pip, uvx)
+	DepsEcosystemPyPI = "pypi"
+	// DepsEcosystemDocker is the ecosystem of container images
+	DepsEcosystemDocker = "docker"
+)
+
+// engineCLIPackages maps engine IDs to the npm package of their CLI
+var engineCLIPackages = map[string]string{
+	"claude":  "@anthropic-ai/claude-code",
+	"codex":   "@openai/codex",
+	"copilot": "@github/copilot",
+}
+
+// WorkflowDependency is a package, engine CLI or container image a workflow installs at run time
+type WorkflowDependency struct {
+	Ecosystem string // npm, pypi or docker
+	Name      string // Package name or image repository
+	Requested string // Version, range or tag as written in the workflow (empty when unversioned)
+	Engine    string // Engine ID when the dependency is an engine CLI
+	Command   string // Command that installs the package (npx, pip or uvx), empty for engines and images
+	Reference string // Reference as written in the workflow (e.g. "@playwright/mcp@latest")
+}
+
+// LockedDependency is an entry of the dependency lock file
+type LockedDependency struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Requested string `json:"requested,omitempty"` // Version, range or tag the entry was resolved from
+	Version   string `json:"version,omitempty"`   // Exact version (or tag of an image)
+	Digest    string `json:"digest,omitempty"`    // Image digest (sha256:...)
+	Engine    string `json:"engine,omitempty"`    // Engine ID for engine CLIs
+}
+
+// DepsLock manages the dependency lock file
+type DepsLock struct {
+	Dependencies []LockedDependency `json:"dependencies"`
+	path         string
+	exists       bool            // whether the file was loaded from disk
+	dirty        bool            // tracks if the lock has unsaved changes
+	used         map[string]bool // keys of the entries used by the compiled workflows
+}
+
+// NewDepsLock creates a dependency lock for the repository at repoRoot
+func NewDepsLock(repoRoot string) *DepsLock {
+	lockPath := filepath.Join(repoRoot, ".github", "aw", DepsLockFileName)
+	depsLockLog.Printf("Creating dependency lock with path: %s", lockPath)
+	return &DepsLock{
+		path: lockPath,
+		used: make(map[string]bool),
+	}
+}
+
+// Load loads the lock from disk. A missing file is not an error.
+func (l *DepsLock) Load() error {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			depsLockLog.Print("Dependency lock does not exist, starting with an empty lock")
+			return nil
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", l.path, err)
+	}
+	l.exists = true
+	l.dirty = false
+	depsLockLog.Printf("Loaded dependency lock with %d entries", len(l.Dependencies))
+	return nil
+}
+
+// Exists reports whether the lock was loaded from an existing file
+func (l *DepsLock) Exists() bool {
+	return l.exists
+}
+
+// GetPath returns the path of the lock file
+func (l *DepsLock) GetPath() string {
+	return l.path
+}
+
+// Save writes the lock to disk with sorted entries, if it has been modified
+func (l *DepsLock) Save() error {
+	if !l.dirty {
+		depsLockLog.Print("Dependency lock is clean, skipping save")
+		return nil
+	}
+	sort.Slice(l.Dependencies, func(i, j int) bool {
+		return depsLockKey(l.Dependencies[i].Ecosystem, l.Dependencies[i].Name, l.Dependencies[i].Requested) <
+			depsLockKey(l.Dependencies[j].Ecosystem, l.Dependencies[j].Name, l.Dependencies[j].Requested)
+	})
+	if l.Dependencies == nil {
+		l.Dependencies = []LockedDependency{}
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal dependency lock: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", l.path, err)
+	}
+	if err := os.WriteFile(l.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", l.path, err)
+	}
+	l.exists = true
+	l.dirty = false
+	depsLockLog.Printf("Saved dependency lock with %d entries to %s", len(l.Dependencies), l.path)
+	return nil
+}
+
+// Lookup returns the entry locking a dependency
+func (l *DepsLock) Lookup(dep WorkflowDependency) (LockedDependency, bool) {
+	key := depsLockKey(dep.Ecosystem, dep.Name, dep.Requested)
+	for _, entry := range l.Dependencies {
+		if depsLockKey(entry.Ecosystem, entry.Name, entry.Requested) == key {
+			return entry, true
+		}
+	}
+	return LockedDependency{}, false
+}
+
+// Set adds or replaces an entry
+func (l *DepsLock) Set(entry LockedDependency) {
+	key := depsLockKey(entry.Ecosystem, entry.Name, entry.Requested)
+	l.used[key] = true
+	for i, existing := range l.Dependencies {
+		if depsLockKey(existing.Ecosystem, existing.Name, existing.Requested) == key {
+			if existing != entry {
+				l.Dependencies[i] = entry
+				l.dirty = true
+			}
+			return
+		}
+	}
+	l.Dependencies = append(l.Dependencies, entry)
+	l.dirty = true
+}
+
+// Prune removes the entries no compiled workflow used. Call it only after compiling
+// every workflow of the repository.
+func (l *DepsLock) Prune() {
+	kept := l.Dependencies[:0]
+	for _, entry := range l.Dependencies {
+		if l.used[depsLockKey(entry.Ecosystem, entry.Name, entry.Requested)] {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) != len(l.Dependencies) {
+		depsLockLog.Printf("Pruned %d unused dependency lock entries", len(l.Dependencies)-len(kept))
+		l.dirty = true
+	}
+	l.Dependencies = kept
+}
+
+// depsLockKey identifies a dependency by ecosystem, name and requested version
+func depsLockKey(ecosystem, name, requested string) string {
+	return ecosystem + ":" + name + "@" + requested
+}
+
+// dependencyCommands are the package launchers whose packages are locked, with the
+// separator of the pinned reference they accept
+var dependencyCommands = []struct {
+	command      string
+	ecosystem    string
+	commandNames []string
+	separator    string
+	extract      func(string) []string
+}{
+	{"npx", DepsEcosystemNpm, []string{"npx"}, "@", extractNpxFromCommands},
+	{"pip", DepsEcosystemPyPI, []string{"pip", "pip3"}, "==", extractPipFromCommands},
+	{"uvx", DepsEcosystemPyPI, []string{"uvx"}, "@", extractUvxFromCommands},
+}
+
+// extractUvxFromCommands extracts the packages run with uvx from command strings
+func extractUvxFromCommands(commands string) []string {
+	extractor := PackageExtractor{
+		CommandNames: []string{"uvx"},
+		TrimSuffixes: "&|;",
+	}
+	return extractor.ExtractPackages(commands)
+}
+
+// CollectWorkflowDependencies lists the engine CLI, packages and container images a
+// workflow installs at run time
+func CollectWorkflowDependencies(workflowData *WorkflowData, actionMode ActionMode) []WorkflowDependency {
+	var deps []WorkflowDependency
+
+	if dep, ok := engineCLIDependency(workflowData); ok {
+		deps = append(deps, dep)
+	}
+
+	for _, launcher := range dependencyCommands {
+		for _, reference := range collectPackagesFromWorkflow(workflowData, launcher.extract, launcher.command) {
+			var name, requested string
+			if launcher.ecosystem == DepsEcosystemNpm {
+				name, requested = splitNpmPackageSpec(reference)
+			} else {
+				name, requested = splitPythonPackageSpec(reference)
+			}
+			if !isLockablePackageName(launcher.ecosystem, name) {
+				continue
+			}
+			deps = append(deps, WorkflowDependency{
+				Ecosystem: launcher.ecosystem,
+				Name:      name,
+				Requested: requested,
+				Command:   launcher.command,
+				Reference: reference,
+			})
+		}
+	}
+
+	for _, image := range collectDockerImages(workflowData.Tools, workflowData, actionMode) {
+		name, tag := splitImageReference(image)
+		deps = append(deps, WorkflowDependency{
+			Ecosystem: DepsEcosystemDocker,
+			Name:      name,
+			Requested: tag,
+			Reference: image,
+		})
+	}
+
+	depsLockLog.Printf("Collected %d runtime dependencies", len(deps))
+	return deps
+}
+
+// isLockablePackageName reports whether a package reference names a registry package
+// rather than a local path, a requirements file or an expression
+func isLockablePackageName(ecosystem, name string) bool {
+	if name == "" || strings.Contains(name, "${{") || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".txt") {
+		return false
+	}
+	return ecosystem != DepsEcosystemPyPI || !strings.ContainsAny(name, "/\\:")
+}
+
+// engineCLIDependency returns the npm package of the workflow's engine CLI, unless the
+// engine runs a custom command
+func engineCLIDependency(workflowData *WorkflowData) (WorkflowDependency, bool) {
+	engineID := workflowData.AI
+	if workflowData.EngineConfig != nil {
+		if workflowData.EngineConfig.Command != "" {
+			return WorkflowDependency{}, false
+		}
+		if workflowData.EngineConfig.ID != "" {
+			engineID = workflowData.EngineConfig.ID
+		}
+	}
+	packageName, ok := engineCLIPackages[engineID]
+	if !ok {
+		return WorkflowDependency{}, false
+	}
+
+	version := ""
+	if workflowData.EngineConfig != nil {
+		version = workflowData.EngineConfig.Version
+	}
+	if version == "" {
+		switch engineID {
+		case "claude":
+			version = string(constants.DefaultClaudeCodeVersion)
+		case "codex":
+			version = string(constants.DefaultCodexVersion)
+		default:
+			version = string(constants.DefaultCopilotVersion)
+		}
+	}
+	return WorkflowDependency{
+		Ecosystem: DepsEcosystemNpm,
+		Name:      packageName,
+		Requested: version,
+		Engine:    engineID,
+		Reference: packageName + "@" + version,
+	}, true
+}
+
+// splitNpmPackageSpec splits an npm package reference into its name and version
+// (e.g. "@playwright/mcp@latest" -> "@playwright/mcp", "latest")
+func splitNpmPackageSpec(spec string) (name, requested string) {
+	if at := strings.LastIndex(spec, "@"); at > 0 {
+		return spec[:at], spec[at+1:]
+	}
+	return spec, ""
+}
+
+// splitPythonPackageSpec splits a Python requirement into its name and version specifier
+// (e.g. "black==24.1.0" -> "black", "==24.1.0"). The uvx form "black@24.1.0" is
+// normalized to "==24.1.0"; extras are dropped from the name.
+func splitPythonPackageSpec(spec string) (name, requested string) {
+	if at := strings.Index(spec, "@"); at > 0 {
+		return strings.ToLower(spec[:at]), "==" + spec[at+1:]
+	}
+	end := strings.IndexAny(spec, "=<>!~;[ ")
+	if end < 0 {
+		return strings.ToLower(spec), ""
+	}
+	name = strings.ToLower(spec[:end])
+	rest := spec[end:]
+	if strings.HasPrefix(rest, "[") {
+		if closing := strings.Index(rest, "]"); closing >= 0 {
+			rest = rest[closing+1:]
+		}
+	}
+	return name, strings.TrimSpace(rest)
+}
+
+// splitImageReference splits a container image reference into its repository and its
+// tag or digest (e.g. "ghcr.io/github/github-mcp-server:v0.30.3")
+func splitImageReference(image string) (name, tag string) {
+	if at := strings.Index(image, "@"); at >= 0 {
+		return image[:at], image[at+1:]
+	}
+	if colon := strings.LastIndex(image, ":"); colon > strings.LastIndex(image, "/") {
+		return image[:colon], image[colon+1:]
+	}
+	return image, ""
+}
+
+// imageTagAndDigest splits the tag or digest of an image reference
+func imageTagAndDigest(requested string) (tag, digest string) {
+	if strings.HasPrefix(requested, "sha256:") {
+		return "", requested
+	}
+	return requested, ""
+}
+
+var exactNpmVersionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+(?:[-+][0-9A-Za-z.+-]+)?$`)
+
+// isExactVersion reports whether the requested version of a package names a single release
+func isExactVersion(ecosystem, requested string) bool {
+	switch ecosystem {
+	case DepsEcosystemNpm:
+		return exactNpmVersionPattern.MatchString(requested)
+	case DepsEcosystemPyPI:
+		version, ok := strings.CutPrefix(requested, "==")
+		return ok && version != "" && !strings.ContainsAny(version, "*,;<>!~ ")
+	case DepsEcosystemDocker:
+		return strings.HasPrefix(requested, "sha256:")
+	}
+	return false
+}
+
+// getDepsLock returns the dependency lock of the repository, loading it on first use
+func (c *Compiler) getDepsLock() *DepsLock {
+	if c.depsLock == nil {
+		baseDir := c.gitRoot
+		if baseDir == "" {
+			cwd, err := os.Getwd()
+			if err != nil {
+				cwd = "."
+			}
+			baseDir = cwd
+		}
+		c.depsLock = NewDepsLock(baseDir)
+		if !c.refreshDepsLock {
+			if err := c.depsLock.Load(); err != nil {
+				depsLockLog.Printf("Failed to load dependency lock: %v", err)
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(err.Error()))
+			}
+		}
+	}
+	return c.depsLock
+}
+
+// GetDepsLock returns the dependency lock used by this compiler instance
+func (c *Compiler) GetDepsLock() *DepsLock {
+	return c.getDepsLock()
+}
+
+// LockedWorkflowDependencies returns the runtime dependencies of a workflow with the
+// versions and digests recorded in the dependency lock. Dependencies missing from the
+// lock keep their exact requested version, or no version.
+func (c *Compiler) LockedWorkflowDependencies(workflowData *WorkflowData) []LockedDependency {
+	lock := c.getDepsLock()
+	var locked []LockedDependency
+	for _, dep := range CollectWorkflowDependencies(workflowData, c.actionMode) {
+		if entry, ok := lock.Lookup(dep); ok {
+			locked = append(locked, entry)
+			continue
+		}
+		entry := LockedDependency{Ecosystem: dep.Ecosystem, Name: dep.Name, Requested: dep.Requested, Engine: dep.Engine}
+		if isExactVersion(dep.Ecosystem, dep.Requested) {
+			entry.Version = strings.TrimPrefix(dep.Requested, "==")
+		}
+		if dep.Ecosystem == DepsEcosystemDocker {
+			entry.Version, entry.Digest = imageTagAndDigest(dep.Requested)
+		}
+		locked = append(locked, entry)
+	}
+	return locked
+}
+
+// applyDepsLock pins the runtime dependencies of a workflow to the versions of the
+// dependency lock. With --lock-deps, dependencies missing from the lock are resolved and
+// added to it.
+func (c *Compiler) applyDepsLock(workflowData *WorkflowData) error {
+	lock := c.getDepsLock()
+	if !lock.Exists() && !c.lockDeps {
+		return nil
+	}
+
+	for _, dep := range CollectWorkflowDependencies(workflowData, c.actionMode) {
+		entry, ok := lock.Lookup(dep)
+		if !ok {
+			if !c.lockDeps {
+				depsLockLog.Printf("Dependency %s is not locked", dep.Reference)
+				continue
+			}
+			resolved, err := resolveDependency(dep)
+			if err != nil {
+				if c.strictMode {
+					return fmt.Errorf("failed to lock %s: %w", dep.Reference, err)
+				}
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to lock %s: %v", dep.Reference, err)))
+				c.IncrementWarningCount()
+				continue
+			}
+			entry = resolved
+		}
+		lock.Set(entry)
+
+		if dep.Ecosystem == DepsEcosystemDocker {
+			pinImageDigest(workflowData, dep, entry.Digest)
+			continue
+		}
+		if isExactVersion(dep.Ecosystem, dep.Requested) || entry.Version == "" {
+			continue
+		}
+		pinDependency(workflowData, dep, entry.Version)
+	}
+	return nil
+}
+
+// pinDependency rewrites the references to a dependency in the workflow to an exact version
+func pinDependency(workflowData *WorkflowData, dep WorkflowDependency, version string) {
+	if dep.Engine != "" {
+		if workflowData.EngineConfig == nil {
+			workflowData.EngineConfig = &EngineConfig{ID: dep.Engine}
+		}
+		depsLockLog.Printf("Pinning %s CLI to %s", dep.Engine, version)
+		workflowData.EngineConfig.Version = version
+		return
+	}
+
+	for _, launcher := range dependencyCommands {
+		if launcher.command != dep.Command {
+			continue
+		}
+		pinned := strings.TrimSuffix(dep.Reference, packageVersionSuffix(dep)) + launcher.separator + version
+		depsLockLog.Printf("Pinning %s to %s", dep.Reference, pinned)
+
+		workflowData.CustomSteps = pinCommandArgument(workflowData.CustomSteps, launcher.commandNames, dep.Reference, pinned)
+		if workflowData.EngineConfig != nil {
+			for _, step := range workflowData.EngineConfig.Steps {
+				if run, ok := step["run"].(string); ok {
+					step["run"] = pinCommandArgument(run, launcher.commandNames, dep.Reference, pinned)
+				}
+			}
+		}
+		for _, toolConfig := range workflowData.Tools {
+			config, ok := toolConfig.(map[string]any)
+			if !ok || config["command"] != launcher.command {
+				continue
+			}
+			if args, ok := config["args"].([]any); ok {
+				for i, arg := range args {
+					if arg == dep.Reference {
+						args[i] = pinned
+						break
+					}
+					if argStr, ok := arg.(string); ok && !strings.HasPrefix(argStr, "-") {
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+// pinImageDigest records the locked digest of a container image on the workflow, where the
+// renderers of the MCP containers and the image download step apply it
+func pinImageDigest(workflowData *WorkflowData, dep WorkflowDependency, digest string) {
+	if digest == "" || isExactVersion(dep.Ecosystem, dep.Requested) || strings.HasPrefix(dep.Reference, constants.DefaultFirewallRegistry+"/") {
+		return
+	}
+	depsLockLog.Printf("Pinning %s to %s", dep.Reference, digest)
+	if workflowData.ImagePins == nil {
+		workflowData.ImagePins = make(map[string]string)
+	}
+	workflowData.ImagePins[dep.Reference] = digest
+}
+
+// packageVersionSuffix returns the version part of a package reference as written
+// (e.g. "@latest" or ">=1.0"), empty when the reference has no version
+func packageVersionSuffix(dep WorkflowDependency) string {
+	if dep.Requested == "" {
+		return ""
+	}
+	if dep.Ecosystem == DepsEcosystemNpm || strings.Contains(dep.Reference, "@") {
+		return dep.Reference[strings.LastIndex(dep.Reference, "@"):]
+	}
+	return dep.Reference[len(dep.Reference)-len(dep.Requested):]
+}
+
+// shellSeparatorPattern matches the operators separating the commands of a shell line
+var shellSeparatorPattern = regexp.MustCompile(`&&|\|\||[;&|]`)
+
+// pinCommandArgument replaces a package reference with its pinned form in the commands
+// (separated by newlines or shell operators) that run one of the given commands
+func pinCommandArgument(commands string, commandNames []string, reference, pinned string) string {
+	if commands == "" {
+		return commands
+	}
+	referencePattern := regexp.MustCompile(`(^|\s)` + regexp.QuoteMeta(reference) + `(\s|$)`)
+	replacement := "${1}" + strings.ReplaceAll(pinned, "$", "$$") + "${2}"
+
+	var result strings.Builder
+	for i, line := range strings.Split(commands, "\n") {
+		if i > 0 {
+			result.WriteString("\n")
+		}
+		start := 0
+		for _, separator := range append(shellSeparatorPattern.FindAllStringIndex(line, -1), []int{len(line), len(line)}) {
+			command := line[start:separator[0]]
+			if slices.ContainsFunc(strings.Fields(command), func(word string) bool { return slices.Contains(commandNames, word) }) {
+				command = referencePattern.ReplaceAllString(command, replacement)
+			}
+			result.WriteString(command)
+			result.WriteString(line[separator[0]:separator[1]])
+			start = separator[1]
+		}
+	}
+	return result.String()
+}
+
+// resolveDependency resolves a dependency to an exact version or digest
+func resolveDependency(dep WorkflowDependency) (LockedDependency, error) {
+	entry := LockedDependency{Ecosystem: dep.Ecosystem, Name: dep.Name, Requested: dep.Requested, Engine: dep.Engine}
+	if dep.Ecosystem == DepsEcosystemDocker {
+		entry.Version, entry.Digest = imageTagAndDigest(dep.Requested)
+		if entry.Digest != "" {
+			return entry, nil
+		}
+		// Like ActionResolver.ResolveImageDigest: the local docker daemon first, then the registry
+		entry.Digest = resolveLocalImageDigest(dep.Reference)
+		if entry.Digest == "" {
+			digest, err := resolveRegistryDigest(dep.Reference)
+			if err != nil {
+				return entry, fmt.Errorf("no digest found for image %s: %w", dep.Reference, err)
+			}
+			entry.Digest = digest
+		}
+		depsLockLog.Printf("Resolved %s to %s", dep.Reference, entry.Digest)
+		return entry, nil
+	}
+	if isExactVersion(dep.Ecosystem, dep.Requested) {
+		entry.Version = strings.TrimPrefix(dep.Requested, "==")
+		return entry, nil
+	}
+
+	var err error
+	switch dep.Ecosystem {
+	case DepsEcosystemNpm:
+		entry.Version, err = resolveNpmVersion(dep.Name, dep.Requested)
+	case DepsEcosystemPyPI:
+		entry.Version, err = resolvePyPIVersion(dep.Name, dep.Requested)
+	}
+	if err != nil {
+		return entry, err
+	}
+	depsLockLog.Printf("Resolved %s to %s%s", dep.Reference, entry.Version, entry.Digest)
+	return entry, nil
+}
+
+// resolveNpmVersion returns the highest published version of an npm package matching
+// a version, range or dist-tag (default: latest)
+func resolveNpmVersion(name, requested string) (string, error) {
+	if _, err := exec.LookPath("npm"); err != nil {
+		return "", fmt.Errorf("npm is required to resolve npm package versions: %w", err)
+	}
+	spec := name
+	if requested != "" {
+		spec += "@" + requested
+	}
+	output, err := exec.Command("npm", "view", spec, "version", "--json").Output()
+	if err != nil {
+		return "", fmt.Errorf("npm view %s failed: %w", spec, err)
+	}
+	return parseNpmViewVersion(output)
+}
+
+// parseNpmViewVersion parses the output of `npm view <spec> version --json`, which is a
+// single version or, for ranges, the list of matching versions in ascending order
+func parseNpmViewVersion(output []byte) (string, error) {
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" {
+		return "", fmt.Errorf("no matching version found")
+	}
+	var version string
+	if err := json.Unmarshal([]byte(trimmed), &version); err == nil {
+		return version, nil
+	}
+	var versions []string
+	if err := json.Unmarshal([]byte(trimmed), &versions); err != nil {
+		return "", fmt.Errorf("unexpected npm view output: %s", trimmed)
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no matching version found")
+	}
+	return versions[len(versions)-1], nil
+}
+
+// resolvePyPIVersion returns the latest release of a Python package. Version ranges
+// cannot be resolved without a full resolver and must be pinned with == instead.
+func resolvePyPIVersion(name, requested string) (string, error) {
+	if requested != "" {
+		return "", fmt.Errorf("cannot lock version range '%s', pin the package with == instead", requested)
+	}
+	pip := "pip"
+	if _, err := exec.LookPath(pip); err != nil {
+		pip = "pip3"
+		if _, err := exec.LookPath(pip); err != nil {
+			return "", fmt.Errorf("pip is required to resolve Python package versions: %w", err)
+		}
+	}
+	output, err := exec.Command(pip, "index", "versions", name).Output()
+	if err != nil {
+		return "", fmt.Errorf("pip index versions %s failed: %w", name, err)
+	}
+	return parsePipIndexVersion(output)
+}
+
+// parsePipIndexVersion parses the latest version from the output of `pip index versions`
+// (first line: "<name> (<version>)")
+func parsePipIndexVersion(output []byte) (string, error) {
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	open := strings.LastIndex(firstLine, "(")
+	closing := strings.LastIndex(firstLine, ")")
+	if open < 0 || closing < open {
+		return "", fmt.Errorf("unexpected pip index output: %s", firstLine)
+	}
+	return firstLine[open+1 : closing], nil
+}
+
+// resolveLocalImageDigest returns the digest the local docker daemon records for an image,
+// or an empty string when the image has not been pulled
+func resolveLocalImageDigest(image string) string {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return ""
+	}
+	output, err := exec.Command("docker", "image", "inspect", "--format", "{{json .RepoDigests}}", image).Output()
+	if err != nil {
+		depsLockLog.Printf("No local digest for %s: %v", image, err)
+		return ""
+	}
+	var repoDigests []string
+	if err := json.Unmarshal(output, &repoDigests); err != nil {
+		return ""
+	}
+	name, _ := splitImageReference(image)
+	for _, repoDigest := range repoDigests {
+		if repository, digest, ok := strings.Cut(repoDigest, "@"); ok && repository == name {
+			return digest
+		}
+	}
+	return ""
+}
@@ -0,0 +1,250 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitPackageSpecs(t *testing.T) {
+	npmTests := []struct{ spec, name, requested string }{
+		{"cowsay", "cowsay", ""},
+		{"cowsay@^1", "cowsay", "^1"},
+		{"@playwright/mcp", "@playwright/mcp", ""},
+		{"@playwright/mcp@latest", "@playwright/mcp", "latest"},
+	}
+	for _, tt := range npmTests {
+		name, requested := splitNpmPackageSpec(tt.spec)
+		assert.Equal(t, tt.name, name, tt.spec)
+		assert.Equal(t, tt.requested, requested, tt.spec)
+	}
+
+	pythonTests := []struct{ spec, name, requested string }{
+		{"black", "black", ""},
+		{"Black==24.1.0", "black", "==24.1.0"},
+		{"requests>=2.0", "requests", ">=2.0"},
+		{"black[d]==24.1.0", "black", "==24.1.0"},
+		{"ruff@0.6.0", "ruff", "==0.6.0"},
+	}
+	for _, tt := range pythonTests {
+		name, requested := splitPythonPackageSpec(tt.spec)
+		assert.Equal(t, tt.name, name, tt.spec)
+		assert.Equal(t, tt.requested, requested, tt.spec)
+	}
+
+	imageTests := []struct{ image, name, tag string }{
+		{"node:lts-alpine", "node", "lts-alpine"},
+		{"mcr.microsoft.com/playwright/mcp", "mcr.microsoft.com/playwright/mcp", ""},
+		{"localhost:5000/tool", "localhost:5000/tool", ""},
+		{"ghcr.io/org/tool@sha256:abc", "ghcr.io/org/tool", "sha256:abc"},
+	}
+	for _, tt := range imageTests {
+		name, tag := splitImageReference(tt.image)
+		assert.Equal(t, tt.name, name, tt.image)
+		assert.Equal(t, tt.tag, tag, tt.image)
+	}
+}
+
+func TestIsExactVersion(t *testing.T) {
+	assert.True(t, isExactVersion(DepsEcosystemNpm, "1.2.3"))
+	assert.True(t, isExactVersion(DepsEcosystemNpm, "0.0.406-beta.1"))
+	assert.False(t, isExactVersion(DepsEcosystemNpm, "latest"))
+	assert.False(t, isExactVersion(DepsEcosystemNpm, "^1"))
+	assert.False(t, isExactVersion(DepsEcosystemNpm, ""))
+	assert.True(t, isExactVersion(DepsEcosystemPyPI, "==24.1.0"))
+	assert.False(t, isExactVersion(DepsEcosystemPyPI, "==24.*"))
+	assert.False(t, isExactVersion(DepsEcosystemPyPI, ">=2.0"))
+	assert.True(t, isExactVersion(DepsEcosystemDocker, "sha256:abc"))
+	assert.False(t, isExactVersion(DepsEcosystemDocker, "latest"))
+}
+
+func TestParseResolverOutput(t *testing.T) {
+	version, err := parseNpmViewVersion([]byte(`"1.6.0"`))
+	require.NoError(t, err)
+	assert.Equal(t, "1.6.0", version)
+
+	version, err = parseNpmViewVersion([]byte("[\n  \"1.5.0\",\n  \"1.6.0\"\n]\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.6.0", version, "the highest version of a range is locked")
+
+	_, err = parseNpmViewVersion([]byte(""))
+	require.Error(t, err)
+
+	version, err = parsePipIndexVersion([]byte("black (24.1.0)\nAvailable versions: 24.1.0, 23.12.1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "24.1.0", version)
+
+	_, err = resolvePyPIVersion("requests", ">=2.0")
+	require.ErrorContains(t, err, "pin the package with ==")
+}
+
+func TestPinCommandArgument(t *testing.T) {
+	commands := "pip install black\necho black\nnpx black; pip3 install black&& echo black\npip install blackjack"
+	got := pinCommandArgument(commands, []string{"pip", "pip3"}, "black", "black==24.1.0")
+	assert.Equal(t, "pip install black==24.1.0\necho black\nnpx black; pip3 install black==24.1.0&& echo black\npip install blackjack", got)
+}
+
+func TestDepsLockSaveLoadPrune(t *testing.T) {
+	dir := testutil.TempDir(t, "deps-lock-*")
+	lock := NewDepsLock(dir)
+	require.NoError(t, lock.Load())
+	assert.False(t, lock.Exists())
+
+	lock.Set(LockedDependency{Ecosystem: DepsEcosystemPyPI, Name: "black", Version: "24.1.0"})
+	lock.Set(LockedDependency{Ecosystem: DepsEcosystemNpm, Name: "cowsay", Requested: "^1", Version: "1.6.0"})
+	require.NoError(t, lock.Save())
+
+	data, err := os.ReadFile(filepath.Join(dir, ".github", "aw", DepsLockFileName))
+	require.NoError(t, err)
+	assert.Less(t, strings.Index(string(data), "cowsay"), strings.Index(string(data), "black"), "entries are sorted by ecosystem")
+
+	reloaded := NewDepsLock(dir)
+	require.NoError(t, reloaded.Load())
+	assert.True(t, reloaded.Exists())
+	entry, ok := reloaded.Lookup(WorkflowDependency{Ecosystem: DepsEcosystemNpm, Name: "cowsay", Requested: "^1"})
+	require.True(t, ok)
+	assert.Equal(t, "1.6.0", entry.Version)
+	_, ok = reloaded.Lookup(WorkflowDependency{Ecosystem: DepsEcosystemNpm, Name: "cowsay"})
+	assert.False(t, ok, "entries are keyed by the requested version")
+
+	// Only the entries set while compiling are kept
+	reloaded.Set(entry)
+	reloaded.Prune()
+	require.NoError(t, reloaded.Save())
+	require.NoError(t, reloaded.Load())
+	require.Len(t, reloaded.Dependencies, 1)
+	assert.Equal(t, "cowsay", reloaded.Dependencies[0].Name)
+}
+
+func TestDepsLockPinsGeneratedSteps(t *testing.T) {
+	dir := testutil.TempDir(t, "deps-lock-compile-*")
+	lockPath := filepath.Join(dir, ".github", "aw", DepsLockFileName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(lockPath), 0755))
+	require.NoError(t, os.WriteFile(lockPath, []byte(`{
+  "dependencies": [
+    {"ecosystem": "npm", "name": "@anthropic-ai/claude-code", "requested": "latest", "version": "2.1.50", "engine": "claude"},
+    {"ecosystem": "npm", "name": "cowsay", "requested": "^1", "version": "1.6.0"},
+    {"ecosystem": "npm", "name": "@modelcontextprotocol/server-everything", "version": "2025.1.1"},
+    {"ecosystem": "pypi", "name": "black", "version": "24.1.0"}
+  ]
+}
+`), 0644))
+
+	workflowPath := filepath.Join(dir, "deps.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(`---
+on: workflow_dispatch
+engine:
+  id: claude
+  version: latest
+permissions:
+  contents: read
+strict: false
+steps:
+  - name: Tools
+    run: |
+      pip install black
+      pip install ruff
+      npx cowsay@^1 hello
+mcp-servers:
+  everything:
+    command: npx
+    args: ["-y", "@modelcontextprotocol/server-everything"]
+    allowed: ["*"]
+---
+# Deps
+`), 0644))
+
+	compiler := NewCompiler(WithGitRoot(dir))
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+	content, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err)
+	lockContent := string(content)
+
+	assert.Contains(t, lockContent, "pip install black==24.1.0")
+	assert.Contains(t, lockContent, "pip install ruff\n", "dependencies missing from the lock are left as written")
+	assert.Contains(t, lockContent, "npx cowsay@1.6.0 hello")
+	assert.Contains(t, lockContent, `"@modelcontextprotocol/server-everything@2025.1.1"`)
+	assert.Contains(t, lockContent, "@anthropic-ai/claude-code@2.1.50")
+	assert.NotContains(t, lockContent, "claude-code@latest")
+
+	// Without --lock-deps the lock file is not modified
+	after, err := os.ReadFile(lockPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(after), "ruff")
+}
+
+func TestDepsLockPinsContainerImages(t *testing.T) {
+	dir := testutil.TempDir(t, "deps-lock-images-*")
+	version := string(constants.DefaultGitHubMCPServerVersion)
+	lockPath := filepath.Join(dir, ".github", "aw", DepsLockFileName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(lockPath), 0755))
+	require.NoError(t, os.WriteFile(lockPath, []byte(`{
+  "dependencies": [
+    {"ecosystem": "docker", "name": "ghcr.io/github/github-mcp-server", "requested": "`+version+`", "version": "`+version+`", "digest": "sha256:5555"}
+  ]
+}
+`), 0644))
+
+	workflowPath := filepath.Join(dir, "images.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(`---
+on: workflow_dispatch
+permissions:
+  contents: read
+  issues: read
+engine: copilot
+tools:
+  github:
+    mode: local
+    toolsets: [issues]
+---
+# Images
+`), 0644))
+
+	compiler := NewCompiler(WithGitRoot(dir))
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+	content, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err)
+	lockContent := string(content)
+
+	assert.Contains(t, lockContent, `"container": "ghcr.io/github/github-mcp-server@sha256:5555"`, "the MCP container should use the locked digest")
+	assert.Contains(t, lockContent, "# ghcr.io/github/github-mcp-server@sha256:5555 # "+version)
+	assert.NotContains(t, lockContent, "ghcr.io/github/github-mcp-server:"+version)
+	assert.Contains(t, lockContent, constants.DefaultFirewallRegistry+"/agent:", "firewall images are pulled by tag")
+}
+
+func TestLockedWorkflowDependencies(t *testing.T) {
+	compiler := NewCompiler(WithGitRoot(testutil.TempDir(t, "deps-lock-list-*")))
+	workflowData := &WorkflowData{
+		AI:          "copilot",
+		CustomSteps: "steps:\n  - run: pip install black==24.1.0 && npx cowsay\n",
+		Tools:       map[string]any{},
+	}
+	deps := compiler.LockedWorkflowDependencies(workflowData)
+	require.Len(t, deps, 3)
+	assert.Equal(t, LockedDependency{Ecosystem: DepsEcosystemNpm, Name: "@github/copilot", Requested: deps[0].Requested, Version: deps[0].Requested, Engine: "copilot"}, deps[0])
+	assert.Equal(t, LockedDependency{Ecosystem: DepsEcosystemNpm, Name: "cowsay"}, deps[1], "unlocked dependencies have no version")
+	assert.Equal(t, LockedDependency{Ecosystem: DepsEcosystemPyPI, Name: "black", Requested: "==24.1.0", Version: "24.1.0"}, deps[2])
+}
+
+func TestResolveDependencyImageDigest(t *testing.T) {
+	pinned := WorkflowDependency{Ecosystem: DepsEcosystemDocker, Name: "node", Requested: "sha256:abcd", Reference: "node@sha256:abcd"}
+	entry, err := resolveDependency(pinned)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:abcd", entry.Digest, "a digest in the reference is used as is")
+
+	// Neither the local docker daemon nor the registry knows the image
+	missing := WorkflowDependency{Ecosystem: DepsEcosystemDocker, Name: "127.0.0.1:1/missing/image", Requested: "v1", Reference: "127.0.0.1:1/missing/image:v1"}
+	entry, err = resolveDependency(missing)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no digest found for image 127.0.0.1:1/missing/image:v1")
+	assert.Empty(t, entry.Digest)
+}
@@ -168,16 +168,22 @@ func collectDockerImages(tools map[string]any, workflowData *WorkflowData, actio
 	return images
 }
 
-// generateDownloadDockerImagesStep generates the step to download Docker images
-func generateDownloadDockerImagesStep(yaml *strings.Builder, dockerImages []string) {
+// generateDownloadDockerImagesStep generates the step to download Docker images.
+// Images pinned to digests are downloaded by digest, with their tags listed in comments.
+func generateDownloadDockerImagesStep(yaml *strings.Builder, dockerImages []string, pins map[string]string) {
 	if len(dockerImages) == 0 {
 		return
 	}
 
 	yaml.WriteString("      - name: Download container images\n")
+	for _, image := range dockerImages {
+		if _, ok := pins[image]; ok {
+			fmt.Fprintf(yaml, "        # %s # %s\n", pinnedImage(image, pins), imageTag(image))
+		}
+	}
 	yaml.WriteString("        run: bash /opt/gh-aw/actions/download_docker_images.sh")
 	for _, image := range dockerImages {
-		fmt.Fprintf(yaml, " %s", image)
+		fmt.Fprintf(yaml, " %s", pinnedImage(image, pins))
 	}
 	yaml.WriteString("\n")
 }
@@ -0,0 +1,331 @@
+// This file provides loading and validation of declarative engine manifests.
+//
+// Engine manifests let a repository add an agentic engine without writing Go code.
+// Each manifest is a YAML file under .github/aw/engines/ that declares how to install
+// and run an agent CLI, where it expects its MCP configuration, which secrets it needs,
+// which capabilities it supports and how to extract metrics from its logs:
+//
+//	id: acme
+//	display-name: Acme Agent
+//	description: In-house coding agent
+//	version: 1.4.0
+//	secrets: [ACME_API_KEY]
+//	capabilities:
+//	  max-turns: true
+//	  http-transport: true
+//	install:
+//	  - name: Install Acme CLI
+//	    run: npm install -g @acme/agent@{{version}}
+//	execution:
+//	  command: acme run --prompt-file {{prompt_file}} --mcp-config {{mcp_config}} {{args}}
+//	mcp:
+//	  config-file: /home/runner/.acme/mcp.json
+//	  format: json
+//	logs:
+//	  turn: '^\[turn \d+\]'
+//	  token-usage: 'tokens used: (\d+)'
+//	  tool-call: 'calling tool (\S+)'
+//
+// Manifests are loaded at compile time and registered in the compiler's EngineRegistry
+// alongside the built-in engines, so `engine: acme` works like `engine: claude`.
+
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/goccy/go-yaml"
+)
+
+var engineManifestLog = logger.New("workflow:engine_manifest")
+
+// EngineManifestsDir is the directory, relative to the repository root, that holds engine manifests
+const EngineManifestsDir = ".github/aw/engines"
+
+// Supported MCP configuration schema flavours for manifest engines
+const (
+	// EngineManifestMCPFormatJSON renders a standard {"mcpServers": {...}} file with multi-line args (Claude style)
+	EngineManifestMCPFormatJSON = "json"
+	// EngineManifestMCPFormatCopilot renders the Copilot CLI flavour with "type" and "tools" fields and inline args
+	EngineManifestMCPFormatCopilot = "copilot"
+)
+
+var (
+	engineManifestIDPattern     = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	engineManifestSecretPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// EngineManifest is the declarative definition of an agentic engine
+type EngineManifest struct {
+	ID             string                     `yaml:"id"`
+	DisplayName    string                     `yaml:"display-name"`
+	Description    string                     `yaml:"description"`
+	Experimental   bool                       `yaml:"experimental"`
+	Version        string                     `yaml:"version"`         // Default version substituted for {{version}} in install steps
+	DocsURL        string                     `yaml:"docs-url"`        // Shown when secret validation fails
+	Secrets        []string                   `yaml:"secrets"`         // Secrets passed to the execution step (at least one must be set)
+	AllowedDomains []string                   `yaml:"allowed-domains"` // Domains the agent needs when the firewall is enabled
+	Capabilities   EngineManifestCapabilities `yaml:"capabilities"`
+	Install        []map[string]any           `yaml:"install"` // GitHub Actions steps run before the agent
+	Execution      EngineManifestExecution    `yaml:"execution"`
+	MCP            EngineManifestMCP          `yaml:"mcp"`
+	Logs           EngineManifestLogs         `yaml:"logs"`
+
+	// Path is the file the manifest was loaded from
+	Path string `yaml:"-"`
+}
+
+// EngineManifestCapabilities mirrors the CapabilityProvider interface
+type EngineManifestCapabilities struct {
+	ToolsAllowlist bool `yaml:"tools-allowlist"`
+	HTTPTransport  bool `yaml:"http-transport"`
+	MaxTurns       bool `yaml:"max-turns"`
+	WebFetch       bool `yaml:"web-fetch"`
+	WebSearch      bool `yaml:"web-search"`
+	Firewall       bool `yaml:"firewall"`
+	Plugins        bool `yaml:"plugins"`
+}
+
+// EngineManifestExecution describes the agent execution step.
+//
+// The command template supports the following placeholders:
+//   - {{prompt_file}}: path of the rendered prompt file
+//   - {{mcp_config}}: path of the MCP configuration file
+//   - {{log_file}}: path of the agent log file
+//   - {{model}}: configured model (shell-quoted, empty when not configured)
+//   - {{max_turns}}: configured max-turns (empty when not configured)
+//   - {{args}}: engine.args from the workflow (shell-quoted)
+type EngineManifestExecution struct {
+	Command     string            `yaml:"command"`
+	StepName    string            `yaml:"step-name"`
+	Env         map[string]string `yaml:"env"`
+	OutputFiles []string          `yaml:"output-files"`
+}
+
+// EngineManifestMCP describes where and in which flavour the MCP configuration is written
+type EngineManifestMCP struct {
+	ConfigFile string `yaml:"config-file"`
+	Format     string `yaml:"format"`
+}
+
+// EngineManifestLogs holds the regular expressions used to extract LogMetrics from agent logs.
+// Each expression is matched against every log line:
+//   - turn: every matching line counts as one turn
+//   - token-usage: the first capture group is added to the token usage
+//   - cost: the first capture group is added to the estimated cost
+//   - tool-call: the first capture group is the name of the called tool
+type EngineManifestLogs struct {
+	File       string `yaml:"file"`
+	Turn       string `yaml:"turn"`
+	TokenUsage string `yaml:"token-usage"`
+	Cost       string `yaml:"cost"`
+	ToolCall   string `yaml:"tool-call"`
+}
+
+// ParseEngineManifest parses and validates an engine manifest
+func ParseEngineManifest(content []byte, path string) (*EngineManifest, error) {
+	var manifest EngineManifest
+	if err := yaml.UnmarshalWithOptions(content, &manifest, yaml.DisallowUnknownField()); err != nil {
+		return nil, fmt.Errorf("invalid engine manifest %s: %w", path, err)
+	}
+	manifest.Path = path
+	if err := manifest.validate(); err != nil {
+		return nil, fmt.Errorf("invalid engine manifest %s: %w", path, err)
+	}
+	engineManifestLog.Printf("Parsed engine manifest: id=%s, path=%s", manifest.ID, path)
+	return &manifest, nil
+}
+
+// validate checks the manifest for required fields and well-formed values
+func (m *EngineManifest) validate() error {
+	if m.ID == "" {
+		return fmt.Errorf("'id' is required")
+	}
+	if !engineManifestIDPattern.MatchString(m.ID) {
+		return fmt.Errorf("'id' must contain only lowercase letters, digits and hyphens and start with a letter, got '%s'", m.ID)
+	}
+	if NewEngineRegistry().IsValidEngine(m.ID) {
+		return fmt.Errorf("'id' %s conflicts with a built-in engine", m.ID)
+	}
+	if strings.TrimSpace(m.Execution.Command) == "" {
+		return fmt.Errorf("'execution.command' is required")
+	}
+	for _, secret := range m.Secrets {
+		if !engineManifestSecretPattern.MatchString(secret) {
+			return fmt.Errorf("invalid secret name '%s'", secret)
+		}
+	}
+	switch m.MCP.Format {
+	case "", EngineManifestMCPFormatJSON, EngineManifestMCPFormatCopilot:
+	default:
+		return fmt.Errorf("'mcp.format' must be '%s' or '%s', got '%s'", EngineManifestMCPFormatJSON, EngineManifestMCPFormatCopilot, m.MCP.Format)
+	}
+	if m.MCP.ConfigFile != "" && !filepath.IsAbs(m.MCP.ConfigFile) {
+		return fmt.Errorf("'mcp.config-file' must be an absolute path, got '%s'", m.MCP.ConfigFile)
+	}
+	for i, step := range m.Install {
+		_, hasRun := step["run"]
+		_, hasUses := step["uses"]
+		if !hasRun && !hasUses {
+			return fmt.Errorf("install step %d must have 'run' or 'uses'", i+1)
+		}
+	}
+
+	patterns := []struct {
+		field        string
+		pattern      string
+		needsCapture bool
+	}{
+		{"logs.turn", m.Logs.Turn, false},
+		{"logs.token-usage", m.Logs.TokenUsage, true},
+		{"logs.cost", m.Logs.Cost, true},
+		{"logs.tool-call", m.Logs.ToolCall, true},
+	}
+	for _, p := range patterns {
+		if p.pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(p.pattern)
+		if err != nil {
+			return fmt.Errorf("'%s' is not a valid regular expression: %w", p.field, err)
+		}
+		if p.needsCapture && re.NumSubexp() < 1 {
+			return fmt.Errorf("'%s' must have a capture group", p.field)
+		}
+	}
+	return nil
+}
+
+// LoadEngineManifests loads all engine manifests (*.yml, *.yaml) from a directory.
+// A missing directory is not an error and yields no manifests.
+func LoadEngineManifests(dir string) ([]*EngineManifest, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read engine manifests directory %s: %w", dir, err)
+	}
+
+	var manifests []*EngineManifest
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yml" && ext != ".yaml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read engine manifest %s: %w", path, err)
+		}
+		manifest, err := ParseEngineManifest(content, path)
+		if err != nil {
+			return nil, err
+		}
+		if previous, exists := seen[manifest.ID]; exists {
+			return nil, fmt.Errorf("engine '%s' is defined in both %s and %s", manifest.ID, previous, path)
+		}
+		seen[manifest.ID] = path
+		manifests = append(manifests, manifest)
+	}
+
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].ID < manifests[j].ID })
+	engineManifestLog.Printf("Loaded %d engine manifests from %s", len(manifests), dir)
+	return manifests, nil
+}
+
+// NewEngineRegistryWithManifests creates a registry with the built-in engines and
+// the engines declared by the manifests in dir
+func NewEngineRegistryWithManifests(dir string) (*EngineRegistry, error) {
+	manifests, err := LoadEngineManifests(dir)
+	if err != nil {
+		return nil, err
+	}
+	return GetGlobalEngineRegistry().withManifests(manifests), nil
+}
+
+// GetRepositoryEngineRegistry returns the engine registry for the current repository:
+// the built-in engines plus the repository's engine manifests. Manifest errors are
+// logged and the built-in registry is returned, so commands that only need to look up
+// engines keep working.
+func GetRepositoryEngineRegistry() *EngineRegistry {
+	gitRoot := findGitRoot()
+	if gitRoot == "" {
+		return GetGlobalEngineRegistry()
+	}
+	registry, err := NewEngineRegistryWithManifests(filepath.Join(gitRoot, EngineManifestsDir))
+	if err != nil {
+		engineManifestLog.Printf("Ignoring engine manifests: %v", err)
+		return GetGlobalEngineRegistry()
+	}
+	return registry
+}
+
+// withManifests returns a copy of the registry with manifest engines added.
+// The receiver is left untouched so the global registry is never modified.
+func (r *EngineRegistry) withManifests(manifests []*EngineManifest) *EngineRegistry {
+	registry := &EngineRegistry{
+		engines: make(map[string]CodingAgentEngine, len(r.engines)+len(manifests)),
+	}
+	for id, engine := range r.engines {
+		registry.engines[id] = engine
+	}
+	for _, manifest := range manifests {
+		registry.Register(NewManifestEngine(manifest))
+	}
+	return registry
+}
+
+// engineManifestsDirForWorkflow returns the engine manifests directory for a workflow file:
+// the aw/engines directory next to the nearest enclosing .github directory, falling back
+// to the repository root
+func engineManifestsDirForWorkflow(markdownPath, gitRoot string) string {
+	dir := filepath.Dir(markdownPath)
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	for {
+		if filepath.Base(dir) == ".github" {
+			return filepath.Join(dir, "aw", "engines")
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	if gitRoot != "" {
+		return filepath.Join(gitRoot, EngineManifestsDir)
+	}
+	return ""
+}
+
+// loadEngineManifests registers the repository's engine manifests in the compiler's
+// engine registry. Manifests are loaded once per directory.
+func (c *Compiler) loadEngineManifests(markdownPath string) error {
+	dir := engineManifestsDirForWorkflow(markdownPath, c.gitRoot)
+	if dir == "" || dir == c.engineManifestsDir {
+		return nil
+	}
+
+	manifests, err := LoadEngineManifests(dir)
+	if err != nil {
+		return err
+	}
+	c.engineManifestsDir = dir
+	if len(manifests) == 0 {
+		return nil
+	}
+	for _, manifest := range manifests {
+		engineManifestLog.Printf("Registering manifest engine %s from %s", manifest.ID, manifest.Path)
+	}
+	c.engineRegistry = GetGlobalEngineRegistry().withManifests(manifests)
+	return nil
+}
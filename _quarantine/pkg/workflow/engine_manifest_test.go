@@ -0,0 +1,284 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEngineManifest = `id: acme
+display-name: Acme Agent
+version: 1.4.0
+docs-url: https://example.com/acme
+secrets: [ACME_API_KEY]
+capabilities:
+  max-turns: true
+  http-transport: true
+install:
+  - name: Install Acme CLI
+    run: npm install -g @acme/agent@{{version}}
+execution:
+  command: acme run --prompt-file {{prompt_file}} --mcp-config {{mcp_config}} {{model}} {{args}}
+  env:
+    ACME_TELEMETRY: "off"
+mcp:
+  config-file: /home/runner/.acme/mcp.json
+  format: copilot
+logs:
+  turn: '^\[turn \d+\]'
+  token-usage: 'tokens used: ([\d,]+)'
+  cost: 'cost: \$([\d.]+)'
+  tool-call: 'calling tool (\S+)'
+`
+
+func TestParseEngineManifest(t *testing.T) {
+	manifest, err := ParseEngineManifest([]byte(testEngineManifest), "acme.yml")
+	require.NoError(t, err, "manifest should parse")
+	assert.Equal(t, "acme", manifest.ID)
+	assert.Equal(t, "Acme Agent", manifest.DisplayName)
+	assert.Equal(t, []string{"ACME_API_KEY"}, manifest.Secrets)
+	assert.True(t, manifest.Capabilities.MaxTurns)
+	assert.Equal(t, EngineManifestMCPFormatCopilot, manifest.MCP.Format)
+	assert.Len(t, manifest.Install, 1)
+}
+
+func TestParseEngineManifestErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		manifest    string
+		errContains string
+	}{
+		{name: "missing id", manifest: "execution:\n  command: run\n", errContains: "'id' is required"},
+		{name: "invalid id", manifest: "id: Acme\nexecution:\n  command: run\n", errContains: "lowercase"},
+		{name: "built-in id", manifest: "id: claude\nexecution:\n  command: run\n", errContains: "built-in engine"},
+		{name: "missing command", manifest: "id: acme\n", errContains: "'execution.command' is required"},
+		{name: "unknown field", manifest: "id: acme\nexecution:\n  command: run\nbogus: true\n", errContains: "bogus"},
+		{name: "invalid mcp format", manifest: "id: acme\nexecution:\n  command: run\nmcp:\n  format: toml\n", errContains: "'mcp.format'"},
+		{name: "relative mcp config file", manifest: "id: acme\nexecution:\n  command: run\nmcp:\n  config-file: mcp.json\n", errContains: "absolute path"},
+		{name: "invalid secret", manifest: "id: acme\nsecrets: [\"bad-name\"]\nexecution:\n  command: run\n", errContains: "invalid secret name"},
+		{name: "install step without run or uses", manifest: "id: acme\ninstall:\n  - name: nothing\nexecution:\n  command: run\n", errContains: "install step 1"},
+		{name: "invalid regex", manifest: "id: acme\nexecution:\n  command: run\nlogs:\n  turn: '(['\n", errContains: "'logs.turn'"},
+		{name: "missing capture group", manifest: "id: acme\nexecution:\n  command: run\nlogs:\n  token-usage: 'tokens \\d+'\n", errContains: "capture group"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseEngineManifest([]byte(tt.manifest), "test.yml")
+			require.Error(t, err, "manifest should be rejected")
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestLoadEngineManifests(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "test-*")
+
+	manifests, err := LoadEngineManifests(filepath.Join(tmpDir, "missing"))
+	require.NoError(t, err, "missing directory should not be an error")
+	assert.Empty(t, manifests)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "acme.yml"), []byte(testEngineManifest), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("not a manifest"), 0644))
+	manifests, err = LoadEngineManifests(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, manifests, 1, "only YAML files should be loaded")
+	assert.Equal(t, "acme", manifests[0].ID)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "acme-copy.yaml"), []byte(testEngineManifest), 0644))
+	_, err = LoadEngineManifests(tmpDir)
+	require.Error(t, err, "duplicate ids should be rejected")
+	assert.Contains(t, err.Error(), "defined in both")
+}
+
+func TestEngineRegistryWithManifests(t *testing.T) {
+	manifest, err := ParseEngineManifest([]byte(testEngineManifest), "acme.yml")
+	require.NoError(t, err)
+
+	registry := GetGlobalEngineRegistry().withManifests([]*EngineManifest{manifest})
+	assert.True(t, registry.IsValidEngine("acme"), "manifest engine should be registered")
+	assert.True(t, registry.IsValidEngine("claude"), "built-in engines should remain registered")
+	assert.False(t, GetGlobalEngineRegistry().IsValidEngine("acme"), "global registry should not be modified")
+
+	engine, err := registry.GetEngine("acme")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Agent", engine.GetDisplayName())
+	assert.True(t, engine.SupportsMaxTurns())
+	assert.True(t, engine.SupportsHTTPTransport())
+	assert.False(t, engine.SupportsFirewall())
+}
+
+func TestEngineRegistrySuggestsClosestEngine(t *testing.T) {
+	manifest, err := ParseEngineManifest([]byte(testEngineManifest), "acme.yml")
+	require.NoError(t, err)
+	registry := GetGlobalEngineRegistry().withManifests([]*EngineManifest{manifest})
+
+	_, err = registry.GetEngine("acmee")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Did you mean: acme?", "manifest engines should be suggested")
+
+	_, err = registry.GetEngine("copiolt")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Did you mean: copilot?", "built-in engines should be suggested")
+
+	_, err = registry.GetEngineByPrefix("cladue")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Did you mean: claude?")
+
+	_, err = registry.GetEngine("something-else-entirely")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "Did you mean")
+}
+
+func TestManifestEngineSteps(t *testing.T) {
+	manifest, err := ParseEngineManifest([]byte(testEngineManifest), "acme.yml")
+	require.NoError(t, err)
+	engine := NewManifestEngine(manifest)
+
+	workflowData := &WorkflowData{
+		Name: "test",
+		EngineConfig: &EngineConfig{
+			ID:      "acme",
+			Version: "2.0.0",
+			Model:   "acme-large",
+			Args:    []string{"--verbose"},
+		},
+	}
+
+	var installYAML strings.Builder
+	for _, step := range engine.GetInstallationSteps(workflowData) {
+		installYAML.WriteString(strings.Join(step, "\n") + "\n")
+	}
+	assert.Contains(t, installYAML.String(), "ACME_API_KEY", "secret validation step should check the manifest secret")
+	assert.Contains(t, installYAML.String(), "npm install -g @acme/agent@2.0.0", "engine.version should override the manifest version")
+
+	steps := engine.GetExecutionSteps(workflowData, "/tmp/gh-aw/agent-stdio.log")
+	require.Len(t, steps, 1)
+	execution := strings.Join(steps[0], "\n")
+	assert.Contains(t, execution, "name: Execute Acme Agent")
+	assert.Contains(t, execution, "id: agentic_execution")
+	assert.Contains(t, execution, "acme run --prompt-file /tmp/gh-aw/aw-prompts/prompt.txt --mcp-config /home/runner/.acme/mcp.json acme-large --verbose 2>&1 | tee -a /tmp/gh-aw/agent-stdio.log")
+	assert.Contains(t, execution, "ACME_API_KEY: ${{ secrets.ACME_API_KEY }}")
+	assert.Contains(t, execution, "ACME_TELEMETRY: off")
+	assert.Contains(t, execution, "GH_AW_MODEL: acme-large")
+}
+
+func TestManifestEngineRenderCommandKeepsQuotedWhitespace(t *testing.T) {
+	manifest, err := ParseEngineManifest([]byte(strings.Replace(testEngineManifest,
+		"command: acme run --prompt-file {{prompt_file}} --mcp-config {{mcp_config}} {{model}} {{args}}",
+		`command: acme run --banner "two  spaces" {{model}} {{args}} --prompt-file {{prompt_file}}`, 1)), "acme.yml")
+	require.NoError(t, err)
+	engine := NewManifestEngine(manifest)
+
+	command := engine.renderCommand(&WorkflowData{Name: "test"}, "/tmp/gh-aw/agent-stdio.log")
+	assert.Equal(t, `acme run --banner "two  spaces" --prompt-file /tmp/gh-aw/aw-prompts/prompt.txt`, command,
+		"empty placeholders should be dropped without touching quoted arguments")
+}
+
+func TestManifestEngineRenderCommandSubstitutesSpacedPlaceholders(t *testing.T) {
+	manifest, err := ParseEngineManifest([]byte(strings.Replace(testEngineManifest,
+		"command: acme run --prompt-file {{prompt_file}} --mcp-config {{mcp_config}} {{model}} {{args}}",
+		"command: acme run --model {{ model }} --max-turns {{max_turns }} --prompt-file {{  prompt_file}}", 1)), "acme.yml")
+	require.NoError(t, err)
+	engine := NewManifestEngine(manifest)
+
+	command := engine.renderCommand(&WorkflowData{
+		Name:         "test",
+		EngineConfig: &EngineConfig{ID: "acme", Model: "acme large; rm -rf /", MaxTurns: "$(id)"},
+	}, "/tmp/gh-aw/agent-stdio.log")
+	assert.Equal(t, `acme run --model 'acme large; rm -rf /' --max-turns '$(id)' --prompt-file /tmp/gh-aw/aw-prompts/prompt.txt`, command,
+		"placeholders with whitespace should be substituted and values shell-quoted")
+}
+
+func TestSubstituteInstallStepPlaceholders(t *testing.T) {
+	step := map[string]any{
+		"name": "Install Acme CLI {{ version }}",
+		"uses": "acme/setup-agent@{{version}}",
+		"with": map[string]any{"version": "{{version}}"},
+		"run":  "npm install -g @acme/agent@{{ version }}",
+	}
+
+	result := substituteInstallStepPlaceholders(step, map[string]string{"version": "1.0 && curl example.com"}).(map[string]any)
+	assert.Equal(t, "npm install -g @acme/agent@'1.0 && curl example.com'", result["run"], "run scripts should get shell-quoted values")
+	assert.Equal(t, "acme/setup-agent@1.0 && curl example.com", result["uses"], "non-shell fields should get the value as is")
+	assert.Equal(t, map[string]any{"version": "1.0 && curl example.com"}, result["with"])
+	assert.Equal(t, "Install Acme CLI 1.0 && curl example.com", result["name"])
+
+	result = substituteInstallStepPlaceholders(step, map[string]string{"version": "1.4.0"}).(map[string]any)
+	assert.Equal(t, "npm install -g @acme/agent@1.4.0", result["run"], "plain versions should not be quoted")
+}
+
+func TestManifestEngineParseLogMetrics(t *testing.T) {
+	manifest, err := ParseEngineManifest([]byte(testEngineManifest), "acme.yml")
+	require.NoError(t, err)
+	engine := NewManifestEngine(manifest)
+
+	logContent := `[turn 1]
+calling tool github_search
+calling tool bash
+tokens used: 1,200
+[turn 2]
+calling tool bash
+tokens used: 300
+cost: $0.25
+`
+	metrics := engine.ParseLogMetrics(logContent, false)
+	assert.Equal(t, 2, metrics.Turns)
+	assert.Equal(t, 1500, metrics.TokenUsage)
+	assert.InDelta(t, 0.25, metrics.EstimatedCost, 0.0001)
+	require.Len(t, metrics.ToolCalls, 2)
+	assert.Equal(t, "bash", metrics.ToolCalls[0].Name)
+	assert.Equal(t, 2, metrics.ToolCalls[0].CallCount)
+	assert.Equal(t, [][]string{{"github_search", "bash"}, {"bash"}}, metrics.ToolSequences)
+}
+
+func TestCompileWorkflowWithManifestEngine(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "test-*")
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	enginesDir := filepath.Join(tmpDir, ".github", "aw", "engines")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	require.NoError(t, os.MkdirAll(enginesDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(enginesDir, "acme.yml"), []byte(testEngineManifest), 0644))
+
+	markdown := `---
+on:
+  issues:
+    types: [opened]
+permissions:
+  contents: read
+engine: acme
+tools:
+  github:
+    toolsets: [issues]
+---
+
+# Acme
+
+Summarize the issue.
+`
+	markdownPath := filepath.Join(workflowsDir, "acme.md")
+	require.NoError(t, os.WriteFile(markdownPath, []byte(markdown), 0644))
+
+	compiler := NewCompiler(WithNoEmit(true))
+	compiler.SetQuiet(true)
+	_, lockContent, err := compiler.CompileToYAML(markdownPath)
+	require.NoError(t, err, "workflow using a manifest engine should compile")
+
+	assert.Contains(t, lockContent, "npm install -g @acme/agent@1.4.0")
+	assert.Contains(t, lockContent, "name: Execute Acme Agent")
+	assert.Contains(t, lockContent, "/home/runner/.acme/mcp.json", "MCP config should be written to the manifest path")
+	assert.Contains(t, lockContent, "ACME_API_KEY: ${{ secrets.ACME_API_KEY }}")
+	assert.False(t, GetGlobalEngineRegistry().IsValidEngine("acme"), "compiling should not modify the global registry")
+
+	// Without the manifest the engine is unknown
+	require.NoError(t, os.Remove(filepath.Join(enginesDir, "acme.yml")))
+	_, _, err = NewCompiler(WithNoEmit(true)).CompileToYAML(markdownPath)
+	require.Error(t, err, "unknown engine should fail to compile")
+	assert.Contains(t, err.Error(), "invalid engine")
+}
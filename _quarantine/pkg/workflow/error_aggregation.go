@@ -136,7 +136,22 @@ func (c *ErrorCollector) FormattedError(category string) error {
 		sb.WriteString(err.Error())
 	}
 
-	return fmt.Errorf("%s", sb.String())
+	return &formattedErrors{message: sb.String(), errs: c.errors}
+}
+
+// formattedErrors is an aggregated error with a formatted message that keeps the
+// collected errors reachable through errors.Is, errors.As and SourceLocations
+type formattedErrors struct {
+	message string
+	errs    []error
+}
+
+func (e *formattedErrors) Error() string {
+	return e.message
+}
+
+func (e *formattedErrors) Unwrap() []error {
+	return e.errs
 }
 
 // FormatAggregatedError formats aggregated errors with a summary header
@@ -0,0 +1,868 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var expressionEvaluatorLog = logger.New("workflow:expression_evaluator")
+
+// EvalContext holds the runtime contexts available to a GitHub Actions expression.
+// Each context is a plain JSON-like value (maps, slices, strings, numbers, booleans, nil).
+type EvalContext struct {
+	GitHub  map[string]any // github context (event_name, actor, event payload, ...)
+	Needs   map[string]any // needs context keyed by job ID ({result, outputs})
+	Inputs  map[string]any // inputs context (workflow_dispatch / workflow_call)
+	Env     map[string]any // env context
+	Steps   map[string]any // steps context keyed by step ID ({outcome, conclusion, outputs})
+	Vars    map[string]any // vars context (repository/organization variables)
+	Secrets map[string]any // secrets context
+
+	// JobStatus is the status reported by the success(), failure() and cancelled()
+	// status check functions: "success" (default when empty), "failure" or "cancelled".
+	// Any other value (e.g. "skipped") makes all three functions return false.
+	JobStatus string
+
+	// Partial enables static evaluation: properties missing from the contexts and the
+	// success(), failure() and cancelled() functions evaluate to an unknown value
+	// instead of null or the job status.
+	Partial bool
+}
+
+// lookup returns the named top-level context
+func (ctx EvalContext) lookup(name string) (any, bool) {
+	switch strings.ToLower(name) {
+	case "github":
+		return ctx.GitHub, true
+	case "needs":
+		return ctx.Needs, true
+	case "inputs":
+		return ctx.Inputs, true
+	case "env":
+		return ctx.Env, true
+	case "steps":
+		return ctx.Steps, true
+	case "vars":
+		return ctx.Vars, true
+	case "secrets":
+		return ctx.Secrets, true
+	}
+	return nil, false
+}
+
+// jobStatus returns the effective job status, defaulting to success
+func (ctx EvalContext) jobStatus() string {
+	if ctx.JobStatus == "" {
+		return "success"
+	}
+	return ctx.JobStatus
+}
+
+// EvaluateJobCondition evaluates a job or step level `if:` condition the way the
+// GitHub Actions runner does. The condition may be wrapped in ${{ }}. An empty
+// condition behaves like success(), and conditions that do not call any status
+// check function are implicitly combined with success().
+func EvaluateJobCondition(condition string, ctx EvalContext) (bool, error) {
+	expr := stripExpressionWrapper(condition)
+	if expr == "" {
+		expr = "success()"
+	}
+	expressionEvaluatorLog.Printf("Evaluating job condition: %s", expr)
+
+	node, err := parseOperandExpression(expr)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse condition %q: %w", expr, err)
+	}
+	if !conditionUsesStatusFunction(node) {
+		node = &AndNode{Left: &FunctionCallNode{FunctionName: "success"}, Right: node}
+	}
+	return EvaluateCondition(node, ctx)
+}
+
+// EvaluateCondition evaluates a condition tree and coerces the result to a boolean
+// using GitHub Actions truthiness rules.
+func EvaluateCondition(node ConditionNode, ctx EvalContext) (bool, error) {
+	if node == nil {
+		return false, fmt.Errorf("cannot evaluate empty expression")
+	}
+	value, err := node.Evaluate(ctx)
+	if err != nil {
+		return false, err
+	}
+	return isTruthy(value), nil
+}
+
+// EvaluateExpressionString evaluates an expression tree and converts the result to
+// the string the runner would substitute for a ${{ }} template.
+func EvaluateExpressionString(node ConditionNode, ctx EvalContext) (string, error) {
+	if node == nil {
+		return "", fmt.Errorf("cannot evaluate empty expression")
+	}
+	value, err := node.Evaluate(ctx)
+	if err != nil {
+		return "", err
+	}
+	return valueToString(value), nil
+}
+
+// EvaluateConditionStatically evaluates a condition with a partial context, where
+// anything not present in the context is treated as unknown. known is false when
+// the result depends on unknown values; otherwise result is the condition's value
+// for every possible value of the unknowns.
+func EvaluateConditionStatically(condition string, ctx EvalContext) (result bool, known bool, err error) {
+	expr := stripExpressionWrapper(condition)
+	if expr == "" {
+		return false, false, nil
+	}
+	node, err := parseOperandExpression(expr)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to parse condition %q: %w", expr, err)
+	}
+	ctx.Partial = true
+	value, err := node.Evaluate(ctx)
+	if err != nil {
+		return false, false, err
+	}
+	if isUnknown(value) {
+		return false, false, nil
+	}
+	return isTruthy(value), true, nil
+}
+
+// conditionUsesStatusFunction reports whether the condition calls success(),
+// failure(), always() or cancelled() anywhere in its tree
+func conditionUsesStatusFunction(node ConditionNode) bool {
+	switch n := node.(type) {
+	case *ExpressionNode:
+		parsed, err := parseOperandExpression(n.Expression)
+		return err == nil && conditionUsesStatusFunction(parsed)
+	case *FunctionCallNode:
+		if isStatusFunction(n.FunctionName) {
+			return true
+		}
+		for _, arg := range n.Arguments {
+			if conditionUsesStatusFunction(arg) {
+				return true
+			}
+		}
+	case *AndNode:
+		return conditionUsesStatusFunction(n.Left) || conditionUsesStatusFunction(n.Right)
+	case *OrNode:
+		return conditionUsesStatusFunction(n.Left) || conditionUsesStatusFunction(n.Right)
+	case *NotNode:
+		return conditionUsesStatusFunction(n.Child)
+	case *ParenthesesNode:
+		return conditionUsesStatusFunction(n.Child)
+	case *ComparisonNode:
+		return conditionUsesStatusFunction(n.Left) || conditionUsesStatusFunction(n.Right)
+	case *TernaryNode:
+		return conditionUsesStatusFunction(n.Condition) ||
+			conditionUsesStatusFunction(n.TrueValue) || conditionUsesStatusFunction(n.FalseValue)
+	case *ContainsNode:
+		return conditionUsesStatusFunction(n.Array) || conditionUsesStatusFunction(n.Value)
+	case *MemberAccessNode:
+		return conditionUsesStatusFunction(n.Target)
+	case *DisjunctionNode:
+		for _, term := range n.Terms {
+			if conditionUsesStatusFunction(term) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isStatusFunction(name string) bool {
+	switch strings.ToLower(name) {
+	case "success", "failure", "always", "cancelled":
+		return true
+	}
+	return false
+}
+
+// unknownValue is the value of an expression that cannot be determined during
+// partial evaluation (see EvalContext.Partial). It propagates through operators
+// and function calls unless the result is decided by the known operands.
+type unknownValue struct{}
+
+func isUnknown(value any) bool {
+	_, ok := value.(unknownValue)
+	return ok
+}
+
+// Evaluate parses the raw expression text and evaluates it
+func (e *ExpressionNode) Evaluate(ctx EvalContext) (any, error) {
+	parsed, err := parseOperandExpression(e.Expression)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Evaluate(ctx)
+}
+
+// Evaluate returns the left operand when it is falsy, otherwise the right operand
+func (a *AndNode) Evaluate(ctx EvalContext) (any, error) {
+	left, err := a.Left.Evaluate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if isUnknown(left) {
+		right, err := a.Right.Evaluate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !isUnknown(right) && !isTruthy(right) {
+			return right, nil
+		}
+		return unknownValue{}, nil
+	}
+	if !isTruthy(left) {
+		return left, nil
+	}
+	return a.Right.Evaluate(ctx)
+}
+
+// Evaluate returns the left operand when it is truthy, otherwise the right operand
+func (o *OrNode) Evaluate(ctx EvalContext) (any, error) {
+	left, err := o.Left.Evaluate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if isUnknown(left) {
+		right, err := o.Right.Evaluate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !isUnknown(right) && isTruthy(right) {
+			return right, nil
+		}
+		return unknownValue{}, nil
+	}
+	if isTruthy(left) {
+		return left, nil
+	}
+	return o.Right.Evaluate(ctx)
+}
+
+// Evaluate returns the boolean negation of the child's truthiness
+func (n *NotNode) Evaluate(ctx EvalContext) (any, error) {
+	value, err := n.Child.Evaluate(ctx)
+	if err != nil || isUnknown(value) {
+		return value, err
+	}
+	return !isTruthy(value), nil
+}
+
+// Evaluate returns the value of the wrapped expression
+func (p *ParenthesesNode) Evaluate(ctx EvalContext) (any, error) {
+	return p.Child.Evaluate(ctx)
+}
+
+// Evaluate returns the first truthy term, or the last term when none is truthy
+func (d *DisjunctionNode) Evaluate(ctx EvalContext) (any, error) {
+	var result any = false
+	unknown := false
+	for _, term := range d.Terms {
+		value, err := term.Evaluate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if isUnknown(value) {
+			unknown = true
+			continue
+		}
+		if isTruthy(value) {
+			return value, nil
+		}
+		result = value
+	}
+	if unknown {
+		return unknownValue{}, nil
+	}
+	return result, nil
+}
+
+// Evaluate calls the built-in function with the evaluated arguments
+func (f *FunctionCallNode) Evaluate(ctx EvalContext) (any, error) {
+	return evaluateFunctionCall(f, ctx)
+}
+
+// Evaluate resolves the property path against the evaluation context
+func (p *PropertyAccessNode) Evaluate(ctx EvalContext) (any, error) {
+	segments, err := splitPropertyPath(p.PropertyPath)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := ctx.lookup(segments[0])
+	if !ok {
+		if ctx.Partial {
+			return unknownValue{}, nil
+		}
+		return nil, fmt.Errorf("unknown context '%s' in '%s'", segments[0], p.PropertyPath)
+	}
+	return walkPropertySegments(root, segments[1:], ctx.Partial), nil
+}
+
+// Evaluate resolves the property path against the value of the target expression
+func (m *MemberAccessNode) Evaluate(ctx EvalContext) (any, error) {
+	target, err := m.Target.Evaluate(ctx)
+	if err != nil || isUnknown(target) {
+		return target, err
+	}
+	segments, err := splitPropertyPath(m.PropertyPath)
+	if err != nil {
+		return nil, err
+	}
+	return walkPropertySegments(target, segments, ctx.Partial), nil
+}
+
+// Evaluate returns the string value
+func (s *StringLiteralNode) Evaluate(ctx EvalContext) (any, error) {
+	return s.Value, nil
+}
+
+// Evaluate returns the boolean value
+func (b *BooleanLiteralNode) Evaluate(ctx EvalContext) (any, error) {
+	return b.Value, nil
+}
+
+// Evaluate returns the numeric value
+func (n *NumberLiteralNode) Evaluate(ctx EvalContext) (any, error) {
+	return parseNumberLiteral(n.Value)
+}
+
+// Evaluate returns null
+func (n *NullLiteralNode) Evaluate(ctx EvalContext) (any, error) {
+	return nil, nil
+}
+
+// Evaluate compares both operands using loose comparison rules
+func (c *ComparisonNode) Evaluate(ctx EvalContext) (any, error) {
+	left, err := c.Left.Evaluate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.Right.Evaluate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if isUnknown(left) || isUnknown(right) {
+		return unknownValue{}, nil
+	}
+	return compareExpressionValues(left, c.Operator, right)
+}
+
+// Evaluate returns the value of the branch selected by the condition
+func (t *TernaryNode) Evaluate(ctx EvalContext) (any, error) {
+	condition, err := t.Condition.Evaluate(ctx)
+	if err != nil || isUnknown(condition) {
+		return condition, err
+	}
+	if isTruthy(condition) {
+		return t.TrueValue.Evaluate(ctx)
+	}
+	return t.FalseValue.Evaluate(ctx)
+}
+
+// Evaluate checks whether the array (or string) contains the value
+func (c *ContainsNode) Evaluate(ctx EvalContext) (any, error) {
+	array, err := c.Array.Evaluate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	value, err := c.Value.Evaluate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if isUnknown(array) || isUnknown(value) {
+		return unknownValue{}, nil
+	}
+	return containsValue(array, value), nil
+}
+
+// evaluateFunctionCall evaluates a built-in expression function
+func evaluateFunctionCall(call *FunctionCallNode, ctx EvalContext) (any, error) {
+	name := strings.ToLower(call.FunctionName)
+	args := make([]any, 0, len(call.Arguments))
+	unknown := false
+	for _, arg := range call.Arguments {
+		value, err := arg.Evaluate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if isUnknown(value) {
+			unknown = true
+		}
+		args = append(args, value)
+	}
+
+	expectArgs := func(min, max int) error {
+		if len(args) < min || (max >= 0 && len(args) > max) {
+			if min == max {
+				return fmt.Errorf("function %s() expects %d argument(s), got %d", call.FunctionName, min, len(args))
+			}
+			return fmt.Errorf("function %s() expects at least %d argument(s), got %d", call.FunctionName, min, len(args))
+		}
+		return nil
+	}
+
+	var minArgs, maxArgs int
+	switch name {
+	case "success", "failure", "cancelled", "always":
+		minArgs, maxArgs = 0, 0
+	case "contains", "startswith", "endswith":
+		minArgs, maxArgs = 2, 2
+	case "join":
+		minArgs, maxArgs = 1, 2
+	case "tojson", "fromjson":
+		minArgs, maxArgs = 1, 1
+	case "format":
+		minArgs, maxArgs = 1, -1
+	case "hashfiles":
+		return nil, fmt.Errorf("function %s() requires a workspace and cannot be evaluated", call.FunctionName)
+	default:
+		return nil, fmt.Errorf("unsupported function %s()", call.FunctionName)
+	}
+	if err := expectArgs(minArgs, maxArgs); err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "always":
+		return true, nil
+	case "success", "failure", "cancelled":
+		if ctx.Partial {
+			return unknownValue{}, nil
+		}
+		return ctx.jobStatus() == name, nil
+	}
+	if unknown {
+		return unknownValue{}, nil
+	}
+
+	switch name {
+	case "contains":
+		return containsValue(args[0], args[1]), nil
+	case "startswith":
+		return strings.HasPrefix(strings.ToLower(valueToString(args[0])), strings.ToLower(valueToString(args[1]))), nil
+	case "endswith":
+		return strings.HasSuffix(strings.ToLower(valueToString(args[0])), strings.ToLower(valueToString(args[1]))), nil
+	case "join":
+		separator := ","
+		if len(args) == 2 {
+			separator = valueToString(args[1])
+		}
+		items, ok := normalizeValue(args[0]).([]any)
+		if !ok {
+			return valueToString(args[0]), nil
+		}
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = valueToString(item)
+		}
+		return strings.Join(parts, separator), nil
+	case "tojson":
+		return valueToJSON(args[0])
+	case "fromjson":
+		var value any
+		if err := json.Unmarshal([]byte(valueToString(args[0])), &value); err != nil {
+			return nil, fmt.Errorf("function %s() received invalid JSON: %w", call.FunctionName, err)
+		}
+		return value, nil
+	default: // format
+		return formatExpressionString(valueToString(args[0]), args[1:])
+	}
+}
+
+// formatExpressionString implements format(): {N} is replaced with the Nth argument
+// and {{ / }} escape literal braces
+func formatExpressionString(format string, args []any) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(format); i++ {
+		ch := format[i]
+		switch {
+		case ch == '{' && i+1 < len(format) && format[i+1] == '{':
+			sb.WriteByte('{')
+			i++
+		case ch == '}' && i+1 < len(format) && format[i+1] == '}':
+			sb.WriteByte('}')
+			i++
+		case ch == '{':
+			end := strings.IndexByte(format[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("format string '%s' has an unclosed '{'", format)
+			}
+			index, err := strconv.Atoi(format[i+1 : i+end])
+			if err != nil || index < 0 {
+				return "", fmt.Errorf("format string '%s' has an invalid placeholder '%s'", format, format[i:i+end+1])
+			}
+			if index >= len(args) {
+				return "", fmt.Errorf("format string '%s' references argument {%d} but only %d were given", format, index, len(args))
+			}
+			sb.WriteString(valueToString(args[index]))
+			i += end
+		case ch == '}':
+			return "", fmt.Errorf("format string '%s' has an unmatched '}'", format)
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+	return sb.String(), nil
+}
+
+// valueToJSON implements toJSON(), which pretty-prints with two-space indentation
+func valueToJSON(value any) (string, error) {
+	normalized := normalizeValue(value)
+	if f, ok := normalized.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1e15 {
+		return valueToString(f), nil
+	}
+	out, err := json.MarshalIndent(normalized, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("function toJSON() failed: %w", err)
+	}
+	return string(out), nil
+}
+
+// walkPropertySegments follows property path segments from a root value. A `*`
+// segment filters all elements of an array (or values of an object) and turns the
+// result into an array. Missing members resolve to null, or to an unknown value
+// during partial evaluation.
+func walkPropertySegments(root any, segments []string, partial bool) any {
+	current := []any{root}
+	filtered := false
+	missing := false
+	for _, segment := range segments {
+		var next []any
+		for _, value := range current {
+			if segment == "*" {
+				switch v := value.(type) {
+				case []any:
+					next = append(next, v...)
+				case map[string]any:
+					for _, item := range v {
+						next = append(next, item)
+					}
+				default:
+					missing = true
+				}
+				continue
+			}
+			if child, ok := indexValue(value, segment); ok {
+				next = append(next, child)
+			} else {
+				missing = true
+				if !filtered {
+					next = append(next, nil)
+				}
+			}
+		}
+		if segment == "*" {
+			filtered = true
+		}
+		current = next
+	}
+
+	if partial && missing {
+		return unknownValue{}
+	}
+	if filtered {
+		if current == nil {
+			return []any{}
+		}
+		return current
+	}
+	if len(current) == 0 {
+		return nil
+	}
+	return current[0]
+}
+
+// indexValue returns the named member of an object or the numbered element of an array.
+// Object member names are matched case-insensitively like the Actions runner does.
+func indexValue(value any, key string) (any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		if child, ok := v[key]; ok {
+			return child, true
+		}
+		for k, child := range v {
+			if strings.EqualFold(k, key) {
+				return child, true
+			}
+		}
+	case map[string]string:
+		if child, ok := v[key]; ok {
+			return child, true
+		}
+	case []any:
+		if idx, err := strconv.Atoi(key); err == nil && idx >= 0 && idx < len(v) {
+			return v[idx], true
+		}
+	case []string:
+		if idx, err := strconv.Atoi(key); err == nil && idx >= 0 && idx < len(v) {
+			return v[idx], true
+		}
+	}
+	return nil, false
+}
+
+// splitPropertyPath splits a property path into its segments, handling both
+// dereference (a.b) and index (a['b'], a[0], a[*]) syntax
+func splitPropertyPath(path string) ([]string, error) {
+	var segments []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			segments = append(segments, current.String())
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(path); i++ {
+		ch := path[i]
+		switch ch {
+		case '.':
+			flush()
+		case '[':
+			flush()
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated index in '%s'", path)
+			}
+			key := strings.TrimSpace(path[i+1 : i+end])
+			if len(key) >= 2 && key[0] == '\'' && key[len(key)-1] == '\'' {
+				key = strings.ReplaceAll(key[1:len(key)-1], "''", "'")
+			}
+			segments = append(segments, key)
+			i += end
+		default:
+			current.WriteByte(ch)
+		}
+	}
+	flush()
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("empty property path")
+	}
+	return segments, nil
+}
+
+// compareExpressionValues applies a comparison operator using GitHub Actions loose comparison rules
+func compareExpressionValues(left any, operator string, right any) (bool, error) {
+	switch operator {
+	case "==":
+		return looseEquals(left, right), nil
+	case "!=":
+		return !looseEquals(left, right), nil
+	case "<", "<=", ">", ">=":
+		ls, lok := left.(string)
+		rs, rok := right.(string)
+		var cmp int
+		if lok && rok {
+			cmp = strings.Compare(strings.ToLower(ls), strings.ToLower(rs))
+		} else {
+			ln, rn := valueToNumber(left), valueToNumber(right)
+			if math.IsNaN(ln) || math.IsNaN(rn) {
+				return false, nil
+			}
+			switch {
+			case ln < rn:
+				cmp = -1
+			case ln > rn:
+				cmp = 1
+			}
+		}
+		switch operator {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	}
+	return false, fmt.Errorf("unsupported comparison operator '%s'", operator)
+}
+
+// looseEquals compares two values the way the Actions runner does: values of the
+// same kind are compared directly (strings case-insensitively), arrays and objects
+// by reference, otherwise both sides are coerced to numbers
+func looseEquals(left, right any) bool {
+	if isCollection(left) || isCollection(right) {
+		return sameCollection(left, right)
+	}
+	left, right = normalizeValue(left), normalizeValue(right)
+	switch l := left.(type) {
+	case nil:
+		if right == nil {
+			return true
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return strings.EqualFold(l, r)
+		}
+	case bool:
+		if r, ok := right.(bool); ok {
+			return l == r
+		}
+	case float64:
+		if r, ok := right.(float64); ok {
+			return l == r
+		}
+	}
+	ln, rn := valueToNumber(left), valueToNumber(right)
+	return !math.IsNaN(ln) && !math.IsNaN(rn) && ln == rn
+}
+
+// isCollection reports whether a value is an array or an object
+func isCollection(value any) bool {
+	kind := reflect.ValueOf(value).Kind()
+	return kind == reflect.Slice || kind == reflect.Map
+}
+
+// sameCollection reports whether two values are the same array or object instance.
+// The values are compared as given, before normalizeValue copies []string and
+// map[string]string values.
+func sameCollection(left, right any) bool {
+	l, r := reflect.ValueOf(left), reflect.ValueOf(right)
+	if !isCollection(left) || l.Type() != r.Type() {
+		return false
+	}
+	if l.Kind() == reflect.Slice && l.Len() != r.Len() {
+		return false
+	}
+	return l.Pointer() == r.Pointer()
+}
+
+// containsValue implements contains(search, item) for both arrays and strings
+func containsValue(search, item any) bool {
+	switch s := normalizeValue(search).(type) {
+	case []any:
+		for _, element := range s {
+			if looseEquals(element, item) {
+				return true
+			}
+		}
+		return false
+	default:
+		return strings.Contains(strings.ToLower(valueToString(s)), strings.ToLower(valueToString(item)))
+	}
+}
+
+// normalizeValue converts Go numeric and collection types into the JSON-like
+// kinds used by the evaluator (float64, []any, map[string]any)
+func normalizeValue(value any) any {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case []string:
+		items := make([]any, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return items
+	case map[string]string:
+		object := make(map[string]any, len(v))
+		for k, s := range v {
+			object[k] = s
+		}
+		return object
+	}
+	return value
+}
+
+// isTruthy applies GitHub Actions truthiness: false, 0, -0, NaN, "" and null are falsy
+func isTruthy(value any) bool {
+	switch v := normalizeValue(value).(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0 && !math.IsNaN(v)
+	case string:
+		return v != ""
+	}
+	return true
+}
+
+// valueToNumber coerces a value to a number, returning NaN when not convertible
+func valueToNumber(value any) float64 {
+	switch v := normalizeValue(value).(type) {
+	case nil:
+		return 0
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	case float64:
+		return v
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if trimmed == "" {
+			return 0
+		}
+		if n, err := parseNumberLiteral(trimmed); err == nil {
+			return n
+		}
+	}
+	return math.NaN()
+}
+
+// valueToString coerces a value to its string representation
+func valueToString(value any) string {
+	switch v := normalizeValue(value).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		return "Array"
+	case map[string]any:
+		return "Object"
+	}
+	return fmt.Sprint(value)
+}
+
+// parseNumberLiteral parses decimal, hexadecimal (0x) and octal (0o) numbers
+func parseNumberLiteral(literal string) (float64, error) {
+	lower := strings.ToLower(literal)
+	sign := 1.0
+	if strings.HasPrefix(lower, "-") {
+		sign = -1
+		lower = lower[1:]
+	}
+	if strings.HasPrefix(lower, "0x") || strings.HasPrefix(lower, "0o") {
+		n, err := strconv.ParseInt(lower, 0, 64)
+		if err != nil {
+			return math.NaN(), fmt.Errorf("invalid number '%s'", literal)
+		}
+		return sign * float64(n), nil
+	}
+	n, err := strconv.ParseFloat(lower, 64)
+	if err != nil {
+		return math.NaN(), fmt.Errorf("invalid number '%s'", literal)
+	}
+	return sign * n, nil
+}
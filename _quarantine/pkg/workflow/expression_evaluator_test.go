@@ -0,0 +1,246 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvalContext() EvalContext {
+	return EvalContext{
+		GitHub: map[string]any{
+			"event_name": "issues",
+			"actor":      "octocat",
+			"event": map[string]any{
+				"action": "opened",
+				"issue": map[string]any{
+					"number": 1,
+					"body":   "/bot please help",
+					"labels": []any{
+						map[string]any{"name": "bug"},
+						map[string]any{"name": "triage"},
+					},
+				},
+			},
+		},
+		Needs: map[string]any{
+			"activation": map[string]any{
+				"result":  "success",
+				"outputs": map[string]any{"activated": "true"},
+			},
+		},
+		Inputs: map[string]any{"count": "3"},
+	}
+}
+
+func TestEvaluateJobCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+		status    string
+		expected  bool
+	}{
+		{name: "empty condition is success()", condition: "", expected: true},
+		{name: "empty condition with failed dependency", condition: "", status: "failure", expected: false},
+		{name: "string equality", condition: "github.event_name == 'issues'", expected: true},
+		{name: "case insensitive string equality", condition: "github.event_name == 'ISSUES'", expected: true},
+		{name: "inequality", condition: "github.event.action != 'opened'", expected: false},
+		{name: "wrapped in template", condition: "${{ github.actor == 'octocat' }}", expected: true},
+		{name: "needs output", condition: "needs.activation.outputs.activated == 'true'", expected: true},
+		{name: "needs result", condition: "needs.activation.result == 'success'", expected: true},
+		{name: "loose number equality", condition: "inputs.count == 3", expected: true},
+		{name: "numeric comparison", condition: "inputs.count > 2", expected: true},
+		{name: "missing property is empty", condition: "github.event.pull_request.number", expected: false},
+		{name: "null comparison", condition: "github.event.pull_request == null", expected: true},
+		{name: "contains string", condition: "contains(github.event.issue.body, '/BOT')", expected: true},
+		{name: "contains array filter", condition: "contains(github.event.issue.labels.*.name, 'bug')", expected: true},
+		{name: "startsWith", condition: "startsWith(github.event.issue.body, '/bot')", expected: true},
+		{name: "endsWith", condition: "endsWith(github.event.issue.body, 'nope')", expected: false},
+		{name: "and or not", condition: "(github.event_name == 'issues' && !cancelled()) || github.event_name == 'push'", expected: true},
+		{name: "implicit success with failed dependency", condition: "github.event_name == 'issues'", status: "failure", expected: false},
+		{name: "always overrides status", condition: "always() && github.event_name == 'issues'", status: "failure", expected: true},
+		{name: "failure function", condition: "failure()", status: "failure", expected: true},
+		{name: "skipped dependency", condition: "!cancelled()", status: "skipped", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestEvalContext()
+			ctx.JobStatus = tt.status
+			result, err := EvaluateJobCondition(tt.condition, ctx)
+			require.NoError(t, err, "condition should evaluate: %s", tt.condition)
+			assert.Equal(t, tt.expected, result, "unexpected result for: %s", tt.condition)
+		})
+	}
+}
+
+func TestEvaluateJobConditionErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+	}{
+		{name: "unknown function", condition: "frobnicate(github.actor)"},
+		{name: "unterminated string", condition: "github.actor == 'octocat"},
+		{name: "unknown context", condition: "runner.os == 'Linux'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EvaluateJobCondition(tt.condition, newTestEvalContext())
+			assert.Error(t, err, "condition should fail to evaluate: %s", tt.condition)
+		})
+	}
+}
+
+func TestEvaluateExpressionString(t *testing.T) {
+	ctx := newTestEvalContext()
+
+	node, err := parseOperandExpression("github.event.issue.number")
+	require.NoError(t, err)
+	result, err := EvaluateExpressionString(node, ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1", result)
+
+	node, err = parseOperandExpression("github.event.action == 'opened'")
+	require.NoError(t, err)
+	result, err = EvaluateExpressionString(node, ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "true", result)
+}
+
+func TestLooseEquals(t *testing.T) {
+	assert.True(t, looseEquals("1", 1.0), "numeric string should equal number")
+	assert.True(t, looseEquals(true, 1.0), "true should equal 1")
+	assert.True(t, looseEquals(nil, 0.0), "null should equal 0")
+	assert.True(t, looseEquals("", false), "empty string should equal false")
+	assert.True(t, looseEquals("Abc", "aBC"), "strings compare case-insensitively")
+	assert.False(t, looseEquals("abc", 0.0), "non-numeric string is NaN")
+	assert.False(t, looseEquals(map[string]any{}, map[string]any{}), "distinct objects are not equal")
+
+	object := map[string]any{"a": 1.0}
+	labels := []string{"bug", "triage"}
+	assert.True(t, looseEquals(object, object), "an object equals itself")
+	assert.True(t, looseEquals(labels, labels), "an array equals itself")
+	assert.False(t, looseEquals(labels, []string{"bug", "triage"}), "distinct arrays are not equal")
+	assert.False(t, looseEquals(labels, labels[:1]), "a slice of an array is a different array")
+	assert.False(t, looseEquals(object, 1.0), "objects are not coerced to numbers")
+	assert.False(t, looseEquals("Array", []any{}), "arrays are not compared as strings")
+}
+
+func TestEvaluateFunctions(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		expected   string
+	}{
+		{name: "format", expression: "format('{0} opened #{1}', github.actor, github.event.issue.number)", expected: "octocat opened #1"},
+		{name: "format escapes braces", expression: "format('{{{0}}}', 'x')", expected: "{x}"},
+		{name: "join default separator", expression: "join(github.event.issue.labels.*.name)", expected: "bug,triage"},
+		{name: "join custom separator", expression: "join(github.event.issue.labels.*.name, ' | ')", expected: "bug | triage"},
+		{name: "join string", expression: "join('single', '-')", expected: "single"},
+		{name: "toJSON string", expression: "toJSON('hi')", expected: `"hi"`},
+		{name: "toJSON number", expression: "toJSON(inputs.count)", expected: `"3"`},
+		{name: "toJSON object", expression: "toJSON(needs.activation.outputs)", expected: "{\n  \"activated\": \"true\"\n}"},
+		{name: "fromJSON member access", expression: "fromJSON('{\"a\":{\"b\":[10,20]}}').a.b[1]", expected: "20"},
+		{name: "fromJSON boolean", expression: "fromJSON('true') == true", expected: "true"},
+		{name: "fromJSON number compare", expression: "fromJSON(inputs.count) > 2", expected: "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := parseOperandExpression(tt.expression)
+			require.NoError(t, err, "expression should parse: %s", tt.expression)
+			result, err := EvaluateExpressionString(node, newTestEvalContext())
+			require.NoError(t, err, "expression should evaluate: %s", tt.expression)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestEvaluateFunctionErrors(t *testing.T) {
+	tests := []string{
+		"format('{1}', 'only one')",
+		"format('{0')",
+		"fromJSON('not json')",
+		"contains('a')",
+		"hashFiles('**/go.sum')",
+	}
+
+	for _, expression := range tests {
+		t.Run(expression, func(t *testing.T) {
+			node, err := parseOperandExpression(expression)
+			require.NoError(t, err, "expression should parse")
+			_, err = node.Evaluate(newTestEvalContext())
+			assert.Error(t, err, "expression should fail to evaluate: %s", expression)
+		})
+	}
+}
+
+func TestConditionNodeEvaluate(t *testing.T) {
+	ctx := newTestEvalContext()
+
+	node := BuildAnd(
+		BuildEventTypeEquals("issues"),
+		&NotNode{Child: BuildPropertyAccess("github.event.issue.locked")},
+	)
+	value, err := node.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, true, value)
+
+	or := &OrNode{Left: &StringLiteralNode{Value: ""}, Right: &StringLiteralNode{Value: "fallback"}}
+	value, err = or.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", value, "|| should return the first truthy operand")
+
+	disjunction := &DisjunctionNode{Terms: []ConditionNode{
+		BuildEventTypeEquals("push"),
+		BuildEventTypeEquals("issues"),
+	}}
+	value, err = disjunction.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, true, value)
+
+	ternary := &TernaryNode{
+		Condition:  BuildEventTypeEquals("issues"),
+		TrueValue:  &NumberLiteralNode{Value: "1"},
+		FalseValue: &NumberLiteralNode{Value: "2"},
+	}
+	value, err = ternary.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, value, 0)
+}
+
+func TestEvaluateConditionStatically(t *testing.T) {
+	tests := []struct {
+		name          string
+		condition     string
+		expectedKnown bool
+		expected      bool
+	}{
+		{name: "event mismatch", condition: "github.event_name == 'pull_request'", expectedKnown: true, expected: false},
+		{name: "event match", condition: "github.event_name == 'issues'", expectedKnown: true, expected: true},
+		{name: "payload is unknown", condition: "github.event.issue.number == 1", expectedKnown: false},
+		{name: "false and unknown", condition: "github.event_name == 'push' && github.event.ref == 'refs/heads/main'", expectedKnown: true, expected: false},
+		{name: "unknown and false", condition: "needs.activation.outputs.activated == 'true' && github.event_name == 'push'", expectedKnown: true, expected: false},
+		{name: "unknown or true", condition: "inputs.force || github.event_name == 'issues'", expectedKnown: true, expected: true},
+		{name: "status functions are unknown", condition: "success()", expectedKnown: false},
+		{name: "always is known", condition: "always() && github.event_name == 'issues'", expectedKnown: true, expected: true},
+		{name: "negated unknown", condition: "!cancelled()", expectedKnown: false},
+		{name: "unknown context", condition: "runner.os == 'Linux'", expectedKnown: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := EvalContext{GitHub: map[string]any{"event_name": "issues"}}
+			result, known, err := EvaluateConditionStatically(tt.condition, ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedKnown, known, "unexpected known state for: %s", tt.condition)
+			if tt.expectedKnown {
+				assert.Equal(t, tt.expected, result, "unexpected result for: %s", tt.condition)
+			}
+		})
+	}
+}
@@ -12,6 +12,9 @@ var expressionNodesLog = logger.New("workflow:expression_nodes")
 // ConditionNode represents a node in a condition expression tree
 type ConditionNode interface {
 	Render() string
+	// Evaluate computes the value of the node against the given contexts using
+	// GitHub Actions expression semantics (see expression_evaluator.go)
+	Evaluate(ctx EvalContext) (any, error)
 }
 
 // ExpressionNode represents a leaf expression
@@ -148,6 +151,17 @@ func (p *PropertyAccessNode) Render() string {
 	return p.PropertyPath
 }
 
+// MemberAccessNode represents property access on the result of another expression
+// like fromJSON(steps.meta.outputs.json).labels[0]
+type MemberAccessNode struct {
+	Target       ConditionNode
+	PropertyPath string // Path suffix including the leading '.' or '['
+}
+
+func (m *MemberAccessNode) Render() string {
+	return m.Target.Render() + m.PropertyPath
+}
+
 // StringLiteralNode represents a string literal value
 type StringLiteralNode struct {
 	Value string
@@ -178,6 +192,13 @@ func (n *NumberLiteralNode) Render() string {
 	return n.Value
 }
 
+// NullLiteralNode represents the null literal
+type NullLiteralNode struct{}
+
+func (n *NullLiteralNode) Render() string {
+	return "null"
+}
+
 // ComparisonNode represents comparison operations like ==, !=, <, >, <=, >=
 type ComparisonNode struct {
 	Left     ConditionNode
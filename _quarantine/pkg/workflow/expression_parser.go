@@ -27,6 +27,10 @@ func stripExpressionWrapper(expression string) string {
 type ExpressionParser struct {
 	tokens []token
 	pos    int
+
+	// operands parses the operands between the logical operators into comparison,
+	// function call, property access and literal nodes instead of ExpressionNode literals
+	operands bool
 }
 
 type token struct {
@@ -45,19 +49,42 @@ const (
 	tokenLeftParen
 	tokenRightParen
 	tokenEOF
+
+	// Operand tokens, only produced when parsing operands
+	tokenComparison
+	tokenString
+	tokenNumber
+	tokenIdentifier
+	tokenComma
+	tokenDot
+	tokenLeftBracket
+	tokenRightBracket
+	tokenStar
 )
 
 // ParseExpression parses a string expression into a ConditionNode tree
 // Supports && (AND), || (OR), ! (NOT), and parentheses for grouping
 // Example: "condition1 && (condition2 || !condition3)"
 func ParseExpression(expression string) (ConditionNode, error) {
+	return parseExpression(expression, false)
+}
+
+// parseOperandExpression parses a string expression like ParseExpression, and also parses
+// the operands into comparison, function call, property access and literal nodes so the
+// tree can be evaluated
+// Example: "github.event_name == 'issues' && contains(github.event.issue.labels.*.name, 'bug')"
+func parseOperandExpression(expression string) (ConditionNode, error) {
+	return parseExpression(expression, true)
+}
+
+func parseExpression(expression string, operands bool) (ConditionNode, error) {
 	expressionsLog.Printf("Parsing expression: %s", expression)
 
 	if strings.TrimSpace(expression) == "" {
 		return nil, fmt.Errorf("empty expression")
 	}
 
-	parser := &ExpressionParser{}
+	parser := &ExpressionParser{operands: operands}
 	tokens, err := parser.tokenize(expression)
 	if err != nil {
 		expressionsLog.Printf("Failed to tokenize expression: %v", err)
@@ -111,6 +138,13 @@ func (p *ExpressionParser) tokenize(expression string) ([]token, error) {
 		case expression[i] == ')':
 			tokens = append(tokens, token{tokenRightParen, ")", i})
 			i++
+		case p.operands:
+			tok, next, err := scanOperandToken(expression, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = next
 		default:
 			// Parse literal expression - everything until we hit a logical operator or paren
 			start := i
@@ -185,6 +219,65 @@ func (p *ExpressionParser) tokenize(expression string) ([]token, error) {
 	return tokens, nil
 }
 
+// scanOperandToken reads the operand token starting at position i and returns it with the
+// position following it
+func scanOperandToken(expression string, i int) (token, int, error) {
+	ch := expression[i]
+	switch {
+	case ch == '\'':
+		// String literal, with '' escaping a single quote
+		var sb strings.Builder
+		for j := i + 1; j < len(expression); j++ {
+			if expression[j] != '\'' {
+				sb.WriteByte(expression[j])
+				continue
+			}
+			if j+1 < len(expression) && expression[j+1] == '\'' {
+				sb.WriteByte('\'')
+				j++
+				continue
+			}
+			return token{tokenString, sb.String(), i}, j + 1, nil
+		}
+		return token{}, 0, fmt.Errorf("unterminated string literal at position %d", i)
+	case ch == '=' || ch == '!' || ch == '<' || ch == '>':
+		if i+1 < len(expression) && expression[i+1] == '=' {
+			return token{tokenComparison, expression[i : i+2], i}, i + 2, nil
+		}
+		if ch == '<' || ch == '>' {
+			return token{tokenComparison, string(ch), i}, i + 1, nil
+		}
+	case ch == ',':
+		return token{tokenComma, ",", i}, i + 1, nil
+	case ch == '.':
+		return token{tokenDot, ".", i}, i + 1, nil
+	case ch == '[':
+		return token{tokenLeftBracket, "[", i}, i + 1, nil
+	case ch == ']':
+		return token{tokenRightBracket, "]", i}, i + 1, nil
+	case ch == '*':
+		return token{tokenStar, "*", i}, i + 1, nil
+	case ch == '-' || (ch >= '0' && ch <= '9'):
+		j := i + 1
+		for j < len(expression) && (isIdentifierChar(expression[j]) || expression[j] == '.' ||
+			((expression[j] == '+' || expression[j] == '-') && (expression[j-1] == 'e' || expression[j-1] == 'E'))) {
+			j++
+		}
+		return token{tokenNumber, expression[i:j], i}, j, nil
+	case isIdentifierChar(ch):
+		j := i + 1
+		for j < len(expression) && isIdentifierChar(expression[j]) {
+			j++
+		}
+		return token{tokenIdentifier, expression[i:j], i}, j, nil
+	}
+	return token{}, 0, fmt.Errorf("unexpected '%c' at position %d", ch, i)
+}
+
+func isIdentifierChar(ch byte) bool {
+	return ch == '_' || ch == '-' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+}
+
 // parseOrExpression parses OR expressions (lowest precedence)
 func (p *ExpressionParser) parseOrExpression() (ConditionNode, error) {
 	left, err := p.parseAndExpression()
@@ -206,14 +299,14 @@ func (p *ExpressionParser) parseOrExpression() (ConditionNode, error) {
 
 // parseAndExpression parses AND expressions (higher precedence than OR)
 func (p *ExpressionParser) parseAndExpression() (ConditionNode, error) {
-	left, err := p.parseUnaryExpression()
+	left, err := p.parseComparisonExpression()
 	if err != nil {
 		return nil, err
 	}
 
 	for p.current().kind == tokenAnd {
 		p.advance() // consume &&
-		right, err := p.parseUnaryExpression()
+		right, err := p.parseComparisonExpression()
 		if err != nil {
 			return nil, err
 		}
@@ -223,6 +316,27 @@ func (p *ExpressionParser) parseAndExpression() (ConditionNode, error) {
 	return left, nil
 }
 
+// parseComparisonExpression parses comparisons of operands (higher precedence than AND,
+// lower than NOT). Without operand parsing, comparisons are part of the literals.
+func (p *ExpressionParser) parseComparisonExpression() (ConditionNode, error) {
+	left, err := p.parseUnaryExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.current().kind == tokenComparison {
+		operator := p.current().value
+		p.advance() // consume the operator
+		right, err := p.parseUnaryExpression()
+		if err != nil {
+			return nil, err
+		}
+		left = &ComparisonNode{Left: left, Operator: operator, Right: right}
+	}
+
+	return left, nil
+}
+
 // parseUnaryExpression parses NOT expressions and primary expressions
 func (p *ExpressionParser) parseUnaryExpression() (ConditionNode, error) {
 	if p.current().kind == tokenNot {
@@ -260,11 +374,119 @@ func (p *ExpressionParser) parsePrimaryExpression() (ConditionNode, error) {
 		p.advance()
 		return &ExpressionNode{Expression: literal}, nil
 
+	case tokenString:
+		value := p.current().value
+		p.advance()
+		return &StringLiteralNode{Value: value}, nil
+
+	case tokenNumber:
+		value := p.current().value
+		p.advance()
+		return &NumberLiteralNode{Value: value}, nil
+
+	case tokenIdentifier:
+		name := p.current().value
+		p.advance()
+		switch strings.ToLower(name) {
+		case "true":
+			return &BooleanLiteralNode{Value: true}, nil
+		case "false":
+			return &BooleanLiteralNode{Value: false}, nil
+		case "null":
+			return &NullLiteralNode{}, nil
+		}
+		if p.current().kind == tokenLeftParen {
+			p.advance() // consume (
+			call, err := p.parseFunctionCall(name)
+			if err != nil {
+				return nil, err
+			}
+			// Member access on the result, like fromJSON(steps.meta.outputs.json).labels[0]
+			suffix, err := p.parsePropertyPath("")
+			if err != nil {
+				return nil, err
+			}
+			if suffix != "" {
+				return &MemberAccessNode{Target: call, PropertyPath: suffix}, nil
+			}
+			return call, nil
+		}
+		path, err := p.parsePropertyPath(name)
+		if err != nil {
+			return nil, err
+		}
+		return &PropertyAccessNode{PropertyPath: path}, nil
+
 	default:
 		return nil, fmt.Errorf("unexpected token '%s' at position %d", p.current().value, p.current().pos)
 	}
 }
 
+// parseFunctionCall parses the arguments of a function call after its opening parenthesis
+func (p *ExpressionParser) parseFunctionCall(name string) (ConditionNode, error) {
+	call := &FunctionCallNode{FunctionName: name}
+	if p.current().kind == tokenRightParen {
+		p.advance() // consume )
+		return call, nil
+	}
+
+	for {
+		arg, err := p.parseOrExpression()
+		if err != nil {
+			return nil, err
+		}
+		call.Arguments = append(call.Arguments, arg)
+
+		switch p.current().kind {
+		case tokenRightParen:
+			p.advance() // consume )
+			return call, nil
+		case tokenComma:
+			p.advance() // consume ,
+		default:
+			return nil, fmt.Errorf("expected ',' or ')' at position %d", p.current().pos)
+		}
+	}
+}
+
+// parsePropertyPath parses the .name, ['name'], [0] and [*] accessors following an
+// identifier or function call and returns them appended to name
+func (p *ExpressionParser) parsePropertyPath(name string) (string, error) {
+	var path strings.Builder
+	path.WriteString(name)
+
+	for {
+		switch p.current().kind {
+		case tokenDot:
+			p.advance() // consume .
+			tok := p.current()
+			if tok.kind != tokenIdentifier && tok.kind != tokenStar {
+				return "", fmt.Errorf("expected property name at position %d", tok.pos)
+			}
+			path.WriteString("." + tok.value)
+			p.advance()
+		case tokenLeftBracket:
+			p.advance() // consume [
+			tok := p.current()
+			switch tok.kind {
+			case tokenString:
+				path.WriteString("['" + strings.ReplaceAll(tok.value, "'", "''") + "']")
+			case tokenNumber, tokenStar:
+				path.WriteString("[" + tok.value + "]")
+			default:
+				return "", fmt.Errorf("unsupported index at position %d", tok.pos)
+			}
+			p.advance()
+			if p.current().kind != tokenRightBracket {
+				return "", fmt.Errorf("expected ']' at position %d", p.current().pos)
+			}
+			p.advance() // consume ]
+		default:
+			return path.String(), nil
+		}
+	}
+}
+
 // current returns the current token
 func (p *ExpressionParser) current() token {
 	if p.pos >= len(p.tokens) {
@@ -472,6 +472,55 @@ func TestExpressionParserEdgeCases(t *testing.T) {
 	}
 }
 
+// TestParseOperandExpression tests that operand parsing builds comparison, function call,
+// property access and literal nodes below the logical operators
+func TestParseOperandExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{
+			input:    "github.event_name == 'issues' && contains(github.event.issue.labels.*.name, 'bug')",
+			expected: "(github.event_name == 'issues') && (contains(github.event.issue.labels.*.name, 'bug'))",
+		},
+		{
+			input:    "!cancelled() && needs['pre-activation'].outputs.activated == 'true'",
+			expected: "(!cancelled()) && (needs['pre-activation'].outputs.activated == 'true')",
+		},
+		{
+			input:    "!github.event.issue == null || github.run_attempt >= 2",
+			expected: "(!(github.event.issue) == null) || (github.run_attempt >= 2)",
+		},
+		{
+			input:    "startsWith(github.ref, 'refs/tags/') || github.event.label.name == 'it''s'",
+			expected: "(startsWith(github.ref, 'refs/tags/')) || (github.event.label.name == 'it's')",
+		},
+		{input: "github.event_name = 'issues'", wantErr: true},
+		{input: "github.event_name == 'issues", wantErr: true},
+		{input: "contains(github.event.issue.labels.*.name, 'bug'", wantErr: true},
+		{input: "github.event.", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := parseOperandExpression(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseOperandExpression() expected error, got %q", result.Render())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseOperandExpression() unexpected error: %v", err)
+			}
+			if rendered := result.Render(); rendered != tt.expected {
+				t.Errorf("parseOperandExpression() = %q, want %q", rendered, tt.expected)
+			}
+		})
+	}
+}
+
 // TestExpressionSafetyComprehensive tests comprehensive expression safety validation
 func TestExpressionSafetyComprehensive(t *testing.T) {
 	tests := []struct {
@@ -19,6 +19,7 @@ func TestForbiddenFieldsImportRejection(t *testing.T) {
 	// Use the SharedWorkflowForbiddenFields constant and create YAML examples for each
 	forbiddenFieldYAML := map[string]string{
 		"on":              `on: issues`,
+		"budget":          `budget: {max-tokens: 1000}`,
 		"command":         `command: /help`,
 		"concurrency":     `concurrency: production`,
 		"container":       `container: node:lts`,
@@ -450,6 +450,13 @@ func (c *Compiler) extractMCPGatewayConfig(mcpVal any) *MCPGatewayRuntimeConfig
 		}
 	}
 
+	// Extract record (record MCP payloads for replay)
+	if recordVal, hasRecord := mcpObj["record"]; hasRecord {
+		if recordBool, ok := recordVal.(bool); ok {
+			mcpConfig.Record = recordBool
+		}
+	}
+
 	return mcpConfig
 }
 
@@ -145,6 +145,9 @@ type FrontmatterConfig struct {
 	// Command/bot configuration
 	Roles []string `json:"roles,omitempty"`
 	Bots  []string `json:"bots,omitempty"`
+
+	// Token and cost limits
+	Budget *BudgetConfig `json:"budget,omitempty"`
 }
 
 // unmarshalFromMap converts a value from a map[string]any to a destination variable
@@ -656,6 +659,9 @@ func (fc *FrontmatterConfig) ToMap() map[string]any {
 	if fc.Bots != nil {
 		result["bots"] = fc.Bots
 	}
+	if fc.Budget != nil {
+		result["budget"] = fc.Budget
+	}
 
 	return result
 }
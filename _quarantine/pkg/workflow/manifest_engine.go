@@ -0,0 +1,542 @@
+// This file provides the agentic engine implementation backed by an engine manifest.
+//
+// ManifestEngine turns a declarative EngineManifest (see engine_manifest.go) into a
+// CodingAgentEngine:
+//
+//   - Installation: secret validation, AWF (when the firewall is enabled) and the manifest's install steps
+//   - Execution: the manifest command template, optionally wrapped with AWF, teed into the agent log
+//   - MCP: the shared JSON renderer in the manifest's schema flavour, written to the manifest's config file
+//   - Logs: regex-based extraction of turns, token usage, cost and tool calls into LogMetrics
+
+package workflow
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var manifestEngineLog = logger.New("workflow:manifest_engine")
+
+// defaultManifestMCPConfigFile is used when the manifest does not set mcp.config-file
+const defaultManifestMCPConfigFile = "/tmp/gh-aw/mcp-config/mcp-servers.json"
+
+// ManifestEngine is an agentic engine defined by an engine manifest
+type ManifestEngine struct {
+	BaseEngine
+	manifest *EngineManifest
+
+	turnPattern       *regexp.Regexp
+	tokenUsagePattern *regexp.Regexp
+	costPattern       *regexp.Regexp
+	toolCallPattern   *regexp.Regexp
+}
+
+// NewManifestEngine creates a new ManifestEngine from a validated manifest
+func NewManifestEngine(manifest *EngineManifest) *ManifestEngine {
+	displayName := manifest.DisplayName
+	if displayName == "" {
+		displayName = manifest.ID
+	}
+	description := manifest.Description
+	if description == "" {
+		description = "Engine defined in " + manifest.Path
+	}
+
+	engine := &ManifestEngine{
+		BaseEngine: BaseEngine{
+			id:                     manifest.ID,
+			displayName:            displayName,
+			description:            description,
+			experimental:           manifest.Experimental,
+			supportsToolsAllowlist: manifest.Capabilities.ToolsAllowlist,
+			supportsHTTPTransport:  manifest.Capabilities.HTTPTransport,
+			supportsMaxTurns:       manifest.Capabilities.MaxTurns,
+			supportsWebFetch:       manifest.Capabilities.WebFetch,
+			supportsWebSearch:      manifest.Capabilities.WebSearch,
+			supportsFirewall:       manifest.Capabilities.Firewall,
+			supportsPlugins:        manifest.Capabilities.Plugins,
+		},
+		manifest: manifest,
+	}
+
+	// Patterns were validated when the manifest was parsed
+	compile := func(pattern string) *regexp.Regexp {
+		if pattern == "" {
+			return nil
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			manifestEngineLog.Printf("Ignoring invalid log pattern %q: %v", pattern, err)
+			return nil
+		}
+		return re
+	}
+	engine.turnPattern = compile(manifest.Logs.Turn)
+	engine.tokenUsagePattern = compile(manifest.Logs.TokenUsage)
+	engine.costPattern = compile(manifest.Logs.Cost)
+	engine.toolCallPattern = compile(manifest.Logs.ToolCall)
+
+	return engine
+}
+
+// Manifest returns the manifest the engine was created from
+func (e *ManifestEngine) Manifest() *EngineManifest {
+	return e.manifest
+}
+
+// mcpConfigFile returns the path of the MCP configuration file the agent reads
+func (e *ManifestEngine) mcpConfigFile() string {
+	if e.manifest.MCP.ConfigFile != "" {
+		return e.manifest.MCP.ConfigFile
+	}
+	return defaultManifestMCPConfigFile
+}
+
+// GetRequiredSecretNames returns the manifest secrets plus the MCP gateway key and safe-inputs secrets
+func (e *ManifestEngine) GetRequiredSecretNames(workflowData *WorkflowData) []string {
+	secrets := append([]string{}, e.manifest.Secrets...)
+
+	if HasMCPServers(workflowData) {
+		secrets = append(secrets, "MCP_GATEWAY_API_KEY")
+	}
+
+	if IsSafeInputsEnabled(workflowData.SafeInputs, workflowData) {
+		for varName := range collectSafeInputsSecrets(workflowData.SafeInputs) {
+			secrets = append(secrets, varName)
+		}
+	}
+
+	return secrets
+}
+
+// GetDeclaredOutputFiles returns the output files declared by the manifest
+func (e *ManifestEngine) GetDeclaredOutputFiles() []string {
+	return append([]string{}, e.manifest.Execution.OutputFiles...)
+}
+
+// GetLogFileForParsing returns the manifest log file, defaulting to the agent stdio log
+func (e *ManifestEngine) GetLogFileForParsing() string {
+	if e.manifest.Logs.File != "" {
+		return e.manifest.Logs.File
+	}
+	return e.BaseEngine.GetLogFileForParsing()
+}
+
+// GetLogParserScriptId returns the generic log parser used for manifest engines
+func (e *ManifestEngine) GetLogParserScriptId() string {
+	return "parse_custom_log"
+}
+
+// GetInstallationSteps returns secret validation, AWF installation (when the firewall is
+// enabled) and the manifest's install steps. Installation is skipped when engine.command is set.
+func (e *ManifestEngine) GetInstallationSteps(workflowData *WorkflowData) []GitHubActionStep {
+	manifestEngineLog.Printf("Generating installation steps for manifest engine %s: workflow=%s", e.id, workflowData.Name)
+
+	if workflowData.EngineConfig != nil && workflowData.EngineConfig.Command != "" {
+		manifestEngineLog.Printf("Skipping installation steps: custom command specified (%s)", workflowData.EngineConfig.Command)
+		return []GitHubActionStep{}
+	}
+
+	var steps []GitHubActionStep
+
+	if len(e.manifest.Secrets) > 0 {
+		steps = append(steps, GenerateMultiSecretValidationStep(e.manifest.Secrets, e.displayName, e.manifest.DocsURL))
+	}
+
+	if e.supportsFirewall && isFirewallEnabled(workflowData) {
+		var awfVersion string
+		if firewallConfig := getFirewallConfig(workflowData); firewallConfig != nil {
+			awfVersion = firewallConfig.Version
+		}
+		if awfInstall := generateAWFInstallationStep(awfVersion, getAgentConfig(workflowData)); len(awfInstall) > 0 {
+			steps = append(steps, awfInstall)
+		}
+	}
+
+	version := e.manifest.Version
+	if workflowData.EngineConfig != nil && workflowData.EngineConfig.Version != "" {
+		version = workflowData.EngineConfig.Version
+	}
+
+	for _, installStep := range e.manifest.Install {
+		step := substituteInstallStepPlaceholders(installStep, map[string]string{"version": version})
+		stepMap, ok := step.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		// Pin actions referenced by install steps like any other workflow step
+		if typedStep, err := MapToStep(stepMap); err == nil {
+			stepMap = ApplyActionPinToTypedStep(typedStep, workflowData).ToMap()
+		} else {
+			manifestEngineLog.Printf("Failed to convert install step to typed step, skipping action pinning: %v", err)
+		}
+
+		stepYAML, err := e.convertStepToYAML(stepMap)
+		if err != nil {
+			manifestEngineLog.Printf("Failed to convert install step to YAML: %v", err)
+			continue
+		}
+		steps = append(steps, GitHubActionStep(strings.Split(strings.TrimRight(stepYAML, "\n"), "\n")))
+	}
+
+	return steps
+}
+
+// GetExecutionSteps returns the step running the manifest's execution command
+func (e *ManifestEngine) GetExecutionSteps(workflowData *WorkflowData, logFile string) []GitHubActionStep {
+	firewallEnabled := e.supportsFirewall && isFirewallEnabled(workflowData)
+	manifestEngineLog.Printf("Generating execution steps for manifest engine %s: workflow=%s, firewall=%v", e.id, workflowData.Name, firewallEnabled)
+
+	steps := InjectCustomEngineSteps(workflowData, e.convertStepToYAML)
+
+	agentCommand := e.renderCommand(workflowData, logFile)
+
+	var command string
+	if firewallEnabled {
+		awfCommand, awfArgs := e.buildAWFArgs(workflowData)
+		command = fmt.Sprintf(`set -o pipefail
+%s %s \
+  -- %s \
+  2>&1 | tee -a %s`, awfCommand, shellJoinArgs(awfArgs), shellEscapeArg(agentCommand), shellEscapeArg(logFile))
+	} else {
+		command = fmt.Sprintf(`set -o pipefail
+%s 2>&1 | tee -a %s`, agentCommand, logFile)
+	}
+
+	env := map[string]string{
+		"GH_AW_PROMPT":        "/tmp/gh-aw/aw-prompts/prompt.txt",
+		"GITHUB_WORKSPACE":    "${{ github.workspace }}",
+		"GITHUB_STEP_SUMMARY": "${{ env.GITHUB_STEP_SUMMARY }}",
+	}
+	for _, secret := range e.manifest.Secrets {
+		env[secret] = fmt.Sprintf("${{ secrets.%s }}", secret)
+	}
+	if HasMCPServers(workflowData) {
+		env["GH_AW_MCP_CONFIG"] = e.mcpConfigFile()
+	}
+	applySafeOutputEnvToMap(env, workflowData)
+
+	if workflowData.EngineConfig != nil {
+		if workflowData.EngineConfig.MaxTurns != "" {
+			env["GH_AW_MAX_TURNS"] = workflowData.EngineConfig.MaxTurns
+		}
+		if workflowData.EngineConfig.Model != "" {
+			env["GH_AW_MODEL"] = workflowData.EngineConfig.Model
+		}
+	}
+
+	for key, value := range e.manifest.Execution.Env {
+		env[key] = value
+	}
+	if workflowData.EngineConfig != nil {
+		for key, value := range workflowData.EngineConfig.Env {
+			env[key] = value
+		}
+	}
+	if agentConfig := getAgentConfig(workflowData); agentConfig != nil {
+		for key, value := range agentConfig.Env {
+			env[key] = value
+		}
+	}
+
+	if IsSafeInputsEnabled(workflowData.SafeInputs, workflowData) {
+		for varName, secretExpr := range collectSafeInputsSecrets(workflowData.SafeInputs) {
+			if _, exists := env[varName]; !exists {
+				env[varName] = secretExpr
+			}
+		}
+	}
+
+	stepName := e.manifest.Execution.StepName
+	if stepName == "" {
+		stepName = "Execute " + e.displayName
+	}
+
+	stepLines := []string{
+		fmt.Sprintf("      - name: %s", stepName),
+		"        id: agentic_execution",
+	}
+	if workflowData.TimeoutMinutes != "" {
+		timeoutValue := strings.TrimPrefix(workflowData.TimeoutMinutes, "timeout-minutes: ")
+		stepLines = append(stepLines, fmt.Sprintf("        timeout-minutes: %s", timeoutValue))
+	} else {
+		stepLines = append(stepLines, fmt.Sprintf("        timeout-minutes: %d", int(constants.DefaultAgenticWorkflowTimeout/time.Minute)))
+	}
+
+	// Only pass the secrets this engine is allowed to see
+	filteredEnv := FilterEnvForSecrets(env, e.GetRequiredSecretNames(workflowData))
+	stepLines = FormatStepWithCommandAndEnv(stepLines, command, filteredEnv)
+
+	return append(steps, GitHubActionStep(stepLines))
+}
+
+// renderCommand expands the manifest command template for the workflow
+func (e *ManifestEngine) renderCommand(workflowData *WorkflowData, logFile string) string {
+	// Values are shell-quoted; args is a list of arguments and is quoted per argument
+	values := map[string]string{
+		"prompt_file": shellEscapeArg("/tmp/gh-aw/aw-prompts/prompt.txt"),
+		"mcp_config":  shellEscapeArg(e.mcpConfigFile()),
+		"log_file":    shellEscapeArg(logFile),
+	}
+
+	command := strings.TrimSpace(e.manifest.Execution.Command)
+	if workflowData.EngineConfig != nil {
+		if workflowData.EngineConfig.Command != "" {
+			// engine.command replaces the executable, keeping the manifest arguments
+			if fields := strings.Fields(command); len(fields) > 0 {
+				command = workflowData.EngineConfig.Command + strings.TrimPrefix(command, fields[0])
+			}
+		}
+		if workflowData.EngineConfig.Model != "" {
+			values["model"] = shellEscapeArg(workflowData.EngineConfig.Model)
+		}
+		if workflowData.EngineConfig.MaxTurns != "" {
+			values["max_turns"] = shellEscapeArg(workflowData.EngineConfig.MaxTurns)
+		}
+		if len(workflowData.EngineConfig.Args) > 0 {
+			values["args"] = shellJoinArgs(workflowData.EngineConfig.Args)
+		}
+	}
+
+	// Drop placeholders that render empty together with the whitespace before them, so
+	// optional arguments leave no gaps while the rest of the command stays verbatim
+	command = emptyManifestPlaceholderPattern.ReplaceAllStringFunc(command, func(match string) string {
+		name := manifestPlaceholderPattern.FindStringSubmatch(match)[1]
+		if values[name] == "" {
+			return ""
+		}
+		return match
+	})
+	rendered, _ := substituteManifestPlaceholders(command, values).(string)
+	return strings.TrimSpace(rendered)
+}
+
+// buildAWFArgs returns the AWF command and arguments used to wrap the agent command
+func (e *ManifestEngine) buildAWFArgs(workflowData *WorkflowData) (string, []string) {
+	firewallConfig := getFirewallConfig(workflowData)
+	agentConfig := getAgentConfig(workflowData)
+
+	awfLogLevel := "info"
+	if firewallConfig != nil && firewallConfig.LogLevel != "" {
+		awfLogLevel = firewallConfig.LogLevel
+	}
+
+	allowedDomains := mergeDomainsWithNetworkToolsAndRuntimes(e.manifest.AllowedDomains, workflowData.NetworkPermissions, workflowData.Tools, workflowData.Runtimes)
+
+	awfArgs := []string{"--enable-chroot", "--env-all", "--container-workdir", "\"${GITHUB_WORKSPACE}\""}
+	if agentConfig != nil {
+		for _, mount := range agentConfig.Mounts {
+			awfArgs = append(awfArgs, "--mount", mount)
+		}
+	}
+	awfArgs = append(awfArgs, "--allow-domains", allowedDomains)
+	if blockedDomains := formatBlockedDomains(workflowData.NetworkPermissions); blockedDomains != "" {
+		awfArgs = append(awfArgs, "--block-domains", blockedDomains)
+	}
+	awfArgs = append(awfArgs, "--log-level", awfLogLevel)
+	awfArgs = append(awfArgs, "--proxy-logs-dir", "/tmp/gh-aw/sandbox/firewall/logs")
+	if HasMCPServers(workflowData) {
+		awfArgs = append(awfArgs, "--enable-host-access")
+	}
+	awfArgs = append(awfArgs, "--image-tag", getAWFImageTag(firewallConfig))
+	awfArgs = append(awfArgs, "--skip-pull")
+	awfArgs = append(awfArgs, getSSLBumpArgs(firewallConfig)...)
+	if firewallConfig != nil {
+		awfArgs = append(awfArgs, firewallConfig.Args...)
+	}
+
+	awfCommand := "sudo -E awf"
+	if agentConfig != nil {
+		awfArgs = append(awfArgs, agentConfig.Args...)
+		if agentConfig.Command != "" {
+			awfCommand = agentConfig.Command
+		}
+	}
+	return awfCommand, awfArgs
+}
+
+// RenderMCPConfig renders the MCP configuration in the manifest's schema flavour
+func (e *ManifestEngine) RenderMCPConfig(yaml *strings.Builder, tools map[string]any, mcpTools []string, workflowData *WorkflowData) {
+	configFile := e.mcpConfigFile()
+	copilotFlavour := e.manifest.MCP.Format == EngineManifestMCPFormatCopilot
+	manifestEngineLog.Printf("Rendering MCP config for manifest engine %s: path=%s, copilot_flavour=%v", e.id, configFile, copilotFlavour)
+
+	if configFile != defaultManifestMCPConfigFile {
+		fmt.Fprintf(yaml, "          mkdir -p %s\n", path.Dir(configFile))
+	}
+
+	createRenderer := func(isLast bool) *MCPConfigRendererUnified {
+		return NewMCPConfigRenderer(MCPRendererOptions{
+			IncludeCopilotFields: copilotFlavour,
+			InlineArgs:           copilotFlavour,
+			Format:               "json",
+			IsLast:               isLast,
+			ActionMode:           GetActionModeFromWorkflowData(workflowData),
+			ImagePins:            getImagePinsFromWorkflowData(workflowData),
+		})
+	}
+
+	_ = RenderJSONMCPConfig(yaml, tools, mcpTools, workflowData, JSONMCPConfigOptions{
+		ConfigPath:    configFile,
+		GatewayConfig: buildMCPGatewayConfig(workflowData),
+		Renderers: MCPToolRenderers{
+			RenderGitHub: func(yaml *strings.Builder, githubTool any, isLast bool, workflowData *WorkflowData) {
+				createRenderer(isLast).RenderGitHubMCP(yaml, githubTool, workflowData)
+			},
+			RenderPlaywright: func(yaml *strings.Builder, playwrightTool any, isLast bool) {
+				createRenderer(isLast).RenderPlaywrightMCP(yaml, playwrightTool)
+			},
+			RenderSerena: func(yaml *strings.Builder, serenaTool any, isLast bool) {
+				createRenderer(isLast).RenderSerenaMCP(yaml, serenaTool)
+			},
+			RenderCacheMemory: func(yaml *strings.Builder, isLast bool, workflowData *WorkflowData) {
+				// Cache-memory is a simple file share, not an MCP server
+			},
+			RenderAgenticWorkflows: func(yaml *strings.Builder, isLast bool) {
+				createRenderer(isLast).RenderAgenticWorkflowsMCP(yaml)
+			},
+			RenderSafeOutputs: func(yaml *strings.Builder, isLast bool, workflowData *WorkflowData) {
+				createRenderer(isLast).RenderSafeOutputsMCP(yaml, workflowData)
+			},
+			RenderSafeInputs: func(yaml *strings.Builder, safeInputs *SafeInputsConfig, isLast bool) {
+				createRenderer(isLast).RenderSafeInputsMCP(yaml, safeInputs, workflowData)
+			},
+			RenderRepoMemory: func(yaml *strings.Builder, isLast bool, workflowData *WorkflowData) {
+				createRenderer(isLast).RenderRepoMemoryMCP(yaml, workflowData)
+			},
+			RenderWebFetch: func(yaml *strings.Builder, isLast bool) {
+				renderMCPFetchServerConfig(yaml, "json", "              ", isLast, copilotFlavour)
+			},
+			RenderCustomMCPConfig: func(yaml *strings.Builder, toolName string, toolConfig map[string]any, isLast bool) error {
+				return renderCustomMCPConfigWrapperWithContext(yaml, toolName, toolConfig, isLast, workflowData)
+			},
+		},
+		FilterTool: func(toolName string) bool {
+			return toolName != "cache-memory"
+		},
+	})
+}
+
+// ParseLogMetrics extracts metrics from the agent log using the manifest's log patterns
+func (e *ManifestEngine) ParseLogMetrics(logContent string, verbose bool) LogMetrics {
+	manifestEngineLog.Printf("Parsing manifest engine log metrics: engine=%s, log_size=%d bytes", e.id, len(logContent))
+
+	var metrics LogMetrics
+	var turns, tokenUsage int
+	var currentSequence []string
+	toolCallMap := make(map[string]*ToolCallInfo)
+
+	for _, line := range strings.Split(logContent, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if e.turnPattern != nil && e.turnPattern.MatchString(line) {
+			turns++
+			if len(currentSequence) > 0 {
+				metrics.ToolSequences = append(metrics.ToolSequences, currentSequence)
+				currentSequence = nil
+			}
+		}
+		if e.tokenUsagePattern != nil {
+			if match := e.tokenUsagePattern.FindStringSubmatch(line); len(match) > 1 {
+				if tokens, err := strconv.Atoi(strings.ReplaceAll(match[1], ",", "")); err == nil {
+					tokenUsage += tokens
+				}
+			}
+		}
+		if e.costPattern != nil {
+			if match := e.costPattern.FindStringSubmatch(line); len(match) > 1 {
+				if cost, err := strconv.ParseFloat(match[1], 64); err == nil {
+					metrics.EstimatedCost += cost
+				}
+			}
+		}
+		if e.toolCallPattern != nil {
+			if match := e.toolCallPattern.FindStringSubmatch(line); len(match) > 1 && match[1] != "" {
+				name := match[1]
+				if info, exists := toolCallMap[name]; exists {
+					info.CallCount++
+				} else {
+					toolCallMap[name] = &ToolCallInfo{Name: name, CallCount: 1}
+				}
+				currentSequence = append(currentSequence, name)
+			}
+		}
+	}
+
+	FinalizeToolMetrics(FinalizeToolMetricsOptions{
+		Metrics:         &metrics,
+		ToolCallMap:     toolCallMap,
+		CurrentSequence: currentSequence,
+		Turns:           turns,
+		TokenUsage:      tokenUsage,
+	})
+
+	if verbose {
+		manifestEngineLog.Printf("Manifest engine metrics: turns=%d, tokens=%d, cost=%.4f, tools=%d", metrics.Turns, metrics.TokenUsage, metrics.EstimatedCost, len(metrics.ToolCalls))
+	}
+	return metrics
+}
+
+// substituteInstallStepPlaceholders replaces the placeholders of an install step. Values are
+// shell-quoted in the run script and inserted as they are elsewhere (uses, with, env).
+func substituteInstallStepPlaceholders(step any, values map[string]string) any {
+	stepMap, ok := step.(map[string]any)
+	if !ok {
+		return substituteManifestPlaceholders(step, values)
+	}
+
+	quoted := make(map[string]string, len(values))
+	for name, value := range values {
+		quoted[name] = shellEscapeArg(value)
+	}
+
+	result := make(map[string]any, len(stepMap))
+	for key, item := range stepMap {
+		if key == "run" {
+			result[key] = substituteManifestPlaceholders(item, quoted)
+		} else {
+			result[key] = substituteManifestPlaceholders(item, values)
+		}
+	}
+	return result
+}
+
+// substituteManifestPlaceholders replaces {{name}} placeholders in strings, maps and slices.
+// Unset placeholders expand to nothing.
+func substituteManifestPlaceholders(value any, values map[string]string) any {
+	switch v := value.(type) {
+	case string:
+		return manifestPlaceholderPattern.ReplaceAllStringFunc(v, func(match string) string {
+			return values[manifestPlaceholderPattern.FindStringSubmatch(match)[1]]
+		})
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[key] = substituteManifestPlaceholders(item, values)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = substituteManifestPlaceholders(item, values)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+var manifestPlaceholderPattern = regexp.MustCompile(`\{\{\s*(prompt_file|mcp_config|log_file|model|max_turns|args|version)\s*\}\}`)
+
+// emptyManifestPlaceholderPattern matches a placeholder with the horizontal whitespace before it
+var emptyManifestPlaceholderPattern = regexp.MustCompile(`[ \t]*` + manifestPlaceholderPattern.String())
@@ -161,7 +161,7 @@ func renderSafeOutputsMCPConfigWithOptions(yaml *strings.Builder, isLast bool, i
 // renderAgenticWorkflowsMCPConfigWithOptions generates the Agentic Workflows MCP server configuration with engine-specific options
 // Per MCP Gateway Specification v1.0.0 section 3.2.1, stdio-based MCP servers MUST be containerized.
 // Uses MCP Gateway spec format: container, entrypoint, entrypointArgs, and mounts fields.
-func renderAgenticWorkflowsMCPConfigWithOptions(yaml *strings.Builder, isLast bool, includeCopilotFields bool, actionMode ActionMode) {
+func renderAgenticWorkflowsMCPConfigWithOptions(yaml *strings.Builder, isLast bool, includeCopilotFields bool, actionMode ActionMode, imagePins map[string]string) {
 	// Environment variables: map of env var name to value (literal) or source variable (reference)
 	envVars := []struct {
 		name      string
@@ -205,7 +205,7 @@ func renderAgenticWorkflowsMCPConfigWithOptions(yaml *strings.Builder, isLast bo
 		mounts = []string{constants.DefaultGhAwMount, constants.DefaultGhBinaryMount, constants.DefaultWorkspaceMount, constants.DefaultTmpGhAwMount}
 	}
 
-	yaml.WriteString("                \"container\": \"" + containerImage + "\",\n")
+	yaml.WriteString("                \"container\": \"" + pinnedImage(containerImage, imagePins) + "\",\n")
 
 	// Only write entrypoint if it's specified (release mode)
 	// In dev mode, use the container's default ENTRYPOINT
@@ -58,6 +58,7 @@ func renderCustomMCPConfigWrapperWithContext(yaml *strings.Builder, toolName str
 		IndentLevel:              "                ",
 		Format:                   "json",
 		RewriteLocalhostToDocker: rewriteLocalhost,
+		ImagePins:                getImagePinsFromWorkflowData(workflowData),
 	}
 
 	err := renderSharedMCPConfig(yaml, toolName, toolConfig, renderer)
@@ -241,7 +242,7 @@ func renderSharedMCPConfig(yaml *strings.Builder, toolName string, toolConfig ma
 			}
 			// Container field - per MCP Gateway Specification v1.0.0 section 4.1.2
 			// Required for stdio servers (containerized servers)
-			fmt.Fprintf(yaml, "%s\"container\": \"%s\"%s\n", renderer.IndentLevel, mcpConfig.Container, comma)
+			fmt.Fprintf(yaml, "%s\"container\": \"%s\"%s\n", renderer.IndentLevel, pinnedImage(mcpConfig.Container, renderer.ImagePins), comma)
 		case "entrypoint":
 			comma := ","
 			if isLast {
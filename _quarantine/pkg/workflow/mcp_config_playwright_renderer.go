@@ -72,13 +72,13 @@ var mcpPlaywrightLog = logger.New("workflow:mcp_config_playwright_renderer")
 // This is a shared function used by both Claude and Custom engines
 func renderPlaywrightMCPConfig(yaml *strings.Builder, playwrightConfig *PlaywrightToolConfig, isLast bool) {
 	mcpPlaywrightLog.Print("Rendering Playwright MCP configuration")
-	renderPlaywrightMCPConfigWithOptions(yaml, playwrightConfig, isLast, false, false)
+	renderPlaywrightMCPConfigWithOptions(yaml, playwrightConfig, isLast, false, false, nil)
 }
 
 // renderPlaywrightMCPConfigWithOptions generates the Playwright MCP server configuration with engine-specific options
 // Per MCP Gateway Specification v1.0.0 section 3.2.1, stdio-based MCP servers MUST be containerized.
 // Uses MCP Gateway spec format: container, entrypointArgs, mounts, and args fields.
-func renderPlaywrightMCPConfigWithOptions(yaml *strings.Builder, playwrightConfig *PlaywrightToolConfig, isLast bool, includeCopilotFields bool, inlineArgs bool) {
+func renderPlaywrightMCPConfigWithOptions(yaml *strings.Builder, playwrightConfig *PlaywrightToolConfig, isLast bool, includeCopilotFields bool, inlineArgs bool, imagePins map[string]string) {
 	args := generatePlaywrightDockerArgs(playwrightConfig)
 	customArgs := getPlaywrightCustomArgs(playwrightConfig)
 
@@ -102,7 +102,7 @@ func renderPlaywrightMCPConfigWithOptions(yaml *strings.Builder, playwrightConfi
 	}
 
 	// MCP Gateway spec fields for containerized stdio servers
-	yaml.WriteString("                \"container\": \"" + playwrightImage + "\",\n")
+	yaml.WriteString("                \"container\": \"" + pinnedImage(playwrightImage, imagePins) + "\",\n")
 
 	// Docker runtime args (goes before container image in docker run command)
 	// These are additional flags for docker run like --init and --network
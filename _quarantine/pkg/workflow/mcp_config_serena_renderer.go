@@ -99,7 +99,7 @@ func selectSerenaContainer(serenaTool any) string {
 // Supports two modes:
 // - "docker" (default): Uses Docker container with stdio transport (ghcr.io/github/serena-mcp-server:latest)
 // - "local": Uses local uvx with HTTP transport on fixed port
-func renderSerenaMCPConfigWithOptions(yaml *strings.Builder, serenaTool any, isLast bool, includeCopilotFields bool, inlineArgs bool) {
+func renderSerenaMCPConfigWithOptions(yaml *strings.Builder, serenaTool any, isLast bool, includeCopilotFields bool, inlineArgs bool, imagePins map[string]string) {
 	customArgs := getSerenaCustomArgs(serenaTool)
 
 	// Determine the mode - check if serenaTool is a map with mode field
@@ -129,7 +129,7 @@ func renderSerenaMCPConfigWithOptions(yaml *strings.Builder, serenaTool any, isL
 
 		// Select the appropriate Serena container based on requested languages
 		containerImage := selectSerenaContainer(serenaTool)
-		yaml.WriteString("                \"container\": \"" + containerImage + ":latest\",\n")
+		yaml.WriteString("                \"container\": \"" + pinnedImage(containerImage+":latest", imagePins) + "\",\n")
 
 		// Docker runtime args (--network host for network access)
 		if inlineArgs {
@@ -38,6 +38,8 @@ type MCPConfigRenderer struct {
 	// RewriteLocalhostToDocker indicates if localhost URLs should be rewritten to host.docker.internal
 	// This is needed when the agent runs inside a firewall container and needs to access MCP servers on the host
 	RewriteLocalhostToDocker bool
+	// ImagePins maps container image references to the digests they are pinned to
+	ImagePins map[string]string
 }
 
 // ToolConfig represents a tool configuration interface for type safety
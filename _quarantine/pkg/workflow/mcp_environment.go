@@ -125,6 +125,12 @@ func collectMCPEnvironmentVariables(tools map[string]any, mcpTools []string, wor
 		}
 	}
 
+	// Check for repo-memory MCP server env vars
+	if hasRepoMemoryMCP(workflowData) {
+		envVars["GH_AW_REPO_MEMORY_PORT"] = "${{ steps.repo-memory-start.outputs.port }}"
+		envVars["GH_AW_REPO_MEMORY_API_KEY"] = "${{ steps.repo-memory-start.outputs.api_key }}"
+	}
+
 	// Check for safe-outputs env vars
 	// Only add env vars if safe-outputs is actually enabled
 	// This prevents referencing step outputs that don't exist when safe-outputs isn't used
@@ -171,7 +177,7 @@ func collectMCPEnvironmentVariables(tools map[string]any, mcpTools []string, wor
 		// Skip standard tools that are handled above
 		if toolName == "github" || toolName == "playwright" || toolName == "serena" ||
 			toolName == "cache-memory" || toolName == "agentic-workflows" ||
-			toolName == "safe-outputs" || toolName == "safe-inputs" {
+			toolName == "safe-outputs" || toolName == "safe-inputs" || toolName == "repo-memory" {
 			continue
 		}
 
@@ -18,6 +18,7 @@
 //   - Domain for gateway access (localhost or host.docker.internal)
 //   - API key for authentication
 //   - Volume mounts for workspace and temporary directories
+//   - Record mode, capturing full MCP request/response payloads for 'gh aw mcp replay'
 //
 // Configuration flow:
 //  1. ensureDefaultMCPGatewayConfig: Sets defaults if not provided
@@ -43,6 +44,7 @@
 //	    mounts:
 //	      - /opt:/opt:ro
 //	      - /tmp:/tmp:rw
+//	    record: true
 package workflow
 
 import (
@@ -101,6 +103,12 @@ func ensureDefaultMCPGatewayConfig(workflowData *WorkflowData) {
 		mcpGatewayConfigLog.Print("Setting default gateway payloadDir")
 		workflowData.SandboxConfig.MCP.PayloadDir = constants.DefaultMCPGatewayPayloadDir
 	}
+
+	// Recordings are written under /tmp/gh-aw so they are redacted and uploaded with the MCP logs
+	if workflowData.SandboxConfig.MCP.Record && workflowData.SandboxConfig.MCP.RecordDir == "" {
+		mcpGatewayConfigLog.Print("Enabling gateway record mode")
+		workflowData.SandboxConfig.MCP.RecordDir = constants.MCPGatewayRecordDir
+	}
 }
 
 // buildMCPGatewayConfig builds the gateway configuration for inclusion in MCP config files
@@ -122,12 +130,13 @@ func buildMCPGatewayConfig(workflowData *WorkflowData) *MCPGatewayRuntimeConfig
 	// Return gateway config with required fields populated
 	// Use ${...} syntax for environment variable references that will be resolved by the gateway at runtime
 	// Per MCP Gateway Specification v1.0.0 section 4.2, variable expressions use "${VARIABLE_NAME}" syntax
-	return &MCPGatewayRuntimeConfig{
+	config := &MCPGatewayRuntimeConfig{
 		Port:       int(DefaultMCPGatewayPort),   // Will be formatted as "${MCP_GATEWAY_PORT}" in renderer
 		Domain:     "${MCP_GATEWAY_DOMAIN}",      // Gateway variable expression
 		APIKey:     "${MCP_GATEWAY_API_KEY}",     // Gateway variable expression
 		PayloadDir: "${MCP_GATEWAY_PAYLOAD_DIR}", // Gateway variable expression for payload directory
 	}
+	return config
 }
 
 // isSandboxDisabled checks if sandbox features are completely disabled (sandbox: false)
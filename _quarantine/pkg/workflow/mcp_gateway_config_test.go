@@ -223,6 +223,20 @@ func TestBuildMCPGatewayConfig(t *testing.T) {
 				PayloadDir: "${MCP_GATEWAY_PAYLOAD_DIR}",
 			},
 		},
+		{
+			name: "record mode is not part of the gateway config",
+			workflowData: &WorkflowData{
+				SandboxConfig: &SandboxConfig{
+					MCP: &MCPGatewayRuntimeConfig{Record: true},
+				},
+			},
+			expected: &MCPGatewayRuntimeConfig{
+				Port:       int(DefaultMCPGatewayPort),
+				Domain:     "${MCP_GATEWAY_DOMAIN}",
+				APIKey:     "${MCP_GATEWAY_API_KEY}",
+				PayloadDir: "${MCP_GATEWAY_PAYLOAD_DIR}",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -236,6 +250,7 @@ func TestBuildMCPGatewayConfig(t *testing.T) {
 				assert.Equal(t, tt.expected.Domain, result.Domain, "Domain should match")
 				assert.Equal(t, tt.expected.APIKey, result.APIKey, "APIKey should match")
 				assert.Equal(t, tt.expected.PayloadDir, result.PayloadDir, "PayloadDir should match")
+				assert.Equal(t, tt.expected.RecordDir, result.RecordDir, "RecordDir should match")
 			}
 		})
 	}
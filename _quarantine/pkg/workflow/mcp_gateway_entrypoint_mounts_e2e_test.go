@@ -5,9 +5,12 @@ package workflow
 import (
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/stringutil"
 	"github.com/github/gh-aw/pkg/testutil"
 	"github.com/stretchr/testify/assert"
@@ -314,3 +317,88 @@ Test that mounts with environment variables are properly handled.
 	assert.True(t, hasWorkspaceMount, "Compiled YAML should contain mount with environment variable")
 	assert.Contains(t, yamlStr, "/tmp:/tmp:rw", "Compiled YAML should contain regular mount")
 }
+
+// TestMCPGatewayRecordE2E tests end-to-end compilation with record mode enabled
+func TestMCPGatewayRecordE2E(t *testing.T) {
+	markdown := `---
+on: workflow_dispatch
+engine: copilot
+sandbox:
+  mcp:
+    container: ghcr.io/github/gh-aw-mcpg
+    record: true
+---
+
+# Test Workflow
+
+Test that record mode configures the gateway recordings directory.
+`
+
+	tmpDir := testutil.TempDir(t, "record-test")
+	testFile := filepath.Join(tmpDir, "test-record.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0644), "Failed to write test file")
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(testFile), "Compilation should succeed")
+
+	result, err := os.ReadFile(stringutil.MarkdownToLockFile(testFile))
+	require.NoError(t, err, "Failed to read lock file")
+	yamlStr := string(result)
+
+	assert.Contains(t, yamlStr, `export MCP_GATEWAY_RECORD_DIR="/tmp/gh-aw/mcp-logs/recordings"`, "Recordings directory should be exported")
+	assert.Contains(t, yamlStr, `export MCP_GATEWAY_RECORD_PORT="81"`, "Recording proxy should listen on the port after the gateway port")
+	assert.NotContains(t, yamlStr, " -e MCP_GATEWAY_RECORD_DIR", "Recordings are written by the recording proxy, not the gateway container")
+	assert.NotContains(t, yamlStr, "recordDir", "Gateway config should not carry recording settings")
+
+	// Recordings must be written before secrets are redacted and the MCP logs uploaded
+	redactIndex := strings.Index(yamlStr, "Redact secrets in logs")
+	assert.Positive(t, redactIndex, "Secret redaction step should be present")
+	assert.Less(t, strings.Index(yamlStr, "MCP_GATEWAY_RECORD_DIR"), redactIndex, "Recording should be configured before redaction")
+}
+
+// TestMCPGatewayWithoutRecordE2E tests that recording is off by default
+func TestMCPGatewayWithoutRecordE2E(t *testing.T) {
+	markdown := `---
+on: workflow_dispatch
+engine: copilot
+---
+
+# Test Workflow
+`
+
+	tmpDir := testutil.TempDir(t, "no-record-test")
+	testFile := filepath.Join(tmpDir, "test-no-record.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0644), "Failed to write test file")
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(testFile), "Compilation should succeed")
+
+	result, err := os.ReadFile(stringutil.MarkdownToLockFile(testFile))
+	require.NoError(t, err, "Failed to read lock file")
+	assert.NotContains(t, string(result), "MCP_GATEWAY_RECORD_DIR", "Recording should be disabled by default")
+	assert.NotContains(t, string(result), "recordDir", "Recording should be disabled by default")
+}
+
+// TestMCPGatewayRecordDirIsRedacted tests that the recordings directory and file type are covered
+// by the files the secret redaction script scans, so recorded payloads are redacted before upload
+func TestMCPGatewayRecordDirIsRedacted(t *testing.T) {
+	_, thisFile, _, ok := runtime.Caller(0)
+	require.True(t, ok, "Failed to get current file path")
+	scriptPath := filepath.Join(filepath.Dir(thisFile), "..", "..", "actions", "setup", "js", "redact_secrets.cjs")
+	script, err := os.ReadFile(scriptPath)
+	require.NoError(t, err, "Failed to read redact_secrets.cjs")
+
+	scanDirs := regexp.MustCompile(`findFiles\("([^"]+)"`).FindAllStringSubmatch(string(script), -1)
+	require.NotEmpty(t, scanDirs, "redact_secrets.cjs should scan directories with findFiles")
+	inScanDir := false
+	for _, match := range scanDirs {
+		if strings.HasPrefix(constants.MCPGatewayRecordDir, strings.TrimSuffix(match[1], "/")+"/") {
+			inScanDir = true
+		}
+	}
+	assert.True(t, inScanDir, "Record dir %s should be inside a directory scanned by the redaction step", constants.MCPGatewayRecordDir)
+
+	extensions := regexp.MustCompile(`targetExtensions = \[([^\]]*)\]`).FindStringSubmatch(string(script))
+	require.Len(t, extensions, 2, "redact_secrets.cjs should declare targetExtensions")
+	assert.Contains(t, extensions[1], `".jsonl"`, "Recording files (.jsonl) should be scanned by the redaction step")
+}
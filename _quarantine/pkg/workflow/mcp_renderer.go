@@ -100,6 +100,8 @@ type MCPRendererOptions struct {
 	IsLast bool
 	// ActionMode indicates the action mode for workflow compilation (dev, release, script)
 	ActionMode ActionMode
+	// ImagePins maps container image references to the digests they are pinned to
+	ImagePins map[string]string
 }
 
 // MCPConfigRendererUnified provides unified rendering methods for MCP configurations
@@ -185,6 +187,7 @@ func (r *MCPConfigRendererUnified) RenderGitHubMCP(yaml *strings.Builder, github
 			IncludeTypeField:   r.options.IncludeCopilotFields,
 			AllowedTools:       getGitHubAllowedTools(githubTool),
 			EffectiveToken:     "", // Token passed via env
+			ImagePins:          r.options.ImagePins,
 		})
 	}
 
@@ -208,7 +211,7 @@ func (r *MCPConfigRendererUnified) RenderPlaywrightMCP(yaml *strings.Builder, pl
 	}
 
 	// JSON format
-	renderPlaywrightMCPConfigWithOptions(yaml, playwrightConfig, r.options.IsLast, r.options.IncludeCopilotFields, r.options.InlineArgs)
+	renderPlaywrightMCPConfigWithOptions(yaml, playwrightConfig, r.options.IsLast, r.options.IncludeCopilotFields, r.options.InlineArgs, r.options.ImagePins)
 }
 
 // renderPlaywrightTOML generates Playwright MCP configuration in TOML format
@@ -223,7 +226,7 @@ func (r *MCPConfigRendererUnified) renderPlaywrightTOML(yaml *strings.Builder, p
 
 	yaml.WriteString("          \n")
 	yaml.WriteString("          [mcp_servers.playwright]\n")
-	yaml.WriteString("          container = \"" + playwrightImage + "\"\n")
+	yaml.WriteString("          container = \"" + pinnedImage(playwrightImage, r.options.ImagePins) + "\"\n")
 
 	// Docker runtime args (goes before container image in docker run command)
 	yaml.WriteString("          args = [\n")
@@ -265,7 +268,7 @@ func (r *MCPConfigRendererUnified) RenderSerenaMCP(yaml *strings.Builder, serena
 	}
 
 	// JSON format
-	renderSerenaMCPConfigWithOptions(yaml, serenaTool, r.options.IsLast, r.options.IncludeCopilotFields, r.options.InlineArgs)
+	renderSerenaMCPConfigWithOptions(yaml, serenaTool, r.options.IsLast, r.options.IncludeCopilotFields, r.options.InlineArgs, r.options.ImagePins)
 }
 
 // renderSerenaTOML generates Serena MCP configuration in TOML format
@@ -294,7 +297,7 @@ func (r *MCPConfigRendererUnified) renderSerenaTOML(yaml *strings.Builder, seren
 		// Docker mode: use stdio transport (default)
 		// Select the appropriate Serena container based on requested languages
 		containerImage := selectSerenaContainer(serenaTool)
-		yaml.WriteString("          container = \"" + containerImage + ":latest\"\n")
+		yaml.WriteString("          container = \"" + pinnedImage(containerImage+":latest", r.options.ImagePins) + "\"\n")
 
 		// Docker runtime args (--network host for network access)
 		yaml.WriteString("          args = [\n")
@@ -405,7 +408,7 @@ func (r *MCPConfigRendererUnified) RenderAgenticWorkflowsMCP(yaml *strings.Build
 	}
 
 	// JSON format
-	renderAgenticWorkflowsMCPConfigWithOptions(yaml, r.options.IsLast, r.options.IncludeCopilotFields, r.options.ActionMode)
+	renderAgenticWorkflowsMCPConfigWithOptions(yaml, r.options.IsLast, r.options.IncludeCopilotFields, r.options.ActionMode, r.options.ImagePins)
 }
 
 // renderAgenticWorkflowsTOML generates Agentic Workflows MCP configuration in TOML format
@@ -436,7 +439,7 @@ func (r *MCPConfigRendererUnified) renderAgenticWorkflowsTOML(yaml *strings.Buil
 		mounts = []string{constants.DefaultGhAwMount, constants.DefaultGhBinaryMount, constants.DefaultWorkspaceMount, constants.DefaultTmpGhAwMount}
 	}
 
-	yaml.WriteString("          container = \"" + containerImage + "\"\n")
+	yaml.WriteString("          container = \"" + pinnedImage(containerImage, r.options.ImagePins) + "\"\n")
 
 	// Only write entrypoint if it's specified (release mode)
 	// In dev mode, use the container's default ENTRYPOINT
@@ -519,7 +522,7 @@ func (r *MCPConfigRendererUnified) renderGitHubTOML(yaml *strings.Builder, githu
 		mounts := getGitHubMounts(githubTool)
 
 		// MCP Gateway spec fields for containerized stdio servers
-		yaml.WriteString("          container = \"ghcr.io/github/github-mcp-server:" + githubDockerImageVersion + "\"\n")
+		yaml.WriteString("          container = \"" + pinnedImage("ghcr.io/github/github-mcp-server:"+githubDockerImageVersion, r.options.ImagePins) + "\"\n")
 
 		// Append custom args if present (these are Docker runtime args, go before container image)
 		if len(customArgs) > 0 {
@@ -627,6 +630,7 @@ type MCPToolRenderers struct {
 	RenderAgenticWorkflows func(yaml *strings.Builder, isLast bool)
 	RenderSafeOutputs      func(yaml *strings.Builder, isLast bool, workflowData *WorkflowData)
 	RenderSafeInputs       func(yaml *strings.Builder, safeInputs *SafeInputsConfig, isLast bool)
+	RenderRepoMemory       func(yaml *strings.Builder, isLast bool, workflowData *WorkflowData)
 	RenderWebFetch         func(yaml *strings.Builder, isLast bool)
 	RenderCustomMCPConfig  RenderCustomMCPToolConfigHandler
 }
@@ -669,6 +673,8 @@ type GitHubMCPDockerOptions struct {
 	EffectiveToken string
 	// Mounts specifies volume mounts for the GitHub MCP server container (format: "host:container:mode")
 	Mounts []string
+	// ImagePins maps container image references to the digests they are pinned to
+	ImagePins map[string]string
 }
 
 // RenderGitHubMCPDockerConfig renders the GitHub MCP server configuration for Docker (local mode).
@@ -686,7 +692,7 @@ func RenderGitHubMCPDockerConfig(yaml *strings.Builder, options GitHubMCPDockerO
 	}
 
 	// MCP Gateway spec fields for containerized stdio servers
-	yaml.WriteString("                \"container\": \"ghcr.io/github/github-mcp-server:" + options.DockerImageVersion + "\",\n")
+	yaml.WriteString("                \"container\": \"" + pinnedImage("ghcr.io/github/github-mcp-server:"+options.DockerImageVersion, options.ImagePins) + "\",\n")
 
 	// Append custom args if present (these are Docker runtime args, go before container image)
 	if len(options.CustomArgs) > 0 {
@@ -927,6 +933,10 @@ func RenderJSONMCPConfig(
 			if options.Renderers.RenderSafeInputs != nil {
 				options.Renderers.RenderSafeInputs(&configBuilder, workflowData.SafeInputs, isLast)
 			}
+		case "repo-memory":
+			if options.Renderers.RenderRepoMemory != nil {
+				options.Renderers.RenderRepoMemory(&configBuilder, isLast, workflowData)
+			}
 		case "web-fetch":
 			options.Renderers.RenderWebFetch(&configBuilder, isLast)
 		default:
@@ -947,10 +957,9 @@ func RenderJSONMCPConfig(
 		fmt.Fprintf(&configBuilder, "              \"apiKey\": \"%s\"", options.GatewayConfig.APIKey)
 		// Add payloadDir if specified
 		if options.GatewayConfig.PayloadDir != "" {
-			fmt.Fprintf(&configBuilder, ",\n              \"payloadDir\": \"%s\"\n", options.GatewayConfig.PayloadDir)
-		} else {
-			configBuilder.WriteString("\n")
+			fmt.Fprintf(&configBuilder, ",\n              \"payloadDir\": \"%s\"", options.GatewayConfig.PayloadDir)
 		}
+		configBuilder.WriteString("\n")
 		configBuilder.WriteString("            }\n")
 	} else {
 		configBuilder.WriteString("            }\n")
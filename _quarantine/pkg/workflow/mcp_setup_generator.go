@@ -111,6 +111,11 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 		mcpTools = append(mcpTools, "safe-inputs")
 	}
 
+	// Check if any repo-memory is exposed through the repo-memory MCP server
+	if hasRepoMemoryMCP(workflowData) {
+		mcpTools = append(mcpTools, "repo-memory")
+	}
+
 	// Populate dispatch-workflow file mappings before generating config
 	// This ensures workflow_files is available in the config.json
 	populateDispatchWorkflowFiles(workflowData, c.markdownPath)
@@ -133,7 +138,7 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 
 	// Collect all Docker images that will be used and generate download step
 	dockerImages := collectDockerImages(tools, workflowData, c.actionMode)
-	generateDownloadDockerImagesStep(yaml, dockerImages)
+	generateDownloadDockerImagesStep(yaml, dockerImages, workflowData.ImagePins)
 
 	// If no MCP tools, no configuration needed
 	if len(mcpTools) == 0 {
@@ -370,6 +375,19 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 					yaml.WriteString("          " + line + "\n")
 				}
 				fmt.Fprintf(yaml, "          EOFGO_%s\n", toolName)
+			} else if toolConfig.TS != "" {
+				// TypeScript tool, written verbatim and run with Node.js type stripping
+				toolScript, err := generateSafeInputTypeScriptToolScript(toolConfig)
+				if err != nil {
+					// Sources are checked when the configuration is extracted
+					mcpSetupGeneratorLog.Printf("Skipping TypeScript tool: %v", err)
+					continue
+				}
+				fmt.Fprintf(yaml, "          cat > /opt/gh-aw/safe-inputs/%s.mts << 'EOFTS_%s'\n", toolName, toolName)
+				for _, line := range strings.Split(toolScript, "\n") {
+					yaml.WriteString("          " + line + "\n")
+				}
+				fmt.Fprintf(yaml, "          EOFTS_%s\n", toolName)
 			}
 		}
 		yaml.WriteString("          \n")
@@ -429,6 +447,9 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 		yaml.WriteString("          \n")
 	}
 
+	// Write and start the repo-memory MCP server if any memory enables mcp
+	generateRepoMemoryMCPSetup(yaml, workflowData)
+
 	// Generate Serena MCP server startup steps if in local mode
 	if isSerenaInLocalMode(workflowData.ParsedTools) {
 		generateSerenaLocalModeSteps(yaml)
@@ -510,6 +531,14 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 		yaml.WriteString("          export MCP_GATEWAY_PAYLOAD_DIR=\"" + payloadDir + "\"\n")
 		yaml.WriteString("          mkdir -p \"${MCP_GATEWAY_PAYLOAD_DIR}\"\n")
 
+		// In record mode, start_mcp_gateway.sh runs the recording proxy on the next port and
+		// points the agent at it
+		if gatewayConfig.RecordDir != "" {
+			yaml.WriteString("          export MCP_GATEWAY_RECORD_DIR=\"" + gatewayConfig.RecordDir + "\"\n")
+			yaml.WriteString("          export MCP_GATEWAY_RECORD_PORT=\"" + fmt.Sprintf("%d", port+1) + "\"\n")
+			yaml.WriteString("          mkdir -p \"${MCP_GATEWAY_RECORD_DIR}\"\n")
+		}
+
 		yaml.WriteString("          export DEBUG=\"*\"\n")
 		yaml.WriteString("          \n")
 
@@ -543,6 +572,7 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 		} else {
 			containerImage += ":" + string(constants.DefaultMCPGatewayVersion)
 		}
+		containerImage = pinnedImage(containerImage, workflowData.ImagePins)
 
 		containerCmd := "docker run -i --rm --network host"
 		containerCmd += " -v /var/run/docker.sock:/var/run/docker.sock" // Enable docker-in-docker for MCP gateway
@@ -614,6 +644,11 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 			containerCmd += " -e GH_AW_SAFE_OUTPUTS_PORT"
 			containerCmd += " -e GH_AW_SAFE_OUTPUTS_API_KEY"
 		}
+		// Environment variables used by the repo-memory MCP server
+		if hasRepoMemoryMCP(workflowData) {
+			containerCmd += " -e GH_AW_REPO_MEMORY_PORT"
+			containerCmd += " -e GH_AW_REPO_MEMORY_API_KEY"
+		}
 		if len(gatewayConfig.Env) > 0 {
 			// Using functional helper to extract map keys
 			envVarNames := sliceutil.MapToSlice(gatewayConfig.Env)
@@ -631,7 +666,7 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 
 			// Mark standard environment variables as already added
 			standardEnvVars := []string{
-				"MCP_GATEWAY_PORT", "MCP_GATEWAY_DOMAIN", "MCP_GATEWAY_API_KEY", "MCP_GATEWAY_PAYLOAD_DIR", "DEBUG",
+				"MCP_GATEWAY_PORT", "MCP_GATEWAY_DOMAIN", "MCP_GATEWAY_API_KEY", "MCP_GATEWAY_PAYLOAD_DIR", "MCP_GATEWAY_RECORD_DIR", "MCP_GATEWAY_RECORD_PORT", "DEBUG",
 				"MCP_GATEWAY_LOG_DIR", "GH_AW_MCP_LOG_DIR", "GH_AW_SAFE_OUTPUTS",
 				"GH_AW_SAFE_OUTPUTS_CONFIG_PATH", "GH_AW_SAFE_OUTPUTS_TOOLS_PATH",
 				"GH_AW_ASSETS_BRANCH", "GH_AW_ASSETS_MAX_SIZE_KB", "GH_AW_ASSETS_ALLOWED_EXTS",
@@ -659,6 +694,10 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 				addedEnvVars["GH_AW_SAFE_OUTPUTS_PORT"] = true
 				addedEnvVars["GH_AW_SAFE_OUTPUTS_API_KEY"] = true
 			}
+			if hasRepoMemoryMCP(workflowData) {
+				addedEnvVars["GH_AW_REPO_MEMORY_PORT"] = true
+				addedEnvVars["GH_AW_REPO_MEMORY_API_KEY"] = true
+			}
 
 			// Mark gateway config environment variables as added
 			if len(gatewayConfig.Env) > 0 {
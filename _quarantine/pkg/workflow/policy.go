@@ -0,0 +1,505 @@
+// This file provides declarative organization policies evaluated against compiled workflows.
+//
+// Strict mode and the dangerous permissions check are fixed rule sets. A policy file lets an
+// organization declare its own rules, and every rule section is optional:
+//
+//	engines:
+//	  allowed: [copilot, claude]
+//	  models: ["gpt-5*", "claude-sonnet-*"]
+//	permissions:
+//	  max:
+//	    all: read       # applies to scopes that are not listed
+//	    issues: write
+//	mcp-servers:
+//	  allowed: [github, "internal-*"]
+//	containers:
+//	  allowed: ["ghcr.io/my-org/*", "node:*"]
+//	network:
+//	  forbidden-ecosystems: [python]
+//	threat-detection:
+//	  required: true
+//	safe-outputs:
+//	  max:
+//	    create-issue: 3
+//	roles:
+//	  required: true
+//	  allowed: [admin, maintainer]
+//
+// Patterns in allowlists use '*' as a wildcard that matches any sequence of characters.
+// A policy is loaded from a local path or from a workflowspec (owner/repo/path@ref), in which
+// case the download is cached in the ImportCache like remote imports. The compiler evaluates
+// the policy against WorkflowData and reports every violation through the error collector.
+
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/goccy/go-yaml"
+)
+
+var policyLog = logger.New("workflow:policy")
+
+// DefaultPolicyFile is the policy file, relative to the repository root, used when no policy is given
+const DefaultPolicyFile = ".github/aw/policy.yml"
+
+// Policy is a declarative organization policy for agentic workflows
+type Policy struct {
+	Engines         *EnginesPolicy         `yaml:"engines,omitempty"`
+	Permissions     *PermissionsPolicy     `yaml:"permissions,omitempty"`
+	MCPServers      *AllowlistPolicy       `yaml:"mcp-servers,omitempty"`
+	Containers      *AllowlistPolicy       `yaml:"containers,omitempty"`
+	Network         *NetworkPolicy         `yaml:"network,omitempty"`
+	ThreatDetection *ThreatDetectionPolicy `yaml:"threat-detection,omitempty"`
+	SafeOutputs     *SafeOutputsPolicy     `yaml:"safe-outputs,omitempty"`
+	Roles           *RolesPolicy           `yaml:"roles,omitempty"`
+
+	Source string `yaml:"-"` // Path or workflowspec the policy was loaded from
+}
+
+// EnginesPolicy restricts the engines and models workflows may use
+type EnginesPolicy struct {
+	Allowed []string `yaml:"allowed,omitempty"` // Allowed engine IDs
+	Models  []string `yaml:"models,omitempty"`  // Allowed model patterns (only checked when a model is set)
+}
+
+// PermissionsPolicy caps the permission level of each scope
+type PermissionsPolicy struct {
+	Max map[string]string `yaml:"max,omitempty"` // Scope -> maximum level ("all" applies to unlisted scopes)
+}
+
+// AllowlistPolicy restricts names or images to a list of patterns
+type AllowlistPolicy struct {
+	Allowed []string `yaml:"allowed"`
+}
+
+// NetworkPolicy restricts the network ecosystems workflows may allow
+type NetworkPolicy struct {
+	ForbiddenEcosystems []string `yaml:"forbidden-ecosystems,omitempty"`
+}
+
+// ThreatDetectionPolicy requires threat detection for workflows with safe outputs
+type ThreatDetectionPolicy struct {
+	Required bool `yaml:"required"`
+}
+
+// SafeOutputsPolicy caps the max count of each safe output type
+type SafeOutputsPolicy struct {
+	Max map[string]int `yaml:"max,omitempty"` // Safe output type (e.g. create-issue) -> maximum max
+}
+
+// RolesPolicy requires workflows to declare roles: and restricts the roles they may use
+type RolesPolicy struct {
+	Required bool     `yaml:"required,omitempty"` // roles: must be set explicitly in the frontmatter
+	Allowed  []string `yaml:"allowed,omitempty"`  // Roles that may trigger workflows (defaults count too)
+}
+
+// PolicyViolation is a single policy rule that a workflow does not satisfy
+type PolicyViolation struct {
+	Rule    string // Policy rule that was violated (e.g. engines.allowed)
+	Message string // Human readable description of the violation
+	Path    string // JSON path of the offending frontmatter value (e.g. /permissions/contents)
+}
+
+// Error formats the violation as an error message
+func (v PolicyViolation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+}
+
+// LoadPolicy loads a policy from a local path or from a workflowspec (owner/repo/path@ref).
+// Remote policies are downloaded through the import cache.
+func LoadPolicy(spec string, cache *parser.ImportCache) (*Policy, error) {
+	policyLog.Printf("Loading policy: %s", spec)
+
+	path := spec
+	if _, err := os.Stat(spec); err != nil {
+		if !isPolicyWorkflowSpec(spec) {
+			return nil, fmt.Errorf("policy file not found: %s", spec)
+		}
+		resolved, err := parser.ResolveIncludePath(spec, "", cache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch policy %s: %w", spec, err)
+		}
+		path = resolved
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy %s: %w", spec, err)
+	}
+	return ParsePolicy(content, spec)
+}
+
+// isPolicyWorkflowSpec reports whether a policy reference is a remote owner/repo/path@ref spec
+func isPolicyWorkflowSpec(spec string) bool {
+	pathPart, ref, found := strings.Cut(spec, "@")
+	if !found || ref == "" || strings.HasPrefix(pathPart, ".") || strings.HasPrefix(pathPart, "/") {
+		return false
+	}
+	return len(strings.Split(pathPart, "/")) >= 3
+}
+
+// ParsePolicy parses and validates a policy file
+func ParsePolicy(content []byte, source string) (*Policy, error) {
+	var policy Policy
+	if err := yaml.UnmarshalWithOptions(content, &policy, yaml.DisallowUnknownField()); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", source, err)
+	}
+	policy.Source = source
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", source, err)
+	}
+	policyLog.Printf("Parsed policy: source=%s", source)
+	return &policy, nil
+}
+
+// validate checks that the policy refers to known scopes, levels and safe outputs
+func (p *Policy) validate() error {
+	if p.Permissions != nil {
+		for _, scope := range slices.Sorted(maps.Keys(p.Permissions.Max)) {
+			if scope != "all" && convertStringToPermissionScope(scope) == "" {
+				return fmt.Errorf("unknown permission scope '%s' in permissions.max", scope)
+			}
+			switch PermissionLevel(p.Permissions.Max[scope]) {
+			case PermissionRead, PermissionWrite, PermissionNone:
+			default:
+				return fmt.Errorf("permissions.max.%s must be 'read', 'write' or 'none', got '%s'", scope, p.Permissions.Max[scope])
+			}
+		}
+	}
+	if p.SafeOutputs != nil {
+		known := make(map[string]bool)
+		for _, toolName := range safeOutputFieldMapping {
+			known[strings.ReplaceAll(toolName, "_", "-")] = true
+		}
+		for _, name := range slices.Sorted(maps.Keys(p.SafeOutputs.Max)) {
+			if !known[name] {
+				return fmt.Errorf("unknown safe output '%s' in safe-outputs.max", name)
+			}
+			if p.SafeOutputs.Max[name] < 1 {
+				return fmt.Errorf("safe-outputs.max.%s must be at least 1", name)
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the violations of the policy by a workflow, grouped by policy section
+func (p *Policy) Evaluate(workflowData *WorkflowData) []PolicyViolation {
+	var frontmatter map[string]any
+	if workflowData.FrontmatterYAML != "" {
+		if err := yaml.Unmarshal([]byte(workflowData.FrontmatterYAML), &frontmatter); err != nil {
+			policyLog.Printf("Failed to parse frontmatter for policy evaluation: %v", err)
+		}
+	}
+
+	var violations []PolicyViolation
+	violations = append(violations, p.evaluateEngines(workflowData)...)
+	violations = append(violations, p.evaluatePermissions(workflowData)...)
+	violations = append(violations, p.evaluateMCPServers(workflowData, frontmatter)...)
+	violations = append(violations, p.evaluateContainers(workflowData, frontmatter)...)
+	violations = append(violations, p.evaluateNetwork(workflowData)...)
+	violations = append(violations, p.evaluateThreatDetection(workflowData)...)
+	violations = append(violations, p.evaluateSafeOutputs(workflowData)...)
+	violations = append(violations, p.evaluateRoles(workflowData, frontmatter)...)
+
+	policyLog.Printf("Policy evaluation completed: violations=%d", len(violations))
+	return violations
+}
+
+func (p *Policy) evaluateEngines(workflowData *WorkflowData) []PolicyViolation {
+	if p.Engines == nil {
+		return nil
+	}
+	engineID := workflowData.AI
+	model := ""
+	if workflowData.EngineConfig != nil {
+		if engineID == "" {
+			engineID = workflowData.EngineConfig.ID
+		}
+		model = workflowData.EngineConfig.Model
+	}
+
+	var violations []PolicyViolation
+	if len(p.Engines.Allowed) > 0 && engineID != "" && !matchesAnyPolicyPattern(p.Engines.Allowed, engineID) {
+		violations = append(violations, PolicyViolation{
+			Rule:    "engines.allowed",
+			Message: fmt.Sprintf("engine '%s' is not allowed. Allowed engines: %s", engineID, strings.Join(p.Engines.Allowed, ", ")),
+			Path:    "/engine",
+		})
+	}
+	if len(p.Engines.Models) > 0 && model != "" && !matchesAnyPolicyPattern(p.Engines.Models, model) {
+		violations = append(violations, PolicyViolation{
+			Rule:    "engines.models",
+			Message: fmt.Sprintf("model '%s' is not allowed. Allowed models: %s", model, strings.Join(p.Engines.Models, ", ")),
+			Path:    "/engine/model",
+		})
+	}
+	return violations
+}
+
+// permissionLevelRank orders permission levels from least to most privileged
+func permissionLevelRank(level PermissionLevel) int {
+	switch level {
+	case PermissionRead:
+		return 1
+	case PermissionWrite:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func (p *Policy) evaluatePermissions(workflowData *WorkflowData) []PolicyViolation {
+	if p.Permissions == nil || len(p.Permissions.Max) == 0 || workflowData.Permissions == "" {
+		return nil
+	}
+	permissions := NewPermissionsParser(workflowData.Permissions).ToPermissions()
+	if permissions == nil {
+		return nil
+	}
+
+	var violations []PolicyViolation
+	for _, scope := range GetAllPermissionScopes() {
+		maxLevel, limited := p.Permissions.Max[string(scope)]
+		if !limited {
+			maxLevel, limited = p.Permissions.Max["all"]
+		}
+		if !limited {
+			continue
+		}
+		level, exists := permissions.Get(scope)
+		if !exists || permissionLevelRank(level) <= permissionLevelRank(PermissionLevel(maxLevel)) {
+			continue
+		}
+		violations = append(violations, PolicyViolation{
+			Rule:    "permissions.max",
+			Message: fmt.Sprintf("permission '%s: %s' exceeds the maximum '%s'", scope, level, maxLevel),
+			Path:    "/permissions/" + string(scope),
+		})
+	}
+	return violations
+}
+
+func (p *Policy) evaluateMCPServers(workflowData *WorkflowData, frontmatter map[string]any) []PolicyViolation {
+	if p.MCPServers == nil || workflowData.ParsedTools == nil {
+		return nil
+	}
+	mcpServers, _ := frontmatter["mcp-servers"].(map[string]any)
+
+	var violations []PolicyViolation
+	for _, name := range slices.Sorted(maps.Keys(workflowData.ParsedTools.Custom)) {
+		if matchesAnyPolicyPattern(p.MCPServers.Allowed, name) {
+			continue
+		}
+		path := "/tools/" + name
+		if _, ok := mcpServers[name]; ok {
+			path = "/mcp-servers/" + name
+		}
+		violations = append(violations, PolicyViolation{
+			Rule:    "mcp-servers.allowed",
+			Message: fmt.Sprintf("MCP server '%s' is not in the allowlist", name),
+			Path:    path,
+		})
+	}
+	return violations
+}
+
+// policyContainer is a container image referenced by a workflow and where it is configured
+type policyContainer struct {
+	image string
+	path  string
+}
+
+// collectPolicyContainers returns the job, service and MCP server container images of a workflow
+func collectPolicyContainers(workflowData *WorkflowData, frontmatter map[string]any) []policyContainer {
+	var containers []policyContainer
+	switch container := frontmatter["container"].(type) {
+	case string:
+		containers = append(containers, policyContainer{container, "/container"})
+	case map[string]any:
+		if image, ok := container["image"].(string); ok {
+			containers = append(containers, policyContainer{image, "/container/image"})
+		}
+	}
+	if services, ok := frontmatter["services"].(map[string]any); ok {
+		for _, name := range slices.Sorted(maps.Keys(services)) {
+			if service, ok := services[name].(map[string]any); ok {
+				if image, ok := service["image"].(string); ok {
+					containers = append(containers, policyContainer{image, "/services/" + name + "/image"})
+				}
+			}
+		}
+	}
+	if workflowData.ParsedTools != nil {
+		mcpServers, _ := frontmatter["mcp-servers"].(map[string]any)
+		for _, name := range slices.Sorted(maps.Keys(workflowData.ParsedTools.Custom)) {
+			image := workflowData.ParsedTools.Custom[name].Container
+			if image == "" {
+				continue
+			}
+			if version := workflowData.ParsedTools.Custom[name].Version; version != "" && !strings.Contains(image, ":") {
+				image += ":" + version
+			}
+			path := "/tools/" + name + "/container"
+			if _, ok := mcpServers[name]; ok {
+				path = "/mcp-servers/" + name + "/container"
+			}
+			containers = append(containers, policyContainer{image, path})
+		}
+	}
+	return containers
+}
+
+func (p *Policy) evaluateContainers(workflowData *WorkflowData, frontmatter map[string]any) []PolicyViolation {
+	if p.Containers == nil {
+		return nil
+	}
+	var violations []PolicyViolation
+	for _, container := range collectPolicyContainers(workflowData, frontmatter) {
+		if matchesAnyPolicyPattern(p.Containers.Allowed, container.image) {
+			continue
+		}
+		violations = append(violations, PolicyViolation{
+			Rule:    "containers.allowed",
+			Message: fmt.Sprintf("container image '%s' is not in the allowlist", container.image),
+			Path:    container.path,
+		})
+	}
+	return violations
+}
+
+func (p *Policy) evaluateNetwork(workflowData *WorkflowData) []PolicyViolation {
+	if p.Network == nil || workflowData.NetworkPermissions == nil {
+		return nil
+	}
+	var violations []PolicyViolation
+	for i, entry := range workflowData.NetworkPermissions.Allowed {
+		for _, ecosystem := range p.Network.ForbiddenEcosystems {
+			if entry == ecosystem {
+				violations = append(violations, PolicyViolation{
+					Rule:    "network.forbidden-ecosystems",
+					Message: fmt.Sprintf("network ecosystem '%s' is forbidden", ecosystem),
+					Path:    fmt.Sprintf("/network/allowed/%d", i),
+				})
+			}
+		}
+	}
+	return violations
+}
+
+func (p *Policy) evaluateThreatDetection(workflowData *WorkflowData) []PolicyViolation {
+	if p.ThreatDetection == nil || !p.ThreatDetection.Required {
+		return nil
+	}
+	if !hasAnySafeOutputEnabled(workflowData.SafeOutputs) || workflowData.SafeOutputs.ThreatDetection != nil {
+		return nil
+	}
+	return []PolicyViolation{{
+		Rule:    "threat-detection.required",
+		Message: "threat detection is required for workflows with safe outputs but is disabled",
+		Path:    "/safe-outputs/threat-detection",
+	}}
+}
+
+func (p *Policy) evaluateSafeOutputs(workflowData *WorkflowData) []PolicyViolation {
+	if p.SafeOutputs == nil || workflowData.SafeOutputs == nil {
+		return nil
+	}
+	var violations []PolicyViolation
+	val := reflect.ValueOf(workflowData.SafeOutputs).Elem()
+	for _, fieldName := range slices.Sorted(maps.Keys(safeOutputFieldMapping)) {
+		name := strings.ReplaceAll(safeOutputFieldMapping[fieldName], "_", "-")
+		limit, limited := p.SafeOutputs.Max[name]
+		field := val.FieldByName(fieldName)
+		if !limited || !field.IsValid() || field.IsNil() {
+			continue
+		}
+		// An unset max uses the safe output's built-in default
+		maxField := field.Elem().FieldByName("Max")
+		if !maxField.IsValid() || maxField.Kind() != reflect.Int {
+			continue
+		}
+		if configured := int(maxField.Int()); configured > limit {
+			violations = append(violations, PolicyViolation{
+				Rule:    "safe-outputs.max",
+				Message: fmt.Sprintf("safe-outputs.%s max %d exceeds the maximum %d", name, configured, limit),
+				Path:    "/safe-outputs/" + name + "/max",
+			})
+		}
+	}
+	return violations
+}
+
+func (p *Policy) evaluateRoles(workflowData *WorkflowData, frontmatter map[string]any) []PolicyViolation {
+	if p.Roles == nil {
+		return nil
+	}
+	var violations []PolicyViolation
+	if _, declared := frontmatter["roles"]; p.Roles.Required && !declared {
+		violations = append(violations, PolicyViolation{
+			Rule:    "roles.required",
+			Message: "roles: must be declared explicitly",
+			Path:    "",
+		})
+	}
+	if len(p.Roles.Allowed) > 0 {
+		for _, role := range workflowData.Roles {
+			if !matchesAnyPolicyPattern(p.Roles.Allowed, role) {
+				violations = append(violations, PolicyViolation{
+					Rule:    "roles.allowed",
+					Message: fmt.Sprintf("role '%s' is not allowed. Allowed roles: %s", role, strings.Join(p.Roles.Allowed, ", ")),
+					Path:    "/roles",
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// matchesAnyPolicyPattern reports whether a value matches one of the patterns, where '*' matches
+// any sequence of characters
+func matchesAnyPolicyPattern(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "*") {
+			if pattern == value {
+				return true
+			}
+			continue
+		}
+		quoted := strings.Split(pattern, "*")
+		for i, part := range quoted {
+			quoted[i] = regexp.QuoteMeta(part)
+		}
+		if regexp.MustCompile("^" + strings.Join(quoted, ".*") + "$").MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePolicy evaluates the compiler's policy against a workflow and aggregates the violations
+func (c *Compiler) validatePolicy(workflowData *WorkflowData) error {
+	if c.policy == nil {
+		return nil
+	}
+	policyLog.Printf("Validating workflow against policy %s", c.policy.Source)
+
+	collector := NewErrorCollector(c.failFast)
+	for _, violation := range c.policy.Evaluate(workflowData) {
+		location := SourceLocation{Rule: "policy/" + violation.Rule, Path: violation.Path, Message: violation.Message}
+		if returnErr := collector.Add(withSourceLocations(errors.New(violation.Error()), location)); returnErr != nil {
+			return returnErr
+		}
+	}
+	return collector.FormattedError("policy")
+}
@@ -0,0 +1,219 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `engines:
+  allowed: [copilot, claude]
+  models: ["claude-sonnet-*"]
+permissions:
+  max:
+    all: read
+    issues: write
+mcp-servers:
+  allowed: [github, "internal-*"]
+containers:
+  allowed: ["ghcr.io/my-org/*"]
+network:
+  forbidden-ecosystems: [python]
+threat-detection:
+  required: true
+safe-outputs:
+  max:
+    create-issue: 3
+roles:
+  required: true
+  allowed: [admin, maintainer]
+`
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy), "policy.yml")
+	require.NoError(t, err)
+	assert.Equal(t, "policy.yml", policy.Source)
+	assert.Equal(t, []string{"copilot", "claude"}, policy.Engines.Allowed)
+	assert.Equal(t, 3, policy.SafeOutputs.Max["create-issue"])
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "unknown section", content: "engine:\n  allowed: [claude]\n", wantErr: "unknown field"},
+		{name: "unknown scope", content: "permissions:\n  max:\n    repo: read\n", wantErr: "unknown permission scope 'repo'"},
+		{name: "invalid level", content: "permissions:\n  max:\n    contents: admin\n", wantErr: "must be 'read', 'write' or 'none'"},
+		{name: "unknown safe output", content: "safe-outputs:\n  max:\n    open-issue: 1\n", wantErr: "unknown safe output 'open-issue'"},
+		{name: "zero max", content: "safe-outputs:\n  max:\n    add-comment: 0\n", wantErr: "must be at least 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(tt.content), "policy.yml")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(testutil.TempDir(t, "test-*"), "policy.yml")
+	require.NoError(t, os.WriteFile(path, []byte(testPolicy), 0644))
+
+	policy, err := LoadPolicy(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, path, policy.Source)
+
+	_, err = LoadPolicy(filepath.Join(filepath.Dir(path), "missing.yml"), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "policy file not found")
+}
+
+func TestIsPolicyWorkflowSpec(t *testing.T) {
+	assert.True(t, isPolicyWorkflowSpec("my-org/policies/aw/policy.yml@main"))
+	assert.False(t, isPolicyWorkflowSpec("my-org/policies/aw/policy.yml"), "a ref is required")
+	assert.False(t, isPolicyWorkflowSpec("policies/policy.yml@main"))
+	assert.False(t, isPolicyWorkflowSpec("./a/b/policy.yml@main"))
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy), "policy.yml")
+	require.NoError(t, err)
+
+	compliant := func() *WorkflowData {
+		return &WorkflowData{
+			AI:                 "claude",
+			EngineConfig:       &EngineConfig{ID: "claude", Model: "claude-sonnet-4"},
+			FrontmatterYAML:    "on: issues\nroles: [admin]\ncontainer: ghcr.io/my-org/runner:1\n",
+			Permissions:        "permissions:\n  contents: read\n  issues: write",
+			ParsedTools:        NewTools(map[string]any{"github": map[string]any{}, "internal-search": map[string]any{"url": "https://search"}}),
+			NetworkPermissions: &NetworkPermissions{Allowed: []string{"defaults", "node"}},
+			SafeOutputs: &SafeOutputsConfig{
+				CreateIssues:    &CreateIssuesConfig{BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 2}},
+				ThreatDetection: &ThreatDetectionConfig{},
+			},
+			Roles: []string{"admin"},
+		}
+	}
+	assert.Empty(t, policy.Evaluate(compliant()))
+
+	tests := []struct {
+		name     string
+		mutate   func(data *WorkflowData)
+		wantRule string
+		wantPath string
+	}{
+		{
+			name:     "engine",
+			mutate:   func(data *WorkflowData) { data.AI = "codex" },
+			wantRule: "engines.allowed",
+			wantPath: "/engine",
+		},
+		{
+			name:     "model",
+			mutate:   func(data *WorkflowData) { data.EngineConfig.Model = "claude-opus-4" },
+			wantRule: "engines.models",
+			wantPath: "/engine/model",
+		},
+		{
+			name:     "permission above the default maximum",
+			mutate:   func(data *WorkflowData) { data.Permissions = "permissions:\n  contents: write" },
+			wantRule: "permissions.max",
+			wantPath: "/permissions/contents",
+		},
+		{
+			name: "mcp server",
+			mutate: func(data *WorkflowData) {
+				data.ParsedTools = NewTools(map[string]any{"legacy": map[string]any{"url": "https://legacy"}})
+				data.FrontmatterYAML += "mcp-servers:\n  legacy:\n    url: https://legacy\n"
+			},
+			wantRule: "mcp-servers.allowed",
+			wantPath: "/mcp-servers/legacy",
+		},
+		{
+			name: "job container",
+			mutate: func(data *WorkflowData) {
+				data.FrontmatterYAML = "on: issues\nroles: [admin]\ncontainer:\n  image: node:18\n"
+			},
+			wantRule: "containers.allowed",
+			wantPath: "/container/image",
+		},
+		{
+			name:     "forbidden ecosystem",
+			mutate:   func(data *WorkflowData) { data.NetworkPermissions.Allowed = []string{"defaults", "python"} },
+			wantRule: "network.forbidden-ecosystems",
+			wantPath: "/network/allowed/1",
+		},
+		{
+			name:     "threat detection disabled",
+			mutate:   func(data *WorkflowData) { data.SafeOutputs.ThreatDetection = nil },
+			wantRule: "threat-detection.required",
+			wantPath: "/safe-outputs/threat-detection",
+		},
+		{
+			name:     "safe output max",
+			mutate:   func(data *WorkflowData) { data.SafeOutputs.CreateIssues.Max = 5 },
+			wantRule: "safe-outputs.max",
+			wantPath: "/safe-outputs/create-issue/max",
+		},
+		{
+			name: "roles not declared",
+			mutate: func(data *WorkflowData) {
+				data.FrontmatterYAML = strings.Replace(data.FrontmatterYAML, "roles: [admin]\n", "", 1)
+			},
+			wantRule: "roles.required",
+		},
+		{
+			name:     "role not allowed",
+			mutate:   func(data *WorkflowData) { data.Roles = []string{"write"} },
+			wantRule: "roles.allowed",
+			wantPath: "/roles",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := compliant()
+			tt.mutate(data)
+			violations := policy.Evaluate(data)
+			require.Len(t, violations, 1, "violations: %v", violations)
+			assert.Equal(t, tt.wantRule, violations[0].Rule)
+			assert.Equal(t, tt.wantPath, violations[0].Path)
+		})
+	}
+}
+
+func TestValidatePolicy(t *testing.T) {
+	policy, err := ParsePolicy([]byte("engines:\n  allowed: [copilot]\nroles:\n  required: true\n"), "policy.yml")
+	require.NoError(t, err)
+
+	compiler := NewCompiler()
+	data := &WorkflowData{AI: "claude", FrontmatterYAML: "on: issues\n"}
+	require.NoError(t, compiler.validatePolicy(data), "no policy is configured")
+
+	compiler.SetPolicy(policy)
+	err = compiler.validatePolicy(data)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Found 2 policy errors")
+	assert.Contains(t, err.Error(), "engines.allowed: engine 'claude' is not allowed")
+
+	compiler = NewCompiler(WithFailFast(true))
+	compiler.SetPolicy(policy)
+	err = compiler.validatePolicy(data)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "Found 2 policy errors", "fail-fast stops at the first violation")
+}
+
+func TestMatchesAnyPolicyPattern(t *testing.T) {
+	assert.True(t, matchesAnyPolicyPattern([]string{"ghcr.io/my-org/*"}, "ghcr.io/my-org/tools/mcp:1.0"))
+	assert.True(t, matchesAnyPolicyPattern([]string{"node:*", "github"}, "github"))
+	assert.False(t, matchesAnyPolicyPattern([]string{"ghcr.io/my-org/*"}, "ghcr.io/other/mcp"))
+	assert.False(t, matchesAnyPolicyPattern([]string{"a.b"}, "axb"), "dots are literal")
+	assert.False(t, matchesAnyPolicyPattern(nil, "github"))
+}
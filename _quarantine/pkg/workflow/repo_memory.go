@@ -41,14 +41,16 @@ type RepoMemoryConfig struct {
 
 // RepoMemoryEntry represents a single repo-memory configuration
 type RepoMemoryEntry struct {
-	ID           string   `yaml:"id"`                       // memory identifier (required for array notation)
-	TargetRepo   string   `yaml:"target-repo,omitempty"`    // target repository (default: current repo)
-	BranchName   string   `yaml:"branch-name,omitempty"`    // branch name (default: memory/{memory-id})
-	FileGlob     []string `yaml:"file-glob,omitempty"`      // file glob patterns for allowed files
-	MaxFileSize  int      `yaml:"max-file-size,omitempty"`  // maximum size per file in bytes (default: 10KB)
-	MaxFileCount int      `yaml:"max-file-count,omitempty"` // maximum file count per commit (default: 100)
-	Description  string   `yaml:"description,omitempty"`    // optional description for this memory
-	CreateOrphan bool     `yaml:"create-orphan,omitempty"`  // create orphaned branch if missing (default: true)
+	ID           string         `yaml:"id"`                       // memory identifier (required for array notation)
+	TargetRepo   string         `yaml:"target-repo,omitempty"`    // target repository (default: current repo)
+	BranchName   string         `yaml:"branch-name,omitempty"`    // branch name (default: memory/{memory-id})
+	FileGlob     []string       `yaml:"file-glob,omitempty"`      // file glob patterns for allowed files
+	MaxFileSize  int            `yaml:"max-file-size,omitempty"`  // maximum size per file in bytes (default: 10KB)
+	MaxFileCount int            `yaml:"max-file-count,omitempty"` // maximum file count per commit (default: 100)
+	Description  string         `yaml:"description,omitempty"`    // optional description for this memory
+	CreateOrphan bool           `yaml:"create-orphan,omitempty"`  // create orphaned branch if missing (default: true)
+	Schema       map[string]any `yaml:"schema,omitempty"`         // JSON Schema every JSON document in the memory must satisfy
+	MCP          bool           `yaml:"mcp,omitempty"`            // expose keyed JSON documents through the repo-memory MCP server
 }
 
 // RepoMemoryToolConfig represents the configuration for repo-memory in tools
@@ -260,6 +262,11 @@ func (c *Compiler) extractRepoMemoryConfig(toolsConfig *ToolsConfig) (*RepoMemor
 					}
 				}
 
+				// Parse schema and mcp
+				if err := parseRepoMemoryDocumentOptions(&entry, memoryMap); err != nil {
+					return nil, err
+				}
+
 				config.Memories = append(config.Memories, entry)
 			}
 		}
@@ -369,6 +376,11 @@ func (c *Compiler) extractRepoMemoryConfig(toolsConfig *ToolsConfig) (*RepoMemor
 			}
 		}
 
+		// Parse schema and mcp
+		if err := parseRepoMemoryDocumentOptions(&entry, configMap); err != nil {
+			return nil, err
+		}
+
 		config.Memories = []RepoMemoryEntry{entry}
 		return config, nil
 	}
@@ -376,6 +388,46 @@ func (c *Compiler) extractRepoMemoryConfig(toolsConfig *ToolsConfig) (*RepoMemor
 	return nil, nil
 }
 
+// parseRepoMemoryDocumentOptions parses the schema and mcp fields of a memory entry
+func parseRepoMemoryDocumentOptions(entry *RepoMemoryEntry, memoryMap map[string]any) error {
+	if schema, exists := memoryMap["schema"]; exists {
+		schemaMap, ok := schema.(map[string]any)
+		if !ok {
+			return fmt.Errorf("repo-memory '%s' schema must be an object containing a JSON Schema", entry.ID)
+		}
+		if err := validateRepoMemorySchema(entry.ID, schemaMap); err != nil {
+			return err
+		}
+		entry.Schema = schemaMap
+	}
+
+	if mcp, exists := memoryMap["mcp"]; exists {
+		if mcpBool, ok := mcp.(bool); ok {
+			entry.MCP = mcpBool
+		}
+	}
+
+	return nil
+}
+
+// hasRepoMemoryMCP returns true when at least one memory is exposed through the repo-memory MCP server
+func hasRepoMemoryMCP(data *WorkflowData) bool {
+	if data == nil || data.RepoMemoryConfig == nil {
+		return false
+	}
+	for _, memory := range data.RepoMemoryConfig.Memories {
+		if memory.MCP {
+			return true
+		}
+	}
+	return false
+}
+
+// repoMemoryOperationsFile returns the path of the operations journal written by the repo-memory MCP server
+func repoMemoryOperationsFile(memoryID string) string {
+	return fmt.Sprintf("/tmp/gh-aw/repo-memory-operations/%s.jsonl", memoryID)
+}
+
 // validateNoDuplicateMemoryIDs checks for duplicate memory IDs and returns an error if found
 func validateNoDuplicateMemoryIDs(memories []RepoMemoryEntry) error {
 	seen := make(map[string]bool)
@@ -412,6 +464,18 @@ func generateRepoMemoryArtifactUpload(builder *strings.Builder, data *WorkflowDa
 		fmt.Fprintf(builder, "          path: %s\n", memoryDir)
 		builder.WriteString("          retention-days: 1\n")
 		builder.WriteString("          if-no-files-found: ignore\n")
+
+		// Step: Upload the operations journal so the push job can replay it on the latest branch
+		if memory.MCP {
+			fmt.Fprintf(builder, "      - name: Upload repo-memory operations (%s)\n", memory.ID)
+			builder.WriteString("        if: always()\n")
+			fmt.Fprintf(builder, "        uses: %s\n", GetActionPin("actions/upload-artifact"))
+			builder.WriteString("        with:\n")
+			fmt.Fprintf(builder, "          name: repo-memory-operations-%s\n", memory.ID)
+			fmt.Fprintf(builder, "          path: %s\n", repoMemoryOperationsFile(memory.ID))
+			builder.WriteString("          retention-days: 1\n")
+			builder.WriteString("          if-no-files-found: ignore\n")
+		}
 	}
 }
 
@@ -578,6 +642,17 @@ func (c *Compiler) buildPushRepoMemoryJob(data *WorkflowData, threatDetectionEna
 		fmt.Fprintf(&step, "          name: repo-memory-%s\n", memory.ID)
 		fmt.Fprintf(&step, "          path: /tmp/gh-aw/repo-memory/%s\n", memory.ID)
 		steps = append(steps, step.String())
+
+		if memory.MCP {
+			var opsStep strings.Builder
+			fmt.Fprintf(&opsStep, "      - name: Download repo-memory operations (%s)\n", memory.ID)
+			fmt.Fprintf(&opsStep, "        uses: %s\n", GetActionPin("actions/download-artifact"))
+			opsStep.WriteString("        continue-on-error: true\n")
+			opsStep.WriteString("        with:\n")
+			fmt.Fprintf(&opsStep, "          name: repo-memory-operations-%s\n", memory.ID)
+			opsStep.WriteString("          path: /tmp/gh-aw/repo-memory-operations\n")
+			steps = append(steps, opsStep.String())
+		}
 	}
 
 	// Determine script loading method based on action mode
@@ -616,6 +691,17 @@ func (c *Compiler) buildPushRepoMemoryJob(data *WorkflowData, threatDetectionEna
 			// Quote the value to prevent YAML alias interpretation of patterns like *.md
 			fmt.Fprintf(&step, "          FILE_GLOB_FILTER: \"%s\"\n", fileGlobFilter)
 		}
+		if len(memory.Schema) > 0 {
+			schemaJSON, err := repoMemorySchemaJSON(memory.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode schema for repo-memory '%s': %w", memory.ID, err)
+			}
+			// Single-quote the JSON so YAML keeps it verbatim
+			fmt.Fprintf(&step, "          MEMORY_SCHEMA: '%s'\n", strings.ReplaceAll(schemaJSON, "'", "''"))
+		}
+		if memory.MCP {
+			fmt.Fprintf(&step, "          OPERATIONS_FILE: %s\n", repoMemoryOperationsFile(memory.ID))
+		}
 		step.WriteString("        with:\n")
 		step.WriteString("          script: |\n")
 
@@ -0,0 +1,168 @@
+// This file provides the repo-memory MCP server configuration.
+//
+// Memories with `mcp: true` are exposed through a built-in HTTP MCP server that offers keyed
+// get/put/append/query tools over JSON documents stored in the memory directory. Every mutation
+// is also appended to a per-memory operations journal, which the push_repo_memory job replays on
+// top of the latest branch content so that concurrent runs merge instead of overwriting each other.
+//
+// The server runs on the host like the safe-inputs server:
+//   - Setup step writes /opt/gh-aw/repo-memory/config.json
+//   - Config step generates the API key and port
+//   - Start step launches repo_memory_mcp_server.cjs via start_repo_memory_server.sh
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var repoMemoryMCPLog = logger.New("workflow:repo_memory_mcp")
+
+// repoMemoryMCPConfigPath is the path of the config file read by the repo-memory MCP server
+const repoMemoryMCPConfigPath = "/opt/gh-aw/repo-memory/config.json"
+
+// RepoMemoryMCPServerEntry is a memory exposed by the repo-memory MCP server
+type RepoMemoryMCPServerEntry struct {
+	ID           string         `json:"id"`
+	Dir          string         `json:"dir"`
+	Journal      string         `json:"journal"`
+	Description  string         `json:"description,omitempty"`
+	Schema       map[string]any `json:"schema,omitempty"`
+	FileGlob     []string       `json:"file_glob,omitempty"`
+	MaxFileSize  int            `json:"max_file_size,omitempty"`
+	MaxFileCount int            `json:"max_file_count,omitempty"`
+}
+
+// RepoMemoryMCPServerConfig is the config file read by the repo-memory MCP server
+type RepoMemoryMCPServerConfig struct {
+	Memories []RepoMemoryMCPServerEntry `json:"memories"`
+}
+
+// generateRepoMemoryMCPServerConfig builds the JSON config for the memories exposed through MCP
+func generateRepoMemoryMCPServerConfig(config *RepoMemoryConfig) string {
+	serverConfig := RepoMemoryMCPServerConfig{Memories: []RepoMemoryMCPServerEntry{}}
+	for _, memory := range config.Memories {
+		if !memory.MCP {
+			continue
+		}
+		serverConfig.Memories = append(serverConfig.Memories, RepoMemoryMCPServerEntry{
+			ID:           memory.ID,
+			Dir:          fmt.Sprintf("/tmp/gh-aw/repo-memory/%s", memory.ID),
+			Journal:      repoMemoryOperationsFile(memory.ID),
+			Description:  memory.Description,
+			Schema:       memory.Schema,
+			FileGlob:     memory.FileGlob,
+			MaxFileSize:  memory.MaxFileSize,
+			MaxFileCount: memory.MaxFileCount,
+		})
+	}
+
+	// Schemas were already round-tripped through JSON during validation, so marshaling cannot fail
+	data, _ := json.Marshal(serverConfig)
+	repoMemoryMCPLog.Printf("Repo-memory MCP config generated for %d memories", len(serverConfig.Memories))
+	return string(data)
+}
+
+// generateRepoMemoryMCPSetup generates the steps that configure and start the repo-memory MCP server
+func generateRepoMemoryMCPSetup(yaml *strings.Builder, workflowData *WorkflowData) {
+	if !hasRepoMemoryMCP(workflowData) {
+		return
+	}
+
+	repoMemoryMCPLog.Print("Generating repo-memory MCP server setup steps")
+
+	configJSON := generateRepoMemoryMCPServerConfig(workflowData.RepoMemoryConfig)
+
+	// Step 1: Write the server config
+	yaml.WriteString("      - name: Setup Repo Memory MCP Config\n")
+	yaml.WriteString("        run: |\n")
+	yaml.WriteString("          mkdir -p /opt/gh-aw/repo-memory /tmp/gh-aw/repo-memory-operations\n")
+	fmt.Fprintf(yaml, "          cat > %s << 'EOF_REPO_MEMORY_CONFIG'\n", repoMemoryMCPConfigPath)
+	yaml.WriteString("          " + configJSON + "\n")
+	yaml.WriteString("          EOF_REPO_MEMORY_CONFIG\n")
+
+	// Step 2: Generate API key and choose port for HTTP server
+	yaml.WriteString("      - name: Generate Repo Memory MCP Server Config\n")
+	yaml.WriteString("        id: repo-memory-config\n")
+	yaml.WriteString("        run: |\n")
+	yaml.WriteString("          # Generate a secure random API key (360 bits of entropy, 40+ chars)\n")
+	yaml.WriteString("          # Mask immediately to prevent timing vulnerabilities\n")
+	yaml.WriteString("          API_KEY=$(openssl rand -base64 45 | tr -d '/+=')\n")
+	yaml.WriteString("          echo \"::add-mask::${API_KEY}\"\n")
+	yaml.WriteString("          \n")
+	fmt.Fprintf(yaml, "          PORT=%d\n", constants.DefaultRepoMemoryMCPPort)
+	yaml.WriteString("          \n")
+	yaml.WriteString("          # Set outputs for next steps\n")
+	yaml.WriteString("          {\n")
+	yaml.WriteString("            echo \"repo_memory_api_key=${API_KEY}\"\n")
+	yaml.WriteString("            echo \"repo_memory_port=${PORT}\"\n")
+	yaml.WriteString("          } >> \"$GITHUB_OUTPUT\"\n")
+	yaml.WriteString("          \n")
+	yaml.WriteString("          echo \"Repo Memory MCP server will run on port ${PORT}\"\n")
+	yaml.WriteString("          \n")
+
+	// Step 3: Start the HTTP server in the background
+	// Security: Pass step outputs through environment variables to prevent template injection
+	yaml.WriteString("      - name: Start Repo Memory MCP HTTP Server\n")
+	yaml.WriteString("        id: repo-memory-start\n")
+	yaml.WriteString("        env:\n")
+	yaml.WriteString("          GH_AW_REPO_MEMORY_PORT: ${{ steps.repo-memory-config.outputs.repo_memory_port }}\n")
+	yaml.WriteString("          GH_AW_REPO_MEMORY_API_KEY: ${{ steps.repo-memory-config.outputs.repo_memory_api_key }}\n")
+	fmt.Fprintf(yaml, "          GH_AW_REPO_MEMORY_CONFIG_PATH: %s\n", repoMemoryMCPConfigPath)
+	yaml.WriteString("        run: |\n")
+	yaml.WriteString("          # Environment variables are set above to prevent template injection\n")
+	yaml.WriteString("          export GH_AW_REPO_MEMORY_PORT\n")
+	yaml.WriteString("          export GH_AW_REPO_MEMORY_API_KEY\n")
+	yaml.WriteString("          export GH_AW_REPO_MEMORY_CONFIG_PATH\n")
+	yaml.WriteString("          \n")
+	yaml.WriteString("          bash /opt/gh-aw/actions/start_repo_memory_server.sh\n")
+	yaml.WriteString("          \n")
+}
+
+// repoMemoryMCPHost returns the host the agent uses to reach the repo-memory MCP server
+func repoMemoryMCPHost(workflowData *WorkflowData) string {
+	if workflowData != nil && workflowData.SandboxConfig != nil && workflowData.SandboxConfig.Agent != nil && workflowData.SandboxConfig.Agent.Disabled {
+		// When agent is disabled (no firewall), use localhost instead of host.docker.internal
+		return "localhost"
+	}
+	return "host.docker.internal"
+}
+
+// RenderRepoMemoryMCP generates the repo-memory MCP server configuration
+func (r *MCPConfigRendererUnified) RenderRepoMemoryMCP(yaml *strings.Builder, workflowData *WorkflowData) {
+	mcpRendererLog.Printf("Rendering Repo Memory MCP: format=%s", r.options.Format)
+
+	host := repoMemoryMCPHost(workflowData)
+
+	if r.options.Format == "toml" {
+		yaml.WriteString("          \n")
+		yaml.WriteString("          [mcp_servers." + constants.RepoMemoryMCPServerID + "]\n")
+		yaml.WriteString("          type = \"http\"\n")
+		yaml.WriteString("          url = \"http://" + host + ":$GH_AW_REPO_MEMORY_PORT\"\n")
+		yaml.WriteString("          headers = { Authorization = \"$GH_AW_REPO_MEMORY_API_KEY\" }\n")
+		return
+	}
+
+	yaml.WriteString("              \"" + constants.RepoMemoryMCPServerID + "\": {\n")
+	yaml.WriteString("                \"type\": \"http\",\n")
+	yaml.WriteString("                \"url\": \"http://" + host + ":$GH_AW_REPO_MEMORY_PORT\",\n")
+	yaml.WriteString("                \"headers\": {\n")
+	if r.options.IncludeCopilotFields {
+		// Copilot format: backslash-escaped shell variable reference
+		yaml.WriteString("                  \"Authorization\": \"\\${GH_AW_REPO_MEMORY_API_KEY}\"\n")
+	} else {
+		yaml.WriteString("                  \"Authorization\": \"$GH_AW_REPO_MEMORY_API_KEY\"\n")
+	}
+	yaml.WriteString("                }\n")
+
+	if r.options.IsLast {
+		yaml.WriteString("              }\n")
+	} else {
+		yaml.WriteString("              },\n")
+	}
+}
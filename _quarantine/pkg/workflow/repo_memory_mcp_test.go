@@ -0,0 +1,138 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func repoMemoryMCPTestData() *WorkflowData {
+	return &WorkflowData{
+		RepoMemoryConfig: &RepoMemoryConfig{
+			Memories: []RepoMemoryEntry{
+				{
+					ID:           "state",
+					BranchName:   "memory/state",
+					FileGlob:     []string{"issues/*.json"},
+					MaxFileSize:  10240,
+					MaxFileCount: 100,
+					Schema:       map[string]any{"type": "object", "description": "it's state"},
+					MCP:          true,
+				},
+				{
+					ID:           "notes",
+					BranchName:   "memory/notes",
+					MaxFileSize:  10240,
+					MaxFileCount: 100,
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateRepoMemoryMCPServerConfig(t *testing.T) {
+	data := repoMemoryMCPTestData()
+
+	var config RepoMemoryMCPServerConfig
+	require.NoError(t, json.Unmarshal([]byte(generateRepoMemoryMCPServerConfig(data.RepoMemoryConfig)), &config), "config should be valid JSON")
+
+	require.Len(t, config.Memories, 1, "only memories with mcp enabled should be exposed")
+	memory := config.Memories[0]
+	assert.Equal(t, "state", memory.ID, "memory ID")
+	assert.Equal(t, "/tmp/gh-aw/repo-memory/state", memory.Dir, "memory directory")
+	assert.Equal(t, "/tmp/gh-aw/repo-memory-operations/state.jsonl", memory.Journal, "operations journal")
+	assert.Equal(t, []string{"issues/*.json"}, memory.FileGlob, "file globs")
+	assert.Equal(t, "object", memory.Schema["type"], "schema")
+}
+
+func TestGenerateRepoMemoryMCPSetup(t *testing.T) {
+	var yaml strings.Builder
+	generateRepoMemoryMCPSetup(&yaml, repoMemoryMCPTestData())
+	output := yaml.String()
+
+	assert.Contains(t, output, "cat > /opt/gh-aw/repo-memory/config.json", "setup should write the server config")
+	assert.Contains(t, output, "id: repo-memory-config", "setup should generate the API key and port")
+	assert.Contains(t, output, "PORT=3002", "setup should use the default repo-memory port")
+	assert.Contains(t, output, "id: repo-memory-start", "setup should start the server")
+	assert.Contains(t, output, "bash /opt/gh-aw/actions/start_repo_memory_server.sh", "setup should use the bundled start script")
+
+	yaml.Reset()
+	generateRepoMemoryMCPSetup(&yaml, &WorkflowData{RepoMemoryConfig: &RepoMemoryConfig{Memories: []RepoMemoryEntry{{ID: "notes"}}}})
+	assert.Empty(t, yaml.String(), "no setup should be generated without mcp")
+}
+
+func TestRenderRepoMemoryMCP(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  MCPRendererOptions
+		data     *WorkflowData
+		expected []string
+	}{
+		{
+			name:    "copilot JSON",
+			options: MCPRendererOptions{Format: "json", IncludeCopilotFields: true, IsLast: true},
+			data:    repoMemoryMCPTestData(),
+			expected: []string{
+				`"repomemory": {`,
+				`"url": "http://host.docker.internal:$GH_AW_REPO_MEMORY_PORT"`,
+				`"Authorization": "\${GH_AW_REPO_MEMORY_API_KEY}"`,
+			},
+		},
+		{
+			name:    "claude JSON with agent disabled",
+			options: MCPRendererOptions{Format: "json", IsLast: false},
+			data: &WorkflowData{
+				RepoMemoryConfig: repoMemoryMCPTestData().RepoMemoryConfig,
+				SandboxConfig:    &SandboxConfig{Agent: &AgentSandboxConfig{Disabled: true}},
+			},
+			expected: []string{
+				`"url": "http://localhost:$GH_AW_REPO_MEMORY_PORT"`,
+				`"Authorization": "$GH_AW_REPO_MEMORY_API_KEY"`,
+				"              },\n",
+			},
+		},
+		{
+			name:    "codex TOML",
+			options: MCPRendererOptions{Format: "toml"},
+			data:    repoMemoryMCPTestData(),
+			expected: []string{
+				"[mcp_servers.repomemory]",
+				`url = "http://host.docker.internal:$GH_AW_REPO_MEMORY_PORT"`,
+				`headers = { Authorization = "$GH_AW_REPO_MEMORY_API_KEY" }`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var yaml strings.Builder
+			NewMCPConfigRenderer(tt.options).RenderRepoMemoryMCP(&yaml, tt.data)
+			for _, expected := range tt.expected {
+				assert.Contains(t, yaml.String(), expected, "rendered config")
+			}
+		})
+	}
+}
+
+func TestRepoMemoryPushJobDocumentOptions(t *testing.T) {
+	data := repoMemoryMCPTestData()
+
+	var upload strings.Builder
+	generateRepoMemoryArtifactUpload(&upload, data)
+	assert.Contains(t, upload.String(), "name: repo-memory-operations-state", "operations journal should be uploaded")
+	assert.NotContains(t, upload.String(), "repo-memory-operations-notes", "no journal upload without mcp")
+
+	pushJob, err := NewCompiler().buildPushRepoMemoryJob(data, false)
+	require.NoError(t, err, "push job should build")
+	output := strings.Join(pushJob.Steps, "\n")
+
+	assert.Contains(t, output, "Download repo-memory operations (state)", "operations journal should be downloaded")
+	assert.Contains(t, output, "OPERATIONS_FILE: /tmp/gh-aw/repo-memory-operations/state.jsonl", "journal should be passed to the push script")
+	assert.Contains(t, output, `MEMORY_SCHEMA: '{"description":"it''s state","type":"object"}'`, "schema should be passed as single-quoted JSON")
+	assert.NotContains(t, output, "Download repo-memory operations (notes)", "no journal download without mcp")
+}
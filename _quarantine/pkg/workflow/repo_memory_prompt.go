@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 )
 
@@ -60,6 +61,8 @@ func generateRepoMemoryPromptSection(yaml *strings.Builder, config *RepoMemoryCo
 			}
 		}
 
+		writeRepoMemoryDocumentNotes(yaml, config.Memories)
+
 		yaml.WriteString("          \n")
 		yaml.WriteString("          Examples of what you can store:\n")
 		fmt.Fprintf(yaml, "          - `%snotes.md` - general notes and observations\n", memoryDir)
@@ -92,6 +95,8 @@ func generateRepoMemoryPromptSection(yaml *strings.Builder, config *RepoMemoryCo
 		yaml.WriteString("          - **Automatic Push**: Changes are automatically committed and pushed after the workflow completes\n")
 		yaml.WriteString("          - **Merge Strategy**: In case of conflicts, your changes (current version) win\n")
 		yaml.WriteString("          - **Persistence**: Files persist across workflow runs via git branch storage\n")
+		writeRepoMemoryDocumentNotes(yaml, config.Memories)
+
 		yaml.WriteString("          \n")
 		yaml.WriteString("          Examples of what you can store:\n")
 		memoryDir := "/tmp/gh-aw/repo-memory"
@@ -102,3 +107,37 @@ func generateRepoMemoryPromptSection(yaml *strings.Builder, config *RepoMemoryCo
 		yaml.WriteString("          Feel free to create, read, update, and organize files in these folders as needed for your tasks.\n")
 	}
 }
+
+// writeRepoMemoryDocumentNotes tells the agent about schemas and the memory MCP tools when configured
+func writeRepoMemoryDocumentNotes(yaml *strings.Builder, memories []RepoMemoryEntry) {
+	var schemaIDs, mcpIDs []string
+	for _, memory := range memories {
+		if len(memory.Schema) > 0 {
+			schemaIDs = append(schemaIDs, memory.ID)
+		}
+		if memory.MCP {
+			mcpIDs = append(mcpIDs, memory.ID)
+		}
+	}
+	if len(schemaIDs) == 0 && len(mcpIDs) == 0 {
+		return
+	}
+
+	repoMemoryPromptLog.Printf("Adding document notes: schemas=%v, mcp=%v", schemaIDs, mcpIDs)
+	yaml.WriteString("          \n")
+	if len(schemaIDs) > 0 {
+		fmt.Fprintf(yaml, "          - **JSON Schema**: Every `.json` file in memory %s must match the memory's JSON Schema; invalid documents fail the push\n", formatRepoMemoryIDs(schemaIDs))
+	}
+	if len(mcpIDs) > 0 {
+		fmt.Fprintf(yaml, "          - **Memory Tools**: Use the `%s` MCP tools (`memory_get`, `memory_put`, `memory_append`, `memory_query`) to read and update JSON documents by key in memory %s. Prefer them over writing JSON files directly: their changes are merged with concurrent runs instead of being overwritten\n", constants.RepoMemoryMCPServerID, formatRepoMemoryIDs(mcpIDs))
+	}
+}
+
+// formatRepoMemoryIDs formats memory IDs as a comma-separated list of code spans
+func formatRepoMemoryIDs(ids []string) string {
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = "`" + id + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
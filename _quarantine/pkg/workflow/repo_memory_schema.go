@@ -0,0 +1,131 @@
+// This file provides validation of repo-memory document schemas.
+//
+// A repo-memory entry may declare a JSON Schema that every JSON document committed to the
+// memory branch must satisfy. The schema is enforced at runtime by json_schema_validator.cjs
+// (in the push job and in the repo-memory MCP server), which implements a subset of JSON
+// Schema because the runner scripts cannot install packages. The compiler therefore rejects
+// schemas using keywords outside that subset instead of letting them be silently ignored.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// repoMemorySchemaKeywords lists the JSON Schema keywords enforced by json_schema_validator.cjs
+var repoMemorySchemaKeywords = map[string]bool{
+	"type": true, "enum": true, "const": true,
+	"properties": true, "required": true, "additionalProperties": true,
+	"items": true, "minItems": true, "maxItems": true, "uniqueItems": true,
+	"minLength": true, "maxLength": true, "pattern": true,
+	"minimum": true, "maximum": true, "exclusiveMinimum": true, "exclusiveMaximum": true,
+	"anyOf": true, "oneOf": true, "allOf": true, "not": true,
+	"$ref": true, "$defs": true, "definitions": true,
+}
+
+// repoMemorySchemaAnnotations lists keywords that carry no validation and are accepted as-is
+var repoMemorySchemaAnnotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+	"default": true, "examples": true, "format": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// validateRepoMemorySchema checks that a repo-memory schema is a valid JSON Schema that only
+// uses keywords the runtime validator enforces
+func validateRepoMemorySchema(memoryID string, schema map[string]any) error {
+	repoMemoryLog.Printf("Validating schema for repo-memory %s", memoryID)
+
+	// Round-trip through JSON so YAML number types become JSON numbers
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("repo-memory '%s' schema is not valid JSON: %w", memoryID, err)
+	}
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(string(data)))
+	if err != nil {
+		return fmt.Errorf("repo-memory '%s' schema is not valid JSON: %w", memoryID, err)
+	}
+
+	if unsupported := unsupportedRepoMemorySchemaKeywords(doc, ""); len(unsupported) > 0 {
+		supported := make([]string, 0, len(repoMemorySchemaKeywords))
+		for keyword := range repoMemorySchemaKeywords {
+			supported = append(supported, keyword)
+		}
+		sort.Strings(supported)
+		return fmt.Errorf("repo-memory '%s' schema uses unsupported keywords: %s. Supported keywords: %s",
+			memoryID, strings.Join(unsupported, ", "), strings.Join(supported, ", "))
+	}
+
+	compiler := jsonschema.NewCompiler()
+	schemaURL := "repo-memory-" + memoryID + ".json"
+	if err := compiler.AddResource(schemaURL, doc); err != nil {
+		return fmt.Errorf("repo-memory '%s' schema is invalid: %w", memoryID, err)
+	}
+	if _, err := compiler.Compile(schemaURL); err != nil {
+		return fmt.Errorf("repo-memory '%s' schema is invalid: %w", memoryID, err)
+	}
+	return nil
+}
+
+// unsupportedRepoMemorySchemaKeywords walks a schema and returns the JSON pointers of keywords
+// the runtime validator does not enforce, along with non-local $ref values
+func unsupportedRepoMemorySchemaKeywords(schema any, pointer string) []string {
+	obj, ok := schema.(map[string]any)
+	if !ok {
+		// Boolean schemas are supported; anything else is reported by the compiler
+		return nil
+	}
+
+	var unsupported []string
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := obj[key]
+		path := pointer + "/" + key
+		switch {
+		case key == "$ref":
+			if ref, ok := value.(string); !ok || !strings.HasPrefix(ref, "#") {
+				unsupported = append(unsupported, fmt.Sprintf("%s (only local references are supported)", path))
+			}
+		case key == "properties" || key == "$defs" || key == "definitions":
+			if children, ok := value.(map[string]any); ok {
+				names := make([]string, 0, len(children))
+				for name := range children {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					unsupported = append(unsupported, unsupportedRepoMemorySchemaKeywords(children[name], path+"/"+name)...)
+				}
+			}
+		case key == "items" || key == "additionalProperties" || key == "not":
+			unsupported = append(unsupported, unsupportedRepoMemorySchemaKeywords(value, path)...)
+		case key == "allOf" || key == "anyOf" || key == "oneOf":
+			if children, ok := value.([]any); ok {
+				for i, child := range children {
+					unsupported = append(unsupported, unsupportedRepoMemorySchemaKeywords(child, fmt.Sprintf("%s/%d", path, i))...)
+				}
+			}
+		case repoMemorySchemaKeywords[key] || repoMemorySchemaAnnotations[key]:
+		default:
+			unsupported = append(unsupported, path)
+		}
+	}
+	return unsupported
+}
+
+// repoMemorySchemaJSON returns the compact JSON encoding of a repo-memory schema
+func repoMemorySchemaJSON(schema map[string]any) (string, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,131 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRepoMemorySchema(t *testing.T) {
+	tests := []struct {
+		name        string
+		schema      map[string]any
+		errContains string
+	}{
+		{
+			name: "supported keywords with annotations",
+			schema: map[string]any{
+				"$schema":     "https://json-schema.org/draft/2020-12/schema",
+				"title":       "Issue state",
+				"type":        "object",
+				"required":    []any{"status"},
+				"description": "State tracked per issue",
+				"properties": map[string]any{
+					"status": map[string]any{"enum": []any{"open", "closed"}},
+					"count":  map[string]any{"type": "integer", "minimum": uint64(0)},
+					"labels": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "uniqueItems": true},
+					"owner":  map[string]any{"$ref": "#/$defs/login"},
+				},
+				"additionalProperties": false,
+				"$defs": map[string]any{
+					"login": map[string]any{"type": "string", "pattern": "^[a-z0-9-]+$", "format": "hostname"},
+				},
+			},
+		},
+		{
+			name: "unsupported keyword nested in anyOf",
+			schema: map[string]any{
+				"anyOf": []any{
+					map[string]any{"type": "string"},
+					map[string]any{"type": "object", "patternProperties": map[string]any{"^x": map[string]any{}}},
+				},
+			},
+			errContains: "/anyOf/1/patternProperties",
+		},
+		{
+			name:        "remote reference",
+			schema:      map[string]any{"$ref": "https://example.com/schema.json"},
+			errContains: "only local references are supported",
+		},
+		{
+			name:        "invalid keyword value",
+			schema:      map[string]any{"type": "text"},
+			errContains: "schema is invalid",
+		},
+		{
+			name:        "unresolvable local reference",
+			schema:      map[string]any{"$ref": "#/$defs/missing"},
+			errContains: "schema is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRepoMemorySchema("state", tt.schema)
+			if tt.errContains == "" {
+				assert.NoError(t, err, "schema should be accepted")
+				return
+			}
+			require.Error(t, err, "schema should be rejected")
+			assert.Contains(t, err.Error(), tt.errContains, "error should explain the rejection")
+			assert.Contains(t, err.Error(), "repo-memory 'state'", "error should name the memory")
+		})
+	}
+}
+
+func TestRepoMemoryConfigSchemaAndMCP(t *testing.T) {
+	toolsConfig, err := ParseToolsConfig(map[string]any{
+		"repo-memory": []any{
+			map[string]any{
+				"id":     "state",
+				"mcp":    true,
+				"schema": map[string]any{"type": "object", "required": []any{"status"}},
+			},
+			map[string]any{"id": "notes"},
+		},
+	})
+	require.NoError(t, err, "tools config should parse")
+
+	config, err := NewCompiler().extractRepoMemoryConfig(toolsConfig)
+	require.NoError(t, err, "repo-memory config should be extracted")
+	require.Len(t, config.Memories, 2, "both memories should be configured")
+
+	assert.True(t, config.Memories[0].MCP, "mcp should be enabled for state")
+	assert.Equal(t, "object", config.Memories[0].Schema["type"], "schema should be kept")
+	assert.False(t, config.Memories[1].MCP, "mcp should default to false")
+	assert.Empty(t, config.Memories[1].Schema, "schema should default to empty")
+	assert.True(t, hasRepoMemoryMCP(&WorkflowData{RepoMemoryConfig: config}), "workflow should use the repo-memory MCP server")
+}
+
+func TestRepoMemoryConfigRejectsInvalidSchema(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoMemory  any
+		errContains string
+	}{
+		{
+			name:        "object notation with unsupported keyword",
+			repoMemory:  map[string]any{"schema": map[string]any{"if": map[string]any{"type": "object"}}},
+			errContains: "unsupported keywords: /if",
+		},
+		{
+			name:        "array notation with non-object schema",
+			repoMemory:  []any{map[string]any{"id": "state", "schema": "state.schema.json"}},
+			errContains: "schema must be an object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolsConfig, err := ParseToolsConfig(map[string]any{"repo-memory": tt.repoMemory})
+			require.NoError(t, err, "tools config should parse")
+
+			_, err = NewCompiler().extractRepoMemoryConfig(toolsConfig)
+			require.Error(t, err, "invalid schema should be rejected")
+			assert.Contains(t, err.Error(), tt.errContains, "error should explain the rejection")
+		})
+	}
+}
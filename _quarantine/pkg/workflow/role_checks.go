@@ -224,7 +224,12 @@ func (c *Compiler) hasWorkflowRunTrigger(frontmatter map[string]any) bool {
 		}
 		// Handle string format
 		if onStr, ok := onValue.(string); ok {
-			return onStr == "workflow_run"
+			if onStr == "workflow_run" {
+				return true
+			}
+			// Trigger shorthands such as "workflow run completed for CI" expand to workflow_run
+			triggerIR, err := ParseTriggerShorthand(onStr)
+			return err == nil && triggerIR != nil && triggerIR.Event == "workflow_run"
 		}
 	}
 
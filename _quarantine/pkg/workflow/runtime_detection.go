@@ -29,6 +29,11 @@ func DetectRuntimeRequirements(workflowData *WorkflowData) []RuntimeRequirement
 		detectFromEngineSteps(workflowData.EngineConfig.Steps, requirements)
 	}
 
+	// Detect from safe-input tools
+	if workflowData.SafeInputs != nil {
+		detectFromSafeInputs(workflowData.SafeInputs, requirements)
+	}
+
 	// Apply runtime overrides from frontmatter
 	if workflowData.Runtimes != nil {
 		applyRuntimeOverrides(workflowData.Runtimes, requirements)
@@ -174,6 +179,18 @@ func detectFromEngineSteps(steps []map[string]any, requirements map[string]*Runt
 	}
 }
 
+// detectFromSafeInputs adds the Node.js runtime for TypeScript safe-input tools, which need
+// type stripping (Node.js 22.6 or later) that the runner's preinstalled Node.js may lack
+func detectFromSafeInputs(safeInputs *SafeInputsConfig, requirements map[string]*RuntimeRequirement) {
+	for _, tool := range safeInputs.Tools {
+		if tool.TS != "" {
+			runtimeSetupLog.Print("TypeScript safe-input tool detected, adding Node.js runtime requirement")
+			updateRequiredRuntime(findRuntimeByID("node"), "", requirements)
+			return
+		}
+	}
+}
+
 // updateRequiredRuntime updates the version requirement, choosing the highest version
 func updateRequiredRuntime(runtime *Runtime, newVersion string, requirements map[string]*RuntimeRequirement) {
 	existing, exists := requirements[runtime.ID]
@@ -98,6 +98,9 @@ func generateSafeInputsToolsConfig(safeInputs *SafeInputsConfig) string {
 			handler = toolName + ".py"
 		} else if toolConfig.Go != "" {
 			handler = toolName + ".go"
+		} else if toolConfig.TS != "" {
+			// TypeScript runs as an ES module with Node.js type stripping
+			handler = toolName + ".mts"
 		}
 
 		// Build env list of required environment variables (not actual secrets)
@@ -348,6 +351,46 @@ func generateSafeInputGoToolScript(toolConfig *SafeInputToolConfig) string {
 	return sb.String()
 }
 
+// safeInputModuleStdinRunner reads the inputs of a TypeScript tool module as JSON from stdin,
+// calls its execute function and writes the JSON result to stdout
+const safeInputModuleStdinRunner = `
+{
+  let input = "";
+  process.stdin.setEncoding("utf8");
+  process.stdin.on("data", chunk => (input += chunk));
+  process.stdin.on("end", async () => {
+    try {
+      const result = await execute(input.trim() ? JSON.parse(input) : {});
+      process.stdout.write(JSON.stringify(result === undefined ? null : result));
+    } catch (error) {
+      console.error(error instanceof Error ? error.stack || error.message : String(error));
+      process.exit(1);
+    }
+  });
+}
+`
+
+// generateSafeInputTypeScriptToolScript generates the ES module file for a TypeScript safe-input tool
+// The source is emitted unchanged and executed with Node.js type stripping
+// (--experimental-strip-types), so stack traces point at the frontmatter source shifted by the
+// header lines. The source must declare an execute function, which receives the inputs object.
+func generateSafeInputTypeScriptToolScript(toolConfig *SafeInputToolConfig) (string, error) {
+	safeInputsLog.Printf("Generating TypeScript tool script: tool=%s, input_count=%d", toolConfig.Name, len(toolConfig.Inputs))
+	if _, err := parseTypeScript(toolConfig.TS); err != nil {
+		return "", fmt.Errorf("safe-inputs tool '%s': ts:%w", toolConfig.Name, err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Auto-generated safe-input tool: " + toolConfig.Name + " (TypeScript)\n")
+	sb.WriteString("// " + toolConfig.Description + "\n\n")
+	sb.WriteString(toolConfig.TS)
+	if !strings.HasSuffix(toolConfig.TS, "\n") {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(safeInputModuleStdinRunner)
+	return sb.String(), nil
+}
+
 // Public wrapper functions for CLI use
 
 // GenerateSafeInputsToolsConfigForInspector generates the tools.json configuration for the safe-inputs MCP server
@@ -385,3 +428,9 @@ func GenerateSafeInputPythonToolScriptForInspector(toolConfig *SafeInputToolConf
 func GenerateSafeInputGoToolScriptForInspector(toolConfig *SafeInputToolConfig) string {
 	return generateSafeInputGoToolScript(toolConfig)
 }
+
+// GenerateSafeInputTypeScriptToolScriptForInspector generates a TypeScript tool handler
+// This is a public wrapper for use by the CLI inspector command
+func GenerateSafeInputTypeScriptToolScriptForInspector(toolConfig *SafeInputToolConfig) (string, error) {
+	return generateSafeInputTypeScriptToolScript(toolConfig)
+}
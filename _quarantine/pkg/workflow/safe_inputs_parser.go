@@ -30,10 +30,11 @@ type SafeInputToolConfig struct {
 	Name        string                     // Tool name (key from the config)
 	Description string                     // Required: tool description
 	Inputs      map[string]*SafeInputParam // Optional: input parameters
-	Script      string                     // JavaScript implementation (mutually exclusive with Run, Py, and Go)
-	Run         string                     // Shell script implementation (mutually exclusive with Script, Py, and Go)
-	Py          string                     // Python script implementation (mutually exclusive with Script, Run, and Go)
-	Go          string                     // Go script implementation (mutually exclusive with Script, Run, and Py)
+	Script      string                     // JavaScript implementation (mutually exclusive with the other implementations)
+	Run         string                     // Shell script implementation (mutually exclusive with the other implementations)
+	Py          string                     // Python script implementation (mutually exclusive with the other implementations)
+	Go          string                     // Go script implementation (mutually exclusive with the other implementations)
+	TS          string                     // TypeScript implementation, run with Node.js type stripping (mutually exclusive with the other implementations)
 	Env         map[string]string          // Environment variables (typically for secrets)
 	Timeout     int                        // Timeout in seconds for tool execution (default: 60)
 }
@@ -174,6 +175,13 @@ func parseSafeInputsMap(safeInputsMap map[string]any) (*SafeInputsConfig, bool)
 			}
 		}
 
+		// Parse ts (TypeScript implementation)
+		if ts, exists := toolMap["ts"]; exists {
+			if tsStr, ok := ts.(string); ok {
+				toolConfig.TS = tsStr
+			}
+		}
+
 		// Parse env (environment variables)
 		if env, exists := toolMap["env"]; exists {
 			if envMap, ok := env.(map[string]any); ok {
@@ -365,6 +373,13 @@ func (c *Compiler) mergeSafeInputs(main *SafeInputsConfig, importedConfigs []str
 				}
 			}
 
+			// Parse ts
+			if ts, exists := toolMap["ts"]; exists {
+				if tsStr, ok := ts.(string); ok {
+					toolConfig.TS = tsStr
+				}
+			}
+
 			// Parse env
 			if env, exists := toolMap["env"]; exists {
 				if envMap, ok := env.(map[string]any); ok {
@@ -0,0 +1,191 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"golang.org/x/mod/semver"
+)
+
+var safeInputsValidationLog = logger.New("workflow:safe_inputs_validation")
+
+// minTypeScriptNodeVersion is the first Node.js version supporting --experimental-strip-types,
+// which runs TypeScript safe-input tools
+const minTypeScriptNodeVersion = "22.6"
+
+// implementations returns the implementation fields set on a tool
+func (t *SafeInputToolConfig) implementations() []string {
+	var impls []string
+	for _, impl := range []struct {
+		name string
+		set  bool
+	}{
+		{"script", t.Script != ""},
+		{"run", t.Run != ""},
+		{"py", t.Py != ""},
+		{"go", t.Go != ""},
+		{"ts", t.TS != ""},
+	} {
+		if impl.set {
+			impls = append(impls, impl.name)
+		}
+	}
+	return impls
+}
+
+// validateSafeInputs checks the TypeScript implementations of safe-input tools. TypeScript
+// sources must parse and declare an execute function whose inputs type matches the input
+// schema, and a Node.js version set in runtimes must support type stripping.
+func validateSafeInputs(config *SafeInputsConfig, runtimes map[string]any) error {
+	if config == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(config.Tools))
+	for name := range config.Tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		tool := config.Tools[name]
+		if tool.TS == "" {
+			continue
+		}
+		if impls := tool.implementations(); len(impls) > 1 {
+			return fmt.Errorf("safe-inputs tool '%s' has multiple implementations (%s); use exactly one", name, strings.Join(impls, ", "))
+		}
+		if err := validateTypeScriptTool(tool); err != nil {
+			return fmt.Errorf("safe-inputs tool '%s': %w", name, err)
+		}
+		if err := validateTypeScriptNodeVersion(runtimes); err != nil {
+			return fmt.Errorf("safe-inputs tool '%s': %w", name, err)
+		}
+		safeInputsValidationLog.Printf("Validated safe-input tool: %s", name)
+	}
+	return nil
+}
+
+// validateTypeScriptNodeVersion checks that the Node.js version configured in runtimes.node
+// can run TypeScript tools. A major version alone selects its latest release, so only the
+// major is compared; versions that are not plain numbers (e.g. "lts/*") are left to setup-node.
+func validateTypeScriptNodeVersion(runtimes map[string]any) error {
+	nodeConfig, ok := runtimes["node"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	versionAny, ok := nodeConfig["version"]
+	if !ok {
+		return nil
+	}
+	version := strings.TrimPrefix(fmt.Sprint(versionAny), "v")
+	if !semver.IsValid("v" + version) {
+		return nil
+	}
+
+	tooOld := compareVersions(version, minTypeScriptNodeVersion) < 0
+	if !strings.Contains(version, ".") {
+		tooOld = extractMajorVersion(version) < extractMajorVersion(minTypeScriptNodeVersion)
+	}
+	if tooOld {
+		return fmt.Errorf("ts: tools run with Node.js type stripping, which requires Node.js %s or later, but runtimes.node.version is %s", minTypeScriptNodeVersion, version)
+	}
+	return nil
+}
+
+// sortedInputNames returns the input names of a tool in a stable order
+func sortedInputNames(tool *SafeInputToolConfig) []string {
+	names := make([]string, 0, len(tool.Inputs))
+	for name := range tool.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateTypeScriptTool parses a TypeScript tool and checks its execute signature
+func validateTypeScriptTool(tool *SafeInputToolConfig) error {
+	source, err := parseTypeScript(tool.TS)
+	if err != nil {
+		return fmt.Errorf("ts:%w", err)
+	}
+	sig, err := source.executeSignature()
+	if err != nil {
+		return err
+	}
+
+	if !sig.typed {
+		if len(tool.Inputs) > 0 {
+			first := sortedInputNames(tool)[0]
+			return fmt.Errorf("execute must declare the type of its inputs, e.g. 'function execute(inputs: { %s: %s })'", first, tsTypeForInput(tool.Inputs[first]))
+		}
+		return nil
+	}
+
+	for _, name := range sortedInputNames(tool) {
+		input := tool.Inputs[name]
+		prop, ok := sig.properties[name]
+		if !ok {
+			if sig.open {
+				continue
+			}
+			return fmt.Errorf("input '%s' is not a property of the execute inputs type; add '%s?: %s'", name, name, tsTypeForInput(input))
+		}
+		kind := input.Type
+		if kind == "integer" {
+			kind = "number"
+		}
+		if !prop.kinds["any"] && !prop.kinds[kind] {
+			return fmt.Errorf("input '%s' has type %s but execute expects %s", name, input.Type, describeKinds(prop.kinds))
+		}
+	}
+
+	props := make([]string, 0, len(sig.properties))
+	for name := range sig.properties {
+		props = append(props, name)
+	}
+	sort.Strings(props)
+	for _, name := range props {
+		if sig.properties[name].optional {
+			continue
+		}
+		input, ok := tool.Inputs[name]
+		if !ok {
+			return fmt.Errorf("execute requires property '%s' which is not declared in inputs", name)
+		}
+		if !input.Required && input.Default == nil {
+			return fmt.Errorf("execute requires property '%s' but input '%s' is optional without a default; mark it required, add a default or declare '%s?'", name, name, name)
+		}
+	}
+	return nil
+}
+
+// tsTypeForInput returns the TypeScript type of an input parameter
+func tsTypeForInput(input *SafeInputParam) string {
+	switch input.Type {
+	case "number", "integer":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return "unknown[]"
+	case "object":
+		return "Record<string, unknown>"
+	}
+	return "string"
+}
+
+// describeKinds formats the JSON types accepted by a TypeScript type
+func describeKinds(kinds map[string]bool) string {
+	if len(kinds) == 0 {
+		return "no value"
+	}
+	names := make([]string, 0, len(kinds))
+	for kind := range kinds {
+		names = append(names, kind)
+	}
+	sort.Strings(names)
+	return strings.Join(names, " | ")
+}
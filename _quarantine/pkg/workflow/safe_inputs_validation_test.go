@@ -0,0 +1,221 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSafeInputsTypeScript(t *testing.T) {
+	inputs := func() map[string]*SafeInputParam {
+		return map[string]*SafeInputParam{
+			"query": {Type: "string", Required: true},
+			"limit": {Type: "number", Default: 10},
+		}
+	}
+	tests := []struct {
+		name   string
+		ts     string
+		errMsg string
+	}{
+		{
+			name: "matching interface",
+			ts:   "interface Inputs { query: string; limit: number }\nexport function execute(inputs: Inputs) { return inputs.query; }",
+		},
+		{
+			name: "inline type with optional property",
+			ts:   "async function execute({ query, limit }: { query: string; limit?: number }) { return [query, limit]; }",
+		},
+		{
+			name:   "missing property",
+			ts:     "function execute(inputs: { query: string }) {}",
+			errMsg: "input 'limit' is not a property of the execute inputs type; add 'limit?: number'",
+		},
+		{
+			name:   "type mismatch",
+			ts:     "function execute(inputs: { query: number; limit: number }) {}",
+			errMsg: "input 'query' has type string but execute expects number",
+		},
+		{
+			name:   "property without input",
+			ts:     "function execute(inputs: { query: string; limit: number; sort: string }) {}",
+			errMsg: "execute requires property 'sort' which is not declared in inputs",
+		},
+		{
+			name:   "untyped inputs",
+			ts:     "function execute(inputs) {}",
+			errMsg: "execute must declare the type of its inputs, e.g. 'function execute(inputs: { limit: number })'",
+		},
+		{
+			name:   "unsupported syntax",
+			ts:     "enum Mode { Fast }\nfunction execute(inputs: any) {}",
+			errMsg: "safe-inputs tool 'search': ts:1:1: enums are not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &SafeInputsConfig{Tools: map[string]*SafeInputToolConfig{
+				"search": {Name: "search", TS: tt.ts, Inputs: inputs()},
+			}}
+			err := validateSafeInputs(config, nil)
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+
+	optional := &SafeInputsConfig{Tools: map[string]*SafeInputToolConfig{
+		"search": {Name: "search", TS: "function execute(inputs: { query: string })", Inputs: map[string]*SafeInputParam{"query": {Type: "string"}}},
+	}}
+	err := validateSafeInputs(optional, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "input 'query' is optional without a default")
+
+	multiple := &SafeInputsConfig{Tools: map[string]*SafeInputToolConfig{
+		"search": {Name: "search", TS: "function execute() {}", Script: "return 1;"},
+	}}
+	err = validateSafeInputs(multiple, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has multiple implementations (script, ts)")
+}
+
+func TestValidateSafeInputsNodeVersion(t *testing.T) {
+	config := &SafeInputsConfig{Tools: map[string]*SafeInputToolConfig{
+		"greet": {Name: "greet", TS: "function execute(inputs: {}) {}"},
+	}}
+	tests := []struct {
+		name    string
+		version any
+		errMsg  string
+	}{
+		{name: "major with type stripping", version: 22},
+		{name: "minimum version", version: "22.6.0"},
+		{name: "newer major", version: "24"},
+		{name: "alias", version: "lts/*"},
+		{name: "old major", version: 20, errMsg: "requires Node.js 22.6 or later, but runtimes.node.version is 20"},
+		{name: "old minor", version: "22.5", errMsg: "runtimes.node.version is 22.5"},
+		{name: "old float", version: 18.19, errMsg: "runtimes.node.version is 18.19"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSafeInputs(config, map[string]any{"node": map[string]any{"version": tt.version}})
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "safe-inputs tool 'greet': ts: tools run with Node.js type stripping")
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+
+	require.NoError(t, validateSafeInputs(config, nil), "the default Node.js version supports type stripping")
+
+	requirements := DetectRuntimeRequirements(&WorkflowData{SafeInputs: config})
+	require.Len(t, requirements, 1, "TypeScript tools should set up Node.js")
+	assert.Equal(t, "node", requirements[0].Runtime.ID)
+}
+
+func TestParseSafeInputsTypeScript(t *testing.T) {
+	config := ParseSafeInputs(map[string]any{
+		"safe-inputs": map[string]any{
+			"greet": map[string]any{"description": "Greet", "ts": "function execute() {}"},
+		},
+	})
+	require.NotNil(t, config)
+	assert.Equal(t, "function execute() {}", config.Tools["greet"].TS)
+
+	toolsJSON := generateSafeInputsToolsConfig(config)
+	assert.Contains(t, toolsJSON, `"handler": "greet.mts"`)
+}
+
+// runSafeInputToolFile writes a generated tool script to path and runs it with node. TypeScript
+// modules (.mts) are run with type stripping and skip the test on Node.js versions without it.
+func runSafeInputToolFile(t *testing.T, scriptPath, script string, inputs map[string]any) any {
+	t.Helper()
+	nodePath, err := exec.LookPath("node")
+	if err != nil {
+		t.Skipf("Node.js not found, skipping tool execution: %v", err)
+	}
+	var args []string
+	if strings.HasSuffix(scriptPath, ".mts") {
+		if err := exec.Command(nodePath, "--experimental-strip-types", "-e", "").Run(); err != nil {
+			t.Skip("Node.js does not support type stripping, skipping tool execution")
+		}
+		args = append(args, "--experimental-strip-types")
+	}
+	require.NoError(t, os.WriteFile(scriptPath, []byte(script), 0644))
+	input, err := json.Marshal(inputs)
+	require.NoError(t, err)
+
+	cmd := exec.Command(nodePath, append(args, scriptPath)...)
+	cmd.Stdin = strings.NewReader(string(input))
+	output, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		t.Fatalf("tool failed: %v\n%s", err, exitErr.Stderr)
+	}
+	require.NoError(t, err)
+	var result any
+	require.NoError(t, json.Unmarshal(output, &result), "output: %s", output)
+	return result
+}
+
+func TestGenerateSafeInputTypeScriptToolScript(t *testing.T) {
+	source := `import path from "path";
+interface Inputs {
+  name: string;
+  times?: number;
+}
+export async function execute({ name, times = 1 }: Inputs): Promise<{ greeting: string }> {
+  const parts: string[] = [];
+  for (let i: number = 0; i < times; i++) parts.push(` + "`Hello, ${name}!`" + `);
+  return { greeting: parts.join(" ") + path.sep };
+}`
+	tool := &SafeInputToolConfig{Name: "greet", Description: "Greet someone", TS: source}
+	script, err := generateSafeInputTypeScriptToolScript(tool)
+	require.NoError(t, err)
+	assert.Contains(t, script, "// Auto-generated safe-input tool: greet (TypeScript)")
+	assert.Contains(t, script, source+"\n", "the source should be emitted unchanged")
+
+	result := runSafeInputToolFile(t, filepath.Join(testutil.TempDir(t, "ts-*"), "tool.mts"), script, map[string]any{"name": "Ada", "times": 2})
+	assert.Equal(t, map[string]any{"greeting": "Hello, Ada! Hello, Ada!/"}, result)
+}
+
+func TestTypeScriptToolWrittenVerbatim(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "ts-verbatim-*")
+	markdownPath := filepath.Join(tmpDir, "verbatim.md")
+	require.NoError(t, os.WriteFile(markdownPath, []byte("---\n"+`on: workflow_dispatch
+engine: copilot
+safe-inputs:
+  banner:
+    description: Render a banner
+    ts: |
+      export function execute(inputs: {}): string {
+        return `+"`"+`line one   
+      line two`+"`"+`;
+      }
+---
+# Verbatim
+`), 0644))
+
+	compiler := NewCompiler(WithNoEmit(true))
+	compiler.SetQuiet(true)
+	_, lockContent, err := compiler.CompileToYAML(markdownPath)
+	require.NoError(t, err)
+	assert.Contains(t, lockContent, "cat > /opt/gh-aw/safe-inputs/banner.mts << 'EOFTS_banner'")
+	assert.Contains(t, lockContent, "          return `line one   \n", "trailing spaces inside template literals must be kept")
+	assert.Contains(t, lockContent, "export function execute(inputs: {}): string {", "types are stripped by Node.js, not the compiler")
+}
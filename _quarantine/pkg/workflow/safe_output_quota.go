@@ -0,0 +1,69 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var safeOutputQuotaLog = logger.New("workflow:safe_output_quota")
+
+// SafeOutputQuotaConfig limits how many items a safe output creates across workflow runs.
+// Items created by previous runs are found through the workflow's tracker-id marker.
+type SafeOutputQuotaConfig struct {
+	MaxPerDay int `yaml:"max-per-day,omitempty"` // Maximum number of items created in the last 24 hours
+	MaxOpen   int `yaml:"max-open,omitempty"`    // Maximum number of items that are open at the same time
+}
+
+// HasQuota returns true when a cross-run quota is configured
+func (q SafeOutputQuotaConfig) HasQuota() bool {
+	return q.MaxPerDay > 0 || q.MaxOpen > 0
+}
+
+// namedSafeOutputQuota associates a quota with the safe output it applies to
+type namedSafeOutputQuota struct {
+	name  string
+	quota SafeOutputQuotaConfig
+}
+
+// collectSafeOutputQuotas returns the safe outputs that support cross-run quotas
+func collectSafeOutputQuotas(config *SafeOutputsConfig) []namedSafeOutputQuota {
+	if config == nil {
+		return nil
+	}
+	var quotas []namedSafeOutputQuota
+	if config.CreateIssues != nil {
+		quotas = append(quotas, namedSafeOutputQuota{"create-issue", config.CreateIssues.SafeOutputQuotaConfig})
+	}
+	if config.CreateDiscussions != nil {
+		quotas = append(quotas, namedSafeOutputQuota{"create-discussion", config.CreateDiscussions.SafeOutputQuotaConfig})
+	}
+	if config.CreatePullRequests != nil {
+		quotas = append(quotas, namedSafeOutputQuota{"create-pull-request", config.CreatePullRequests.SafeOutputQuotaConfig})
+	}
+	if config.AddComments != nil {
+		quotas = append(quotas, namedSafeOutputQuota{"add-comment", config.AddComments.SafeOutputQuotaConfig})
+	}
+	return quotas
+}
+
+// validateSafeOutputQuotas validates the cross-run quotas of safe outputs. Quotas are
+// enforced by counting the items that carry the workflow's tracker-id, so a tracker-id
+// is required when any quota is configured.
+func validateSafeOutputQuotas(workflowData *WorkflowData) error {
+	for _, named := range collectSafeOutputQuotas(workflowData.SafeOutputs) {
+		if !named.quota.HasQuota() {
+			continue
+		}
+		safeOutputQuotaLog.Printf("Validating quota for %s: max-per-day=%d, max-open=%d", named.name, named.quota.MaxPerDay, named.quota.MaxOpen)
+
+		if named.name == "add-comment" && named.quota.MaxOpen > 0 {
+			return errors.New("safe-outputs.add-comment does not support max-open: comments cannot be open or closed. Use max-per-day instead")
+		}
+		if workflowData.TrackerID == "" {
+			return fmt.Errorf("safe-outputs.%s quotas (max-per-day, max-open) require a tracker-id to find items created by previous runs.\n\nAdd a tracker-id to the frontmatter, for example:\ntracker-id: daily-report-tracker", named.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSafeOutputQuotas(t *testing.T) {
+	tests := []struct {
+		name        string
+		trackerID   string
+		safeOutputs *SafeOutputsConfig
+		wantErr     string
+	}{
+		{
+			name:        "no safe outputs",
+			safeOutputs: nil,
+		},
+		{
+			name:        "no quota without tracker-id",
+			safeOutputs: &SafeOutputsConfig{CreateIssues: &CreateIssuesConfig{}},
+		},
+		{
+			name:      "issue quotas with tracker-id",
+			trackerID: "daily-report",
+			safeOutputs: &SafeOutputsConfig{CreateIssues: &CreateIssuesConfig{
+				SafeOutputQuotaConfig: SafeOutputQuotaConfig{MaxPerDay: 5, MaxOpen: 10},
+			}},
+		},
+		{
+			name: "quota without tracker-id",
+			safeOutputs: &SafeOutputsConfig{CreateDiscussions: &CreateDiscussionsConfig{
+				SafeOutputQuotaConfig: SafeOutputQuotaConfig{MaxPerDay: 1},
+			}},
+			wantErr: "safe-outputs.create-discussion quotas (max-per-day, max-open) require a tracker-id",
+		},
+		{
+			name:      "max-open on add-comment",
+			trackerID: "daily-report",
+			safeOutputs: &SafeOutputsConfig{AddComments: &AddCommentsConfig{
+				SafeOutputQuotaConfig: SafeOutputQuotaConfig{MaxOpen: 3},
+			}},
+			wantErr: "add-comment does not support max-open",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSafeOutputQuotas(&WorkflowData{TrackerID: tt.trackerID, SafeOutputs: tt.safeOutputs})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSafeOutputQuotaCompilation(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "quota-test")
+	content := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: copilot
+tracker-id: daily-report
+safe-outputs:
+  create-issue:
+    max-per-day: 5
+    max-open: 10
+  add-comment:
+    max-per-day: 20
+---
+
+# Daily Report
+
+Report on the repository.
+`
+	workflowPath := filepath.Join(tmpDir, "daily-report.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, `\"max_per_day\":5`, "handler config should carry the create-issue daily quota")
+	assert.Contains(t, lock, `\"max_open\":10`, "handler config should carry the create-issue open quota")
+	assert.Contains(t, lock, `\"max_per_day\":20`, "handler config should carry the add-comment daily quota")
+	assert.Contains(t, lock, `GH_AW_TRACKER_ID: "daily-report"`, "safe outputs job should receive the tracker-id")
+}
@@ -0,0 +1,47 @@
+// This file implements the runtime guard of schedules that cron cannot represent exactly:
+// schedules in a timezone with daylight saving time, which are emitted as one cron
+// expression per UTC offset, and calendar rules such as "first monday of month" or
+// "every 2 weeks on friday", which are emitted as weekly cron expressions.
+//
+// The guards of the emitted cron expressions are passed to a "Check schedule" step of the
+// pre-activation job, which skips scheduled runs the guard of their cron expression does
+// not allow. Runs triggered by other events are not affected.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var scheduleGuardLog = logger.New("workflow:schedule_guard")
+
+// ScheduleGuards maps emitted schedule cron expressions to their runtime guards.
+// A scheduled run is allowed when any guard of its cron expression allows it.
+type ScheduleGuards map[string][]parser.ScheduleGuard
+
+// generateScheduleGuardStep generates the pre-activation step that checks the guard of
+// the cron expression that triggered a scheduled run
+func generateScheduleGuardStep(data *WorkflowData) []string {
+	guardsJSON, err := json.Marshal(data.ScheduleGuards)
+	if err != nil {
+		scheduleGuardLog.Printf("Failed to marshal schedule guards: %v", err)
+		guardsJSON = []byte("{}")
+	}
+	scheduleGuardLog.Printf("Generating schedule guard step for %d cron expression(s)", len(data.ScheduleGuards))
+
+	var steps []string
+	steps = append(steps, "      - name: Check schedule\n")
+	steps = append(steps, fmt.Sprintf("        id: %s\n", constants.CheckScheduleStepID))
+	steps = append(steps, fmt.Sprintf("        uses: %s\n", GetActionPin("actions/github-script")))
+	steps = append(steps, "        env:\n")
+	steps = append(steps, fmt.Sprintf("          GH_AW_SCHEDULE_GUARDS: %q\n", string(guardsJSON)))
+	steps = append(steps, "        with:\n")
+	steps = append(steps, "          script: |\n")
+	steps = append(steps, generateGitHubScriptWithRequire("check_schedule.cjs"))
+	return steps
+}
@@ -0,0 +1,118 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleGuardCompilation(t *testing.T) {
+	tests := []struct {
+		name         string
+		on           string
+		wantCrons    int
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name:      "timezone with daylight saving time",
+			on:        "on:\n  schedule:\n    - cron: daily around 09:00 Europe/Kyiv\n",
+			wantCrons: 2,
+			wantContains: []string{
+				" * 1-3,10-12 *",
+				" * 3-10 *",
+				"# Friendly format: daily around 09:00 Europe/Kyiv (scattered)",
+				"name: Check schedule",
+				"check_schedule.cjs",
+				"GH_AW_SCHEDULE_GUARDS:",
+				`\"timezone\":\"Europe/Kyiv\",\"utc_offset\":120`,
+				"steps.check_schedule.outputs.schedule_ok == 'true'",
+			},
+		},
+		{
+			name:      "timezone without daylight saving time",
+			on:        "on: weekdays between 08:00 and 10:00 Asia/Tokyo\n",
+			wantCrons: 1,
+			wantContains: []string{
+				" * * 0-4",
+				"# Friendly format: weekdays between 08:00 and 10:00 Asia/Tokyo (scattered)",
+			},
+			wantMissing: []string{"name: Check schedule"},
+		},
+		{
+			name:      "calendar rule",
+			on:        "on:\n  schedule: first monday of month\n",
+			wantCrons: 1,
+			wantContains: []string{
+				" * * 1",
+				"# Friendly format: first monday of month (scattered)",
+				"name: Check schedule",
+				`\"nth\":1`,
+			},
+		},
+		{
+			name:        "plain fuzzy schedule",
+			on:          "on: daily\n",
+			wantCrons:   1,
+			wantMissing: []string{"name: Check schedule"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := testutil.TempDir(t, "schedule-guard-test")
+			content := "---\n" + tt.on + "permissions:\n  contents: read\nengine: claude\n---\n\n# Scheduled\n\nDo the work.\n"
+			workflowPath := filepath.Join(tmpDir, "scheduled.md")
+			require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+			compiler := NewCompiler()
+			compiler.SetWorkflowIdentifier("scheduled.md")
+			require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+			lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+			require.NoError(t, err)
+			lock := string(lockContent)
+
+			assert.Equal(t, tt.wantCrons, strings.Count(lock, "- cron: "), "number of emitted cron expressions")
+			for _, want := range tt.wantContains {
+				assert.Contains(t, lock, want, "lock file should contain %q", want)
+			}
+			for _, missing := range tt.wantMissing {
+				assert.NotContains(t, lock, missing, "lock file should not contain %q", missing)
+			}
+		})
+	}
+}
+
+func TestScheduleGuardsForWorkflow(t *testing.T) {
+	compiler := NewCompiler()
+	compiler.SetWorkflowIdentifier("scheduled.md")
+	frontmatter := map[string]any{"on": map[string]any{"schedule": []any{
+		map[string]any{"cron": "TZ=Europe/Kyiv 0 9 * * 1-5"},
+		map[string]any{"cron": "0 6 * 3-10 1-5"},
+	}}}
+	require.NoError(t, compiler.preprocessScheduleFields(frontmatter, "", ""))
+
+	schedule := frontmatter["on"].(map[string]any)["schedule"].([]any)
+	require.Len(t, schedule, 3, "the Kyiv schedule expands to one cron expression per UTC offset")
+	assert.Equal(t, "0 7 * 1-3,10-12 1-5", schedule[0].(map[string]any)["cron"])
+	assert.Equal(t, "0 6 * 3-10 1-5", schedule[1].(map[string]any)["cron"])
+	assert.Equal(t, "0 6 * 3-10 1-5", schedule[2].(map[string]any)["cron"])
+	assert.Equal(t, "TZ=Europe/Kyiv 0 9 * * 1-5", compiler.scheduleFriendlyFormats[0])
+	assert.Equal(t, "TZ=Europe/Kyiv 0 9 * * 1-5", compiler.scheduleFriendlyFormats[1])
+	assert.NotContains(t, compiler.scheduleFriendlyFormats, 2)
+
+	// The summer cron expression is also emitted without a guard, so it is not checked
+	guards := compiler.scheduleGuardsForWorkflow()
+	require.Len(t, guards, 1)
+	require.Len(t, guards["0 7 * 1-3,10-12 1-5"], 1)
+	assert.Equal(t, "Europe/Kyiv", guards["0 7 * 1-3,10-12 1-5"][0].Timezone)
+}
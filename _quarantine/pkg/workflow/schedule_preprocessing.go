@@ -3,6 +3,8 @@ package workflow
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/console"
@@ -13,18 +15,20 @@ import (
 var schedulePreprocessingLog = logger.New("workflow:schedule_preprocessing")
 
 // normalizeScheduleString handles the common schedule string parsing, warning emission,
-// fuzzy scattering, and validation logic. It returns the normalized cron expression
-// and the original friendly format, or an error if validation fails.
-func (c *Compiler) normalizeScheduleString(scheduleStr string, itemIndex int) (parsedCron string, friendlyFormat string, err error) {
+// fuzzy scattering, and validation logic. It returns the UTC cron expressions to emit
+// (several when a timezone's daylight saving time requires it) and the original friendly
+// format, or an error if validation fails. Runtime guards of the cron expressions are
+// recorded in c.scheduleGuards.
+func (c *Compiler) normalizeScheduleString(scheduleStr string, itemIndex int) (crons []string, friendlyFormat string, err error) {
 	// Try to parse as a schedule expression
 	parsedCron, original, err := parser.ParseSchedule(scheduleStr)
 	if err != nil {
 		// Return error for array items, but return nil error for top-level parsing
 		// (caller will handle differently based on context)
 		if itemIndex >= 0 {
-			return "", "", fmt.Errorf("invalid schedule expression in item %d: %w", itemIndex, err)
+			return nil, "", fmt.Errorf("invalid schedule expression in item %d: %w", itemIndex, err)
 		}
-		return "", "", err
+		return nil, "", err
 	}
 
 	// Warn if using explicit daily cron pattern
@@ -57,7 +61,12 @@ func (c *Compiler) normalizeScheduleString(scheduleStr string, itemIndex int) (p
 			c.IncrementWarningCount()
 			c.addScheduleWarning("Fuzzy schedule scattering without repository context. Workflows with the same name in different repositories may collide. Ensure you are in a git repository with a configured remote.")
 		}
-		scatteredCron, err := parser.ScatterSchedule(parsedCron, seed)
+		// Prefer the repository-wide plan, which keeps workflows of the repository from
+		// being scattered to the same minute
+		scatteredCron, planned := c.schedulePlan.Lookup(c.workflowIdentifier, parsedCron)
+		if !planned {
+			scatteredCron, err = parser.ScatterSchedule(parsedCron, seed)
+		}
 		if err != nil {
 			schedulePreprocessingLog.Printf("Warning: failed to scatter fuzzy schedule: %v", err)
 			// Keep the original fuzzy schedule as fallback
@@ -75,18 +84,87 @@ func (c *Compiler) normalizeScheduleString(scheduleStr string, itemIndex int) (p
 	// FUZZY cron expressions are not supported by GitHub Actions
 	if parser.IsFuzzyCron(parsedCron) {
 		if itemIndex >= 0 {
-			return "", "", fmt.Errorf("fuzzy cron expression '%s' in item %d must be scattered to proper cron format before compilation (ensure workflow identifier is set)", parsedCron, itemIndex)
+			return nil, "", fmt.Errorf("fuzzy cron expression '%s' in item %d must be scattered to proper cron format before compilation (ensure workflow identifier is set)", parsedCron, itemIndex)
 		}
-		return "", "", fmt.Errorf("fuzzy cron expression '%s' must be scattered to proper cron format before compilation (ensure workflow identifier is set)", parsedCron)
+		return nil, "", fmt.Errorf("fuzzy cron expression '%s' must be scattered to proper cron format before compilation (ensure workflow identifier is set)", parsedCron)
 	}
-	if !parser.IsCronExpression(parsedCron) {
+
+	// Convert timezones and calendar rules to UTC cron expressions with runtime guards
+	expanded, err := parser.ExpandSchedule(parsedCron)
+	if err != nil {
 		if itemIndex >= 0 {
-			return "", "", fmt.Errorf("invalid cron expression '%s' in item %d: must have exactly 5 fields (minute hour day-of-month month day-of-week)", parsedCron, itemIndex)
+			return nil, "", fmt.Errorf("invalid schedule expression in item %d: %w", itemIndex, err)
+		}
+		return nil, "", err
+	}
+	for _, expandedCron := range expanded {
+		if !parser.IsCronExpression(expandedCron.Cron) {
+			if itemIndex >= 0 {
+				return nil, "", fmt.Errorf("invalid cron expression '%s' in item %d: must have exactly 5 fields (minute hour day-of-month month day-of-week)", expandedCron.Cron, itemIndex)
+			}
+			return nil, "", fmt.Errorf("invalid cron expression '%s': must have exactly 5 fields (minute hour day-of-month month day-of-week)", expandedCron.Cron)
+		}
+		crons = append(crons, expandedCron.Cron)
+		c.addScheduleGuard(expandedCron)
+	}
+	if len(expanded) > 1 {
+		schedulePreprocessingLog.Printf("Expanded schedule %s to %d cron expressions", parsedCron, len(expanded))
+	}
+
+	return crons, original, nil
+}
+
+// addScheduleGuard records the runtime guard of an emitted cron expression. Unguarded
+// cron expressions are recorded with an empty guard so that a guard never blocks them.
+func (c *Compiler) addScheduleGuard(scheduleCron parser.ScheduleCron) {
+	if c.scheduleGuards == nil {
+		c.scheduleGuards = make(ScheduleGuards)
+	}
+	var guard parser.ScheduleGuard
+	if scheduleCron.Guard != nil {
+		guard = *scheduleCron.Guard
+	}
+	c.scheduleGuards[scheduleCron.Cron] = append(c.scheduleGuards[scheduleCron.Cron], guard)
+}
+
+// scheduleGuardsForWorkflow returns the guards of the cron expressions that must be checked
+// at run time. A run is allowed when any guard of its cron expression allows it, so cron
+// expressions that are also emitted without a guard are not checked.
+func (c *Compiler) scheduleGuardsForWorkflow() ScheduleGuards {
+	var guards ScheduleGuards
+	for cron, cronGuards := range c.scheduleGuards {
+		if slices.Contains(cronGuards, parser.ScheduleGuard{}) {
+			continue
+		}
+		if guards == nil {
+			guards = make(ScheduleGuards)
 		}
-		return "", "", fmt.Errorf("invalid cron expression '%s': must have exactly 5 fields (minute hour day-of-month month day-of-week)", parsedCron)
+		guards[cron] = cronGuards
+	}
+	return guards
+}
+
+// scheduleCronItems builds the schedule array items for the given cron expressions
+func scheduleCronItems(crons []string) []any {
+	items := make([]any, 0, len(crons))
+	for _, cron := range crons {
+		items = append(items, map[string]any{"cron": cron})
 	}
+	return items
+}
 
-	return parsedCron, original, nil
+// setScheduleFriendlyFormat records the friendly format of the schedule items emitted for
+// a schedule expression, starting at the given index of the schedule array
+func (c *Compiler) setScheduleFriendlyFormat(firstIndex, count int, friendly string) {
+	if friendly == "" {
+		return
+	}
+	if c.scheduleFriendlyFormats == nil {
+		c.scheduleFriendlyFormats = make(map[int]string)
+	}
+	for i := firstIndex; i < firstIndex+count; i++ {
+		c.scheduleFriendlyFormats[i] = friendly
+	}
 }
 
 // preprocessScheduleFields converts human-friendly schedule expressions to cron expressions
@@ -94,6 +172,9 @@ func (c *Compiler) normalizeScheduleString(scheduleStr string, itemIndex int) (p
 func (c *Compiler) preprocessScheduleFields(frontmatter map[string]any, markdownPath string, content string) error {
 	schedulePreprocessingLog.Print("Preprocessing schedule fields in frontmatter")
 
+	// The job condition of a trigger shorthand is recorded below for the current workflow
+	c.triggerCondition = ""
+
 	// Check if "on" field exists
 	onValue, exists := frontmatter["on"]
 	if !exists {
@@ -147,11 +228,14 @@ func (c *Compiler) preprocessScheduleFields(frontmatter map[string]any, markdown
 			onMap := triggerIR.ToYAMLMap()
 			frontmatter["on"] = onMap
 
+			// Filters without a native event filter are enforced by the job condition
+			c.triggerCondition = triggerIR.JobCondition()
+
 			return nil
 		}
 
 		// Try to parse as a schedule expression (only if not already recognized as another trigger type)
-		parsedCron, original, err := c.normalizeScheduleString(onStr, -1)
+		crons, original, err := c.normalizeScheduleString(onStr, -1)
 		if err != nil {
 			// Check if this is an explicit rejection of unsupported syntax
 			// vs. just not being a valid schedule at all
@@ -167,27 +251,15 @@ func (c *Compiler) preprocessScheduleFields(frontmatter map[string]any, markdown
 
 		schedulePreprocessingLog.Printf("Converting shorthand 'on: %s' to schedule + workflow_dispatch", onStr)
 
-		// Create schedule array format with workflow_dispatch
-		scheduleArray := []any{
-			map[string]any{
-				"cron": parsedCron,
-			},
-		}
-
 		// Replace the simple "on: schedule" with expanded format
 		onMap := map[string]any{
-			"schedule":          scheduleArray,
+			"schedule":          scheduleCronItems(crons),
 			"workflow_dispatch": nil,
 		}
 		frontmatter["on"] = onMap
 
 		// Store friendly format if it was converted
-		if original != "" {
-			if c.scheduleFriendlyFormats == nil {
-				c.scheduleFriendlyFormats = make(map[int]string)
-			}
-			c.scheduleFriendlyFormats[0] = original
-		}
+		c.setScheduleFriendlyFormat(0, len(crons), original)
 
 		return nil
 	}
@@ -209,26 +281,16 @@ func (c *Compiler) preprocessScheduleFields(frontmatter map[string]any, markdown
 	if scheduleStr, ok := scheduleValue.(string); ok {
 		schedulePreprocessingLog.Printf("Converting shorthand schedule string to array format: %s", scheduleStr)
 		// Convert string to array format with single item
-		parsedCron, original, err := c.normalizeScheduleString(scheduleStr, -1)
+		crons, original, err := c.normalizeScheduleString(scheduleStr, -1)
 		if err != nil {
 			return fmt.Errorf("invalid schedule expression: %w", err)
 		}
 
 		// Create array format
-		scheduleArray := []any{
-			map[string]any{
-				"cron": parsedCron,
-			},
-		}
-		onMap["schedule"] = scheduleArray
+		onMap["schedule"] = scheduleCronItems(crons)
 
 		// Store friendly format if it was converted
-		if original != "" {
-			if c.scheduleFriendlyFormats == nil {
-				c.scheduleFriendlyFormats = make(map[int]string)
-			}
-			c.scheduleFriendlyFormats[0] = original
-		}
+		c.setScheduleFriendlyFormat(0, len(crons), original)
 
 		// Add workflow_dispatch if not already present
 		if _, hasWorkflowDispatch := onMap["workflow_dispatch"]; !hasWorkflowDispatch {
@@ -250,8 +312,10 @@ func (c *Compiler) preprocessScheduleFields(frontmatter map[string]any, markdown
 		c.scheduleFriendlyFormats = make(map[int]string)
 	}
 
-	// Process each schedule item
+	// Process each schedule item. An item may expand into several items (e.g. one per
+	// UTC offset of a timezone with daylight saving time), so the array is rebuilt.
 	schedulePreprocessingLog.Printf("Processing %d schedule items", len(scheduleArray))
+	var expandedArray []any
 	for i, item := range scheduleArray {
 		itemMap, ok := item.(map[string]any)
 		if !ok {
@@ -269,20 +333,26 @@ func (c *Compiler) preprocessScheduleFields(frontmatter map[string]any, markdown
 		}
 
 		// Try to parse as human-friendly schedule
-		parsedCron, original, err := c.normalizeScheduleString(cronStr, i)
+		crons, original, err := c.normalizeScheduleString(cronStr, i)
 		if err != nil {
 			// Error already includes item index from normalizeScheduleString
 			return err
 		}
 
-		// Update the cron field with the parsed cron expression
-		itemMap["cron"] = parsedCron
-
 		// If there was an original friendly format, store it for later use
-		if original != "" {
-			c.scheduleFriendlyFormats[i] = original
+		c.setScheduleFriendlyFormat(len(expandedArray), len(crons), original)
+
+		// Update the cron field with the parsed cron expression, copying any other
+		// fields of the item to the additional items
+		itemMap["cron"] = crons[0]
+		expandedArray = append(expandedArray, itemMap)
+		for _, cron := range crons[1:] {
+			extraItem := maps.Clone(itemMap)
+			extraItem["cron"] = cron
+			expandedArray = append(expandedArray, extraItem)
 		}
 	}
+	onMap["schedule"] = expandedArray
 
 	// Add workflow_dispatch if not already present
 	if _, hasWorkflowDispatch := onMap["workflow_dispatch"]; !hasWorkflowDispatch {
@@ -293,6 +363,44 @@ func (c *Compiler) preprocessScheduleFields(frontmatter map[string]any, markdown
 	return nil
 }
 
+// ExtractScheduleExpressions returns the schedule expressions of a workflow's "on" section
+// as written (friendly or cron), in the shapes accepted by preprocessScheduleFields
+func ExtractScheduleExpressions(frontmatter map[string]any) []string {
+	switch on := frontmatter["on"].(type) {
+	case string:
+		// Other trigger shorthands take precedence over schedule expressions
+		if _, isSlashCommand, _ := parseSlashCommandShorthand(on); isSlashCommand {
+			return nil
+		}
+		if _, _, isLabelTrigger, _ := parseLabelTriggerShorthand(on); isLabelTrigger {
+			return nil
+		}
+		if triggerIR, err := ParseTriggerShorthand(on); err != nil || triggerIR != nil {
+			return nil
+		}
+		if _, _, err := parser.ParseSchedule(on); err != nil {
+			return nil
+		}
+		return []string{on}
+	case map[string]any:
+		switch schedule := on["schedule"].(type) {
+		case string:
+			return []string{schedule}
+		case []any:
+			var expressions []string
+			for _, item := range schedule {
+				if itemMap, ok := item.(map[string]any); ok {
+					if cronStr, ok := itemMap["cron"].(string); ok {
+						expressions = append(expressions, cronStr)
+					}
+				}
+			}
+			return expressions
+		}
+	}
+	return nil
+}
+
 // createTriggerParseError creates a detailed error for trigger parsing issues with source location
 func (c *Compiler) createTriggerParseError(filePath, content, triggerStr string, err error) error {
 	schedulePreprocessingLog.Printf("Creating trigger parse error for: %s", triggerStr)
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
 )
 
 // TestScheduleWorkflowDispatchAutomatic verifies that workflow_dispatch is automatically
@@ -1343,3 +1345,70 @@ func TestFriendlyFormatDeterminism(t *testing.T) {
 		t.Errorf("expected identical results for same workflow, got:\n===First===\n%s\n===Third===\n%s", result1, result3)
 	}
 }
+
+// TestExtractScheduleExpressions verifies that schedule expressions are found in every
+// shape of the "on" section and that other trigger shorthands are ignored
+func TestExtractScheduleExpressions(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontmatter map[string]any
+		expected    []string
+	}{
+		{name: "on string", frontmatter: map[string]any{"on": "daily"}, expected: []string{"daily"}},
+		{name: "slash command", frontmatter: map[string]any{"on": "/triage"}},
+		{name: "event name", frontmatter: map[string]any{"on": "push"}},
+		{name: "schedule string", frontmatter: map[string]any{"on": map[string]any{"schedule": "weekly on monday"}}, expected: []string{"weekly on monday"}},
+		{
+			name: "schedule array",
+			frontmatter: map[string]any{"on": map[string]any{"schedule": []any{
+				map[string]any{"cron": "0 9 * * 1"},
+				map[string]any{"cron": "hourly"},
+			}}},
+			expected: []string{"0 9 * * 1", "hourly"},
+		},
+		{name: "no schedule", frontmatter: map[string]any{"on": map[string]any{"issues": nil}}},
+		{name: "no on", frontmatter: map[string]any{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractScheduleExpressions(tt.frontmatter)
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestFuzzyScheduleScatteringWithSchedulePlan verifies that the repository-wide plan takes
+// precedence over scattering each workflow independently
+func TestFuzzyScheduleScatteringWithSchedulePlan(t *testing.T) {
+	plan := parser.PlanSchedules([]parser.ScheduleEntry{
+		{Workflow: "test-workflow.md", Seed: "test-workflow.md", Cron: "FUZZY:DAILY * * *"},
+		{Workflow: "other-workflow.md", Seed: "other-workflow.md", Cron: "0 0 * * *"},
+	})
+	planned, _ := plan.Lookup("test-workflow.md", "FUZZY:DAILY * * *")
+
+	compiler := NewCompiler()
+	compiler.SetWorkflowIdentifier("test-workflow.md")
+	compiler.SetSchedulePlan(plan)
+	frontmatter := map[string]any{"on": map[string]any{"schedule": "daily"}}
+	if err := compiler.preprocessScheduleFields(frontmatter, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schedule := frontmatter["on"].(map[string]any)["schedule"].([]any)
+	if got := schedule[0].(map[string]any)["cron"]; got != planned {
+		t.Errorf("expected the planned cron %q, got %q", planned, got)
+	}
+
+	// Workflows outside the plan are scattered independently
+	compiler.SetWorkflowIdentifier("new-workflow.md")
+	frontmatter = map[string]any{"on": map[string]any{"schedule": "daily"}}
+	if err := compiler.preprocessScheduleFields(frontmatter, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, _ := parser.ScatterSchedule("FUZZY:DAILY * * *", "new-workflow.md")
+	schedule = frontmatter["on"].(map[string]any)["schedule"].([]any)
+	if got := schedule[0].(map[string]any)["cron"]; got != expected {
+		t.Errorf("expected the scattered cron %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,91 @@
+// This file provides source locations for compiler errors.
+//
+// Validators run against the parsed frontmatter or the generated lock file, so their errors
+// carry no position in the workflow markdown. A validator that knows which frontmatter value
+// it rejected attaches a SourceLocation to its error; tools that report findings against the
+// markdown file (e.g. compile --sarif) collect them with SourceLocations.
+
+package workflow
+
+import (
+	"errors"
+	"strings"
+)
+
+// SourceLocation identifies the value in a workflow markdown file that caused a compiler error
+type SourceLocation struct {
+	Rule    string // Rule identifier (e.g. dangerous-permissions, policy/engines.allowed)
+	Path    string // JSON path in the frontmatter (e.g. /permissions/contents)
+	Text    string // Source text to search for when the value is not in the frontmatter
+	Message string // Message specific to this location; the first line of the error message is used when empty
+}
+
+// SourceLocatedError annotates an error with the source locations of the values that caused it.
+// The error message is unchanged.
+type SourceLocatedError struct {
+	Err       error
+	Locations []SourceLocation
+}
+
+// Error implements the error interface
+func (e *SourceLocatedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *SourceLocatedError) Unwrap() error {
+	return e.Err
+}
+
+// withSourceLocations annotates err with source locations; nil errors stay nil
+func withSourceLocations(err error, locations ...SourceLocation) error {
+	if err == nil {
+		return nil
+	}
+	return &SourceLocatedError{Err: err, Locations: locations}
+}
+
+// SourceLocations returns the source locations attached anywhere in an error tree.
+// Field names of WorkflowValidationError and ConfigurationError are dotted frontmatter
+// paths, so those errors are located as well.
+func SourceLocations(err error) []SourceLocation {
+	var locations []SourceLocation
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case nil:
+			return
+		case *SourceLocatedError:
+			message, _, _ := strings.Cut(e.Err.Error(), "\n")
+			for _, location := range e.Locations {
+				if location.Message == "" {
+					location.Message = message
+				}
+				locations = append(locations, location)
+			}
+			return
+		case *WorkflowValidationError:
+			locations = append(locations, SourceLocation{Rule: "validation", Path: dottedFieldToJSONPath(e.Field), Message: e.Reason})
+			return
+		case *ConfigurationError:
+			locations = append(locations, SourceLocation{Rule: "configuration", Path: dottedFieldToJSONPath(e.ConfigKey), Message: e.Reason})
+			return
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		default:
+			walk(errors.Unwrap(err))
+		}
+	}
+	walk(err)
+	return locations
+}
+
+// dottedFieldToJSONPath converts a dotted field name (network.firewall.allow-urls) to a JSON path
+func dottedFieldToJSONPath(field string) string {
+	if field == "" {
+		return ""
+	}
+	return "/" + strings.ReplaceAll(field, ".", "/")
+}
@@ -0,0 +1,64 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceLocations(t *testing.T) {
+	located := withSourceLocations(errors.New("engine 'codex' is not allowed"), SourceLocation{Rule: "policy/engines.allowed", Path: "/engine"})
+	assert.Equal(t, "engine 'codex' is not allowed", located.Error(), "the message is unchanged")
+	assert.Nil(t, withSourceLocations(nil, SourceLocation{Rule: "x"}))
+
+	wrapped := fmt.Errorf("workflow.md:1:1: error: %w", located)
+	assert.Equal(t, []SourceLocation{{Rule: "policy/engines.allowed", Path: "/engine", Message: "engine 'codex' is not allowed"}}, SourceLocations(wrapped))
+
+	validationErr := NewValidationError("network.firewall.allow-urls", "", "requires ssl-bump: true", "")
+	assert.Equal(t, []SourceLocation{{Rule: "validation", Path: "/network/firewall/allow-urls", Message: "requires ssl-bump: true"}}, SourceLocations(validationErr))
+
+	assert.Empty(t, SourceLocations(errors.New("plain error")))
+	assert.Empty(t, SourceLocations(nil))
+}
+
+func TestSourceLocationsThroughErrorCollector(t *testing.T) {
+	collector := NewErrorCollector(false)
+	require.NoError(t, collector.Add(withSourceLocations(errors.New("first"), SourceLocation{Rule: "a", Path: "/a"})))
+	require.NoError(t, collector.Add(errors.New("second")))
+	require.NoError(t, collector.Add(withSourceLocations(errors.New("third"), SourceLocation{Rule: "c", Path: "/c"})))
+
+	err := collector.FormattedError("test")
+	require.Error(t, err)
+	assert.Equal(t, "Found 3 test errors:\n  • first\n  • second\n  • third", err.Error())
+	locations := SourceLocations(err)
+	require.Len(t, locations, 2)
+	assert.Equal(t, "/a", locations[0].Path)
+	assert.Equal(t, "/c", locations[1].Path)
+}
+
+func TestDangerousPermissionsSourceLocations(t *testing.T) {
+	err := validateDangerousPermissions(&WorkflowData{Permissions: "permissions:\n  contents: write\n  issues: write"})
+	require.Error(t, err)
+
+	locations := SourceLocations(err)
+	require.Len(t, locations, 2)
+	assert.Equal(t, "dangerous-permissions", locations[0].Rule)
+	assert.Equal(t, "/permissions/contents", locations[0].Path)
+	assert.Equal(t, "/permissions/issues", locations[1].Path)
+}
+
+func TestTemplateInjectionSourceLocations(t *testing.T) {
+	err := validateNoTemplateInjection("jobs:\n  agent:\n    steps:\n      - run: echo \"${{ github.event.issue.title }}\"\n")
+	require.Error(t, err)
+
+	locations := SourceLocations(err)
+	require.Len(t, locations, 1)
+	assert.Equal(t, "template-injection", locations[0].Rule)
+	assert.Equal(t, "${{ github.event.issue.title }}", locations[0].Text)
+	assert.Empty(t, locations[0].Path)
+}
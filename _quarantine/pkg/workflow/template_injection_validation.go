@@ -122,7 +122,16 @@ func validateNoTemplateInjection(yamlContent string) error {
 	// If we found violations, return a detailed error
 	if len(violations) > 0 {
 		templateInjectionValidationLog.Printf("Template injection validation failed: %d violations found", len(violations))
-		return formatTemplateInjectionError(violations)
+		// The expressions are copied from the markdown into the run scripts, so their text locates them
+		locations := make([]SourceLocation, 0, len(violations))
+		for _, v := range violations {
+			locations = append(locations, SourceLocation{
+				Rule:    "template-injection",
+				Text:    v.Expression,
+				Message: fmt.Sprintf("%s is used directly in a shell command; pass it through an environment variable", v.Expression),
+			})
+		}
+		return withSourceLocations(formatTemplateInjectionError(violations), locations...)
 	}
 
 	templateInjectionValidationLog.Print("Template injection validation passed")
@@ -3,7 +3,6 @@ package workflow
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -23,7 +22,7 @@ func (c *Compiler) applyDefaults(data *WorkflowData, markdownPath string) error
 	isCommandTrigger := false
 	if data.On == "" {
 		// Check the original frontmatter for command trigger
-		content, err := os.ReadFile(markdownPath)
+		content, err := c.readMarkdownFile(markdownPath)
 		if err == nil {
 			result, err := parser.ExtractFrontmatterFromContent(string(content))
 			if err == nil {
@@ -375,6 +375,8 @@ type MCPGatewayRuntimeConfig struct {
 	Domain         string            `yaml:"domain,omitempty"`         // Domain for gateway URL (localhost or host.docker.internal)
 	Mounts         []string          `yaml:"mounts,omitempty"`         // Volume mounts for the gateway container (format: "source:dest:mode")
 	PayloadDir     string            `yaml:"payload-dir,omitempty"`    // Directory path for storing large payload JSON files (must be absolute path)
+	Record         bool              `yaml:"record,omitempty"`         // Record full MCP request/response payloads for replay
+	RecordDir      string            `yaml:"-"`                        // Directory the recording proxy writes recordings to (set when Record is enabled)
 }
 
 // HasTool checks if a tool is present in the configuration
@@ -23,6 +23,11 @@ type TriggerIR struct {
 	// Conditions contains job-level conditions for complex filtering
 	Conditions []string
 
+	// EnforceConditions compiles Conditions into the job if condition. The merged pull
+	// request, labeled issue and Dependabot shorthands leave it unset and compile to the
+	// event alone.
+	EnforceConditions bool
+
 	// AdditionalEvents contains other events to include (e.g., workflow_dispatch)
 	AdditionalEvents map[string]any
 }
@@ -85,6 +90,11 @@ func ParseTriggerShorthand(input string) (*TriggerIR, error) {
 		return ir, err
 	}
 
+	// 10. CI and deployment patterns
+	if ir, err := parseCIDeploymentTrigger(input); ir != nil || err != nil {
+		return ir, err
+	}
+
 	// Not a recognized trigger shorthand
 	return nil, nil
 }
@@ -125,6 +135,26 @@ func (ir *TriggerIR) ToYAMLMap() map[string]any {
 	return result
 }
 
+// JobCondition returns the job if condition enforcing the trigger's conditions, or an empty
+// string when it has none or does not enforce them. The conditions only apply to the
+// trigger's own event so that the additional events (such as workflow_dispatch) still run
+// the workflow.
+func (ir *TriggerIR) JobCondition() string {
+	if !ir.EnforceConditions || len(ir.Conditions) == 0 {
+		return ""
+	}
+
+	var condition ConditionNode = &ExpressionNode{Expression: ir.Conditions[0]}
+	for _, expression := range ir.Conditions[1:] {
+		condition = BuildAnd(condition, &ExpressionNode{Expression: expression})
+	}
+
+	if len(ir.AdditionalEvents) > 0 {
+		condition = BuildOr(BuildNotEquals(BuildPropertyAccess("github.event_name"), BuildStringLiteral(ir.Event)), condition)
+	}
+	return condition.Render()
+}
+
 // parseSlashCommandTrigger parses slash command triggers like "/test"
 func parseSlashCommandTrigger(input string) (*TriggerIR, error) {
 	commandName, isSlashCommand, err := parseSlashCommandShorthand(input)
@@ -170,8 +200,15 @@ func parseSourceControlTrigger(input string) (*TriggerIR, error) {
 	case "push":
 		return parsePushTrigger(tokens)
 	case "pull", "pull_request":
-		// Normalize "pull" to "pull_request"
-		normalizedTokens := append([]string{"pull_request"}, tokens[1:]...)
+		// Normalize "pull" and "pull request" to "pull_request"
+		rest := tokens[1:]
+		if tokens[0] == "pull" && len(rest) > 0 && rest[0] == "request" {
+			rest = rest[1:]
+		}
+		if len(rest) > 0 && rest[0] == "review" {
+			return parsePullRequestReviewTrigger(rest)
+		}
+		normalizedTokens := append([]string{"pull_request"}, rest...)
 		return parsePullRequestTrigger(normalizedTokens)
 	default:
 		return nil, nil
@@ -292,6 +329,54 @@ func parsePullRequestTrigger(tokens []string) (*TriggerIR, error) {
 	return nil, fmt.Errorf("invalid pull_request trigger format: '%s'. Expected format: 'pull_request <type>' or 'pull_request affecting <path>'. Valid types: opened, edited, closed, reopened, synchronize, merged, labeled, unlabeled. Example: 'pull_request opened' or 'pull_request affecting src/**'", strings.Join(tokens, " "))
 }
 
+// parsePullRequestReviewTrigger parses pull request review triggers like
+// "pull request review submitted approved"
+func parsePullRequestReviewTrigger(tokens []string) (*TriggerIR, error) {
+	activityType := "submitted"
+	if len(tokens) >= 2 {
+		activityType = tokens[1]
+	}
+
+	validTypes := map[string]bool{
+		"submitted": true,
+		"edited":    true,
+		"dismissed": true,
+	}
+
+	if !validTypes[activityType] {
+		return nil, fmt.Errorf("invalid pull request review activity type: '%s'. Valid types: submitted, edited, dismissed. Example: 'pull request review submitted approved'", activityType)
+	}
+
+	ir := &TriggerIR{
+		Event: "pull_request_review",
+		Types: []string{activityType},
+		AdditionalEvents: map[string]any{
+			"workflow_dispatch": nil,
+		},
+	}
+
+	if len(tokens) < 3 {
+		return ir, nil
+	}
+
+	// Review state filter: "pull request review submitted approved"
+	validStates := map[string]bool{
+		"approved":          true,
+		"changes_requested": true,
+		"commented":         true,
+	}
+
+	state := tokens[2]
+	if activityType != "submitted" || len(tokens) > 3 || !validStates[state] {
+		return nil, fmt.Errorf("invalid pull request review trigger format: 'pull request %s'. Expected format: 'pull request review <type>' or 'pull request review submitted <state>'. Valid states: approved, changes_requested, commented. Example: 'pull request review submitted approved'", strings.Join(tokens, " "))
+	}
+
+	// pull_request_review has no native filter for the review state
+	ir.Conditions = []string{fmt.Sprintf("github.event.review.state == '%s'", state)}
+	ir.EnforceConditions = true
+	return ir, nil
+}
+
 // parseIssueDiscussionTrigger parses issue and discussion triggers
 func parseIssueDiscussionTrigger(input string) (*TriggerIR, error) {
 	tokens := strings.Fields(input)
@@ -304,6 +389,8 @@ func parseIssueDiscussionTrigger(input string) (*TriggerIR, error) {
 		return parseIssueTrigger(tokens)
 	case "discussion":
 		return parseDiscussionTrigger(tokens)
+	case "milestone":
+		return parseMilestoneTrigger(tokens)
 	default:
 		return nil, nil
 	}
@@ -392,6 +479,31 @@ func parseDiscussionTrigger(tokens []string) (*TriggerIR, error) {
 	}, nil
 }
 
+// parseMilestoneTrigger parses milestone triggers
+func parseMilestoneTrigger(tokens []string) (*TriggerIR, error) {
+	activityType := tokens[1]
+
+	validTypes := map[string]bool{
+		"created": true,
+		"closed":  true,
+		"opened":  true,
+		"edited":  true,
+		"deleted": true,
+	}
+
+	if !validTypes[activityType] || len(tokens) > 2 {
+		return nil, fmt.Errorf("invalid milestone trigger format: '%s'. Expected format: 'milestone <type>'. Valid types: created, closed, opened, edited, deleted. Example: 'milestone closed'", strings.Join(tokens, " "))
+	}
+
+	return &TriggerIR{
+		Event: "milestone",
+		Types: []string{activityType},
+		AdditionalEvents: map[string]any{
+			"workflow_dispatch": nil,
+		},
+	}, nil
+}
+
 // parseManualTrigger parses manual invocation triggers
 func parseManualTrigger(input string) (*TriggerIR, error) {
 	tokens := strings.Fields(input)
@@ -471,6 +583,8 @@ func parseReleaseRepositoryTrigger(input string) (*TriggerIR, error) {
 		return parseReleaseTrigger(tokens)
 	case "repository":
 		return parseRepositoryTrigger(tokens)
+	case "package":
+		return parsePackageTrigger(tokens)
 	default:
 		return nil, nil
 	}
@@ -507,6 +621,23 @@ func parseReleaseTrigger(tokens []string) (*TriggerIR, error) {
 	}, nil
 }
 
+// parsePackageTrigger parses GitHub Packages triggers
+func parsePackageTrigger(tokens []string) (*TriggerIR, error) {
+	activityType := tokens[1]
+
+	if (activityType != "published" && activityType != "updated") || len(tokens) > 2 {
+		return nil, fmt.Errorf("invalid package trigger format: '%s'. Expected format: 'package <type>'. Valid types: published, updated. Example: 'package published'", strings.Join(tokens, " "))
+	}
+
+	return &TriggerIR{
+		Event: "registry_package",
+		Types: []string{activityType},
+		AdditionalEvents: map[string]any{
+			"workflow_dispatch": nil,
+		},
+	}, nil
+}
+
 // parseRepositoryTrigger parses repository lifecycle triggers
 func parseRepositoryTrigger(tokens []string) (*TriggerIR, error) {
 	if len(tokens) < 2 {
@@ -603,3 +734,200 @@ func parseExternalTrigger(input string) (*TriggerIR, error) {
 
 	return nil, nil
 }
+
+// conclusionShorthands maps the outcome words of check and workflow run triggers
+// to the conclusion reported by GitHub
+var conclusionShorthands = map[string]string{
+	"failed":    "failure",
+	"succeeded": "success",
+	"cancelled": "cancelled",
+	"timed_out": "timed_out",
+}
+
+// parseCIDeploymentTrigger parses check, workflow run, deployment, and merge queue triggers
+func parseCIDeploymentTrigger(input string) (*TriggerIR, error) {
+	tokens := strings.Fields(input)
+	if len(tokens) < 2 {
+		return nil, nil
+	}
+
+	switch {
+	case tokens[0] == "check" && (tokens[1] == "run" || tokens[1] == "suite"):
+		return parseCheckTrigger(tokens)
+	case tokens[0] == "workflow" && tokens[1] == "run":
+		return parseWorkflowRunTrigger(tokens)
+	case tokens[0] == "deployment" && tokens[1] == "status":
+		return parseDeploymentStatusTrigger(tokens)
+	case tokens[0] == "merge" && tokens[1] == "group":
+		if len(tokens) > 2 {
+			return nil, fmt.Errorf("invalid merge group trigger format: '%s'. Expected format: 'merge group'", input)
+		}
+		return &TriggerIR{
+			Event: "merge_group",
+			Types: []string{"checks_requested"},
+			AdditionalEvents: map[string]any{
+				"workflow_dispatch": nil,
+			},
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// parseCheckTrigger parses check run and check suite triggers like "check run failed"
+func parseCheckTrigger(tokens []string) (*TriggerIR, error) {
+	kind := tokens[1]
+	event := "check_" + kind
+
+	// GitHub Actions only supports the completed activity type for check suites
+	validTypes := map[string]bool{"completed": true}
+	typeNames := "completed"
+	if kind == "run" {
+		validTypes = map[string]bool{
+			"created":          true,
+			"rerequested":      true,
+			"completed":        true,
+			"requested_action": true,
+		}
+		typeNames = "created, rerequested, completed, requested_action"
+	}
+
+	format := fmt.Sprintf("Expected format: 'check %s <type>' or 'check %s <outcome>'. Valid types: %s. Valid outcomes: failed, succeeded, cancelled, timed_out. Example: 'check %s failed'", kind, kind, typeNames, kind)
+	if len(tokens) != 3 {
+		return nil, fmt.Errorf("invalid check %s trigger format: '%s'. %s", kind, strings.Join(tokens, " "), format)
+	}
+
+	activityType := tokens[2]
+	if validTypes[activityType] {
+		return &TriggerIR{
+			Event: event,
+			Types: []string{activityType},
+			AdditionalEvents: map[string]any{
+				"workflow_dispatch": nil,
+			},
+		}, nil
+	}
+
+	conclusion, ok := conclusionShorthands[activityType]
+	if !ok {
+		return nil, fmt.Errorf("invalid check %s activity type: '%s'. %s", kind, activityType, format)
+	}
+
+	// Check events have no native filter for the conclusion
+	return &TriggerIR{
+		Event:             event,
+		Types:             []string{"completed"},
+		Conditions:        []string{fmt.Sprintf("github.event.%s.conclusion == '%s'", event, conclusion)},
+		EnforceConditions: true,
+		AdditionalEvents: map[string]any{
+			"workflow_dispatch": nil,
+		},
+	}, nil
+}
+
+// parseWorkflowRunTrigger parses workflow run triggers like
+// "workflow run completed for "CI" on main"
+func parseWorkflowRunTrigger(tokens []string) (*TriggerIR, error) {
+	const format = `Expected format: 'workflow run <type> for <workflow> [on <branch>]'. Valid types: completed, requested, in_progress, failed, succeeded, cancelled, timed_out. Example: 'workflow run completed for "CI" on main'`
+
+	if len(tokens) < 3 {
+		return nil, fmt.Errorf("workflow run trigger requires an activity type and a workflow. %s", format)
+	}
+
+	activityType := tokens[2]
+	ir := &TriggerIR{
+		Event: "workflow_run",
+		AdditionalEvents: map[string]any{
+			"workflow_dispatch": nil,
+		},
+	}
+	switch activityType {
+	case "completed", "requested", "in_progress":
+		ir.Types = []string{activityType}
+	default:
+		conclusion, ok := conclusionShorthands[activityType]
+		if !ok {
+			return nil, fmt.Errorf("invalid workflow run activity type: '%s'. %s", activityType, format)
+		}
+		// workflow_run has no native filter for the conclusion
+		ir.Types = []string{"completed"}
+		ir.Conditions = []string{fmt.Sprintf("github.event.workflow_run.conclusion == '%s'", conclusion)}
+		ir.EnforceConditions = true
+	}
+
+	if len(tokens) < 4 || tokens[3] != "for" {
+		return nil, fmt.Errorf("workflow run trigger requires the workflow to watch: '%s'. %s", strings.Join(tokens, " "), format)
+	}
+
+	// The workflow name may be quoted and contain spaces: for "Build and Test" on main
+	rest := strings.Join(tokens[4:], " ")
+	var workflowName string
+	if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+		end := strings.IndexByte(rest[1:], rest[0])
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated workflow name in workflow run trigger: '%s'. %s", strings.Join(tokens, " "), format)
+		}
+		workflowName = rest[1 : end+1]
+		rest = strings.TrimSpace(rest[end+2:])
+	} else if before, after, found := strings.Cut(rest, " on "); found {
+		workflowName, rest = before, "on "+after
+	} else {
+		workflowName, rest = rest, ""
+	}
+
+	if strings.TrimSpace(workflowName) == "" {
+		return nil, fmt.Errorf("workflow run trigger requires the workflow to watch: '%s'. %s", strings.Join(tokens, " "), format)
+	}
+	ir.Filters = map[string]any{
+		"workflows": []string{workflowName},
+	}
+
+	if rest != "" {
+		branch, isBranch := strings.CutPrefix(rest, "on ")
+		branch = strings.TrimSpace(branch)
+		if !isBranch || branch == "" || strings.Contains(branch, " ") {
+			return nil, fmt.Errorf("invalid workflow run trigger format: '%s'. %s", strings.Join(tokens, " "), format)
+		}
+		ir.Filters["branches"] = []string{branch}
+	}
+
+	return ir, nil
+}
+
+// parseDeploymentStatusTrigger parses deployment status triggers like "deployment status failure"
+func parseDeploymentStatusTrigger(tokens []string) (*TriggerIR, error) {
+	ir := &TriggerIR{
+		Event:   "deployment_status",
+		Filters: map[string]any{}, // Empty map to avoid null in YAML
+		AdditionalEvents: map[string]any{
+			"workflow_dispatch": nil,
+		},
+	}
+	if len(tokens) == 2 {
+		return ir, nil
+	}
+
+	validStates := map[string]bool{
+		"error":       true,
+		"failure":     true,
+		"inactive":    true,
+		"in_progress": true,
+		"pending":     true,
+		"queued":      true,
+		"success":     true,
+		"waiting":     true,
+	}
+
+	state := tokens[2]
+	if conclusion, ok := conclusionShorthands[state]; ok && validStates[conclusion] {
+		state = conclusion
+	}
+	if !validStates[state] || len(tokens) > 3 {
+		return nil, fmt.Errorf("invalid deployment status trigger format: '%s'. Expected format: 'deployment status [<state>]'. Valid states: error, failure, inactive, in_progress, pending, queued, success, waiting. Example: 'deployment status failure'", strings.Join(tokens, " "))
+	}
+
+	// deployment_status has no native filter for the state
+	ir.Conditions = []string{fmt.Sprintf("github.event.deployment_status.state == '%s'", state)}
+	ir.EnforceConditions = true
+	return ir, nil
+}
@@ -3,7 +3,13 @@
 package workflow
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
 )
 
 func TestParseTriggerShorthand(t *testing.T) {
@@ -219,6 +225,79 @@ func TestParseTriggerShorthand(t *testing.T) {
 			},
 		},
 
+		// CI and Deployment Patterns
+		{
+			name:      "check run failed",
+			input:     "check run failed",
+			wantEvent: "check_run",
+			wantTypes: []string{"completed"},
+			wantConds: []string{"github.event.check_run.conclusion == 'failure'"},
+		},
+		{
+			name:      "check suite completed",
+			input:     "check suite completed",
+			wantEvent: "check_suite",
+			wantTypes: []string{"completed"},
+		},
+		{
+			name:      "workflow run completed on branch",
+			input:     `workflow run completed for "CI" on main`,
+			wantEvent: "workflow_run",
+			wantTypes: []string{"completed"},
+			wantFilters: map[string]any{
+				"workflows": []string{"CI"},
+				"branches":  []string{"main"},
+			},
+		},
+		{
+			name:      "workflow run failed with unquoted name",
+			input:     "workflow run failed for Build and Test",
+			wantEvent: "workflow_run",
+			wantTypes: []string{"completed"},
+			wantFilters: map[string]any{
+				"workflows": []string{"Build and Test"},
+			},
+			wantConds: []string{"github.event.workflow_run.conclusion == 'failure'"},
+		},
+		{
+			name:        "deployment status failure",
+			input:       "deployment status failure",
+			wantEvent:   "deployment_status",
+			wantFilters: map[string]any{},
+			wantConds:   []string{"github.event.deployment_status.state == 'failure'"},
+		},
+		{
+			name:      "merge group",
+			input:     "merge group",
+			wantEvent: "merge_group",
+			wantTypes: []string{"checks_requested"},
+		},
+		{
+			name:      "pull request review submitted approved",
+			input:     "pull request review submitted approved",
+			wantEvent: "pull_request_review",
+			wantTypes: []string{"submitted"},
+			wantConds: []string{"github.event.review.state == 'approved'"},
+		},
+		{
+			name:      "pull request opened",
+			input:     "pull request opened",
+			wantEvent: "pull_request",
+			wantTypes: []string{"opened"},
+		},
+		{
+			name:      "milestone closed",
+			input:     "milestone closed",
+			wantEvent: "milestone",
+			wantTypes: []string{"closed"},
+		},
+		{
+			name:      "package published",
+			input:     "package published",
+			wantEvent: "registry_package",
+			wantTypes: []string{"published"},
+		},
+
 		// Invalid/Unrecognized Patterns
 		{
 			name:    "not a trigger shorthand",
@@ -493,6 +572,134 @@ func TestParseIssueDiscussionTriggers(t *testing.T) {
 }
 
 // Helper function to compare maps
+func TestTriggerShorthandErrors(t *testing.T) {
+	tests := []struct {
+		input          string
+		errorSubstring string
+	}{
+		{"check run exploded", "invalid check run activity type: 'exploded'"},
+		{"check suite created", "Valid types: completed."},
+		{"workflow run completed", "requires the workflow to watch"},
+		{"workflow run done for CI", "invalid workflow run activity type: 'done'"},
+		{`workflow run completed for "CI on main`, "unterminated workflow name"},
+		{"workflow run completed for CI on main and develop", "Expected format: 'workflow run <type> for <workflow> [on <branch>]'"},
+		{"deployment status broken", "Valid states: error, failure"},
+		{"merge group now", "Expected format: 'merge group'"},
+		{"pull request review submitted happy", "Valid states: approved, changes_requested, commented"},
+		{"pull request review dismissed approved", "'pull request review submitted <state>'"},
+		{"milestone finished", "Example: 'milestone closed'"},
+		{"package deleted", "Valid types: published, updated"},
+	}
+	for _, tt := range tests {
+		_, err := ParseTriggerShorthand(tt.input)
+		if err == nil || !strings.Contains(err.Error(), tt.errorSubstring) {
+			t.Errorf("ParseTriggerShorthand(%q): expected error containing %q, got %v", tt.input, tt.errorSubstring, err)
+		}
+	}
+}
+
+func TestTriggerIRJobCondition(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"milestone closed", ""},
+		{"check run failed", "(github.event_name != 'check_run') || (github.event.check_run.conclusion == 'failure')"},
+		{"workflow run failed for CI", "(github.event_name != 'workflow_run') || (github.event.workflow_run.conclusion == 'failure')"},
+		{"deployment status failure", "(github.event_name != 'deployment_status') || (github.event.deployment_status.state == 'failure')"},
+		{"pull_request merged", ""},
+		{"issue opened labeled bug", ""},
+		{"dependabot pull request", ""},
+	}
+	for _, tt := range tests {
+		ir, err := ParseTriggerShorthand(tt.input)
+		if err != nil {
+			t.Fatalf("ParseTriggerShorthand(%q) unexpected error = %v", tt.input, err)
+		}
+		if got := ir.JobCondition(); got != tt.want {
+			t.Errorf("JobCondition() for %q = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestWorkflowRunTriggerIncludesWorkflowDispatch(t *testing.T) {
+	for _, input := range []string{"workflow run completed for CI", `workflow run failed for "Build and Test" on main`} {
+		ir, err := ParseTriggerShorthand(input)
+		if err != nil {
+			t.Fatalf("ParseTriggerShorthand(%q) unexpected error = %v", input, err)
+		}
+		if _, ok := ir.ToYAMLMap()["workflow_dispatch"]; !ok {
+			t.Errorf("ParseTriggerShorthand(%q) expected workflow_dispatch, got %v", input, ir.ToYAMLMap())
+		}
+	}
+}
+
+func TestTriggerShorthandConditionCompiledIntoJobIf(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "trigger-condition-*")
+	workflowPath := filepath.Join(tmpDir, "approved.md")
+	content := `---
+on: pull request review submitted approved
+if: github.repository == 'org/repo'
+permissions:
+  contents: read
+engine: copilot
+---
+# Approved review
+`
+	if err := os.WriteFile(workflowPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	compiler := NewCompiler()
+	if err := compiler.CompileWorkflow(workflowPath); err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock := string(lockContent)
+	if !strings.Contains(lock, "pull_request_review:") {
+		t.Errorf("expected a pull_request_review trigger, got:\n%s", lock)
+	}
+	if !strings.Contains(lock, "github.event.review.state == 'approved'") {
+		t.Errorf("expected the review state to be checked in a job condition")
+	}
+	if !strings.Contains(lock, "github.repository == 'org/repo'") {
+		t.Errorf("expected the workflow if condition to be kept")
+	}
+}
+
+func TestTriggerShorthandConditionNotCompiledForMergedPullRequest(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "trigger-condition-merged-*")
+	workflowPath := filepath.Join(tmpDir, "merged.md")
+	content := `---
+on: pull_request merged
+permissions:
+  contents: read
+engine: copilot
+---
+# Merged pull request
+`
+	if err := os.WriteFile(workflowPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	compiler := NewCompiler()
+	if err := compiler.CompileWorkflow(workflowPath); err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(lockContent), "github.event.pull_request.merged == true") {
+		t.Errorf("expected the merged pull request shorthand to compile without a job condition")
+	}
+}
+
 func mapsEqual(a, b map[string]any) bool {
 	if len(a) != len(b) {
 		return false
@@ -0,0 +1,941 @@
+// This file reads the type declarations of safe-inputs `ts:` tools.
+//
+// TypeScript tools run with Node.js' built-in type stripping, so their sources are written to
+// the runner unchanged. The compiler only tokenizes the source to find the execute function,
+// resolve the interfaces and type aliases describing its inputs and cross-check them against
+// the input schema in the frontmatter. Type checking is not performed. Enums and namespaces
+// are rejected here because type stripping cannot run them; Node.js reports any other syntax
+// that needs a transform when the tool starts.
+
+package workflow
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var typescriptLog = logger.New("workflow:typescript")
+
+type tsTokenKind int
+
+const (
+	tsIdent tsTokenKind = iota
+	tsPunct
+	tsString
+	tsTemplate
+	tsNumber
+	tsRegex
+)
+
+// tsToken is a token of a TypeScript source; comments and whitespace are not tokens
+type tsToken struct {
+	kind     tsTokenKind
+	text     string
+	start    int
+	end      int
+	nlBefore bool // a line break separates the token from the previous one
+}
+
+// tsPunctuators lists multi-character punctuators, longest first. '>' is always a token of
+// its own so that nested generic type arguments close correctly.
+var tsPunctuators = []string{
+	"...", "===", "!==", "**=", "<<=", "&&=", "||=", "??=",
+	"==", "!=", "=>", "**", "&&", "||", "??", "?.", "++", "--",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "<<", "<=",
+}
+
+// tsRegexKeywords lists keywords after which a slash starts a regular expression
+var tsRegexKeywords = map[string]bool{
+	"return": true, "typeof": true, "instanceof": true, "in": true, "of": true, "new": true,
+	"delete": true, "void": true, "throw": true, "case": true, "do": true, "else": true,
+	"yield": true, "await": true,
+}
+
+// tsLexer splits a TypeScript source into tokens
+type tsLexer struct {
+	src    string
+	pos    int
+	tokens []tsToken
+	nl     bool
+}
+
+func tokenizeTypeScript(src string) ([]tsToken, error) {
+	l := &tsLexer{src: src}
+	if strings.HasPrefix(src, "#!") {
+		for l.pos < len(src) && src[l.pos] != '\n' {
+			l.pos++
+		}
+	}
+	for {
+		tok, ok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return l.tokens, nil
+		}
+		l.tokens = append(l.tokens, tok)
+	}
+}
+
+// errorf returns an error located at a byte offset of the source
+func (l *tsLexer) errorf(pos int, format string, args ...any) error {
+	line, col := tsPosition(l.src, pos)
+	return fmt.Errorf("%d:%d: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+// tsPosition converts a byte offset into a 1-based line and column
+func tsPosition(src string, pos int) (int, int) {
+	line := 1 + strings.Count(src[:pos], "\n")
+	col := pos - strings.LastIndex(src[:pos], "\n")
+	return line, col
+}
+
+// skipSpace skips whitespace and comments, recording line breaks
+func (l *tsLexer) skipSpace() error {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.nl = true
+			l.pos++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\v' || c == '\f':
+			l.pos++
+		case strings.HasPrefix(l.src[l.pos:], "//"):
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				return l.errorf(l.pos, "unterminated comment")
+			}
+			if strings.Contains(l.src[l.pos:l.pos+end+2], "\n") {
+				l.nl = true
+			}
+			l.pos += end + 4
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			if !unicode.IsSpace(r) && r != '\uFEFF' {
+				return nil
+			}
+			if r == '\u2028' || r == '\u2029' {
+				l.nl = true
+			}
+			l.pos += size
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// regexAllowed reports whether a slash at the current position starts a regular expression
+func (l *tsLexer) regexAllowed() bool {
+	if len(l.tokens) == 0 {
+		return true
+	}
+	prev := l.tokens[len(l.tokens)-1]
+	switch prev.kind {
+	case tsIdent:
+		return tsRegexKeywords[prev.text]
+	case tsPunct:
+		return prev.text != ")" && prev.text != "]" && prev.text != "}"
+	}
+	return false
+}
+
+func (l *tsLexer) next() (tsToken, bool, error) {
+	l.nl = false
+	if err := l.skipSpace(); err != nil {
+		return tsToken{}, false, err
+	}
+	if l.pos >= len(l.src) {
+		return tsToken{}, false, nil
+	}
+
+	start := l.pos
+	tok := tsToken{start: start, nlBefore: l.nl}
+	c := l.src[start]
+	switch {
+	case isTSIdentStart(l.src[start:]) || c == '#':
+		l.pos++
+		for l.pos < len(l.src) && isTSIdentPart(l.src[l.pos:]) {
+			_, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			l.pos += size
+		}
+		tok.kind = tsIdent
+	case c >= '0' && c <= '9' || (c == '.' && start+1 < len(l.src) && l.src[start+1] >= '0' && l.src[start+1] <= '9'):
+		for l.pos < len(l.src) {
+			ch := l.src[l.pos]
+			if (ch == '+' || ch == '-') && (l.src[l.pos-1] == 'e' || l.src[l.pos-1] == 'E') && !strings.HasPrefix(strings.ToLower(l.src[start:]), "0x") {
+				l.pos++
+				continue
+			}
+			if !(ch == '.' || ch == '_' || ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z') {
+				break
+			}
+			l.pos++
+		}
+		tok.kind = tsNumber
+	case c == '"' || c == '\'':
+		if err := l.scanString(c); err != nil {
+			return tsToken{}, false, err
+		}
+		tok.kind = tsString
+	case c == '`':
+		if err := l.scanTemplate(); err != nil {
+			return tsToken{}, false, err
+		}
+		tok.kind = tsTemplate
+	case c == '/' && l.regexAllowed():
+		if err := l.scanRegex(); err != nil {
+			return tsToken{}, false, err
+		}
+		tok.kind = tsRegex
+	default:
+		tok.kind = tsPunct
+		l.pos++
+		for _, punct := range tsPunctuators {
+			if strings.HasPrefix(l.src[start:], punct) {
+				// "?." followed by a digit is a conditional expression, e.g. a?.5:b
+				if punct == "?." && start+2 < len(l.src) && l.src[start+2] >= '0' && l.src[start+2] <= '9' {
+					continue
+				}
+				l.pos = start + len(punct)
+				break
+			}
+		}
+	}
+	tok.end = l.pos
+	tok.text = l.src[start:l.pos]
+	return tok, true, nil
+}
+
+func isTSIdentStart(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r == '_' || r == '$' || r == '\\' || unicode.IsLetter(r)
+}
+
+func isTSIdentPart(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return isTSIdentStart(s) || unicode.IsDigit(r) || r == '\u200C' || r == '\u200D'
+}
+
+func (l *tsLexer) scanString(quote byte) error {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos += 2
+			continue
+		case '\n':
+			return l.errorf(start, "unterminated string")
+		case quote:
+			l.pos++
+			return nil
+		}
+		l.pos++
+	}
+	return l.errorf(start, "unterminated string")
+}
+
+// scanTemplate scans a template literal, including the code of its ${} substitutions
+func (l *tsLexer) scanTemplate() error {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.src) {
+		switch {
+		case l.src[l.pos] == '\\':
+			l.pos += 2
+		case l.src[l.pos] == '`':
+			l.pos++
+			return nil
+		case strings.HasPrefix(l.src[l.pos:], "${"):
+			l.pos += 2
+			// Tokenize the substitution on a nested lexer so strings and braces inside it are skipped
+			inner := &tsLexer{src: l.src, pos: l.pos}
+			depth := 0
+			for {
+				tok, ok, err := inner.next()
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return l.errorf(start, "unterminated template literal")
+				}
+				if tok.text == "{" {
+					depth++
+				} else if tok.text == "}" {
+					if depth == 0 {
+						break
+					}
+					depth--
+				}
+				inner.tokens = append(inner.tokens, tok)
+			}
+			l.pos = inner.pos
+		default:
+			l.pos++
+		}
+	}
+	return l.errorf(start, "unterminated template literal")
+}
+
+func (l *tsLexer) scanRegex() error {
+	start := l.pos
+	l.pos++
+	inClass := false
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos += 2
+			continue
+		case '\n':
+			return l.errorf(start, "unterminated regular expression")
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				l.pos++
+				for l.pos < len(l.src) && isTSIdentPart(l.src[l.pos:]) {
+					l.pos++
+				}
+				return nil
+			}
+		}
+		l.pos++
+	}
+	return l.errorf(start, "unterminated regular expression")
+}
+
+// tsSource is a tokenized TypeScript source with the type declarations it contains
+type tsSource struct {
+	src   string
+	toks  []tsToken
+	match []int // index of the matching bracket for (, [, { and their closers; -1 otherwise
+	types map[string]tsTypeDecl
+}
+
+// tsTypeDecl is an interface or type alias declared in the source
+type tsTypeDecl struct {
+	start int      // first token of the interface body or the aliased type
+	end   int      // token after the body or type
+	bases [][2]int // token ranges of the interfaces an interface extends
+}
+
+// parseTypeScript tokenizes a TypeScript source and collects its type declarations
+func parseTypeScript(src string) (*tsSource, error) {
+	toks, err := tokenizeTypeScript(src)
+	if err != nil {
+		return nil, err
+	}
+	tp := &tsSource{src: src, toks: toks, types: make(map[string]tsTypeDecl)}
+	if err := tp.matchBrackets(); err != nil {
+		return nil, err
+	}
+	if err := tp.collectDeclarations(); err != nil {
+		return nil, err
+	}
+	typescriptLog.Printf("Parsed TypeScript source: tokens=%d, types=%d", len(toks), len(tp.types))
+	return tp, nil
+}
+
+func (tp *tsSource) errorf(i int, format string, args ...any) error {
+	pos := len(tp.src)
+	if i < len(tp.toks) {
+		pos = tp.toks[i].start
+	}
+	line, col := tsPosition(tp.src, pos)
+	return fmt.Errorf("%d:%d: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+func (tp *tsSource) matchBrackets() error {
+	tp.match = make([]int, len(tp.toks))
+	var stack []int
+	pairs := map[string]string{")": "(", "]": "[", "}": "{"}
+	for i, tok := range tp.toks {
+		tp.match[i] = -1
+		if tok.kind != tsPunct {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			stack = append(stack, i)
+		case ")", "]", "}":
+			if len(stack) == 0 || tp.toks[stack[len(stack)-1]].text != pairs[tok.text] {
+				return tp.errorf(i, "unexpected '%s'", tok.text)
+			}
+			open := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			tp.match[open] = i
+			tp.match[i] = open
+		}
+	}
+	if len(stack) > 0 {
+		return tp.errorf(stack[len(stack)-1], "unclosed '%s'", tp.toks[stack[len(stack)-1]].text)
+	}
+	return nil
+}
+
+// text returns the text of token i, or "" past the end
+func (tp *tsSource) text(i int) string {
+	if i < 0 || i >= len(tp.toks) {
+		return ""
+	}
+	return tp.toks[i].text
+}
+
+func (tp *tsSource) isIdent(i int) bool {
+	return i >= 0 && i < len(tp.toks) && tp.toks[i].kind == tsIdent
+}
+
+// endsExpression reports whether token i can end an expression
+func (tp *tsSource) endsExpression(i int) bool {
+	if i < 0 || i >= len(tp.toks) {
+		return false
+	}
+	tok := tp.toks[i]
+	switch tok.kind {
+	case tsIdent:
+		return !tsRegexKeywords[tok.text] || tok.text == "this"
+	case tsPunct:
+		return tok.text == ")" || tok.text == "]" || tok.text == "}"
+	}
+	return true
+}
+
+// atStatementStart reports whether token i starts a statement
+func (tp *tsSource) atStatementStart(i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := tp.toks[i-1].text
+	if prev == ";" || prev == "{" || prev == "}" {
+		return true
+	}
+	return tp.toks[i].nlBefore && tp.endsExpression(i-1)
+}
+
+// collectDeclarations records the interfaces and type aliases of the source and rejects the
+// TypeScript constructs that type stripping cannot execute
+func (tp *tsSource) collectDeclarations() error {
+	for i := 0; i < len(tp.toks); i++ {
+		if tp.toks[i].kind != tsIdent || !tp.atStatementStart(i) {
+			continue
+		}
+		j := i
+		for tp.text(j) == "export" || tp.text(j) == "declare" || tp.text(j) == "default" {
+			j++
+		}
+		name := tp.text(j + 1)
+		switch tp.text(j) {
+		case "interface":
+			if !tp.isIdent(j + 1) {
+				continue
+			}
+			open := j + 2
+			for open < len(tp.toks) && tp.text(open) != "{" {
+				if tp.text(open) == "(" || tp.text(open) == "[" {
+					open = tp.match[open]
+				}
+				open++
+			}
+			if open >= len(tp.toks) {
+				return tp.errorf(j, "interface %s has no body", name)
+			}
+			decl := tsTypeDecl{start: open, end: tp.match[open] + 1}
+			for k := j + 2; k < open; k++ {
+				if tp.text(k) == "extends" {
+					decl.bases = tp.splitType(k+1, open, ",")
+					break
+				}
+			}
+			tp.types[name] = decl
+
+		case "type":
+			if !tp.isIdent(j+1) || (tp.text(j+2) != "=" && tp.text(j+2) != "<") {
+				continue
+			}
+			eq := j + 2
+			if tp.text(eq) == "<" {
+				end := tp.skipAngle(eq, len(tp.toks))
+				if end < 0 {
+					return tp.errorf(eq, "unterminated type parameters")
+				}
+				eq = end
+			}
+			if tp.text(eq) != "=" {
+				return tp.errorf(eq, "expected '=' in type alias %s", name)
+			}
+			tp.types[name] = tsTypeDecl{start: eq + 1, end: tp.parseType(eq+1, len(tp.toks))}
+
+		case "enum":
+			if tp.isIdent(j + 1) {
+				return tp.errorf(j, "enums are not supported in TypeScript tools; use a union of string literals or a const object")
+			}
+		case "const":
+			if name == "enum" {
+				return tp.errorf(j, "enums are not supported in TypeScript tools; use a union of string literals or a const object")
+			}
+		case "namespace", "module":
+			if (tp.isIdent(j+1) || tp.toks[min(j+1, len(tp.toks)-1)].kind == tsString) && tp.text(j+2) == "{" {
+				return tp.errorf(j, "namespaces are not supported in TypeScript tools")
+			}
+		}
+	}
+	return nil
+}
+
+// skipAngle returns the index after the '>' closing the angle brackets opened at i, or -1 when
+// the tokens do not form type parameters or arguments
+func (tp *tsSource) skipAngle(i, to int) int {
+	depth := 0
+	for j := i; j < to; j++ {
+		tok := tp.toks[j]
+		switch tok.text {
+		case "<":
+			depth++
+		case ">":
+			depth--
+			if depth == 0 {
+				return j + 1
+			}
+		case "(", "[", "{":
+			j = tp.match[j]
+		case ";", ")", "]", "}", "&&", "||", "==", "===", "!=", "!==", "+", "-", "*", "/", "%":
+			return -1
+		}
+		if tok.kind == tsString && depth == 0 {
+			return -1
+		}
+	}
+	return -1
+}
+
+// parseType returns the index after the type starting at token i
+func (tp *tsSource) parseType(i, to int) int {
+	end := tp.parseUnionType(i, to)
+	if end < to && tp.text(end) == "extends" && end > i {
+		// Conditional type: A extends B ? C : D
+		check := tp.parseUnionType(end+1, to)
+		if tp.text(check) == "?" {
+			whenTrue := tp.parseType(check+1, to)
+			if tp.text(whenTrue) == ":" {
+				return tp.parseType(whenTrue+1, to)
+			}
+		}
+	}
+	return end
+}
+
+func (tp *tsSource) parseUnionType(i, to int) int {
+	if tp.text(i) == "|" || tp.text(i) == "&" {
+		i++
+	}
+	i = tp.parsePostfixType(tp.parsePrimaryType(i, to), to)
+	for i < to && (tp.text(i) == "|" || tp.text(i) == "&") {
+		i = tp.parsePostfixType(tp.parsePrimaryType(i+1, to), to)
+	}
+	return i
+}
+
+func (tp *tsSource) parsePostfixType(i, to int) int {
+	for i < to && tp.text(i) == "[" && !tp.toks[i].nlBefore {
+		i = tp.match[i] + 1
+	}
+	return i
+}
+
+func (tp *tsSource) parsePrimaryType(i, to int) int {
+	if i >= to {
+		return i
+	}
+	tok := tp.toks[i]
+	switch tok.kind {
+	case tsString, tsTemplate, tsNumber:
+		return i + 1
+	case tsPunct:
+		switch tok.text {
+		case "(":
+			close := tp.match[i]
+			if tp.text(close+1) == "=>" {
+				return tp.parseType(close+2, to)
+			}
+			return close + 1
+		case "<":
+			end := tp.skipAngle(i, to)
+			if end > 0 && tp.text(end) == "(" {
+				return tp.parsePrimaryType(end, to)
+			}
+			return i
+		case "{", "[":
+			return tp.match[i] + 1
+		case "-":
+			return i + 2
+		}
+		return i
+	}
+
+	switch tok.text {
+	case "new":
+		return tp.parsePrimaryType(i+1, to)
+	case "abstract":
+		if tp.text(i+1) == "new" {
+			return tp.parsePrimaryType(i+2, to)
+		}
+	case "keyof", "unique", "readonly", "infer":
+		end := tp.parsePrimaryType(i+1, to)
+		if tok.text == "infer" && tp.text(end) == "extends" {
+			// infer U extends X inside a conditional type
+			return tp.parsePrimaryType(end+1, to)
+		}
+		return tp.parsePostfixType(end, to)
+	case "asserts":
+		if tp.isIdent(i + 1) {
+			i += 2
+			if tp.text(i) == "is" {
+				return tp.parseType(i+1, to)
+			}
+			return i
+		}
+	case "typeof":
+		i++
+		if tp.text(i) == "import" {
+			return tp.parsePrimaryType(i, to)
+		}
+		for tp.isIdent(i) {
+			i++
+			if tp.text(i) != "." {
+				break
+			}
+			i++
+		}
+		return tp.typeArgumentsOf(i, to)
+	case "import":
+		if tp.text(i+1) == "(" {
+			i = tp.match[i+1] + 1
+			for tp.text(i) == "." && tp.isIdent(i+1) {
+				i += 2
+			}
+			return tp.typeArgumentsOf(i, to)
+		}
+	}
+
+	// Type reference, possibly qualified and with type arguments, or a type predicate
+	i++
+	for tp.text(i) == "." && tp.isIdent(i+1) {
+		i += 2
+	}
+	i = tp.typeArgumentsOf(i, to)
+	if tp.text(i) == "is" && !tp.toks[i].nlBefore {
+		return tp.parseType(i+1, to)
+	}
+	return i
+}
+
+// typeArgumentsOf skips type arguments following a type reference
+func (tp *tsSource) typeArgumentsOf(i, to int) int {
+	if tp.text(i) == "<" {
+		if end := tp.skipAngle(i, to); end > 0 {
+			return end
+		}
+	}
+	return i
+}
+
+// tsInputProperty is a property of the type of the inputs parameter of execute
+type tsInputProperty struct {
+	optional bool
+	kinds    map[string]bool // JSON types the property accepts; "any" accepts every type
+}
+
+// tsExecuteSignature describes the inputs parameter of the execute function of a tool
+type tsExecuteSignature struct {
+	typed      bool // the inputs parameter has a type annotation
+	open       bool // the type has an index signature and accepts other properties
+	properties map[string]tsInputProperty
+}
+
+// executeSignature finds the top-level execute function and resolves the type of its first
+// parameter to the properties it declares
+func (tp *tsSource) executeSignature() (*tsExecuteSignature, error) {
+	open := -1
+	depth := 0
+	for i := 0; i < len(tp.toks) && open < 0; i++ {
+		switch tp.text(i) {
+		case "(", "[", "{":
+			depth++
+			continue
+		case ")", "]", "}":
+			depth--
+			continue
+		}
+		if depth != 0 || tp.text(i+1) != "execute" || tp.toks[i].kind != tsIdent {
+			continue
+		}
+		switch tp.text(i) {
+		case "function":
+			if tp.text(i+2) == "(" {
+				open = i + 2
+			} else if tp.text(i+2) == "<" {
+				if end := tp.skipAngle(i+2, len(tp.toks)); end > 0 && tp.text(end) == "(" {
+					open = end
+				}
+			}
+		case "const", "let", "var":
+			j := i + 2
+			if tp.text(j) == ":" {
+				j = tp.parseType(j+1, len(tp.toks))
+			}
+			if tp.text(j) != "=" {
+				continue
+			}
+			j++
+			if tp.text(j) == "async" {
+				j++
+			}
+			if tp.text(j) == "function" {
+				j++
+				if tp.isIdent(j) {
+					j++
+				}
+			}
+			if tp.text(j) == "<" {
+				if end := tp.skipAngle(j, len(tp.toks)); end > 0 {
+					j = end
+				}
+			}
+			switch {
+			case tp.text(j) == "(":
+				open = j
+			case tp.isIdent(j) && tp.text(j+1) == "=>":
+				return &tsExecuteSignature{}, nil
+			}
+		}
+	}
+	if open < 0 {
+		return nil, fmt.Errorf("no top-level execute function found; declare 'function execute(inputs: Inputs)'")
+	}
+
+	close := tp.match[open]
+	i := open + 1
+	switch {
+	case i == close:
+		return &tsExecuteSignature{}, nil
+	case tp.text(i) == "{" || tp.text(i) == "[":
+		i = tp.match[i] + 1
+	case tp.isIdent(i):
+		i++
+	}
+	if tp.text(i) == "?" {
+		i++
+	}
+	if tp.text(i) != ":" {
+		return &tsExecuteSignature{}, nil
+	}
+	start := i + 1
+	end := tp.parseType(start, close)
+	sig := &tsExecuteSignature{typed: true, properties: make(map[string]tsInputProperty)}
+	if err := tp.collectProperties(start, end, sig, 0); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// collectProperties adds the properties of the object type in tokens start..end-1 to sig
+func (tp *tsSource) collectProperties(start, end int, sig *tsExecuteSignature, depth int) error {
+	if depth > 10 {
+		return fmt.Errorf("type of the execute inputs is too deeply nested")
+	}
+	for tp.text(start) == "(" && tp.match[start] == end-1 {
+		start, end = start+1, end-1
+	}
+	if parts := tp.splitType(start, end, "&"); len(parts) > 1 {
+		for _, part := range parts {
+			if err := tp.collectProperties(part[0], part[1], sig, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch {
+	case tp.text(start) == "{" && tp.match[start] == end-1:
+		tp.collectMembers(start, sig)
+		return nil
+	case tp.isIdent(start) && end == start+1:
+		name := tp.text(start)
+		decl, ok := tp.types[name]
+		if !ok {
+			return fmt.Errorf("cannot resolve type '%s' of the execute inputs; declare it as an interface or type alias in the tool source", name)
+		}
+		if tp.text(decl.start) == "{" && tp.match[decl.start] == decl.end-1 {
+			for _, base := range decl.bases {
+				if err := tp.collectProperties(base[0], base[1], sig, depth+1); err != nil {
+					return err
+				}
+			}
+			tp.collectMembers(decl.start, sig)
+			return nil
+		}
+		return tp.collectProperties(decl.start, decl.end, sig, depth+1)
+	case tp.isIdent(start) && (tp.text(start) == "any" || tp.text(start) == "unknown" || tp.text(start) == "Record"):
+		sig.open = true
+		return nil
+	}
+	line, col := tsPosition(tp.src, tp.toks[start].start)
+	return fmt.Errorf("%d:%d: the type of the execute inputs must be an object type, interface or type alias", line, col)
+}
+
+// collectMembers adds the property signatures of the object type literal opening at open
+func (tp *tsSource) collectMembers(open int, sig *tsExecuteSignature) {
+	close := tp.match[open]
+	for i := open + 1; i < close; {
+		switch {
+		case tp.text(i) == ";" || tp.text(i) == ",":
+			i++
+			continue
+		case tp.text(i) == "readonly" && (tp.isIdent(i+1) || tp.toks[i+1].kind == tsString):
+			i++
+		case tp.text(i) == "[" && tp.isIdent(i+1) && tp.text(i+2) == ":":
+			sig.open = true
+			i = tp.match[i] + 1
+			if tp.text(i) == ":" {
+				i = tp.parseType(i+1, close)
+			}
+			continue
+		}
+
+		if tp.toks[i].kind != tsIdent && tp.toks[i].kind != tsString && tp.toks[i].kind != tsNumber {
+			// Call, construct or computed signatures do not describe JSON properties
+			i = tp.memberDelimiter(i, close)
+			continue
+		}
+		name := tp.text(i)
+		if tp.toks[i].kind == tsString {
+			name = name[1 : len(name)-1]
+		}
+		i++
+		optional := false
+		if tp.text(i) == "?" {
+			optional = true
+			i++
+		}
+		if tp.text(i) != ":" {
+			// Method signature
+			i = tp.memberDelimiter(i, close)
+			continue
+		}
+		end := tp.parseType(i+1, close)
+		sig.properties[name] = tsInputProperty{optional: optional, kinds: tp.typeKinds(i+1, end, 0)}
+		i = end
+	}
+}
+
+// memberDelimiter returns the index of the separator ending the type member at i
+func (tp *tsSource) memberDelimiter(i, close int) int {
+	for j := i; j < close; j++ {
+		switch tp.text(j) {
+		case ";", ",":
+			return j
+		case "(", "[", "{":
+			j = tp.match[j]
+			continue
+		}
+		if j > i && tp.toks[j].nlBefore && tp.endsExpression(j-1) {
+			return j
+		}
+	}
+	return close
+}
+
+// splitType splits tokens start..end-1 at top-level occurrences of the separator
+func (tp *tsSource) splitType(start, end int, sep string) [][2]int {
+	var parts [][2]int
+	partStart := start
+	angle := 0
+	for i := start; i < end; i++ {
+		switch tp.text(i) {
+		case "(", "[", "{":
+			i = tp.match[i]
+		case "<":
+			angle++
+		case ">":
+			angle--
+		case sep:
+			if angle == 0 {
+				if i > partStart {
+					parts = append(parts, [2]int{partStart, i})
+				}
+				partStart = i + 1
+			}
+		}
+	}
+	if end > partStart {
+		parts = append(parts, [2]int{partStart, end})
+	}
+	return parts
+}
+
+// typeKinds returns the JSON types accepted by the type in tokens start..end-1
+func (tp *tsSource) typeKinds(start, end, depth int) map[string]bool {
+	kinds := make(map[string]bool)
+	if depth > 10 || start >= end {
+		kinds["any"] = true
+		return kinds
+	}
+	for _, part := range tp.splitType(start, end, "|") {
+		s, e := part[0], part[1]
+		for tp.text(s) == "(" && tp.match[s] == e-1 {
+			s, e = s+1, e-1
+		}
+		tok := tp.toks[s]
+		switch {
+		case tp.text(e-1) == "]" && tp.match[e-1] > s || tok.text == "[":
+			kinds["array"] = true
+		case tok.text == "{":
+			kinds["object"] = true
+		case tok.kind == tsString || tok.kind == tsTemplate:
+			kinds["string"] = true
+		case tok.kind == tsNumber || tok.text == "-":
+			kinds["number"] = true
+		case tok.kind != tsIdent:
+			kinds["any"] = true
+		default:
+			switch tok.text {
+			case "string":
+				kinds["string"] = true
+			case "number", "bigint":
+				kinds["number"] = true
+			case "boolean", "true", "false":
+				kinds["boolean"] = true
+			case "null", "undefined", "void", "never":
+			case "Array", "ReadonlyArray", "readonly":
+				kinds["array"] = true
+			case "object", "Record", "Partial", "Readonly", "Required", "Pick", "Omit", "Map":
+				kinds["object"] = true
+			default:
+				decl, ok := tp.types[tok.text]
+				switch {
+				case !ok:
+					kinds["any"] = true
+				case tp.text(decl.start) == "{":
+					kinds["object"] = true
+				default:
+					for kind := range tp.typeKinds(decl.start, decl.end, depth+1) {
+						kinds[kind] = true
+					}
+				}
+			}
+		}
+	}
+	return kinds
+}
@@ -0,0 +1,82 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTypeScriptUnsupported(t *testing.T) {
+	tests := []struct {
+		source string
+		errMsg string
+	}{
+		{"enum Color { Red, Green }", "enums are not supported"},
+		{"export const enum E { A }", "enums are not supported"},
+		{"namespace N { export const a = 1; }", "namespaces are not supported"},
+		{"const s = 'unterminated", "1:11: unterminated string"},
+		{"function f() {", "unclosed '{'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			_, err := parseTypeScript(tt.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestTypeScriptExecuteSignature(t *testing.T) {
+	source := `interface Base { verbose?: boolean }
+export interface Inputs extends Base {
+  query: string;
+  limit?: number | undefined;
+  "tags": string[];
+  mode: 'fast' | 'slow';
+  options: { depth: number };
+  ref: Ref;
+}
+export type Ref = string;
+export async function execute(inputs: Inputs): Promise<string> {
+  return inputs.query;
+}`
+	parsed, err := parseTypeScript(source)
+	require.NoError(t, err)
+	sig, err := parsed.executeSignature()
+	require.NoError(t, err)
+
+	assert.True(t, sig.typed)
+	assert.False(t, sig.open)
+	require.Len(t, sig.properties, 7)
+	assert.Equal(t, map[string]bool{"string": true}, sig.properties["query"].kinds)
+	assert.Equal(t, map[string]bool{"number": true}, sig.properties["limit"].kinds)
+	assert.True(t, sig.properties["limit"].optional)
+	assert.True(t, sig.properties["verbose"].optional, "inherited from the base interface")
+	assert.Equal(t, map[string]bool{"array": true}, sig.properties["tags"].kinds)
+	assert.Equal(t, map[string]bool{"string": true}, sig.properties["mode"].kinds)
+	assert.Equal(t, map[string]bool{"object": true}, sig.properties["options"].kinds)
+	assert.Equal(t, map[string]bool{"string": true}, sig.properties["ref"].kinds)
+
+	parsed, err = parseTypeScript("const execute = async ({ a }: { a: number; [key: string]: unknown }) => a;")
+	require.NoError(t, err)
+	sig, err = parsed.executeSignature()
+	require.NoError(t, err)
+	assert.True(t, sig.open)
+	assert.Contains(t, sig.properties, "a")
+
+	parsed, err = parseTypeScript("function execute(inputs: Missing) {}")
+	require.NoError(t, err)
+	_, err = parsed.executeSignature()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot resolve type 'Missing'")
+
+	parsed, err = parseTypeScript("function run() {}")
+	require.NoError(t, err)
+	_, err = parsed.executeSignature()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no top-level execute function")
+}
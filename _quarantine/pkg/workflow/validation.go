@@ -15,6 +15,7 @@
 //   - npm_validation.go: NPM package validation
 //   - docker_validation.go: Docker image validation
 //   - expression_safety.go: GitHub Actions expression security
+//   - condition_validation.go: Statically always-false job and step conditions
 //   - engine_validation.go: AI engine configuration validation
 //   - mcp_config_validation.go: MCP server configuration validation
 //   - template_validation.go: Template structure validation